@@ -0,0 +1,311 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// Adaptive concurrency tuning, following the gradient algorithm: the
+// limit tracks the ratio of the long-term latency baseline to recent
+// latency, plus headroom of the square root of the limit for probing.
+const (
+	adaptiveShortAlpha = 0.2         // Weight of each sample in the recent latency
+	adaptiveLongAlpha  = 2.0 / 601.0 // Weight of each sample in the baseline, about 600 samples
+	adaptiveTolerance  = 1.5         // Latency increase tolerated before backing off
+	adaptiveSmoothing  = 0.2         // Share of each new estimate applied to the limit
+	adaptiveMinGrad    = 0.5         // Most the limit shrinks per sample, before smoothing
+)
+
+// AdaptiveConcurrency protects a downstream by bounding how many items
+// process at once, tuning the bound from observed latency instead of a
+// fixed bulkhead size. While latency stays near its long-term baseline the
+// limit grows, probing for spare capacity; when recent latency rises above
+// the baseline, a sign the downstream is queueing, the limit shrinks in
+// proportion. Items over the limit wait for a slot, in arrival order,
+// until their context ends.
+//
+// The limit starts at initialLimit and stays within the bounds set with
+// SetMinLimit and SetMaxLimit, 1 and 1000 by default. It only grows while
+// at least half of it is in use, so an idle service does not drift to the
+// maximum. Canceled items are not counted as latency samples.
+//
+// CRITICAL: AdaptiveConcurrency is STATEFUL. Create it once and reuse it.
+//
+// Example:
+//
+//	var SearchLimitID = pipz.NewIdentity("search-limit", "Adapts search concurrency to latency")
+//	limited := pipz.NewAdaptiveConcurrency(SearchLimitID, searchBackend, 20).
+//	    SetMaxLimit(200)
+type AdaptiveConcurrency[T any] struct {
+	processor Chainable[T]
+	clock     clockz.Clock
+	identity  Identity
+	waiters   []chan struct{}
+	limit     float64
+	minLimit  float64
+	maxLimit  float64
+	shortRTT  float64
+	longRTT   float64
+	inFlight  int
+	mu        sync.Mutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewAdaptiveConcurrency creates an AdaptiveConcurrency starting at
+// initialLimit concurrent items.
+func NewAdaptiveConcurrency[T any](identity Identity, processor Chainable[T], initialLimit int) *AdaptiveConcurrency[T] {
+	return &AdaptiveConcurrency[T]{
+		identity:  identity,
+		processor: processor,
+		limit:     float64(min(max(initialLimit, 1), 1000)),
+		minLimit:  1,
+		maxLimit:  1000,
+	}
+}
+
+// Process implements the Chainable interface.
+func (a *AdaptiveConcurrency[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, a.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, a, a.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	if err := a.acquire(ctx); err != nil {
+		return data, &Error[T]{
+			Err:       err,
+			InputData: errorInput(data),
+			Path:      []Identity{a.identity},
+			Timeout:   errors.Is(err, context.DeadlineExceeded),
+			Canceled:  errors.Is(err, context.Canceled),
+			Timestamp: time.Now(),
+		}
+	}
+
+	a.mu.Lock()
+	processor := a.processor
+	clock := a.getClock()
+	a.mu.Unlock()
+
+	start := clock.Now()
+	defer func() {
+		a.release(ctx, clock.Since(start), ctx.Err() == nil)
+	}()
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, a.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{a.identity},
+		}
+	}
+	return result, nil
+}
+
+// acquire takes a slot, waiting in arrival order while the limit is
+// reached.
+func (a *AdaptiveConcurrency[T]) acquire(ctx context.Context) error {
+	a.mu.Lock()
+	if len(a.waiters) == 0 && a.inFlight < a.slotsLocked() {
+		a.inFlight++
+		a.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	a.waiters = append(a.waiters, ready)
+	a.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if i := slices.Index(a.waiters, ready); i >= 0 {
+			a.waiters = slices.Delete(a.waiters, i, i+1)
+		} else {
+			// Granted while giving up; pass the slot on
+			a.inFlight--
+			a.grantLocked()
+		}
+		return ctx.Err()
+	}
+}
+
+// release frees a slot and, for completed items, adjusts the limit from
+// their latency.
+func (a *AdaptiveConcurrency[T]) release(ctx context.Context, rtt time.Duration, sample bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	before := a.slotsLocked()
+	if sample {
+		a.updateLocked(rtt.Seconds())
+	}
+	a.inFlight--
+	a.grantLocked()
+
+	if after := a.slotsLocked(); after != before {
+		capitan.Debug(ctx, SignalAdaptiveLimitChanged,
+			FieldName.Field(a.identity.Name()),
+			FieldIdentityID.Field(a.identity.ID().String()),
+			FieldLimit.Field(after),
+			FieldInFlight.Field(a.inFlight),
+		)
+	}
+}
+
+// updateLocked folds a latency sample into the limit. The caller holds mu
+// and has not yet released the sample's slot.
+func (a *AdaptiveConcurrency[T]) updateLocked(rtt float64) {
+	if rtt <= 0 {
+		rtt = math.SmallestNonzeroFloat64
+	}
+	if a.longRTT == 0 {
+		a.shortRTT, a.longRTT = rtt, rtt
+		return
+	}
+	a.shortRTT += adaptiveShortAlpha * (rtt - a.shortRTT)
+	a.longRTT += adaptiveLongAlpha * (rtt - a.longRTT)
+
+	// Let the baseline catch up quickly once latency recovers
+	if a.longRTT/a.shortRTT > 2 {
+		a.longRTT *= 0.95
+	}
+
+	gradient := max(adaptiveMinGrad, min(1, adaptiveTolerance*a.longRTT/a.shortRTT))
+	estimate := a.limit*gradient + math.Sqrt(a.limit)
+	if estimate > a.limit && float64(a.inFlight) < a.limit/2 {
+		// Too little load to know whether more would be absorbed
+		return
+	}
+	a.limit = a.limit*(1-adaptiveSmoothing) + estimate*adaptiveSmoothing
+	a.limit = min(max(a.limit, a.minLimit), a.maxLimit)
+}
+
+// grantLocked hands free slots to waiters in arrival order. The caller
+// holds mu.
+func (a *AdaptiveConcurrency[T]) grantLocked() {
+	for len(a.waiters) > 0 && a.inFlight < a.slotsLocked() {
+		a.inFlight++
+		close(a.waiters[0])
+		a.waiters = a.waiters[1:]
+	}
+}
+
+// slotsLocked returns the current limit as a whole number of items. The
+// caller holds mu.
+func (a *AdaptiveConcurrency[T]) slotsLocked() int {
+	return max(int(a.limit), 1)
+}
+
+// SetMinLimit sets the lowest the limit may fall. Values below one are
+// treated as one.
+func (a *AdaptiveConcurrency[T]) SetMinLimit(n int) *AdaptiveConcurrency[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.minLimit = float64(max(n, 1))
+	a.maxLimit = max(a.maxLimit, a.minLimit)
+	a.limit = min(max(a.limit, a.minLimit), a.maxLimit)
+	a.grantLocked()
+	return a
+}
+
+// SetMaxLimit sets the highest the limit may rise. Values below the
+// minimum are raised to it.
+func (a *AdaptiveConcurrency[T]) SetMaxLimit(n int) *AdaptiveConcurrency[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.maxLimit = max(float64(n), a.minLimit)
+	a.limit = min(a.limit, a.maxLimit)
+	return a
+}
+
+// SetProcessor updates the protected processor.
+func (a *AdaptiveConcurrency[T]) SetProcessor(processor Chainable[T]) *AdaptiveConcurrency[T] {
+	noteChildren(a.identity, processor)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.processor = processor
+	return a
+}
+
+// Limit returns the current concurrency limit.
+func (a *AdaptiveConcurrency[T]) Limit() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.slotsLocked()
+}
+
+// InFlight returns the number of items currently being processed.
+func (a *AdaptiveConcurrency[T]) InFlight() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.inFlight
+}
+
+// WithClock sets a custom clock for testing.
+func (a *AdaptiveConcurrency[T]) WithClock(clock clockz.Clock) *AdaptiveConcurrency[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clock = clock
+	return a
+}
+
+// getClock returns the clock to use.
+func (a *AdaptiveConcurrency[T]) getClock() clockz.Clock {
+	if a.clock == nil {
+		return clockz.RealClock
+	}
+	return a.clock
+}
+
+// Identity returns the identity of this connector.
+func (a *AdaptiveConcurrency[T]) Identity() Identity {
+	return a.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (a *AdaptiveConcurrency[T]) Schema() Node {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return Node{
+		Identity: a.identity,
+		Type:     "adaptiveconcurrency",
+		Flow:     AdaptiveConcurrencyFlow{Processor: a.processor.Schema()},
+		Metadata: map[string]any{
+			"limit":     a.slotsLocked(),
+			"min_limit": int(a.minLimit),
+			"max_limit": int(a.maxLimit),
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (a *AdaptiveConcurrency[T]) Close() error {
+	a.closeOnce.Do(func() {
+		a.mu.Lock()
+		processor := a.processor
+		a.mu.Unlock()
+		a.closeErr = processor.Close()
+	})
+	return a.closeErr
+}
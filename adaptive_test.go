@@ -0,0 +1,137 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveConcurrency(t *testing.T) {
+	// sample feeds n latency samples with the limit fully in use, or idle.
+	sample := func(a *AdaptiveConcurrency[int], n int, rtt float64, busy bool) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		for i := 0; i < n; i++ {
+			a.inFlight = 0
+			if busy {
+				a.inFlight = a.slotsLocked()
+			}
+			a.updateLocked(rtt)
+		}
+		a.inFlight = 0
+	}
+
+	t.Run("Bounds Concurrency", func(t *testing.T) {
+		release := make(chan struct{})
+		entered := make(chan struct{}, 3)
+		hold := Effect(testIdentity("hold"), func(_ context.Context, _ int) error {
+			entered <- struct{}{}
+			<-release
+			return nil
+		})
+		limited := NewAdaptiveConcurrency(testIdentity("adaptive"), hold, 2)
+		done := make(chan error, 3)
+		for i := 0; i < 3; i++ {
+			go func() {
+				_, err := limited.Process(context.Background(), i)
+				done <- err
+			}()
+		}
+		<-entered
+		<-entered
+		select {
+		case <-entered:
+			t.Fatal("expected the third item to wait for a slot")
+		case <-time.After(20 * time.Millisecond):
+		}
+		if limited.InFlight() != 2 {
+			t.Errorf("expected 2 in flight, got %d", limited.InFlight())
+		}
+
+		close(release)
+		for i := 0; i < 3; i++ {
+			if err := <-done; err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		if limited.InFlight() != 0 {
+			t.Errorf("expected no items in flight, got %d", limited.InFlight())
+		}
+	})
+
+	t.Run("Grows While Latency Is Flat", func(t *testing.T) {
+		limited := NewAdaptiveConcurrency(testIdentity("adaptive"), passthroughInt(), 10)
+		sample(limited, 50, 0.010, true)
+		if limited.Limit() <= 10 {
+			t.Errorf("expected the limit to grow, got %d", limited.Limit())
+		}
+	})
+
+	t.Run("Backs Off When Latency Rises", func(t *testing.T) {
+		limited := NewAdaptiveConcurrency(testIdentity("adaptive"), passthroughInt(), 10)
+		sample(limited, 50, 0.010, true)
+		grown := limited.Limit()
+		sample(limited, 20, 0.050, true)
+		if limited.Limit() >= grown {
+			t.Errorf("expected the limit to shrink below %d, got %d", grown, limited.Limit())
+		}
+	})
+
+	t.Run("Idle Does Not Grow", func(t *testing.T) {
+		limited := NewAdaptiveConcurrency(testIdentity("adaptive"), passthroughInt(), 10)
+		sample(limited, 50, 0.010, false)
+		if limited.Limit() != 10 {
+			t.Errorf("expected the limit to stay at 10, got %d", limited.Limit())
+		}
+	})
+
+	t.Run("Respects Bounds", func(t *testing.T) {
+		limited := NewAdaptiveConcurrency(testIdentity("adaptive"), passthroughInt(), 10).
+			SetMinLimit(5).
+			SetMaxLimit(12)
+		sample(limited, 200, 0.010, true)
+		if limited.Limit() != 12 {
+			t.Errorf("expected the limit capped at 12, got %d", limited.Limit())
+		}
+		sample(limited, 40, 1, true)
+		if limited.Limit() != 5 {
+			t.Errorf("expected the limit floored at 5, got %d", limited.Limit())
+		}
+	})
+
+	t.Run("Waiter Canceled", func(t *testing.T) {
+		release := make(chan struct{})
+		entered := make(chan struct{})
+		hold := Effect(testIdentity("hold"), func(_ context.Context, _ int) error {
+			close(entered)
+			<-release
+			return nil
+		})
+		limited := NewAdaptiveConcurrency(testIdentity("adaptive"), hold, 1)
+		go limited.Process(context.Background(), 1) //nolint:errcheck
+		<-entered
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := limited.Process(ctx, 2)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.IsTimeout() {
+			t.Errorf("expected a timeout waiting for a slot, got %v", err)
+		}
+		close(release)
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := NewAdaptiveConcurrency(testIdentity("adaptive"), passthroughInt(), 20).Schema()
+		if node.Type != "adaptiveconcurrency" {
+			t.Errorf("expected adaptiveconcurrency type, got %s", node.Type)
+		}
+		if _, ok := node.Flow.(AdaptiveConcurrencyFlow); !ok {
+			t.Errorf("expected AdaptiveConcurrencyFlow, got %T", node.Flow)
+		}
+		if node.Metadata["limit"] != 20 || node.Metadata["max_limit"] != 1000 {
+			t.Errorf("unexpected metadata: %v", node.Metadata)
+		}
+	})
+}
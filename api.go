@@ -153,7 +153,7 @@
 //	// Rate limiting
 //	var ApiLimitName = pipz.NewIdentity("api-limit", "")
 //	rateLimiter := pipz.NewRateLimiter(ApiLimitName, 100, 10) // 100/sec, burst 10
-//	rateLimiter.SetMode("drop") // Or "wait" (default)
+//	rateLimiter.SetMode(pipz.RateLimitDrop) // Or pipz.RateLimitWait (default)
 //
 //	// Circuit breaker
 //	var ServiceBreakerName = pipz.NewIdentity("service-breaker", "")
@@ -345,6 +345,8 @@ package pipz
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -355,7 +357,10 @@ import (
 //
 // Each Identity has an auto-generated UUID that uniquely identifies the
 // processor or connector instance, enabling correlation between schema
-// definitions and runtime signal events.
+// definitions and runtime signal events. An optional semantic version,
+// set with WithVersion, is surfaced in schemas and diffs and lets a
+// Sequence refuse downgrades on Replace. Tags, set with WithTags, label
+// stages by role so ProcessSubset can run only some of them.
 //
 // Example:
 //
@@ -372,6 +377,8 @@ type Identity struct {
 	id          uuid.UUID
 	name        string
 	description string
+	version     string
+	tags        string
 }
 
 // NewIdentity creates a new Identity with an auto-generated UUID.
@@ -400,6 +407,56 @@ func (i Identity) Description() string {
 	return i.description
 }
 
+// WithVersion returns a copy of the identity declaring a semantic version
+// such as "1.4.0" or "v2.0.0-rc.1". The copy keeps the same ID.
+//
+// Example:
+//
+//	var ScoreRiskID = pipz.NewIdentity("score-risk", "Scores order risk").WithVersion("2.1.0")
+func (i Identity) WithVersion(version string) Identity {
+	i.version = version
+	return i
+}
+
+// Version returns the declared semantic version, or "" if none was set.
+func (i Identity) Version() string {
+	return i.version
+}
+
+// tagSeparator joins an identity's tags, keeping Identity comparable.
+const tagSeparator = "\x1f"
+
+// WithTags returns a copy of the identity labeled with tags, such as
+// "validation" or "side-effect", in addition to any it already has. The
+// copy keeps the same ID. Empty tags are ignored.
+//
+// Example:
+//
+//	var CheckStockID = pipz.NewIdentity("check-stock", "Checks stock levels").WithTags("validation")
+func (i Identity) WithTags(tags ...string) Identity {
+	all := append(i.Tags(), tags...)
+	slices.Sort(all)
+	all = slices.Compact(all)
+	if len(all) > 0 && all[0] == "" {
+		all = all[1:]
+	}
+	i.tags = strings.Join(all, tagSeparator)
+	return i
+}
+
+// Tags returns the identity's tags in sorted order, or nil if it has none.
+func (i Identity) Tags() []string {
+	if i.tags == "" {
+		return nil
+	}
+	return strings.Split(i.tags, tagSeparator)
+}
+
+// HasTag reports whether the identity is labeled with tag.
+func (i Identity) HasTag(tag string) bool {
+	return tag != "" && slices.Contains(i.Tags(), tag)
+}
+
 // String implements fmt.Stringer, returning the name for convenient logging.
 func (i Identity) String() string {
 	return i.name
@@ -450,6 +507,7 @@ type Chainable[T any] interface {
 type Processor[T any] struct {
 	fn       func(context.Context, T) (T, error)
 	identity Identity
+	readOnly bool
 }
 
 // Process implements the Chainable interface, allowing individual processors
@@ -464,7 +522,28 @@ type Processor[T any] struct {
 //	pipeline := pipz.NewSequence(PipelineID, validator, transformer)
 func (p Processor[T]) Process(ctx context.Context, data T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, p.identity, data)
-	return p.fn(ctx, data)
+	if activeHooks.Load() == nil {
+		return p.fn(ctx, data)
+	}
+	return p.hooked(ctx, data)
+}
+
+// hooked runs the processor under the active processor hooks: the panic
+// circuit, simulation stubs, the FaultHook, and calibration.
+func (p Processor[T]) hooked(ctx context.Context, data T) (T, error) {
+	bypass, quarantineErr := checkPanicCircuit(p.identity, data)
+	if quarantineErr != nil {
+		return data, quarantineErr
+	}
+	if bypass || stubProcessor(ctx, p.identity, data) {
+		return data, nil
+	}
+	ctx, faultErr := checkFault(ctx, p.identity, data)
+	if faultErr != nil {
+		var zero T
+		return zero, faultErr
+	}
+	return calibrated(ctx, p.identity, p.fn, data)
 }
 
 // Identity returns the identity of the processor for debugging and error reporting.
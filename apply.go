@@ -45,7 +45,7 @@ func Apply[T any](identity Identity, fn func(context.Context, T) (T, error)) Pro
 				var zero T
 				return zero, &Error[T]{
 					Path:      []Identity{identity},
-					InputData: value,
+					InputData: errorInput(value),
 					Err:       err,
 					Timestamp: time.Now(),
 					Duration:  time.Since(start),
@@ -0,0 +1,293 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+var (
+	// ErrPendingApproval is wrapped by the PendingApprovalError an Approval
+	// returns while its item awaits a decision.
+	ErrPendingApproval = errors.New("pending approval")
+
+	// ErrApprovalDenied is wrapped by errors from an Approval whose item's
+	// request was denied.
+	ErrApprovalDenied = errors.New("approval denied")
+)
+
+// PendingApprovalError reports an item paused by an Approval until its
+// ticket is decided. It unwraps to ErrPendingApproval.
+type PendingApprovalError struct {
+	Key      string
+	TicketID string
+}
+
+// Error implements the error interface.
+func (e *PendingApprovalError) Error() string {
+	return fmt.Sprintf("%v: ticket %s", ErrPendingApproval, e.TicketID)
+}
+
+// Unwrap returns ErrPendingApproval.
+func (*PendingApprovalError) Unwrap() error {
+	return ErrPendingApproval
+}
+
+// ApprovalDecision is the state of an approval ticket.
+type ApprovalDecision int
+
+// Approval decisions.
+const (
+	ApprovalPending ApprovalDecision = iota
+	ApprovalGranted
+	ApprovalDenied
+)
+
+// String returns the decision's name.
+func (d ApprovalDecision) String() string {
+	switch d {
+	case ApprovalGranted:
+		return "granted"
+	case ApprovalDenied:
+		return "denied"
+	default:
+		return "pending"
+	}
+}
+
+// ApprovalStore records which items have approval tickets and reports the
+// decisions made on them, typically backed by the table or ticketing system
+// approvers work in. It must outlive the process for approvals to survive
+// restarts.
+type ApprovalStore interface {
+	// Ticket returns the ticket recorded for the item key, if any.
+	Ticket(ctx context.Context, key string) (ticketID string, ok bool, err error)
+	// Record records ticketID as the ticket for the item key.
+	Record(ctx context.Context, key, ticketID string) error
+	// Decision returns the current decision on ticketID.
+	Decision(ctx context.Context, ticketID string) (ApprovalDecision, error)
+}
+
+// Approval is a human-in-the-loop gate for high-value work such as large
+// orders or refunds. The first time an item arrives, Approval asks the
+// requester to open an approval ticket, records it in the store, and fails
+// with a PendingApprovalError. When the item is processed again, it
+// continues unchanged once the ticket is granted, fails with
+// ErrApprovalDenied once it is denied, and is still pending otherwise.
+//
+// pipz does not park the paused item: resume it by processing it again,
+// from a queue redelivery, a scheduled sweep of pending work, or a handler
+// notified of the decision. Items are matched to tickets by the key
+// function, such as an order ID; without one they are keyed by DeepHash of
+// their content, so the item must not change between attempts.
+//
+// Example:
+//
+//	var RefundApprovalID = pipz.NewIdentity("refund-approval", "Requires sign-off for large refunds")
+//	approval := pipz.NewApproval(RefundApprovalID,
+//	    func(ctx context.Context, r Refund) (string, error) {
+//	        return tickets.Open(ctx, "Approve refund "+r.ID, r.Amount)
+//	    },
+//	    approvalStore,
+//	).SetKeyFunc(func(_ context.Context, r Refund) string { return r.ID })
+//
+//	refunds := pipz.NewSequence(RefundID, validate,
+//	    pipz.NewFilter(LargeRefundID, isLarge, approval),
+//	    issueRefund,
+//	)
+type Approval[T any] struct {
+	requester func(context.Context, T) (string, error)
+	store     ApprovalStore
+	key       func(context.Context, T) string
+	identity  Identity
+	mu        sync.RWMutex
+}
+
+// NewApproval creates an Approval opening tickets with requester and
+// tracking them in store.
+func NewApproval[T any](identity Identity, requester func(context.Context, T) (string, error), store ApprovalStore) *Approval[T] {
+	return &Approval[T]{
+		identity:  identity,
+		requester: requester,
+		store:     store,
+	}
+}
+
+// Process implements the Chainable interface.
+// Passes the item through once its approval is granted.
+func (a *Approval[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, a.identity, data)
+
+	a.mu.RLock()
+	requester := a.requester
+	store := a.store
+	keyFunc := a.key
+	a.mu.RUnlock()
+
+	key := strconv.FormatUint(DeepHash(data), 16)
+	if keyFunc != nil {
+		key = keyFunc(ctx, data)
+	}
+
+	ticketID, ok, err := store.Ticket(ctx, key)
+	if err != nil {
+		return data, a.fail(data, fmt.Errorf("looking up approval: %w", err))
+	}
+	if !ok {
+		ticketID, err = requester(ctx, data)
+		if err != nil {
+			return data, a.fail(data, fmt.Errorf("requesting approval: %w", err))
+		}
+		if err := store.Record(ctx, key, ticketID); err != nil {
+			return data, a.fail(data, fmt.Errorf("recording approval ticket %s: %w", ticketID, err))
+		}
+		capitan.Info(ctx, SignalApprovalRequested,
+			FieldName.Field(a.identity.Name()),
+			FieldIdentityID.Field(a.identity.ID().String()),
+			FieldItemKey.Field(key),
+			FieldTicketID.Field(ticketID),
+		)
+		return data, a.fail(data, &PendingApprovalError{Key: key, TicketID: ticketID})
+	}
+
+	decision, err := store.Decision(ctx, ticketID)
+	if err != nil {
+		return data, a.fail(data, fmt.Errorf("checking approval ticket %s: %w", ticketID, err))
+	}
+	switch decision {
+	case ApprovalGranted:
+		return data, nil
+	case ApprovalDenied:
+		capitan.Warn(ctx, SignalApprovalDenied,
+			FieldName.Field(a.identity.Name()),
+			FieldIdentityID.Field(a.identity.ID().String()),
+			FieldItemKey.Field(key),
+			FieldTicketID.Field(ticketID),
+		)
+		return data, a.fail(data, fmt.Errorf("%w: ticket %s", ErrApprovalDenied, ticketID))
+	default:
+		return data, a.fail(data, &PendingApprovalError{Key: key, TicketID: ticketID})
+	}
+}
+
+// fail wraps err in an Error attributed to this gate.
+func (a *Approval[T]) fail(data T, err error) *Error[T] {
+	return &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(data),
+		Err:       err,
+		Path:      []Identity{a.identity},
+	}
+}
+
+// SetKeyFunc sets the function matching an item to its ticket across
+// attempts. A nil function keys items by DeepHash of their content.
+func (a *Approval[T]) SetKeyFunc(key func(context.Context, T) string) *Approval[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.key = key
+	return a
+}
+
+// SetRequester updates the function opening approval tickets.
+func (a *Approval[T]) SetRequester(requester func(context.Context, T) (string, error)) *Approval[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requester = requester
+	return a
+}
+
+// SetStore updates the approval store.
+func (a *Approval[T]) SetStore(store ApprovalStore) *Approval[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.store = store
+	return a
+}
+
+// Identity returns the identity of this gate.
+func (a *Approval[T]) Identity() Identity {
+	return a.identity
+}
+
+// Schema returns a Node representing this gate in the pipeline schema.
+func (a *Approval[T]) Schema() Node {
+	return Node{
+		Identity: a.identity,
+		Type:     "approval",
+	}
+}
+
+// Close implements the Chainable interface. Approvals hold no resources.
+func (*Approval[T]) Close() error {
+	return nil
+}
+
+// MemoryApprovalStore is an in-process ApprovalStore for tests and
+// single-process tools. Approvals recorded in it are lost on restart.
+type MemoryApprovalStore struct {
+	tickets   map[string]string
+	decisions map[string]ApprovalDecision
+	mu        sync.Mutex
+}
+
+// NewMemoryApprovalStore creates an empty MemoryApprovalStore.
+func NewMemoryApprovalStore() *MemoryApprovalStore {
+	return &MemoryApprovalStore{
+		tickets:   make(map[string]string),
+		decisions: make(map[string]ApprovalDecision),
+	}
+}
+
+// Ticket implements ApprovalStore.
+func (s *MemoryApprovalStore) Ticket(_ context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ticketID, ok := s.tickets[key]
+	return ticketID, ok, nil
+}
+
+// Record implements ApprovalStore.
+func (s *MemoryApprovalStore) Record(_ context.Context, key, ticketID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tickets[key] = ticketID
+	if _, ok := s.decisions[ticketID]; !ok {
+		s.decisions[ticketID] = ApprovalPending
+	}
+	return nil
+}
+
+// Decision implements ApprovalStore. Unknown tickets are pending.
+func (s *MemoryApprovalStore) Decision(_ context.Context, ticketID string) (ApprovalDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.decisions[ticketID], nil
+}
+
+// Decide records the decision on ticketID.
+func (s *MemoryApprovalStore) Decide(ticketID string, decision ApprovalDecision) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.decisions[ticketID] = decision
+}
+
+// Pending returns the tickets awaiting a decision, sorted.
+func (s *MemoryApprovalStore) Pending() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pending []string
+	for ticketID, decision := range s.decisions {
+		if decision == ApprovalPending {
+			pending = append(pending, ticketID)
+		}
+	}
+	sort.Strings(pending)
+	return pending
+}
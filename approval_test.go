@@ -0,0 +1,128 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestApproval(t *testing.T) {
+	type refund struct {
+		ID     string
+		Amount int
+	}
+	newGate := func() (*Approval[refund], *MemoryApprovalStore, *int) {
+		store := NewMemoryApprovalStore()
+		opened := 0
+		gate := NewApproval(NewIdentity("refund-approval", ""),
+			func(_ context.Context, r refund) (string, error) {
+				opened++
+				return fmt.Sprintf("T-%s-%d", r.ID, opened), nil
+			},
+			store,
+		).SetKeyFunc(func(_ context.Context, r refund) string { return r.ID })
+		return gate, store, &opened
+	}
+	ctx := context.Background()
+
+	t.Run("First Pass Is Pending", func(t *testing.T) {
+		gate, store, opened := newGate()
+		_, err := gate.Process(ctx, refund{ID: "r1", Amount: 900})
+		if !errors.Is(err, ErrPendingApproval) {
+			t.Fatalf("expected ErrPendingApproval, got %v", err)
+		}
+		var pending *PendingApprovalError
+		if !errors.As(err, &pending) || pending.TicketID != "T-r1-1" || pending.Key != "r1" {
+			t.Errorf("unexpected pending error: %+v", pending)
+		}
+		var pipeErr *Error[refund]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "refund-approval" {
+			t.Errorf("expected pipeline error with path, got %v", err)
+		}
+		if *opened != 1 {
+			t.Errorf("expected 1 ticket, got %d", *opened)
+		}
+		if got := store.Pending(); len(got) != 1 || got[0] != "T-r1-1" {
+			t.Errorf("pending = %v", got)
+		}
+	})
+
+	t.Run("Resume Stays Pending Without New Ticket", func(t *testing.T) {
+		gate, _, opened := newGate()
+		_, _ = gate.Process(ctx, refund{ID: "r1"}) //nolint:errcheck // opens the ticket
+		_, err := gate.Process(ctx, refund{ID: "r1"})
+		if !errors.Is(err, ErrPendingApproval) {
+			t.Fatalf("expected pending, got %v", err)
+		}
+		if *opened != 1 {
+			t.Errorf("expected ticket reuse, opened %d", *opened)
+		}
+	})
+
+	t.Run("Granted Continues", func(t *testing.T) {
+		gate, store, _ := newGate()
+		_, _ = gate.Process(ctx, refund{ID: "r1", Amount: 900}) //nolint:errcheck // opens the ticket
+		store.Decide("T-r1-1", ApprovalGranted)
+
+		result, err := gate.Process(ctx, refund{ID: "r1", Amount: 900})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Amount != 900 {
+			t.Errorf("data changed: %+v", result)
+		}
+		if len(store.Pending()) != 0 {
+			t.Errorf("expected no pending tickets, got %v", store.Pending())
+		}
+	})
+
+	t.Run("Denied Fails", func(t *testing.T) {
+		gate, store, _ := newGate()
+		_, _ = gate.Process(ctx, refund{ID: "r1"}) //nolint:errcheck // opens the ticket
+		store.Decide("T-r1-1", ApprovalDenied)
+
+		_, err := gate.Process(ctx, refund{ID: "r1"})
+		if !errors.Is(err, ErrApprovalDenied) || errors.Is(err, ErrPendingApproval) {
+			t.Errorf("expected ErrApprovalDenied, got %v", err)
+		}
+	})
+
+	t.Run("Requester Error", func(t *testing.T) {
+		gate, store, _ := newGate()
+		gate.SetRequester(func(context.Context, refund) (string, error) {
+			return "", errors.New("ticketing down")
+		})
+		_, err := gate.Process(ctx, refund{ID: "r1"})
+		if err == nil || errors.Is(err, ErrPendingApproval) {
+			t.Fatalf("expected requester error, got %v", err)
+		}
+		if _, ok, _ := store.Ticket(ctx, "r1"); ok { //nolint:errcheck // memory store never fails
+			t.Error("no ticket should be recorded after a failed request")
+		}
+	})
+
+	t.Run("Content Key By Default", func(t *testing.T) {
+		gate, _, opened := newGate()
+		gate.SetKeyFunc(nil)
+		_, _ = gate.Process(ctx, refund{ID: "r1", Amount: 1}) //nolint:errcheck // opens the ticket
+		_, _ = gate.Process(ctx, refund{ID: "r1", Amount: 1}) //nolint:errcheck // same content
+		_, _ = gate.Process(ctx, refund{ID: "r1", Amount: 2}) //nolint:errcheck // changed content
+		if *opened != 2 {
+			t.Errorf("expected 2 tickets, got %d", *opened)
+		}
+	})
+
+	t.Run("Decision String", func(t *testing.T) {
+		if ApprovalPending.String() != "pending" || ApprovalGranted.String() != "granted" || ApprovalDenied.String() != "denied" {
+			t.Error("unexpected decision names")
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		gate, _, _ := newGate()
+		if node := gate.Schema(); node.Type != "approval" || node.Identity.Name() != "refund-approval" {
+			t.Errorf("unexpected schema: %+v", node)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrAssertionFailed is wrapped by errors from an Assert whose predicate
+// returned false.
+var ErrAssertionFailed = errors.New("assertion failed")
+
+// Assert creates a Processor that checks an invariant between stages. When
+// pred returns false, SignalAssertionFailed is emitted and the item fails
+// with an error wrapping ErrAssertionFailed and msg, naming the broken
+// contract at the point it was broken instead of wherever the bad data
+// finally causes trouble. Data that satisfies pred passes through unchanged.
+//
+// By default a violation fails the item, which is what tests want. Set
+// Policy.LogAssertions in production to only signal the violation and let
+// the item continue. Asserts are ReadOnly, so fan-out connectors run them
+// without cloning the input.
+//
+// Example:
+//
+//	var PricedID = pipz.NewIdentity("assert-priced", "Every line item has a price after pricing")
+//	pipeline := pipz.NewSequence(CheckoutID,
+//	    priceItems,
+//	    pipz.Assert(PricedID, func(_ context.Context, o Order) bool {
+//	        for _, item := range o.Items {
+//	            if item.Price <= 0 {
+//	                return false
+//	            }
+//	        }
+//	        return true
+//	    }, "line item without a price"),
+//	    chargeCustomer,
+//	)
+func Assert[T any](identity Identity, pred func(context.Context, T) bool, msg string) Processor[T] {
+	return Processor[T]{
+		identity: identity,
+		readOnly: true,
+		fn: func(ctx context.Context, value T) (result T, err error) {
+			defer recoverFromPanic(&result, &err, identity, value)
+			if pred(ctx, value) {
+				return value, nil
+			}
+			violation := fmt.Errorf("%w: %s", ErrAssertionFailed, msg)
+			fields := []capitan.Field{
+				FieldName.Field(identity.Name()),
+				FieldIdentityID.Field(identity.ID().String()),
+				FieldError.Field(violation.Error()),
+			}
+			if PolicyFromContext(ctx).LogAssertions {
+				capitan.Warn(ctx, SignalAssertionFailed, fields...)
+				return value, nil
+			}
+			capitan.Error(ctx, SignalAssertionFailed, fields...)
+			return value, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(value),
+				Err:       violation,
+				Path:      []Identity{identity},
+			}
+		},
+	}
+}
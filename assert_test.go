@@ -0,0 +1,76 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestAssert(t *testing.T) {
+	positive := Assert(NewIdentity("positive", ""), func(_ context.Context, n int) bool {
+		return n > 0
+	}, "value must be positive")
+
+	t.Run("Passes When Predicate Holds", func(t *testing.T) {
+		result, err := positive.Process(context.Background(), 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != 5 {
+			t.Errorf("expected 5, got %d", result)
+		}
+	})
+
+	t.Run("Fails On Violation", func(t *testing.T) {
+		_, err := positive.Process(context.Background(), -1)
+		if !errors.Is(err, ErrAssertionFailed) {
+			t.Fatalf("expected ErrAssertionFailed, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "value must be positive") {
+			t.Errorf("expected message in error, got %v", err)
+		}
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "positive" {
+			t.Errorf("expected path [positive], got %v", err)
+		}
+	})
+
+	t.Run("Logs Only Under Policy", func(t *testing.T) {
+		ctx := WithPolicy(context.Background(), Policy{LogAssertions: true})
+		result, err := positive.Process(ctx, -1)
+		if err != nil {
+			t.Fatalf("expected violation to be logged only, got %v", err)
+		}
+		if result != -1 {
+			t.Errorf("expected data unchanged, got %d", result)
+		}
+	})
+
+	t.Run("Emits Failed Signal", func(t *testing.T) {
+		var name, msg string
+		listener := capitan.Hook(SignalAssertionFailed, func(_ context.Context, e *capitan.Event) {
+			name, _ = FieldName.From(e)
+			msg, _ = FieldError.From(e)
+		})
+		defer listener.Close()
+
+		ctx := WithPolicy(context.Background(), Policy{LogAssertions: true})
+		_, _ = positive.Process(ctx, 0) //nolint:errcheck // logged only
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if name != "positive" || !strings.Contains(msg, "value must be positive") {
+			t.Errorf("unexpected signal fields: %q, %q", name, msg)
+		}
+	})
+
+	t.Run("Is ReadOnly", func(t *testing.T) {
+		if !positive.ReadOnly() {
+			t.Error("expected Assert to be read-only")
+		}
+	})
+}
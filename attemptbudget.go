@@ -0,0 +1,176 @@
+package pipz
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// AttemptBudget bounds the duplicate work that retries and hedges add on
+// top of baseline traffic. Each item passing a budgeted connector earns
+// the budget ratio extra attempts; each retry or hedge spends one. When
+// the budget is spent, connectors stop adding attempts and fail or wait
+// with what they have, so a partial outage cannot multiply load on the
+// struggling dependency.
+//
+// Share one budget between every Retry, Backoff, and Hedge in front of the
+// same dependency with SetBudget: their combined extra attempts then stay
+// within ratio times the baseline, however they are nested. An item earns
+// the budget once, at the outermost connector using it.
+//
+// SetMinPerSecond guarantees a trickle of extra attempts for low-traffic
+// services, and SetMaxTokens caps how many unspent attempts can be saved
+// up during quiet periods.
+//
+// Example:
+//
+//	// Retries and hedges together add at most 10% on top of baseline
+//	budget := pipz.NewAttemptBudget(0.1).SetMinPerSecond(1)
+//	inventory := pipz.NewRetry(InventoryRetryID,
+//	    pipz.NewHedge(InventoryHedgeID, fetchInventory, 50*time.Millisecond).SetBudget(budget),
+//	    3,
+//	).SetBudget(budget)
+type AttemptBudget struct {
+	clock        clockz.Clock
+	lastRefill   time.Time
+	ratio        float64
+	minPerSecond float64
+	maxTokens    float64
+	tokens       float64
+	stats        AttemptBudgetStats
+	mu           sync.Mutex
+}
+
+// AttemptBudgetStats counts the items and extra attempts seen by an
+// AttemptBudget.
+type AttemptBudgetStats struct {
+	// Items is the number of items that earned the budget.
+	Items int64 `json:"items"`
+	// Extra is the number of retries and hedges the budget allowed.
+	Extra int64 `json:"extra"`
+	// Denied is the number of retries and hedges the budget refused.
+	Denied int64 `json:"denied"`
+}
+
+// attemptBudgetKey marks a context whose item has earned budget, so nested
+// connectors sharing it do not count the item again.
+type attemptBudgetKey struct {
+	budget *AttemptBudget
+}
+
+// NewAttemptBudget creates an AttemptBudget allowing extra attempts up to
+// ratio times the items seen, such as 0.2 for 20%. It starts empty and
+// saves up at most 10 unspent attempts.
+func NewAttemptBudget(ratio float64) *AttemptBudget {
+	return &AttemptBudget{
+		ratio:     max(ratio, 0),
+		maxTokens: 10,
+	}
+}
+
+// SetMinPerSecond allows n extra attempts per second regardless of
+// traffic, so services with few items can still retry.
+func (b *AttemptBudget) SetMinPerSecond(n float64) *AttemptBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	b.minPerSecond = max(n, 0)
+	return b
+}
+
+// SetMaxTokens caps how many unspent extra attempts the budget saves up.
+// A cap below one is treated as one.
+func (b *AttemptBudget) SetMaxTokens(n int) *AttemptBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.maxTokens = float64(max(n, 1))
+	b.tokens = math.Min(b.tokens, b.maxTokens)
+	return b
+}
+
+// Available returns the number of extra attempts currently allowed.
+func (b *AttemptBudget) Available() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	return int(b.tokens)
+}
+
+// Stats returns the budget's counters.
+func (b *AttemptBudget) Stats() AttemptBudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stats
+}
+
+// WithClock sets a custom clock for testing.
+func (b *AttemptBudget) WithClock(clock clockz.Clock) *AttemptBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = clock
+	b.lastRefill = time.Time{}
+	return b
+}
+
+// earn credits the budget for the item carried by ctx, unless a connector
+// sharing the budget already has, and returns ctx marked as credited.
+func (b *AttemptBudget) earn(ctx context.Context) context.Context {
+	key := attemptBudgetKey{b}
+	if ctx.Value(key) != nil {
+		return ctx
+	}
+	b.mu.Lock()
+	b.refill()
+	b.tokens = math.Min(b.tokens+b.ratio, b.maxTokens)
+	b.stats.Items++
+	b.mu.Unlock()
+	return context.WithValue(ctx, key, true)
+}
+
+// spend takes one extra attempt from the budget, reporting whether one was
+// available.
+func (b *AttemptBudget) spend() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	if b.tokens < 1 {
+		b.stats.Denied++
+		return false
+	}
+	b.tokens--
+	b.stats.Extra++
+	return true
+}
+
+// refill adds the per-second minimum for the time since the last refill.
+// Must be called with mutex held.
+func (b *AttemptBudget) refill() {
+	now := b.getClock().Now()
+	if !b.lastRefill.IsZero() && b.minPerSecond > 0 {
+		elapsed := now.Sub(b.lastRefill).Seconds()
+		b.tokens = math.Min(b.tokens+elapsed*b.minPerSecond, b.maxTokens)
+	}
+	b.lastRefill = now
+}
+
+// emitBudgetExhausted signals that the connector skipped an extra attempt
+// after attempt because its budget was spent.
+func emitBudgetExhausted(ctx context.Context, identity Identity, attempt int) {
+	capitan.Warn(ctx, SignalAttemptBudgetExhausted,
+		FieldName.Field(identity.Name()),
+		FieldIdentityID.Field(identity.ID().String()),
+		FieldAttempt.Field(attempt),
+	)
+}
+
+// getClock returns the clock to use.
+func (b *AttemptBudget) getClock() clockz.Clock {
+	if b.clock == nil {
+		return clockz.RealClock
+	}
+	return b.clock
+}
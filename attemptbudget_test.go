@@ -0,0 +1,102 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+func TestAttemptBudget(t *testing.T) {
+	t.Run("Earns Ratio Per Item", func(t *testing.T) {
+		budget := NewAttemptBudget(0.5)
+		ctx := context.Background()
+		for i := 0; i < 4; i++ {
+			budget.earn(ctx)
+		}
+		if budget.Available() != 2 {
+			t.Errorf("expected 2 attempts available, got %d", budget.Available())
+		}
+		if !budget.spend() || !budget.spend() || budget.spend() {
+			t.Error("expected exactly 2 attempts to be spent")
+		}
+		if stats := budget.Stats(); stats.Items != 4 || stats.Extra != 2 || stats.Denied != 1 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Earns Once Per Item", func(t *testing.T) {
+		budget := NewAttemptBudget(1)
+		ctx := budget.earn(context.Background())
+		budget.earn(ctx)
+		if stats := budget.Stats(); stats.Items != 1 {
+			t.Errorf("expected nested connectors to count the item once, got %d", stats.Items)
+		}
+	})
+
+	t.Run("Caps Saved Attempts", func(t *testing.T) {
+		budget := NewAttemptBudget(1).SetMaxTokens(3)
+		for i := 0; i < 10; i++ {
+			budget.earn(context.Background())
+		}
+		if budget.Available() != 3 {
+			t.Errorf("expected 3 attempts available, got %d", budget.Available())
+		}
+	})
+
+	t.Run("Minimum Per Second", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		budget := NewAttemptBudget(0).WithClock(clock).SetMinPerSecond(2)
+		if budget.Available() != 0 {
+			t.Errorf("expected an empty budget, got %d", budget.Available())
+		}
+		clock.Advance(1500 * time.Millisecond)
+		if budget.Available() != 3 {
+			t.Errorf("expected 3 attempts after 1.5s, got %d", budget.Available())
+		}
+	})
+
+	t.Run("Stops Retry", func(t *testing.T) {
+		var calls atomic.Int32
+		failing := Apply(testIdentity("failing"), func(_ context.Context, n int) (int, error) {
+			calls.Add(1)
+			return n, errors.New("boom")
+		})
+		budget := NewAttemptBudget(1)
+		retry := NewRetry(testIdentity("retry"), failing, 5).SetBudget(budget)
+
+		if _, err := retry.Process(context.Background(), 1); err == nil {
+			t.Fatal("expected error")
+		}
+		if calls.Load() != 2 {
+			t.Errorf("expected 1 budgeted retry, got %d attempts", calls.Load())
+		}
+		if stats := budget.Stats(); stats.Extra != 1 || stats.Denied != 1 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Shared With Backoff", func(t *testing.T) {
+		var calls atomic.Int32
+		failing := Apply(testIdentity("failing"), func(_ context.Context, n int) (int, error) {
+			calls.Add(1)
+			return n, errors.New("boom")
+		})
+		budget := NewAttemptBudget(0)
+		backoff := NewBackoff(testIdentity("backoff"), failing, 5, time.Millisecond).SetBudget(budget)
+		retry := NewRetry(testIdentity("retry"), backoff, 3).SetBudget(budget)
+
+		if _, err := retry.Process(context.Background(), 1); err == nil {
+			t.Fatal("expected error")
+		}
+		if calls.Load() != 1 {
+			t.Errorf("expected an empty budget to allow no retries, got %d attempts", calls.Load())
+		}
+		if stats := budget.Stats(); stats.Items != 1 || stats.Denied != 2 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+}
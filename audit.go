@@ -0,0 +1,316 @@
+package pipz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrAuditChainBroken is returned by VerifyAuditChain when an entry's hash
+// or link to its predecessor does not match, indicating tampering or loss.
+var ErrAuditChainBroken = errors.New("audit chain broken")
+
+// AuditEntry is a single tamper-evident audit record.
+// Each entry's Hash covers its own fields plus the previous entry's Hash,
+// so modifying, reordering, or deleting any entry invalidates every
+// entry that follows it.
+type AuditEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Actor      string    `json:"actor,omitempty"`
+	Action     string    `json:"action"`
+	Error      string    `json:"error,omitempty"`
+	BeforeHash string    `json:"before_hash"`
+	AfterHash  string    `json:"after_hash,omitempty"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+	Path       []string  `json:"path"`
+	Sequence   uint64    `json:"sequence"`
+}
+
+// computeHash returns the chained hash for the entry's contents.
+func (e AuditEntry) computeHash() string {
+	h := sha256.New()
+	for _, part := range []string{
+		strconv.FormatUint(e.Sequence, 10),
+		e.Timestamp.UTC().Format(time.RFC3339Nano),
+		e.Actor,
+		e.Action,
+		strings.Join(e.Path, "\x1f"),
+		e.BeforeHash,
+		e.AfterHash,
+		e.Error,
+		e.PrevHash,
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyAuditChain checks that entries form an unbroken hash chain.
+// Entries must be supplied in sequence order. The first entry may link to
+// any PrevHash, allowing verification of a window from a longer log.
+func VerifyAuditChain(entries []AuditEntry) error {
+	for i, e := range entries {
+		if e.computeHash() != e.Hash {
+			return fmt.Errorf("%w: entry %d hash mismatch", ErrAuditChainBroken, e.Sequence)
+		}
+		if i > 0 && e.PrevHash != entries[i-1].Hash {
+			return fmt.Errorf("%w: entry %d does not link to entry %d", ErrAuditChainBroken, e.Sequence, entries[i-1].Sequence)
+		}
+	}
+	return nil
+}
+
+// AuditWriter persists audit entries.
+// Implementations might append to a file, insert into a database,
+// or forward to a SIEM. WriteAudit is called serially in chain order.
+type AuditWriter interface {
+	WriteAudit(ctx context.Context, entry AuditEntry) error
+}
+
+// AuditWriterFunc adapts a function to the AuditWriter interface.
+type AuditWriterFunc func(ctx context.Context, entry AuditEntry) error
+
+// WriteAudit implements AuditWriter.
+func (f AuditWriterFunc) WriteAudit(ctx context.Context, entry AuditEntry) error {
+	return f(ctx, entry)
+}
+
+// AuditSink records a tamper-evident audit entry for every item processed by
+// the wrapped processor. Each entry captures who acted (via the actor
+// function), what ran (the processor identity and error path), and SHA-256
+// hashes of the data before and after processing, without storing the data
+// itself. Entries are chained by hash so gaps or edits are detectable with
+// VerifyAuditChain.
+//
+// Audit writes are part of the processing contract: if the writer fails,
+// Process returns an error even when the wrapped processor succeeded, so no
+// change goes unrecorded. Writes are serialized to preserve chain order.
+//
+// CRITICAL: AuditSink is STATEFUL - it holds the chain head. Create it once
+// and reuse it; a new AuditSink starts a new chain.
+//
+// Example:
+//
+//	var AuditedRefundsID = pipz.NewIdentity("audited-refunds", "Audit trail for refunds")
+//	audited := pipz.NewAuditSink(AuditedRefundsID, refundPipeline,
+//	    pipz.AuditWriterFunc(func(ctx context.Context, e pipz.AuditEntry) error {
+//	        return auditStore.Append(ctx, e)
+//	    }),
+//	).SetActor(func(ctx context.Context) string {
+//	    if u, ok := pipz.PrincipalFrom[User](ctx); ok {
+//	        return u.ID
+//	    }
+//	    return "anonymous"
+//	})
+type AuditSink[T any] struct {
+	processor Chainable[T]
+	writer    AuditWriter
+	actor     func(context.Context) string
+	hasher    func(T) ([]byte, error)
+	identity  Identity
+	prevHash  string
+	sequence  uint64
+	mu        sync.RWMutex
+	chainMu   sync.Mutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewAuditSink creates an AuditSink wrapping processor and writing to writer.
+// Data is hashed from its JSON encoding by default; use SetHasher for types
+// that do not marshal deterministically.
+func NewAuditSink[T any](identity Identity, processor Chainable[T], writer AuditWriter) *AuditSink[T] {
+	return &AuditSink[T]{
+		identity:  identity,
+		processor: processor,
+		writer:    writer,
+		hasher: func(data T) ([]byte, error) {
+			return json.Marshal(data)
+		},
+	}
+}
+
+// Process implements the Chainable interface.
+func (a *AuditSink[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, a.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, a, a.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	a.mu.RLock()
+	processor := a.processor
+	writer := a.writer
+	actor := a.actor
+	hasher := a.hasher
+	a.mu.RUnlock()
+
+	entry := AuditEntry{
+		Action:     processor.Identity().Name(),
+		BeforeHash: hashAuditData(hasher, data),
+		Path:       []string{a.identity.Name(), processor.Identity().Name()},
+	}
+	if actor != nil {
+		entry.Actor = actor(ctx)
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		entry.Error = err.Error()
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			path := make([]string, 0, len(pipeErr.Path)+1)
+			path = append(path, a.identity.Name())
+			for _, id := range pipeErr.Path {
+				path = append(path, id.Name())
+			}
+			entry.Path = path
+		}
+	} else {
+		entry.AfterHash = hashAuditData(hasher, result)
+	}
+
+	if writeErr := a.append(ctx, writer, entry); writeErr != nil {
+		capitan.Error(ctx, SignalAuditWriteFailed,
+			FieldName.Field(a.identity.Name()),
+			FieldIdentityID.Field(a.identity.ID().String()),
+			FieldError.Field(writeErr.Error()),
+		)
+		if err == nil {
+			return result, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(data),
+				Err:       fmt.Errorf("audit write failed: %w", writeErr),
+				Path:      []Identity{a.identity},
+			}
+		}
+	}
+
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, a.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{a.identity},
+		}
+	}
+	return result, nil
+}
+
+// append links entry into the chain and writes it.
+// The chain head only advances when the write succeeds.
+func (a *AuditSink[T]) append(ctx context.Context, writer AuditWriter, entry AuditEntry) error {
+	a.chainMu.Lock()
+	defer a.chainMu.Unlock()
+
+	entry.Sequence = a.sequence + 1
+	entry.PrevHash = a.prevHash
+	entry.Timestamp = time.Now()
+	entry.Hash = entry.computeHash()
+
+	if err := writer.WriteAudit(ctx, entry); err != nil {
+		return err
+	}
+	a.sequence = entry.Sequence
+	a.prevHash = entry.Hash
+	return nil
+}
+
+// hashAuditData returns the hex SHA-256 of the encoded data.
+// Encoding failures are recorded rather than failing the audit.
+func hashAuditData[T any](hasher func(T) ([]byte, error), data T) string {
+	b, err := hasher(data)
+	if err != nil {
+		return "unhashable"
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// SetActor sets the function that identifies who performed the action.
+func (a *AuditSink[T]) SetActor(actor func(context.Context) string) *AuditSink[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.actor = actor
+	return a
+}
+
+// SetHasher sets the function used to encode data before hashing.
+func (a *AuditSink[T]) SetHasher(hasher func(T) ([]byte, error)) *AuditSink[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.hasher = hasher
+	return a
+}
+
+// SetWriter updates the audit writer. The chain continues across writers.
+func (a *AuditSink[T]) SetWriter(writer AuditWriter) *AuditSink[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.writer = writer
+	return a
+}
+
+// Resume continues an existing chain from its last persisted entry,
+// typically loaded from the writer's store after a restart.
+func (a *AuditSink[T]) Resume(last AuditEntry) *AuditSink[T] {
+	a.chainMu.Lock()
+	defer a.chainMu.Unlock()
+	a.sequence = last.Sequence
+	a.prevHash = last.Hash
+	return a
+}
+
+// Head returns the sequence number and hash of the most recent entry.
+func (a *AuditSink[T]) Head() (uint64, string) {
+	a.chainMu.Lock()
+	defer a.chainMu.Unlock()
+	return a.sequence, a.prevHash
+}
+
+// Identity returns the identity of this connector.
+func (a *AuditSink[T]) Identity() Identity {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (a *AuditSink[T]) Schema() Node {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return Node{
+		Identity: a.identity,
+		Type:     "auditsink",
+		Flow:     AuditSinkFlow{Processor: a.processor.Schema()},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (a *AuditSink[T]) Close() error {
+	a.closeOnce.Do(func() {
+		a.mu.RLock()
+		defer a.mu.RUnlock()
+		a.closeErr = a.processor.Close()
+	})
+	return a.closeErr
+}
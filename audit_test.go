@@ -0,0 +1,187 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+type memoryAuditWriter struct {
+	entries []AuditEntry
+	err     error
+	mu      sync.Mutex
+}
+
+func (w *memoryAuditWriter) WriteAudit(_ context.Context, e AuditEntry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	w.entries = append(w.entries, e)
+	return nil
+}
+
+func TestAuditSink_RecordsChainedEntries(t *testing.T) {
+	writer := &memoryAuditWriter{}
+	inner := Transform(NewIdentity("double", ""), func(_ context.Context, n int) int { return n * 2 })
+	sink := NewAuditSink(NewIdentity("audit", ""), inner, writer).
+		SetActor(func(ctx context.Context) string {
+			p, _ := PrincipalFrom[testPrincipal](ctx)
+			return p.ID
+		})
+
+	ctx := WithPrincipal(context.Background(), testPrincipal{ID: "alice"})
+	for i := 1; i <= 3; i++ {
+		result, err := sink.Process(ctx, i)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != i*2 {
+			t.Errorf("expected %d, got %d", i*2, result)
+		}
+	}
+
+	if len(writer.entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(writer.entries))
+	}
+	first := writer.entries[0]
+	if first.Actor != "alice" || first.Action != "double" || first.Sequence != 1 {
+		t.Errorf("unexpected first entry: %+v", first)
+	}
+	if first.BeforeHash == "" || first.AfterHash == "" || first.BeforeHash == first.AfterHash {
+		t.Errorf("expected distinct before/after hashes, got %+v", first)
+	}
+	if writer.entries[1].PrevHash != first.Hash {
+		t.Error("expected second entry to link to first")
+	}
+	if err := VerifyAuditChain(writer.entries); err != nil {
+		t.Errorf("expected valid chain, got %v", err)
+	}
+
+	seq, head := sink.Head()
+	if seq != 3 || head != writer.entries[2].Hash {
+		t.Errorf("unexpected head %d %s", seq, head)
+	}
+}
+
+func TestAuditSink_TamperDetection(t *testing.T) {
+	writer := &memoryAuditWriter{}
+	inner := Transform(NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })
+	sink := NewAuditSink(NewIdentity("audit", ""), inner, writer)
+	for i := 0; i < 3; i++ {
+		_, _ = sink.Process(context.Background(), i) //nolint:errcheck // noop cannot fail
+	}
+
+	t.Run("Modified Entry", func(t *testing.T) {
+		entries := append([]AuditEntry(nil), writer.entries...)
+		entries[1].Actor = "mallory"
+		if err := VerifyAuditChain(entries); !errors.Is(err, ErrAuditChainBroken) {
+			t.Errorf("expected ErrAuditChainBroken, got %v", err)
+		}
+	})
+
+	t.Run("Deleted Entry", func(t *testing.T) {
+		entries := []AuditEntry{writer.entries[0], writer.entries[2]}
+		if err := VerifyAuditChain(entries); !errors.Is(err, ErrAuditChainBroken) {
+			t.Errorf("expected ErrAuditChainBroken, got %v", err)
+		}
+	})
+
+	t.Run("Window Verifies", func(t *testing.T) {
+		if err := VerifyAuditChain(writer.entries[1:]); err != nil {
+			t.Errorf("expected valid window, got %v", err)
+		}
+	})
+}
+
+func TestAuditSink_FailuresAndWriterErrors(t *testing.T) {
+	t.Run("Records Failed Processing", func(t *testing.T) {
+		writer := &memoryAuditWriter{}
+		failing := Apply(NewIdentity("charge", ""), func(_ context.Context, n int) (int, error) {
+			return n, errors.New("declined")
+		})
+		seq := NewSequence(NewIdentity("payment", ""), failing)
+		sink := NewAuditSink(NewIdentity("audit", ""), seq, writer)
+
+		_, err := sink.Process(context.Background(), 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "audit" {
+			t.Fatalf("expected error with audit path, got %v", err)
+		}
+		entry := writer.entries[0]
+		if entry.Error == "" || entry.AfterHash != "" {
+			t.Errorf("expected error entry without after hash, got %+v", entry)
+		}
+		want := []string{"audit", "payment", "charge"}
+		if len(entry.Path) != len(want) {
+			t.Fatalf("expected path %v, got %v", want, entry.Path)
+		}
+		for i := range want {
+			if entry.Path[i] != want[i] {
+				t.Errorf("expected path %v, got %v", want, entry.Path)
+			}
+		}
+	})
+
+	t.Run("Writer Failure Fails Processing", func(t *testing.T) {
+		writer := &memoryAuditWriter{err: errors.New("disk full")}
+		inner := Transform(NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })
+		sink := NewAuditSink(NewIdentity("audit", ""), inner, writer)
+
+		_, err := sink.Process(context.Background(), 1)
+		if err == nil {
+			t.Fatal("expected audit write failure to surface")
+		}
+		if seq, _ := sink.Head(); seq != 0 {
+			t.Errorf("expected chain head not to advance, got %d", seq)
+		}
+
+		sink.SetWriter(&memoryAuditWriter{})
+		if _, err := sink.Process(context.Background(), 1); err != nil {
+			t.Errorf("expected success after writer replaced, got %v", err)
+		}
+	})
+
+	t.Run("Unhashable Data", func(t *testing.T) {
+		writer := &memoryAuditWriter{}
+		inner := Transform(NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })
+		sink := NewAuditSink(NewIdentity("audit", ""), inner, writer).
+			SetHasher(func(int) ([]byte, error) { return nil, errors.New("nope") })
+		if _, err := sink.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if writer.entries[0].BeforeHash != "unhashable" {
+			t.Errorf("expected unhashable marker, got %q", writer.entries[0].BeforeHash)
+		}
+	})
+}
+
+func TestAuditSink_ResumeSchemaClose(t *testing.T) {
+	writer := &memoryAuditWriter{}
+	inner := Transform(NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })
+	sink := NewAuditSink(NewIdentity("audit", ""), inner, writer).
+		Resume(AuditEntry{Sequence: 41, Hash: "abc"})
+
+	if _, err := sink.Process(context.Background(), 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if writer.entries[0].Sequence != 42 || writer.entries[0].PrevHash != "abc" {
+		t.Errorf("expected resumed chain, got %+v", writer.entries[0])
+	}
+
+	schema := sink.Schema()
+	if schema.Type != "auditsink" {
+		t.Errorf("expected type auditsink, got %s", schema.Type)
+	}
+	if flow, ok := AuditSinkKey.From(schema); !ok || flow.Processor.Identity.Name() != "noop" {
+		t.Error("expected processor in flow")
+	}
+	if sink.Identity().Name() != "audit" {
+		t.Error("unexpected identity")
+	}
+	if err := sink.Close(); err != nil {
+		t.Errorf("unexpected close error: %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// Authorization errors.
+var (
+	// ErrUnauthenticated indicates no principal was present in the context.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden indicates the principal is not permitted to perform the operation.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// principalKey is a per-type context key for principals.
+type principalKey[P any] struct{}
+
+// WithPrincipal returns a context carrying the given principal.
+// The principal type acts as the key, so different principal types
+// (e.g. a User and a ServiceAccount) can coexist in the same context.
+//
+// Example:
+//
+//	ctx = pipz.WithPrincipal(ctx, User{ID: "u-123", Roles: []string{"admin"}})
+//	result, err := pipeline.Process(ctx, order)
+func WithPrincipal[P any](ctx context.Context, principal P) context.Context {
+	return context.WithValue(ctx, principalKey[P]{}, principal)
+}
+
+// PrincipalFrom extracts a principal of type P from the context.
+// Returns the principal and true if present, or the zero value and false otherwise.
+func PrincipalFrom[P any](ctx context.Context) (P, bool) {
+	var zero P
+	if ctx == nil {
+		return zero, false
+	}
+	p, ok := ctx.Value(principalKey[P]{}).(P)
+	return p, ok
+}
+
+// RequirePrincipal returns a policy that fails with ErrUnauthenticated when
+// no principal of type P is present in the context. When a principal is
+// present, check (if non-nil) decides whether the operation is allowed.
+//
+// Example:
+//
+//	adminOnly := pipz.RequirePrincipal(func(_ context.Context, u User, _ Order) error {
+//	    if !u.HasRole("admin") {
+//	        return pipz.ErrForbidden
+//	    }
+//	    return nil
+//	})
+func RequirePrincipal[P, T any](check func(context.Context, P, T) error) func(context.Context, T) error {
+	return func(ctx context.Context, data T) error {
+		principal, ok := PrincipalFrom[P](ctx)
+		if !ok {
+			return ErrUnauthenticated
+		}
+		if check == nil {
+			return nil
+		}
+		return check(ctx, principal, data)
+	}
+}
+
+// Authz creates a Processor that enforces an authorization policy.
+// The policy inspects the context (typically the principal) and the data,
+// returning nil to allow processing or an error to deny it. Data always
+// passes through unchanged when allowed.
+//
+// Denials stop the pipeline with an Error[T] wrapping the policy error and
+// emit an authz.denied signal for security monitoring. Policies should return
+// ErrUnauthenticated or ErrForbidden (optionally wrapped) so callers can
+// distinguish authorization failures with errors.Is.
+//
+// Example:
+//
+//	var AuthorizeRefundID = pipz.NewIdentity("authorize-refund", "Only account owners may refund")
+//	authorize := pipz.Authz(AuthorizeRefundID,
+//	    pipz.RequirePrincipal(func(_ context.Context, u User, r Refund) error {
+//	        if u.AccountID != r.AccountID {
+//	            return fmt.Errorf("%w: not account owner", pipz.ErrForbidden)
+//	        }
+//	        return nil
+//	    }),
+//	)
+func Authz[T any](identity Identity, policy func(context.Context, T) error) Processor[T] {
+	return Processor[T]{
+		identity: identity,
+		fn: func(ctx context.Context, value T) (result T, err error) {
+			defer recoverFromPanic(&result, &err, identity, value)
+			start := time.Now()
+			if err = policy(ctx, value); err != nil {
+				capitan.Warn(ctx, SignalAuthzDenied,
+					FieldName.Field(identity.Name()),
+					FieldIdentityID.Field(identity.ID().String()),
+					FieldError.Field(err.Error()),
+				)
+				var zero T
+				return zero, &Error[T]{
+					Path:      []Identity{identity},
+					InputData: errorInput(value),
+					Err:       err,
+					Timestamp: time.Now(),
+					Duration:  time.Since(start),
+				}
+			}
+			return value, nil
+		},
+	}
+}
+
+// DenyByDefault guards an entire pipeline behind authorization policies.
+// Every policy must allow the request before the wrapped processor runs.
+// A DenyByDefault with no policies denies everything, so forgetting to
+// configure authorization fails closed rather than open.
+//
+// Use Authz for individual authorization steps inside a pipeline, and
+// DenyByDefault at the pipeline boundary to guarantee nothing executes
+// without an explicit allow decision.
+//
+// Example:
+//
+//	var GuardedOrdersID = pipz.NewIdentity("guarded-orders", "Authenticated order processing")
+//	guarded := pipz.NewDenyByDefault(GuardedOrdersID, orderPipeline,
+//	    pipz.RequirePrincipal[User, Order](nil),
+//	)
+type DenyByDefault[T any] struct {
+	processor Chainable[T]
+	policies  []func(context.Context, T) error
+	identity  Identity
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewDenyByDefault creates a DenyByDefault connector wrapping processor.
+func NewDenyByDefault[T any](identity Identity, processor Chainable[T], policies ...func(context.Context, T) error) *DenyByDefault[T] {
+	return &DenyByDefault[T]{
+		identity:  identity,
+		processor: processor,
+		policies:  policies,
+	}
+}
+
+// Process implements the Chainable interface.
+func (d *DenyByDefault[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, d.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, d, d.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	d.mu.RLock()
+	processor := d.processor
+	policies := make([]func(context.Context, T) error, len(d.policies))
+	copy(policies, d.policies)
+	d.mu.RUnlock()
+
+	denial := fmt.Errorf("%w: no authorization policy configured", ErrForbidden)
+	if len(policies) > 0 {
+		denial = nil
+		for _, policy := range policies {
+			if policyErr := policy(ctx, data); policyErr != nil {
+				denial = policyErr
+				break
+			}
+		}
+	}
+
+	if denial != nil {
+		capitan.Warn(ctx, SignalAuthzDenied,
+			FieldName.Field(d.identity.Name()),
+			FieldIdentityID.Field(d.identity.ID().String()),
+			FieldError.Field(denial.Error()),
+		)
+		var zero T
+		return zero, &Error[T]{
+			Path:      []Identity{d.identity},
+			InputData: errorInput(data),
+			Err:       denial,
+			Timestamp: time.Now(),
+		}
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, d.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{d.identity},
+		}
+	}
+	return result, nil
+}
+
+// AddPolicy appends an authorization policy.
+func (d *DenyByDefault[T]) AddPolicy(policy func(context.Context, T) error) *DenyByDefault[T] {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.policies = append(d.policies, policy)
+	return d
+}
+
+// SetProcessor updates the guarded processor.
+func (d *DenyByDefault[T]) SetProcessor(processor Chainable[T]) *DenyByDefault[T] {
+	noteChildren(d.identity, processor)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.processor = processor
+	return d
+}
+
+// Identity returns the identity of this connector.
+func (d *DenyByDefault[T]) Identity() Identity {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (d *DenyByDefault[T]) Schema() Node {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return Node{
+		Identity: d.identity,
+		Type:     "denybydefault",
+		Flow:     DenyByDefaultFlow{Processor: d.processor.Schema()},
+		Metadata: map[string]any{
+			"policies": len(d.policies),
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (d *DenyByDefault[T]) Close() error {
+	d.closeOnce.Do(func() {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+		d.closeErr = d.processor.Close()
+	})
+	return d.closeErr
+}
@@ -0,0 +1,193 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+type testPrincipal struct {
+	ID    string
+	Admin bool
+}
+
+func TestPrincipalContext(t *testing.T) {
+	t.Run("Round Trip", func(t *testing.T) {
+		ctx := WithPrincipal(context.Background(), testPrincipal{ID: "u-1"})
+		p, ok := PrincipalFrom[testPrincipal](ctx)
+		if !ok || p.ID != "u-1" {
+			t.Errorf("expected principal u-1, got %+v (ok=%v)", p, ok)
+		}
+	})
+
+	t.Run("Missing Principal", func(t *testing.T) {
+		if _, ok := PrincipalFrom[testPrincipal](context.Background()); ok {
+			t.Error("expected no principal")
+		}
+		var nilCtx context.Context
+		if _, ok := PrincipalFrom[testPrincipal](nilCtx); ok {
+			t.Error("expected no principal for nil context")
+		}
+	})
+
+	t.Run("Types Do Not Collide", func(t *testing.T) {
+		ctx := WithPrincipal(context.Background(), testPrincipal{ID: "u-1"})
+		ctx = WithPrincipal(ctx, "service-account")
+		p, _ := PrincipalFrom[testPrincipal](ctx)
+		s, _ := PrincipalFrom[string](ctx)
+		if p.ID != "u-1" || s != "service-account" {
+			t.Errorf("expected both principals, got %+v and %q", p, s)
+		}
+	})
+}
+
+func TestAuthz(t *testing.T) {
+	adminOnly := RequirePrincipal(func(_ context.Context, p testPrincipal, _ int) error {
+		if !p.Admin {
+			return ErrForbidden
+		}
+		return nil
+	})
+	authz := Authz(NewIdentity("admin-only", ""), adminOnly)
+
+	t.Run("Allows", func(t *testing.T) {
+		ctx := WithPrincipal(context.Background(), testPrincipal{ID: "a", Admin: true})
+		result, err := authz.Process(ctx, 42)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != 42 {
+			t.Errorf("expected data to pass through, got %d", result)
+		}
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		ctx := WithPrincipal(context.Background(), testPrincipal{ID: "b"})
+		_, err := authz.Process(ctx, 42)
+		if !errors.Is(err, ErrForbidden) {
+			t.Fatalf("expected ErrForbidden, got %v", err)
+		}
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "admin-only" {
+			t.Errorf("expected pipeline error with path, got %v", err)
+		}
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		_, err := authz.Process(context.Background(), 42)
+		if !errors.Is(err, ErrUnauthenticated) {
+			t.Fatalf("expected ErrUnauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("RequirePrincipal Without Check", func(t *testing.T) {
+		policy := RequirePrincipal[testPrincipal, int](nil)
+		if err := policy(WithPrincipal(context.Background(), testPrincipal{}), 1); err != nil {
+			t.Errorf("expected allow, got %v", err)
+		}
+	})
+
+	t.Run("Emits Denied Signal", func(t *testing.T) {
+		var name string
+		listener := capitan.Hook(SignalAuthzDenied, func(_ context.Context, e *capitan.Event) {
+			name, _ = FieldName.From(e)
+		})
+		defer listener.Close()
+
+		_, _ = authz.Process(context.Background(), 1) //nolint:errcheck // denial expected
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if name != "admin-only" {
+			t.Errorf("expected signal from 'admin-only', got %q", name)
+		}
+	})
+}
+
+func TestDenyByDefault(t *testing.T) {
+	inner := Transform(NewIdentity("double", ""), func(_ context.Context, n int) int { return n * 2 })
+
+	t.Run("No Policies Denies", func(t *testing.T) {
+		guard := NewDenyByDefault(NewIdentity("guard", ""), inner)
+		_, err := guard.Process(context.Background(), 5)
+		if !errors.Is(err, ErrForbidden) {
+			t.Fatalf("expected ErrForbidden, got %v", err)
+		}
+	})
+
+	t.Run("All Policies Allow", func(t *testing.T) {
+		guard := NewDenyByDefault(NewIdentity("guard", ""), inner,
+			RequirePrincipal[testPrincipal, int](nil),
+		)
+		result, err := guard.Process(WithPrincipal(context.Background(), testPrincipal{ID: "x"}), 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != 10 {
+			t.Errorf("expected 10, got %d", result)
+		}
+	})
+
+	t.Run("Any Policy Denies", func(t *testing.T) {
+		called := false
+		probe := Effect(NewIdentity("probe", ""), func(_ context.Context, _ int) error {
+			called = true
+			return nil
+		})
+		guard := NewDenyByDefault(NewIdentity("guard", ""), probe,
+			func(_ context.Context, _ int) error { return nil },
+		).AddPolicy(func(_ context.Context, _ int) error { return ErrForbidden })
+
+		_, err := guard.Process(context.Background(), 5)
+		if !errors.Is(err, ErrForbidden) {
+			t.Fatalf("expected ErrForbidden, got %v", err)
+		}
+		if called {
+			t.Error("guarded processor must not run when denied")
+		}
+	})
+
+	t.Run("Wraps Inner Errors", func(t *testing.T) {
+		failing := Apply(NewIdentity("fail", ""), func(_ context.Context, n int) (int, error) {
+			return n, errors.New("boom")
+		})
+		guard := NewDenyByDefault(NewIdentity("guard", ""), inner,
+			func(_ context.Context, _ int) error { return nil },
+		).SetProcessor(failing)
+
+		_, err := guard.Process(context.Background(), 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected pipeline error, got %v", err)
+		}
+		if len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "guard" || pipeErr.Path[1].Name() != "fail" {
+			t.Errorf("unexpected path: %v", pipeErr.Path)
+		}
+	})
+
+	t.Run("Schema And Close", func(t *testing.T) {
+		guard := NewDenyByDefault(NewIdentity("guard", ""), inner,
+			func(_ context.Context, _ int) error { return nil },
+		)
+		schema := guard.Schema()
+		if schema.Type != "denybydefault" {
+			t.Errorf("expected type denybydefault, got %s", schema.Type)
+		}
+		flow, ok := DenyByDefaultKey.From(schema)
+		if !ok || flow.Processor.Identity.Name() != "double" {
+			t.Errorf("expected processor child in flow, got %+v", schema.Flow)
+		}
+		if schema.Metadata["policies"] != 1 {
+			t.Errorf("expected 1 policy in metadata, got %v", schema.Metadata["policies"])
+		}
+		if guard.Identity().Name() != "guard" {
+			t.Errorf("unexpected identity %v", guard.Identity())
+		}
+		if err := guard.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package pipz
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -29,9 +30,18 @@ import (
 // For example, with baseDelay=1s and maxAttempts=5:
 //
 //	Delays: 1s, 2s, 4s, 8s (total wait: 15s plus processing time)
+//
+// When a failed attempt returns a RetryAfterError, Backoff waits the delay
+// the upstream requested instead of its own for that attempt; the
+// exponential schedule resumes afterwards.
+//
+// With SetStash, the input is stashed, compressed or spilled to disk, while
+// Backoff waits, bounding memory when many large items are backing off.
 type Backoff[T any] struct {
 	processor   Chainable[T]
 	clock       clockz.Clock
+	stash       Stash[T]
+	budget      *AttemptBudget
 	identity    Identity
 	baseDelay   time.Duration
 	mu          sync.RWMutex
@@ -54,16 +64,46 @@ func NewBackoff[T any](identity Identity, processor Chainable[T], maxAttempts in
 	}
 }
 
+// NewBackoffWithOptions creates a Backoff from functional options.
+// It accepts WithMaxAttempts (default 3), WithBaseDelay (default 100ms), and
+// WithClock. Invalid or inapplicable options return an error wrapping
+// ErrInvalidOption.
+func NewBackoffWithOptions[T any](identity Identity, processor Chainable[T], opts ...Option) (*Backoff[T], error) {
+	cfg, err := newConfig("backoff", Config{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+	}, optMaxAttempts|optBaseDelay|optClock, 0, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	b := NewBackoff(identity, processor, cfg.MaxAttempts, cfg.BaseDelay)
+	b.clock = cfg.Clock
+	return b, nil
+}
+
 // Process implements the Chainable interface.
 func (b *Backoff[T]) Process(ctx context.Context, data T) (result T, err error) {
-	defer recoverFromPanic(&result, &err, b.identity, data)
+	defer recoverFromInputPanic(&result, &err, b.identity, &data)
+
+	ctx, guardErr := enterDepth(ctx, b, b.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
 	b.mu.RLock()
 	processor := b.processor
 	maxAttempts := b.maxAttempts
 	baseDelay := b.baseDelay
+	stash := b.stash
+	budget := b.budget
 	clock := b.getClock()
 	b.mu.RUnlock()
 
+	maxAttempts, ctx = allowedAttempts(ctx, b.identity, maxAttempts)
+	if budget != nil {
+		ctx = budget.earn(ctx)
+	}
+
 	var lastErr error
 	var lastResult T
 	delay := baseDelay
@@ -75,38 +115,76 @@ func (b *Backoff[T]) Process(ctx context.Context, data T) (result T, err error)
 			return result, nil
 		}
 
+		// Skips and early exits are deliberate outcomes, not failures to retry
+		if isControl(err) {
+			return result, err
+		}
+
 		// Attempt failed
 		lastErr = err
 		lastResult = result
 
+		// Stop retrying once the shared attempt budget is spent
+		if i < maxAttempts-1 && budget != nil && !budget.spend() {
+			emitBudgetExhausted(ctx, b.identity, i+1)
+			break
+		}
+
 		// Don't sleep after the last attempt
 		if i < maxAttempts-1 {
+			// Wait as long as the upstream asked, if it did
+			wait := delay
+			if requested, ok := retryAfterDelay(err); ok {
+				wait = requested
+			}
+
 			// Emit backoff waiting signal
 			nextDelay := delay * 2
-			capitan.Warn(context.Background(), SignalBackoffWaiting,
+			capitan.Warn(ctx, SignalBackoffWaiting,
 				FieldName.Field(b.identity.Name()),
 				FieldIdentityID.Field(b.identity.ID().String()),
 				FieldAttempt.Field(i+1),
 				FieldMaxAttempts.Field(maxAttempts),
-				FieldDelay.Field(delay.Seconds()),
+				FieldDelay.Field(wait.Seconds()),
 				FieldNextDelay.Field(nextDelay.Seconds()),
 				FieldTimestamp.Field(float64(time.Now().Unix())),
 			)
 
+			// Stash the input while waiting; the failed attempt's result and
+			// error may hold it too, and are superseded by the next attempt
+			restore := stashInput(stash, &data)
+			if restore != nil {
+				var zero T
+				lastErr, lastResult = nil, zero
+			}
+
 			select {
-			case <-clock.After(delay):
+			case <-clock.After(wait):
 				delay = nextDelay // Exponential backoff
 			case <-ctx.Done():
 				// Context canceled/timed out
+				if restore != nil {
+					data, _ = restore()
+				}
 				return data, &Error[T]{
 					Err:       ctx.Err(),
-					InputData: data,
+					InputData: errorInput(data),
 					Path:      []Identity{b.identity},
 					Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
 					Canceled:  errors.Is(ctx.Err(), context.Canceled),
 					Timestamp: time.Now(),
 				}
 			}
+			if restore != nil {
+				var restoreErr error
+				if data, restoreErr = restore(); restoreErr != nil {
+					return data, &Error[T]{
+						Err:       fmt.Errorf("restoring stashed input: %w", restoreErr),
+						Path:      []Identity{b.identity},
+						Timestamp: time.Now(),
+					}
+				}
+			}
 		}
 	}
 
@@ -115,13 +193,13 @@ func (b *Backoff[T]) Process(ctx context.Context, data T) (result T, err error)
 		var pipeErr *Error[T]
 		if errors.As(lastErr, &pipeErr) {
 			// Prepend this backoff's identity to the path
-			pipeErr.Path = append([]Identity{b.identity}, pipeErr.Path...)
+			pipeErr.prependPath(ctx, b.identity)
 			return data, pipeErr
 		}
 		// Handle non-pipeline errors by wrapping them
 		return data, &Error[T]{
 			Timestamp: time.Now(),
-			InputData: data,
+			InputData: errorInput(data),
 			Err:       lastErr,
 			Path:      []Identity{b.identity},
 		}
@@ -148,6 +226,48 @@ func (b *Backoff[T]) SetBaseDelay(d time.Duration) *Backoff[T] {
 	return b
 }
 
+// SetBudget makes retries spend from budget, shared with other Retry,
+// Backoff, and Hedge connectors in front of the same dependency. Once it
+// is spent, the last failure is returned without waiting for further
+// attempts. Nil removes the budget.
+func (b *Backoff[T]) SetBudget(budget *AttemptBudget) *Backoff[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.budget = budget
+	return b
+}
+
+// SetStash sets where the input is held while waiting between attempts,
+// such as NewCompressStash or NewSpillStash. Nil keeps it in memory.
+func (b *Backoff[T]) SetStash(stash Stash[T]) *Backoff[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stash = stash
+	return b
+}
+
+// Reconfigure atomically updates the attempt limit and base delay from
+// WithMaxAttempts and WithBaseDelay. In-flight calls finish with the settings
+// they started with. Invalid or inapplicable options leave the connector
+// unchanged and return an error wrapping ErrInvalidOption.
+func (b *Backoff[T]) Reconfigure(ctx context.Context, opts ...Option) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cfg, err := newConfig("backoff", Config{
+		MaxAttempts: b.maxAttempts,
+		BaseDelay:   b.baseDelay,
+	}, optMaxAttempts|optBaseDelay, 0, opts)
+	if err != nil {
+		return err
+	}
+
+	b.maxAttempts = cfg.MaxAttempts
+	b.baseDelay = cfg.BaseDelay
+	emitReconfigured(ctx, b.identity, cfg)
+	return nil
+}
+
 // GetMaxAttempts returns the current maximum attempts setting.
 func (b *Backoff[T]) GetMaxAttempts() int {
 	b.mu.RLock()
@@ -0,0 +1,276 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// ErrBatchBudgetExhausted is returned by a BudgetTimeout when its batch has
+// no time left for the item.
+var ErrBatchBudgetExhausted = errors.New("batch budget exhausted")
+
+// batchBudgetKey is the context key for the batch budget.
+type batchBudgetKey struct{}
+
+// BatchBudget divides the total time allowed for a batch among its items,
+// so the first slow items cannot starve the rest. It is attached to the
+// context with WithBatchBudget and consulted by BudgetTimeout connectors,
+// which run each item under its share.
+//
+// By default every item gets an equal share of the total, fixed when the
+// budget is created. With SetRebalance, each item's share is instead
+// computed when it starts, from the time actually left and the items not
+// yet started, so time saved by items finishing early goes to the rest.
+// SetParallelism accounts for items processed concurrently.
+//
+// Example:
+//
+//	budget := pipz.NewBatchBudget(30*time.Second, len(batch)).SetRebalance(true)
+//	ctx = pipz.WithBatchBudget(ctx, budget)
+//	for _, item := range batch {
+//	    results = append(results, perItem.Process(ctx, item))
+//	}
+type BatchBudget struct {
+	start       time.Time
+	clock       clockz.Clock
+	total       time.Duration
+	items       int
+	started     int
+	parallelism int
+	rebalance   bool
+	mu          sync.Mutex
+}
+
+// NewBatchBudget creates a budget of total for items items, starting now.
+func NewBatchBudget(total time.Duration, items int) *BatchBudget {
+	return &BatchBudget{
+		start:       time.Now(),
+		total:       total,
+		items:       items,
+		parallelism: 1,
+	}
+}
+
+// WithBatchBudget returns a context carrying budget for the BudgetTimeout
+// connectors processing the batch.
+func WithBatchBudget(ctx context.Context, budget *BatchBudget) context.Context {
+	return context.WithValue(ctx, batchBudgetKey{}, budget)
+}
+
+// BatchBudgetFromContext returns the batch budget carried by ctx, if any.
+func BatchBudgetFromContext(ctx context.Context) (*BatchBudget, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	budget, ok := ctx.Value(batchBudgetKey{}).(*BatchBudget)
+	return budget, ok
+}
+
+// reserve starts an item and returns its share of the budget. The share
+// never extends past the end of the batch; zero means no time is left.
+func (b *BatchBudget) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	left := b.total - b.getClock().Since(b.start)
+	if left <= 0 {
+		return 0
+	}
+	items := b.items
+	if b.rebalance {
+		items = b.items - b.started
+	}
+	b.started++
+	share := left
+	if items > b.parallelism {
+		if b.rebalance {
+			share = left * time.Duration(b.parallelism) / time.Duration(items)
+		} else {
+			share = b.total * time.Duration(b.parallelism) / time.Duration(items)
+		}
+	}
+	return min(share, left)
+}
+
+// Remaining returns the time left in the batch and the number of items not
+// yet started.
+func (b *BatchBudget) Remaining() (time.Duration, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return max(b.total-b.getClock().Since(b.start), 0), max(b.items-b.started, 0)
+}
+
+// SetRebalance chooses between fixed equal shares (false, the default) and
+// shares recomputed from the time actually left as each item starts.
+func (b *BatchBudget) SetRebalance(rebalance bool) *BatchBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rebalance = rebalance
+	return b
+}
+
+// SetParallelism declares how many items are processed at once, so each
+// share covers its fraction of the batch rather than of serial time.
+// Values below one are treated as one.
+func (b *BatchBudget) SetParallelism(n int) *BatchBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.parallelism = max(n, 1)
+	return b
+}
+
+// WithClock sets a custom clock for testing. The batch restarts at the
+// clock's current time.
+func (b *BatchBudget) WithClock(clock clockz.Clock) *BatchBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = clock
+	b.start = clock.Now()
+	return b
+}
+
+// getClock returns the clock to use.
+func (b *BatchBudget) getClock() clockz.Clock {
+	if b.clock == nil {
+		return clockz.RealClock
+	}
+	return b.clock
+}
+
+// BudgetTimeout runs each item under a timeout taken from the BatchBudget
+// in its context: the batch's remaining time divided among its remaining
+// items. An item arriving after the batch's time is spent fails with
+// ErrBatchBudgetExhausted without running. Without a budget in the context
+// the processor runs unbounded, so the same pipeline serves single items
+// and budgeted batches.
+//
+// Like Deadline, BudgetTimeout propagates the timeout through the context
+// and waits for the processor to return.
+//
+// Example:
+//
+//	var EnrichID = pipz.NewIdentity("enrich-item", "Enriches within the batch's budget")
+//	perItem := pipz.NewBudgetTimeout(EnrichID, enrich)
+//
+//	ctx = pipz.WithBatchBudget(ctx, pipz.NewBatchBudget(10*time.Second, len(batch)))
+type BudgetTimeout[T any] struct {
+	processor Chainable[T]
+	identity  Identity
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewBudgetTimeout creates a BudgetTimeout bounding processor by the batch
+// budget in each item's context.
+func NewBudgetTimeout[T any](identity Identity, processor Chainable[T]) *BudgetTimeout[T] {
+	return &BudgetTimeout[T]{
+		identity:  identity,
+		processor: processor,
+	}
+}
+
+// Process implements the Chainable interface.
+// Runs the processor under the item's share of the batch budget.
+func (b *BudgetTimeout[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, b.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, b, b.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	b.mu.RLock()
+	processor := b.processor
+	b.mu.RUnlock()
+
+	clock := clockz.RealClock
+	start := clock.Now()
+	if budget, ok := BatchBudgetFromContext(ctx); ok {
+		clock = budget.getClock()
+		start = clock.Now()
+		share := budget.reserve()
+		if share <= 0 {
+			_, pending := budget.Remaining()
+			capitan.Warn(ctx, SignalBatchBudgetExhausted,
+				FieldName.Field(b.identity.Name()),
+				FieldIdentityID.Field(b.identity.ID().String()),
+				FieldQueued.Field(pending),
+			)
+			return data, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(data),
+				Err:       fmt.Errorf("%w: %d items not yet started", ErrBatchBudgetExhausted, pending),
+				Path:      []Identity{b.identity},
+				Timeout:   true,
+			}
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, share)
+		defer cancel()
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, b.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{b.identity},
+			Duration:  clock.Since(start),
+			Timeout:   errors.Is(err, context.DeadlineExceeded),
+			Canceled:  errors.Is(err, context.Canceled),
+		}
+	}
+	return result, nil
+}
+
+// SetProcessor updates the bounded processor.
+func (b *BudgetTimeout[T]) SetProcessor(processor Chainable[T]) *BudgetTimeout[T] {
+	noteChildren(b.identity, processor)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.processor = processor
+	return b
+}
+
+// Identity returns the identity of this connector.
+func (b *BudgetTimeout[T]) Identity() Identity {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (b *BudgetTimeout[T]) Schema() Node {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return Node{
+		Identity: b.identity,
+		Type:     "budgettimeout",
+		Flow:     BudgetTimeoutFlow{Processor: b.processor.Schema()},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (b *BudgetTimeout[T]) Close() error {
+	b.closeOnce.Do(func() {
+		b.mu.RLock()
+		defer b.mu.RUnlock()
+		b.closeErr = b.processor.Close()
+	})
+	return b.closeErr
+}
@@ -0,0 +1,119 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+func TestBatchBudget(t *testing.T) {
+	t.Run("Fixed Equal Shares", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		budget := NewBatchBudget(10*time.Second, 5).WithClock(clock)
+		if share := budget.reserve(); share != 2*time.Second {
+			t.Fatalf("expected 2s share, got %v", share)
+		}
+		clock.Advance(500 * time.Millisecond)
+		if share := budget.reserve(); share != 2*time.Second {
+			t.Errorf("expected fixed 2s share, got %v", share)
+		}
+	})
+
+	t.Run("Rebalance Gives Saved Time To The Rest", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		budget := NewBatchBudget(10*time.Second, 5).WithClock(clock).SetRebalance(true)
+		budget.reserve()
+		clock.Advance(time.Second)
+		if share := budget.reserve(); share != 2250*time.Millisecond {
+			t.Errorf("expected 9s over 4 items, got %v", share)
+		}
+		left, pending := budget.Remaining()
+		if left != 9*time.Second || pending != 3 {
+			t.Errorf("expected 9s and 3 items remaining, got %v and %d", left, pending)
+		}
+	})
+
+	t.Run("Share Never Passes End Of Batch", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		budget := NewBatchBudget(10*time.Second, 2).WithClock(clock)
+		budget.reserve()
+		clock.Advance(9 * time.Second)
+		if share := budget.reserve(); share != time.Second {
+			t.Errorf("expected share capped at 1s left, got %v", share)
+		}
+	})
+
+	t.Run("Parallelism Widens Shares", func(t *testing.T) {
+		budget := NewBatchBudget(10*time.Second, 10).WithClock(clockz.NewFakeClock()).SetParallelism(5)
+		if share := budget.reserve(); share != 5*time.Second {
+			t.Errorf("expected 5s share with 5 workers, got %v", share)
+		}
+	})
+}
+
+func TestBudgetTimeout(t *testing.T) {
+	var deadlines []time.Duration
+	clock := clockz.NewFakeClock()
+	record := Effect(NewIdentity("record", ""), func(ctx context.Context, _ int) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			deadlines = append(deadlines, 0)
+			return nil
+		}
+		deadlines = append(deadlines, deadline.Sub(clock.Now()))
+		clock.Advance(time.Second)
+		return nil
+	})
+
+	t.Run("Applies Share As Timeout", func(t *testing.T) {
+		deadlines = nil
+		budget := NewBatchBudget(12*time.Second, 4).WithClock(clock).SetRebalance(true)
+		ctx := WithBatchBudget(context.Background(), budget)
+		perItem := NewBudgetTimeout(NewIdentity("per-item", ""), record)
+		for i := range 4 {
+			if _, err := perItem.Process(ctx, i); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		want := []time.Duration{3 * time.Second, 3666666666, 5 * time.Second, 9 * time.Second}
+		for i := range want {
+			if deadlines[i] != want[i] {
+				t.Errorf("item %d: expected %v, got %v", i, want[i], deadlines[i])
+			}
+		}
+	})
+
+	t.Run("Exhausted Budget Rejects", func(t *testing.T) {
+		budget := NewBatchBudget(time.Second, 3).WithClock(clock)
+		clock.Advance(2 * time.Second)
+		ctx := WithBatchBudget(context.Background(), budget)
+		_, err := NewBudgetTimeout(NewIdentity("per-item", ""), record).Process(ctx, 1)
+		if !errors.Is(err, ErrBatchBudgetExhausted) {
+			t.Fatalf("expected ErrBatchBudgetExhausted, got %v", err)
+		}
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.Timeout {
+			t.Errorf("expected timeout error, got %v", err)
+		}
+	})
+
+	t.Run("No Budget Runs Unbounded", func(t *testing.T) {
+		deadlines = nil
+		if _, err := NewBudgetTimeout(NewIdentity("per-item", ""), record).Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(deadlines) != 1 || deadlines[0] != 0 {
+			t.Errorf("expected no deadline, got %v", deadlines)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := NewBudgetTimeout(NewIdentity("per-item", ""), record).Schema()
+		if _, ok := BudgetTimeoutKey.From(node); !ok {
+			t.Fatalf("expected BudgetTimeoutFlow, got %T", node.Flow)
+		}
+	})
+}
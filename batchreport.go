@@ -0,0 +1,261 @@
+package pipz
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BatchReportEntry records one item that failed during a batch run.
+// Processor names the node that failed, the innermost on the error's path.
+// Keys holds the item's identifying fields, as returned by the report's
+// key extractor, so failed records can be found and rerun.
+type BatchReportEntry struct {
+	Timestamp   time.Time         `json:"timestamp"`
+	Keys        map[string]string `json:"keys,omitempty"`
+	Processor   string            `json:"processor"`
+	ProcessorID string            `json:"processor_id,omitempty"`
+	Path        string            `json:"path,omitempty"`
+	Category    string            `json:"category"`
+	Error       string            `json:"error"`
+}
+
+// BatchReportSummary counts a batch run's outcomes. ByProcessor counts the
+// failures attributed to each failing processor.
+type BatchReportSummary struct {
+	ByProcessor map[string]int `json:"by_processor"`
+	Processed   int            `json:"processed"`
+	Succeeded   int            `json:"succeeded"`
+	Failed      int            `json:"failed"`
+	Skipped     int            `json:"skipped"`
+}
+
+// BatchReport accumulates the item-level errors of a batch or ETL run and
+// writes them as a structured report when the run ends, with failure
+// counts per processor. Record every item's outcome; items skipped with
+// ErrSkip are counted but not reported as failures.
+//
+// BatchReport is safe for concurrent use, so items processed by parallel
+// workers can record into one report.
+//
+// Example:
+//
+//	report := pipz.NewBatchReport(func(o Order) map[string]string {
+//	    return map[string]string{"order_id": o.ID, "customer": o.CustomerID}
+//	})
+//	for _, order := range orders {
+//	    _, err := importPipeline.Process(ctx, order)
+//	    report.Record(order, err)
+//	}
+//	if err := report.WriteFile("import-errors.csv"); err != nil {
+//	    log.Printf("writing error report: %v", err)
+//	}
+type BatchReport[T any] struct {
+	keys        func(T) map[string]string
+	entries     []BatchReportEntry
+	byProcessor map[string]int
+	processed   int
+	failed      int
+	skipped     int
+	maxEntries  int
+	mu          sync.Mutex
+}
+
+// NewBatchReport creates a BatchReport. keys extracts the identifying
+// fields recorded with each failure; it may be nil.
+func NewBatchReport[T any](keys func(T) map[string]string) *BatchReport[T] {
+	return &BatchReport[T]{
+		keys:        keys,
+		byProcessor: make(map[string]int),
+	}
+}
+
+// SetMaxEntries bounds how many failures are kept for the report, so a run
+// where every item fails cannot exhaust memory. Failures past the bound are
+// still counted in the summary. Zero, the default, keeps every failure.
+func (r *BatchReport[T]) SetMaxEntries(n int) *BatchReport[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxEntries = max(n, 0)
+	return r
+}
+
+// Record records the outcome of processing item. A nil err counts as a
+// success.
+func (r *BatchReport[T]) Record(item T, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.processed++
+	if err == nil {
+		return
+	}
+	if errors.Is(err, ErrSkip) {
+		r.skipped++
+		return
+	}
+	r.failed++
+
+	entry := BatchReportEntry{
+		Timestamp: time.Now(),
+		Processor: unknownPath,
+		Category:  ErrorCategoryError,
+		Error:     err.Error(),
+	}
+	var pipeErr *Error[T]
+	if errors.As(err, &pipeErr) {
+		entry.Timestamp = pipeErr.Timestamp
+		entry.Path = pipeErr.pathString()
+		entry.Category = pipeErr.Category()
+		if pipeErr.Err != nil {
+			entry.Error = pipeErr.Err.Error()
+		}
+		if len(pipeErr.Path) > 0 {
+			failed := pipeErr.Path[len(pipeErr.Path)-1]
+			entry.Processor = failed.Name()
+			entry.ProcessorID = failed.ID().String()
+		}
+	}
+	r.byProcessor[entry.Processor]++
+
+	if r.maxEntries > 0 && len(r.entries) >= r.maxEntries {
+		return
+	}
+	if r.keys != nil {
+		entry.Keys = r.keys(item)
+	}
+	r.entries = append(r.entries, entry)
+}
+
+// Entries returns the recorded failures in recording order.
+func (r *BatchReport[T]) Entries() []BatchReportEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.entries)
+}
+
+// Summary returns the run's outcome counts.
+func (r *BatchReport[T]) Summary() BatchReportSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.summaryLocked()
+}
+
+// summaryLocked builds the summary; the caller holds mu.
+func (r *BatchReport[T]) summaryLocked() BatchReportSummary {
+	return BatchReportSummary{
+		ByProcessor: maps.Clone(r.byProcessor),
+		Processed:   r.processed,
+		Succeeded:   r.processed - r.failed - r.skipped,
+		Failed:      r.failed,
+		Skipped:     r.skipped,
+	}
+}
+
+// WriteJSON writes the report as a JSON document holding the summary and
+// every recorded failure.
+func (r *BatchReport[T]) WriteJSON(w io.Writer) error {
+	r.mu.Lock()
+	doc := struct {
+		Summary BatchReportSummary `json:"summary"`
+		Errors  []BatchReportEntry `json:"errors"`
+	}{r.summaryLocked(), slices.Clone(r.entries)}
+	r.mu.Unlock()
+
+	if doc.Errors == nil {
+		doc.Errors = []BatchReportEntry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// WriteCSV writes one row per recorded failure, with a column for each
+// key field after the fixed columns. Key columns are sorted by name; an
+// item missing a key leaves its cell empty.
+func (r *BatchReport[T]) WriteCSV(w io.Writer) error {
+	entries := r.Entries()
+
+	keySet := make(map[string]struct{})
+	for _, entry := range entries {
+		for k := range entry.Keys {
+			keySet[k] = struct{}{}
+		}
+	}
+	keys := slices.Sorted(maps.Keys(keySet))
+
+	cw := csv.NewWriter(w)
+	header := append([]string{"timestamp", "processor", "processor_id", "path", "category", "error"}, keys...)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		row := []string{
+			entry.Timestamp.Format(time.RFC3339Nano),
+			entry.Processor,
+			entry.ProcessorID,
+			entry.Path,
+			entry.Category,
+			entry.Error,
+		}
+		for _, k := range keys {
+			row = append(row, entry.Keys[k])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteSummaryCSV writes the failure count of each failing processor, most
+// failures first.
+func (r *BatchReport[T]) WriteSummaryCSV(w io.Writer) error {
+	counts := r.Summary().ByProcessor
+	processors := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		if counts[a] != counts[b] {
+			return counts[b] - counts[a]
+		}
+		return strings.Compare(a, b)
+	})
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"processor", "failures"}); err != nil {
+		return err
+	}
+	for _, processor := range processors {
+		if err := cw.Write([]string{processor, strconv.Itoa(counts[processor])}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteFile writes the report to the named file, as CSV when the name ends
+// in ".csv" and as JSON otherwise.
+func (r *BatchReport[T]) WriteFile(name string) (err error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if strings.EqualFold(filepath.Ext(name), ".csv") {
+		return r.WriteCSV(f)
+	}
+	return r.WriteJSON(f)
+}
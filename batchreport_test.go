@@ -0,0 +1,196 @@
+package pipz
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type batchRecord struct {
+	ID    string
+	Value int
+}
+
+func newBatchReportPipeline() *Sequence[batchRecord] {
+	return NewSequence(NewIdentity("import", ""),
+		Apply(NewIdentity("validate", ""), func(_ context.Context, r batchRecord) (batchRecord, error) {
+			if r.Value < 0 {
+				return r, errors.New("negative value")
+			}
+			return r, nil
+		}),
+		Apply(NewIdentity("filter", ""), func(_ context.Context, r batchRecord) (batchRecord, error) {
+			if r.Value == 0 {
+				return r, Skip("empty")
+			}
+			return r, nil
+		}),
+		Apply(NewIdentity("store", ""), func(_ context.Context, r batchRecord) (batchRecord, error) {
+			if r.Value > 100 {
+				return r, errors.New("value too large")
+			}
+			return r, nil
+		}),
+	)
+}
+
+func runBatchReport(t *testing.T, report *BatchReport[batchRecord]) {
+	t.Helper()
+	pipeline := newBatchReportPipeline()
+	for _, r := range []batchRecord{
+		{ID: "a", Value: 1},
+		{ID: "b", Value: -1},
+		{ID: "c", Value: 0},
+		{ID: "d", Value: 500},
+		{ID: "e", Value: -2},
+	} {
+		_, err := pipeline.Process(context.Background(), r)
+		report.Record(r, err)
+	}
+}
+
+func batchRecordKeys(r batchRecord) map[string]string {
+	return map[string]string{"id": r.ID}
+}
+
+func TestBatchReport(t *testing.T) {
+	t.Run("Records Failures And Summary", func(t *testing.T) {
+		report := NewBatchReport(batchRecordKeys)
+		runBatchReport(t, report)
+
+		summary := report.Summary()
+		if summary.Processed != 5 || summary.Succeeded != 1 || summary.Failed != 3 || summary.Skipped != 1 {
+			t.Errorf("unexpected summary: %+v", summary)
+		}
+		if summary.ByProcessor["validate"] != 2 || summary.ByProcessor["store"] != 1 {
+			t.Errorf("unexpected per-processor counts: %v", summary.ByProcessor)
+		}
+
+		entries := report.Entries()
+		if len(entries) != 3 {
+			t.Fatalf("expected 3 entries, got %d", len(entries))
+		}
+		first := entries[0]
+		if first.Processor != "validate" || first.Path != "import -> validate" || first.Error != "negative value" || first.Keys["id"] != "b" {
+			t.Errorf("unexpected entry: %+v", first)
+		}
+		if first.Category != ErrorCategoryError || first.ProcessorID == "" {
+			t.Errorf("expected category and processor ID, got %+v", first)
+		}
+	})
+
+	t.Run("Plain Errors", func(t *testing.T) {
+		report := NewBatchReport[batchRecord](nil)
+		report.Record(batchRecord{ID: "x"}, errors.New("decode failed"))
+
+		entries := report.Entries()
+		if len(entries) != 1 || entries[0].Processor != unknownPath || entries[0].Error != "decode failed" || entries[0].Keys != nil {
+			t.Errorf("unexpected entries: %+v", entries)
+		}
+	})
+
+	t.Run("Max Entries Keeps Counting", func(t *testing.T) {
+		report := NewBatchReport(batchRecordKeys).SetMaxEntries(1)
+		runBatchReport(t, report)
+
+		if len(report.Entries()) != 1 {
+			t.Errorf("expected 1 retained entry, got %d", len(report.Entries()))
+		}
+		if report.Summary().Failed != 3 {
+			t.Errorf("expected 3 failures counted, got %d", report.Summary().Failed)
+		}
+	})
+
+	t.Run("Write JSON", func(t *testing.T) {
+		report := NewBatchReport(batchRecordKeys)
+		runBatchReport(t, report)
+
+		var b strings.Builder
+		if err := report.WriteJSON(&b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var doc struct {
+			Summary BatchReportSummary `json:"summary"`
+			Errors  []BatchReportEntry `json:"errors"`
+		}
+		if err := json.Unmarshal([]byte(b.String()), &doc); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if doc.Summary.Failed != 3 || len(doc.Errors) != 3 || doc.Errors[1].Keys["id"] != "d" {
+			t.Errorf("unexpected document: %+v", doc)
+		}
+	})
+
+	t.Run("Write CSV", func(t *testing.T) {
+		report := NewBatchReport(func(r batchRecord) map[string]string {
+			if r.ID == "d" {
+				return map[string]string{"id": r.ID, "shard": "7"}
+			}
+			return batchRecordKeys(r)
+		})
+		runBatchReport(t, report)
+
+		var b strings.Builder
+		if err := report.WriteCSV(&b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rows, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid CSV: %v", err)
+		}
+		if len(rows) != 4 {
+			t.Fatalf("expected header and 3 rows, got %d", len(rows))
+		}
+		if got := strings.Join(rows[0], ","); got != "timestamp,processor,processor_id,path,category,error,id,shard" {
+			t.Errorf("unexpected header: %s", got)
+		}
+		if rows[1][1] != "validate" || rows[1][6] != "b" || rows[1][7] != "" {
+			t.Errorf("unexpected row: %v", rows[1])
+		}
+		if rows[2][1] != "store" || rows[2][5] != "value too large" || rows[2][7] != "7" {
+			t.Errorf("unexpected row: %v", rows[2])
+		}
+	})
+
+	t.Run("Write Summary CSV", func(t *testing.T) {
+		report := NewBatchReport(batchRecordKeys)
+		runBatchReport(t, report)
+
+		var b strings.Builder
+		if err := report.WriteSummaryCSV(&b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "processor,failures\nvalidate,2\nstore,1\n"; b.String() != want {
+			t.Errorf("expected %q, got %q", want, b.String())
+		}
+	})
+
+	t.Run("Write File By Extension", func(t *testing.T) {
+		report := NewBatchReport(batchRecordKeys)
+		runBatchReport(t, report)
+		dir := t.TempDir()
+
+		csvPath := filepath.Join(dir, "errors.csv")
+		if err := report.WriteFile(csvPath); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := os.ReadFile(csvPath)
+		if err != nil || !strings.HasPrefix(string(data), "timestamp,") {
+			t.Errorf("expected CSV file, got %q (%v)", data, err)
+		}
+
+		jsonPath := filepath.Join(dir, "errors.json")
+		if err := report.WriteFile(jsonPath); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err = os.ReadFile(jsonPath)
+		if err != nil || !json.Valid(data) {
+			t.Errorf("expected JSON file, got %q (%v)", data, err)
+		}
+	})
+}
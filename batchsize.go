@@ -0,0 +1,133 @@
+package pipz
+
+import (
+	"sync"
+	"time"
+)
+
+// Batch size tuning: each batch's latency gives the size that would have
+// met the target, assuming latency grows with size. The size moves toward
+// that estimate, quickly when shrinking and gradually when growing.
+const (
+	batchGrowSmoothing = 0.2 // Share of a larger estimate applied per batch
+	batchMaxGrowth     = 2.0 // Most the estimate may exceed the current size
+	batchMinShrink     = 0.5 // Most the size shrinks per batch
+)
+
+// BatchSizeController tunes a batch size from downstream feedback, so bulk
+// sinks such as database inserts and batch APIs get batches as large as
+// they can absorb within a target latency. After each batch, report its
+// size, processing latency, and failed items with Observe:
+//   - A batch over the target latency shrinks the size in proportion, by
+//     at most half, so the next batch should meet the target.
+//   - A full batch without failures under the target grows the size
+//     gradually toward what would meet the target, at most doubling it.
+//     Batches cut short by their window say nothing about larger sizes
+//     and leave it unchanged.
+//   - A batch whose error rate exceeds SetMaxErrorRate halves the size,
+//     since errors under load usually mean the sink is overwhelmed.
+//
+// The size stays within SetMinSize and SetMaxSize, 1 and 10000 by default.
+// Give a controller to BatchSubscription.SetBatchController, or consult
+// Size when assembling batches elsewhere.
+//
+// CRITICAL: BatchSizeController is STATEFUL. Create it once and reuse it.
+//
+// Example:
+//
+//	sizer := pipz.NewBatchSizeController(500*time.Millisecond, 100).
+//	    SetMaxSize(5000)
+//	sub := pipz.NewBatchSubscription(EventsID, kafkaSubscriber, nil, bulkInsert).
+//	    SetBatchController(sizer)
+type BatchSizeController struct {
+	target       time.Duration
+	size         float64
+	minSize      float64
+	maxSize      float64
+	maxErrorRate float64
+	mu           sync.Mutex
+}
+
+// NewBatchSizeController creates a BatchSizeController aiming for batches
+// processed within target, starting at initial items. By default a batch
+// with more than 10% of its items failed halves the size.
+func NewBatchSizeController(target time.Duration, initial int) *BatchSizeController {
+	return &BatchSizeController{
+		target:       target,
+		size:         float64(min(max(initial, 1), 10000)),
+		minSize:      1,
+		maxSize:      10000,
+		maxErrorRate: 0.1,
+	}
+}
+
+// Observe adjusts the size from a batch of size items that took latency to
+// process, failed of them failing. Batches of no items are ignored.
+func (c *BatchSizeController) Observe(size int, latency time.Duration, failed int) {
+	if size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case float64(failed)/float64(size) > c.maxErrorRate:
+		c.size *= batchMinShrink
+	case latency > c.target:
+		estimate := float64(size) * float64(c.target) / float64(latency)
+		c.size = min(c.size, max(estimate, c.size*batchMinShrink))
+	case failed == 0 && size >= int(c.size):
+		estimate := c.size * batchMaxGrowth
+		if latency > 0 {
+			estimate = min(estimate, float64(size)*float64(c.target)/float64(latency))
+		}
+		if estimate > c.size {
+			c.size += batchGrowSmoothing * (estimate - c.size)
+		}
+	}
+	c.size = min(max(c.size, c.minSize), c.maxSize)
+}
+
+// Size returns the current batch size.
+func (c *BatchSizeController) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return max(int(c.size), 1)
+}
+
+// SetTarget sets the latency each batch should be processed within.
+func (c *BatchSizeController) SetTarget(target time.Duration) *BatchSizeController {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.target = target
+	return c
+}
+
+// SetMinSize sets the smallest batch size. Values below 1 are treated as 1.
+func (c *BatchSizeController) SetMinSize(n int) *BatchSizeController {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.minSize = float64(max(n, 1))
+	c.maxSize = max(c.maxSize, c.minSize)
+	c.size = max(c.size, c.minSize)
+	return c
+}
+
+// SetMaxSize sets the largest batch size.
+func (c *BatchSizeController) SetMaxSize(n int) *BatchSizeController {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxSize = float64(max(n, 1))
+	c.minSize = min(c.minSize, c.maxSize)
+	c.size = min(c.size, c.maxSize)
+	return c
+}
+
+// SetMaxErrorRate sets the share of failed items, from 0 to 1, above which
+// a batch halves the size.
+func (c *BatchSizeController) SetMaxErrorRate(rate float64) *BatchSizeController {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxErrorRate = min(max(rate, 0), 1)
+	return c
+}
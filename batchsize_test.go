@@ -0,0 +1,83 @@
+package pipz
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBatchSizeController(t *testing.T) {
+	t.Run("Shrinks Slow Batches In Proportion", func(t *testing.T) {
+		c := NewBatchSizeController(100*time.Millisecond, 100)
+		c.Observe(100, 125*time.Millisecond, 0)
+		if c.Size() != 80 {
+			t.Errorf("expected 80, got %d", c.Size())
+		}
+		c.Observe(80, time.Second, 0)
+		if c.Size() != 40 {
+			t.Errorf("expected shrink capped at half, got %d", c.Size())
+		}
+	})
+
+	t.Run("Grows Full Fast Batches Gradually", func(t *testing.T) {
+		c := NewBatchSizeController(100*time.Millisecond, 100)
+		c.Observe(100, 50*time.Millisecond, 0)
+		if c.Size() != 120 {
+			t.Errorf("expected 120, got %d", c.Size())
+		}
+		c.Observe(10, time.Millisecond, 0)
+		if c.Size() != 120 {
+			t.Errorf("expected partial batch to leave the size, got %d", c.Size())
+		}
+		c.Observe(120, 0, 0)
+		if c.Size() != 144 {
+			t.Errorf("expected growth capped at double, got %d", c.Size())
+		}
+	})
+
+	t.Run("Halves On Errors", func(t *testing.T) {
+		c := NewBatchSizeController(time.Second, 100)
+		c.Observe(100, time.Millisecond, 10)
+		if c.Size() != 100 {
+			t.Errorf("expected error rate at the limit to be tolerated, got %d", c.Size())
+		}
+		c.Observe(100, time.Millisecond, 11)
+		if c.Size() != 50 {
+			t.Errorf("expected 50, got %d", c.Size())
+		}
+		c.SetMaxErrorRate(0)
+		c.Observe(50, time.Millisecond, 1)
+		if c.Size() != 25 {
+			t.Errorf("expected 25, got %d", c.Size())
+		}
+	})
+
+	t.Run("Bounds", func(t *testing.T) {
+		c := NewBatchSizeController(time.Second, 100).SetMinSize(60).SetMaxSize(110)
+		c.Observe(100, 10*time.Second, 0)
+		if c.Size() != 60 {
+			t.Errorf("expected min 60, got %d", c.Size())
+		}
+		for range 20 {
+			c.Observe(c.Size(), time.Millisecond, 0)
+		}
+		if c.Size() != 110 {
+			t.Errorf("expected max 110, got %d", c.Size())
+		}
+		c.SetMaxSize(50)
+		if c.Size() != 50 {
+			t.Errorf("expected size lowered to new max, got %d", c.Size())
+		}
+		c.Observe(0, time.Hour, 0)
+		if c.Size() != 50 {
+			t.Errorf("expected empty batch ignored, got %d", c.Size())
+		}
+	})
+
+	t.Run("Target Change", func(t *testing.T) {
+		c := NewBatchSizeController(time.Second, 100).SetTarget(100 * time.Millisecond)
+		c.Observe(100, 200*time.Millisecond, 0)
+		if c.Size() != 50 {
+			t.Errorf("expected 50, got %d", c.Size())
+		}
+	})
+}
@@ -0,0 +1,425 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// ErrBatchNotCommitted marks messages that were processed successfully but
+// not acked because other messages in their batch failed and could not be
+// nacked individually.
+var ErrBatchNotCommitted = errors.New("batch not committed")
+
+// BatchError reports a partial batch failure. Return it, or an error
+// wrapping it, from a batch processor to fail only some items; the other
+// items count as processed. Failed is keyed by index into the batch the
+// processor received.
+type BatchError struct {
+	Failed map[int]error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d batch items failed", len(e.Failed))
+}
+
+// BatchAcker is implemented by Subscribers that can acknowledge several
+// messages in one call, such as committing the highest offset of a batch.
+// BatchSubscription prefers it to acking messages one by one.
+type BatchAcker interface {
+	AckBatch(ctx context.Context, msgs []Message) error
+}
+
+type batchKey struct{}
+
+// MessagesFromContext returns the messages of the batch being processed by
+// a BatchSubscription, in batch order. Messages that failed to decode are
+// included, so indices match the batch only when every message decoded.
+func MessagesFromContext(ctx context.Context) ([]Message, bool) {
+	msgs, ok := ctx.Value(batchKey{}).([]Message)
+	return msgs, ok
+}
+
+// BatchSubscription runs messages from a Subscriber through a pipeline in
+// micro-batches. It accumulates messages until the batch holds the
+// configured size or the batch window has passed since its first message,
+// then decodes them and processes the batch as one []T. Batches are
+// processed one at a time, in order.
+//
+// A batch is committed as a whole: on success every message is acked, in a
+// single call when the Subscriber implements BatchAcker. Messages that fail
+// to decode, and items the processor reports in a BatchError, are nacked
+// individually while the rest are acked, but only if every failed message
+// provides Nack. Otherwise nothing is acked, so the broker redelivers the
+// whole batch; the successful messages are nacked too, with an error
+// wrapping ErrBatchNotCommitted. Any other processor error fails the whole
+// batch.
+//
+// Failures go to the error handler with the failed messages: the default
+// emits a subscription.batch-failed signal and continues, while a handler
+// that returns an error stops Run with it.
+//
+// With SetBatchController, the batch size follows a BatchSizeController
+// fed with each batch's processing latency and failures, instead of the
+// fixed SetBatchSize.
+//
+// Example:
+//
+//	sub := pipz.NewBatchSubscription(EventsID, kafkaSubscriber,
+//	    pipz.JSONDecoder[Event](), bulkInsert).
+//	    SetBatchSize(500).
+//	    SetBatchWindow(200 * time.Millisecond)
+//
+//	if err := sub.Run(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+type BatchSubscription[T any] struct {
+	clock      clockz.Clock
+	identity   Identity
+	subscriber Subscriber
+	decode     Decoder[T]
+	processor  Chainable[[]T]
+	onError    func(context.Context, []Message, error) error
+	sizer      *BatchSizeController
+	size       int
+	window     time.Duration
+	timeout    time.Duration
+	mu         sync.RWMutex
+}
+
+// NewBatchSubscription creates a BatchSubscription with batches of up to
+// 100 messages and a one second batch window. A nil decode decodes JSON.
+func NewBatchSubscription[T any](identity Identity, subscriber Subscriber, decode Decoder[T], processor Chainable[[]T]) *BatchSubscription[T] {
+	if decode == nil {
+		decode = JSONDecoder[T]()
+	}
+	return &BatchSubscription[T]{
+		identity:   identity,
+		subscriber: subscriber,
+		decode:     decode,
+		processor:  processor,
+		size:       100,
+		window:     time.Second,
+	}
+}
+
+// SetBatchSize sets the most messages in a batch. Values below 1 are
+// treated as 1. Takes effect on the next Run.
+func (b *BatchSubscription[T]) SetBatchSize(n int) *BatchSubscription[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n < 1 {
+		n = 1
+	}
+	b.size = n
+	return b
+}
+
+// SetBatchController makes the batch size follow sizer, which observes the
+// latency and failures of every processed batch. Canceled batches are not
+// observed. Nil restores the fixed SetBatchSize. Takes effect on the next
+// Run.
+func (b *BatchSubscription[T]) SetBatchController(sizer *BatchSizeController) *BatchSubscription[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sizer = sizer
+	return b
+}
+
+// SetBatchWindow sets how long a batch waits for more messages after its
+// first one arrives. Zero waits until the batch is full. Takes effect on
+// the next Run.
+func (b *BatchSubscription[T]) SetBatchWindow(d time.Duration) *BatchSubscription[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.window = d
+	return b
+}
+
+// SetBatchTimeout bounds processing of each batch. Zero disables the
+// timeout.
+func (b *BatchSubscription[T]) SetBatchTimeout(d time.Duration) *BatchSubscription[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.timeout = d
+	return b
+}
+
+// SetErrorHandler sets the error policy. The handler receives the failed
+// messages of each batch with the joined error; returning nil continues
+// with the next batch, returning an error stops Run with it.
+func (b *BatchSubscription[T]) SetErrorHandler(handler func(context.Context, []Message, error) error) *BatchSubscription[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onError = handler
+	return b
+}
+
+// WithClock sets a custom clock for the batch window.
+func (b *BatchSubscription[T]) WithClock(clock clockz.Clock) *BatchSubscription[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = clock
+	return b
+}
+
+// Identity returns the identity of this subscription.
+func (b *BatchSubscription[T]) Identity() Identity {
+	return b.identity
+}
+
+// getClock returns the clock to use.
+func (b *BatchSubscription[T]) getClock() clockz.Clock {
+	if b.clock == nil {
+		return clockz.RealClock
+	}
+	return b.clock
+}
+
+// Run receives and processes batches until ctx is canceled, the subscriber
+// returns ErrSubscriptionClosed, or the error handler stops it. A partial
+// batch is processed when the subscription closes or Receive fails; on
+// cancellation it is nacked instead. Cancellation and a closed subscription
+// return nil; a receive failure or handler error is returned.
+func (b *BatchSubscription[T]) Run(ctx context.Context) error {
+	b.mu.RLock()
+	size := b.size
+	sizer := b.sizer
+	window := b.window
+	clock := b.getClock()
+	b.mu.RUnlock()
+
+	var (
+		batch   []Message
+		flushAt time.Time
+	)
+	for {
+		recvCtx, cancel := ctx, context.CancelFunc(func() {})
+		if len(batch) > 0 && window > 0 {
+			remaining := flushAt.Sub(clock.Now())
+			if remaining <= 0 {
+				if err := b.flush(ctx, batch); err != nil {
+					return err
+				}
+				batch = nil
+				continue
+			}
+			recvCtx, cancel = clock.WithTimeout(ctx, remaining)
+		}
+		msg, err := b.subscriber.Receive(recvCtx)
+		windowClosed := recvCtx.Err() != nil
+		cancel()
+
+		if err != nil {
+			switch {
+			case ctx.Err() != nil:
+				b.abandon(ctx, batch, ctx.Err())
+				return nil
+			case windowClosed && len(batch) > 0:
+				if flushErr := b.flush(ctx, batch); flushErr != nil {
+					return flushErr
+				}
+				batch = nil
+				continue
+			}
+			if len(batch) > 0 {
+				if flushErr := b.flush(ctx, batch); flushErr != nil {
+					return flushErr
+				}
+			}
+			if errors.Is(err, ErrSubscriptionClosed) {
+				return nil
+			}
+			return fmt.Errorf("receive: %w", err)
+		}
+
+		if len(batch) == 0 {
+			flushAt = clock.Now().Add(window)
+		}
+		batch = append(batch, msg)
+		if sizer != nil {
+			size = sizer.Size()
+		}
+		if len(batch) >= size {
+			if err := b.flush(ctx, batch); err != nil {
+				return err
+			}
+			batch = nil
+		}
+	}
+}
+
+// flush decodes and processes one batch, then settles its messages.
+func (b *BatchSubscription[T]) flush(ctx context.Context, msgs []Message) error {
+	b.mu.RLock()
+	decode := b.decode
+	processor := b.processor
+	timeout := b.timeout
+	sizer := b.sizer
+	clock := b.getClock()
+	b.mu.RUnlock()
+
+	batchCtx := context.WithValue(ctx, batchKey{}, msgs)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		batchCtx, cancel = context.WithTimeout(batchCtx, timeout)
+		defer cancel()
+	}
+
+	failed := make(map[int]error)
+	items := make([]T, 0, len(msgs))
+	index := make([]int, 0, len(msgs)) // item index -> message index
+	for i, msg := range msgs {
+		decodeErr := recoverCall(batchCtx, func() error {
+			data, err := decode(msg.Payload)
+			if err != nil {
+				return err
+			}
+			items = append(items, data)
+			index = append(index, i)
+			return nil
+		})
+		if decodeErr != nil {
+			failed[i] = fmt.Errorf("decode: %w", decodeErr)
+		}
+	}
+
+	if len(items) > 0 {
+		start := clock.Now()
+		processErr := recoverCall(batchCtx, func() error {
+			_, err := processor.Process(batchCtx, items)
+			return err
+		})
+		latency := clock.Since(start)
+		processFailed := 0
+		var batchErr *BatchError
+		switch {
+		case processErr == nil || isControl(processErr):
+		case errors.As(processErr, &batchErr) && len(batchErr.Failed) > 0:
+			for i, itemErr := range batchErr.Failed {
+				if i >= 0 && i < len(index) {
+					failed[index[i]] = itemErr
+					processFailed++
+				}
+			}
+		default:
+			for _, i := range index {
+				failed[i] = processErr
+			}
+			processFailed = len(index)
+		}
+		if sizer != nil && ctx.Err() == nil {
+			b.observeBatch(ctx, sizer, len(items), latency, processFailed)
+		}
+	}
+
+	return b.settle(batchCtx, msgs, failed)
+}
+
+// observeBatch feeds a processed batch to sizer, signaling when the batch
+// size changes. Decode failures are left out, as they reflect the data
+// rather than the load on the processor.
+func (b *BatchSubscription[T]) observeBatch(ctx context.Context, sizer *BatchSizeController, items int, latency time.Duration, failed int) {
+	before := sizer.Size()
+	sizer.Observe(items, latency, failed)
+	if after := sizer.Size(); after != before {
+		capitan.Debug(ctx, SignalBatchSizeChanged,
+			FieldName.Field(b.identity.Name()),
+			FieldIdentityID.Field(b.identity.ID().String()),
+			FieldBatchSize.Field(after),
+		)
+	}
+}
+
+// settle acks and nacks a processed batch and reports failures.
+func (b *BatchSubscription[T]) settle(ctx context.Context, msgs []Message, failed map[int]error) error {
+	// Acking around failures is only safe if each of them can be nacked.
+	if len(failed) > 0 && len(failed) < len(msgs) {
+		for i := range failed {
+			if msgs[i].Nack == nil {
+				notCommitted := fmt.Errorf("%w: %d of %d messages failed", ErrBatchNotCommitted, len(failed), len(msgs))
+				for j := range msgs {
+					if _, ok := failed[j]; !ok {
+						failed[j] = notCommitted
+					}
+				}
+				break
+			}
+		}
+	}
+
+	var errs []error
+	acked := make([]Message, 0, len(msgs)-len(failed))
+	for i, msg := range msgs {
+		if _, ok := failed[i]; !ok {
+			acked = append(acked, msg)
+		}
+	}
+	if len(acked) > 0 {
+		if acker, ok := b.subscriber.(BatchAcker); ok {
+			if ackErr := recoverCall(ctx, func() error { return acker.AckBatch(ctx, acked) }); ackErr != nil {
+				errs = append(errs, fmt.Errorf("ack: %w", ackErr))
+			}
+		} else {
+			for _, msg := range acked {
+				if msg.Ack == nil {
+					continue
+				}
+				if ackErr := recoverCall(ctx, func() error { return msg.Ack(ctx) }); ackErr != nil {
+					errs = append(errs, fmt.Errorf("ack: %w", ackErr))
+				}
+			}
+		}
+	}
+
+	failedMsgs := make([]Message, 0, len(failed))
+	for i, msg := range msgs {
+		msgErr, ok := failed[i]
+		if !ok {
+			continue
+		}
+		failedMsgs = append(failedMsgs, msg)
+		errs = append(errs, msgErr)
+		if msg.Nack != nil {
+			if nackErr := recoverCall(ctx, func() error { return msg.Nack(ctx, msgErr) }); nackErr != nil {
+				errs = append(errs, fmt.Errorf("nack: %w", nackErr))
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	err := errors.Join(errs...)
+
+	b.mu.RLock()
+	onError := b.onError
+	b.mu.RUnlock()
+	if onError != nil {
+		return onError(ctx, failedMsgs, err)
+	}
+	// Detached so the report survives Run canceling its context on return.
+	capitan.Error(context.WithoutCancel(ctx), SignalSubscriptionBatchFailed,
+		FieldName.Field(b.identity.Name()),
+		FieldIdentityID.Field(b.identity.ID().String()),
+		FieldBatchSize.Field(len(msgs)),
+		FieldErrorCount.Field(len(failedMsgs)),
+		FieldError.Field(err.Error()),
+	)
+	return nil
+}
+
+// abandon nacks a batch left unprocessed at shutdown.
+func (*BatchSubscription[T]) abandon(ctx context.Context, msgs []Message, cause error) {
+	nackCtx := context.WithoutCancel(ctx)
+	for _, msg := range msgs {
+		if msg.Nack != nil {
+			_ = recoverCall(nackCtx, func() error { return msg.Nack(nackCtx, cause) }) //nolint:errcheck // shutting down
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// batchAckSubscriber records batch acks on top of a queueSubscriber.
+type batchAckSubscriber struct {
+	queueSubscriber
+	batches [][]Message
+}
+
+func (b *batchAckSubscriber) AckBatch(_ context.Context, msgs []Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.batches = append(b.batches, msgs)
+	return nil
+}
+
+// chanSubscriber delivers messages from a channel until ctx is done.
+type chanSubscriber chan Message
+
+func (c chanSubscriber) Receive(ctx context.Context) (Message, error) {
+	select {
+	case msg := <-c:
+		return msg, nil
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	}
+}
+
+// recordBatches returns a batch processor that records each batch it sees.
+func recordBatches(mu *sync.Mutex, sizes *[]int) Chainable[[]int] {
+	return Effect(NewIdentity("record", ""), func(_ context.Context, batch []int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		*sizes = append(*sizes, len(batch))
+		return nil
+	})
+}
+
+func TestBatchSubscription(t *testing.T) {
+	t.Run("Flushes By Size And On Close", func(t *testing.T) {
+		var acked atomic.Int64
+		ack := func(context.Context) error { acked.Add(1); return nil }
+		sub := &queueSubscriber{}
+		for range 5 {
+			sub.messages = append(sub.messages, Message{Payload: []byte("1"), Ack: ack})
+		}
+		var mu sync.Mutex
+		var sizes []int
+
+		err := NewBatchSubscription[int](NewIdentity("numbers", ""), sub, nil, recordBatches(&mu, &sizes)).
+			SetBatchSize(2).
+			Run(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+			t.Errorf("expected batches [2 2 1], got %v", sizes)
+		}
+		if acked.Load() != 5 {
+			t.Errorf("expected 5 acks, got %d", acked.Load())
+		}
+	})
+
+	t.Run("Commits With BatchAcker", func(t *testing.T) {
+		individual := func(context.Context) error {
+			t.Error("individual ack used despite BatchAcker")
+			return nil
+		}
+		sub := &batchAckSubscriber{queueSubscriber: queueSubscriber{messages: []Message{
+			{Payload: []byte("1"), Ack: individual},
+			{Payload: []byte("2"), Ack: individual},
+			{Payload: []byte("3"), Ack: individual},
+		}}}
+		var mu sync.Mutex
+		var sizes []int
+
+		err := NewBatchSubscription[int](NewIdentity("numbers", ""), sub, nil, recordBatches(&mu, &sizes)).
+			SetBatchSize(3).
+			Run(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sub.batches) != 1 || len(sub.batches[0]) != 3 {
+			t.Errorf("expected one batch ack of 3 messages, got %v", sub.batches)
+		}
+	})
+
+	t.Run("Flushes When Window Closes", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sub := make(chanSubscriber, 1)
+		sub <- Message{Payload: []byte("1")}
+		flushed := make(chan int, 1)
+		processor := Effect(NewIdentity("record", ""), func(_ context.Context, batch []int) error {
+			flushed <- len(batch)
+			return nil
+		})
+
+		done := make(chan error, 1)
+		go func() {
+			done <- NewBatchSubscription[int](NewIdentity("numbers", ""), sub, nil, processor).
+				SetBatchSize(10).
+				SetBatchWindow(20 * time.Millisecond).
+				Run(ctx)
+		}()
+		select {
+		case n := <-flushed:
+			if n != 1 {
+				t.Errorf("expected a batch of 1, got %d", n)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("partial batch was not flushed after the window")
+		}
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("expected nil on cancellation, got %v", err)
+		}
+	})
+
+	t.Run("Cancellation Nacks Pending Batch", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		sub := make(chanSubscriber, 1)
+		nacked := make(chan error, 1)
+		sub <- Message{Payload: []byte("1"), Nack: func(_ context.Context, err error) error {
+			nacked <- err
+			return nil
+		}}
+		processor := Effect(NewIdentity("record", ""), func(context.Context, []int) error {
+			t.Error("pending batch processed after cancellation")
+			return nil
+		})
+
+		done := make(chan error, 1)
+		go func() {
+			done <- NewBatchSubscription[int](NewIdentity("numbers", ""), sub, nil, processor).
+				SetBatchSize(10).
+				SetBatchWindow(0).
+				Run(ctx)
+		}()
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatalf("expected nil on cancellation, got %v", err)
+		}
+		select {
+		case err := <-nacked:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("expected nack with context.Canceled, got %v", err)
+			}
+		default:
+			t.Error("pending message was not nacked")
+		}
+	})
+
+	t.Run("Partial Failure Nacks Only Failed Items", func(t *testing.T) {
+		var acked, nacked []string
+		var mu sync.Mutex
+		msg := func(topic, payload string) Message {
+			return Message{
+				Topic:   topic,
+				Payload: []byte(payload),
+				Ack:     func(context.Context) error { mu.Lock(); acked = append(acked, topic); mu.Unlock(); return nil },
+				Nack:    func(context.Context, error) error { mu.Lock(); nacked = append(nacked, topic); mu.Unlock(); return nil },
+			}
+		}
+		sub := &queueSubscriber{messages: []Message{msg("a", "1"), msg("b", "2"), msg("c", "3")}}
+		processor := Effect(NewIdentity("insert", ""), func(ctx context.Context, batch []int) error {
+			if msgs, ok := MessagesFromContext(ctx); !ok || len(msgs) != len(batch) {
+				return errors.New("missing messages in context")
+			}
+			return &BatchError{Failed: map[int]error{1: errors.New("duplicate key")}}
+		})
+
+		var failedCount int
+		listener := capitan.Hook(SignalSubscriptionBatchFailed, func(_ context.Context, e *capitan.Event) {
+			failedCount, _ = FieldErrorCount.From(e)
+		})
+		defer listener.Close()
+
+		err := NewBatchSubscription[int](NewIdentity("numbers", ""), sub, nil, processor).
+			SetBatchSize(3).
+			Run(context.Background())
+		if err != nil {
+			t.Fatalf("default policy should continue, got %v", err)
+		}
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if len(acked) != 2 || acked[0] != "a" || acked[1] != "c" {
+			t.Errorf("expected a and c acked, got %v", acked)
+		}
+		if len(nacked) != 1 || nacked[0] != "b" {
+			t.Errorf("expected b nacked, got %v", nacked)
+		}
+		if failedCount != 1 {
+			t.Errorf("expected signal with 1 failed message, got %d", failedCount)
+		}
+	})
+
+	t.Run("Failed Item Without Nack Fails Whole Batch", func(t *testing.T) {
+		var acked atomic.Int64
+		var notCommitted atomic.Int64
+		sub := &queueSubscriber{messages: []Message{
+			{Payload: []byte("1"), Ack: func(context.Context) error { acked.Add(1); return nil }, Nack: func(_ context.Context, err error) error {
+				if errors.Is(err, ErrBatchNotCommitted) {
+					notCommitted.Add(1)
+				}
+				return nil
+			}},
+			{Payload: []byte("2"), Ack: func(context.Context) error { acked.Add(1); return nil }},
+		}}
+		processor := Effect(NewIdentity("insert", ""), func(context.Context, []int) error {
+			return &BatchError{Failed: map[int]error{1: errors.New("rejected")}}
+		})
+
+		var failed []Message
+		err := NewBatchSubscription[int](NewIdentity("numbers", ""), sub, nil, processor).
+			SetBatchSize(2).
+			SetErrorHandler(func(_ context.Context, msgs []Message, _ error) error {
+				failed = msgs
+				return nil
+			}).
+			Run(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if acked.Load() != 0 {
+			t.Errorf("expected no acks, got %d", acked.Load())
+		}
+		if notCommitted.Load() != 1 {
+			t.Errorf("expected successful message nacked as not committed, got %d", notCommitted.Load())
+		}
+		if len(failed) != 2 {
+			t.Errorf("expected whole batch reported failed, got %d messages", len(failed))
+		}
+	})
+
+	t.Run("Decode Failures Are Excluded From Batch", func(t *testing.T) {
+		var nacked atomic.Int64
+		nack := func(context.Context, error) error { nacked.Add(1); return nil }
+		sub := &queueSubscriber{messages: []Message{
+			{Payload: []byte("1"), Nack: nack},
+			{Payload: []byte("not json"), Nack: nack},
+			{Payload: []byte("3"), Nack: nack},
+		}}
+		var mu sync.Mutex
+		var sizes []int
+
+		err := NewBatchSubscription[int](NewIdentity("numbers", ""), sub, nil, recordBatches(&mu, &sizes)).
+			SetBatchSize(3).
+			SetErrorHandler(func(context.Context, []Message, error) error { return nil }).
+			Run(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sizes) != 1 || sizes[0] != 2 {
+			t.Errorf("expected one batch of 2 decoded items, got %v", sizes)
+		}
+		if nacked.Load() != 1 {
+			t.Errorf("expected 1 nack, got %d", nacked.Load())
+		}
+	})
+
+	t.Run("Error Handler Stops Run", func(t *testing.T) {
+		stopErr := errors.New("stop")
+		var processed atomic.Int64
+		sub := &queueSubscriber{messages: []Message{{Payload: []byte("1")}, {Payload: []byte("2")}, {Payload: []byte("3")}}}
+		fail := Apply(NewIdentity("fail", ""), func(_ context.Context, batch []int) ([]int, error) {
+			processed.Add(1)
+			return batch, errors.New("boom")
+		})
+		err := NewBatchSubscription[int](NewIdentity("numbers", ""), sub, nil, fail).
+			SetBatchSize(1).
+			SetErrorHandler(func(context.Context, []Message, error) error { return stopErr }).
+			Run(context.Background())
+		if !errors.Is(err, stopErr) {
+			t.Fatalf("expected stop error, got %v", err)
+		}
+		if processed.Load() != 1 {
+			t.Errorf("expected processing to stop after first failed batch, got %d", processed.Load())
+		}
+	})
+
+	t.Run("Batch Controller Sizes Batches", func(t *testing.T) {
+		queue := func() *queueSubscriber {
+			sub := &queueSubscriber{}
+			for range 40 {
+				sub.messages = append(sub.messages, Message{Payload: []byte("1")})
+			}
+			return sub
+		}
+
+		var mu sync.Mutex
+		var sizes []int
+		sizer := NewBatchSizeController(time.Second, 2)
+		err := NewBatchSubscription[int](NewIdentity("numbers", ""), queue(), nil, recordBatches(&mu, &sizes)).
+			SetBatchController(sizer).
+			Run(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sizes[0] != 2 || sizer.Size() <= 2 || sizes[len(sizes)-2] <= 2 {
+			t.Errorf("expected fast batches to grow from 2, got %v", sizes)
+		}
+
+		sizes = nil
+		fail := Apply(NewIdentity("fail", ""), func(_ context.Context, batch []int) ([]int, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			sizes = append(sizes, len(batch))
+			return batch, errors.New("overloaded")
+		})
+		err = NewBatchSubscription[int](NewIdentity("numbers", ""), queue(), nil, fail).
+			SetBatchController(NewBatchSizeController(time.Second, 16)).
+			SetErrorHandler(func(context.Context, []Message, error) error { return nil }).
+			Run(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sizes) < 4 || sizes[0] != 16 || sizes[1] != 8 || sizes[2] != 4 || sizes[3] != 2 {
+			t.Errorf("expected failing batches to halve, got %v", sizes)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package pipz
+
+import (
+	"context"
+)
+
+// Bind creates a Processor from a function that takes static configuration
+// alongside the data, such as a template, a lookup table, or a settings
+// struct. config is fixed when the processor is built and passed to every
+// call, so processors receive their configuration explicitly rather than
+// through package-level variables or a closure written for each one, and
+// the same function can back several processors with different settings.
+//
+// Bind behaves like Apply: an error stops the pipeline and is wrapped with
+// the processor's identity. config is shared by every call, including
+// concurrent ones; bind values or read-only data, not state mutated while
+// processing.
+//
+// Example:
+//
+//	type Thresholds struct{ Review, Block float64 }
+//
+//	func scoreRisk(ctx context.Context, t Thresholds, o Order) (Order, error) {
+//	    switch {
+//	    case o.Risk >= t.Block:
+//	        return o, ErrBlocked
+//	    case o.Risk >= t.Review:
+//	        o.NeedsReview = true
+//	    }
+//	    return o, nil
+//	}
+//
+//	var DomesticRiskID = pipz.NewIdentity("domestic-risk", "Scores domestic orders")
+//	var ExportRiskID = pipz.NewIdentity("export-risk", "Scores export orders")
+//	domestic := pipz.Bind(DomesticRiskID, Thresholds{Review: 0.6, Block: 0.9}, scoreRisk)
+//	export := pipz.Bind(ExportRiskID, Thresholds{Review: 0.4, Block: 0.8}, scoreRisk)
+func Bind[C, T any](identity Identity, config C, fn func(context.Context, C, T) (T, error)) Processor[T] {
+	return Apply(identity, func(ctx context.Context, data T) (T, error) {
+		return fn(ctx, config, data)
+	})
+}
@@ -0,0 +1,63 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBind(t *testing.T) {
+	type limits struct {
+		max    int
+		prefix string
+	}
+	check := func(_ context.Context, l limits, s string) (string, error) {
+		if len(s) > l.max {
+			return s, errors.New("too long")
+		}
+		return l.prefix + s, nil
+	}
+
+	t.Run("Passes Config To Every Call", func(t *testing.T) {
+		short := Bind(testIdentity("short"), limits{max: 3, prefix: "s:"}, check)
+		long := Bind(testIdentity("long"), limits{max: 10, prefix: "l:"}, check)
+		if out, err := short.Process(context.Background(), "abc"); err != nil || out != "s:abc" {
+			t.Errorf("expected s:abc, got %q, %v", out, err)
+		}
+		if out, err := long.Process(context.Background(), "abcdef"); err != nil || out != "l:abcdef" {
+			t.Errorf("expected l:abcdef, got %q, %v", out, err)
+		}
+	})
+
+	t.Run("Wraps Errors", func(t *testing.T) {
+		short := Bind(testIdentity("short"), limits{max: 3}, check)
+		_, err := short.Process(context.Background(), "abcdef")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "short" || pipeErr.InputData != "abcdef" {
+			t.Errorf("expected an error from short, got %v", err)
+		}
+	})
+
+	t.Run("Recovers Panics", func(t *testing.T) {
+		boom := Bind(testIdentity("boom"), "cfg", func(context.Context, string, string) (string, error) {
+			panic("bad config")
+		})
+		if _, err := boom.Process(context.Background(), "x"); !errors.Is(err, ErrPanic) {
+			t.Errorf("expected a recovered panic, got %v", err)
+		}
+	})
+
+	t.Run("Composes", func(t *testing.T) {
+		upper := Bind(testIdentity("upper"), strings.ToUpper, func(_ context.Context, f func(string) string, s string) (string, error) {
+			return f(s), nil
+		})
+		seq := NewSequence(testIdentity("seq"), Bind(testIdentity("short"), limits{max: 5, prefix: "x-"}, check), upper)
+		if out, err := seq.Process(context.Background(), "ab"); err != nil || out != "X-AB" {
+			t.Errorf("expected X-AB, got %q, %v", out, err)
+		}
+		if seq.Schema().Flow.(SequenceFlow).Steps[1].Type != "processor" {
+			t.Error("expected bound processors to appear as processors")
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package pipz
+
+import (
+	"context"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// BranchRetry is a lightweight retry policy for the branches of a Race or
+// Contest, such as letting each provider retry once quickly, without
+// wrapping every branch in its own Retry connector.
+//
+// Branch retries are coordinated with the competition: a branch stops
+// retrying as soon as another branch wins, and does not start an attempt
+// that cannot finish before the context deadline, judged by how long its
+// previous attempt took.
+type BranchRetry struct {
+	// Attempts is the total number of attempts per branch, including the
+	// first. Values below two disable retrying.
+	Attempts int
+	// Delay is the wait between attempts.
+	Delay time.Duration
+}
+
+// branchRetries holds the branch retry policies of a Race or Contest. The
+// owner's mutex guards it.
+type branchRetries struct {
+	byBranch map[Identity]BranchRetry
+	all      BranchRetry
+}
+
+// set applies policy to branches, or to every branch without a policy of
+// its own when none are given.
+func (b *branchRetries) set(policy BranchRetry, branches []Identity) {
+	if len(branches) == 0 {
+		b.all = policy
+		return
+	}
+	if b.byBranch == nil {
+		b.byBranch = make(map[Identity]BranchRetry, len(branches))
+	}
+	for _, branch := range branches {
+		b.byBranch[branch] = policy
+	}
+}
+
+// policy returns the policy of branch.
+func (b *branchRetries) policy(branch Identity) BranchRetry {
+	if policy, ok := b.byBranch[branch]; ok {
+		return policy
+	}
+	return b.all
+}
+
+// branchAttempts returns the attempts of each processor that retries, keyed by
+// name, for the owner's schema, or nil if none do.
+func branchAttempts[T any](b *branchRetries, processors []Chainable[T]) map[string]int {
+	var attempts map[string]int
+	for _, p := range processors {
+		if policy := b.policy(p.Identity()); policy.Attempts > 1 {
+			if attempts == nil {
+				attempts = make(map[string]int)
+			}
+			attempts[p.Identity().Name()] = policy.Attempts
+		}
+	}
+	return attempts
+}
+
+// runBranch runs one branch of a Race or Contest on its own copy of input,
+// retrying failures under policy until an attempt succeeds, the attempts
+// are spent, ctx ends, or another attempt could not finish before ctx's
+// deadline. It returns the last attempt's result.
+func runBranch[T Cloner[T]](ctx context.Context, clock clockz.Clock, owner Identity, p Chainable[T], input T, policy BranchRetry) (T, error) {
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		data, err := p.Process(ctx, branchInput(p, input))
+		if err == nil || isControl(err) || attempt >= policy.Attempts || ctx.Err() != nil {
+			return data, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < policy.Delay+time.Since(start) {
+			return data, err
+		}
+
+		capitan.Debug(ctx, SignalBranchRetrying,
+			FieldName.Field(owner.Name()),
+			FieldIdentityID.Field(owner.ID().String()),
+			FieldProcessorName.Field(p.Identity().Name()),
+			FieldAttempt.Field(attempt),
+			FieldError.Field(err.Error()),
+		)
+		if policy.Delay > 0 {
+			select {
+			case <-clock.After(policy.Delay):
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				return data, err
+			}
+		}
+	}
+}
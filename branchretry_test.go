@@ -0,0 +1,129 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBranchRetry(t *testing.T) {
+	ctx := context.Background()
+
+	// flaky returns a branch failing its first failures attempts, then
+	// returning value, and its attempt counter.
+	flaky := func(name string, failures int32, value int) (Chainable[TestData], *atomic.Int32) {
+		var attempts atomic.Int32
+		return Apply(testIdentity(name), func(_ context.Context, d TestData) (TestData, error) {
+			if attempts.Add(1) <= failures {
+				return d, errors.New(name + " unavailable")
+			}
+			d.Value = value
+			return d, nil
+		}), &attempts
+	}
+	failing := func(name string) Chainable[TestData] {
+		return Apply(testIdentity(name), func(_ context.Context, d TestData) (TestData, error) {
+			return d, errors.New(name + " down")
+		})
+	}
+
+	t.Run("Race Branch Retries", func(t *testing.T) {
+		primary, attempts := flaky("primary", 1, 7)
+		race := NewRace(testIdentity("race"), primary, failing("backup")).
+			SetBranchRetry(BranchRetry{Attempts: 2})
+
+		result, err := race.Process(ctx, TestData{})
+		if err != nil || result.Value != 7 {
+			t.Fatalf("expected retried branch to win, got %v, %v", result.Value, err)
+		}
+		if attempts.Load() != 2 {
+			t.Errorf("expected 2 attempts, got %d", attempts.Load())
+		}
+	})
+
+	t.Run("Per Branch Policy", func(t *testing.T) {
+		a, aAttempts := flaky("a", 10, 1)
+		b, bAttempts := flaky("b", 10, 2)
+		race := NewRace(testIdentity("race"), a, b).
+			SetBranchRetry(BranchRetry{Attempts: 3}, a.Identity())
+
+		if _, err := race.Process(ctx, TestData{}); err == nil {
+			t.Fatal("expected every branch to fail")
+		}
+		if aAttempts.Load() != 3 || bAttempts.Load() != 1 {
+			t.Errorf("expected 3 and 1 attempts, got %d and %d", aAttempts.Load(), bAttempts.Load())
+		}
+		meta := race.Schema().Metadata["branch_attempts"].(map[string]int)
+		if len(meta) != 1 || meta["a"] != 3 {
+			t.Errorf("unexpected schema metadata %v", meta)
+		}
+	})
+
+	t.Run("Stops When Another Branch Wins", func(t *testing.T) {
+		retrying, attempts := flaky("retrying", 100, 0)
+		winner := Transform(testIdentity("winner"), func(_ context.Context, d TestData) TestData {
+			time.Sleep(20 * time.Millisecond)
+			d.Value = 9
+			return d
+		})
+		race := NewRace(testIdentity("race"), retrying, winner).
+			SetBranchRetry(BranchRetry{Attempts: 100, Delay: 5 * time.Millisecond})
+
+		result, err := race.Process(ctx, TestData{})
+		if err != nil || result.Value != 9 {
+			t.Fatalf("expected winner, got %v, %v", result.Value, err)
+		}
+		// Let an attempt already under way when the race ended finish
+		time.Sleep(10 * time.Millisecond)
+		settled := attempts.Load()
+		time.Sleep(30 * time.Millisecond)
+		if attempts.Load() != settled || settled >= 100 {
+			t.Errorf("expected retries to stop after the race was won, got %d then %d", settled, attempts.Load())
+		}
+	})
+
+	t.Run("Respects Deadline", func(t *testing.T) {
+		var attempts atomic.Int32
+		slow := Apply(testIdentity("slow"), func(_ context.Context, d TestData) (TestData, error) {
+			attempts.Add(1)
+			time.Sleep(30 * time.Millisecond)
+			return d, errors.New("slow failure")
+		})
+		race := NewRace(testIdentity("race"), slow).
+			SetBranchRetry(BranchRetry{Attempts: 5})
+
+		deadlineCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if _, err := race.Process(deadlineCtx, TestData{}); err == nil {
+			t.Fatal("expected failure")
+		}
+		if attempts.Load() != 1 {
+			t.Errorf("expected no retry that could not finish before the deadline, got %d attempts", attempts.Load())
+		}
+		if elapsed := time.Since(start); elapsed > 45*time.Millisecond {
+			t.Errorf("expected race to fail before its deadline, took %v", elapsed)
+		}
+	})
+
+	t.Run("Contest Branch Retries", func(t *testing.T) {
+		quote, attempts := flaky("quote", 2, 40)
+		contest := NewContest(testIdentity("contest"),
+			func(_ context.Context, d TestData) bool { return d.Value < 50 },
+			quote, failing("other"),
+		).SetBranchRetry(BranchRetry{Attempts: 3, Delay: time.Millisecond})
+
+		result, err := contest.Process(ctx, TestData{})
+		if err != nil || result.Value != 40 {
+			t.Fatalf("expected retried quote to win, got %v, %v", result.Value, err)
+		}
+		if attempts.Load() != 3 {
+			t.Errorf("expected 3 attempts, got %d", attempts.Load())
+		}
+		if _, ok := contest.Schema().Metadata["branch_attempts"]; !ok {
+			t.Error("expected branch attempts in schema metadata")
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package pipz
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/capitan"
+)
+
+// BreakerTransition announces that a CircuitBreaker changed state. Breaker
+// is the breaker's name, which replicas share; Origin identifies the
+// publishing instance so it can ignore its own announcements.
+type BreakerTransition struct {
+	Breaker string              `json:"breaker"`
+	Origin  string              `json:"origin"`
+	State   CircuitBreakerState `json:"state"`
+}
+
+// BreakerSync shares CircuitBreaker transitions between replicas, typically
+// over a message bus or key-value store with change notifications.
+// Publish is called after each open or close transition; Subscribe
+// registers a callback for transitions of the named breaker and returns a
+// function that removes it.
+type BreakerSync interface {
+	Publish(ctx context.Context, transition BreakerTransition) error
+	Subscribe(breaker string, fn func(BreakerTransition)) (unsubscribe func())
+}
+
+// MemoryBreakerSync is an in-process BreakerSync that delivers transitions
+// synchronously to every subscriber. It links breakers within one process
+// and serves as a reference for networked implementations.
+type MemoryBreakerSync struct {
+	subscribers map[string]map[int]func(BreakerTransition)
+	next        int
+	mu          sync.RWMutex
+}
+
+// NewMemoryBreakerSync creates an empty MemoryBreakerSync.
+func NewMemoryBreakerSync() *MemoryBreakerSync {
+	return &MemoryBreakerSync{
+		subscribers: make(map[string]map[int]func(BreakerTransition)),
+	}
+}
+
+// Publish delivers transition to the breaker's subscribers.
+func (m *MemoryBreakerSync) Publish(_ context.Context, transition BreakerTransition) error {
+	m.mu.RLock()
+	fns := make([]func(BreakerTransition), 0, len(m.subscribers[transition.Breaker]))
+	for _, fn := range m.subscribers[transition.Breaker] {
+		fns = append(fns, fn)
+	}
+	m.mu.RUnlock()
+
+	for _, fn := range fns {
+		fn(transition)
+	}
+	return nil
+}
+
+// Subscribe registers fn for transitions of the named breaker.
+func (m *MemoryBreakerSync) Subscribe(breaker string, fn func(BreakerTransition)) func() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.subscribers[breaker] == nil {
+		m.subscribers[breaker] = make(map[int]func(BreakerTransition))
+	}
+	id := m.next
+	m.next++
+	m.subscribers[breaker][id] = fn
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		delete(m.subscribers[breaker], id)
+	}
+}
+
+// SetSync shares this breaker's state with replicas through sync. The
+// breaker publishes its open and close transitions, and when a replica
+// reports that it opened, this breaker opens pre-emptively instead of
+// discovering the outage through its own failures. Remote transitions to
+// closed are not adopted: each replica probes recovery itself through
+// half-open. Replicas are matched by breaker name. Passing nil detaches the
+// breaker.
+//
+// Example:
+//
+//	shared := newRedisBreakerSync(client) // implements pipz.BreakerSync
+//	breaker := pipz.NewCircuitBreaker(PaymentsBreakerID, charge, 5, 30*time.Second).
+//	    SetSync(shared)
+func (cb *CircuitBreaker[T]) SetSync(breakerSync BreakerSync) *CircuitBreaker[T] {
+	cb.mu.Lock()
+	unsubscribe := cb.unsubscribe
+	cb.sync = breakerSync
+	cb.unsubscribe = nil
+	if cb.origin == "" {
+		cb.origin = uuid.NewString()
+	}
+	name := cb.identity.Name()
+	cb.mu.Unlock()
+
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+	if breakerSync != nil {
+		unsub := breakerSync.Subscribe(name, cb.applyRemote)
+		cb.mu.Lock()
+		cb.unsubscribe = unsub
+		cb.mu.Unlock()
+	}
+	return cb
+}
+
+// applyRemote adopts an open transition published by a replica.
+func (cb *CircuitBreaker[T]) applyRemote(transition BreakerTransition) {
+	cb.mu.Lock()
+	if transition.Origin == cb.origin || transition.State.State != stateOpen || cb.state == stateOpen {
+		cb.mu.Unlock()
+		return
+	}
+
+	now := cb.getClock().Now()
+	cb.state = stateOpen
+	cb.lastFailTime = transition.State.LastFailTime
+	if cb.lastFailTime.IsZero() || cb.lastFailTime.After(now) {
+		cb.lastFailTime = now
+	}
+	cb.failures = 0
+	cb.successes = 0
+	cb.generation++ // in-flight results no longer apply
+	generation := cb.generation
+	cb.mu.Unlock()
+
+	capitan.Warn(context.Background(), SignalCircuitBreakerRemoteOpened,
+		FieldName.Field(cb.identity.Name()),
+		FieldIdentityID.Field(cb.identity.ID().String()),
+		FieldState.Field(stateOpen),
+		FieldGeneration.Field(generation),
+		FieldTimestamp.Field(float64(now.Unix())),
+	)
+}
+
+// publishTransition announces state to replicas when a sync is attached.
+// It must be called without the breaker's mutex held.
+func (cb *CircuitBreaker[T]) publishTransition(ctx context.Context, state CircuitBreakerState) {
+	cb.mu.Lock()
+	breakerSync := cb.sync
+	origin := cb.origin
+	cb.mu.Unlock()
+	if breakerSync == nil {
+		return
+	}
+
+	// Share the transition even if the triggering request was canceled.
+	ctx = context.WithoutCancel(ctx)
+	err := breakerSync.Publish(ctx, BreakerTransition{
+		Breaker: cb.identity.Name(),
+		Origin:  origin,
+		State:   state,
+	})
+	if err != nil {
+		capitan.Error(ctx, SignalCircuitBreakerSyncFailed,
+			FieldName.Field(cb.identity.Name()),
+			FieldIdentityID.Field(cb.identity.ID().String()),
+			FieldState.Field(state.State),
+			FieldError.Field(err.Error()),
+		)
+	}
+}
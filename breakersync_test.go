@@ -0,0 +1,140 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+func TestBreakerSync(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	dependency := func() Chainable[int] {
+		return Apply(NewIdentity("dependency", ""), func(_ context.Context, v int) (int, error) {
+			if down.Load() {
+				return v, errors.New("unavailable")
+			}
+			return v, nil
+		})
+	}
+	replica := func(shared BreakerSync, clock clockz.Clock) *CircuitBreaker[int] {
+		return NewCircuitBreaker(NewIdentity("payments", ""), dependency(), 2, time.Minute).
+			WithClock(clock).
+			SetSync(shared)
+	}
+
+	t.Run("Replica Opens Pre-emptively", func(t *testing.T) {
+		down.Store(true)
+		shared := NewMemoryBreakerSync()
+		clock := clockz.NewFakeClock()
+		a := replica(shared, clock)
+		b := replica(shared, clock)
+
+		for i := 0; i < 2; i++ {
+			_, _ = a.Process(context.Background(), i) //nolint:errcheck // tripping replica a
+		}
+		if a.GetState() != stateOpen {
+			t.Fatalf("expected replica a open, got %s", a.GetState())
+		}
+		if b.GetState() != stateOpen {
+			t.Errorf("expected replica b to open from shared state, got %s", b.GetState())
+		}
+		if b.Snapshot().LastFailTime != a.Snapshot().LastFailTime {
+			t.Error("expected replica b to adopt the remote failure time")
+		}
+	})
+
+	t.Run("Recovery Is Local", func(t *testing.T) {
+		down.Store(true)
+		shared := NewMemoryBreakerSync()
+		clock := clockz.NewFakeClock()
+		a := replica(shared, clock)
+		b := replica(shared, clock)
+		for i := 0; i < 2; i++ {
+			_, _ = a.Process(context.Background(), i) //nolint:errcheck // tripping replica a
+		}
+
+		down.Store(false)
+		clock.Advance(2 * time.Minute)
+		if _, err := a.Process(context.Background(), 1); err != nil {
+			t.Fatalf("expected half-open probe to succeed, got %v", err)
+		}
+		if a.GetState() != stateClosed {
+			t.Fatalf("expected replica a closed, got %s", a.GetState())
+		}
+		if b.GetState() == stateClosed {
+			t.Error("expected replica b to probe recovery itself rather than adopt the close")
+		}
+	})
+
+	t.Run("Different Names Are Independent", func(t *testing.T) {
+		down.Store(true)
+		shared := NewMemoryBreakerSync()
+		a := replica(shared, clockz.NewFakeClock())
+		other := NewCircuitBreaker(NewIdentity("inventory", ""), dependency(), 2, time.Minute).SetSync(shared)
+		for i := 0; i < 2; i++ {
+			_, _ = a.Process(context.Background(), i) //nolint:errcheck // tripping replica a
+		}
+		if other.GetState() != stateClosed {
+			t.Errorf("expected unrelated breaker closed, got %s", other.GetState())
+		}
+	})
+
+	t.Run("Detach And Close Unsubscribe", func(t *testing.T) {
+		down.Store(true)
+		shared := NewMemoryBreakerSync()
+		clock := clockz.NewFakeClock()
+		a := replica(shared, clock)
+		detached := replica(shared, clock).SetSync(nil)
+		closed := replica(shared, clock)
+		if err := closed.Close(); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+
+		for i := 0; i < 2; i++ {
+			_, _ = a.Process(context.Background(), i) //nolint:errcheck // tripping replica a
+		}
+		if detached.GetState() != stateClosed || closed.GetState() != stateClosed {
+			t.Errorf("expected detached breakers unaffected, got %s and %s", detached.GetState(), closed.GetState())
+		}
+	})
+
+	t.Run("Publish Failure Emits Signal", func(t *testing.T) {
+		down.Store(true)
+		var failed atomic.Int32
+		listener := capitan.Hook(SignalCircuitBreakerSyncFailed, func(_ context.Context, _ *capitan.Event) {
+			failed.Add(1)
+		})
+		defer listener.Close()
+
+		cb := replica(failingBreakerSync{}, clockz.NewFakeClock())
+		for i := 0; i < 2; i++ {
+			_, _ = cb.Process(context.Background(), i) //nolint:errcheck // tripping the breaker
+		}
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if failed.Load() != 1 {
+			t.Errorf("expected 1 sync failure signal, got %d", failed.Load())
+		}
+		if cb.GetState() != stateOpen {
+			t.Errorf("expected breaker open despite publish failure, got %s", cb.GetState())
+		}
+	})
+}
+
+type failingBreakerSync struct{}
+
+func (failingBreakerSync) Publish(context.Context, BreakerTransition) error {
+	return errors.New("bus unavailable")
+}
+
+func (failingBreakerSync) Subscribe(string, func(BreakerTransition)) func() {
+	return func() {}
+}
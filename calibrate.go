@@ -0,0 +1,245 @@
+package pipz
+
+import (
+	"context"
+	"iter"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NodeCost is the measured cost of one processor in a calibration run.
+// Latency and allocations are per call; Max is the slowest call.
+type NodeCost struct {
+	Identity   Identity
+	Path       string
+	Type       string
+	Calls      int64
+	Errors     int64
+	Mean       time.Duration
+	Max        time.Duration
+	AllocBytes uint64
+	Allocs     uint64
+}
+
+// CalibrationReport holds the costs measured by Calibrate, attached to the
+// schema of the calibrated pipeline by path.
+type CalibrationReport struct {
+	// Schema is the calibrated pipeline's schema.
+	Schema Schema
+	// Nodes holds the cost of every processor that ran, in schema order.
+	// A processor reached through several paths appears at each of them
+	// with the costs of all its calls.
+	Nodes []NodeCost
+	// Items and Failed count the corpus items processed and failed.
+	Items  int
+	Failed int
+	// Mean and Max are end-to-end latencies per item.
+	Mean time.Duration
+	Max  time.Duration
+	// AllocBytes and Allocs are mean allocations per item.
+	AllocBytes uint64
+	Allocs     uint64
+}
+
+// Cost returns the measured cost of the processor at path, as produced by
+// Schema.Nodes.
+func (r *CalibrationReport) Cost(path string) (NodeCost, bool) {
+	for _, cost := range r.Nodes {
+		if cost.Path == path {
+			return cost, true
+		}
+	}
+	return NodeCost{}, false
+}
+
+// EstimateLatency estimates the calibrated pipeline's latency as
+// EstimateLatency does, with Expected computed from the measured mean of
+// each processor rather than left at zero.
+func (r *CalibrationReport) EstimateLatency() LatencyEstimate {
+	measured := make(map[string]time.Duration, len(r.Nodes))
+	for _, cost := range r.Nodes {
+		measured[cost.Path] = cost.Mean
+	}
+	return estimateLatency(r.Schema.Root, r.Schema.Root.Identity.Name(), measured)
+}
+
+// calibration is the state of a calibration run, carried on its context.
+type calibration struct {
+	samples map[uuid.UUID]*costSamples
+	mu      sync.Mutex
+}
+
+// costSamples accumulates the measured calls of one processor.
+type costSamples struct {
+	calls      int64
+	errors     int64
+	total      time.Duration
+	max        time.Duration
+	allocBytes uint64
+	allocs     uint64
+}
+
+// calibrationKey is the context key for the active calibration.
+type calibrationKey struct{}
+
+// Calibrate processes every item of corpus with processor, one at a time,
+// measuring the latency and heap allocations of each processor it runs,
+// and returns the costs attached to the pipeline's schema. Where
+// EstimateLatency reads the configured bounds, calibration measures what
+// processors actually cost on representative data, for visualizations and
+// for the latency estimate's Expected figure.
+//
+// Items are processed for real. Run calibration against a sandbox, or pass
+// sideEffects to stub side-effecting stages as Simulate does; stubbed
+// stages are not measured. A nil sideEffects stubs nothing.
+//
+// Measurement is intrusive: allocations are read with runtime.ReadMemStats
+// around every processor call, which briefly stops the world, so keep
+// calibration out of production traffic. Allocations made concurrently by
+// other goroutines, including parallel branches of the pipeline itself,
+// are attributed to whichever calls overlap them. Custom Chainables are
+// measured only through the processors they contain.
+//
+// Calibrate stops early, returning the report so far with the context's
+// error, when ctx ends.
+//
+// Example:
+//
+//	report, err := pipz.Calibrate(ctx, checkout, slices.Values(sampleOrders), pipz.SelectTags("side-effect"))
+//	if err != nil {
+//	    return err
+//	}
+//	for _, cost := range report.Nodes {
+//	    fmt.Printf("%-40s %8v %6d B/op\n", cost.Path, cost.Mean, cost.AllocBytes)
+//	}
+//	fmt.Println(report.EstimateLatency()) // expected 42ms, best 1.5s, worst 9s
+func Calibrate[T any](ctx context.Context, processor Chainable[T], corpus iter.Seq[T], sideEffects Selector) (*CalibrationReport, error) {
+	cal := &calibration{samples: make(map[uuid.UUID]*costSamples)}
+	updateHooks(func(h *processorHooks) { h.calibrations++ })
+	defer updateHooks(func(h *processorHooks) { h.calibrations-- })
+	runCtx := context.WithValue(ctx, calibrationKey{}, cal)
+	if sideEffects != nil {
+		updateHooks(func(h *processorHooks) { h.simulations++ })
+		defer updateHooks(func(h *processorHooks) { h.simulations-- })
+		runCtx = context.WithValue(runCtx, simulationKey{}, &simulation{sideEffects: sideEffects})
+	}
+
+	report := &CalibrationReport{Schema: NewSchema(processor.Schema())}
+	var items costSamples
+	var err error
+	for item := range corpus {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		_, call, processErr := measureCall(runCtx, processor.Process, item)
+		items.add(call)
+		report.Items++
+		if processErr != nil {
+			report.Failed++
+		}
+	}
+	if items.calls > 0 {
+		report.Mean = items.total / time.Duration(items.calls)
+		report.Max = items.max
+		report.AllocBytes = items.allocBytes / uint64(items.calls)
+		report.Allocs = items.allocs / uint64(items.calls)
+	}
+	report.Nodes = cal.costs(report.Schema)
+	return report, err
+}
+
+// calibrationFrom returns the calibration ctx belongs to, or nil outside a
+// calibration run.
+func calibrationFrom(ctx context.Context) *calibration {
+	if hooks := activeHooks.Load(); hooks == nil || hooks.calibrations == 0 || ctx == nil {
+		return nil
+	}
+	cal, _ := ctx.Value(calibrationKey{}).(*calibration)
+	return cal
+}
+
+// record adds a measured call of the processor identified by identity.
+func (c *calibration) record(identity Identity, call costSamples) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	samples, ok := c.samples[identity.ID()]
+	if !ok {
+		samples = &costSamples{}
+		c.samples[identity.ID()] = samples
+	}
+	samples.add(call)
+}
+
+// costs returns the measured cost of each processor in schema, in order.
+func (c *calibration) costs(schema Schema) []NodeCost {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var costs []NodeCost
+	schema.WalkPaths(func(n SchemaNode) {
+		samples, ok := c.samples[n.Node.Identity.ID()]
+		if !ok || n.Node.Flow != nil || samples.calls == 0 {
+			return
+		}
+		calls := samples.calls
+		costs = append(costs, NodeCost{
+			Identity:   n.Node.Identity,
+			Path:       n.Path,
+			Type:       n.Node.Type,
+			Calls:      calls,
+			Errors:     samples.errors,
+			Mean:       samples.total / time.Duration(calls),
+			Max:        samples.max,
+			AllocBytes: samples.allocBytes / uint64(calls),
+			Allocs:     samples.allocs / uint64(calls),
+		})
+	})
+	return costs
+}
+
+// add accumulates call into s.
+func (s *costSamples) add(call costSamples) {
+	s.calls += call.calls
+	s.errors += call.errors
+	s.total += call.total
+	s.max = max(s.max, call.max)
+	s.allocBytes += call.allocBytes
+	s.allocs += call.allocs
+}
+
+// measureCall runs fn on data, returning its latency and allocations as a
+// single call.
+func measureCall[In, Out any](ctx context.Context, fn func(context.Context, In) (Out, error), data In) (Out, costSamples, error) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	result, err := fn(ctx, data)
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	call := costSamples{
+		calls:      1,
+		total:      elapsed,
+		max:        elapsed,
+		allocBytes: after.TotalAlloc - before.TotalAlloc,
+		allocs:     after.Mallocs - before.Mallocs,
+	}
+	if err != nil {
+		call.errors = 1
+	}
+	return result, call, err
+}
+
+// calibrated runs fn as the processor identified by identity, measuring
+// its cost if ctx belongs to a calibration run.
+func calibrated[In, Out any](ctx context.Context, identity Identity, fn func(context.Context, In) (Out, error), data In) (Out, error) {
+	cal := calibrationFrom(ctx)
+	if cal == nil {
+		return fn(ctx, data)
+	}
+	result, call, err := measureCall(ctx, fn, data)
+	cal.record(identity, call)
+	return result, err
+}
@@ -0,0 +1,110 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalibrate(t *testing.T) {
+	var (
+		pipelineID = testIdentity("checkout")
+		slowID     = testIdentity("slow")
+		allocID    = testIdentity("alloc")
+		sendID     = testIdentity("send")
+	)
+	slow := Transform(slowID, func(_ context.Context, n int) int {
+		time.Sleep(2 * time.Millisecond)
+		return n
+	})
+	var sink []byte
+	alloc := Transform(allocID, func(_ context.Context, n int) int {
+		sink = make([]byte, 64<<10)
+		return n + len(sink) - len(sink)
+	})
+	sent := 0
+	send := Apply(sendID, func(_ context.Context, n int) (int, error) {
+		sent++
+		if n < 0 {
+			return n, errors.New("negative")
+		}
+		return n, nil
+	})
+	pipeline := NewSequence(pipelineID, slow, alloc, send)
+
+	t.Run("Measures Processors", func(t *testing.T) {
+		report, err := Calibrate(context.Background(), pipeline, slices.Values([]int{1, 2, -3}), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Items != 3 || report.Failed != 1 {
+			t.Errorf("expected 3 items with 1 failed, got %d, %d", report.Items, report.Failed)
+		}
+		if len(report.Nodes) != 3 {
+			t.Fatalf("expected three measured processors, got %+v", report.Nodes)
+		}
+		slowCost, ok := report.Cost("checkout/slow")
+		if !ok || slowCost.Calls != 3 || slowCost.Mean < 2*time.Millisecond || slowCost.Max < slowCost.Mean {
+			t.Errorf("unexpected slow cost %+v", slowCost)
+		}
+		if allocCost, _ := report.Cost("checkout/alloc"); allocCost.AllocBytes < 64<<10 || allocCost.Allocs < 1 {
+			t.Errorf("expected at least 64KiB allocated per call, got %+v", allocCost)
+		}
+		if sendCost, _ := report.Cost("checkout/send"); sendCost.Errors != 1 {
+			t.Errorf("expected one failed send, got %+v", sendCost)
+		}
+		if report.Mean < slowCost.Mean || report.AllocBytes < 64<<10 {
+			t.Errorf("expected item costs to cover their processors, got %v, %d B", report.Mean, report.AllocBytes)
+		}
+	})
+
+	t.Run("Feeds Latency Estimate", func(t *testing.T) {
+		bounded := NewTimeout(testIdentity("bounded"), pipeline, time.Second)
+		report, err := Calibrate(context.Background(), bounded, slices.Values([]int{1, 2}), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		estimate := report.EstimateLatency()
+		if estimate.Expected < 2*time.Millisecond || estimate.Expected > time.Second || estimate.Worst != time.Second {
+			t.Errorf("expected a measured expected latency under the 1s bound, got %v", estimate)
+		}
+		if !strings.HasPrefix(estimate.String(), "expected ") {
+			t.Errorf("expected the summary to lead with the measured latency, got %q", estimate)
+		}
+		if EstimateLatency(bounded).Expected != 0 {
+			t.Error("expected no expected latency without calibration")
+		}
+	})
+
+	t.Run("Stubs Side Effects", func(t *testing.T) {
+		sent = 0
+		report, err := Calibrate(context.Background(), pipeline, slices.Values([]int{1, 2}), func(id Identity) bool { return id.Name() == "send" })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sent != 0 {
+			t.Errorf("expected send stubbed, ran %d times", sent)
+		}
+		if _, ok := report.Cost("checkout/send"); ok {
+			t.Error("expected stubbed stages to be unmeasured")
+		}
+	})
+
+	t.Run("Stops When Context Ends", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		report, err := Calibrate(ctx, pipeline, slices.Values([]int{1, 2}), nil)
+		if !errors.Is(err, context.Canceled) || report.Items != 0 {
+			t.Errorf("expected no items and a canceled error, got %d, %v", report.Items, err)
+		}
+	})
+
+	t.Run("No Cost Outside Calibration", func(t *testing.T) {
+		if calibrationFrom(context.Background()) != nil {
+			t.Error("expected no calibration on a plain context")
+		}
+	})
+}
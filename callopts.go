@@ -0,0 +1,103 @@
+package pipz
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+
+	"github.com/google/uuid"
+)
+
+// CallOption overrides connector behavior for a single Process call, such
+// as when support tooling replays a problematic request.
+type CallOption func(*callOverrides)
+
+// callOverrides holds the overrides attached to one call's context.
+type callOverrides struct {
+	routes      map[uuid.UUID]string
+	logLevel    *slog.Level
+	noRetries   bool
+	bypassCache bool
+}
+
+// callOverridesKey is the context key for call overrides.
+type callOverridesKey struct{}
+
+// NoRetries makes every Retry and Backoff in the call make a single
+// attempt, so a replayed failure surfaces immediately.
+func NoRetries() CallOption {
+	return func(o *callOverrides) {
+		o.noRetries = true
+	}
+}
+
+// ForceRoute makes the Switch with the given identity take route,
+// ignoring its condition.
+func ForceRoute(switchID Identity, route string) CallOption {
+	return func(o *callOverrides) {
+		if o.routes == nil {
+			o.routes = make(map[uuid.UUID]string)
+		}
+		o.routes[switchID.ID()] = route
+	}
+}
+
+// BypassCache makes every Memo recompute instead of reusing memoized
+// results, and every Switch with a route cache run its condition instead of
+// reusing cached routes; the fresh results replace the cached ones. Caching
+// processors of your own can honor it via CacheBypassed.
+func BypassCache() CallOption {
+	return func(o *callOverrides) {
+		o.bypassCache = true
+	}
+}
+
+// LogVerbosity makes LogBridge log the call's signals from level up,
+// overriding a higher WithMinLogLevel. The logger's handler must still be
+// enabled for level.
+func LogVerbosity(level slog.Level) CallOption {
+	return func(o *callOverrides) {
+		o.logLevel = &level
+	}
+}
+
+// WithCallOptions returns a context applying opts to everything processed
+// with it, on top of any overrides already attached. Pipeline.ProcessWith
+// is the usual entry point.
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	overrides := &callOverrides{}
+	if parent := callOverridesFrom(ctx); parent != nil {
+		*overrides = *parent
+		overrides.routes = maps.Clone(parent.routes)
+	}
+	for _, opt := range opts {
+		opt(overrides)
+	}
+	return context.WithValue(ctx, callOverridesKey{}, overrides)
+}
+
+// CacheBypassed reports whether the call carrying ctx asked caches to be
+// bypassed with BypassCache.
+func CacheBypassed(ctx context.Context) bool {
+	o := callOverridesFrom(ctx)
+	return o != nil && o.bypassCache
+}
+
+// callOverridesFrom returns the overrides attached to ctx, or nil.
+func callOverridesFrom(ctx context.Context) *callOverrides {
+	if ctx == nil {
+		return nil
+	}
+	o, _ := ctx.Value(callOverridesKey{}).(*callOverrides)
+	return o
+}
+
+// forcedRoute returns the route forced for the Switch with identity.
+func forcedRoute(ctx context.Context, identity Identity) (string, bool) {
+	o := callOverridesFrom(ctx)
+	if o == nil {
+		return "", false
+	}
+	route, ok := o.routes[identity.ID()]
+	return route, ok
+}
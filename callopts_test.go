@@ -0,0 +1,96 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestCallOptions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("No Retries", func(t *testing.T) {
+		calls := 0
+		failing := Apply(NewIdentity("flaky", ""), func(_ context.Context, n int) (int, error) {
+			calls++
+			return n, errors.New("unavailable")
+		})
+		pipeline := NewPipeline(NewIdentity("replay", ""), NewRetry(NewIdentity("retry", ""), failing, 3))
+
+		_, _ = pipeline.ProcessWith(ctx, 1, NoRetries()) //nolint:errcheck // counting attempts
+		if calls != 1 {
+			t.Errorf("expected a single attempt, got %d", calls)
+		}
+		calls = 0
+		_, _ = pipeline.Process(ctx, 1) //nolint:errcheck // counting attempts
+		if calls != 3 {
+			t.Errorf("override leaked into a later call: %d attempts", calls)
+		}
+	})
+
+	t.Run("Force Route", func(t *testing.T) {
+		routerID := NewIdentity("router", "")
+		router := NewSwitch(routerID, func(_ context.Context, _ int) string { return "auto" }).
+			AddRoute("auto", Transform(NewIdentity("auto", ""), func(_ context.Context, n int) int { return n + 1 })).
+			AddRoute("manual", Transform(NewIdentity("manual", ""), func(_ context.Context, n int) int { return n * 100 }))
+		pipeline := NewPipeline(NewIdentity("replay", ""), router)
+
+		if got, _ := pipeline.ProcessWith(ctx, 2, ForceRoute(routerID, "manual")); got != 200 {
+			t.Errorf("forced route result = %d", got)
+		}
+		if got, _ := pipeline.ProcessWith(ctx, 2, ForceRoute(NewIdentity("other", ""), "manual")); got != 3 {
+			t.Errorf("route forced on the wrong switch: %d", got)
+		}
+	})
+
+	t.Run("Bypass Cache", func(t *testing.T) {
+		calls := 0
+		memo := NewMemo(func(n int) string { return "k" }, 0, func(_ context.Context, n int) int {
+			calls++
+			return n
+		})
+		memo.Get(ctx, 1)
+		if got := memo.Get(ctx, 2); got != 1 || calls != 1 {
+			t.Fatalf("expected memoized result, got %d after %d calls", got, calls)
+		}
+		bypass := WithCallOptions(ctx, BypassCache())
+		if !CacheBypassed(bypass) || CacheBypassed(ctx) {
+			t.Error("CacheBypassed reported incorrectly")
+		}
+		if got := memo.Get(bypass, 2); got != 2 || calls != 2 {
+			t.Errorf("expected recomputed result, got %d after %d calls", got, calls)
+		}
+		if got := memo.Get(ctx, 3); got != 2 {
+			t.Errorf("expected fresh result memoized, got %d", got)
+		}
+	})
+
+	t.Run("Options Accumulate", func(t *testing.T) {
+		routerID := NewIdentity("router", "")
+		outer := WithCallOptions(ctx, ForceRoute(routerID, "a"))
+		inner := WithCallOptions(outer, NoRetries())
+		if route, ok := forcedRoute(inner, routerID); !ok || route != "a" {
+			t.Errorf("inherited route = %q, %v", route, ok)
+		}
+		if o := callOverridesFrom(outer); o.noRetries {
+			t.Error("inner options leaked into the outer context")
+		}
+	})
+
+	t.Run("Log Verbosity", func(t *testing.T) {
+		var buf syncBuffer
+		bridge := BridgeLogs(newTestLogger(&buf), WithLogSignals(SignalSwitchRouted), WithMinLogLevel(slog.LevelWarn))
+		defer bridge.Close()
+		pipeline := NewPipeline(NewIdentity("replay", ""), NewSwitch(NewIdentity("router", ""), func(_ context.Context, _ int) string { return "none" }))
+
+		_, _ = pipeline.Process(ctx, 1)                                   //nolint:errcheck // logging under test
+		_, _ = pipeline.ProcessWith(ctx, 1, LogVerbosity(slog.LevelInfo)) //nolint:errcheck // logging under test
+		if err := bridge.Drain(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if recs := buf.records(t); len(recs) != 1 {
+			t.Errorf("expected only the verbose call logged, got %d records", len(recs))
+		}
+	})
+}
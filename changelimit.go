@@ -0,0 +1,299 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// ErrChangeTooFast is wrapped by errors from a ChangeLimit rejecting a
+// value that moved further than allowed since its key's last value.
+var ErrChangeTooFast = errors.New("value changed too fast")
+
+// ChangeLimit is a data-quality guard for values that should move
+// gradually, such as fraud scores, prices, or sensor readings. It remembers
+// the last accepted value per key and rejects an item whose value moved
+// more than the limit since then, so a score jumping from 0 to 1 or a price
+// falling 90% is stopped before it reaches downstream systems. With
+// SetClamp it instead moves the value by at most the limit and lets the
+// item through.
+//
+// The limit is absolute by default; SetRelative makes it a fraction of the
+// previous value. The first value of a key is accepted as its baseline, as
+// is the first value after the key's TTL lapses. Rejected values do not
+// replace the baseline.
+//
+// SetTTL and SetMaxKeys bound the memory held for keys that stop
+// appearing, and SetCompaction evicts expired keys in the background
+// between items; see KeyStats.
+//
+// CRITICAL: ChangeLimit is STATEFUL - it remembers values across calls.
+// Create it once and reuse it.
+//
+// Example:
+//
+//	var PriceJumpID = pipz.NewIdentity("price-jump", "Rejects price moves over 50%")
+//	guard := pipz.NewChangeLimit(PriceJumpID,
+//	    func(_ context.Context, p Price) string { return p.SKU },
+//	    func(p Price) float64 { return p.Amount },
+//	    0.5,
+//	).SetRelative(true).SetTTL(24 * time.Hour)
+type ChangeLimit[T any] struct {
+	key       func(context.Context, T) string
+	value     func(T) float64
+	clamp     func(T, float64) T
+	clock     clockz.Clock
+	state     *keyedState[float64]
+	compactor *compactor
+	identity  Identity
+	maxChange float64
+	relative  bool
+	mu        sync.Mutex
+	rejected  atomic.Int64
+	clamped   atomic.Int64
+}
+
+// NewChangeLimit creates a ChangeLimit rejecting values that move more than
+// maxChange from the last accepted value of their key.
+func NewChangeLimit[T any](identity Identity, key func(context.Context, T) string, value func(T) float64, maxChange float64) *ChangeLimit[T] {
+	return &ChangeLimit[T]{
+		identity:  identity,
+		key:       key,
+		value:     value,
+		maxChange: math.Abs(maxChange),
+		state:     newKeyedState[float64](),
+	}
+}
+
+// Process implements the Chainable interface.
+// Passes, clamps, or rejects the item by how far its value moved.
+func (c *ChangeLimit[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, c.identity, data)
+
+	c.mu.Lock()
+	keyFunc := c.key
+	valueFunc := c.value
+	clamp := c.clamp
+	c.mu.Unlock()
+
+	key := keyFunc(ctx, data)
+	value := valueFunc(data)
+
+	c.mu.Lock()
+	now := c.getClock().Now()
+	previous, ok := c.state.get(key, now)
+	limit := c.maxChange
+	if ok && c.relative {
+		limit = c.maxChange * math.Abs(previous)
+	}
+	if !ok || (c.relative && previous == 0) || math.Abs(value-previous) <= limit {
+		expired, displaced := c.state.put(key, value, now)
+		keys := len(c.state.entries)
+		c.mu.Unlock()
+		emitEvicted(ctx, c.identity, expired, displaced, keys)
+		return data, nil
+	}
+	if clamp != nil {
+		bounded := previous + math.Copysign(limit, value-previous)
+		expired, displaced := c.state.put(key, bounded, now)
+		keys := len(c.state.entries)
+		c.mu.Unlock()
+		emitEvicted(ctx, c.identity, expired, displaced, keys)
+
+		c.clamped.Add(1)
+		c.emit(ctx, key, previous, value, "clamp")
+		return clamp(data, bounded), nil
+	}
+	c.mu.Unlock()
+
+	c.rejected.Add(1)
+	c.emit(ctx, key, previous, value, "reject")
+	return data, &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(data),
+		Err:       fmt.Errorf("%w: %s moved from %g to %g, limit %g", ErrChangeTooFast, key, previous, value, limit),
+		Path:      []Identity{c.identity},
+	}
+}
+
+// emit reports a limited change.
+func (c *ChangeLimit[T]) emit(ctx context.Context, key string, previous, value float64, mode string) {
+	capitan.Warn(ctx, SignalChangeLimited,
+		FieldName.Field(c.identity.Name()),
+		FieldIdentityID.Field(c.identity.ID().String()),
+		FieldItemKey.Field(key),
+		FieldPreviousValue.Field(previous),
+		FieldValue.Field(value),
+		FieldMode.Field(mode),
+	)
+}
+
+// SetRelative makes the limit a fraction of the previous value, so 0.5
+// allows a 50% move either way. A previous value of zero allows any change.
+func (c *ChangeLimit[T]) SetRelative(relative bool) *ChangeLimit[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.relative = relative
+	return c
+}
+
+// SetMaxChange updates the limit.
+func (c *ChangeLimit[T]) SetMaxChange(maxChange float64) *ChangeLimit[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxChange = math.Abs(maxChange)
+	return c
+}
+
+// SetClamp switches from rejecting to clamping: clamp receives the item and
+// the value moved by at most the limit, and returns the item carrying it.
+// A nil clamp restores rejection.
+func (c *ChangeLimit[T]) SetClamp(clamp func(T, float64) T) *ChangeLimit[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clamp = clamp
+	return c
+}
+
+// SetTTL forgets a key's last value once it has not been seen for ttl, so
+// its next value starts a new baseline. Zero remembers values forever.
+func (c *ChangeLimit[T]) SetTTL(ttl time.Duration) *ChangeLimit[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.ttl = ttl
+	return c
+}
+
+// SetMaxKeys bounds the number of keys remembered, forgetting the least
+// recently accepted keys beyond it. Zero remembers any number of keys.
+func (c *ChangeLimit[T]) SetMaxKeys(n int) *ChangeLimit[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.maxKeys = max(n, 0)
+	return c
+}
+
+// SetCompaction runs Compact every interval in the background until Close,
+// so expired keys are evicted even when no items arrive. Zero stops
+// background compaction.
+func (c *ChangeLimit[T]) SetCompaction(interval time.Duration) *ChangeLimit[T] {
+	c.mu.Lock()
+	previous := c.compactor
+	c.compactor = nil
+	if interval > 0 {
+		c.compactor = startCompactor(c.getClock(), interval, func() { c.Compact() })
+	}
+	c.mu.Unlock()
+	previous.Stop()
+	return c
+}
+
+// Compact evicts expired keys and keys beyond the key limit, returning the
+// number evicted. Keys are also evicted as items are processed.
+func (c *ChangeLimit[T]) Compact() int {
+	c.mu.Lock()
+	expired, displaced := c.state.compact(c.getClock().Now())
+	keys := len(c.state.entries)
+	c.mu.Unlock()
+	emitEvicted(context.Background(), c.identity, expired, displaced, keys)
+	return expired + displaced
+}
+
+// KeyStats returns the number of keys remembered and evicted.
+func (c *ChangeLimit[T]) KeyStats() KeyStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.keyStats()
+}
+
+// SetKeyFunc updates the function extracting each item's key.
+func (c *ChangeLimit[T]) SetKeyFunc(key func(context.Context, T) string) *ChangeLimit[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.key = key
+	return c
+}
+
+// Last returns the last accepted value of key.
+func (c *ChangeLimit[T]) Last(key string) (float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.get(key, c.getClock().Now())
+}
+
+// Forget drops key's last value, so its next value starts a new baseline.
+func (c *ChangeLimit[T]) Forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.remove(key)
+}
+
+// Rejected returns the number of items rejected.
+func (c *ChangeLimit[T]) Rejected() int64 {
+	return c.rejected.Load()
+}
+
+// Clamped returns the number of items clamped.
+func (c *ChangeLimit[T]) Clamped() int64 {
+	return c.clamped.Load()
+}
+
+// WithClock sets a custom clock for testing.
+func (c *ChangeLimit[T]) WithClock(clock clockz.Clock) *ChangeLimit[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+	return c
+}
+
+// getClock returns the clock to use.
+func (c *ChangeLimit[T]) getClock() clockz.Clock {
+	if c.clock == nil {
+		return clockz.RealClock
+	}
+	return c.clock
+}
+
+// Identity returns the identity of this guard.
+func (c *ChangeLimit[T]) Identity() Identity {
+	return c.identity
+}
+
+// Schema returns a Node representing this guard in the pipeline schema.
+func (c *ChangeLimit[T]) Schema() Node {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	mode := "reject"
+	if c.clamp != nil {
+		mode = "clamp"
+	}
+	return Node{
+		Identity: c.identity,
+		Type:     "changelimit",
+		Metadata: map[string]any{
+			"max_change": c.maxChange,
+			"relative":   c.relative,
+			"mode":       mode,
+			"ttl":        c.state.ttl.String(),
+			"max_keys":   c.state.maxKeys,
+		},
+	}
+}
+
+// Close stops background compaction. Close is idempotent.
+func (c *ChangeLimit[T]) Close() error {
+	c.mu.Lock()
+	compactor := c.compactor
+	c.compactor = nil
+	c.mu.Unlock()
+	compactor.Stop()
+	return nil
+}
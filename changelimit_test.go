@@ -0,0 +1,137 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+func TestChangeLimit(t *testing.T) {
+	type score struct {
+		Account string
+		Value   float64
+	}
+	newLimit := func(maxChange float64) *ChangeLimit[score] {
+		return NewChangeLimit(NewIdentity("score-jump", ""),
+			func(_ context.Context, s score) string { return s.Account },
+			func(s score) float64 { return s.Value },
+			maxChange,
+		)
+	}
+	ctx := context.Background()
+
+	t.Run("First Value Is Baseline", func(t *testing.T) {
+		limit := newLimit(0.2)
+		if _, err := limit.Process(ctx, score{"a", 0.9}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if last, ok := limit.Last("a"); !ok || last != 0.9 {
+			t.Errorf("last = %v, %v", last, ok)
+		}
+	})
+
+	t.Run("Rejects Absolute Jump", func(t *testing.T) {
+		limit := newLimit(0.2)
+		_, _ = limit.Process(ctx, score{"a", 0}) //nolint:errcheck // baseline
+		if _, err := limit.Process(ctx, score{"a", 0.15}); err != nil {
+			t.Fatalf("small move rejected: %v", err)
+		}
+		_, err := limit.Process(ctx, score{"a", 1})
+		if !errors.Is(err, ErrChangeTooFast) {
+			t.Fatalf("expected ErrChangeTooFast, got %v", err)
+		}
+		var pipeErr *Error[score]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "score-jump" {
+			t.Errorf("expected pipeline error, got %v", err)
+		}
+		if last, _ := limit.Last("a"); last != 0.15 {
+			t.Errorf("rejected value replaced baseline: %v", last)
+		}
+		if limit.Rejected() != 1 {
+			t.Errorf("rejected = %d", limit.Rejected())
+		}
+	})
+
+	t.Run("Keys Are Independent", func(t *testing.T) {
+		limit := newLimit(0.2)
+		_, _ = limit.Process(ctx, score{"a", 0}) //nolint:errcheck // baseline
+		if _, err := limit.Process(ctx, score{"b", 1}); err != nil {
+			t.Errorf("new key rejected: %v", err)
+		}
+	})
+
+	t.Run("Relative Limit", func(t *testing.T) {
+		limit := newLimit(0.5).SetRelative(true)
+		_, _ = limit.Process(ctx, score{"sku", 100}) //nolint:errcheck // baseline
+		if _, err := limit.Process(ctx, score{"sku", 140}); err != nil {
+			t.Errorf("40%% move rejected: %v", err)
+		}
+		if _, err := limit.Process(ctx, score{"sku", 10}); !errors.Is(err, ErrChangeTooFast) {
+			t.Errorf("expected 90%% drop rejected, got %v", err)
+		}
+
+		_, _ = limit.Process(ctx, score{"zero", 0}) //nolint:errcheck // baseline
+		if _, err := limit.Process(ctx, score{"zero", 50}); err != nil {
+			t.Errorf("move from zero rejected: %v", err)
+		}
+	})
+
+	t.Run("Clamp", func(t *testing.T) {
+		limit := newLimit(0.2).SetClamp(func(s score, v float64) score {
+			s.Value = v
+			return s
+		})
+		_, _ = limit.Process(ctx, score{"a", 0.5}) //nolint:errcheck // baseline
+		result, err := limit.Process(ctx, score{"a", 0})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Value < 0.2999 || result.Value > 0.3001 {
+			t.Errorf("clamped value = %v, want 0.3", result.Value)
+		}
+		if last, _ := limit.Last("a"); last != result.Value {
+			t.Errorf("baseline = %v, want clamped %v", last, result.Value)
+		}
+		if limit.Clamped() != 1 || limit.Rejected() != 0 {
+			t.Errorf("clamped/rejected = %d/%d", limit.Clamped(), limit.Rejected())
+		}
+	})
+
+	t.Run("TTL Resets Baseline", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		limit := newLimit(0.2).SetTTL(time.Hour).WithClock(clock)
+		_, _ = limit.Process(ctx, score{"a", 0}) //nolint:errcheck // baseline
+
+		clock.Advance(30 * time.Minute)
+		if _, err := limit.Process(ctx, score{"a", 1}); !errors.Is(err, ErrChangeTooFast) {
+			t.Fatalf("expected rejection within TTL, got %v", err)
+		}
+
+		clock.Advance(2 * time.Hour)
+		if _, ok := limit.Last("a"); ok {
+			t.Error("expected expired baseline")
+		}
+		if _, err := limit.Process(ctx, score{"a", 1}); err != nil {
+			t.Errorf("expected new baseline after TTL, got %v", err)
+		}
+	})
+
+	t.Run("Forget", func(t *testing.T) {
+		limit := newLimit(0.2)
+		_, _ = limit.Process(ctx, score{"a", 0}) //nolint:errcheck // baseline
+		limit.Forget("a")
+		if _, err := limit.Process(ctx, score{"a", 1}); err != nil {
+			t.Errorf("expected new baseline after Forget, got %v", err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := newLimit(0.5).SetRelative(true).Schema()
+		if node.Type != "changelimit" || node.Metadata["relative"] != true || node.Metadata["mode"] != "reject" {
+			t.Errorf("unexpected schema: %+v", node)
+		}
+	})
+}
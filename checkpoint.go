@@ -0,0 +1,140 @@
+package pipz
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// Checkpointer stores the positions a Subscription has processed up to,
+// so a restarted subscription resumes where the last one stopped. A
+// position is the offset of the next message to read from a partition.
+// Implementations typically write to a database, a key-value store, or
+// the broker's own offset storage.
+type Checkpointer interface {
+	// Load returns the committed positions of stream, keyed by partition,
+	// or none if stream has never been committed.
+	Load(ctx context.Context, stream string) (map[string]int64, error)
+	// Commit records positions as processed for stream.
+	Commit(ctx context.Context, stream string, positions map[string]int64) error
+}
+
+// Seeker is implemented by Subscribers that can start reading from given
+// positions, keyed by partition. Subscription seeks to the checkpointed
+// positions before receiving its first message.
+type Seeker interface {
+	Seek(ctx context.Context, positions map[string]int64) error
+}
+
+// MemoryCheckpointer is a Checkpointer held in memory, for tests and for
+// resuming a subscription restarted within one process.
+type MemoryCheckpointer struct {
+	streams map[string]map[string]int64
+	mu      sync.Mutex
+}
+
+// NewMemoryCheckpointer creates an empty MemoryCheckpointer.
+func NewMemoryCheckpointer() *MemoryCheckpointer {
+	return &MemoryCheckpointer{streams: make(map[string]map[string]int64)}
+}
+
+// Load implements Checkpointer.
+func (m *MemoryCheckpointer) Load(_ context.Context, stream string) (map[string]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.streams[stream]), nil
+}
+
+// Commit implements Checkpointer.
+func (m *MemoryCheckpointer) Commit(_ context.Context, stream string, positions map[string]int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.streams[stream] = maps.Clone(positions)
+	return nil
+}
+
+// offsetTracker tracks the messages of each partition in flight, so only
+// positions below every unfinished message are committed. Messages that
+// finish out of order are then redelivered after a restart rather than
+// skipped, giving at-least-once processing.
+type offsetTracker struct {
+	partitions map[string]*partitionOffsets
+	committed  map[string]int64
+	mu         sync.Mutex
+	commitMu   sync.Mutex
+}
+
+// partitionOffsets holds the unfinished offsets of one partition and the
+// offset after the highest one received.
+type partitionOffsets struct {
+	pending map[int64]int
+	next    int64
+}
+
+// newOffsetTracker creates an offsetTracker starting from the committed
+// positions.
+func newOffsetTracker(committed map[string]int64) *offsetTracker {
+	t := &offsetTracker{
+		partitions: make(map[string]*partitionOffsets),
+		committed:  maps.Clone(committed),
+	}
+	for partition, next := range committed {
+		t.partitions[partition] = &partitionOffsets{pending: make(map[int64]int), next: next}
+	}
+	return t
+}
+
+// start records msg as in flight.
+func (t *offsetTracker) start(msg Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.partitions[msg.Partition]
+	if !ok {
+		p = &partitionOffsets{pending: make(map[int64]int), next: msg.Offset}
+		t.partitions[msg.Partition] = p
+	}
+	p.pending[msg.Offset]++
+	p.next = max(p.next, msg.Offset+1)
+}
+
+// done records msg as finished.
+func (t *offsetTracker) done(msg Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.partitions[msg.Partition]
+	if p.pending[msg.Offset]--; p.pending[msg.Offset] <= 0 {
+		delete(p.pending, msg.Offset)
+	}
+}
+
+// positions returns the position of each partition: its lowest unfinished
+// offset, or the offset after the last received when none is unfinished.
+func (t *offsetTracker) positions() map[string]int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	positions := make(map[string]int64, len(t.partitions))
+	for partition, p := range t.partitions {
+		position := p.next
+		for offset := range p.pending {
+			position = min(position, offset)
+		}
+		positions[partition] = position
+	}
+	return positions
+}
+
+// commit commits the current positions with checkpointer, unless they are
+// already committed. Commits are serialized.
+func (t *offsetTracker) commit(ctx context.Context, checkpointer Checkpointer, stream string) error {
+	t.commitMu.Lock()
+	defer t.commitMu.Unlock()
+	positions := t.positions()
+	if maps.Equal(positions, t.committed) {
+		return nil
+	}
+	if err := recoverCall(ctx, func() error { return checkpointer.Commit(ctx, stream, positions) }); err != nil {
+		return err
+	}
+	t.committed = positions
+	return nil
+}
@@ -0,0 +1,242 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+// logSubscriber replays a partitioned log from the positions it is sought
+// to, recording them.
+type logSubscriber struct {
+	sought map[string]int64
+	log    []Message
+	next   int
+	mu     sync.Mutex
+}
+
+func newLogSubscriber(partitions map[string]int) *logSubscriber {
+	l := &logSubscriber{}
+	for offset := 0; ; offset++ {
+		added := false
+		for _, partition := range []string{"p0", "p1"} {
+			if offset < partitions[partition] {
+				l.log = append(l.log, Message{
+					Partition: partition,
+					Offset:    int64(offset),
+					Payload:   []byte(strconv.Itoa(offset)),
+				})
+				added = true
+			}
+		}
+		if !added {
+			return l
+		}
+	}
+}
+
+func (l *logSubscriber) Seek(_ context.Context, positions map[string]int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sought = positions
+	kept := l.log[:0]
+	for _, msg := range l.log {
+		if msg.Offset >= positions[msg.Partition] {
+			kept = append(kept, msg)
+		}
+	}
+	l.log = kept
+	return nil
+}
+
+func (l *logSubscriber) Receive(_ context.Context) (Message, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.next >= len(l.log) {
+		return Message{}, ErrSubscriptionClosed
+	}
+	msg := l.log[l.next]
+	l.next++
+	return msg, nil
+}
+
+// failingCheckpointer fails every call.
+type failingCheckpointer struct{ loadErr, commitErr error }
+
+func (f failingCheckpointer) Load(context.Context, string) (map[string]int64, error) {
+	return nil, f.loadErr
+}
+
+func (f failingCheckpointer) Commit(context.Context, string, map[string]int64) error {
+	return f.commitErr
+}
+
+func TestSubscriptionCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	stream := testIdentity("orders")
+
+	t.Run("Resumes From Committed Positions", func(t *testing.T) {
+		checkpoints := NewMemoryCheckpointer()
+		var processed []int
+		var mu sync.Mutex
+		record := Effect(testIdentity("record"), func(_ context.Context, n int) error {
+			mu.Lock()
+			defer mu.Unlock()
+			processed = append(processed, n)
+			return nil
+		})
+
+		first := newLogSubscriber(map[string]int{"p0": 3, "p1": 2})
+		if err := NewSubscription[int](stream, first, nil, record).SetCheckpointer(checkpoints, 0).Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+		positions, _ := checkpoints.Load(ctx, "orders")
+		if positions["p0"] != 3 || positions["p1"] != 2 {
+			t.Fatalf("expected positions p0=3 p1=2, got %v", positions)
+		}
+
+		processed = nil
+		restarted := newLogSubscriber(map[string]int{"p0": 5, "p1": 2})
+		if err := NewSubscription[int](stream, restarted, nil, record).SetCheckpointer(checkpoints, 0).Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if restarted.sought["p0"] != 3 || len(processed) != 2 || processed[0] != 3 || processed[1] != 4 {
+			t.Errorf("expected resume at p0 offset 3, sought %v and processed %v", restarted.sought, processed)
+		}
+	})
+
+	t.Run("Stopped Message Is Not Committed", func(t *testing.T) {
+		checkpoints := NewMemoryCheckpointer()
+		fail := Apply(testIdentity("fail"), func(_ context.Context, n int) (int, error) {
+			if n == 2 {
+				return n, errors.New("poison")
+			}
+			return n, nil
+		})
+		stopErr := errors.New("stop")
+		err := NewSubscription[int](stream, newLogSubscriber(map[string]int{"p0": 4}), nil, fail).
+			SetCheckpointer(checkpoints, time.Hour).
+			SetErrorHandler(func(context.Context, Message, error) error { return stopErr }).
+			Run(ctx)
+		if !errors.Is(err, stopErr) {
+			t.Fatalf("expected stop error, got %v", err)
+		}
+		if positions, _ := checkpoints.Load(ctx, "orders"); positions["p0"] != 2 {
+			t.Errorf("expected final commit before the failed message, got %v", positions)
+		}
+	})
+
+	t.Run("Continued Failures Are Committed", func(t *testing.T) {
+		checkpoints := NewMemoryCheckpointer()
+		fail := Apply(testIdentity("fail"), func(_ context.Context, n int) (int, error) {
+			return n, errors.New("bad record")
+		})
+		err := NewSubscription[int](stream, newLogSubscriber(map[string]int{"p0": 2}), nil, fail).
+			SetCheckpointer(checkpoints, 0).
+			SetErrorHandler(func(context.Context, Message, error) error { return nil }).
+			Run(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if positions, _ := checkpoints.Load(ctx, "orders"); positions["p0"] != 2 {
+			t.Errorf("expected failures handled by the error handler to be committed, got %v", positions)
+		}
+	})
+
+	t.Run("Commits On Interval", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		checkpoints := NewMemoryCheckpointer()
+		msgs := make(chanSubscriber, 2)
+		msgs <- Message{Partition: "p0", Offset: 7, Payload: []byte("7")}
+		msgs <- Message{Partition: "p0", Offset: 8, Payload: []byte("8")}
+		var processed atomic.Int32
+		count := Effect(testIdentity("count"), func(context.Context, int) error {
+			processed.Add(1)
+			return nil
+		})
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() {
+			done <- NewSubscription[int](stream, msgs, nil, count).
+				WithClock(clock).
+				SetCheckpointer(checkpoints, time.Second).
+				Run(runCtx)
+		}()
+
+		deadline := time.Now().Add(time.Second)
+		for {
+			if positions, _ := checkpoints.Load(ctx, "orders"); processed.Load() == 2 && positions["p0"] == 9 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("expected positions committed on the interval")
+			}
+			advanceWhenWaiting(t, clock, time.Second)
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Load Failure Stops Run", func(t *testing.T) {
+		loadErr := errors.New("store down")
+		err := NewSubscription[int](stream, newLogSubscriber(nil), nil, Transform(testIdentity("noop"), func(_ context.Context, n int) int { return n })).
+			SetCheckpointer(failingCheckpointer{loadErr: loadErr}, 0).
+			Run(ctx)
+		if !errors.Is(err, loadErr) {
+			t.Errorf("expected load error, got %v", err)
+		}
+	})
+
+	t.Run("Commit Failure Does Not Stop Run", func(t *testing.T) {
+		err := NewSubscription[int](stream, newLogSubscriber(map[string]int{"p0": 2}), nil, Transform(testIdentity("noop"), func(_ context.Context, n int) int { return n })).
+			SetCheckpointer(failingCheckpointer{commitErr: errors.New("store down")}, 0).
+			Run(ctx)
+		if err != nil {
+			t.Errorf("expected commit failures to be retried, not returned, got %v", err)
+		}
+	})
+}
+
+func TestOffsetTracker(t *testing.T) {
+	msg := func(offset int64) Message { return Message{Partition: "p0", Offset: offset} }
+
+	t.Run("Commits Below Unfinished Messages", func(t *testing.T) {
+		tracker := newOffsetTracker(map[string]int64{"p0": 10, "p1": 4})
+		for _, offset := range []int64{10, 11, 12} {
+			tracker.start(msg(offset))
+		}
+		tracker.done(msg(11))
+		tracker.done(msg(12))
+		if positions := tracker.positions(); positions["p0"] != 10 || positions["p1"] != 4 {
+			t.Errorf("expected p0=10 p1=4, got %v", positions)
+		}
+		tracker.done(msg(10))
+		if positions := tracker.positions(); positions["p0"] != 13 {
+			t.Errorf("expected p0=13, got %v", positions)
+		}
+	})
+
+	t.Run("Redelivered Offsets", func(t *testing.T) {
+		tracker := newOffsetTracker(nil)
+		tracker.start(msg(3))
+		tracker.start(msg(3))
+		tracker.done(msg(3))
+		if positions := tracker.positions(); positions["p0"] != 3 {
+			t.Errorf("expected p0=3 while a copy is unfinished, got %v", positions)
+		}
+		tracker.done(msg(3))
+		if positions := tracker.positions(); positions["p0"] != 4 {
+			t.Errorf("expected p0=4, got %v", positions)
+		}
+	})
+}
@@ -11,6 +11,10 @@ import (
 	"github.com/zoobzio/clockz"
 )
 
+// ErrCircuitOpen is wrapped by errors from a CircuitBreaker rejecting an
+// item while open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
 // State constants.
 const (
 	stateClosed   = "closed"
@@ -101,7 +105,10 @@ type CircuitBreaker[T any] struct {
 	lastFailTime     time.Time
 	processor        Chainable[T]
 	clock            clockz.Clock
+	sync             BreakerSync
+	unsubscribe      func()
 	identity         Identity
+	origin           string
 	state            string
 	mu               sync.Mutex
 	resetTimeout     time.Duration
@@ -132,10 +139,42 @@ func NewCircuitBreaker[T any](identity Identity, processor Chainable[T], failure
 	}
 }
 
+// NewCircuitBreakerWithOptions creates a CircuitBreaker from functional options.
+// It accepts WithFailureThreshold (default 5), WithSuccessThreshold (default 1),
+// WithResetTimeout (default 30s), and WithClock. Invalid or inapplicable
+// options return an error wrapping ErrInvalidOption.
+//
+// Example:
+//
+//	breaker, err := pipz.NewCircuitBreakerWithOptions(BreakerID, apiCall,
+//	    pipz.WithFailureThreshold(10),
+//	    pipz.WithResetTimeout(time.Minute),
+//	)
+func NewCircuitBreakerWithOptions[T any](identity Identity, processor Chainable[T], opts ...Option) (*CircuitBreaker[T], error) {
+	cfg, err := newConfig("circuit breaker", Config{
+		FailureThreshold: 5,
+		SuccessThreshold: 1,
+		ResetTimeout:     30 * time.Second,
+	}, optFailureThreshold|optSuccessThreshold|optResetTimeout|optClock, 0, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cb := NewCircuitBreaker(identity, processor, cfg.FailureThreshold, cfg.ResetTimeout)
+	cb.successThreshold = cfg.SuccessThreshold
+	cb.clock = cfg.Clock
+	return cb, nil
+}
+
 // Process implements the Chainable interface.
 func (cb *CircuitBreaker[T]) Process(ctx context.Context, data T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, cb.identity, data)
 
+	ctx, guardErr := enterDepth(ctx, cb, cb.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
 	cb.mu.Lock()
 
 	// Check if we should transition from open to half-open
@@ -173,8 +212,8 @@ func (cb *CircuitBreaker[T]) Process(ctx context.Context, data T) (result T, err
 
 		cb.mu.Unlock()
 		return data, &Error[T]{
-			Err:       fmt.Errorf("circuit breaker is open"),
-			InputData: data,
+			Err:       ErrCircuitOpen,
+			InputData: errorInput(data),
 			Path:      []Identity{cb.identity},
 			Timestamp: cb.getClock().Now(),
 		}
@@ -185,9 +224,23 @@ func (cb *CircuitBreaker[T]) Process(ctx context.Context, data T) (result T, err
 	// Try the operation
 	result, err = processor.Process(ctx, data)
 
+	// Publish any transition once the mutex is released
+	var transition *CircuitBreakerState
+	defer func() {
+		if transition != nil {
+			cb.publishTransition(ctx, *transition)
+		}
+	}()
+
 	// Record the result
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
+	defer func() {
+		if cb.generation == generation && cb.state != state && cb.state != stateHalfOpen {
+			snapshot := cb.snapshotLocked()
+			transition = &snapshot
+		}
+	}()
 
 	// Only update state if we're still in the same generation
 	// This prevents race conditions in half-open state
@@ -196,16 +249,22 @@ func (cb *CircuitBreaker[T]) Process(ctx context.Context, data T) (result T, err
 	}
 
 	if err != nil {
-		cb.onFailure(ctx)
+		// Skips and early exits mean the dependency answered; only failures
+		// trip the breaker
+		if isControl(err) {
+			cb.onSuccess(ctx)
+		} else {
+			cb.onFailure(ctx)
+		}
 		// Wrap the error with circuit breaker context
 		var pipeErr *Error[T]
 		if errors.As(err, &pipeErr) {
-			pipeErr.Path = append([]Identity{cb.identity}, pipeErr.Path...)
+			pipeErr.prependPath(ctx, cb.identity)
 			return result, pipeErr
 		}
 		return result, &Error[T]{
 			Err:       err,
-			InputData: data,
+			InputData: errorInput(data),
 			Path:      []Identity{cb.identity},
 			Timestamp: cb.getClock().Now(),
 		}
@@ -311,6 +370,31 @@ func (cb *CircuitBreaker[T]) SetResetTimeout(d time.Duration) *CircuitBreaker[T]
 	return cb
 }
 
+// Reconfigure atomically updates the failure threshold, success threshold,
+// and reset timeout from WithFailureThreshold, WithSuccessThreshold, and
+// WithResetTimeout. Unspecified parameters keep their current values and the
+// circuit state is preserved. Invalid or inapplicable options leave the
+// breaker unchanged and return an error wrapping ErrInvalidOption.
+func (cb *CircuitBreaker[T]) Reconfigure(ctx context.Context, opts ...Option) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cfg, err := newConfig("circuit breaker", Config{
+		FailureThreshold: cb.failureThreshold,
+		SuccessThreshold: cb.successThreshold,
+		ResetTimeout:     cb.resetTimeout,
+	}, optFailureThreshold|optSuccessThreshold|optResetTimeout, 0, opts)
+	if err != nil {
+		return err
+	}
+
+	cb.failureThreshold = cfg.FailureThreshold
+	cb.successThreshold = cfg.SuccessThreshold
+	cb.resetTimeout = cfg.ResetTimeout
+	emitReconfigured(ctx, cb.identity, cfg)
+	return nil
+}
+
 // GetState returns the current circuit state.
 func (cb *CircuitBreaker[T]) GetState() string {
 	cb.mu.Lock()
@@ -356,6 +440,58 @@ func (cb *CircuitBreaker[T]) Reset() *CircuitBreaker[T] {
 	return cb
 }
 
+// CircuitBreakerState is a snapshot of a CircuitBreaker's runtime state.
+type CircuitBreakerState struct {
+	LastFailTime time.Time `json:"last_fail_time"`
+	State        string    `json:"state"`
+	Generation   int       `json:"generation"`
+	Failures     int       `json:"failures"`
+	Successes    int       `json:"successes"`
+}
+
+// Snapshot returns the breaker's current state for persistence.
+func (cb *CircuitBreaker[T]) Snapshot() CircuitBreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.snapshotLocked()
+}
+
+// snapshotLocked returns the current state. Must be called with mutex held.
+func (cb *CircuitBreaker[T]) snapshotLocked() CircuitBreakerState {
+	return CircuitBreakerState{
+		LastFailTime: cb.lastFailTime,
+		State:        cb.state,
+		Generation:   cb.generation,
+		Failures:     cb.failures,
+		Successes:    cb.successes,
+	}
+}
+
+// Restore replaces the breaker's state with a snapshot, typically one saved
+// by another instance or before a restart. An open breaker stays open until
+// the reset timeout has elapsed since LastFailTime. A snapshot with an
+// unknown state or negative counts returns an error wrapping
+// ErrInvalidSnapshot and leaves the breaker unchanged.
+func (cb *CircuitBreaker[T]) Restore(state CircuitBreakerState) error {
+	switch state.State {
+	case stateClosed, stateOpen, stateHalfOpen:
+	default:
+		return fmt.Errorf("%w: unknown circuit state %q", ErrInvalidSnapshot, state.State)
+	}
+	if state.Failures < 0 || state.Successes < 0 || state.Generation < 0 {
+		return fmt.Errorf("%w: negative circuit counts", ErrInvalidSnapshot)
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.lastFailTime = state.LastFailTime
+	cb.state = state.State
+	cb.generation = state.Generation
+	cb.failures = state.Failures
+	cb.successes = state.Successes
+	return nil
+}
+
 // WithClock sets a custom clock for testing.
 func (cb *CircuitBreaker[T]) WithClock(clock clockz.Clock) *CircuitBreaker[T] {
 	cb.mu.Lock()
@@ -402,6 +538,10 @@ func (cb *CircuitBreaker[T]) Close() error {
 	cb.closeOnce.Do(func() {
 		cb.mu.Lock()
 		defer cb.mu.Unlock()
+		if cb.unsubscribe != nil {
+			cb.unsubscribe()
+			cb.unsubscribe = nil
+		}
 		cb.closeErr = cb.processor.Close()
 	})
 	return cb.closeErr
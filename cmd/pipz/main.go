@@ -0,0 +1,67 @@
+// Command pipz provides developer tooling for pipz pipelines.
+//
+// Usage:
+//
+//	pipz new -type Order -processors validate,enrich,save
+//
+// The new command generates a package with a Cloner data type, processor
+// stubs with identities, a composed pipeline, table-driven tests, and a
+// benchmark, ready to fill in.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `pipz is a tool for working with pipz pipelines.
+
+Usage:
+
+	pipz <command> [flags]
+
+Commands:
+
+	new    generate a data type, processors, pipeline, and tests
+
+Run "pipz <command> -h" for command flags.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "new":
+		err = runNew(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "pipz: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(stderr, "pipz %s: %v\n", args[0], err)
+		return 1
+	}
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// errUsage reports invalid flags after the usage has been printed.
+var errUsage = errors.New("invalid usage")
+
+// processorName matches kebab-case processor names such as enrich-address.
+var processorName = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// scaffold describes the package generated by pipz new.
+type scaffold struct {
+	Package    string
+	Type       string
+	Receiver   string
+	Name       string
+	Processors []processorStub
+}
+
+// processorStub describes one generated processor.
+type processorStub struct {
+	Name   string // identity name, e.g. enrich-address
+	GoName string // exported Go name, e.g. EnrichAddress
+}
+
+// runNew implements pipz new.
+func runNew(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	typeName := fs.String("type", "", "exported name of the data type, e.g. Order (required)")
+	processors := fs.String("processors", "validate,process", "comma-separated kebab-case processor names")
+	pkg := fs.String("package", "", "package name (default: lowercase type name)")
+	dir := fs.String("dir", "", "output directory (default: ./<package>)")
+	force := fs.Bool("force", false, "overwrite existing files")
+	fs.Usage = func() {
+		fmt.Fprint(stderr, "Usage: pipz new -type Name [flags]\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if *typeName == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	s, err := newScaffold(*typeName, *pkg, strings.Split(*processors, ","))
+	if err != nil {
+		return err
+	}
+	if *dir == "" {
+		*dir = s.Package
+	}
+
+	files, err := s.render()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(*dir, name)
+		if !*force {
+			if _, statErr := os.Stat(path); statErr == nil {
+				return fmt.Errorf("%s already exists (use -force to overwrite)", path)
+			}
+		}
+		if err := os.WriteFile(path, files[name], 0o644); err != nil { //nolint:gosec // generated source is meant to be readable
+			return err
+		}
+		fmt.Fprintln(stdout, "created", path)
+	}
+	return nil
+}
+
+// newScaffold validates the inputs and derives the generated names.
+func newScaffold(typeName, pkg string, processors []string) (*scaffold, error) {
+	if !token.IsIdentifier(typeName) || !token.IsExported(typeName) {
+		return nil, fmt.Errorf("type %q must be an exported Go identifier", typeName)
+	}
+	if pkg == "" {
+		pkg = strings.ToLower(typeName)
+	}
+	if !token.IsIdentifier(pkg) || pkg != strings.ToLower(pkg) {
+		return nil, fmt.Errorf("package %q must be a lowercase Go identifier", pkg)
+	}
+
+	s := &scaffold{
+		Package:  pkg,
+		Type:     typeName,
+		Receiver: strings.ToLower(typeName[:1]),
+		Name:     kebab(typeName),
+	}
+	seen := make(map[string]bool, len(processors))
+	for _, name := range processors {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !processorName.MatchString(name) {
+			return nil, fmt.Errorf("processor %q must be kebab-case, e.g. enrich-address", name)
+		}
+		stub := processorStub{Name: name, GoName: camel(name)}
+		if seen[stub.GoName] || stub.GoName == typeName {
+			return nil, fmt.Errorf("processor %q collides with another generated name", name)
+		}
+		seen[stub.GoName] = true
+		s.Processors = append(s.Processors, stub)
+	}
+	if len(s.Processors) == 0 {
+		return nil, errors.New("at least one processor is required")
+	}
+	return s, nil
+}
+
+// render executes the templates and formats the generated files.
+func (s *scaffold) render() (map[string][]byte, error) {
+	base := strings.ReplaceAll(s.Name, "-", "_")
+	files := map[string]*template.Template{
+		base + ".go":      sourceTemplate,
+		base + "_test.go": testTemplate,
+	}
+	out := make(map[string][]byte, len(files))
+	for name, tmpl := range files {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, s); err != nil {
+			return nil, fmt.Errorf("render %s: %w", name, err)
+		}
+		formatted, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("format %s: %w", name, err)
+		}
+		out[name] = formatted
+	}
+	return out, nil
+}
+
+// camel converts a kebab-case name to an exported Go name.
+func camel(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "-") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
+
+// kebab converts an exported Go name to a kebab-case identity name.
+func kebab(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word at a lower-to-upper change or at the last
+			// capital of an acronym followed by lowercase (HTTPRequest).
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var sourceTemplate = template.Must(template.New("source").Parse(`// Package {{.Package}} processes {{.Type}} values with a pipz pipeline.
+package {{.Package}}
+
+import (
+	"context"
+
+	"github.com/zoobzio/pipz"
+)
+
+// {{.Type}} is the data flowing through the pipeline.
+type {{.Type}} struct {
+	ID string
+}
+
+// Clone returns a deep copy of {{.Receiver}} for connectors that run processors in
+// parallel. Copy any slices, maps, and pointers added to {{.Type}}.
+func ({{.Receiver}} {{.Type}}) Clone() {{.Type}} {
+	return {{.Receiver}}
+}
+
+// Identities for the pipeline and its processors.
+var (
+	{{.Type}}PipelineID = pipz.NewIdentity("{{.Name}}-pipeline", "Processes {{.Type}} values")
+	{{.Type}}StepsID    = pipz.NewIdentity("{{.Name}}-steps", "Runs the {{.Name}} processors in order")
+{{- range .Processors}}
+	{{.GoName}}ID = pipz.NewIdentity("{{.Name}}", "TODO: describe {{.Name}}")
+{{- end}}
+)
+{{range .Processors}}
+// {{.GoName}} is the {{.Name}} step.
+func {{.GoName}}(_ context.Context, {{$.Receiver}} {{$.Type}}) ({{$.Type}}, error) {
+	return {{$.Receiver}}, nil
+}
+{{end}}
+// New{{.Type}}Pipeline composes the processors into a pipeline.
+func New{{.Type}}Pipeline() *pipz.Pipeline[{{.Type}}] {
+	return pipz.NewPipeline({{.Type}}PipelineID, pipz.NewSequence({{.Type}}StepsID,
+{{- range .Processors}}
+		pipz.Apply({{.GoName}}ID, {{.GoName}}),
+{{- end}}
+	))
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Parse(`package {{.Package}}
+
+import (
+	"context"
+	"testing"
+)
+{{range .Processors}}
+func Test{{.GoName}}(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   {{$.Type}}
+		want    {{$.Type}}
+		wantErr bool
+	}{
+		{name: "Passes Through", input: {{$.Type}}{ID: "1"}, want: {{$.Type}}{ID: "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := {{.GoName}}(context.Background(), tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+{{end}}
+func Test{{.Type}}Pipeline(t *testing.T) {
+	pipeline := New{{.Type}}Pipeline()
+	defer pipeline.Close()
+
+	got, err := pipeline.Process(context.Background(), {{.Type}}{ID: "1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != "1" {
+		t.Errorf("expected ID 1, got %q", got.ID)
+	}
+}
+
+func Benchmark{{.Type}}Pipeline(b *testing.B) {
+	pipeline := New{{.Type}}Pipeline()
+	defer pipeline.Close()
+	ctx := context.Background()
+	input := {{.Type}}{ID: "1"}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := pipeline.Process(ctx, input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+`))
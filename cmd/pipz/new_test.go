@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunNew(t *testing.T) {
+	t.Run("Generates Parsable Package", func(t *testing.T) {
+		dir := t.TempDir()
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"new", "-type", "Order", "-processors", "validate,enrich-address", "-dir", dir}, &stdout, &stderr)
+		if code != 0 {
+			t.Fatalf("expected exit 0, got %d: %s", code, stderr.String())
+		}
+
+		for _, name := range []string{"order.go", "order_test.go"} {
+			path := filepath.Join(dir, name)
+			if !strings.Contains(stdout.String(), path) {
+				t.Errorf("expected %s to be reported, got %q", path, stdout.String())
+			}
+			file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+			if err != nil {
+				t.Fatalf("generated %s does not parse: %v", name, err)
+			}
+			if file.Name.Name != "order" {
+				t.Errorf("expected package order, got %s", file.Name.Name)
+			}
+		}
+
+		source, err := os.ReadFile(filepath.Join(dir, "order.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			"func (o Order) Clone() Order",
+			`pipz.NewIdentity("enrich-address"`,
+			"func EnrichAddress(_ context.Context, o Order) (Order, error)",
+			"func NewOrderPipeline() *pipz.Pipeline[Order]",
+		} {
+			if !strings.Contains(string(source), want) {
+				t.Errorf("expected generated source to contain %q", want)
+			}
+		}
+
+		tests, err := os.ReadFile(filepath.Join(dir, "order_test.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{"func TestValidate(", "func TestOrderPipeline(", "func BenchmarkOrderPipeline("} {
+			if !strings.Contains(string(tests), want) {
+				t.Errorf("expected generated tests to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("Refuses To Overwrite", func(t *testing.T) {
+		dir := t.TempDir()
+		args := []string{"new", "-type", "Order", "-dir", dir}
+		var stderr bytes.Buffer
+		if code := run(args, &bytes.Buffer{}, &stderr); code != 0 {
+			t.Fatalf("expected first run to succeed: %s", stderr.String())
+		}
+		stderr.Reset()
+		if code := run(args, &bytes.Buffer{}, &stderr); code != 1 || !strings.Contains(stderr.String(), "already exists") {
+			t.Errorf("expected overwrite refusal, got %d: %s", code, stderr.String())
+		}
+		if code := run(append(args, "-force"), &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+			t.Errorf("expected -force to overwrite, got %d", code)
+		}
+	})
+
+	t.Run("Rejects Invalid Input", func(t *testing.T) {
+		cases := [][]string{
+			{"new"},
+			{"new", "-type", "order"},
+			{"new", "-type", "Order", "-processors", "Enrich_Address"},
+			{"new", "-type", "Order", "-processors", "save,save"},
+			{"new", "-type", "Order", "-package", "Orders"},
+			{"bogus"},
+		}
+		for _, args := range cases {
+			if code := run(args, &bytes.Buffer{}, &bytes.Buffer{}); code == 0 {
+				t.Errorf("expected %v to fail", args)
+			}
+		}
+	})
+}
+
+func TestNames(t *testing.T) {
+	t.Run("Camel", func(t *testing.T) {
+		if got := camel("enrich-address"); got != "EnrichAddress" {
+			t.Errorf("expected EnrichAddress, got %s", got)
+		}
+	})
+
+	t.Run("Kebab", func(t *testing.T) {
+		for input, want := range map[string]string{
+			"Order":         "order",
+			"LineItem":      "line-item",
+			"HTTPOrder":     "http-order",
+			"OrderV2":       "order-v2",
+			"ShippingLabel": "shipping-label",
+		} {
+			if got := kebab(input); got != want {
+				t.Errorf("kebab(%q): expected %q, got %q", input, want, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// CollectedErrors holds every failure from a CollectErrors run, in processor
+// order. It implements Unwrap() []error, so errors.Is and errors.As search
+// all collected failures.
+type CollectedErrors struct {
+	Errors []error
+}
+
+// Error implements the error interface.
+func (e *CollectedErrors) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		parts[i] = err.Error()
+	}
+	return fmt.Sprintf("%d error(s): %s", len(e.Errors), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the collected errors.
+func (e *CollectedErrors) Unwrap() []error {
+	return e.Errors
+}
+
+// CollectErrors runs processors in order like Sequence, but keeps going
+// past failures so a single run reports every problem at once. Each
+// processor receives the last successful value; a failing processor's
+// output is discarded.
+//
+// When any processor fails, Process returns the last good value together
+// with an Error whose Err is a *CollectedErrors holding each failure.
+// Validation pipelines are the typical use: users want to see every
+// invalid field, not just the first.
+//
+// If the context is canceled, remaining processors are skipped and the
+// context error is collected alongside earlier failures.
+//
+// Example:
+//
+//	var ValidateUserID = pipz.NewIdentity("validate-user", "Reports all validation problems")
+//	validate := pipz.NewCollectErrors(ValidateUserID,
+//	    pipz.Effect(CheckEmailID, checkEmail),
+//	    pipz.Effect(CheckAgeID, checkAge),
+//	    pipz.Effect(CheckCountryID, checkCountry),
+//	)
+//
+//	user, err := validate.Process(ctx, user)
+//	var collected *pipz.CollectedErrors
+//	if errors.As(err, &collected) {
+//	    for _, e := range collected.Errors {
+//	        log.Println(e)
+//	    }
+//	}
+type CollectErrors[T any] struct {
+	identity   Identity
+	processors []Chainable[T]
+	mu         sync.RWMutex
+	closeOnce  sync.Once
+	closeErr   error
+}
+
+// NewCollectErrors creates a CollectErrors running the given processors.
+func NewCollectErrors[T any](identity Identity, processors ...Chainable[T]) *CollectErrors[T] {
+	return &CollectErrors[T]{
+		identity:   identity,
+		processors: processors,
+	}
+}
+
+// Process implements the Chainable interface.
+func (c *CollectErrors[T]) Process(ctx context.Context, input T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, c.identity, input)
+
+	ctx, guardErr := enterDepth(ctx, c, c.identity, input)
+	if guardErr != nil {
+		return input, guardErr
+	}
+
+	start := time.Now()
+
+	c.mu.RLock()
+	processors := make([]Chainable[T], len(c.processors))
+	copy(processors, c.processors)
+	c.mu.RUnlock()
+
+	result = input
+	var errs []error
+	for _, proc := range processors {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			errs = append(errs, ctxErr)
+			break
+		}
+		out, procErr := proc.Process(ctx, result)
+		if procErr != nil {
+			var pipeErr *Error[T]
+			if errors.As(procErr, &pipeErr) {
+				pipeErr.prependPath(ctx, c.identity)
+			}
+			errs = append(errs, procErr)
+			continue
+		}
+		result = out
+	}
+
+	if len(errs) == 0 {
+		return result, nil
+	}
+
+	capitan.Warn(ctx, SignalCollectErrorsFailed,
+		FieldName.Field(c.identity.Name()),
+		FieldIdentityID.Field(c.identity.ID().String()),
+		FieldProcessorCount.Field(len(processors)),
+		FieldErrorCount.Field(len(errs)),
+		FieldDuration.Field(time.Since(start).Seconds()),
+	)
+
+	last := errs[len(errs)-1]
+	return result, &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(input),
+		Err:       &CollectedErrors{Errors: errs},
+		Path:      []Identity{c.identity},
+		Duration:  time.Since(start),
+		Timeout:   errors.Is(last, context.DeadlineExceeded),
+		Canceled:  errors.Is(last, context.Canceled),
+	}
+}
+
+// Add appends a processor.
+func (c *CollectErrors[T]) Add(processor Chainable[T]) *CollectErrors[T] {
+	noteChildren(c.identity, processor)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.processors = append(c.processors, processor)
+	return c
+}
+
+// Remove removes the processor at the specified index.
+func (c *CollectErrors[T]) Remove(index int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if index < 0 || index >= len(c.processors) {
+		return ErrIndexOutOfBounds
+	}
+
+	c.processors = append(c.processors[:index], c.processors[index+1:]...)
+	return nil
+}
+
+// Len returns the number of processors.
+func (c *CollectErrors[T]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.processors)
+}
+
+// SetProcessors replaces all processors atomically.
+func (c *CollectErrors[T]) SetProcessors(processors ...Chainable[T]) *CollectErrors[T] {
+	noteChildren(c.identity, processors...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.processors = make([]Chainable[T], len(processors))
+	copy(c.processors, processors)
+	return c
+}
+
+// Identity returns the identity of this connector.
+func (c *CollectErrors[T]) Identity() Identity {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (c *CollectErrors[T]) Schema() Node {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	steps := make([]Node, len(c.processors))
+	for i, proc := range c.processors {
+		steps[i] = proc.Schema()
+	}
+
+	return Node{
+		Identity: c.identity,
+		Type:     "collecterrors",
+		Flow:     CollectErrorsFlow{Steps: steps},
+	}
+}
+
+// Close gracefully shuts down the connector and all its child processors.
+// Close is idempotent - multiple calls return the same result.
+func (c *CollectErrors[T]) Close() error {
+	c.closeOnce.Do(func() {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+
+		var errs []error
+		for i := len(c.processors) - 1; i >= 0; i-- {
+			if err := c.processors[i].Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		c.closeErr = errors.Join(errs...)
+	})
+	return c.closeErr
+}
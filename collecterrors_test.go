@@ -0,0 +1,148 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestCollectErrors(t *testing.T) {
+	errOdd := errors.New("odd")
+	errBig := errors.New("too big")
+	double := Transform(NewIdentity("double", ""), func(_ context.Context, v int) int { return v * 2 })
+	checkOdd := Apply(NewIdentity("check-odd", ""), func(_ context.Context, v int) (int, error) {
+		if v%2 != 0 {
+			return -1, errOdd
+		}
+		return v, nil
+	})
+	checkBig := Apply(NewIdentity("check-big", ""), func(_ context.Context, v int) (int, error) {
+		if v > 10 {
+			return -1, errBig
+		}
+		return v, nil
+	})
+
+	t.Run("All Succeed", func(t *testing.T) {
+		c := NewCollectErrors(NewIdentity("collect", ""), double, checkOdd, checkBig)
+		result, err := c.Process(context.Background(), 3)
+		if err != nil || result != 6 {
+			t.Errorf("expected 6, got %d (%v)", result, err)
+		}
+	})
+
+	t.Run("Collects Every Failure", func(t *testing.T) {
+		c := NewCollectErrors(NewIdentity("collect", ""), checkOdd, checkBig, double)
+		result, err := c.Process(context.Background(), 11)
+		if result != 22 {
+			t.Errorf("expected last good value 22, got %d", result)
+		}
+
+		var collected *CollectedErrors
+		if !errors.As(err, &collected) {
+			t.Fatalf("expected CollectedErrors, got %v", err)
+		}
+		if len(collected.Errors) != 2 {
+			t.Fatalf("expected 2 errors, got %d", len(collected.Errors))
+		}
+		if !errors.Is(err, errOdd) || !errors.Is(err, errBig) {
+			t.Errorf("expected errors.Is to find both failures, got %v", err)
+		}
+
+		var first *Error[int]
+		if !errors.As(collected.Errors[0], &first) {
+			t.Fatalf("expected pipz error, got %v", collected.Errors[0])
+		}
+		if len(first.Path) != 2 || first.Path[0].Name() != "collect" || first.Path[1].Name() != "check-odd" {
+			t.Errorf("unexpected path: %v", first.Path)
+		}
+	})
+
+	t.Run("Error Path", func(t *testing.T) {
+		c := NewCollectErrors(NewIdentity("collect", ""), checkOdd)
+		_, err := c.Process(context.Background(), 1)
+
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected pipz error, got %v", err)
+		}
+		if pipeErr.Path[0].Name() != "collect" || pipeErr.InputData != 1 {
+			t.Errorf("unexpected error: path %v input %d", pipeErr.Path, pipeErr.InputData)
+		}
+	})
+
+	t.Run("Canceled Context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		c := NewCollectErrors(NewIdentity("collect", ""), double)
+		result, err := c.Process(ctx, 4)
+		if result != 4 {
+			t.Errorf("expected input returned, got %d", result)
+		}
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.Canceled {
+			t.Errorf("expected canceled error, got %v", err)
+		}
+	})
+
+	t.Run("Emits Signal", func(t *testing.T) {
+		var count int
+		listener := capitan.Hook(SignalCollectErrorsFailed, func(_ context.Context, e *capitan.Event) {
+			count, _ = FieldErrorCount.From(e)
+		})
+		defer listener.Close()
+
+		_, _ = NewCollectErrors(NewIdentity("collect", ""), checkOdd, checkBig).Process(context.Background(), 11) //nolint:errcheck // signal checked below
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("expected error_count 2, got %d", count)
+		}
+	})
+
+	t.Run("Modification", func(t *testing.T) {
+		c := NewCollectErrors[int](NewIdentity("collect", "")).Add(double).Add(checkOdd)
+		if c.Len() != 2 {
+			t.Errorf("expected 2 processors, got %d", c.Len())
+		}
+		if err := c.Remove(5); !errors.Is(err, ErrIndexOutOfBounds) {
+			t.Errorf("expected ErrIndexOutOfBounds, got %v", err)
+		}
+		if err := c.Remove(0); err != nil || c.Len() != 1 {
+			t.Errorf("expected removal, got %v (len %d)", err, c.Len())
+		}
+		c.SetProcessors(double, double, double)
+		if result, _ := c.Process(context.Background(), 1); result != 8 { //nolint:errcheck // no failures possible
+			t.Errorf("expected 8, got %d", result)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		c := NewCollectErrors(NewIdentity("collect", ""), checkOdd, checkBig)
+		node := c.Schema()
+		flow, ok := CollectErrorsKey.From(node)
+		if !ok || node.Type != "collecterrors" {
+			t.Fatalf("expected collecterrors flow, got %s", node.Type)
+		}
+		if len(flow.Steps) != 2 {
+			t.Errorf("expected 2 steps, got %d", len(flow.Steps))
+		}
+		if NewSchema(node).Count() != 3 {
+			t.Errorf("expected schema walk to visit steps")
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		c := NewCollectErrors(NewIdentity("collect", ""), double)
+		if err := c.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+		if err := c.Close(); err != nil {
+			t.Errorf("expected idempotent close, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package pipz
+
+import (
+	"container/list"
+	"context"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// Reasons a keyed connector evicts a key.
+const (
+	// EvictExpired marks keys not written within the connector's TTL.
+	EvictExpired = "expired"
+	// EvictCapacity marks the least recently written keys, dropped to
+	// stay within the connector's key limit.
+	EvictCapacity = "capacity"
+)
+
+// KeyStats describes the per-key state held by a keyed connector, such as
+// ChangeLimit or Quarantine.
+type KeyStats struct {
+	// Keys is the number of keys currently held.
+	Keys int `json:"keys"`
+	// Expired is the number of keys evicted for outliving the TTL.
+	Expired int64 `json:"expired"`
+	// Displaced is the number of keys evicted to stay within the key limit.
+	Displaced int64 `json:"displaced"`
+}
+
+// keyedEntry is the state of one key.
+type keyedEntry[V any] struct {
+	written time.Time
+	value   V
+	key     string
+}
+
+// keyedState holds per-key state bounded by a TTL and a key limit. Keys
+// are kept in the order they were last written, so both expired keys and
+// the least recently written ones are found at the back in constant time.
+// It is not safe for concurrent use; callers hold their own mutex.
+type keyedState[V any] struct {
+	entries map[string]*list.Element
+	order   *list.List
+	stats   KeyStats
+	ttl     time.Duration
+	maxKeys int
+}
+
+func newKeyedState[V any]() *keyedState[V] {
+	return &keyedState[V]{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the value of key, unless it was last written more than the
+// TTL before now. Expired keys are left for compact to evict.
+func (s *keyedState[V]) get(key string, now time.Time) (V, bool) {
+	var zero V
+	el, ok := s.entries[key]
+	if !ok || s.expired(el.Value.(*keyedEntry[V]), now) {
+		return zero, false
+	}
+	return el.Value.(*keyedEntry[V]).value, true
+}
+
+// put writes the value of key, evicting expired keys and then the least
+// recently written keys beyond the key limit. It returns the number of
+// keys evicted for each reason.
+func (s *keyedState[V]) put(key string, value V, now time.Time) (expired, displaced int) {
+	if el, ok := s.entries[key]; ok {
+		entry := el.Value.(*keyedEntry[V])
+		entry.value, entry.written = value, now
+		s.order.MoveToFront(el)
+	} else {
+		s.entries[key] = s.order.PushFront(&keyedEntry[V]{key: key, value: value, written: now})
+	}
+	return s.compact(now)
+}
+
+// remove drops key, reporting whether it was held.
+func (s *keyedState[V]) remove(key string) bool {
+	el, ok := s.entries[key]
+	if ok {
+		s.evict(el)
+	}
+	return ok
+}
+
+// clear drops every key without counting them as evicted.
+func (s *keyedState[V]) clear() {
+	clear(s.entries)
+	s.order.Init()
+}
+
+// compact evicts every key last written more than the TTL before now,
+// then the least recently written keys beyond the key limit. It returns
+// the number evicted for each reason.
+func (s *keyedState[V]) compact(now time.Time) (expired, displaced int) {
+	for el := s.order.Back(); el != nil && s.expired(el.Value.(*keyedEntry[V]), now); el = s.order.Back() {
+		s.evict(el)
+		expired++
+	}
+	for s.maxKeys > 0 && s.order.Len() > s.maxKeys {
+		s.evict(s.order.Back())
+		displaced++
+	}
+	s.stats.Expired += int64(expired)
+	s.stats.Displaced += int64(displaced)
+	return expired, displaced
+}
+
+func (s *keyedState[V]) expired(entry *keyedEntry[V], now time.Time) bool {
+	return s.ttl > 0 && now.Sub(entry.written) > s.ttl
+}
+
+func (s *keyedState[V]) evict(el *list.Element) {
+	delete(s.entries, el.Value.(*keyedEntry[V]).key)
+	s.order.Remove(el)
+}
+
+// keyStats returns the state's counters.
+func (s *keyedState[V]) keyStats() KeyStats {
+	stats := s.stats
+	stats.Keys = s.order.Len()
+	return stats
+}
+
+// emitEvicted reports keys a keyed connector evicted, if any.
+func emitEvicted(ctx context.Context, identity Identity, expired, displaced, remaining int) {
+	for _, e := range []struct {
+		reason string
+		n      int
+	}{{EvictExpired, expired}, {EvictCapacity, displaced}} {
+		if e.n == 0 {
+			continue
+		}
+		capitan.Debug(ctx, SignalKeysEvicted,
+			FieldName.Field(identity.Name()),
+			FieldIdentityID.Field(identity.ID().String()),
+			FieldEvicted.Field(e.n),
+			FieldKeys.Field(remaining),
+			FieldReason.Field(e.reason),
+		)
+	}
+}
+
+// compactor runs a compaction function in the background on an interval.
+type compactor struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startCompactor calls compact every interval until the compactor is
+// stopped.
+func startCompactor(clock clockz.Clock, interval time.Duration, compact func()) *compactor {
+	c := &compactor{stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(c.done)
+		for {
+			select {
+			case <-clock.After(interval):
+				compact()
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// Stop stops the compactor and waits for a running compaction to finish.
+// It must not be called with a lock compact takes. A nil compactor is
+// already stopped.
+func (c *compactor) Stop() {
+	if c == nil {
+		return
+	}
+	close(c.stop)
+	<-c.done
+}
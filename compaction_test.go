@@ -0,0 +1,193 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+func TestKeyedState(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	t.Run("Evicts Least Recently Written", func(t *testing.T) {
+		s := newKeyedState[int]()
+		s.maxKeys = 2
+		s.put("a", 1, now)
+		s.put("b", 2, now)
+		s.put("a", 3, now) // a is now the most recent
+		if expired, displaced := s.put("c", 4, now); expired != 0 || displaced != 1 {
+			t.Errorf("expected 1 displaced key, got %d expired and %d displaced", expired, displaced)
+		}
+		if _, ok := s.get("b", now); ok {
+			t.Error("expected b to be displaced")
+		}
+		if v, ok := s.get("a", now); !ok || v != 3 {
+			t.Errorf("expected a to be kept with 3, got %d, %v", v, ok)
+		}
+	})
+
+	t.Run("Expires After TTL", func(t *testing.T) {
+		s := newKeyedState[int]()
+		s.ttl = time.Minute
+		s.put("a", 1, now)
+		s.put("b", 2, now.Add(30*time.Second))
+		later := now.Add(80 * time.Second)
+		if _, ok := s.get("a", later); ok {
+			t.Error("expected a to have expired")
+		}
+		if _, ok := s.get("b", later); !ok {
+			t.Error("expected b to be live")
+		}
+		if expired, _ := s.compact(later); expired != 1 {
+			t.Errorf("expected 1 expired key, got %d", expired)
+		}
+		stats := s.keyStats()
+		if stats.Keys != 1 || stats.Expired != 1 || stats.Displaced != 0 {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		s := newKeyedState[int]()
+		s.put("a", 1, now)
+		if !s.remove("a") || s.remove("a") {
+			t.Error("expected remove to report whether the key was held")
+		}
+	})
+}
+
+func TestKeyedConnectorCompaction(t *testing.T) {
+	waitForKeys := func(t *testing.T, stats func() KeyStats, keys int) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for stats().Keys != keys {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d keys, got %+v", keys, stats())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	t.Run("ChangeLimit Max Keys", func(t *testing.T) {
+		limit := NewChangeLimit(testIdentity("limit"),
+			func(_ context.Context, v int) string { return strconv.Itoa(v) },
+			func(v int) float64 { return float64(v) },
+			1,
+		).SetMaxKeys(3)
+		for v := range 10 {
+			if _, err := limit.Process(context.Background(), v); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		stats := limit.KeyStats()
+		if stats.Keys != 3 || stats.Displaced != 7 {
+			t.Errorf("expected 3 keys and 7 displaced, got %+v", stats)
+		}
+		if _, ok := limit.Last("9"); !ok {
+			t.Error("expected the most recent key to be kept")
+		}
+	})
+
+	t.Run("ChangeLimit Background Compaction", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		limit := NewChangeLimit(testIdentity("limit"),
+			func(_ context.Context, v int) string { return strconv.Itoa(v) },
+			func(v int) float64 { return float64(v) },
+			1,
+		).WithClock(clock).SetTTL(time.Minute).SetCompaction(10 * time.Second)
+		defer limit.Close()
+
+		for v := range 5 {
+			limit.Process(context.Background(), v) //nolint:errcheck
+		}
+		// Each step waits for the compactor to re-arm, so every tick runs.
+		for range 6 {
+			advanceWhenWaiting(t, clock, 10*time.Second)
+		}
+		if keys := limit.KeyStats().Keys; keys != 5 {
+			t.Errorf("expected no keys compacted within the TTL, got %d", keys)
+		}
+		limit.Process(context.Background(), 5) //nolint:errcheck
+		limit.Process(context.Background(), 6) //nolint:errcheck
+
+		advanceWhenWaiting(t, clock, 10*time.Second)
+		waitForKeys(t, limit.KeyStats, 2)
+		for range 6 {
+			advanceWhenWaiting(t, clock, 10*time.Second)
+		}
+		waitForKeys(t, limit.KeyStats, 0)
+		if limit.KeyStats().Expired != 7 {
+			t.Errorf("expected 7 expired keys, got %+v", limit.KeyStats())
+		}
+	})
+
+	t.Run("Close Stops Compaction", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		limit := NewChangeLimit(testIdentity("limit"),
+			func(_ context.Context, v int) string { return strconv.Itoa(v) },
+			func(v int) float64 { return float64(v) },
+			1,
+		).WithClock(clock).SetTTL(time.Minute).SetCompaction(10 * time.Second)
+		limit.Process(context.Background(), 1) //nolint:errcheck
+		waitForWaiters(t, clock)
+
+		if err := limit.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := limit.Close(); err != nil {
+			t.Fatalf("unexpected error on second close: %v", err)
+		}
+		clock.Advance(2 * time.Minute)
+		clock.BlockUntilReady()
+		time.Sleep(5 * time.Millisecond)
+		if limit.KeyStats().Keys != 1 {
+			t.Errorf("expected no compaction after Close, got %+v", limit.KeyStats())
+		}
+	})
+
+	t.Run("Quarantine Max Keys And Compaction", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		failing := Apply(testIdentity("fail"), func(_ context.Context, v int) (int, error) {
+			return v, errors.New("poison")
+		})
+		guard := NewQuarantine(testIdentity("guard"), 3, failing, Transform(testIdentity("sink"), func(_ context.Context, v int) int { return v })).
+			SetKeyFunc(func(_ context.Context, v int) string { return strconv.Itoa(v) }).
+			SetMaxKeys(2).
+			SetWindow(time.Hour).
+			WithClock(clock).
+			SetCompaction(time.Minute)
+		defer guard.Close()
+
+		for v := range 4 {
+			guard.Process(context.Background(), v) //nolint:errcheck
+		}
+		if stats := guard.KeyStats(); stats.Keys != 2 || stats.Displaced != 2 {
+			t.Errorf("expected 2 keys and 2 displaced, got %+v", stats)
+		}
+		if guard.Failures("0") != 0 || guard.Failures("3") != 1 {
+			t.Errorf("expected oldest key forgotten and newest kept")
+		}
+
+		advanceWhenWaiting(t, clock, time.Hour+time.Second)
+		waitForKeys(t, guard.KeyStats, 0)
+	})
+
+	t.Run("Compact", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		limit := NewChangeLimit(testIdentity("limit"),
+			func(_ context.Context, v int) string { return strconv.Itoa(v) },
+			func(v int) float64 { return float64(v) },
+			1,
+		).WithClock(clock).SetTTL(time.Minute)
+		limit.Process(context.Background(), 1) //nolint:errcheck
+		limit.Process(context.Background(), 2) //nolint:errcheck
+		clock.Advance(2 * time.Minute)
+		if n := limit.Compact(); n != 2 {
+			t.Errorf("expected 2 evicted, got %d", n)
+		}
+	})
+}
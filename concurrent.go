@@ -16,7 +16,8 @@ import (
 // receives a deep copy of the input, ensuring complete isolation.
 //
 // Concurrent supports two modes:
-//   - Without reducer (nil): Returns the original input unchanged after all processors complete
+//   - Without reducer (nil): Returns the original input unchanged after all processors complete,
+//     or, if T implements Mergeable, the input merged with each successful result
 //   - With reducer: Collects all results and errors, then calls the reducer function to produce the final output
 //
 // The input type T must implement the Cloner[T] interface to provide efficient,
@@ -44,6 +45,7 @@ import (
 //   - Preserves trace context and spans for distributed tracing
 //   - Waits for all processors to complete
 //   - Reducer receives map[string]T for results and map[string]error for errors (keyed by processor name)
+//   - WithMaxConcurrency bounds how many processors run at once; the rest wait their turn
 //
 // Example without reducer (side effects):
 //
@@ -106,6 +108,7 @@ type Concurrent[T Cloner[T]] struct {
 	identity   Identity
 	processors []Chainable[T]
 	reducer    func(original T, results map[Identity]T, errors map[Identity]error) T
+	limit      int
 	mu         sync.RWMutex
 	closeOnce  sync.Once
 	closeErr   error
@@ -127,25 +130,33 @@ func NewConcurrent[T Cloner[T]](identity Identity, reducer func(original T, resu
 func (c *Concurrent[T]) Process(ctx context.Context, input T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, c.identity, input)
 
+	ctx, guardErr := enterDepth(ctx, c, c.identity, input)
+	if guardErr != nil {
+		return input, guardErr
+	}
+
 	start := time.Now()
 
 	c.mu.RLock()
 	processors := make([]Chainable[T], len(c.processors))
 	copy(processors, c.processors)
+	limit := c.limit
 	c.mu.RUnlock()
 
 	if len(processors) == 0 {
 		return input, nil
 	}
 
+	before, checkIsolated := isolationSnapshot(ctx, input)
+
 	var wg sync.WaitGroup
-	wg.Add(len(processors))
 
-	// Collect results if reducer is provided
+	// Collect results if a reducer or Merge will fold them
+	collect := c.reducer != nil || isMergeable(input)
 	var resultsMu sync.Mutex
 	var results map[Identity]T
 	var errs map[Identity]error
-	if c.reducer != nil {
+	if collect {
 		results = make(map[Identity]T, len(processors))
 		errs = make(map[Identity]error, len(processors))
 	}
@@ -153,20 +164,41 @@ func (c *Concurrent[T]) Process(ctx context.Context, input T) (result T, err err
 	// Track error count for signal (atomic for safe concurrent access)
 	var errorCount atomic.Int32
 
+	// Bound parallelism by the configured limit or the active policy,
+	// whichever is tighter
+	if capped := allowedConcurrency(ctx, c.identity, len(processors)); capped > 0 && (limit <= 0 || capped < limit) {
+		limit = capped
+	}
+	var sem chan struct{}
+	if limit > 0 && limit < len(processors) {
+		sem = make(chan struct{}, limit)
+	}
+
 	// Process all with the original context to preserve tracing
 	for _, processor := range processors {
+		if sem != nil {
+			// Wait for a free slot; stop launching once the context is done
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		wg.Add(1)
 		go func(p Chainable[T]) {
+			if sem != nil {
+				defer func() { <-sem }()
+			}
 			defer func() {
 				// Always call wg.Done() even if Clone() or Process() panics
 				// This prevents deadlock in wg.Wait()
 				if r := recover(); r != nil {
 					// Record panic as an error so the reducer can see it
-					if c.reducer != nil {
+					if collect {
 						resultsMu.Lock()
-						errs[p.Identity()] = &panicError{
-							identity:  p.Identity(),
-							sanitized: sanitizePanicMessage(r),
-						}
+						errs[p.Identity()] = newPanicError(p.Identity(), r)
 						errorCount.Add(1)
 						resultsMu.Unlock()
 					} else {
@@ -176,14 +208,14 @@ func (c *Concurrent[T]) Process(ctx context.Context, input T) (result T, err err
 				wg.Done()
 			}()
 
-			// Create an isolated copy using the Clone method
-			inputCopy := input.Clone()
+			// Create an isolated copy unless the branch is read-only
+			inputCopy := branchInput(p, input)
 
 			// Process with the context
 			res, err := p.Process(ctx, inputCopy)
 
-			// Collect results if reducer is provided
-			if c.reducer != nil {
+			// Collect results if a reducer or Merge will fold them
+			if collect {
 				resultsMu.Lock()
 				if err != nil {
 					errs[p.Identity()] = err
@@ -216,10 +248,16 @@ func (c *Concurrent[T]) Process(ctx context.Context, input T) (result T, err err
 			FieldDuration.Field(time.Since(start).Seconds()),
 		)
 
+		if checkIsolated {
+			if isoErr := checkIsolation(ctx, c.identity, input, before); isoErr != nil {
+				return input, isoErr
+			}
+		}
+
 		if c.reducer != nil {
 			return c.reducer(input, results, errs), nil
 		}
-		return input, nil
+		return mergeBranches(input, processors, results), nil
 	case <-ctx.Done():
 		// Context canceled - emit signal with current state
 		capitan.Info(ctx, SignalConcurrentCompleted,
@@ -230,7 +268,7 @@ func (c *Concurrent[T]) Process(ctx context.Context, input T) (result T, err err
 			FieldDuration.Field(time.Since(start).Seconds()),
 		)
 
-		if c.reducer != nil {
+		if collect {
 			// Copy maps while holding lock - goroutines may still be writing
 			resultsMu.Lock()
 			resultsCopy := make(map[Identity]T, len(results))
@@ -243,14 +281,29 @@ func (c *Concurrent[T]) Process(ctx context.Context, input T) (result T, err err
 			}
 			resultsMu.Unlock()
 
-			return c.reducer(input, resultsCopy, errsCopy), nil
+			if c.reducer != nil {
+				return c.reducer(input, resultsCopy, errsCopy), nil
+			}
+			return mergeBranches(input, processors, resultsCopy), nil
 		}
 		return input, nil
 	}
 }
 
+// WithMaxConcurrency caps the number of processors running at once, so a
+// large fan-out does not hit its targets all together. Processors beyond the
+// limit start as earlier ones finish, in order; once the context is done no
+// further processors are started. Zero or negative means no limit.
+func (c *Concurrent[T]) WithMaxConcurrency(n int) *Concurrent[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limit = n
+	return c
+}
+
 // Add appends a processor to the concurrent execution list.
 func (c *Concurrent[T]) Add(processor Chainable[T]) *Concurrent[T] {
+	noteChildren(c.identity, processor)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.processors = append(c.processors, processor)
@@ -287,6 +340,7 @@ func (c *Concurrent[T]) Clear() *Concurrent[T] {
 
 // SetProcessors replaces all processors atomically.
 func (c *Concurrent[T]) SetProcessors(processors ...Chainable[T]) *Concurrent[T] {
+	noteChildren(c.identity, processors...)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.processors = make([]Chainable[T], len(processors))
@@ -315,6 +369,9 @@ func (c *Concurrent[T]) Schema() Node {
 		Identity: c.identity,
 		Type:     "concurrent",
 		Flow:     ConcurrentFlow{Tasks: tasks},
+		Metadata: map[string]any{
+			"max_concurrency": c.limit,
+		},
 	}
 }
 
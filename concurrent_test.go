@@ -469,6 +469,40 @@ func TestConcurrent(t *testing.T) {
 		}
 	})
 
+	t.Run("Max Concurrency Bounds Parallelism", func(t *testing.T) {
+		var running, peak, total atomic.Int32
+		track := Effect(NewIdentity("track", ""), func(_ context.Context, _ TestData) error {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			total.Add(1)
+			return nil
+		})
+		processors := make([]Chainable[TestData], 10)
+		for i := range processors {
+			processors[i] = track
+		}
+
+		concurrent := NewConcurrent(NewIdentity("bounded", ""), nil, processors...).WithMaxConcurrency(3)
+		if _, err := concurrent.Process(context.Background(), TestData{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if total.Load() != 10 {
+			t.Errorf("expected all 10 processors to run, got %d", total.Load())
+		}
+		if peak.Load() > 3 {
+			t.Errorf("expected at most 3 running at once, got %d", peak.Load())
+		}
+		if got := concurrent.Schema().Metadata["max_concurrency"]; got != 3 {
+			t.Errorf("expected max_concurrency metadata 3, got %v", got)
+		}
+	})
 }
 
 func TestConcurrentClose(t *testing.T) {
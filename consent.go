@@ -0,0 +1,247 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrNoConsent is returned by a Consent connector in ConsentDrop mode when the
+// data subject has not consented to the declared purpose.
+var ErrNoConsent = errors.New("no consent for purpose")
+
+// ConsentFunc decides whether a data subject has consented to processing
+// for a purpose. Implementations typically consult a consent store or
+// preference center and should be fast; they are called for every record.
+type ConsentFunc func(ctx context.Context, subjectID, purpose string) bool
+
+// ConsentMode determines how records lacking consent are handled.
+type ConsentMode string
+
+// Consent handling modes.
+const (
+	// ConsentDrop suppresses the record by failing with ErrNoConsent.
+	ConsentDrop ConsentMode = "drop"
+	// ConsentAnonymize strips identifying data with the configured
+	// anonymizer, then processes the anonymized record.
+	ConsentAnonymize ConsentMode = "anonymize"
+)
+
+// ParseConsentMode returns the consent handling mode named s. Unknown names
+// return an error wrapping ErrInvalidMode that lists the valid names.
+func ParseConsentMode(s string) (ConsentMode, error) {
+	return parseMode("consent", s, ConsentDrop, ConsentAnonymize)
+}
+
+// Consent enforces data-subject consent before a processor runs.
+// Each record's subject is extracted and checked against the pipeline's
+// declared purpose (e.g. "marketing", "analytics"). Records with consent
+// are processed normally. Records without consent are either dropped,
+// surfacing ErrNoConsent so the caller can discard them, or anonymized
+// and then processed, depending on the mode.
+//
+// Suppressed records are counted (see Suppressed and Anonymized) and emit a
+// consent.suppressed signal carrying the purpose and mode, never the subject.
+//
+// Example:
+//
+//	var MarketingConsentID = pipz.NewIdentity("marketing-consent", "Requires marketing consent")
+//	consent := pipz.NewConsent(MarketingConsentID, "marketing",
+//	    func(e Event) string { return e.UserID },
+//	    preferences.HasConsent,
+//	    marketingPipeline,
+//	).SetAnonymizer(func(e Event) Event {
+//	    e.UserID, e.Email = "", ""
+//	    return e
+//	})
+type Consent[T any] struct {
+	processor  Chainable[T]
+	subject    func(T) string
+	decide     ConsentFunc
+	anonymizer func(T) T
+	identity   Identity
+	purpose    string
+	mode       ConsentMode
+	suppressed atomic.Int64
+	anonymized atomic.Int64
+	mu         sync.RWMutex
+	closeOnce  sync.Once
+	closeErr   error
+}
+
+// NewConsent creates a Consent connector in ConsentDrop mode.
+// subject extracts the data subject ID from each record.
+func NewConsent[T any](identity Identity, purpose string, subject func(T) string, decide ConsentFunc, processor Chainable[T]) *Consent[T] {
+	return &Consent[T]{
+		identity:  identity,
+		purpose:   purpose,
+		subject:   subject,
+		decide:    decide,
+		processor: processor,
+		mode:      ConsentDrop,
+	}
+}
+
+// Process implements the Chainable interface.
+func (c *Consent[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, c.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, c, c.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	c.mu.RLock()
+	processor := c.processor
+	subject := c.subject
+	decide := c.decide
+	anonymizer := c.anonymizer
+	purpose := c.purpose
+	mode := c.mode
+	c.mu.RUnlock()
+
+	input := data
+	if !decide(ctx, subject(data), purpose) {
+		capitan.Info(ctx, SignalConsentSuppressed,
+			FieldName.Field(c.identity.Name()),
+			FieldIdentityID.Field(c.identity.ID().String()),
+			FieldPurpose.Field(purpose),
+			FieldMode.Field(string(mode)),
+		)
+		if mode != ConsentAnonymize || anonymizer == nil {
+			c.suppressed.Add(1)
+			var zero T
+			return zero, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(data),
+				Err:       fmt.Errorf("%w %q", ErrNoConsent, purpose),
+				Path:      []Identity{c.identity},
+			}
+		}
+		c.anonymized.Add(1)
+		input = anonymizer(data)
+	}
+
+	result, err = processor.Process(ctx, input)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, c.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(input),
+			Err:       err,
+			Path:      []Identity{c.identity},
+		}
+	}
+	return result, nil
+}
+
+// SetAnonymizer sets the function used to strip identifying data and
+// switches the connector to ConsentAnonymize mode.
+func (c *Consent[T]) SetAnonymizer(anonymizer func(T) T) *Consent[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.anonymizer = anonymizer
+	c.mode = ConsentAnonymize
+	return c
+}
+
+// SetMode sets how records lacking consent are handled.
+// ConsentAnonymize without an anonymizer behaves as ConsentDrop.
+func (c *Consent[T]) SetMode(mode ConsentMode) *Consent[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mode = mode
+	return c
+}
+
+// SetPurpose updates the declared processing purpose.
+func (c *Consent[T]) SetPurpose(purpose string) *Consent[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.purpose = purpose
+	return c
+}
+
+// SetDecider updates the consent decision function.
+func (c *Consent[T]) SetDecider(decide ConsentFunc) *Consent[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.decide = decide
+	return c
+}
+
+// SetProcessor updates the processor run for consented records.
+func (c *Consent[T]) SetProcessor(processor Chainable[T]) *Consent[T] {
+	noteChildren(c.identity, processor)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.processor = processor
+	return c
+}
+
+// Purpose returns the declared processing purpose.
+func (c *Consent[T]) Purpose() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.purpose
+}
+
+// Mode returns the current consent handling mode.
+func (c *Consent[T]) Mode() ConsentMode {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.mode
+}
+
+// Suppressed returns the number of records dropped for lack of consent.
+func (c *Consent[T]) Suppressed() int64 {
+	return c.suppressed.Load()
+}
+
+// Anonymized returns the number of records anonymized for lack of consent.
+func (c *Consent[T]) Anonymized() int64 {
+	return c.anonymized.Load()
+}
+
+// Identity returns the identity of this connector.
+func (c *Consent[T]) Identity() Identity {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (c *Consent[T]) Schema() Node {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Node{
+		Identity: c.identity,
+		Type:     "consent",
+		Flow:     ConsentFlow{Processor: c.processor.Schema()},
+		Metadata: map[string]any{
+			"purpose": c.purpose,
+			"mode":    string(c.mode),
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (c *Consent[T]) Close() error {
+	c.closeOnce.Do(func() {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		c.closeErr = c.processor.Close()
+	})
+	return c.closeErr
+}
@@ -0,0 +1,148 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+type consentRecord struct {
+	Subject string
+	Email   string
+	Value   int
+}
+
+func TestConsent(t *testing.T) {
+	granted := map[string]bool{"alice": true}
+	decide := func(_ context.Context, subject, purpose string) bool {
+		return purpose == "analytics" && granted[subject]
+	}
+	subject := func(r consentRecord) string { return r.Subject }
+	double := Transform(NewIdentity("double", ""), func(_ context.Context, r consentRecord) consentRecord {
+		r.Value *= 2
+		return r
+	})
+
+	t.Run("Consented Records Are Processed", func(t *testing.T) {
+		c := NewConsent(NewIdentity("consent", ""), "analytics", subject, decide, double)
+		result, err := c.Process(context.Background(), consentRecord{Subject: "alice", Value: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Value != 4 {
+			t.Errorf("expected 4, got %d", result.Value)
+		}
+		if c.Suppressed() != 0 {
+			t.Errorf("expected no suppressions, got %d", c.Suppressed())
+		}
+	})
+
+	t.Run("Drop Without Consent", func(t *testing.T) {
+		c := NewConsent(NewIdentity("consent", ""), "analytics", subject, decide, double)
+		_, err := c.Process(context.Background(), consentRecord{Subject: "bob", Value: 2})
+		if !errors.Is(err, ErrNoConsent) {
+			t.Fatalf("expected ErrNoConsent, got %v", err)
+		}
+		var pipeErr *Error[consentRecord]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "consent" {
+			t.Errorf("expected path to start with consent, got %v", err)
+		}
+		if c.Suppressed() != 1 {
+			t.Errorf("expected 1 suppression, got %d", c.Suppressed())
+		}
+	})
+
+	t.Run("Purpose Is Checked", func(t *testing.T) {
+		c := NewConsent(NewIdentity("consent", ""), "marketing", subject, decide, double)
+		if _, err := c.Process(context.Background(), consentRecord{Subject: "alice"}); !errors.Is(err, ErrNoConsent) {
+			t.Errorf("expected ErrNoConsent for undeclared purpose, got %v", err)
+		}
+		c.SetPurpose("analytics")
+		if _, err := c.Process(context.Background(), consentRecord{Subject: "alice"}); err != nil {
+			t.Errorf("unexpected error after purpose change: %v", err)
+		}
+	})
+
+	t.Run("Anonymize Without Consent", func(t *testing.T) {
+		c := NewConsent(NewIdentity("consent", ""), "analytics", subject, decide, double).
+			SetAnonymizer(func(r consentRecord) consentRecord {
+				r.Subject, r.Email = "", ""
+				return r
+			})
+		if c.Mode() != ConsentAnonymize {
+			t.Errorf("expected anonymize mode, got %s", c.Mode())
+		}
+		result, err := c.Process(context.Background(), consentRecord{Subject: "bob", Email: "bob@example.com", Value: 3})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Subject != "" || result.Email != "" || result.Value != 6 {
+			t.Errorf("expected anonymized, processed record, got %+v", result)
+		}
+		if c.Anonymized() != 1 || c.Suppressed() != 0 {
+			t.Errorf("expected 1 anonymized and 0 suppressed, got %d and %d", c.Anonymized(), c.Suppressed())
+		}
+	})
+
+	t.Run("Anonymize Mode Without Anonymizer Drops", func(t *testing.T) {
+		c := NewConsent(NewIdentity("consent", ""), "analytics", subject, decide, double).SetMode(ConsentAnonymize)
+		if _, err := c.Process(context.Background(), consentRecord{Subject: "bob"}); !errors.Is(err, ErrNoConsent) {
+			t.Errorf("expected ErrNoConsent, got %v", err)
+		}
+	})
+
+	t.Run("Child Error Path", func(t *testing.T) {
+		failing := Apply(NewIdentity("fail", ""), func(_ context.Context, r consentRecord) (consentRecord, error) {
+			return r, errors.New("boom")
+		})
+		c := NewConsent(NewIdentity("consent", ""), "analytics", subject, decide, failing)
+		_, err := c.Process(context.Background(), consentRecord{Subject: "alice"})
+		var pipeErr *Error[consentRecord]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected pipz error, got %v", err)
+		}
+		if len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "consent" || pipeErr.Path[1].Name() != "fail" {
+			t.Errorf("unexpected path: %v", pipeErr.Path)
+		}
+	})
+
+	t.Run("Emits Signal", func(t *testing.T) {
+		var purpose, mode string
+		listener := capitan.Hook(SignalConsentSuppressed, func(_ context.Context, e *capitan.Event) {
+			purpose, _ = FieldPurpose.From(e)
+			mode, _ = FieldMode.From(e)
+		})
+		defer listener.Close()
+
+		c := NewConsent(NewIdentity("consent", ""), "analytics", subject, decide, double)
+		_, _ = c.Process(context.Background(), consentRecord{Subject: "bob"}) //nolint:errcheck // drop expected
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if purpose != "analytics" || mode != "drop" {
+			t.Errorf("unexpected signal fields: purpose=%q mode=%q", purpose, mode)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		c := NewConsent(NewIdentity("consent", ""), "analytics", subject, decide, double)
+		schema := c.Schema()
+		if schema.Type != "consent" || schema.Metadata["purpose"] != "analytics" {
+			t.Errorf("unexpected schema: %+v", schema)
+		}
+		flow, ok := ConsentKey.From(schema)
+		if !ok || flow.Processor.Identity.Name() != "double" {
+			t.Errorf("expected consent flow with double processor, got %+v", schema.Flow)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		c := NewConsent(NewIdentity("consent", ""), "analytics", subject, decide, double)
+		if err := c.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ContentHashStore records the content hash each item had when it was last
+// processed, typically backed by a column next to the synced records. It
+// must outlive the process for unchanged items to be skipped across runs.
+type ContentHashStore interface {
+	// Hash returns the hash recorded for the item key, if any.
+	Hash(ctx context.Context, key string) (hash string, ok bool, err error)
+	// Record records hash as the hash of the item key.
+	Record(ctx context.Context, key, hash string) error
+}
+
+// ETag returns a stable hash of v's content as a hex string, suitable as an
+// HTTP ETag or a ContentHash fingerprint. It is DeepHash of v, so maps hash
+// independently of their order; pass only the fields that matter, as
+// functions and channels hash by identity and differ between processes.
+func ETag(v any) string {
+	return strconv.FormatUint(DeepHash(v), 16)
+}
+
+// ContentHash skips processing of items whose content has not changed
+// since they were last processed, cutting the no-op updates that dominate
+// sync pipelines. It hashes the fields the fields function selects with
+// ETag and compares the hash with the one recorded for the item's key: an
+// unchanged item is returned as is without running processor, while a
+// changed or new item is processed and, on success, its hash recorded.
+// Failed items record nothing, so they are retried in full on the next run.
+//
+// Select only the fields the processor depends on, leaving out timestamps
+// and other values that change without the content changing. SetAttach
+// stores the hash on the item, such as for an ETag header.
+//
+// Example:
+//
+//	var SyncProductID = pipz.NewIdentity("sync-product", "Pushes changed products to the catalog")
+//	sync := pipz.NewContentHash(SyncProductID, pushToCatalog, hashStore,
+//	    func(p Product) string { return p.SKU },
+//	    func(p Product) any { return []any{p.Title, p.Price, p.Images} },
+//	)
+type ContentHash[T any] struct {
+	processor Chainable[T]
+	store     ContentHashStore
+	key       func(T) string
+	fields    func(T) any
+	attach    func(T, string) T
+	identity  Identity
+	unchanged atomic.Int64
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewContentHash creates a ContentHash running processor for items whose
+// fields, as selected by fields, changed since the hash recorded in store
+// under the item's key.
+func NewContentHash[T any](identity Identity, processor Chainable[T], store ContentHashStore, key func(T) string, fields func(T) any) *ContentHash[T] {
+	return &ContentHash[T]{
+		identity:  identity,
+		processor: processor,
+		store:     store,
+		key:       key,
+		fields:    fields,
+	}
+}
+
+// Process implements the Chainable interface.
+func (c *ContentHash[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, c.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, c, c.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	c.mu.RLock()
+	processor := c.processor
+	store := c.store
+	keyFunc := c.key
+	fields := c.fields
+	attach := c.attach
+	c.mu.RUnlock()
+
+	key := keyFunc(data)
+	hash := ETag(fields(data))
+	if attach != nil {
+		data = attach(data, hash)
+	}
+
+	previous, ok, err := store.Hash(ctx, key)
+	if err != nil {
+		return data, c.fail(data, fmt.Errorf("looking up content hash: %w", err))
+	}
+	if ok && previous == hash {
+		c.unchanged.Add(1)
+		capitan.Debug(ctx, SignalContentUnchanged,
+			FieldName.Field(c.identity.Name()),
+			FieldIdentityID.Field(c.identity.ID().String()),
+			FieldItemKey.Field(key),
+		)
+		return data, nil
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		if isControl(err) {
+			return result, err
+		}
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, c.identity)
+			return result, pipeErr
+		}
+		return result, c.fail(data, err)
+	}
+
+	if err := store.Record(ctx, key, hash); err != nil {
+		return result, c.fail(data, fmt.Errorf("recording content hash: %w", err))
+	}
+	return result, nil
+}
+
+// fail wraps err in an Error attributed to this connector.
+func (c *ContentHash[T]) fail(data T, err error) *Error[T] {
+	return &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(data),
+		Err:       err,
+		Path:      []Identity{c.identity},
+	}
+}
+
+// SetAttach sets a function storing the item's content hash on it before
+// it is compared or processed.
+func (c *ContentHash[T]) SetAttach(attach func(T, string) T) *ContentHash[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attach = attach
+	return c
+}
+
+// SetStore updates the content hash store.
+func (c *ContentHash[T]) SetStore(store ContentHashStore) *ContentHash[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store = store
+	return c
+}
+
+// SetProcessor updates the processor run for changed items.
+func (c *ContentHash[T]) SetProcessor(processor Chainable[T]) *ContentHash[T] {
+	noteChildren(c.identity, processor)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.processor = processor
+	return c
+}
+
+// Unchanged returns the number of items skipped as unchanged.
+func (c *ContentHash[T]) Unchanged() int64 {
+	return c.unchanged.Load()
+}
+
+// Identity returns the identity of this connector.
+func (c *ContentHash[T]) Identity() Identity {
+	return c.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (c *ContentHash[T]) Schema() Node {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Node{
+		Identity: c.identity,
+		Type:     "contenthash",
+		Flow:     ContentHashFlow{Processor: c.processor.Schema()},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (c *ContentHash[T]) Close() error {
+	c.closeOnce.Do(func() {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		c.closeErr = c.processor.Close()
+	})
+	return c.closeErr
+}
+
+// MemoryContentHashStore is an in-process ContentHashStore for tests and
+// single-process tools. Hashes recorded in it are lost on restart.
+type MemoryContentHashStore struct {
+	hashes map[string]string
+	mu     sync.Mutex
+}
+
+// NewMemoryContentHashStore creates an empty MemoryContentHashStore.
+func NewMemoryContentHashStore() *MemoryContentHashStore {
+	return &MemoryContentHashStore{hashes: make(map[string]string)}
+}
+
+// Hash implements ContentHashStore.
+func (s *MemoryContentHashStore) Hash(_ context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hash, ok := s.hashes[key]
+	return hash, ok, nil
+}
+
+// Record implements ContentHashStore.
+func (s *MemoryContentHashStore) Record(_ context.Context, key, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hashes[key] = hash
+	return nil
+}
@@ -0,0 +1,149 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+type hashedRecord struct {
+	ID    string
+	Title string
+	Seen  int64
+	ETag  string
+}
+
+func TestContentHash(t *testing.T) {
+	// newCounted returns a ContentHash over a counting processor keyed by
+	// ID and hashing only Title.
+	newCounted := func(store ContentHashStore) (*ContentHash[hashedRecord], *atomic.Int64) {
+		var calls atomic.Int64
+		push := Apply(testIdentity("push"), func(_ context.Context, r hashedRecord) (hashedRecord, error) {
+			calls.Add(1)
+			return r, nil
+		})
+		ch := NewContentHash(testIdentity("content-hash"), push, store,
+			func(r hashedRecord) string { return r.ID },
+			func(r hashedRecord) any { return r.Title },
+		)
+		return ch, &calls
+	}
+
+	t.Run("Skips Unchanged Content", func(t *testing.T) {
+		ch, calls := newCounted(NewMemoryContentHashStore())
+		ctx := context.Background()
+
+		for i := range 3 {
+			if _, err := ch.Process(ctx, hashedRecord{ID: "a", Title: "x", Seen: int64(i)}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if calls.Load() != 1 {
+			t.Errorf("expected 1 call for unchanged content, got %d", calls.Load())
+		}
+		if ch.Unchanged() != 2 {
+			t.Errorf("expected 2 unchanged, got %d", ch.Unchanged())
+		}
+
+		if _, err := ch.Process(ctx, hashedRecord{ID: "a", Title: "y"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := ch.Process(ctx, hashedRecord{ID: "b", Title: "y"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls.Load() != 3 {
+			t.Errorf("expected changed and new items to be processed, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("Failure Records Nothing", func(t *testing.T) {
+		store := NewMemoryContentHashStore()
+		failing := Apply(testIdentity("push"), func(_ context.Context, r hashedRecord) (hashedRecord, error) {
+			return r, errors.New("catalog down")
+		})
+		ch := NewContentHash(testIdentity("content-hash"), failing, store,
+			func(r hashedRecord) string { return r.ID },
+			func(r hashedRecord) any { return r.Title },
+		)
+
+		_, err := ch.Process(context.Background(), hashedRecord{ID: "a", Title: "x"})
+		var pipeErr *Error[hashedRecord]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "content-hash" {
+			t.Fatalf("expected error with path through content-hash, got %v", err)
+		}
+		if _, ok, _ := store.Hash(context.Background(), "a"); ok {
+			t.Error("expected no hash recorded for failed item")
+		}
+	})
+
+	t.Run("Store Errors Fail Item", func(t *testing.T) {
+		ch, calls := newCounted(brokenHashStore{})
+		_, err := ch.Process(context.Background(), hashedRecord{ID: "a"})
+		if !errors.Is(err, errHashStore) {
+			t.Errorf("expected store error, got %v", err)
+		}
+		if calls.Load() != 0 {
+			t.Errorf("expected processor not to run, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("Attaches ETag", func(t *testing.T) {
+		ch, _ := newCounted(NewMemoryContentHashStore())
+		ch.SetAttach(func(r hashedRecord, etag string) hashedRecord {
+			r.ETag = etag
+			return r
+		})
+		for range 2 {
+			result, err := ch.Process(context.Background(), hashedRecord{ID: "a", Title: "x"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.ETag != ETag("x") {
+				t.Errorf("expected ETag %q, got %q", ETag("x"), result.ETag)
+			}
+		}
+	})
+
+	t.Run("ETag Is Stable", func(t *testing.T) {
+		a := ETag(map[string]int{"a": 1, "b": 2})
+		b := ETag(map[string]int{"b": 2, "a": 1})
+		if a != b {
+			t.Errorf("expected map order not to affect ETag, got %q and %q", a, b)
+		}
+		if ETag("x") == ETag("y") {
+			t.Error("expected different content to hash differently")
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		ch, _ := newCounted(NewMemoryContentHashStore())
+		node := ch.Schema()
+		flow, ok := ContentHashKey.From(node)
+		if node.Type != "contenthash" || !ok || flow.Processor.Identity.Name() != "push" {
+			t.Errorf("unexpected schema %+v", node)
+		}
+	})
+
+	t.Run("Close Is Idempotent", func(t *testing.T) {
+		ch, _ := newCounted(NewMemoryContentHashStore())
+		if err := ch.Close(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := ch.Close(); err != nil {
+			t.Errorf("unexpected error on second close: %v", err)
+		}
+	})
+}
+
+var errHashStore = errors.New("hash store unavailable")
+
+type brokenHashStore struct{}
+
+func (brokenHashStore) Hash(context.Context, string) (string, bool, error) {
+	return "", false, errHashStore
+}
+
+func (brokenHashStore) Record(context.Context, string, string) error {
+	return errHashStore
+}
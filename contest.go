@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
 )
 
 // Contest runs all processors in parallel and returns the first result that
@@ -38,6 +39,15 @@ import (
 //   - Condition is evaluated as results arrive (no waiting for all)
 //   - Can reduce latency while ensuring quality constraints
 //
+// SetScore switches Contest from "first acceptable" to "best acceptable
+// within a latency budget": it waits up to the budget, collecting results
+// that meet the condition, and returns the highest-scoring one. If nothing
+// acceptable arrived by then, the first acceptable result after the budget
+// wins as usual.
+//
+// SetBranchRetry lets branches retry failures within the contest without
+// nesting a Retry in each branch.
+//
 // Example:
 //
 //	// Find the first shipping rate under $50
@@ -49,10 +59,19 @@ import (
 //	    upsRates,
 //	    uspsRates,
 //	)
+//
+//	// Or take the cheapest rate under $50 quoted within 200ms
+//	contest.SetScore(func(_ context.Context, rate Rate) float64 {
+//	    return -rate.Cost
+//	}, 200*time.Millisecond)
 type Contest[T Cloner[T]] struct {
 	identity   Identity
 	condition  func(context.Context, T) bool
+	score      func(context.Context, T) float64
+	clock      clockz.Clock
 	processors []Chainable[T]
+	retries    branchRetries
+	budget     time.Duration
 	mu         sync.RWMutex
 	closeOnce  sync.Once
 	closeErr   error
@@ -73,12 +92,24 @@ func NewContest[T Cloner[T]](identity Identity, condition func(context.Context,
 func (c *Contest[T]) Process(ctx context.Context, input T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, c.identity, input)
 
+	ctx, guardErr := enterDepth(ctx, c, c.identity, input)
+	if guardErr != nil {
+		return input, guardErr
+	}
+
 	start := time.Now()
 
 	c.mu.RLock()
 	processors := make([]Chainable[T], len(c.processors))
 	copy(processors, c.processors)
 	condition := c.condition
+	score := c.score
+	budget := c.budget
+	clock := c.getClock()
+	policies := make([]BranchRetry, len(processors))
+	for i, p := range processors {
+		policies[i] = c.retries.policy(p.Identity())
+	}
 	c.mu.RUnlock()
 
 	if len(processors) == 0 {
@@ -86,7 +117,7 @@ func (c *Contest[T]) Process(ctx context.Context, input T) (result T, err error)
 		return zero, &Error[T]{
 			Path:      []Identity{c.identity},
 			Err:       fmt.Errorf("no processors provided to Contest"),
-			InputData: input,
+			InputData: errorInput(input),
 			Timestamp: time.Now(),
 			Duration:  0,
 		}
@@ -97,7 +128,7 @@ func (c *Contest[T]) Process(ctx context.Context, input T) (result T, err error)
 		return zero, &Error[T]{
 			Path:      []Identity{c.identity},
 			Err:       fmt.Errorf("no condition provided to Contest"),
-			InputData: input,
+			InputData: errorInput(input),
 			Timestamp: time.Now(),
 			Duration:  0,
 		}
@@ -120,11 +151,8 @@ func (c *Contest[T]) Process(ctx context.Context, input T) (result T, err error)
 	// Launch all processors
 	for i, processor := range processors {
 		go func(idx int, p Chainable[T]) {
-			// Create an isolated copy using the Clone method
-			inputCopy := input.Clone()
-
-			// Process
-			data, processErr := p.Process(contestCtx, inputCopy)
+			// Each attempt gets an isolated copy unless the branch is read-only
+			data, processErr := runBranch(contestCtx, clock, c.identity, p, input, policies[idx])
 			select {
 			case resultCh <- contestResult{data: data, err: processErr, idx: idx, name: p.Identity().Name()}:
 			case <-contestCtx.Done():
@@ -132,6 +160,28 @@ func (c *Contest[T]) Process(ctx context.Context, input T) (result T, err error)
 		}(i, processor)
 	}
 
+	win := func(res contestResult) T {
+		// Winner! Cancel other goroutines and return
+		cancel()
+
+		// Emit winner signal
+		capitan.Info(ctx, SignalContestWinner,
+			FieldName.Field(c.identity.Name()),
+			FieldIdentityID.Field(c.identity.ID().String()),
+			FieldWinnerName.Field(res.name),
+			FieldDuration.Field(time.Since(start).Seconds()),
+		)
+		return res.data
+	}
+
+	// In scored mode, hold acceptable results until the budget elapses
+	var budgetCh <-chan time.Time
+	if score != nil && budget > 0 {
+		budgetCh = clock.After(budget)
+	}
+	var best *contestResult
+	var bestScore float64
+
 	// Collect results and check conditions
 	var allErrors []error
 	completedCount := 0
@@ -143,26 +193,26 @@ func (c *Contest[T]) Process(ctx context.Context, input T) (result T, err error)
 
 			if res.err == nil {
 				// Check if this successful result meets the condition
-				if condition(ctx, res.data) {
-					// Winner! Cancel other goroutines and return
-					cancel()
-
-					// Emit winner signal
-					capitan.Info(ctx, SignalContestWinner,
-						FieldName.Field(c.identity.Name()),
-						FieldIdentityID.Field(c.identity.ID().String()),
-						FieldWinnerName.Field(res.name),
-						FieldDuration.Field(time.Since(start).Seconds()),
-					)
-
-					return res.data, nil
+				if !condition(ctx, res.data) {
+					// Result doesn't meet condition, continue waiting for others
+					continue
+				}
+				if score == nil || (budget > 0 && budgetCh == nil) {
+					return win(res), nil
+				}
+				if s := score(ctx, res.data); best == nil || s > bestScore {
+					best, bestScore = &res, s
 				}
-				// Result doesn't meet condition, continue waiting for others
 			} else {
 				// Track errors for potential return if all fail
-				if res.err != nil {
-					allErrors = append(allErrors, res.err)
-				}
+				allErrors = append(allErrors, res.err)
+			}
+
+		case <-budgetCh:
+			// Budget spent - take the best so far, or the next acceptable
+			budgetCh = nil
+			if best != nil {
+				return win(*best), nil
 			}
 
 		case <-ctx.Done():
@@ -171,6 +221,11 @@ func (c *Contest[T]) Process(ctx context.Context, input T) (result T, err error)
 		}
 	}
 
+	// Everything finished within the budget
+	if best != nil {
+		return win(*best), nil
+	}
+
 	// No processor produced a result meeting the condition
 	if len(allErrors) == len(processors) {
 		// All processors failed with errors
@@ -183,7 +238,7 @@ func (c *Contest[T]) Process(ctx context.Context, input T) (result T, err error)
 	return input, &Error[T]{
 		Path:      []Identity{c.identity},
 		Err:       err,
-		InputData: input,
+		InputData: errorInput(input),
 		Timestamp: time.Now(),
 		Duration:  0,
 	}
@@ -198,8 +253,48 @@ func (c *Contest[T]) SetCondition(condition func(context.Context, T) bool) *Cont
 	return c
 }
 
+// SetScore enables best-result selection. Contest waits up to budget for
+// results meeting the condition and returns the one with the highest score;
+// negate costs to prefer the cheapest. If none arrived within the budget,
+// the first acceptable result afterwards wins. A zero budget waits for every
+// competitor. Passing a nil score restores first-acceptable selection.
+func (c *Contest[T]) SetScore(score func(context.Context, T) float64, budget time.Duration) *Contest[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.score = score
+	c.budget = budget
+	return c
+}
+
+// SetBranchRetry sets the retry policy of the given branches, or of every
+// branch without a policy of its own when no branches are given. The zero
+// BranchRetry disables retrying.
+func (c *Contest[T]) SetBranchRetry(policy BranchRetry, branches ...Identity) *Contest[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retries.set(policy, branches)
+	return c
+}
+
+// WithClock sets a custom clock for testing.
+func (c *Contest[T]) WithClock(clock clockz.Clock) *Contest[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+	return c
+}
+
+// getClock returns the clock to use.
+func (c *Contest[T]) getClock() clockz.Clock {
+	if c.clock == nil {
+		return clockz.RealClock
+	}
+	return c.clock
+}
+
 // Add appends a processor to the contest execution list.
 func (c *Contest[T]) Add(processor Chainable[T]) *Contest[T] {
+	noteChildren(c.identity, processor)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.processors = append(c.processors, processor)
@@ -236,6 +331,7 @@ func (c *Contest[T]) Clear() *Contest[T] {
 
 // SetProcessors replaces all processors atomically.
 func (c *Contest[T]) SetProcessors(processors ...Chainable[T]) *Contest[T] {
+	noteChildren(c.identity, processors...)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.processors = make([]Chainable[T], len(processors))
@@ -260,11 +356,24 @@ func (c *Contest[T]) Schema() Node {
 		competitors[i] = proc.Schema()
 	}
 
-	return Node{
+	node := Node{
 		Identity: c.identity,
 		Type:     "contest",
 		Flow:     ContestFlow{Competitors: competitors},
 	}
+	if c.score != nil {
+		node.Metadata = map[string]any{
+			"selection": "best",
+			"budget":    c.budget.String(),
+		}
+	}
+	if attempts := branchAttempts(&c.retries, c.processors); attempts != nil {
+		if node.Metadata == nil {
+			node.Metadata = make(map[string]any)
+		}
+		node.Metadata["branch_attempts"] = attempts
+	}
+	return node
 }
 
 // Close gracefully shuts down the connector and all its child processors.
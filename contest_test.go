@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
 )
 
 func TestContest(t *testing.T) {
@@ -627,3 +628,115 @@ func TestContestSignals(t *testing.T) {
 		}
 	})
 }
+
+func TestContestScore(t *testing.T) {
+	// quote returns a competitor quoting cost once release is closed.
+	quote := func(name string, cost int, release <-chan struct{}) Chainable[TestData] {
+		return Apply(NewIdentity(name, ""), func(ctx context.Context, d TestData) (TestData, error) {
+			select {
+			case <-release:
+				d.Value = cost
+				return d, nil
+			case <-ctx.Done():
+				return d, ctx.Err()
+			}
+		})
+	}
+	now := make(chan struct{})
+	close(now)
+	under50 := func(_ context.Context, d TestData) bool { return d.Value < 50 }
+	cheapest := func(_ context.Context, d TestData) float64 { return -float64(d.Value) }
+
+	t.Run("Best When All Finish Within Budget", func(t *testing.T) {
+		contest := NewContest(NewIdentity("rates", ""), under50,
+			quote("fedex", 30, now), quote("ups", 10, now), quote("usps", 20, now), quote("dhl", 5000, now),
+		).SetScore(cheapest, time.Minute).WithClock(clockz.NewFakeClock())
+
+		result, err := contest.Process(context.Background(), TestData{})
+		if err != nil || result.Value != 10 {
+			t.Errorf("expected cheapest acceptable 10, got %d (%v)", result.Value, err)
+		}
+	})
+
+	t.Run("Budget Elapses Returns Best So Far", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		never := make(chan struct{})
+		contest := NewContest(NewIdentity("rates", ""), under50,
+			quote("fedex", 30, now), quote("ups", 40, now), quote("slow", 5, never),
+		).SetScore(cheapest, 100*time.Millisecond).WithClock(clock)
+
+		done := make(chan TestData)
+		go func() {
+			result, _ := contest.Process(context.Background(), TestData{}) //nolint:errcheck // result checked below
+			done <- result
+		}()
+		time.Sleep(20 * time.Millisecond) // let the immediate quotes arrive
+		advanceWhenWaiting(t, clock, 100*time.Millisecond)
+
+		select {
+		case result := <-done:
+			if result.Value != 30 {
+				t.Errorf("expected best so far 30, got %d", result.Value)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected contest to return when the budget elapsed")
+		}
+	})
+
+	t.Run("First Acceptable After Budget", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		late := make(chan struct{})
+		never := make(chan struct{})
+		contest := NewContest(NewIdentity("rates", ""), under50,
+			quote("late", 40, late), quote("never", 5, never),
+		).SetScore(cheapest, 100*time.Millisecond).WithClock(clock)
+
+		done := make(chan TestData)
+		go func() {
+			result, _ := contest.Process(context.Background(), TestData{}) //nolint:errcheck // result checked below
+			done <- result
+		}()
+		advanceWhenWaiting(t, clock, 100*time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		close(late)
+
+		select {
+		case result := <-done:
+			if result.Value != 40 {
+				t.Errorf("expected first acceptable 40, got %d", result.Value)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the first acceptable result after the budget to win")
+		}
+	})
+
+	t.Run("Zero Budget Waits For All", func(t *testing.T) {
+		contest := NewContest(NewIdentity("rates", ""), under50,
+			quote("fedex", 30, now), quote("ups", 10, now),
+		).SetScore(cheapest, 0)
+		result, err := contest.Process(context.Background(), TestData{})
+		if err != nil || result.Value != 10 {
+			t.Errorf("expected 10, got %d (%v)", result.Value, err)
+		}
+	})
+
+	t.Run("None Acceptable", func(t *testing.T) {
+		contest := NewContest(NewIdentity("rates", ""), under50,
+			quote("dhl", 5000, now),
+		).SetScore(cheapest, time.Minute).WithClock(clockz.NewFakeClock())
+		if _, err := contest.Process(context.Background(), TestData{}); err == nil {
+			t.Error("expected error when no result meets the condition")
+		}
+	})
+
+	t.Run("Schema Metadata", func(t *testing.T) {
+		contest := NewContest(NewIdentity("rates", ""), under50, quote("fedex", 30, now))
+		if contest.Schema().Metadata != nil {
+			t.Error("expected no metadata in first-acceptable mode")
+		}
+		node := contest.SetScore(cheapest, 200*time.Millisecond).Schema()
+		if node.Metadata["selection"] != "best" || node.Metadata["budget"] != "200ms" {
+			t.Errorf("unexpected metadata: %v", node.Metadata)
+		}
+	})
+}
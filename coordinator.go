@@ -0,0 +1,259 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// Coordinator errors.
+var (
+	// ErrCoordinatorAborted is wrapped by errors from a Coordinator that
+	// aborted because a participant failed to prepare.
+	ErrCoordinatorAborted = errors.New("coordinator aborted")
+
+	// ErrPartialCommit is wrapped by errors from a Coordinator whose
+	// participants did not all commit after every one had prepared.
+	ErrPartialCommit = errors.New("partial commit")
+)
+
+// Participant is one resource in a Coordinator, given as the pipelines
+// running its two-phase protocol. Prepare reserves the change, such as
+// holding stock or authorizing a card, and returns data for Commit, which
+// makes it permanent, or Abort, which releases it. Abort may be nil when
+// an unconfirmed reservation simply expires.
+type Participant[T any] struct {
+	Prepare Chainable[T]
+	Commit  Chainable[T]
+	Abort   Chainable[T]
+}
+
+// NewParticipant returns a Participant with the given phase pipelines.
+func NewParticipant[T any](prepare, commit, abort Chainable[T]) Participant[T] {
+	return Participant[T]{Prepare: prepare, Commit: commit, Abort: abort}
+}
+
+// Coordinator commits a change across several resources only if all of
+// them can take it, in the style of a two-phase commit. It runs every
+// participant's Prepare concurrently, each on its own clone of the input.
+// If all succeed, it runs every Commit on the result of that participant's
+// Prepare; otherwise it runs Abort on the result of each Prepare that
+// succeeded and fails with an error wrapping ErrCoordinatorAborted and the
+// prepare failures.
+//
+// It is a lighter alternative to compensating each step after the fact,
+// for resources that support reserving before confirming. Once every
+// participant has prepared the decision is final: every Commit runs even
+// if another fails, and commit failures are returned wrapped in
+// ErrPartialCommit for reconciliation rather than aborted. Participants
+// whose Prepare fails are expected to leave nothing behind.
+//
+// Coordinator returns the input, merged with each Commit result if T
+// implements Mergeable.
+//
+// Example:
+//
+//	var PlaceOrderID = pipz.NewIdentity("place-order", "Reserves stock and payment together")
+//	placeOrder := pipz.NewCoordinator(PlaceOrderID,
+//	    pipz.NewParticipant(reserveStock, confirmStock, releaseStock),
+//	    pipz.NewParticipant(authorizeCard, captureCard, voidAuthorization),
+//	)
+type Coordinator[T Cloner[T]] struct {
+	identity     Identity
+	participants []Participant[T]
+	mu           sync.RWMutex
+	closeOnce    sync.Once
+	closeErr     error
+}
+
+// NewCoordinator creates a Coordinator over participants.
+func NewCoordinator[T Cloner[T]](identity Identity, participants ...Participant[T]) *Coordinator[T] {
+	noteParticipants(identity, participants)
+	return &Coordinator[T]{
+		identity:     identity,
+		participants: participants,
+	}
+}
+
+// Process implements the Chainable interface.
+func (c *Coordinator[T]) Process(ctx context.Context, input T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, c.identity, input)
+
+	ctx, guardErr := enterDepth(ctx, c, c.identity, input)
+	if guardErr != nil {
+		return input, guardErr
+	}
+
+	c.mu.RLock()
+	participants := make([]Participant[T], len(c.participants))
+	copy(participants, c.participants)
+	c.mu.RUnlock()
+
+	// Phase one: every participant prepares on its own clone
+	prepared := make([]T, len(participants))
+	prepareErrs := runPhase(c.identity, participants, func(i int, p Participant[T]) error {
+		var err error
+		prepared[i], err = p.Prepare.Process(ctx, branchInput(p.Prepare, input))
+		return err
+	})
+
+	if err := errors.Join(prepareErrs...); err != nil {
+		// Phase two, abort: release what was reserved; the item's context
+		// may be done, but the reservations must still be released
+		abortCtx := context.WithoutCancel(ctx)
+		abortErrs := runPhase(c.identity, participants, func(i int, p Participant[T]) error {
+			if prepareErrs[i] != nil || p.Abort == nil {
+				return nil
+			}
+			_, err := p.Abort.Process(abortCtx, prepared[i])
+			return err
+		})
+		capitan.Warn(ctx, SignalCoordinatorAborted,
+			FieldName.Field(c.identity.Name()),
+			FieldIdentityID.Field(c.identity.ID().String()),
+			FieldProcessorCount.Field(len(participants)),
+			FieldError.Field(err.Error()),
+		)
+		return input, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(input),
+			Err:       fmt.Errorf("%w: %w", ErrCoordinatorAborted, errors.Join(err, errors.Join(abortErrs...))),
+			Path:      []Identity{c.identity},
+		}
+	}
+
+	// Phase two, commit: the decision is final, so every commit runs
+	commitCtx := context.WithoutCancel(ctx)
+	committed := make(map[Identity]T, len(participants))
+	commits := make([]Chainable[T], len(participants))
+	var committedMu sync.Mutex
+	commitErrs := runPhase(c.identity, participants, func(i int, p Participant[T]) error {
+		commits[i] = p.Commit
+		res, err := p.Commit.Process(commitCtx, prepared[i])
+		if err == nil {
+			committedMu.Lock()
+			committed[p.Commit.Identity()] = res
+			committedMu.Unlock()
+		}
+		return err
+	})
+	if err := errors.Join(commitErrs...); err != nil {
+		capitan.Error(ctx, SignalCoordinatorPartialCommit,
+			FieldName.Field(c.identity.Name()),
+			FieldIdentityID.Field(c.identity.ID().String()),
+			FieldProcessorCount.Field(len(participants)),
+			FieldError.Field(err.Error()),
+		)
+		return input, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(input),
+			Err:       fmt.Errorf("%w: %w", ErrPartialCommit, err),
+			Path:      []Identity{c.identity},
+		}
+	}
+
+	capitan.Info(ctx, SignalCoordinatorCommitted,
+		FieldName.Field(c.identity.Name()),
+		FieldIdentityID.Field(c.identity.ID().String()),
+		FieldProcessorCount.Field(len(participants)),
+	)
+	return mergeBranches(input, commits, committed), nil
+}
+
+// runPhase runs fn for every participant concurrently and returns their
+// errors by participant index, recording panics as errors of identity.
+func runPhase[T any](identity Identity, participants []Participant[T], fn func(int, Participant[T]) error) []error {
+	errs := make([]error, len(participants))
+	var wg sync.WaitGroup
+	for i, p := range participants {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = newPanicError(identity, r)
+				}
+			}()
+			errs[i] = fn(i, p)
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// Add appends participants to the coordinator.
+func (c *Coordinator[T]) Add(participants ...Participant[T]) *Coordinator[T] {
+	noteParticipants(c.identity, participants)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.participants = append(c.participants, participants...)
+	return c
+}
+
+// noteParticipants records the phase pipelines of participants as children
+// of parent, for the recursion guard (see noteChildren).
+func noteParticipants[T any](parent Identity, participants []Participant[T]) {
+	for _, p := range participants {
+		noteChildren(parent, p.Prepare, p.Commit, p.Abort)
+	}
+}
+
+// Len returns the number of participants.
+func (c *Coordinator[T]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.participants)
+}
+
+// Identity returns the identity of this connector.
+func (c *Coordinator[T]) Identity() Identity {
+	return c.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (c *Coordinator[T]) Schema() Node {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	participants := make([]ParticipantFlow, len(c.participants))
+	for i, p := range c.participants {
+		participants[i] = ParticipantFlow{
+			Prepare: p.Prepare.Schema(),
+			Commit:  p.Commit.Schema(),
+		}
+		if p.Abort != nil {
+			abort := p.Abort.Schema()
+			participants[i].Abort = &abort
+		}
+	}
+	return Node{
+		Identity: c.identity,
+		Type:     "coordinator",
+		Flow:     CoordinatorFlow{Participants: participants},
+	}
+}
+
+// Close gracefully shuts down the connector and every participant's
+// pipelines.
+// Close is idempotent - multiple calls return the same result.
+func (c *Coordinator[T]) Close() error {
+	c.closeOnce.Do(func() {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+
+		var errs []error
+		for i := len(c.participants) - 1; i >= 0; i-- {
+			p := c.participants[i]
+			if p.Abort != nil {
+				errs = append(errs, p.Abort.Close())
+			}
+			errs = append(errs, p.Commit.Close(), p.Prepare.Close())
+		}
+		c.closeErr = errors.Join(errs...)
+	})
+	return c.closeErr
+}
@@ -0,0 +1,126 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestCoordinator(t *testing.T) {
+	// ledger records which phases ran, by participant.
+	type ledger struct {
+		ran []string
+		mu  sync.Mutex
+	}
+	record := func(l *ledger, name string, fail bool) Chainable[taggedOrder] {
+		return Apply(testIdentity(name), func(_ context.Context, o taggedOrder) (taggedOrder, error) {
+			l.mu.Lock()
+			l.ran = append(l.ran, name)
+			l.mu.Unlock()
+			if fail {
+				return o, errors.New(name + " failed")
+			}
+			o.Tags[name] = "done"
+			return o, nil
+		})
+	}
+	has := func(l *ledger, name string) bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for _, r := range l.ran {
+			if r == name {
+				return true
+			}
+		}
+		return false
+	}
+	input := func() taggedOrder {
+		return taggedOrder{Tags: map[string]string{"origin": "web"}, Trace: "trace-1"}
+	}
+
+	t.Run("Commits When All Prepare", func(t *testing.T) {
+		var l ledger
+		coord := NewCoordinator(testIdentity("coord"),
+			NewParticipant(record(&l, "reserve-stock", false), record(&l, "confirm-stock", false), record(&l, "release-stock", false)),
+			NewParticipant(record(&l, "authorize", false), record(&l, "capture", false), nil),
+		)
+		result, err := coord.Process(context.Background(), input())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !has(&l, "confirm-stock") || !has(&l, "capture") || has(&l, "release-stock") {
+			t.Errorf("unexpected phases: %v", l.ran)
+		}
+		// Commits see their own prepare, and the results merge
+		if result.Tags["reserve-stock"] != "done" || result.Tags["capture"] != "done" || result.Tags["authorize"] != "done" {
+			t.Errorf("expected merged commit results, got %v", result.Tags)
+		}
+	})
+
+	t.Run("Aborts Prepared On Failure", func(t *testing.T) {
+		var l ledger
+		coord := NewCoordinator(testIdentity("coord"),
+			NewParticipant(record(&l, "reserve-stock", false), record(&l, "confirm-stock", false), record(&l, "release-stock", false)),
+			NewParticipant(record(&l, "authorize", true), record(&l, "capture", false), record(&l, "void", false)),
+		)
+		_, err := coord.Process(context.Background(), input())
+		if !errors.Is(err, ErrCoordinatorAborted) {
+			t.Fatalf("expected ErrCoordinatorAborted, got %v", err)
+		}
+		if !has(&l, "release-stock") || has(&l, "void") {
+			t.Errorf("expected only the prepared participant aborted, got %v", l.ran)
+		}
+		if has(&l, "confirm-stock") || has(&l, "capture") {
+			t.Errorf("expected no commits, got %v", l.ran)
+		}
+	})
+
+	t.Run("Partial Commit", func(t *testing.T) {
+		var l ledger
+		coord := NewCoordinator(testIdentity("coord"),
+			NewParticipant(record(&l, "reserve-stock", false), record(&l, "confirm-stock", true), nil),
+			NewParticipant(record(&l, "authorize", false), record(&l, "capture", false), nil),
+		)
+		_, err := coord.Process(context.Background(), input())
+		if !errors.Is(err, ErrPartialCommit) {
+			t.Fatalf("expected ErrPartialCommit, got %v", err)
+		}
+		if !has(&l, "capture") {
+			t.Errorf("expected the other commit to run, got %v", l.ran)
+		}
+	})
+
+	t.Run("Participant Closing A Cycle", func(t *testing.T) {
+		defer recursionPossible.Store(recursionPossible.Load())
+		recursionPossible.Store(false)
+
+		var l ledger
+		coord := NewCoordinator[taggedOrder](testIdentity("coord"))
+		outer := NewSequence(testIdentity("outer"), Chainable[taggedOrder](coord))
+		coord.Add(NewParticipant(record(&l, "reserve", false), record(&l, "confirm", false), nil))
+		if recursionPossible.Load() {
+			t.Fatal("expected no cycle from participants not containing the coordinator")
+		}
+		coord.Add(NewParticipant(record(&l, "authorize", false), record(&l, "capture", false), outer))
+		if !recursionPossible.Load() {
+			t.Fatal("expected the cycle through the abort pipeline to be found")
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		var l ledger
+		node := NewCoordinator(testIdentity("coord"),
+			NewParticipant(record(&l, "reserve", false), record(&l, "confirm", false), record(&l, "release", false)),
+		).Add(
+			NewParticipant(record(&l, "authorize", false), record(&l, "capture", false), nil),
+		).Schema()
+		flow, ok := node.Flow.(CoordinatorFlow)
+		if node.Type != "coordinator" || !ok || len(flow.Participants) != 2 {
+			t.Fatalf("unexpected schema: %s %+v", node.Type, node.Flow)
+		}
+		if flow.Participants[0].Abort == nil || flow.Participants[1].Abort != nil {
+			t.Errorf("unexpected abort nodes: %+v", flow.Participants)
+		}
+	})
+}
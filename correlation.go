@@ -0,0 +1,77 @@
+package pipz
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// correlation holds the correlation ID of one root execution. The ID is
+// generated on first use, so executions nobody observes pay nothing for it.
+type correlation struct {
+	id   string
+	once sync.Once
+}
+
+// ID returns the correlation ID, generating it if none was given.
+func (c *correlation) ID() string {
+	c.once.Do(func() {
+		if c.id == "" {
+			c.id = uuid.NewString()
+		}
+	})
+	return c.id
+}
+
+// correlationKey is the context key for the execution's correlation.
+type correlationKey struct{}
+
+// withCorrelation returns ctx carrying a new correlation, identified by id
+// or a generated ID if id is empty, unless ctx already carries one.
+func withCorrelation(ctx context.Context, id string) context.Context {
+	if _, ok := ctx.Value(correlationKey{}).(*correlation); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationKey{}, &correlation{id: id})
+}
+
+// WithCorrelationID returns a context whose executions are correlated by
+// id, such as a request ID received from an upstream service, instead of a
+// generated ID. Nested executions keep the ID of the outermost.
+//
+// Example:
+//
+//	ctx = pipz.WithCorrelationID(ctx, r.Header.Get("X-Request-ID"))
+//	result, err := pipeline.Process(ctx, order)
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, &correlation{id: id})
+}
+
+// CorrelationIDFromContext returns the correlation ID of the execution ctx
+// belongs to. Every root Process call, whether of a Pipeline or a bare
+// connector, starts an execution with its own ID, which every connector
+// nested within it shares, including retry attempts, fallbacks, and nested
+// Pipelines. A Pipeline at the root uses its execution ID. Outside any
+// execution it returns false.
+//
+// SignalFields includes the ID as correlation_id in every signal emitted
+// during the execution, so one request's journey can be reconstructed from
+// the signals alone.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	c := correlationFrom(ctx)
+	if c == nil {
+		return "", false
+	}
+	return c.ID(), true
+}
+
+// correlationFrom returns the correlation of the execution ctx belongs to,
+// or nil outside any execution.
+func correlationFrom(ctx context.Context) *correlation {
+	c, _ := ctx.Value(correlationKey{}).(*correlation)
+	return c
+}
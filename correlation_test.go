@@ -0,0 +1,109 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestCorrelationID(t *testing.T) {
+	// capture records the correlation IDs seen by each attempt.
+	capture := func(ids *[]string, mu *sync.Mutex, fail bool) Chainable[int] {
+		return Apply(testIdentity("capture"), func(ctx context.Context, n int) (int, error) {
+			id, _ := CorrelationIDFromContext(ctx)
+			mu.Lock()
+			*ids = append(*ids, id)
+			mu.Unlock()
+			if fail {
+				return n, errors.New("flaky")
+			}
+			return n, nil
+		})
+	}
+
+	t.Run("Shared Across Nested Connectors", func(t *testing.T) {
+		var ids []string
+		var mu sync.Mutex
+		pipeline := NewSequence(testIdentity("seq"),
+			NewRetry(testIdentity("retry"), capture(&ids, &mu, true), 2),
+		)
+		fallback := NewFallback(testIdentity("fallback"), pipeline, capture(&ids, &mu, false))
+		if _, err := fallback.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ids) != 3 || ids[0] == "" || ids[1] != ids[0] || ids[2] != ids[0] {
+			t.Errorf("expected one ID across attempts and fallback, got %v", ids)
+		}
+
+		if _, err := fallback.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ids[3] == ids[0] {
+			t.Error("expected each root execution to get its own ID")
+		}
+	})
+
+	t.Run("Pipeline Uses Execution ID", func(t *testing.T) {
+		var seen, execution string
+		probe := Effect(testIdentity("probe"), func(ctx context.Context, _ int) error {
+			seen, _ = CorrelationIDFromContext(ctx)
+			id, _ := ExecutionIDFromContext(ctx)
+			execution = id.String()
+			return nil
+		})
+		if _, err := NewPipeline(testIdentity("pipeline"), probe).Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if seen == "" || seen != execution {
+			t.Errorf("expected correlation %q to match execution %q", seen, execution)
+		}
+	})
+
+	t.Run("Caller Supplied", func(t *testing.T) {
+		var ids []string
+		var mu sync.Mutex
+		ctx := WithCorrelationID(context.Background(), "req-123")
+		outer := NewPipeline(testIdentity("outer"), NewPipeline(testIdentity("inner"), capture(&ids, &mu, false)))
+		if _, err := outer.Process(ctx, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ids) != 1 || ids[0] != "req-123" {
+			t.Errorf("expected req-123 through nested pipelines, got %v", ids)
+		}
+	})
+
+	t.Run("Outside Execution", func(t *testing.T) {
+		if _, ok := CorrelationIDFromContext(context.Background()); ok {
+			t.Error("expected no correlation outside an execution")
+		}
+	})
+
+	t.Run("Carried By Signals", func(t *testing.T) {
+		var captured []capitan.Field
+		done := make(chan struct{})
+		listener := capitan.Hook(SignalBackoffWaiting, func(_ context.Context, e *capitan.Event) {
+			captured = SignalFields(e)
+			close(done)
+		})
+		defer listener.Close()
+
+		failing := Apply(testIdentity("failing"), func(_ context.Context, n int) (int, error) {
+			return n, errors.New("boom")
+		})
+		ctx := WithCorrelationID(context.Background(), "req-456")
+		NewBackoff(testIdentity("backoff"), failing, 2, time.Millisecond).Process(ctx, 1) //nolint:errcheck
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("backoff waiting signal not received")
+		}
+		if got := FieldCorrelationID.ExtractFromFields(captured); got != "req-456" {
+			t.Errorf("correlation_id = %q, want req-456", got)
+		}
+	})
+}
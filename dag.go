@@ -0,0 +1,305 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrInvalidDAG is wrapped by errors from a DAG whose nodes depend on
+// processors that were not added before them.
+var ErrInvalidDAG = errors.New("invalid dag")
+
+// dagNode is a processor in a DAG with the indexes of its prerequisites.
+type dagNode[T any] struct {
+	processor Chainable[T]
+	after     []int
+}
+
+// DAG runs processors in dependency order, concurrently wherever the
+// dependencies allow: a middle ground between Sequence, where every step
+// waits for the one before, and Concurrent, where nothing waits. Each
+// processor declares the processors it needs when added, and starts as soon
+// as they have all finished.
+//
+// Processors without dependencies receive a clone of the input. A
+// processor with dependencies receives a clone of its first prerequisite's
+// result merged with the others' results in the order they were declared.
+// The DAG returns the input merged with the results of its final
+// processors, those nothing depends on, in the order they were added. Both
+// merges use Mergeable; for types that do not implement it, the last
+// result wins, so such a DAG should end in a single final processor.
+//
+// The first failure cancels the processors still running, skips those
+// waiting on prerequisites, and is returned once the running ones stop.
+//
+// Dependencies must be added before the processors depending on them,
+// which rules out cycles. Add records a dependency on an unknown processor
+// as an error wrapping ErrInvalidDAG, returned by Validate and Process.
+//
+// Example:
+//
+//	var EnrichID = pipz.NewIdentity("enrich-order", "Enriches orders from dependent lookups")
+//	dag := pipz.NewDAG[Order](EnrichID).
+//	    Add(fetchCustomer).
+//	    Add(fetchInventory).
+//	    Add(scoreFraud, FetchCustomerID).                      // needs the customer
+//	    Add(quoteShipping, FetchCustomerID, FetchInventoryID) // needs both
+type DAG[T Cloner[T]] struct {
+	identity  Identity
+	nodes     []dagNode[T]
+	err       error
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewDAG creates an empty DAG. Add processors with Add.
+func NewDAG[T Cloner[T]](identity Identity) *DAG[T] {
+	return &DAG[T]{identity: identity}
+}
+
+// Add adds processor to the DAG, to run once the processors identified by
+// after have finished. Those processors must already have been added.
+func (d *DAG[T]) Add(processor Chainable[T], after ...Identity) *DAG[T] {
+	noteChildren(d.identity, processor)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	node := dagNode[T]{processor: processor, after: make([]int, 0, len(after))}
+	for _, id := range after {
+		i := d.indexLocked(id)
+		if i < 0 {
+			d.err = errors.Join(d.err, fmt.Errorf("%w: %s depends on %s, which has not been added",
+				ErrInvalidDAG, processor.Identity().Name(), id.Name()))
+			continue
+		}
+		node.after = append(node.after, i)
+	}
+	d.nodes = append(d.nodes, node)
+	return d
+}
+
+// indexLocked returns the index of the processor identified by id, or -1.
+// The caller holds mu.
+func (d *DAG[T]) indexLocked(id Identity) int {
+	for i, node := range d.nodes {
+		if node.processor.Identity().ID() == id.ID() {
+			return i
+		}
+	}
+	return -1
+}
+
+// Validate returns the errors recorded by Add, wrapping ErrInvalidDAG, or
+// nil if every dependency was found.
+func (d *DAG[T]) Validate() error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.err
+}
+
+// Len returns the number of processors in the DAG.
+func (d *DAG[T]) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.nodes)
+}
+
+// Process implements the Chainable interface.
+func (d *DAG[T]) Process(ctx context.Context, input T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, d.identity, input)
+
+	ctx, guardErr := enterDepth(ctx, d, d.identity, input)
+	if guardErr != nil {
+		return input, guardErr
+	}
+
+	d.mu.RLock()
+	nodes := d.nodes
+	invalid := d.err
+	d.mu.RUnlock()
+
+	if invalid != nil {
+		return input, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(input),
+			Err:       invalid,
+			Path:      []Identity{d.identity},
+		}
+	}
+	if len(nodes) == 0 {
+		return input, nil
+	}
+
+	start := time.Now()
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]T, len(nodes))
+	done := make([]chan struct{}, len(nodes))
+	final := make([]bool, len(nodes))
+	for i, node := range nodes {
+		done[i] = make(chan struct{})
+		final[i] = true
+		for _, dep := range node.after {
+			final[dep] = false
+		}
+	}
+
+	var failOnce sync.Once
+	var firstErr error
+	fail := func(err error) {
+		failOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for i, node := range nodes {
+		go func() {
+			defer close(done[i])
+			defer func() {
+				if r := recover(); r != nil {
+					fail(newPanicError(node.processor.Identity(), r))
+				}
+			}()
+
+			// Wait for prerequisites; a failure anywhere skips this node
+			for _, dep := range node.after {
+				<-done[dep]
+			}
+			if runCtx.Err() != nil {
+				return
+			}
+
+			var nodeInput T
+			if len(node.after) == 0 {
+				nodeInput = branchInput(node.processor, input)
+			} else {
+				prior := make([]T, len(node.after))
+				for j, dep := range node.after {
+					prior[j] = results[dep]
+				}
+				nodeInput = foldResults(prior[0].Clone(), prior[1:])
+			}
+
+			res, err := node.processor.Process(runCtx, nodeInput)
+			if err != nil {
+				fail(err)
+				return
+			}
+			results[i] = res
+		}()
+	}
+	for _, ch := range done {
+		<-ch
+	}
+	// Nodes skipped because the caller's context ended left no results
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+
+	capitan.Info(ctx, SignalDAGCompleted,
+		FieldName.Field(d.identity.Name()),
+		FieldIdentityID.Field(d.identity.ID().String()),
+		FieldProcessorCount.Field(len(nodes)),
+		FieldDuration.Field(time.Since(start).Seconds()),
+	)
+
+	if firstErr != nil {
+		if isControl(firstErr) {
+			return input, firstErr
+		}
+		var pipeErr *Error[T]
+		if errors.As(firstErr, &pipeErr) {
+			pipeErr.prependPath(ctx, d.identity)
+			return input, pipeErr
+		}
+		return input, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(input),
+			Err:       firstErr,
+			Path:      []Identity{d.identity},
+			Timeout:   errors.Is(firstErr, context.DeadlineExceeded),
+			Canceled:  errors.Is(firstErr, context.Canceled),
+		}
+	}
+
+	sinks := make([]T, 0, len(nodes))
+	for i, res := range results {
+		if final[i] {
+			sinks = append(sinks, res)
+		}
+	}
+	if isMergeable(input) {
+		return foldResults(input, sinks), nil
+	}
+	return sinks[len(sinks)-1], nil
+}
+
+// foldResults merges others into base in order when T implements
+// Mergeable, and otherwise returns the last of them, or base if there are
+// none.
+func foldResults[T any](base T, others []T) T {
+	for _, other := range others {
+		mergeable, ok := any(base).(Mergeable[T])
+		if !ok {
+			base = other
+			continue
+		}
+		base = mergeable.Merge(other)
+	}
+	return base
+}
+
+// Identity returns the identity of this connector.
+func (d *DAG[T]) Identity() Identity {
+	return d.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (d *DAG[T]) Schema() Node {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	nodes := make([]Node, len(d.nodes))
+	dependsOn := make(map[string][]string)
+	for i, node := range d.nodes {
+		nodes[i] = node.processor.Schema()
+		if len(node.after) == 0 {
+			continue
+		}
+		ids := make([]string, len(node.after))
+		for j, dep := range node.after {
+			ids[j] = d.nodes[dep].processor.Identity().ID().String()
+		}
+		dependsOn[node.processor.Identity().ID().String()] = ids
+	}
+	return Node{
+		Identity: d.identity,
+		Type:     "dag",
+		Flow:     DAGFlow{Nodes: nodes, DependsOn: dependsOn},
+	}
+}
+
+// Close gracefully shuts down the connector and all its child processors.
+// Close is idempotent - multiple calls return the same result.
+func (d *DAG[T]) Close() error {
+	d.closeOnce.Do(func() {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+		var errs []error
+		for i := len(d.nodes) - 1; i >= 0; i-- {
+			if err := d.nodes[i].processor.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		d.closeErr = errors.Join(errs...)
+	})
+	return d.closeErr
+}
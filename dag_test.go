@@ -0,0 +1,182 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDAG(t *testing.T) {
+	// dagStep tags the order with key, recording the tags it saw on entry.
+	dagStep := func(id Identity, key string, saw *map[string]string) Chainable[taggedOrder] {
+		return Transform(id, func(_ context.Context, o taggedOrder) taggedOrder {
+			if saw != nil {
+				*saw = o.Clone().Tags
+			}
+			o.Tags[key] = "done"
+			return o
+		})
+	}
+	input := func() taggedOrder {
+		return taggedOrder{Tags: map[string]string{"origin": "web"}, Trace: "trace-1"}
+	}
+
+	t.Run("Runs In Dependency Order", func(t *testing.T) {
+		customerID := testIdentity("customer")
+		inventoryID := testIdentity("inventory")
+		var fraudSaw, shippingSaw map[string]string
+		dag := NewDAG[taggedOrder](testIdentity("dag")).
+			Add(dagStep(customerID, "customer", nil)).
+			Add(dagStep(inventoryID, "inventory", nil)).
+			Add(dagStep(testIdentity("fraud"), "fraud", &fraudSaw), customerID).
+			Add(dagStep(testIdentity("shipping"), "shipping", &shippingSaw), customerID, inventoryID)
+
+		result, err := dag.Process(context.Background(), input())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fraudSaw["customer"] != "done" || fraudSaw["inventory"] != "" {
+			t.Errorf("expected fraud to see only the customer, got %v", fraudSaw)
+		}
+		if shippingSaw["customer"] != "done" || shippingSaw["inventory"] != "done" {
+			t.Errorf("expected shipping to see both prerequisites, got %v", shippingSaw)
+		}
+		for _, key := range []string{"origin", "customer", "inventory", "fraud", "shipping"} {
+			if result.Tags[key] == "" {
+				t.Errorf("expected result to carry %s, got %v", key, result.Tags)
+			}
+		}
+	})
+
+	t.Run("Independent Nodes Run Concurrently", func(t *testing.T) {
+		var running, peak atomic.Int32
+		slow := func(name string) Chainable[taggedOrder] {
+			return Transform(testIdentity(name), func(_ context.Context, o taggedOrder) taggedOrder {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				running.Add(-1)
+				return o
+			})
+		}
+		dag := NewDAG[taggedOrder](testIdentity("dag")).Add(slow("a")).Add(slow("b")).Add(slow("c"))
+		if _, err := dag.Process(context.Background(), input()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if peak.Load() < 2 {
+			t.Errorf("expected independent nodes to overlap, peak was %d", peak.Load())
+		}
+	})
+
+	t.Run("Failure Skips Dependents", func(t *testing.T) {
+		failingID := testIdentity("failing")
+		failing := Apply(failingID, func(_ context.Context, o taggedOrder) (taggedOrder, error) {
+			return o, errors.New("lookup failed")
+		})
+		var ran atomic.Bool
+		dependent := Effect(testIdentity("dependent"), func(_ context.Context, _ taggedOrder) error {
+			ran.Store(true)
+			return nil
+		})
+		dag := NewDAG[taggedOrder](testIdentity("dag")).Add(failing).Add(dependent, failingID)
+
+		_, err := dag.Process(context.Background(), input())
+		var pipeErr *Error[taggedOrder]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "dag" {
+			t.Fatalf("expected path through dag, got %v", err)
+		}
+		if ran.Load() {
+			t.Error("expected dependent not to run")
+		}
+	})
+
+	t.Run("Unknown Dependency", func(t *testing.T) {
+		dag := NewDAG[taggedOrder](testIdentity("dag")).
+			Add(dagStep(testIdentity("a"), "a", nil), testIdentity("missing"))
+		if !errors.Is(dag.Validate(), ErrInvalidDAG) {
+			t.Errorf("expected ErrInvalidDAG, got %v", dag.Validate())
+		}
+		if _, err := dag.Process(context.Background(), input()); !errors.Is(err, ErrInvalidDAG) {
+			t.Errorf("expected Process to fail with ErrInvalidDAG, got %v", err)
+		}
+	})
+
+	t.Run("Dependencies Match By ID", func(t *testing.T) {
+		customerID := testIdentity("customer")
+		dag := NewDAG[taggedOrder](testIdentity("dag")).
+			Add(dagStep(customerID.WithTags("lookup"), "customer", nil)).
+			Add(dagStep(testIdentity("fraud"), "fraud", nil), customerID)
+		if err := dag.Validate(); err != nil {
+			t.Errorf("expected the tagged step to satisfy the dependency, got %v", err)
+		}
+	})
+
+	t.Run("Context Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		dag := NewDAG[clonableInt](testIdentity("dag")).
+			Add(Transform(testIdentity("step"), func(_ context.Context, n clonableInt) clonableInt { return n + 1 }))
+		result, err := dag.Process(ctx, 1)
+		var pipeErr *Error[clonableInt]
+		if !errors.As(err, &pipeErr) || !pipeErr.Canceled || !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected a canceled error, got %v", err)
+		}
+		if result != 1 {
+			t.Errorf("expected the input back, got %d", result)
+		}
+
+		ctx, cancel = context.WithCancel(context.Background())
+		firstID := testIdentity("first")
+		dag = NewDAG[clonableInt](testIdentity("dag")).
+			Add(Transform(firstID, func(_ context.Context, n clonableInt) clonableInt {
+				cancel()
+				return n + 1
+			})).
+			Add(Transform(testIdentity("second"), func(_ context.Context, n clonableInt) clonableInt { return n * 10 }), firstID)
+		result, err = dag.Process(ctx, 1)
+		if !errors.As(err, &pipeErr) || !pipeErr.Canceled {
+			t.Fatalf("expected a canceled error, got %v", err)
+		}
+		if result != 1 {
+			t.Errorf("expected the input back, got %d", result)
+		}
+	})
+
+	t.Run("Last Result Without Merge", func(t *testing.T) {
+		firstID := testIdentity("first")
+		dag := NewDAG[clonableInt](testIdentity("dag")).
+			Add(Transform(firstID, func(_ context.Context, n clonableInt) clonableInt { return n + 1 })).
+			Add(Transform(testIdentity("second"), func(_ context.Context, n clonableInt) clonableInt { return n * 10 }), firstID)
+		result, err := dag.Process(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != 20 {
+			t.Errorf("expected 20, got %d", result)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		aID := testIdentity("a")
+		bID := testIdentity("b")
+		node := NewDAG[taggedOrder](testIdentity("dag")).
+			Add(dagStep(aID, "a", nil)).
+			Add(dagStep(bID, "b", nil), aID).
+			Schema()
+		flow, ok := node.Flow.(DAGFlow)
+		if node.Type != "dag" || !ok {
+			t.Fatalf("expected dag node with DAGFlow, got %s %T", node.Type, node.Flow)
+		}
+		deps := flow.DependsOn[bID.ID().String()]
+		if len(flow.Nodes) != 2 || len(deps) != 1 || deps[0] != aID.ID().String() {
+			t.Errorf("unexpected flow: %+v", flow)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package pipz
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// DashboardQueries holds the PromQL templates for a generated dashboard's
+// panels. pipz emits signals rather than metrics, so the queries are
+// written against whatever names the application's signal-to-metrics
+// bridge exports. In each template "{{path}}", "{{name}}", "{{id}}", and
+// "{{type}}" are replaced with the node's schema path (see SchemaNode),
+// identity name, identity UUID, and type. An empty template omits its
+// panel.
+type DashboardQueries struct {
+	// Rate charts items processed per second.
+	Rate string
+	// Errors charts failures per second.
+	Errors string
+	// Duration charts processing latency, such as a histogram quantile.
+	Duration string
+	// BreakerState shows the state of circuitbreaker nodes.
+	BreakerState string
+}
+
+// DashboardOptions configures GrafanaDashboard.
+type DashboardOptions struct {
+	// Title of the dashboard. Defaults to the root node's name.
+	Title string
+	// UID of the dashboard, so regenerating it replaces the old one.
+	UID string
+	// Datasource is the UID of the Prometheus datasource.
+	Datasource string
+	// Queries are the panel templates.
+	Queries DashboardQueries
+	// Types limits panels to nodes of these types, such as "processor"
+	// and "circuitbreaker". Empty includes every node.
+	Types []string
+}
+
+// dashboardPanel is the subset of the Grafana panel model GrafanaDashboard
+// produces.
+type dashboardPanel struct {
+	Datasource  *dashboardDatasource  `json:"datasource,omitempty"`
+	FieldConfig *dashboardFieldConfig `json:"fieldConfig,omitempty"`
+	GridPos     dashboardGridPos      `json:"gridPos"`
+	Title       string                `json:"title"`
+	Type        string                `json:"type"`
+	Targets     []dashboardTarget     `json:"targets,omitempty"`
+	ID          int                   `json:"id"`
+}
+
+type dashboardDatasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid,omitempty"`
+}
+
+type dashboardFieldConfig struct {
+	Defaults dashboardDefaults `json:"defaults"`
+}
+
+type dashboardDefaults struct {
+	Unit string `json:"unit,omitempty"`
+}
+
+type dashboardGridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type dashboardTarget struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+	RefID        string `json:"refId"`
+}
+
+// GrafanaDashboard generates a Grafana dashboard definition from a schema,
+// so a new pipeline's observability is generated rather than handcrafted
+// and stays in step with its structure. Each node gets a row, titled with
+// its schema path, holding rate, error, and duration panels; circuit
+// breakers also get a state panel. The result is dashboard JSON ready for
+// Grafana's import API or file provisioning.
+//
+// Example:
+//
+//	dashboard, err := pipz.GrafanaDashboard(pipz.NewSchema(pipeline.Schema()), pipz.DashboardOptions{
+//	    UID:        "orders",
+//	    Datasource: "prometheus",
+//	    Queries: pipz.DashboardQueries{
+//	        Rate:     `sum(rate(pipz_processed_total{path="{{path}}"}[5m]))`,
+//	        Errors:   `sum(rate(pipz_failed_total{path="{{path}}"}[5m]))`,
+//	        Duration: `histogram_quantile(0.99, sum by (le) (rate(pipz_duration_seconds_bucket{path="{{path}}"}[5m])))`,
+//	    },
+//	})
+func GrafanaDashboard(schema Schema, opts DashboardOptions) ([]byte, error) {
+	title := opts.Title
+	if title == "" {
+		title = schema.Root.Identity.Name()
+	}
+	var datasource *dashboardDatasource
+	if opts.Datasource != "" {
+		datasource = &dashboardDatasource{Type: "prometheus", UID: opts.Datasource}
+	}
+
+	panels := []dashboardPanel{}
+	y := 0
+	schema.WalkPaths(func(n SchemaNode) {
+		if len(opts.Types) > 0 && !slices.Contains(opts.Types, n.Node.Type) {
+			return
+		}
+		replacer := strings.NewReplacer(
+			"{{path}}", n.Path,
+			"{{name}}", n.Node.Identity.Name(),
+			"{{id}}", n.Node.Identity.ID().String(),
+			"{{type}}", n.Node.Type,
+		)
+
+		type panelSpec struct {
+			title, kind, query, unit string
+		}
+		specs := []panelSpec{
+			{"Rate", "timeseries", opts.Queries.Rate, "reqps"},
+			{"Errors", "timeseries", opts.Queries.Errors, "reqps"},
+			{"Duration", "timeseries", opts.Queries.Duration, "s"},
+		}
+		if n.Node.Type == "circuitbreaker" {
+			specs = append(specs, panelSpec{"Breaker State", "stat", opts.Queries.BreakerState, ""})
+		}
+		var row []dashboardPanel
+		for _, spec := range specs {
+			if spec.query == "" {
+				continue
+			}
+			row = append(row, dashboardPanel{
+				Title:      spec.title,
+				Type:       spec.kind,
+				Datasource: datasource,
+				Targets: []dashboardTarget{{
+					RefID:        "A",
+					Expr:         replacer.Replace(spec.query),
+					LegendFormat: n.Node.Identity.Name(),
+				}},
+				FieldConfig: &dashboardFieldConfig{Defaults: dashboardDefaults{Unit: spec.unit}},
+			})
+		}
+		if len(row) == 0 {
+			return
+		}
+
+		panels = append(panels, dashboardPanel{
+			Title:   n.Path + " (" + n.Node.Type + ")",
+			Type:    "row",
+			GridPos: dashboardGridPos{H: 1, W: 24, Y: y},
+		})
+		y++
+		width := 24 / len(row)
+		for i := range row {
+			row[i].GridPos = dashboardGridPos{H: 8, W: width, X: i * width, Y: y}
+		}
+		panels = append(panels, row...)
+		y += 8
+	})
+	for i := range panels {
+		panels[i].ID = i + 1
+	}
+
+	return json.MarshalIndent(map[string]any{
+		"title":         title,
+		"uid":           opts.UID,
+		"tags":          []string{"pipz"},
+		"schemaVersion": 39,
+		"editable":      true,
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"panels":        panels,
+	}, "", "  ")
+}
@@ -0,0 +1,154 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestGrafanaDashboard(t *testing.T) {
+	type panel struct {
+		Title   string `json:"title"`
+		Type    string `json:"type"`
+		GridPos struct {
+			W int `json:"w"`
+			X int `json:"x"`
+			Y int `json:"y"`
+		} `json:"gridPos"`
+		Targets []struct {
+			Expr string `json:"expr"`
+		} `json:"targets"`
+		ID int `json:"id"`
+	}
+	type dashboard struct {
+		Title  string  `json:"title"`
+		UID    string  `json:"uid"`
+		Panels []panel `json:"panels"`
+	}
+
+	noop := func(_ context.Context, n int) int { return n }
+	charge := Transform(NewIdentity("charge", ""), noop)
+	breaker := NewCircuitBreaker(NewIdentity("breaker", ""), charge, 5, time.Minute)
+	seq := NewSequence(NewIdentity("checkout", ""), Transform(NewIdentity("validate", ""), noop), breaker)
+	schema := NewSchema(seq.Schema())
+
+	generate := func(t *testing.T, opts DashboardOptions) dashboard {
+		t.Helper()
+		raw, err := GrafanaDashboard(schema, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var d dashboard
+		if err := json.Unmarshal(raw, &d); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return d
+	}
+	queries := DashboardQueries{
+		Rate:         `rate(processed{path="{{path}}"}[5m])`,
+		Errors:       `rate(failed{name="{{name}}"}[5m])`,
+		Duration:     `duration{type="{{type}}"}`,
+		BreakerState: `breaker_state{path="{{path}}"}`,
+	}
+
+	t.Run("Row Per Node", func(t *testing.T) {
+		d := generate(t, DashboardOptions{UID: "checkout-dash", Queries: queries})
+		if d.Title != "checkout" || d.UID != "checkout-dash" {
+			t.Errorf("title/uid = %q/%q", d.Title, d.UID)
+		}
+		var rows []string
+		for _, p := range d.Panels {
+			if p.Type == "row" {
+				rows = append(rows, p.Title)
+			}
+		}
+		want := []string{
+			"checkout (sequence)",
+			"checkout/validate (processor)",
+			"checkout/breaker (circuitbreaker)",
+			"checkout/breaker/charge (processor)",
+		}
+		if len(rows) != len(want) {
+			t.Fatalf("rows = %v, want %v", rows, want)
+		}
+		for i := range want {
+			if rows[i] != want[i] {
+				t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+			}
+		}
+		for i, p := range d.Panels {
+			if p.ID != i+1 {
+				t.Errorf("panel %d has id %d", i, p.ID)
+			}
+		}
+	})
+
+	t.Run("Substitutes Placeholders", func(t *testing.T) {
+		d := generate(t, DashboardOptions{Queries: queries})
+		exprs := map[string]bool{}
+		for _, p := range d.Panels {
+			for _, target := range p.Targets {
+				exprs[target.Expr] = true
+			}
+		}
+		for _, expr := range []string{
+			`rate(processed{path="checkout/validate"}[5m])`,
+			`rate(failed{name="charge"}[5m])`,
+			`duration{type="circuitbreaker"}`,
+			`breaker_state{path="checkout/breaker"}`,
+		} {
+			if !exprs[expr] {
+				t.Errorf("missing query %s", expr)
+			}
+		}
+	})
+
+	t.Run("Breaker State Only For Breakers", func(t *testing.T) {
+		d := generate(t, DashboardOptions{Queries: queries})
+		var states int
+		for _, p := range d.Panels {
+			if p.Title == "Breaker State" {
+				states++
+				if p.Type != "stat" {
+					t.Errorf("breaker panel type = %q", p.Type)
+				}
+			}
+		}
+		if states != 1 {
+			t.Errorf("expected 1 breaker state panel, got %d", states)
+		}
+	})
+
+	t.Run("Empty Templates Omit Panels", func(t *testing.T) {
+		d := generate(t, DashboardOptions{Queries: DashboardQueries{Rate: "rate"}})
+		for _, p := range d.Panels {
+			if p.Type != "row" && p.Title != "Rate" {
+				t.Errorf("unexpected panel %q", p.Title)
+			}
+			if p.Title == "Rate" && p.GridPos.W != 24 {
+				t.Errorf("single panel width = %d, want 24", p.GridPos.W)
+			}
+		}
+
+		d = generate(t, DashboardOptions{})
+		if len(d.Panels) != 0 {
+			t.Errorf("expected no panels without queries, got %d", len(d.Panels))
+		}
+	})
+
+	t.Run("Types Filter", func(t *testing.T) {
+		d := generate(t, DashboardOptions{Queries: queries, Types: []string{"circuitbreaker"}})
+		if len(d.Panels) != 5 {
+			t.Fatalf("expected row and 4 panels, got %d", len(d.Panels))
+		}
+		if d.Panels[0].Title != "checkout/breaker (circuitbreaker)" {
+			t.Errorf("row = %q", d.Panels[0].Title)
+		}
+		for i, p := range d.Panels[1:] {
+			if p.GridPos.Y != 1 || p.GridPos.X != i*6 || p.GridPos.W != 6 {
+				t.Errorf("panel %d grid = %+v", i, p.GridPos)
+			}
+		}
+	})
+}
@@ -0,0 +1,257 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// QualityCheck is one weighted rule of a DataQuality stage, such as a
+// completeness, format, or range check. Weight sets its share of the
+// score; checks with no positive weight count once.
+type QualityCheck[T any] struct {
+	Check  func(T) bool
+	Name   string
+	Weight float64
+}
+
+// NewQualityCheck returns a QualityCheck named name passing when check
+// returns true.
+func NewQualityCheck[T any](name string, weight float64, check func(T) bool) QualityCheck[T] {
+	return QualityCheck[T]{Name: name, Weight: weight, Check: check}
+}
+
+// RequiredCheck returns a completeness check passing when field is not the
+// zero value.
+func RequiredCheck[T any, F comparable](name string, weight float64, field func(T) F) QualityCheck[T] {
+	return NewQualityCheck(name, weight, func(data T) bool {
+		var zero F
+		return field(data) != zero
+	})
+}
+
+// RangeCheck returns a range check passing when field is within [lo, hi].
+func RangeCheck[T any, F int | int64 | float64](name string, weight float64, field func(T) F, lo, hi F) QualityCheck[T] {
+	return NewQualityCheck(name, weight, func(data T) bool {
+		v := field(data)
+		return v >= lo && v <= hi
+	})
+}
+
+// QualityReport is the result of scoring one record.
+type QualityReport struct {
+	// Failed names the checks the record failed, in check order.
+	Failed []string `json:"failed,omitempty"`
+	// Score is the weighted share of checks passed, from 0 to 1.
+	Score float64 `json:"score"`
+}
+
+// DataQuality formalizes an ETL quality gate. It scores each record by the
+// weighted share of checks it passes and, when the score is below the
+// threshold set with SetRemediation, routes the record to a remediation
+// pipeline instead of passing it on. Records at or above the threshold
+// continue unchanged; without a remediation pipeline every record
+// continues and DataQuality only scores.
+//
+// The score is attached three ways: SetAttach stores it on the record,
+// every later signal of the run carries it as quality_score (see
+// AddSignalFields), and Report returns it to downstream processors without
+// rescoring within a compute scope.
+//
+// Example:
+//
+//	var QualityID = pipz.NewIdentity("customer-quality", "Scores customer records")
+//	quality := pipz.NewDataQuality(QualityID,
+//	    pipz.RequiredCheck("has-email", 2, func(c Customer) string { return c.Email }),
+//	    pipz.NewQualityCheck("valid-email", 2, func(c Customer) bool { return emailPattern.MatchString(c.Email) }),
+//	    pipz.RangeCheck("plausible-age", 1, func(c Customer) int { return c.Age }, 0, 120),
+//	).SetRemediation(0.8, remediate).SetAttach(func(c Customer, r pipz.QualityReport) Customer {
+//	    c.QualityScore = r.Score
+//	    return c
+//	})
+type DataQuality[T any] struct {
+	remediation Chainable[T]
+	attach      func(T, QualityReport) T
+	report      Derived[T, QualityReport]
+	identity    Identity
+	checks      []QualityCheck[T]
+	threshold   float64
+	mu          sync.RWMutex
+	remediated  atomic.Int64
+	closeOnce   sync.Once
+	closeErr    error
+}
+
+// NewDataQuality creates a DataQuality stage scoring records with checks.
+func NewDataQuality[T any](identity Identity, checks ...QualityCheck[T]) *DataQuality[T] {
+	d := &DataQuality[T]{
+		identity: identity,
+		checks:   checks,
+	}
+	d.report = NewDerived("pipz.quality/"+identity.ID().String(), func(_ context.Context, data T) (QualityReport, error) {
+		return d.Score(data), nil
+	})
+	return d
+}
+
+// Process implements the Chainable interface.
+// Scores the record and routes it to remediation if it falls short.
+func (d *DataQuality[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, d.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, d, d.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	d.mu.RLock()
+	remediation := d.remediation
+	attach := d.attach
+	threshold := d.threshold
+	d.mu.RUnlock()
+
+	report := d.Report(ctx, data)
+	AddSignalFields(ctx, StaticFields(FieldQualityScore.Field(report.Score)))
+	if attach != nil {
+		data = attach(data, report)
+	}
+	if remediation == nil || report.Score >= threshold {
+		return data, nil
+	}
+
+	d.remediated.Add(1)
+	capitan.Warn(ctx, SignalQualityRemediated,
+		FieldName.Field(d.identity.Name()),
+		FieldIdentityID.Field(d.identity.ID().String()),
+		FieldQualityScore.Field(report.Score),
+	)
+	result, err = remediation.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, d.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{d.identity},
+		}
+	}
+	return result, nil
+}
+
+// Score runs the checks against data.
+func (d *DataQuality[T]) Score(data T) QualityReport {
+	d.mu.RLock()
+	checks := d.checks
+	d.mu.RUnlock()
+
+	report := QualityReport{Score: 1}
+	var total, passed float64
+	for _, check := range checks {
+		weight := check.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		total += weight
+		if check.Check(data) {
+			passed += weight
+		} else {
+			report.Failed = append(report.Failed, check.Name)
+		}
+	}
+	if total > 0 {
+		report.Score = passed / total
+	}
+	return report
+}
+
+// Report returns data's quality report. Within a compute scope, such as a
+// Pipeline run, the checks run once, so processors after the stage read the
+// score it computed instead of rescoring.
+func (d *DataQuality[T]) Report(ctx context.Context, data T) QualityReport {
+	report, _ := d.report.Get(ctx, data) //nolint:errcheck // scoring never fails
+	return report
+}
+
+// SetRemediation routes records scoring below threshold to remediation.
+func (d *DataQuality[T]) SetRemediation(threshold float64, remediation Chainable[T]) *DataQuality[T] {
+	noteChildren(d.identity, remediation)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.threshold = threshold
+	d.remediation = remediation
+	return d
+}
+
+// SetAttach sets the function storing the report on each record.
+func (d *DataQuality[T]) SetAttach(attach func(T, QualityReport) T) *DataQuality[T] {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.attach = attach
+	return d
+}
+
+// AddCheck appends a check.
+func (d *DataQuality[T]) AddCheck(check QualityCheck[T]) *DataQuality[T] {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	checks := make([]QualityCheck[T], len(d.checks), len(d.checks)+1)
+	copy(checks, d.checks)
+	d.checks = append(checks, check)
+	return d
+}
+
+// Remediated returns the number of records routed to remediation.
+func (d *DataQuality[T]) Remediated() int64 {
+	return d.remediated.Load()
+}
+
+// Identity returns the identity of this stage.
+func (d *DataQuality[T]) Identity() Identity {
+	return d.identity
+}
+
+// Schema returns a Node representing this stage in the pipeline schema.
+func (d *DataQuality[T]) Schema() Node {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	names := make([]string, len(d.checks))
+	for i, check := range d.checks {
+		names[i] = check.Name
+	}
+	var flow DataQualityFlow
+	if d.remediation != nil {
+		remediation := d.remediation.Schema()
+		flow.Remediation = &remediation
+	}
+	return Node{
+		Identity: d.identity,
+		Type:     "dataquality",
+		Flow:     flow,
+		Metadata: map[string]any{
+			"checks":    names,
+			"threshold": d.threshold,
+		},
+	}
+}
+
+// Close gracefully shuts down the stage and its remediation pipeline.
+// Close is idempotent - multiple calls return the same result.
+func (d *DataQuality[T]) Close() error {
+	d.closeOnce.Do(func() {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+		if d.remediation != nil {
+			d.closeErr = d.remediation.Close()
+		}
+	})
+	return d.closeErr
+}
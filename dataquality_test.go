@@ -0,0 +1,154 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDataQuality(t *testing.T) {
+	type customer struct {
+		Email   string
+		Age     int
+		Quality float64
+	}
+	var checked atomic.Int64
+	newStage := func() *DataQuality[customer] {
+		return NewDataQuality(NewIdentity("customer-quality", ""),
+			RequiredCheck("has-email", 2, func(c customer) string { return c.Email }),
+			NewQualityCheck("checked", 0, func(customer) bool {
+				checked.Add(1)
+				return true
+			}),
+			RangeCheck("plausible-age", 1, func(c customer) int { return c.Age }, 0, 120),
+		)
+	}
+	ctx := context.Background()
+
+	t.Run("Weighted Score", func(t *testing.T) {
+		stage := newStage()
+		if r := stage.Score(customer{Email: "a@b.c", Age: 30}); r.Score != 1 || len(r.Failed) != 0 {
+			t.Errorf("complete record: %+v", r)
+		}
+		r := stage.Score(customer{Age: 200})
+		if r.Score != 0.25 {
+			t.Errorf("score = %v, want 0.25", r.Score)
+		}
+		if len(r.Failed) != 2 || r.Failed[0] != "has-email" || r.Failed[1] != "plausible-age" {
+			t.Errorf("failed = %v", r.Failed)
+		}
+	})
+
+	t.Run("No Checks Scores One", func(t *testing.T) {
+		if r := NewDataQuality[customer](NewIdentity("empty", "")).Score(customer{}); r.Score != 1 {
+			t.Errorf("score = %v", r.Score)
+		}
+	})
+
+	t.Run("Passes Without Remediation", func(t *testing.T) {
+		stage := newStage()
+		result, err := stage.Process(ctx, customer{Age: 200})
+		if err != nil || result.Age != 200 {
+			t.Errorf("unexpected result %+v, %v", result, err)
+		}
+	})
+
+	t.Run("Routes Low Quality To Remediation", func(t *testing.T) {
+		var remediated atomic.Int64
+		remediate := Transform(NewIdentity("remediate", ""), func(_ context.Context, c customer) customer {
+			remediated.Add(1)
+			c.Email = "unknown@example.com"
+			return c
+		})
+		stage := newStage().SetRemediation(0.8, remediate).SetAttach(func(c customer, r QualityReport) customer {
+			c.Quality = r.Score
+			return c
+		})
+
+		result, err := stage.Process(ctx, customer{Email: "a@b.c", Age: 30})
+		if err != nil || result.Quality != 1 || remediated.Load() != 0 {
+			t.Errorf("good record: %+v, %v, remediated %d", result, err, remediated.Load())
+		}
+
+		result, err = stage.Process(ctx, customer{Age: 30})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Email != "unknown@example.com" || result.Quality != 0.5 {
+			t.Errorf("remediated record: %+v", result)
+		}
+		if stage.Remediated() != 1 {
+			t.Errorf("remediated = %d", stage.Remediated())
+		}
+	})
+
+	t.Run("Remediation Error Has Path", func(t *testing.T) {
+		failing := Apply(NewIdentity("remediate", ""), func(_ context.Context, c customer) (customer, error) {
+			return c, errors.New("cannot fix")
+		})
+		stage := newStage().SetRemediation(0.8, failing)
+		_, err := stage.Process(ctx, customer{})
+		var pipeErr *Error[customer]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "customer-quality" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Report Reuses Score Within Run", func(t *testing.T) {
+		stage := newStage()
+		var downstream QualityReport
+		read := Effect(NewIdentity("read", ""), func(ctx context.Context, c customer) error {
+			downstream = stage.Report(ctx, c)
+			return nil
+		})
+		pipeline := NewPipeline(NewIdentity("ingest", ""), NewSequence(NewIdentity("steps", ""), stage, read))
+
+		before := checked.Load()
+		if _, err := pipeline.Process(ctx, customer{Email: "a@b.c", Age: 30}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := checked.Load() - before; got != 1 {
+			t.Errorf("checks ran %d times, want 1", got)
+		}
+		if downstream.Score != 1 {
+			t.Errorf("downstream score = %v", downstream.Score)
+		}
+	})
+
+	t.Run("Score Attached To Signal Fields", func(t *testing.T) {
+		stage := newStage()
+		var fields int
+		probe := Effect(NewIdentity("probe", ""), func(ctx context.Context, _ customer) error {
+			for _, f := range SignalFieldsFromContext(ctx) {
+				if f.Key().Name() == FieldQualityScore.Name() && f.Value() == 0.5 {
+					fields++
+				}
+			}
+			return nil
+		})
+		pipeline := NewPipeline(NewIdentity("ingest", ""), NewSequence(NewIdentity("steps", ""), stage, probe))
+		if _, err := pipeline.Process(ctx, customer{Age: 30}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fields != 1 {
+			t.Errorf("expected quality_score signal field, found %d", fields)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		stage := newStage()
+		if flow, ok := stage.Schema().Flow.(DataQualityFlow); !ok || flow.Remediation != nil {
+			t.Errorf("unexpected flow without remediation: %+v", stage.Schema().Flow)
+		}
+		stage.SetRemediation(0.5, Transform(NewIdentity("remediate", ""), func(_ context.Context, c customer) customer { return c }))
+		node := stage.Schema()
+		flow, ok := DataQualityKey.From(node)
+		if !ok || flow.Remediation == nil || flow.Remediation.Identity.Name() != "remediate" {
+			t.Errorf("unexpected flow: %+v", node.Flow)
+		}
+		if names, _ := node.Metadata["checks"].([]string); len(names) != 3 {
+			t.Errorf("checks metadata = %v", node.Metadata["checks"])
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// PastDueError reports an item whose own deadline had already passed when
+// it reached a Deadline connector. It unwraps to context.DeadlineExceeded,
+// so Error.IsTimeout reports true.
+type PastDueError struct {
+	Deadline time.Time
+	Overdue  time.Duration
+}
+
+// Error implements the error interface.
+func (e *PastDueError) Error() string {
+	return fmt.Sprintf("past due by %v (deadline %s)", e.Overdue, e.Deadline.Format(time.RFC3339))
+}
+
+// Unwrap returns context.DeadlineExceeded.
+func (*PastDueError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Deadline bounds processing by a deadline carried in the data itself, as
+// queue messages often do. It reads the deadline with the supplied function
+// and runs the processor under a context that expires then; a deadline
+// already in the past fails immediately with a PastDueError, without
+// running the processor. A zero deadline means none and runs the processor
+// under the caller's context.
+//
+// Unlike Timeout, Deadline does not abandon a processor that ignores its
+// context: it propagates the deadline and waits for the processor to
+// return, so downstream calls honor the message's SLA.
+//
+// Example:
+//
+//	var SLAID = pipz.NewIdentity("order-sla", "Honors the deadline carried by each order")
+//	sla := pipz.NewDeadline(SLAID, func(o Order) time.Time {
+//	    return o.RespondBy
+//	}, fulfilment)
+type Deadline[T any] struct {
+	processor Chainable[T]
+	deadline  func(T) time.Time
+	clock     clockz.Clock
+	identity  Identity
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewDeadline creates a Deadline connector reading each item's deadline
+// with deadline.
+func NewDeadline[T any](identity Identity, deadline func(T) time.Time, processor Chainable[T]) *Deadline[T] {
+	return &Deadline[T]{
+		identity:  identity,
+		deadline:  deadline,
+		processor: processor,
+	}
+}
+
+// Process implements the Chainable interface.
+func (d *Deadline[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, d.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, d, d.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	d.mu.RLock()
+	processor := d.processor
+	deadline := d.deadline
+	clock := d.getClock()
+	d.mu.RUnlock()
+
+	start := clock.Now()
+	if due := deadline(data); !due.IsZero() {
+		remaining := due.Sub(start)
+		if remaining <= 0 {
+			capitan.Warn(ctx, SignalDeadlinePastDue,
+				FieldName.Field(d.identity.Name()),
+				FieldIdentityID.Field(d.identity.ID().String()),
+				FieldDuration.Field((-remaining).Seconds()),
+			)
+			return data, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(data),
+				Err:       &PastDueError{Deadline: due, Overdue: -remaining},
+				Path:      []Identity{d.identity},
+				Timeout:   true,
+			}
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, remaining)
+		defer cancel()
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, d.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{d.identity},
+			Duration:  clock.Since(start),
+			Timeout:   errors.Is(err, context.DeadlineExceeded),
+			Canceled:  errors.Is(err, context.Canceled),
+		}
+	}
+	return result, nil
+}
+
+// SetDeadline updates the function reading each item's deadline.
+func (d *Deadline[T]) SetDeadline(deadline func(T) time.Time) *Deadline[T] {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deadline = deadline
+	return d
+}
+
+// SetProcessor updates the bounded processor.
+func (d *Deadline[T]) SetProcessor(processor Chainable[T]) *Deadline[T] {
+	noteChildren(d.identity, processor)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.processor = processor
+	return d
+}
+
+// WithClock sets a custom clock for testing.
+func (d *Deadline[T]) WithClock(clock clockz.Clock) *Deadline[T] {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.clock = clock
+	return d
+}
+
+// getClock returns the clock to use.
+func (d *Deadline[T]) getClock() clockz.Clock {
+	if d.clock == nil {
+		return clockz.RealClock
+	}
+	return d.clock
+}
+
+// Identity returns the identity of this connector.
+func (d *Deadline[T]) Identity() Identity {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (d *Deadline[T]) Schema() Node {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return Node{
+		Identity: d.identity,
+		Type:     "deadline",
+		Flow:     DeadlineFlow{Processor: d.processor.Schema()},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (d *Deadline[T]) Close() error {
+	d.closeOnce.Do(func() {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+		d.closeErr = d.processor.Close()
+	})
+	return d.closeErr
+}
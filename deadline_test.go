@@ -0,0 +1,127 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+type slaMessage struct {
+	RespondBy time.Time
+	Body      string
+}
+
+func TestDeadline(t *testing.T) {
+	respondBy := func(m slaMessage) time.Time { return m.RespondBy }
+	var seen time.Time
+	record := Apply(NewIdentity("record", ""), func(ctx context.Context, m slaMessage) (slaMessage, error) {
+		seen, _ = ctx.Deadline()
+		m.Body = "handled"
+		return m, nil
+	})
+
+	t.Run("Derives Context Deadline", func(t *testing.T) {
+		due := time.Now().Add(time.Minute)
+		seen = time.Time{}
+		d := NewDeadline(NewIdentity("sla", ""), respondBy, record)
+		result, err := d.Process(context.Background(), slaMessage{RespondBy: due})
+		if err != nil || result.Body != "handled" {
+			t.Fatalf("expected success, got %+v (%v)", result, err)
+		}
+		if diff := seen.Sub(due); seen.IsZero() || diff > time.Millisecond || diff < -time.Millisecond {
+			t.Errorf("expected context deadline near %v, got %v", due, seen)
+		}
+	})
+
+	t.Run("Zero Deadline Runs Unbounded", func(t *testing.T) {
+		seen = time.Time{}
+		d := NewDeadline(NewIdentity("sla", ""), respondBy, record)
+		if _, err := d.Process(context.Background(), slaMessage{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !seen.IsZero() {
+			t.Errorf("expected no deadline, got %v", seen)
+		}
+	})
+
+	t.Run("Past Due Fails Immediately", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		ran := false
+		d := NewDeadline(NewIdentity("sla", ""), respondBy,
+			Effect(NewIdentity("work", ""), func(context.Context, slaMessage) error {
+				ran = true
+				return nil
+			}),
+		).WithClock(clock)
+
+		_, err := d.Process(context.Background(), slaMessage{RespondBy: clock.Now().Add(-3 * time.Second)})
+		var pastDue *PastDueError
+		if !errors.As(err, &pastDue) || pastDue.Overdue != 3*time.Second {
+			t.Fatalf("expected PastDueError overdue 3s, got %v", err)
+		}
+		var pipeErr *Error[slaMessage]
+		if !errors.As(err, &pipeErr) || !pipeErr.IsTimeout() || pipeErr.Path[0].Name() != "sla" {
+			t.Errorf("expected timeout pipz error at sla, got %v", err)
+		}
+		if ran {
+			t.Error("processor should not run for a past-due item")
+		}
+	})
+
+	t.Run("Expires During Processing", func(t *testing.T) {
+		slow := Apply(NewIdentity("slow", ""), func(ctx context.Context, m slaMessage) (slaMessage, error) {
+			<-ctx.Done()
+			return m, ctx.Err()
+		})
+		d := NewDeadline(NewIdentity("sla", ""), respondBy, slow)
+		_, err := d.Process(context.Background(), slaMessage{RespondBy: time.Now().Add(20 * time.Millisecond)})
+
+		var pipeErr *Error[slaMessage]
+		if !errors.As(err, &pipeErr) || !pipeErr.IsTimeout() {
+			t.Fatalf("expected timeout error, got %v", err)
+		}
+		if len(pipeErr.Path) != 2 || pipeErr.Path[1].Name() != "slow" {
+			t.Errorf("unexpected path: %v", pipeErr.Path)
+		}
+	})
+
+	t.Run("Emits Past Due Signal", func(t *testing.T) {
+		var name string
+		listener := capitan.Hook(SignalDeadlinePastDue, func(_ context.Context, e *capitan.Event) {
+			name, _ = FieldName.From(e)
+		})
+		defer listener.Close()
+
+		d := NewDeadline(NewIdentity("order-sla", ""), respondBy, record)
+		_, _ = d.Process(context.Background(), slaMessage{RespondBy: time.Now().Add(-time.Second)}) //nolint:errcheck // signal checked below
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if name != "order-sla" {
+			t.Errorf("expected name 'order-sla', got %q", name)
+		}
+	})
+
+	t.Run("Setters And Schema", func(t *testing.T) {
+		d := NewDeadline(NewIdentity("sla", ""), respondBy, record).
+			SetDeadline(func(slaMessage) time.Time { return time.Time{} }).
+			SetProcessor(Transform(NewIdentity("noop", ""), func(_ context.Context, m slaMessage) slaMessage { return m }))
+		if _, err := d.Process(context.Background(), slaMessage{RespondBy: time.Now().Add(-time.Hour)}); err != nil {
+			t.Errorf("expected replaced deadline func to disable the bound, got %v", err)
+		}
+
+		node := d.Schema()
+		flow, ok := DeadlineKey.From(node)
+		if !ok || node.Type != "deadline" || flow.Processor.Identity.Name() != "noop" {
+			t.Errorf("unexpected schema: %+v", node)
+		}
+		if err := d.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	})
+}
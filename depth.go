@@ -0,0 +1,143 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxDepth is the connector nesting depth allowed when the active
+// Policy does not set MaxDepth. It is far deeper than any hand-built
+// pipeline while still failing long before the goroutine stack is exhausted.
+const DefaultMaxDepth = 1000
+
+var (
+	// ErrCycleDetected is returned when a connector is re-entered while it
+	// is already on the active processing path, meaning the composition
+	// contains itself directly or indirectly.
+	ErrCycleDetected = errors.New("cycle detected")
+	// ErrMaxDepthExceeded is returned when connector nesting exceeds the
+	// active Policy's MaxDepth (or DefaultMaxDepth).
+	ErrMaxDepthExceeded = errors.New("max depth exceeded")
+)
+
+// recursionPossible is set once a connector has been given a child that
+// contains it, directly or indirectly. Until then no composition can
+// re-enter a connector, so connectors track the processing path only when
+// the active Policy sets MaxDepth. It stays set, as cycles are not tracked
+// as they are taken apart.
+var recursionPossible atomic.Bool
+
+// noteChildren records that parent is being given children, checking their
+// schemas for parent to find a cycle about to be closed. Setters call it
+// before taking their lock, while the schemas still terminate. Cycles can
+// only be closed this way, as a constructor's children cannot contain the
+// connector being built; once one exists, schemas may not terminate, so no
+// further checks are made.
+func noteChildren[T any](parent Identity, children ...Chainable[T]) {
+	if recursionPossible.Load() {
+		return
+	}
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		if NewSchema(child.Schema()).Find(func(n Node) bool { return n.Identity.ID() == parent.ID() }) != nil {
+			recursionPossible.Store(true)
+			return
+		}
+	}
+}
+
+// depthFrame records one connector on the active processing path.
+type depthFrame struct {
+	parent *depthFrame
+	node   any
+	depth  int
+	limit  int
+	cycles bool
+}
+
+// depthKey is the context key for the innermost depth frame.
+type depthKey struct{}
+
+// enterDepth guards node (the connector instance) against re-entering
+// itself and runaway nesting, and returns the context for its children.
+// Every connector calls it on entry, so that a composition containing itself
+// fails with ErrCycleDetected, and nesting beyond the active Policy's
+// MaxDepth fails with ErrMaxDepthExceeded, instead of overflowing the stack.
+// The path is only tracked while it is needed: once a cycle has been closed
+// (see noteChildren), or while the Policy sets MaxDepth. Limits are resolved
+// from the Policy once, at the outermost tracking connector. The outermost
+// connector also starts the execution's correlation, and every connector
+// consults the FaultHook, if one is installed.
+func enterDepth[T any](ctx context.Context, node any, identity Identity, data T) (context.Context, *Error[T]) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = withCorrelation(ctx, "")
+
+	parent, _ := ctx.Value(depthKey{}).(*depthFrame)
+	var policy Policy
+	if parent == nil {
+		policy = PolicyFromContext(ctx)
+		if policy.MaxDepth <= 0 && !recursionPossible.Load() {
+			return checkFault(ctx, identity, data)
+		}
+	}
+
+	frame := &depthFrame{parent: parent, node: node, depth: 1}
+	if parent != nil {
+		frame.depth = parent.depth + 1
+		frame.limit = parent.limit
+		frame.cycles = parent.cycles
+	} else {
+		frame.limit = policy.MaxDepth
+		if frame.limit <= 0 {
+			frame.limit = DefaultMaxDepth
+		}
+		frame.cycles = policy.AllowRecursion
+	}
+
+	var guardErr error
+	switch {
+	case frame.depth > frame.limit:
+		guardErr = fmt.Errorf("%w: depth %d exceeds limit %d", ErrMaxDepthExceeded, frame.depth, frame.limit)
+	case !frame.cycles && parent.contains(node):
+		guardErr = fmt.Errorf("%w: %s is already on the processing path", ErrCycleDetected, identity.Name())
+	}
+	if guardErr != nil {
+		return ctx, &Error[T]{
+			Err:       guardErr,
+			InputData: errorInput(data),
+			Path:      []Identity{identity},
+			Timestamp: time.Now(),
+		}
+	}
+
+	return checkFault(context.WithValue(ctx, depthKey{}, frame), identity, data)
+}
+
+// contains reports whether node appears on the path ending at f.
+func (f *depthFrame) contains(node any) bool {
+	for ; f != nil; f = f.parent {
+		if f.node == node {
+			return true
+		}
+	}
+	return false
+}
+
+// depthFrom returns how many connectors are on the processing path tracked
+// in ctx, or zero when the path is not tracked.
+func depthFrom(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	if f, ok := ctx.Value(depthKey{}).(*depthFrame); ok {
+		return f.depth
+	}
+	return 0
+}
@@ -0,0 +1,127 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDepthGuard(t *testing.T) {
+	double := Transform(NewIdentity("double", ""), func(_ context.Context, v int) int { return v * 2 })
+
+	t.Run("Detects Indirect Cycle", func(t *testing.T) {
+		outer := NewSequence[int](NewIdentity("outer", ""))
+		inner := NewSequence(NewIdentity("inner", ""), double, outer)
+		outer.Register(inner)
+
+		_, err := outer.Process(context.Background(), 1)
+		if !errors.Is(err, ErrCycleDetected) {
+			t.Fatalf("expected ErrCycleDetected, got %v", err)
+		}
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 3 {
+			t.Fatalf("expected path outer -> inner -> outer, got %v", err)
+		}
+	})
+
+	t.Run("Reuse Without Nesting Is Not A Cycle", func(t *testing.T) {
+		step := NewSequence(NewIdentity("step", ""), double)
+		seq := NewSequence(NewIdentity("seq", ""), step, step)
+
+		result, err := seq.Process(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != 4 {
+			t.Errorf("expected 4, got %d", result)
+		}
+	})
+
+	t.Run("Max Depth From Policy", func(t *testing.T) {
+		var chain Chainable[int] = double
+		for i := 0; i < 5; i++ {
+			chain = NewSequence(NewIdentity("level", ""), chain)
+		}
+
+		ctx := WithPolicy(context.Background(), Policy{MaxDepth: 3})
+		if _, err := chain.Process(ctx, 1); !errors.Is(err, ErrMaxDepthExceeded) {
+			t.Fatalf("expected ErrMaxDepthExceeded, got %v", err)
+		}
+		if _, err := chain.Process(context.Background(), 1); err != nil {
+			t.Fatalf("expected default depth to allow 5 levels, got %v", err)
+		}
+	})
+
+	t.Run("Allow Recursion Still Bounded", func(t *testing.T) {
+		countdown := NewSwitch(NewIdentity("countdown", ""), func(_ context.Context, v int) string {
+			if v <= 0 {
+				return "done"
+			}
+			return "again"
+		})
+		decrement := NewSequence(NewIdentity("decrement", ""),
+			Transform(NewIdentity("dec", ""), func(_ context.Context, v int) int { return v - 1 }),
+			countdown,
+		)
+		countdown.AddRoute("again", decrement)
+
+		if _, err := countdown.Process(context.Background(), 3); !errors.Is(err, ErrCycleDetected) {
+			t.Fatalf("expected ErrCycleDetected by default, got %v", err)
+		}
+
+		ctx := WithPolicy(context.Background(), Policy{AllowRecursion: true})
+		result, err := countdown.Process(ctx, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != 0 {
+			t.Errorf("expected 0, got %d", result)
+		}
+
+		ctx = WithPolicy(context.Background(), Policy{AllowRecursion: true, MaxDepth: 10})
+		if _, err := countdown.Process(ctx, 100); !errors.Is(err, ErrMaxDepthExceeded) {
+			t.Fatalf("expected ErrMaxDepthExceeded, got %v", err)
+		}
+	})
+
+	t.Run("Setter Closing A Cycle", func(t *testing.T) {
+		defer recursionPossible.Store(recursionPossible.Load())
+		recursionPossible.Store(false)
+
+		outer := NewSequence(NewIdentity("outer", ""), double)
+		if _, err := outer.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		outer.Register(NewSequence(NewIdentity("unrelated", ""), double))
+		if recursionPossible.Load() {
+			t.Fatal("expected no cycle from a child not containing the sequence")
+		}
+		outer.Register(NewSequence(NewIdentity("inner", ""), outer))
+		if !recursionPossible.Load() {
+			t.Fatal("expected the cycle to be found when it is closed")
+		}
+		if _, err := outer.Process(context.Background(), 1); !errors.Is(err, ErrCycleDetected) {
+			t.Fatalf("expected ErrCycleDetected, got %v", err)
+		}
+	})
+
+	t.Run("Depth Tracked Under MaxDepth", func(t *testing.T) {
+		var depth int
+		probe := Effect(NewIdentity("probe", ""), func(ctx context.Context, _ int) error {
+			depth = depthFrom(ctx)
+			return nil
+		})
+		seq := NewSequence(NewIdentity("outer", ""), NewSequence(NewIdentity("inner", ""), probe))
+
+		ctx := WithPolicy(context.Background(), Policy{MaxDepth: 10})
+		if _, err := seq.Process(ctx, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if depth != 2 {
+			t.Errorf("expected depth 2, got %d", depth)
+		}
+		if depthFrom(context.Background()) != 0 {
+			t.Error("expected zero depth outside connectors")
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// errDerivedAbandoned is reported to callers waiting on a computation that
+// panicked.
+var errDerivedAbandoned = errors.New("derived computation panicked")
+
+// computeScopeKey is the context key for the compute scope.
+type computeScopeKey struct{}
+
+// computeScope holds the Derived values computed during one root
+// invocation.
+type computeScope struct {
+	cells map[string]*computeCell
+	mu    sync.Mutex
+}
+
+// computeCell is one Derived value, complete once done is closed.
+type computeCell struct {
+	value any
+	err   error
+	done  chan struct{}
+}
+
+// WithComputeScope returns a context in which Derived values are computed
+// at most once. Pipeline opens one for each Process call, so this is only
+// needed when processing without a Pipeline. A context that already
+// carries a scope is returned unchanged, keeping the scope of the root
+// invocation.
+func WithComputeScope(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(computeScopeKey{}).(*computeScope); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, computeScopeKey{}, &computeScope{cells: make(map[string]*computeCell)})
+}
+
+// Derived is a named value computed from the data, such as a sentiment,
+// urgency, or fraud score, that several processors of one pipeline need.
+// Within a compute scope the first Get computes it and every later Get,
+// from any branch, returns the same result; concurrent branches asking at
+// once wait for the one computation. Errors are not kept, so a retried
+// processor computes again.
+//
+// Outside a compute scope every Get computes. Names are shared by every
+// Derived in the scope, so give each a distinct name.
+//
+// Example:
+//
+//	var fraudScore = pipz.NewDerived("fraud-score", func(ctx context.Context, o Order) (float64, error) {
+//	    return scorer.Score(ctx, o)
+//	})
+//
+//	review := pipz.NewFilter(ReviewID, func(ctx context.Context, o Order) bool {
+//	    score, err := fraudScore.Get(ctx, o)
+//	    return err != nil || score > 0.8
+//	}, manualReview)
+//	hold := pipz.Apply(HoldID, func(ctx context.Context, o Order) (Order, error) {
+//	    score, err := fraudScore.Get(ctx, o) // computed once per Process call
+//	    o.Held = score > 0.5
+//	    return o, err
+//	})
+type Derived[T, V any] struct {
+	fn   func(context.Context, T) (V, error)
+	name string
+}
+
+// NewDerived creates a Derived value computed by fn.
+func NewDerived[T, V any](name string, fn func(context.Context, T) (V, error)) Derived[T, V] {
+	return Derived[T, V]{name: name, fn: fn}
+}
+
+// Name returns the name the value is shared under.
+func (d Derived[T, V]) Name() string {
+	return d.name
+}
+
+// Get returns the value for the current compute scope, computing it from
+// data if no processor has yet.
+func (d Derived[T, V]) Get(ctx context.Context, data T) (V, error) {
+	var zero V
+	scope, ok := ctx.Value(computeScopeKey{}).(*computeScope)
+	if !ok {
+		return d.fn(ctx, data)
+	}
+
+	scope.mu.Lock()
+	if cell, found := scope.cells[d.name]; found {
+		scope.mu.Unlock()
+		select {
+		case <-cell.done:
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+		if cell.err != nil {
+			return zero, cell.err
+		}
+		value, typed := cell.value.(V)
+		if !typed {
+			return zero, fmt.Errorf("derived value %q is %T, not %T", d.name, cell.value, zero)
+		}
+		return value, nil
+	}
+	cell := &computeCell{done: make(chan struct{})}
+	scope.cells[d.name] = cell
+	scope.mu.Unlock()
+
+	completed := false
+	defer func() {
+		if !completed {
+			cell.err = errDerivedAbandoned
+		}
+		if cell.err != nil {
+			scope.mu.Lock()
+			delete(scope.cells, d.name)
+			scope.mu.Unlock()
+		}
+		close(cell.done)
+	}()
+	value, err := d.fn(ctx, data)
+	cell.value, cell.err, completed = value, err, true
+	return value, err
+}
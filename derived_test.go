@@ -0,0 +1,146 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDerived(t *testing.T) {
+	t.Run("Computed Once Per Pipeline Run", func(t *testing.T) {
+		var calls atomic.Int64
+		length := NewDerived("length", func(_ context.Context, s string) (int, error) {
+			calls.Add(1)
+			return len(s), nil
+		})
+		use := func(name string) Chainable[string] {
+			return Apply(NewIdentity(name, ""), func(ctx context.Context, s string) (string, error) {
+				_, err := length.Get(ctx, s)
+				return s, err
+			})
+		}
+		pipeline := NewPipeline(NewIdentity("p", ""), NewSequence(NewIdentity("seq", ""), use("a"), use("b"), use("c")))
+
+		if _, err := pipeline.Process(context.Background(), "hello"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls.Load() != 1 {
+			t.Fatalf("expected one computation, got %d", calls.Load())
+		}
+		if _, err := pipeline.Process(context.Background(), "hello"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls.Load() != 2 {
+			t.Errorf("expected a fresh scope per run, got %d computations", calls.Load())
+		}
+	})
+
+	t.Run("Concurrent Branches Share One Computation", func(t *testing.T) {
+		var calls atomic.Int64
+		slow := NewDerived("slow", func(_ context.Context, s string) (string, error) {
+			calls.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			return strings.ToUpper(s), nil
+		})
+		ctx := WithComputeScope(context.Background())
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if v, err := slow.Get(ctx, "a"); err != nil || v != "A" {
+					t.Errorf("unexpected result %q, %v", v, err)
+				}
+			}()
+		}
+		wg.Wait()
+		if calls.Load() != 1 {
+			t.Errorf("expected one computation, got %d", calls.Load())
+		}
+	})
+
+	t.Run("Nested Pipelines Keep Root Scope", func(t *testing.T) {
+		var calls atomic.Int64
+		d := NewDerived("d", func(context.Context, int) (int, error) {
+			calls.Add(1)
+			return 1, nil
+		})
+		get := Apply(NewIdentity("get", ""), func(ctx context.Context, v int) (int, error) {
+			_, err := d.Get(ctx, v)
+			return v, err
+		})
+		inner := NewPipeline(NewIdentity("inner", ""), get)
+		outer := NewPipeline(NewIdentity("outer", ""), NewSequence(NewIdentity("seq", ""), get, inner))
+		if _, err := outer.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("expected inner pipeline to reuse the root scope, got %d computations", calls.Load())
+		}
+	})
+
+	t.Run("Errors Are Not Kept", func(t *testing.T) {
+		var calls atomic.Int64
+		flaky := NewDerived("flaky", func(context.Context, int) (int, error) {
+			if calls.Add(1) == 1 {
+				return 0, errors.New("unavailable")
+			}
+			return 42, nil
+		})
+		ctx := WithComputeScope(context.Background())
+		if _, err := flaky.Get(ctx, 0); err == nil {
+			t.Fatal("expected first computation to fail")
+		}
+		if v, err := flaky.Get(ctx, 0); err != nil || v != 42 {
+			t.Errorf("expected recomputation after error, got %d, %v", v, err)
+		}
+	})
+
+	t.Run("Without Scope Always Computes", func(t *testing.T) {
+		var calls atomic.Int64
+		d := NewDerived("d", func(context.Context, int) (int, error) {
+			calls.Add(1)
+			return 0, nil
+		})
+		_, _ = d.Get(context.Background(), 0) //nolint:errcheck // counted below
+		_, _ = d.Get(context.Background(), 0) //nolint:errcheck // counted below
+		if calls.Load() != 2 {
+			t.Errorf("expected computation on every Get, got %d", calls.Load())
+		}
+	})
+
+	t.Run("Type Mismatch", func(t *testing.T) {
+		ctx := WithComputeScope(context.Background())
+		asInt := NewDerived("shared", func(context.Context, int) (int, error) { return 1, nil })
+		asString := NewDerived("shared", func(context.Context, int) (string, error) { return "1", nil })
+		if _, err := asInt.Get(ctx, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := asString.Get(ctx, 0); err == nil || !strings.Contains(err.Error(), `"shared"`) {
+			t.Errorf("expected type mismatch error, got %v", err)
+		}
+	})
+
+	t.Run("Panic Releases Waiters", func(t *testing.T) {
+		ctx := WithComputeScope(context.Background())
+		var calls atomic.Int64
+		d := NewDerived("boom", func(context.Context, int) (int, error) {
+			if calls.Add(1) == 1 {
+				panic("boom")
+			}
+			return 7, nil
+		})
+		func() {
+			defer func() { _ = recover() }() //nolint:errcheck // panic is expected
+			_, _ = d.Get(ctx, 0)             //nolint:errcheck // panics
+		}()
+		if v, err := d.Get(ctx, 0); err != nil || v != 7 {
+			t.Errorf("expected recomputation after panic, got %d, %v", v, err)
+		}
+	})
+}
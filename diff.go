@@ -0,0 +1,206 @@
+package pipz
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ChangeKind classifies a node-level difference between two schemas.
+type ChangeKind string
+
+// Schema change kinds.
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// SettingChange records a single setting that differs between two nodes.
+// The "type" key reports a node type change, "version" a change in the
+// identity's declared version, and "order" reordered children; all other
+// keys come from node metadata.
+type SettingChange struct {
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+	Key    string `json:"key"`
+}
+
+// NodeChange records one added, removed, or changed node.
+// Path is the slash-separated chain of names from the root; repeated sibling
+// names are disambiguated with a "#n" suffix.
+type NodeChange struct {
+	Path     string          `json:"path"`
+	Kind     ChangeKind      `json:"kind"`
+	Type     string          `json:"type"`
+	Settings []SettingChange `json:"settings,omitempty"`
+}
+
+// SchemaDiff is a structured comparison of two pipeline schemas.
+type SchemaDiff struct {
+	Changes []NodeChange `json:"changes"`
+}
+
+// Empty reports whether the schemas are equivalent.
+func (d SchemaDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// String renders the diff one change per line, prefixed with + (added),
+// - (removed), or ~ (changed).
+func (d SchemaDiff) String() string {
+	var b strings.Builder
+	for _, c := range d.Changes {
+		switch c.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "+ %s (%s)\n", c.Path, c.Type)
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "- %s (%s)\n", c.Path, c.Type)
+		default:
+			fmt.Fprintf(&b, "~ %s (%s)\n", c.Path, c.Type)
+			for _, s := range c.Settings {
+				fmt.Fprintf(&b, "    %s: %v -> %v\n", s.Key, s.Before, s.After)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Diff compares the schemas of two pipelines.
+// Nodes are matched by their name path rather than identity ID, so two
+// independently constructed pipelines (for example, the running pipeline
+// and one built from a new config) compare meaningfully.
+//
+// Example:
+//
+//	diff := pipz.Diff(running, candidate)
+//	if !diff.Empty() {
+//	    log.Printf("pipeline changes:\n%s", diff)
+//	}
+func Diff[T any](a, b Chainable[T]) SchemaDiff {
+	return DiffSchemas(a.Schema(), b.Schema())
+}
+
+// DiffSchemas compares two schema trees. See Diff.
+func DiffSchemas(a, b Node) SchemaDiff {
+	before := flattenSchema(a)
+	after := flattenSchema(b)
+
+	afterByPath := make(map[string]flatNode, len(after))
+	for _, n := range after {
+		afterByPath[n.path] = n
+	}
+	beforeByPath := make(map[string]flatNode, len(before))
+	for _, n := range before {
+		beforeByPath[n.path] = n
+	}
+
+	var diff SchemaDiff
+	for _, old := range before {
+		updated, ok := afterByPath[old.path]
+		if !ok {
+			diff.Changes = append(diff.Changes, NodeChange{Path: old.path, Kind: ChangeRemoved, Type: old.node.Type})
+			continue
+		}
+		if settings := diffNodes(old, updated, afterByPath); len(settings) > 0 {
+			diff.Changes = append(diff.Changes, NodeChange{
+				Path:     old.path,
+				Kind:     ChangeChanged,
+				Type:     updated.node.Type,
+				Settings: settings,
+			})
+		}
+	}
+	for _, updated := range after {
+		if _, ok := beforeByPath[updated.path]; !ok {
+			diff.Changes = append(diff.Changes, NodeChange{Path: updated.path, Kind: ChangeAdded, Type: updated.node.Type})
+		}
+	}
+	return diff
+}
+
+// flatNode is a schema node with its resolved path and child paths.
+type flatNode struct {
+	node     Node
+	path     string
+	children []string
+}
+
+// flattenSchema lists nodes in pre-order with their name paths.
+func flattenSchema(root Node) []flatNode {
+	var out []flatNode
+	index := make(map[string]int)
+	NewSchema(root).WalkPaths(func(n SchemaNode) {
+		if n.Parent != nil {
+			parent := index[n.ParentPath()]
+			out[parent].children = append(out[parent].children, n.Path)
+		}
+		index[n.Path] = len(out)
+		out = append(out, flatNode{node: n.Node, path: n.Path})
+	})
+	return out
+}
+
+// diffNodes compares the settings of two matched nodes.
+func diffNodes(a, b flatNode, afterByPath map[string]flatNode) []SettingChange {
+	var settings []SettingChange
+	if a.node.Type != b.node.Type {
+		settings = append(settings, SettingChange{Key: "type", Before: a.node.Type, After: b.node.Type})
+	}
+	if a.node.Identity.Version() != b.node.Identity.Version() {
+		settings = append(settings, SettingChange{Key: "version", Before: a.node.Identity.Version(), After: b.node.Identity.Version()})
+	}
+
+	keys := make(map[string]bool)
+	for k := range a.node.Metadata {
+		keys[k] = true
+	}
+	for k := range b.node.Metadata {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		before, after := a.node.Metadata[k], b.node.Metadata[k]
+		if !reflect.DeepEqual(before, after) {
+			settings = append(settings, SettingChange{Key: k, Before: before, After: after})
+		}
+	}
+
+	// Report reordering of children present in both trees. Additions and
+	// removals are reported on the children themselves.
+	var common []string
+	for _, c := range a.children {
+		if _, ok := afterByPath[c]; ok {
+			common = append(common, c)
+		}
+	}
+	var reordered []string
+	inBefore := make(map[string]bool, len(a.children))
+	for _, c := range a.children {
+		inBefore[c] = true
+	}
+	for _, c := range b.children {
+		if inBefore[c] {
+			reordered = append(reordered, c)
+		}
+	}
+	if !slices.Equal(common, reordered) {
+		settings = append(settings, SettingChange{Key: "order", Before: baseNames(common), After: baseNames(reordered)})
+	}
+	return settings
+}
+
+// baseNames strips the parent path from child paths.
+func baseNames(paths []string) []string {
+	names := make([]string, len(paths))
+	for i, p := range paths {
+		names[i] = p[strings.LastIndex(p, "/")+1:]
+	}
+	return names
+}
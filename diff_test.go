@@ -0,0 +1,85 @@
+package pipz
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	step := func(name string) Chainable[int] {
+		return Transform(NewIdentity(name, ""), func(_ context.Context, v int) int { return v })
+	}
+
+	t.Run("Identical Pipelines", func(t *testing.T) {
+		a := NewSequence(NewIdentity("root", ""), step("a"), step("b"))
+		b := NewSequence(NewIdentity("root", ""), step("a"), step("b"))
+		if diff := Diff[int](a, b); !diff.Empty() {
+			t.Errorf("expected empty diff, got:\n%s", diff)
+		}
+	})
+
+	t.Run("Added And Removed", func(t *testing.T) {
+		a := NewSequence(NewIdentity("root", ""), step("a"), step("b"))
+		b := NewSequence(NewIdentity("root", ""), step("a"), step("c"))
+		diff := Diff[int](a, b)
+		if len(diff.Changes) != 2 {
+			t.Fatalf("expected 2 changes, got:\n%s", diff)
+		}
+		if diff.Changes[0].Kind != ChangeRemoved || diff.Changes[0].Path != "root/b" {
+			t.Errorf("expected root/b removed, got %+v", diff.Changes[0])
+		}
+		if diff.Changes[1].Kind != ChangeAdded || diff.Changes[1].Path != "root/c" {
+			t.Errorf("expected root/c added, got %+v", diff.Changes[1])
+		}
+	})
+
+	t.Run("Changed Settings", func(t *testing.T) {
+		a := NewSequence(NewIdentity("root", ""), NewTimeout(NewIdentity("deadline", ""), step("a"), time.Second))
+		b := NewSequence(NewIdentity("root", ""), NewTimeout(NewIdentity("deadline", ""), step("a"), 2*time.Second))
+		diff := Diff[int](a, b)
+		if len(diff.Changes) != 1 || diff.Changes[0].Kind != ChangeChanged || diff.Changes[0].Path != "root/deadline" {
+			t.Fatalf("expected deadline changed, got:\n%s", diff)
+		}
+		if len(diff.Changes[0].Settings) == 0 {
+			t.Error("expected setting changes")
+		}
+	})
+
+	t.Run("Changed Type", func(t *testing.T) {
+		a := NewSequence(NewIdentity("root", ""), step("a"))
+		b := NewSequence(NewIdentity("root", ""), NewSequence(NewIdentity("a", ""), step("inner")))
+		diff := Diff[int](a, b)
+		if len(diff.Changes) != 2 || diff.Changes[0].Settings[0].Key != "type" {
+			t.Errorf("expected type change, got:\n%s", diff)
+		}
+	})
+
+	t.Run("Reordered", func(t *testing.T) {
+		a := NewSequence(NewIdentity("root", ""), step("a"), step("b"))
+		b := NewSequence(NewIdentity("root", ""), step("b"), step("a"))
+		diff := Diff[int](a, b)
+		if len(diff.Changes) != 1 || diff.Changes[0].Path != "root" || diff.Changes[0].Settings[0].Key != "order" {
+			t.Errorf("expected order change on root, got:\n%s", diff)
+		}
+	})
+
+	t.Run("Duplicate Names", func(t *testing.T) {
+		a := NewSequence(NewIdentity("root", ""), step("a"), step("a"))
+		b := NewSequence(NewIdentity("root", ""), step("a"))
+		diff := Diff[int](a, b)
+		if len(diff.Changes) != 1 || diff.Changes[0].Path != "root/a#2" || diff.Changes[0].Kind != ChangeRemoved {
+			t.Errorf("expected root/a#2 removed, got:\n%s", diff)
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		a := NewSequence(NewIdentity("root", ""), step("a"))
+		b := NewSequence(NewIdentity("root", ""), step("b"))
+		out := Diff[int](a, b).String()
+		if !strings.Contains(out, "- root/a") || !strings.Contains(out, "+ root/b") {
+			t.Errorf("unexpected rendering:\n%s", out)
+		}
+	})
+}
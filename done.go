@@ -0,0 +1,55 @@
+package pipz
+
+import "errors"
+
+// ErrDone is matched by errors.Is for the early-exit signal returned by
+// Done.
+var ErrDone = errors.New("done")
+
+// doneError carries the value a processor finished with.
+type doneError[T any] struct {
+	value T
+}
+
+func (*doneError[T]) Error() string { return ErrDone.Error() }
+
+func (*doneError[T]) Unwrap() error { return ErrDone }
+
+// Done ends the enclosing Sequence successfully with value, skipping its
+// remaining steps. It is returned like an error so it can travel through
+// processors such as Apply, but Sequence and Pipeline turn it back into
+// (value, nil). Retry, Backoff, Fallback, CircuitBreaker, and Handle pass it
+// through without treating it as a failure.
+//
+// Done completes only the innermost Sequence, like a return from a block:
+// an outer Sequence continues with value after it. Use it to short-circuit
+// on a cache hit instead of nesting Fallbacks.
+//
+// Example:
+//
+//	fromCache := pipz.Apply(CacheID, func(ctx context.Context, q Quote) (Quote, error) {
+//	    if cached, ok := cache.Get(q.Key()); ok {
+//	        return pipz.Done(cached)
+//	    }
+//	    return q, nil
+//	})
+//	quote := pipz.NewSequence(QuoteID, fromCache, callProviders, store)
+func Done[T any](value T) (T, error) {
+	return value, &doneError[T]{value: value}
+}
+
+// doneValue extracts the value from an early exit returned by Done.
+func doneValue[T any](err error) (T, bool) {
+	var done *doneError[T]
+	if errors.As(err, &done) {
+		return done.value, true
+	}
+	var zero T
+	return zero, false
+}
+
+// isControl reports whether err is a control outcome, a skip or an early
+// exit, rather than a failure.
+func isControl(err error) bool {
+	return errors.Is(err, ErrSkip) || errors.Is(err, ErrDone)
+}
@@ -0,0 +1,115 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestDone(t *testing.T) {
+	cached := Apply(NewIdentity("cached", ""), func(_ context.Context, v int) (int, error) {
+		if v < 0 {
+			return Done(100)
+		}
+		return v, nil
+	})
+	var laterCalls atomic.Int32
+	double := Transform(NewIdentity("double", ""), func(_ context.Context, v int) int {
+		laterCalls.Add(1)
+		return v * 2
+	})
+
+	t.Run("Done Helper", func(t *testing.T) {
+		value, err := Done(7)
+		if value != 7 || !errors.Is(err, ErrDone) {
+			t.Errorf("expected 7 with ErrDone, got %d (%v)", value, err)
+		}
+		if got, ok := doneValue[int](err); !ok || got != 7 {
+			t.Errorf("expected done value 7, got %d (%v)", got, ok)
+		}
+		if _, ok := doneValue[string](err); ok {
+			t.Error("expected done value of another type not to match")
+		}
+	})
+
+	t.Run("Sequence Returns Early Without Error", func(t *testing.T) {
+		var doneAt string
+		listener := capitan.Hook(SignalSequenceDone, func(_ context.Context, e *capitan.Event) {
+			doneAt, _ = FieldProcessorName.From(e)
+		})
+		defer listener.Close()
+
+		laterCalls.Store(0)
+		seq := NewSequence(NewIdentity("seq", ""), cached, double)
+		result, err := seq.Process(context.Background(), -1)
+		if err != nil || result != 100 {
+			t.Fatalf("expected 100 without error, got %d (%v)", result, err)
+		}
+		if laterCalls.Load() != 0 {
+			t.Errorf("expected remaining steps to be skipped, ran %d", laterCalls.Load())
+		}
+		if kept, keptErr := seq.Process(context.Background(), 4); keptErr != nil || kept != 8 {
+			t.Errorf("expected normal value to continue, got %d (%v)", kept, keptErr)
+		}
+
+		if drainErr := listener.Drain(context.Background()); drainErr != nil {
+			t.Fatalf("drain failed: %v", drainErr)
+		}
+		if doneAt != "cached" {
+			t.Errorf("expected done signal from 'cached', got %q", doneAt)
+		}
+	})
+
+	t.Run("Outer Sequence Continues", func(t *testing.T) {
+		inner := NewSequence(NewIdentity("inner", ""), cached, double)
+		outer := NewSequence(NewIdentity("outer", ""), inner, double)
+		result, err := outer.Process(context.Background(), -1)
+		if err != nil || result != 200 {
+			t.Errorf("expected outer sequence to continue with 100, got %d (%v)", result, err)
+		}
+	})
+
+	t.Run("Connectors Pass It Through", func(t *testing.T) {
+		var calls atomic.Int32
+		finish := Apply(NewIdentity("finish", ""), func(_ context.Context, _ int) (int, error) {
+			calls.Add(1)
+			return Done(5)
+		})
+		var handled bool
+		handler := Effect(NewIdentity("handler", ""), func(context.Context, *Error[int]) error {
+			handled = true
+			return nil
+		})
+		wrapped := NewSequence(NewIdentity("seq", ""),
+			NewHandle(NewIdentity("handle", ""),
+				NewFallback(NewIdentity("fallback", ""),
+					NewRetry(NewIdentity("retry", ""), finish, 3),
+					double,
+				),
+				handler,
+			),
+			double,
+		)
+		result, err := wrapped.Process(context.Background(), 1)
+		if err != nil || result != 5 {
+			t.Errorf("expected 5 without error, got %d (%v)", result, err)
+		}
+		if calls.Load() != 1 || handled {
+			t.Errorf("expected one attempt and no handler, got %d (handled: %v)", calls.Load(), handled)
+		}
+	})
+
+	t.Run("Pipeline Root", func(t *testing.T) {
+		p := NewPipeline(NewIdentity("pipeline", ""), cached)
+		result, err := p.Process(context.Background(), -1)
+		if err != nil || result != 100 {
+			t.Errorf("expected 100 without error, got %d (%v)", result, err)
+		}
+		if stats := p.Stats(); stats.Failed != 0 || stats.Skipped != 0 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+}
@@ -21,7 +21,8 @@ import (
 //   - Validating without transformation
 //
 // Unlike Apply, Effect cannot transform data. Unlike Transform, it can fail.
-// This separation ensures side effects are explicit and testable.
+// This separation ensures side effects are explicit and testable. Effects are
+// ReadOnly, so fan-out connectors run them without cloning the input.
 //
 // Example:
 //
@@ -36,6 +37,7 @@ import (
 func Effect[T any](identity Identity, fn func(context.Context, T) error) Processor[T] {
 	return Processor[T]{
 		identity: identity,
+		readOnly: true,
 		fn: func(ctx context.Context, value T) (result T, err error) {
 			defer recoverFromPanic(&result, &err, identity, value)
 			start := time.Now()
@@ -44,7 +46,7 @@ func Effect[T any](identity Identity, fn func(context.Context, T) error) Process
 				var zero T
 				return zero, &Error[T]{
 					Path:      []Identity{identity},
-					InputData: value,
+					InputData: errorInput(value),
 					Err:       err,
 					Timestamp: time.Now(),
 					Duration:  time.Since(start),
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime/debug"
 	"strings"
 	"time"
 )
@@ -15,14 +16,34 @@ import (
 //
 // The Path field contains Identity values, enabling correlation between
 // error paths and schema definitions via the Identity.ID() UUIDs.
+//
+// InputData holds the input as limited by the RetentionPolicy, which can
+// redact, truncate, or omit it before errors reach logs.
+//
+// When the failure happened inside a Sequence, StepIndex is the index of the
+// step that failed and LastGood the value it received: the output of the
+// last successful step, or the Sequence input if the first step failed.
+// With nested Sequences both describe the innermost one, closest to the
+// failure. HasStep reports whether they are set.
+//
+// A Pipeline with SetMaxPathDepth cuts long paths down to the outermost and
+// innermost frames; PathTruncated counts the frames removed, and Error()
+// marks where they were.
 type Error[T any] struct {
-	Timestamp time.Time
-	InputData T
-	Err       error
-	Path      []Identity
-	Duration  time.Duration
-	Timeout   bool
-	Canceled  bool
+	Timestamp     time.Time
+	InputData     T
+	LastGood      T
+	Err           error
+	Path          []Identity
+	Duration      time.Duration
+	StepIndex     int
+	PathTruncated int
+	Timeout       bool
+	Canceled      bool
+	stepSet       bool
+	snapshot      *errorSnapshot[T]
+	pathBuf       []Identity
+	pathCut       int
 }
 
 const unknownPath = "unknown"
@@ -32,23 +53,19 @@ func (e *Error[T]) Error() string {
 	if e == nil {
 		return "<nil>"
 	}
-	pathStrs := make([]string, len(e.Path))
-	for i, id := range e.Path {
-		pathStrs[i] = id.Name()
-	}
-	path := strings.Join(pathStrs, " -> ")
+	path := e.pathString()
 	if path == "" {
 		path = unknownPath
 	}
 
 	if e.Timeout {
-		return fmt.Sprintf("%s timed out after %v: %v", path, e.Duration, e.Err)
+		return truncateMessage(fmt.Sprintf("%s timed out after %v: %v", path, e.Duration, e.Err))
 	}
 	if e.Canceled {
-		return fmt.Sprintf("%s canceled after %v: %v", path, e.Duration, e.Err)
+		return truncateMessage(fmt.Sprintf("%s canceled after %v: %v", path, e.Duration, e.Err))
 	}
 
-	return fmt.Sprintf("%s failed after %v: %v", path, e.Duration, e.Err)
+	return truncateMessage(fmt.Sprintf("%s failed after %v: %v", path, e.Duration, e.Err))
 }
 
 // Unwrap returns the underlying error, supporting error wrapping patterns.
@@ -61,6 +78,81 @@ func (e *Error[T]) Unwrap() error {
 	return e.Err
 }
 
+// pathString renders Path as "a -> b -> c", with a "...(+n)" marker where
+// truncated frames were removed.
+func (e *Error[T]) pathString() string {
+	names := make([]string, 0, len(e.Path)+1)
+	for i, id := range e.Path {
+		if e.PathTruncated > 0 && i == e.pathCut {
+			names = append(names, fmt.Sprintf("...(+%d)", e.PathTruncated))
+		}
+		names = append(names, id.Name())
+	}
+	return strings.Join(names, " -> ")
+}
+
+// truncatePath cuts Path to maxDepth frames, keeping the outermost and
+// innermost ones. A path already truncated is cut around its existing
+// marker so it has a single gap.
+func (e *Error[T]) truncatePath(maxDepth int) {
+	if maxDepth <= 0 || len(e.Path) <= maxDepth {
+		return
+	}
+	cut := len(e.Path) - maxDepth
+	at := maxDepth / 2
+	if e.PathTruncated > 0 {
+		at = min(max(at, e.pathCut-cut), e.pathCut)
+	}
+	path := make([]Identity, 0, maxDepth)
+	path = append(path, e.Path[:at]...)
+	path = append(path, e.Path[at+cut:]...)
+	e.Path = path
+	e.pathBuf = nil
+	e.pathCut = at
+	e.PathTruncated += cut
+}
+
+// prependPath adds identity to the front of Path as the error returns
+// through a connector. The first prepend copies Path into a buffer with room
+// for every connector still above on the processing path, so the remaining
+// levels fill it in place instead of allocating a slice each. Where the path
+// is not tracked, the buffer doubles as it fills instead.
+func (e *Error[T]) prependPath(ctx context.Context, identity Identity) {
+	if e.PathTruncated > 0 {
+		e.pathCut++
+	}
+	if free := len(e.pathBuf) - len(e.Path); free > 0 && len(e.Path) > 0 && &e.pathBuf[free] == &e.Path[0] {
+		e.pathBuf[free-1] = identity
+		e.Path = e.pathBuf[free-1:]
+		return
+	}
+	headroom := depthFrom(ctx) - 1
+	if headroom < 0 {
+		headroom = len(e.Path) + 1
+	}
+	buf := make([]Identity, headroom+1+len(e.Path))
+	buf[headroom] = identity
+	copy(buf[headroom+1:], e.Path)
+	e.pathBuf = buf
+	e.Path = buf[headroom:]
+}
+
+// HasStep reports whether a Sequence recorded StepIndex and LastGood.
+func (e *Error[T]) HasStep() bool {
+	return e != nil && e.stepSet
+}
+
+// setStep records the failing Sequence step unless a nested Sequence
+// already did.
+func (e *Error[T]) setStep(index int, lastGood T) {
+	if e.stepSet {
+		return
+	}
+	e.StepIndex = index
+	e.LastGood = stepInput(e.InputData, lastGood)
+	e.stepSet = true
+}
+
 // IsTimeout returns true if the error was caused by a timeout.
 // This includes both explicit timeout from the Timeout connector
 // and context deadline exceeded. Useful for implementing timeout-specific
@@ -86,64 +178,48 @@ func (e *Error[T]) IsCanceled() bool {
 // panicError is a security-focused panic recovery error.
 // It represents a panic that occurred during processing, with sensitive
 // information sanitized to prevent information leakage through panic messages.
+// The raw value and stack are kept so a Pipeline with a SanitizerPolicy can
+// render the message differently; they are never part of Error().
 type panicError struct {
+	value     any
 	identity  Identity
 	sanitized string
+	stack     []byte
+	policied  bool
+}
+
+// newPanicError captures a recovered panic value, and the stack that raised
+// it while a SanitizerPolicy that keeps stacks is set.
+func newPanicError(identity Identity, value any) *panicError {
+	var stack []byte
+	if stackKeepers.Load() > 0 {
+		stack = debug.Stack()
+	}
+	return &panicError{
+		identity:  identity,
+		value:     value,
+		stack:     stack,
+		sanitized: sanitizePanicMessage(value),
+	}
 }
 
 func (pe *panicError) Error() string {
+	if pe.identity.Name() == "" {
+		return pe.sanitized
+	}
 	return fmt.Sprintf("panic in processor %q: %s", pe.identity.Name(), pe.sanitized)
 }
 
+// Unwrap returns ErrPanic so recovered panics can be detected with errors.Is.
+func (*panicError) Unwrap() error {
+	return ErrPanic
+}
+
 // sanitizePanicMessage removes potentially sensitive information from panic messages.
 // This prevents accidental exposure of internal details, memory addresses, or
 // other sensitive data that might be contained in panic messages.
 func sanitizePanicMessage(panicValue interface{}) string {
-	if panicValue == nil {
-		return "unknown panic (nil value)"
-	}
-
-	msg := fmt.Sprintf("%v", panicValue)
-
-	// Remove memory addresses (replace hex digits after 0x)
-	for strings.Contains(msg, "0x") {
-		start := strings.Index(msg, "0x")
-		if start == -1 {
-			break
-		}
-		end := start + 2
-		// Find end of hex address
-		for end < len(msg) && ((msg[end] >= '0' && msg[end] <= '9') ||
-			(msg[end] >= 'a' && msg[end] <= 'f') ||
-			(msg[end] >= 'A' && msg[end] <= 'F')) {
-			end++
-		}
-		if end > start+2 {
-			msg = msg[:start] + "0x***" + msg[end:]
-		} else {
-			break
-		}
-	}
-
-	// Remove file paths that might contain sensitive directory names
-	if strings.Contains(msg, "/") || strings.Contains(msg, "\\") {
-		return "panic occurred (file path sanitized)"
-	}
-
-	// If message is very long, truncate to prevent excessive log spam
-	if len(msg) > 200 {
-		return "panic occurred (message truncated for security)"
-	}
-
-	// Remove any potential stack trace information and package addresses
-	if strings.Contains(msg, "goroutine") || strings.Contains(msg, "runtime.") ||
-		strings.Contains(msg, "sync.") || strings.Contains(msg, "net.") ||
-		strings.Contains(msg, "os.") || strings.Contains(msg, "fmt.") ||
-		strings.Contains(msg, "io.") {
-		return "panic occurred (stack trace sanitized)"
-	}
-
-	return fmt.Sprintf("panic occurred: %s", msg)
+	return SanitizerPolicy{}.sanitize(panicValue, nil)
 }
 
 // recoverFromPanic provides security-focused panic recovery for Process methods.
@@ -155,16 +231,33 @@ func sanitizePanicMessage(panicValue interface{}) string {
 //	defer recoverFromPanic(&result, &err, identity, inputData)
 func recoverFromPanic[T any](result *T, err *error, identity Identity, inputData T) {
 	if r := recover(); r != nil {
-		var zero T
-		*result = zero
-		*err = &Error[T]{
-			Path:      []Identity{identity},
-			InputData: inputData,
-			Err:       &panicError{identity: identity, sanitized: sanitizePanicMessage(r)},
-			Timestamp: time.Now(),
-			Duration:  0, // We don't track duration for panics
-			Timeout:   false,
-			Canceled:  false,
-		}
+		setPanicResult(result, err, identity, inputData, r)
+	}
+}
+
+// recoverFromInputPanic is recoverFromPanic for connectors that release
+// their input mid-call, such as Backoff with a Stash: the input is read
+// when a panic is recovered rather than when the call is deferred, so the
+// deferred call does not keep the payload alive.
+func recoverFromInputPanic[T any](result *T, err *error, identity Identity, inputData *T) {
+	if r := recover(); r != nil {
+		setPanicResult(result, err, identity, *inputData, r)
+	}
+}
+
+// setPanicResult converts a recovered panic into the zero result and an Error,
+// and records it with the panic circuit.
+func setPanicResult[T any](result *T, err *error, identity Identity, inputData T, r any) {
+	var zero T
+	*result = zero
+	*err = &Error[T]{
+		Path:      []Identity{identity},
+		InputData: errorInput(inputData),
+		Err:       newPanicError(identity, r),
+		Timestamp: time.Now(),
+		Duration:  0, // We don't track duration for panics
+		Timeout:   false,
+		Canceled:  false,
 	}
+	notePanic(identity)
 }
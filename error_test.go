@@ -2,6 +2,7 @@ package pipz
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -400,3 +401,130 @@ func TestError(t *testing.T) {
 		}
 	})
 }
+
+func TestErrorPrependPath(t *testing.T) {
+	names := func(path []Identity) string {
+		parts := make([]string, len(path))
+		for i, id := range path {
+			parts[i] = id.Name()
+		}
+		return strings.Join(parts, "/")
+	}
+
+	t.Run("Nested Connectors Build Path In Order", func(t *testing.T) {
+		var chain Chainable[int] = Apply(NewIdentity("leaf", ""), func(_ context.Context, v int) (int, error) {
+			return v, errors.New("boom")
+		})
+		for _, name := range []string{"c", "b", "a"} {
+			chain = NewSequence(NewIdentity(name, ""), chain)
+		}
+		_, err := chain.Process(context.Background(), 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected pipeline error, got %v", err)
+		}
+		if got := names(pipeErr.Path); got != "a/b/c/leaf" {
+			t.Errorf("expected a/b/c/leaf, got %s", got)
+		}
+	})
+
+	t.Run("Earlier Path Slices Are Unchanged", func(t *testing.T) {
+		e := &Error[int]{Path: []Identity{NewIdentity("leaf", "")}}
+		ctx := context.WithValue(context.Background(), depthKey{}, &depthFrame{depth: 3})
+		e.prependPath(ctx, NewIdentity("c", ""))
+		before := e.Path
+		e.prependPath(ctx, NewIdentity("b", ""))
+		if got := names(before); got != "c/leaf" {
+			t.Errorf("expected earlier slice c/leaf, got %s", got)
+		}
+		if got := names(e.Path); got != "b/c/leaf" {
+			t.Errorf("expected b/c/leaf, got %s", got)
+		}
+	})
+
+	t.Run("Replaced Path Is Copied", func(t *testing.T) {
+		e := &Error[int]{Path: []Identity{NewIdentity("leaf", "")}}
+		ctx := context.WithValue(context.Background(), depthKey{}, &depthFrame{depth: 3})
+		e.prependPath(ctx, NewIdentity("c", ""))
+		e.Path = []Identity{NewIdentity("other", "")}
+		e.prependPath(ctx, NewIdentity("b", ""))
+		if got := names(e.Path); got != "b/other" {
+			t.Errorf("expected b/other, got %s", got)
+		}
+	})
+
+	t.Run("Headroom Exhausted Reallocates", func(t *testing.T) {
+		e := &Error[int]{Path: []Identity{NewIdentity("leaf", "")}}
+		for _, name := range []string{"c", "b", "a"} {
+			e.prependPath(context.Background(), NewIdentity(name, ""))
+		}
+		if got := names(e.Path); got != "a/b/c/leaf" {
+			t.Errorf("expected a/b/c/leaf, got %s", got)
+		}
+	})
+}
+
+func TestErrorTruncatePath(t *testing.T) {
+	nested := func(depth int) Chainable[int] {
+		var chain Chainable[int] = Apply(NewIdentity("leaf", ""), func(_ context.Context, v int) (int, error) {
+			return v, errors.New("boom")
+		})
+		for i := depth; i > 0; i-- {
+			chain = NewSequence(NewIdentity(fmt.Sprintf("s%d", i), ""), chain)
+		}
+		return chain
+	}
+
+	t.Run("Pipeline Caps Path", func(t *testing.T) {
+		pipeline := NewPipeline(NewIdentity("p", ""), nested(6)).SetMaxPathDepth(4)
+		_, err := pipeline.Process(context.Background(), 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected pipeline error, got %v", err)
+		}
+		if len(pipeErr.Path) != 4 || pipeErr.PathTruncated != 3 {
+			t.Fatalf("expected 4 frames with 3 truncated, got %d and %d", len(pipeErr.Path), pipeErr.PathTruncated)
+		}
+		if !strings.HasPrefix(err.Error(), "s1 -> s2 -> ...(+3) -> s6 -> leaf failed") {
+			t.Errorf("unexpected message: %s", err.Error())
+		}
+		data, marshalErr := json.Marshal(pipeErr)
+		if marshalErr != nil || !strings.Contains(string(data), `"path_truncated":3`) {
+			t.Errorf("expected path_truncated in JSON, got %s, %v", data, marshalErr)
+		}
+	})
+
+	t.Run("Short Path Is Untouched", func(t *testing.T) {
+		pipeline := NewPipeline(NewIdentity("p", ""), nested(2)).SetMaxPathDepth(4)
+		_, err := pipeline.Process(context.Background(), 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 3 || pipeErr.PathTruncated != 0 {
+			t.Fatalf("expected untruncated path, got %v", err)
+		}
+		if strings.Contains(err.Error(), "...") {
+			t.Errorf("unexpected marker: %s", err.Error())
+		}
+	})
+
+	t.Run("Nested Pipelines Keep One Gap", func(t *testing.T) {
+		inner := NewPipeline(NewIdentity("inner", ""), nested(6)).SetMaxPathDepth(4)
+		var outer Chainable[int] = inner
+		for _, name := range []string{"o3", "o2", "o1"} {
+			outer = NewSequence(NewIdentity(name, ""), outer)
+		}
+		_, err := NewPipeline(NewIdentity("outer", ""), outer).SetMaxPathDepth(5).Process(context.Background(), 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected pipeline error, got %v", err)
+		}
+		if len(pipeErr.Path) != 5 || pipeErr.PathTruncated != 5 {
+			t.Fatalf("expected 5 frames with 5 truncated, got %d and %d", len(pipeErr.Path), pipeErr.PathTruncated)
+		}
+		if got := strings.Count(err.Error(), "..."); got != 1 {
+			t.Errorf("expected a single marker, got %d in %s", got, err.Error())
+		}
+		if !strings.HasPrefix(err.Error(), "o1 -> o2 -> o3 -> ...(+5) -> s6 -> leaf") {
+			t.Errorf("unexpected message: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package pipz
+
+import (
+	"reflect"
+	"unicode/utf8"
+)
+
+// truncatedSuffix marks error messages cut to RetentionPolicy.MaxMessageLength.
+const truncatedSuffix = "... (truncated)"
+
+// ErrorOptions limits what Error[T] retains and prints.
+//
+// Deprecated: Set the equivalent RetentionPolicy fields instead: Redact for
+// Scrub, MaxBytes for MaxInputBytes, MaxMessageLength, and OmitInput.
+type ErrorOptions struct {
+	// Scrub rewrites input data before it is stored. It must return a value
+	// of the same type; any other result stores the zero value instead.
+	Scrub func(any) any
+	// MaxInputBytes truncates string and []byte inputs (including named
+	// types over them) to this many bytes. Zero means no limit.
+	MaxInputBytes int
+	// MaxMessageLength truncates Error() messages to this many characters.
+	// Zero means no limit.
+	MaxMessageLength int
+	// OmitInput stores the zero value instead of input data.
+	OmitInput bool
+}
+
+// SetErrorOptions sets the RetentionPolicy fields equivalent to opts,
+// keeping the policy's other fields. Scrub becomes the policy's Redact, so
+// it also sees error messages and History entries; return values of types
+// it does not handle unchanged.
+//
+// Deprecated: Use SetRetentionPolicy.
+func SetErrorOptions(opts ErrorOptions) {
+	policy := CurrentRetentionPolicy()
+	policy.Redact = opts.Scrub
+	policy.MaxBytes = opts.MaxInputBytes
+	policy.MaxMessageLength = opts.MaxMessageLength
+	policy.OmitInput = opts.OmitInput
+	SetRetentionPolicy(policy)
+}
+
+// CurrentErrorOptions returns the ErrorOptions equivalent to the
+// process-wide RetentionPolicy.
+//
+// Deprecated: Use CurrentRetentionPolicy.
+func CurrentErrorOptions() ErrorOptions {
+	policy := CurrentRetentionPolicy()
+	return ErrorOptions{
+		Scrub:            policy.Redact,
+		MaxInputBytes:    policy.MaxBytes,
+		MaxMessageLength: policy.MaxMessageLength,
+		OmitInput:        policy.OmitInput,
+	}
+}
+
+// errorInput applies the RetentionPolicy to input data stored in an Error.
+func errorInput[T any](data T) T {
+	policy := retentionPolicy.Load()
+	if policy == nil {
+		return data
+	}
+	if policy.OmitInput || !inputSampler.take(policy.SampleRate) {
+		var zero T
+		return zero
+	}
+	return retainValue(*policy, data)
+}
+
+// stepInput applies the RetentionPolicy to the LastGood value of an Error
+// whose InputData is input. It follows the sampling decision already made
+// for input rather than sampling again, so an error keeps or drops both:
+// under a sampling policy LastGood is kept only if input was, a zero input
+// counting as dropped.
+func stepInput[T any](input, lastGood T) T {
+	policy := retentionPolicy.Load()
+	if policy == nil {
+		return lastGood
+	}
+	if policy.OmitInput || policy.samples() && reflect.ValueOf(&input).Elem().IsZero() {
+		var zero T
+		return zero
+	}
+	return retainValue(*policy, lastGood)
+}
+
+// RetainErrorInput applies the process-wide RetentionPolicy to input about
+// to be stored in an Error[T] built outside pipz, such as by a custom
+// connector, so it is omitted, sampled, redacted, and truncated like the
+// input of errors pipz builds.
+//
+// Example:
+//
+//	return data, &pipz.Error[Order]{
+//	    Timestamp: time.Now(),
+//	    InputData: pipz.RetainErrorInput(data),
+//	    Err:       ErrQueueFull,
+//	    Path:      []pipz.Identity{q.identity},
+//	}
+func RetainErrorInput[T any](data T) T {
+	return errorInput(data)
+}
+
+// truncateInput shortens string and byte-slice values to limit bytes.
+// Strings are cut at the last rune boundary within the limit, so they stay
+// valid UTF-8.
+func truncateInput[T any](data T, limit int) T {
+	v := reflect.ValueOf(&data).Elem()
+	switch {
+	case v.Kind() == reflect.String && v.Len() > limit:
+		s := v.String()
+		cut := limit
+		for cut > 0 && limit-cut < utf8.UTFMax && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		v.SetString(s[:cut])
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 && v.Len() > limit:
+		// Copy so the stored input does not alias the caller's buffer.
+		v.SetBytes(append([]byte(nil), v.Bytes()[:limit]...))
+	}
+	return data
+}
+
+// truncateMessage applies RetentionPolicy.MaxMessageLength to msg.
+func truncateMessage(msg string) string {
+	policy := retentionPolicy.Load()
+	if policy == nil || policy.MaxMessageLength <= 0 || utf8.RuneCountInString(msg) <= policy.MaxMessageLength {
+		return msg
+	}
+	runes := []rune(msg)
+	return string(runes[:policy.MaxMessageLength]) + truncatedSuffix
+}
@@ -0,0 +1,141 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type errorDataUser struct {
+	Name  string
+	Email string
+}
+
+func TestErrorRetention(t *testing.T) {
+	failing := func(_ context.Context, u errorDataUser) (errorDataUser, error) {
+		return u, errors.New("rejected")
+	}
+
+	t.Run("Defaults Keep Input", func(t *testing.T) {
+		defer SetRetentionPolicy(RetentionPolicy{})
+		id := NewIdentity("apply", "")
+		_, err := Apply(id, failing).Process(context.Background(), errorDataUser{Name: "ann", Email: "ann@example.com"})
+
+		var pipeErr *Error[errorDataUser]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected *Error, got %v", err)
+		}
+		if pipeErr.InputData.Email != "ann@example.com" {
+			t.Errorf("expected full input data, got %+v", pipeErr.InputData)
+		}
+	})
+
+	t.Run("Omit Input", func(t *testing.T) {
+		SetRetentionPolicy(RetentionPolicy{OmitInput: true})
+		defer SetRetentionPolicy(RetentionPolicy{})
+		id := NewIdentity("apply", "")
+		seq := NewSequence(NewIdentity("seq", ""), Apply(id, failing))
+		_, err := seq.Process(context.Background(), errorDataUser{Name: "ann", Email: "ann@example.com"})
+
+		var pipeErr *Error[errorDataUser]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected *Error, got %v", err)
+		}
+		if pipeErr.InputData != (errorDataUser{}) {
+			t.Errorf("expected zero input data, got %+v", pipeErr.InputData)
+		}
+		if len(pipeErr.Path) != 2 {
+			t.Errorf("expected path preserved, got %v", pipeErr.Path)
+		}
+	})
+
+	t.Run("Redact Input", func(t *testing.T) {
+		SetRetentionPolicy(RetentionPolicy{Redact: func(v any) any {
+			if u, ok := v.(errorDataUser); ok {
+				u.Email = "[redacted]"
+				return u
+			}
+			return v
+		}})
+		defer SetRetentionPolicy(RetentionPolicy{})
+		id := NewIdentity("apply", "")
+		_, err := Apply(id, failing).Process(context.Background(), errorDataUser{Name: "ann", Email: "ann@example.com"})
+
+		var pipeErr *Error[errorDataUser]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected *Error, got %v", err)
+		}
+		if pipeErr.InputData.Email != "[redacted]" || pipeErr.InputData.Name != "ann" {
+			t.Errorf("expected redacted input data, got %+v", pipeErr.InputData)
+		}
+	})
+
+	t.Run("Redact Wrong Type Stores Zero", func(t *testing.T) {
+		SetRetentionPolicy(RetentionPolicy{Redact: func(any) any { return "nope" }})
+		defer SetRetentionPolicy(RetentionPolicy{})
+
+		got := errorInput(errorDataUser{Name: "ann"})
+		if got != (errorDataUser{}) {
+			t.Errorf("expected zero value, got %+v", got)
+		}
+	})
+
+	t.Run("Max Input Bytes", func(t *testing.T) {
+		SetRetentionPolicy(RetentionPolicy{MaxBytes: 4})
+		defer SetRetentionPolicy(RetentionPolicy{})
+
+		if got := errorInput("abcdefgh"); got != "abcd" {
+			t.Errorf("expected truncated string, got %q", got)
+		}
+		if got := errorInput("abcé"); got != "abc" {
+			t.Errorf("expected the cut at a rune boundary, got %q", got)
+		}
+		type payload []byte
+		original := payload("abcdefgh")
+		got := errorInput(original)
+		if string(got) != "abcd" {
+			t.Errorf("expected truncated bytes, got %q", got)
+		}
+		got[0] = 'z'
+		if original[0] != 'a' {
+			t.Error("expected truncated bytes to be copied")
+		}
+		if got := errorInput(errorDataUser{Name: "longer than four"}); got.Name != "longer than four" {
+			t.Errorf("expected structs untouched, got %+v", got)
+		}
+	})
+
+	t.Run("Max Message Length", func(t *testing.T) {
+		SetRetentionPolicy(RetentionPolicy{MaxMessageLength: 10})
+		defer SetRetentionPolicy(RetentionPolicy{})
+		err := &Error[string]{
+			Err:  errors.New(strings.Repeat("x", 100)),
+			Path: []Identity{NewIdentity("stage", "")},
+		}
+
+		msg := err.Error()
+		if !strings.HasSuffix(msg, truncatedSuffix) {
+			t.Errorf("expected truncation suffix, got %q", msg)
+		}
+		if len(msg) != 10+len(truncatedSuffix) {
+			t.Errorf("expected 10 characters before suffix, got %q", msg)
+		}
+	})
+
+	t.Run("Deprecated Error Options", func(t *testing.T) {
+		SetRetentionPolicy(RetentionPolicy{TTL: time.Hour})
+		defer SetRetentionPolicy(RetentionPolicy{})
+		if got := CurrentErrorOptions(); got.OmitInput || got.MaxInputBytes != 0 {
+			t.Errorf("expected zero options, got %+v", got)
+		}
+		SetErrorOptions(ErrorOptions{MaxInputBytes: 8, OmitInput: true})
+		if got := CurrentErrorOptions(); got.MaxInputBytes != 8 || !got.OmitInput {
+			t.Errorf("expected stored options, got %+v", got)
+		}
+		if got := CurrentRetentionPolicy(); got.MaxBytes != 8 || !got.OmitInput || got.TTL != time.Hour {
+			t.Errorf("expected the options set on the policy, got %+v", got)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package pipz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Error categories reported by Error.Category.
+const (
+	ErrorCategorySkipped  = "skipped"
+	ErrorCategoryTimeout  = "timeout"
+	ErrorCategoryCanceled = "canceled"
+	ErrorCategoryPanic    = "panic"
+	ErrorCategoryRejected = "rejected"
+	ErrorCategoryError    = "error"
+)
+
+// maxLoggedInput bounds the encoded input in MarshalJSON and LogValue when
+// RetentionPolicy.MaxBytes is not set.
+const maxLoggedInput = 1024
+
+// rejectionErrors are failures where a processor deliberately refused the
+// input rather than breaking.
+var rejectionErrors = []error{
+	ErrGuardRejected,
+	ErrValidationFailed,
+	ErrUnacceptable,
+	ErrUnauthenticated,
+	ErrForbidden,
+	ErrNoConsent,
+	ErrPIIDetected,
+}
+
+// errorRecord is the stable structured form of an Error.
+type errorRecord struct {
+	Timestamp      time.Time       `json:"timestamp"`
+	Input          json.RawMessage `json:"input,omitempty"`
+	Error          string          `json:"error"`
+	Category       string          `json:"category"`
+	Processor      string          `json:"processor"`
+	ProcessorID    string          `json:"processor_id"`
+	InputType      string          `json:"input_type"`
+	Path           []ReportStep    `json:"path"`
+	PathTruncated  int             `json:"path_truncated,omitempty"`
+	DurationMS     float64         `json:"duration_ms"`
+	Timeout        bool            `json:"timeout"`
+	Canceled       bool            `json:"canceled"`
+	InputTruncated bool            `json:"input_truncated,omitempty"`
+}
+
+// Category classifies the failure for alerting and log filtering: skipped
+// (see ErrSkip), timeout, canceled, panic, rejected (a guard, validation,
+// verification, consent, PII, or authorization check refused the input), or
+// error.
+func (e *Error[T]) Category() string {
+	switch {
+	case e == nil:
+		return ""
+	case IsSkip(e.Err):
+		return ErrorCategorySkipped
+	case e.IsTimeout():
+		return ErrorCategoryTimeout
+	case e.IsCanceled():
+		return ErrorCategoryCanceled
+	case errors.Is(e.Err, ErrPanic):
+		return ErrorCategoryPanic
+	}
+	for _, target := range rejectionErrors {
+		if errors.Is(e.Err, target) {
+			return ErrorCategoryRejected
+		}
+	}
+	return ErrorCategoryError
+}
+
+// MarshalJSON encodes the error in a stable structured form for logs and
+// error pipelines:
+//
+//	{
+//	  "timestamp": "2025-01-01T12:00:00Z",
+//	  "error": "payment failed after 1.5s: card declined",
+//	  "category": "error",
+//	  "processor": "charge",
+//	  "processor_id": "…",
+//	  "path": [{"id": "…", "name": "checkout"}, {"id": "…", "name": "charge"}],
+//	  "duration_ms": 1500,
+//	  "timeout": false,
+//	  "canceled": false,
+//	  "input_type": "main.Order",
+//	  "input": {"id": "A-1"}
+//	}
+//
+// Processor is the last step on the path, where the failure originated.
+// Input holds Redacted() when the input implements Redactable, otherwise
+// the stored InputData (already limited by the RetentionPolicy). Inputs whose
+// encoding exceeds RetentionPolicy.MaxBytes, or 1KiB when unset, are cut
+// and emitted as a JSON string with input_truncated set; inputs that cannot
+// be encoded are left out.
+func (e *Error[T]) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(e.record())
+}
+
+// LogValue implements slog.LogValuer, logging the error as a group with the
+// same fields as MarshalJSON.
+//
+// Example:
+//
+//	var pipeErr *pipz.Error[Order]
+//	if errors.As(err, &pipeErr) {
+//	    logger.Error("order failed", "err", pipeErr)
+//	}
+func (e *Error[T]) LogValue() slog.Value {
+	if e == nil {
+		return slog.Value{}
+	}
+	r := e.record()
+	attrs := []slog.Attr{
+		slog.Time("timestamp", r.Timestamp),
+		slog.String("error", r.Error),
+		slog.String("category", r.Category),
+		slog.String("processor", r.Processor),
+		slog.String("processor_id", r.ProcessorID),
+		slog.String("path", e.pathString()),
+		slog.Float64("duration_ms", r.DurationMS),
+		slog.Bool("timeout", r.Timeout),
+		slog.Bool("canceled", r.Canceled),
+		slog.String("input_type", r.InputType),
+	}
+	if r.PathTruncated > 0 {
+		attrs = append(attrs, slog.Int("path_truncated", r.PathTruncated))
+	}
+	if r.Input != nil {
+		input := string(r.Input)
+		if r.InputTruncated {
+			// Log the cut text itself rather than its JSON quoting.
+			_ = json.Unmarshal(r.Input, &input) //nolint:errcheck // encoded by record
+		}
+		attrs = append(attrs, slog.String("input", input))
+		if r.InputTruncated {
+			attrs = append(attrs, slog.Bool("input_truncated", true))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// record builds the structured form of e.
+func (e *Error[T]) record() errorRecord {
+	r := errorRecord{
+		Timestamp:     e.Timestamp,
+		Error:         e.Error(),
+		Category:      e.Category(),
+		Path:          make([]ReportStep, len(e.Path)),
+		PathTruncated: e.PathTruncated,
+		DurationMS:    float64(e.Duration) / float64(time.Millisecond),
+		Timeout:       e.Timeout,
+		Canceled:      e.Canceled,
+		InputType:     fmt.Sprintf("%T", e.InputData),
+	}
+	for i, id := range e.Path {
+		r.Path[i] = ReportStep{ID: id.ID().String(), Name: id.Name()}
+	}
+	if n := len(e.Path); n > 0 {
+		r.Processor = e.Path[n-1].Name()
+		r.ProcessorID = e.Path[n-1].ID().String()
+	}
+
+	var input any = e.InputData
+	if red, ok := input.(Redactable); ok {
+		input = red.Redacted()
+	}
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return r
+	}
+	limit := CurrentRetentionPolicy().MaxBytes
+	if limit <= 0 {
+		limit = maxLoggedInput
+	}
+	if len(encoded) <= limit {
+		r.Input = encoded
+		return r
+	}
+	cut, err := json.Marshal(strings.ToValidUTF8(string(encoded[:limit]), "") + truncatedSuffix)
+	if err != nil {
+		return r
+	}
+	r.Input = cut
+	r.InputTruncated = true
+	return r
+}
@@ -0,0 +1,150 @@
+package pipz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorCategory(t *testing.T) {
+	tests := []struct {
+		err  *Error[int]
+		name string
+		want string
+	}{
+		{&Error[int]{Err: errors.New("boom")}, "Plain", ErrorCategoryError},
+		{&Error[int]{Err: context.DeadlineExceeded}, "Deadline", ErrorCategoryTimeout},
+		{&Error[int]{Err: errors.New("x"), Timeout: true}, "Timeout Flag", ErrorCategoryTimeout},
+		{&Error[int]{Err: context.Canceled}, "Canceled", ErrorCategoryCanceled},
+		{&Error[int]{Err: &panicError{sanitized: "panic occurred: x"}}, "Panic", ErrorCategoryPanic},
+		{&Error[int]{Err: fmt.Errorf("age: %w", ErrValidationFailed)}, "Rejected", ErrorCategoryRejected},
+		{nil, "Nil", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Category(); got != tt.want {
+				t.Errorf("Category() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorMarshalJSON(t *testing.T) {
+	checkout, charge := NewIdentity("checkout", ""), NewIdentity("charge", "")
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	pipeErr := &Error[TestData]{
+		Timestamp: at,
+		InputData: TestData{Value: 7},
+		Err:       errors.New("card declined"),
+		Path:      []Identity{checkout, charge},
+		Duration:  1500 * time.Millisecond,
+	}
+
+	t.Run("Stable Fields", func(t *testing.T) {
+		data, err := json.Marshal(pipeErr)
+		if err != nil {
+			t.Fatalf("marshal failed: %v", err)
+		}
+		var got map[string]any
+		if unmarshalErr := json.Unmarshal(data, &got); unmarshalErr != nil {
+			t.Fatalf("unmarshal failed: %v", unmarshalErr)
+		}
+		want := map[string]any{
+			"timestamp":    "2025-01-01T12:00:00Z",
+			"error":        "checkout -> charge failed after 1.5s: card declined",
+			"category":     "error",
+			"processor":    "charge",
+			"processor_id": charge.ID().String(),
+			"duration_ms":  1500.0,
+			"timeout":      false,
+			"canceled":     false,
+			"input_type":   "pipz.TestData",
+		}
+		for k, v := range want {
+			if got[k] != v {
+				t.Errorf("%s = %v, want %v", k, got[k], v)
+			}
+		}
+		path, ok := got["path"].([]any)
+		if !ok || len(path) != 2 || path[0].(map[string]any)["name"] != "checkout" {
+			t.Errorf("unexpected path: %v", got["path"])
+		}
+		if input, ok := got["input"].(map[string]any); !ok || input["Value"] != 7.0 {
+			t.Errorf("unexpected input: %v", got["input"])
+		}
+	})
+
+	t.Run("Redactable Input", func(t *testing.T) {
+		redacted := &Error[redactableOrder]{Err: errors.New("x"), InputData: redactableOrder{ID: "A-1", Card: "4111"}}
+		data, _ := json.Marshal(redacted) //nolint:errcheck // checked via content
+		if strings.Contains(string(data), "4111") || !strings.Contains(string(data), "A-1") {
+			t.Errorf("expected redacted input, got %s", data)
+		}
+	})
+
+	t.Run("Truncates Large Input", func(t *testing.T) {
+		big := &Error[string]{Err: errors.New("x"), InputData: strings.Repeat("a", 5000)}
+		data, _ := json.Marshal(big) //nolint:errcheck // checked via content
+		var got struct {
+			Input     string `json:"input"`
+			Truncated bool   `json:"input_truncated"`
+		}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("unmarshal failed: %v", err)
+		}
+		if !got.Truncated || !strings.HasSuffix(got.Input, truncatedSuffix) || len(got.Input) > maxLoggedInput+len(truncatedSuffix) {
+			t.Errorf("expected truncated input, got %d bytes (truncated=%v)", len(got.Input), got.Truncated)
+		}
+	})
+
+	t.Run("Unencodable Input Omitted", func(t *testing.T) {
+		data, err := json.Marshal(&Error[chan int]{Err: errors.New("x"), InputData: make(chan int)})
+		if err != nil || strings.Contains(string(data), `"input":`) {
+			t.Errorf("expected input omitted, got %s (%v)", data, err)
+		}
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		var nilErr *Error[int]
+		if data, err := json.Marshal(nilErr); err != nil || string(data) != "null" {
+			t.Errorf("expected null, got %s (%v)", data, err)
+		}
+	})
+}
+
+func TestErrorLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	pipeErr := &Error[int]{
+		Err:      context.DeadlineExceeded,
+		Path:     []Identity{NewIdentity("fetch", ""), NewIdentity("call", "")},
+		Timeout:  true,
+		Duration: time.Second,
+	}
+	logger.Error("failed", "err", pipeErr)
+
+	var got struct {
+		Err map[string]any `json:"err"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if got.Err["category"] != "timeout" || got.Err["path"] != "fetch -> call" || got.Err["processor"] != "call" || got.Err["input"] != "0" {
+		t.Errorf("unexpected log group: %v", got.Err)
+	}
+}
+
+type redactableOrder struct {
+	ID   string
+	Card string
+}
+
+func (o redactableOrder) Redacted() any {
+	return map[string]string{"id": o.ID}
+}
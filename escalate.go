@@ -0,0 +1,217 @@
+package pipz
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// Escalate gates an expensive error handler, such as paging or ticket
+// creation, behind an escalation policy. It is itself an error handler and
+// is passed to NewHandle in place of the handler it wraps.
+//
+// An error escalates when it matches the severity criteria, or when the
+// same processor (the last identity in the error's Path) has failed the
+// threshold number of times within the window. Escalating resets that
+// processor's count, so a persistent fault pages once per threshold rather
+// than on every failure. Errors that do not escalate are dropped.
+//
+// Without a threshold or severity criteria, every error escalates.
+//
+// Example:
+//
+//	var PageOnCallID = pipz.NewIdentity("page-oncall", "Pages on repeated payment failures")
+//	page := pipz.NewEscalate(PageOnCallID, pagerHandler).
+//	    SetThreshold(5, time.Minute).
+//	    SetSeverity(func(err *pipz.Error[Order]) bool {
+//	        return errors.Is(err, ErrFraudSuspected)
+//	    })
+//
+//	payments := pipz.NewHandle(PaymentsID, chargeCard, page)
+type Escalate[T any] struct {
+	handler   Chainable[*Error[T]]
+	severity  func(*Error[T]) bool
+	clock     clockz.Clock
+	failures  map[uuid.UUID][]time.Time
+	identity  Identity
+	threshold int
+	window    time.Duration
+	mu        sync.Mutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewEscalate creates an Escalate wrapping handler. Configure the policy
+// with SetThreshold and SetSeverity.
+func NewEscalate[T any](identity Identity, handler Chainable[*Error[T]]) *Escalate[T] {
+	return &Escalate[T]{
+		identity: identity,
+		handler:  handler,
+		failures: make(map[uuid.UUID][]time.Time),
+	}
+}
+
+// Process implements the Chainable interface. It records the failure and
+// invokes the handler only when the error escalates.
+func (e *Escalate[T]) Process(ctx context.Context, pipeErr *Error[T]) (result *Error[T], err error) {
+	defer recoverFromPanic(&result, &err, e.identity, pipeErr)
+
+	ctx, guardErr := enterDepth(ctx, e, e.identity, pipeErr)
+	if guardErr != nil {
+		return pipeErr, guardErr
+	}
+
+	severe, count, escalate, source := e.record(pipeErr)
+
+	if !escalate {
+		capitan.Info(ctx, SignalEscalateSuppressed,
+			FieldName.Field(e.identity.Name()),
+			FieldIdentityID.Field(e.identity.ID().String()),
+			FieldProcessorName.Field(source),
+			FieldFailures.Field(count),
+		)
+		return pipeErr, nil
+	}
+
+	capitan.Warn(ctx, SignalEscalateTriggered,
+		FieldName.Field(e.identity.Name()),
+		FieldIdentityID.Field(e.identity.ID().String()),
+		FieldProcessorName.Field(source),
+		FieldFailures.Field(count),
+		FieldSevere.Field(severe),
+	)
+
+	e.mu.Lock()
+	handler := e.handler
+	e.mu.Unlock()
+	return handler.Process(ctx, pipeErr)
+}
+
+// record counts the failure against its source processor and decides
+// whether it escalates.
+func (e *Escalate[T]) record(pipeErr *Error[T]) (severe bool, count int, escalate bool, source string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var key uuid.UUID
+	if pipeErr != nil && len(pipeErr.Path) > 0 {
+		last := pipeErr.Path[len(pipeErr.Path)-1]
+		key, source = last.ID(), last.Name()
+	}
+
+	severe = e.severity != nil && pipeErr != nil && e.severity(pipeErr)
+	if e.threshold <= 0 {
+		return severe, 1, severe || e.severity == nil, source
+	}
+
+	now := e.getClock().Now()
+	times := e.failures[key]
+	kept := times[:0]
+	for _, t := range times {
+		if e.window <= 0 || now.Sub(t) < e.window {
+			kept = append(kept, t)
+		}
+	}
+	kept = append(kept, now)
+	count = len(kept)
+
+	escalate = severe || count >= e.threshold
+	if escalate {
+		delete(e.failures, key)
+	} else {
+		e.failures[key] = kept
+	}
+	return severe, count, escalate, source
+}
+
+// SetThreshold escalates once the same processor has failed n times within
+// window. A window of zero counts failures without expiry. Changing the
+// threshold clears existing counts.
+func (e *Escalate[T]) SetThreshold(n int, window time.Duration) *Escalate[T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.threshold = n
+	e.window = window
+	e.failures = make(map[uuid.UUID][]time.Time)
+	return e
+}
+
+// SetSeverity escalates any error for which severe returns true,
+// regardless of the threshold.
+func (e *Escalate[T]) SetSeverity(severe func(*Error[T]) bool) *Escalate[T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.severity = severe
+	return e
+}
+
+// SetHandler updates the handler invoked on escalation.
+func (e *Escalate[T]) SetHandler(handler Chainable[*Error[T]]) *Escalate[T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.handler = handler
+	return e
+}
+
+// Reset clears all failure counts.
+func (e *Escalate[T]) Reset() *Escalate[T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = make(map[uuid.UUID][]time.Time)
+	return e
+}
+
+// WithClock sets a custom clock for testing.
+func (e *Escalate[T]) WithClock(clock clockz.Clock) *Escalate[T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.clock = clock
+	return e
+}
+
+// getClock returns the clock to use.
+func (e *Escalate[T]) getClock() clockz.Clock {
+	if e.clock == nil {
+		return clockz.RealClock
+	}
+	return e.clock
+}
+
+// Identity returns the identity of this connector.
+func (e *Escalate[T]) Identity() Identity {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (e *Escalate[T]) Schema() Node {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return Node{
+		Identity: e.identity,
+		Type:     "escalate",
+		Flow:     EscalateFlow{Handler: e.handler.Schema()},
+		Metadata: map[string]any{
+			"threshold": e.threshold,
+			"window":    e.window.String(),
+			"severity":  e.severity != nil,
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its handler.
+// Close is idempotent - multiple calls return the same result.
+func (e *Escalate[T]) Close() error {
+	e.closeOnce.Do(func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.closeErr = e.handler.Close()
+	})
+	return e.closeErr
+}
@@ -0,0 +1,208 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+func TestEscalate(t *testing.T) {
+	errFatal := errors.New("fatal")
+	newHandler := func(calls *atomic.Int32) Chainable[*Error[int]] {
+		return Effect(NewIdentity("page", ""), func(_ context.Context, _ *Error[int]) error {
+			calls.Add(1)
+			return nil
+		})
+	}
+	failing := func(name string, err error) Chainable[int] {
+		return Apply(NewIdentity(name, ""), func(_ context.Context, v int) (int, error) {
+			return v, err
+		})
+	}
+
+	t.Run("No Policy Escalates Everything", func(t *testing.T) {
+		var calls atomic.Int32
+		h := NewHandle(NewIdentity("handle", ""), failing("charge", errors.New("boom")),
+			NewEscalate(NewIdentity("escalate", ""), newHandler(&calls)))
+		for i := 0; i < 3; i++ {
+			_, _ = h.Process(context.Background(), i) //nolint:errcheck // handler calls checked below
+		}
+		if calls.Load() != 3 {
+			t.Errorf("expected 3 handler calls, got %d", calls.Load())
+		}
+	})
+
+	t.Run("Threshold Within Window", func(t *testing.T) {
+		var calls atomic.Int32
+		clock := clockz.NewFakeClock()
+		esc := NewEscalate(NewIdentity("escalate", ""), newHandler(&calls)).
+			SetThreshold(3, time.Minute).
+			WithClock(clock)
+		h := NewHandle(NewIdentity("handle", ""), failing("charge", errors.New("boom")), esc)
+
+		for i := 0; i < 2; i++ {
+			_, _ = h.Process(context.Background(), i) //nolint:errcheck // handler calls checked below
+		}
+		if calls.Load() != 0 {
+			t.Fatalf("expected no escalation below threshold, got %d", calls.Load())
+		}
+		_, _ = h.Process(context.Background(), 2) //nolint:errcheck // handler calls checked below
+		if calls.Load() != 1 {
+			t.Fatalf("expected escalation at threshold, got %d", calls.Load())
+		}
+		// Count resets after escalating.
+		_, _ = h.Process(context.Background(), 3) //nolint:errcheck // handler calls checked below
+		if calls.Load() != 1 {
+			t.Errorf("expected count reset after escalation, got %d", calls.Load())
+		}
+	})
+
+	t.Run("Window Expires Old Failures", func(t *testing.T) {
+		var calls atomic.Int32
+		clock := clockz.NewFakeClock()
+		esc := NewEscalate(NewIdentity("escalate", ""), newHandler(&calls)).
+			SetThreshold(2, time.Minute).
+			WithClock(clock)
+		h := NewHandle(NewIdentity("handle", ""), failing("charge", errors.New("boom")), esc)
+
+		_, _ = h.Process(context.Background(), 1) //nolint:errcheck // handler calls checked below
+		clock.Advance(2 * time.Minute)
+		_, _ = h.Process(context.Background(), 2) //nolint:errcheck // handler calls checked below
+		if calls.Load() != 0 {
+			t.Errorf("expected expired failure not to count, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("Counts Per Processor", func(t *testing.T) {
+		var calls atomic.Int32
+		esc := NewEscalate(NewIdentity("escalate", ""), newHandler(&calls)).SetThreshold(2, time.Minute)
+		a := NewHandle(NewIdentity("a", ""), failing("charge", errors.New("boom")), esc)
+		b := NewHandle(NewIdentity("b", ""), failing("refund", errors.New("boom")), esc)
+
+		_, _ = a.Process(context.Background(), 1) //nolint:errcheck // handler calls checked below
+		_, _ = b.Process(context.Background(), 1) //nolint:errcheck // handler calls checked below
+		if calls.Load() != 0 {
+			t.Errorf("expected separate counts per processor, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("Severity Escalates Immediately", func(t *testing.T) {
+		var calls atomic.Int32
+		esc := NewEscalate(NewIdentity("escalate", ""), newHandler(&calls)).
+			SetThreshold(100, time.Minute).
+			SetSeverity(func(err *Error[int]) bool { return errors.Is(err, errFatal) })
+
+		fatal := NewHandle(NewIdentity("handle", ""), failing("charge", errFatal), esc)
+		minor := NewHandle(NewIdentity("handle", ""), failing("charge", errors.New("minor")), esc)
+
+		_, _ = minor.Process(context.Background(), 1) //nolint:errcheck // handler calls checked below
+		if calls.Load() != 0 {
+			t.Fatalf("expected minor error suppressed, got %d calls", calls.Load())
+		}
+		_, _ = fatal.Process(context.Background(), 1) //nolint:errcheck // handler calls checked below
+		if calls.Load() != 1 {
+			t.Errorf("expected severe error to escalate, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("Severity Only", func(t *testing.T) {
+		var calls atomic.Int32
+		esc := NewEscalate(NewIdentity("escalate", ""), newHandler(&calls)).
+			SetSeverity(func(err *Error[int]) bool { return errors.Is(err, errFatal) })
+		h := NewHandle(NewIdentity("handle", ""), failing("charge", errors.New("minor")), esc)
+
+		for i := 0; i < 5; i++ {
+			_, _ = h.Process(context.Background(), i) //nolint:errcheck // handler calls checked below
+		}
+		if calls.Load() != 0 {
+			t.Errorf("expected non-severe errors suppressed, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("Handle Error Passes Through", func(t *testing.T) {
+		var calls atomic.Int32
+		boom := errors.New("boom")
+		esc := NewEscalate(NewIdentity("escalate", ""), newHandler(&calls)).SetThreshold(5, time.Minute)
+		h := NewHandle(NewIdentity("handle", ""), failing("charge", boom), esc)
+		if _, err := h.Process(context.Background(), 1); !errors.Is(err, boom) {
+			t.Errorf("expected original error, got %v", err)
+		}
+	})
+
+	t.Run("Emits Signals", func(t *testing.T) {
+		var suppressed, triggered atomic.Int32
+		sl := capitan.Hook(SignalEscalateSuppressed, func(_ context.Context, _ *capitan.Event) {
+			suppressed.Add(1)
+		})
+		defer sl.Close()
+		var source string
+		tl := capitan.Hook(SignalEscalateTriggered, func(_ context.Context, e *capitan.Event) {
+			triggered.Add(1)
+			source, _ = FieldProcessorName.From(e)
+		})
+		defer tl.Close()
+
+		var calls atomic.Int32
+		esc := NewEscalate(NewIdentity("escalate", ""), newHandler(&calls)).SetThreshold(2, time.Minute)
+		h := NewHandle(NewIdentity("handle", ""), failing("charge", errors.New("boom")), esc)
+		_, _ = h.Process(context.Background(), 1) //nolint:errcheck // signals checked below
+		_, _ = h.Process(context.Background(), 2) //nolint:errcheck // signals checked below
+
+		if err := sl.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if err := tl.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if suppressed.Load() != 1 || triggered.Load() != 1 {
+			t.Errorf("expected 1 suppressed and 1 triggered, got %d and %d", suppressed.Load(), triggered.Load())
+		}
+		if source != "charge" {
+			t.Errorf("expected source processor 'charge', got %q", source)
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		var calls atomic.Int32
+		esc := NewEscalate(NewIdentity("escalate", ""), newHandler(&calls)).SetThreshold(2, time.Minute)
+		h := NewHandle(NewIdentity("handle", ""), failing("charge", errors.New("boom")), esc)
+		_, _ = h.Process(context.Background(), 1) //nolint:errcheck // handler calls checked below
+		esc.Reset()
+		_, _ = h.Process(context.Background(), 2) //nolint:errcheck // handler calls checked below
+		if calls.Load() != 0 {
+			t.Errorf("expected reset to clear counts, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		var calls atomic.Int32
+		esc := NewEscalate(NewIdentity("escalate", ""), newHandler(&calls)).SetThreshold(3, time.Minute)
+		node := esc.Schema()
+		flow, ok := EscalateKey.From(node)
+		if !ok || node.Type != "escalate" {
+			t.Fatalf("expected escalate flow, got %s", node.Type)
+		}
+		if flow.Handler.Identity.Name() != "page" {
+			t.Errorf("expected handler child, got %s", flow.Handler.Identity.Name())
+		}
+		if node.Metadata["threshold"] != 3 {
+			t.Errorf("expected threshold metadata, got %v", node.Metadata["threshold"])
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		var calls atomic.Int32
+		esc := NewEscalate(NewIdentity("escalate", ""), newHandler(&calls))
+		if err := esc.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+		if err := esc.Close(); err != nil {
+			t.Errorf("expected idempotent close, got %v", err)
+		}
+	})
+}
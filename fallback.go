@@ -41,6 +41,7 @@ import (
 //
 // This creates infinite recursion risk if all processors fail, leading to stack overflow.
 type Fallback[T any] struct {
+	served     func(ctx context.Context, served FallbackServed, result T) T
 	identity   Identity
 	processors []Chainable[T]
 	mu         sync.RWMutex
@@ -68,9 +69,15 @@ func NewFallback[T any](identity Identity, processors ...Chainable[T]) *Fallback
 func (f *Fallback[T]) Process(ctx context.Context, data T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, f.identity, data)
 
+	ctx, guardErr := enterDepth(ctx, f, f.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
 	f.mu.RLock()
 	processors := make([]Chainable[T], len(f.processors))
 	copy(processors, f.processors)
+	hook := f.served
 	f.mu.RUnlock()
 
 	if len(processors) == 0 {
@@ -78,7 +85,7 @@ func (f *Fallback[T]) Process(ctx context.Context, data T) (result T, err error)
 		return zero, &Error[T]{
 			Path:      []Identity{f.identity},
 			Err:       fmt.Errorf("no processors provided to Fallback"),
-			InputData: data,
+			InputData: errorInput(data),
 			Timestamp: time.Now(),
 			Duration:  0,
 		}
@@ -110,10 +117,34 @@ func (f *Fallback[T]) Process(ctx context.Context, data T) (result T, err error)
 
 		result, err := processor.Process(ctx, data)
 		if err == nil {
-			// Success! Return immediately
+			// Success! Record which branch served and return immediately
+			served := FallbackServed{Identity: processor.Identity(), Index: i}
+			fields := []capitan.Field{
+				FieldName.Field(name),
+				FieldIdentityID.Field(f.identity.ID().String()),
+				FieldProcessorIndex.Field(i),
+				FieldProcessorName.Field(procName),
+			}
+			if served.Degraded() {
+				capitan.Warn(ctx, SignalFallbackServed, fields...)
+			} else {
+				capitan.Info(ctx, SignalFallbackServed, fields...)
+			}
+			if hook != nil {
+				result = hook(ctx, served, result)
+			}
 			return result, nil
 		}
 
+		// Skips and early exits are deliberate outcomes, not failures
+		if isControl(err) {
+			var pipeErr *Error[T]
+			if errors.As(err, &pipeErr) {
+				pipeErr.prependPath(ctx, f.identity)
+			}
+			return result, err
+		}
+
 		// Store the error for potential return
 		lastErr = err
 	}
@@ -129,13 +160,13 @@ func (f *Fallback[T]) Process(ctx context.Context, data T) (result T, err error)
 
 		var pipeErr *Error[T]
 		if errors.As(lastErr, &pipeErr) {
-			pipeErr.Path = append([]Identity{f.identity}, pipeErr.Path...)
+			pipeErr.prependPath(ctx, f.identity)
 			return data, pipeErr
 		}
 		// Wrap non-pipeline errors
 		return data, &Error[T]{
 			Timestamp: time.Now(),
-			InputData: data,
+			InputData: errorInput(data),
 			Err:       lastErr,
 			Path:      []Identity{f.identity},
 		}
@@ -143,9 +174,43 @@ func (f *Fallback[T]) Process(ctx context.Context, data T) (result T, err error)
 	return data, nil
 }
 
+// FallbackServed identifies the processor that produced a Fallback result.
+type FallbackServed struct {
+	Identity Identity
+	Index    int
+}
+
+// Degraded reports whether a backup rather than the primary served.
+func (s FallbackServed) Degraded() bool {
+	return s.Index > 0
+}
+
+// SetServedHook registers a hook called with the serving processor after
+// every success. The hook may annotate the result, for example to mark a
+// response as served from a cache fallback, and returns the value passed
+// on. Every success also emits SignalFallbackServed, at warn level when a
+// backup served, so dashboards can track degraded-path usage without a
+// hook.
+//
+// Example:
+//
+//	fetch := pipz.NewFallback(FetchID, liveAPI, cachedCopy).
+//	    SetServedHook(func(_ context.Context, s pipz.FallbackServed, r Response) Response {
+//	        r.Degraded = s.Degraded()
+//	        r.Source = s.Identity.Name()
+//	        return r
+//	    })
+func (f *Fallback[T]) SetServedHook(hook func(ctx context.Context, served FallbackServed, result T) T) *Fallback[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.served = hook
+	return f
+}
+
 // SetProcessors replaces all processors with the provided ones.
 // If no processors are provided, Process() will return an error.
 func (f *Fallback[T]) SetProcessors(processors ...Chainable[T]) *Fallback[T] {
+	noteChildren(f.identity, processors...)
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	f.processors = make([]Chainable[T], len(processors))
@@ -155,6 +220,7 @@ func (f *Fallback[T]) SetProcessors(processors ...Chainable[T]) *Fallback[T] {
 
 // AddFallback appends a processor to the end of the fallback chain.
 func (f *Fallback[T]) AddFallback(processor Chainable[T]) *Fallback[T] {
+	noteChildren(f.identity, processor)
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	f.processors = append(f.processors, processor)
@@ -163,6 +229,7 @@ func (f *Fallback[T]) AddFallback(processor Chainable[T]) *Fallback[T] {
 
 // InsertAt inserts a processor at the specified index.
 func (f *Fallback[T]) InsertAt(index int, processor Chainable[T]) error {
+	noteChildren(f.identity, processor)
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	if index < 0 || index > len(f.processors) {
@@ -272,6 +339,7 @@ func (f *Fallback[T]) GetFallback() Chainable[T] {
 
 // SetPrimary updates the first processor (for backward compatibility).
 func (f *Fallback[T]) SetPrimary(processor Chainable[T]) *Fallback[T] {
+	noteChildren(f.identity, processor)
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	if len(f.processors) > 0 {
@@ -283,6 +351,7 @@ func (f *Fallback[T]) SetPrimary(processor Chainable[T]) *Fallback[T] {
 // SetFallback updates the second processor (for backward compatibility).
 // If there's no second processor, adds one.
 func (f *Fallback[T]) SetFallback(processor Chainable[T]) *Fallback[T] {
+	noteChildren(f.identity, processor)
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	if len(f.processors) > 1 {
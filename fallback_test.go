@@ -5,6 +5,8 @@ import (
 	"errors"
 	"strings"
 	"testing"
+
+	"github.com/zoobzio/capitan"
 )
 
 // plainErrorProcessor is a test helper that returns plain errors (not Error[T] types).
@@ -488,6 +490,73 @@ func TestFallback(t *testing.T) {
 		}
 	})
 
+	t.Run("Served Hook Annotates Result", func(t *testing.T) {
+		primary := Apply(NewIdentity("primary", ""), func(_ context.Context, _ int) (int, error) {
+			return 0, errors.New("primary failed")
+		})
+		cache := Transform(NewIdentity("cache", ""), func(_ context.Context, n int) int {
+			return n
+		})
+
+		var got FallbackServed
+		fb := NewFallback(NewIdentity("test-fallback", ""), primary, cache).
+			SetServedHook(func(_ context.Context, served FallbackServed, result int) int {
+				got = served
+				return result + 1000
+			})
+
+		result, err := fb.Process(context.Background(), 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != 1005 {
+			t.Errorf("expected hook to annotate result, got %d", result)
+		}
+		if got.Identity.Name() != "cache" || got.Index != 1 || !got.Degraded() {
+			t.Errorf("expected degraded cache branch, got %+v", got)
+		}
+	})
+
+	t.Run("Served Hook Not Called On Failure", func(t *testing.T) {
+		failing := Apply(NewIdentity("primary", ""), func(_ context.Context, _ int) (int, error) {
+			return 0, errors.New("primary failed")
+		})
+		called := false
+		fb := NewFallback(NewIdentity("test-fallback", ""), failing).
+			SetServedHook(func(_ context.Context, _ FallbackServed, result int) int {
+				called = true
+				return result
+			})
+		if _, err := fb.Process(context.Background(), 5); err == nil {
+			t.Fatal("expected error")
+		}
+		if called {
+			t.Error("expected hook not to run when every branch fails")
+		}
+	})
+
+	t.Run("Served Signal", func(t *testing.T) {
+		var (
+			index int
+			name  string
+		)
+		listener := capitan.Hook(SignalFallbackServed, func(_ context.Context, e *capitan.Event) {
+			index, _ = FieldProcessorIndex.From(e)
+			name, _ = FieldProcessorName.From(e)
+		})
+		defer listener.Close()
+
+		primary := Transform(NewIdentity("primary", ""), func(_ context.Context, n int) int { return n })
+		_, _ = NewFallback(NewIdentity("test-fallback", ""), primary).Process(context.Background(), 1) //nolint:errcheck // signal checked below
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if index != 0 || name != "primary" {
+			t.Errorf("expected primary at index 0, got %q at %d", name, index)
+		}
+	})
+
 	t.Run("Close Tests", func(t *testing.T) {
 		t.Run("Closes All Children", func(t *testing.T) {
 			p1 := newTrackingProcessor[int](NewIdentity("p1", ""))
@@ -0,0 +1,78 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// FaultHook decides whether to fail a node before it executes. It receives
+// the identities on the active processing path, outermost first and ending
+// with the node about to run, and returns a non-nil error to fail that node
+// without executing it.
+type FaultHook func(ctx context.Context, path []Identity) error
+
+// SetFaultHook installs a process-wide FaultHook consulted by every
+// Pipeline, connector, and processor before it executes, or removes it when
+// hook is nil. It exists for failure testing of fully assembled pipelines,
+// normally through the testing package's InjectFault; production code should
+// not install one. With no hook installed the check costs one atomic load.
+func SetFaultHook(hook FaultHook) {
+	updateHooks(func(h *processorHooks) {
+		h.fault = nil
+		if hook != nil {
+			h.fault = &hook
+		}
+	})
+}
+
+// faultFrame records one node on the path seen by the FaultHook. Frames are
+// only recorded while a hook is installed.
+type faultFrame struct {
+	parent   *faultFrame
+	identity Identity
+}
+
+// faultKey is the context key for the innermost fault frame.
+type faultKey struct{}
+
+// checkFault records identity on the fault path carried by ctx and consults
+// the installed FaultHook, returning the extended context and the injected
+// failure, if any.
+func checkFault[T any](ctx context.Context, identity Identity, data T) (context.Context, *Error[T]) {
+	hooks := activeHooks.Load()
+	if hooks == nil || hooks.fault == nil {
+		return ctx, nil
+	}
+	hook := hooks.fault
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	parent, _ := ctx.Value(faultKey{}).(*faultFrame)
+	frame := &faultFrame{parent: parent, identity: identity}
+	ctx = context.WithValue(ctx, faultKey{}, frame)
+
+	depth := 0
+	for f := frame; f != nil; f = f.parent {
+		depth++
+	}
+	path := make([]Identity, depth)
+	for f := frame; f != nil; f = f.parent {
+		depth--
+		path[depth] = f.identity
+	}
+
+	err := (*hook)(ctx, path)
+	if err == nil {
+		return ctx, nil
+	}
+	return ctx, &Error[T]{
+		Err:       err,
+		InputData: errorInput(data),
+		Path:      []Identity{identity},
+		Timestamp: time.Now(),
+		Timeout:   errors.Is(err, context.DeadlineExceeded),
+		Canceled:  errors.Is(err, context.Canceled),
+	}
+}
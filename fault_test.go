@@ -0,0 +1,88 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFaultHook(t *testing.T) {
+	t.Run("Hook Sees Full Path", func(t *testing.T) {
+		var paths [][]string
+		SetFaultHook(func(_ context.Context, path []Identity) error {
+			names := make([]string, len(path))
+			for i, id := range path {
+				names[i] = id.Name()
+			}
+			paths = append(paths, names)
+			return nil
+		})
+		defer SetFaultHook(nil)
+
+		double := Transform(NewIdentity("double", ""), func(_ context.Context, v int) int { return v * 2 })
+		pipeline := NewPipeline(NewIdentity("payments", ""), NewSequence(NewIdentity("steps", ""), double))
+		if _, err := pipeline.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{"payments", "steps", "double"}
+		if len(paths) != 3 || len(paths[2]) != 3 {
+			t.Fatalf("expected 3 hook calls ending at depth 3, got %v", paths)
+		}
+		for i, name := range want {
+			if paths[2][i] != name {
+				t.Errorf("expected path %v, got %v", want, paths[2])
+			}
+		}
+	})
+
+	t.Run("Injected Error Fails Node Without Running It", func(t *testing.T) {
+		injected := errors.New("injected")
+		SetFaultHook(func(_ context.Context, path []Identity) error {
+			if path[len(path)-1].Name() == "charge" {
+				return injected
+			}
+			return nil
+		})
+		defer SetFaultHook(nil)
+
+		ran := false
+		charge := Effect(NewIdentity("charge", ""), func(context.Context, int) error { ran = true; return nil })
+		seq := NewSequence(NewIdentity("checkout", ""), charge)
+		_, err := seq.Process(context.Background(), 1)
+		if !errors.Is(err, injected) {
+			t.Fatalf("expected injected error, got %v", err)
+		}
+		if ran {
+			t.Error("faulted processor should not run")
+		}
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[1].Name() != "charge" {
+			t.Errorf("expected path checkout -> charge, got %v", err)
+		}
+	})
+
+	t.Run("Faulted Connector", func(t *testing.T) {
+		injected := errors.New("injected")
+		SetFaultHook(func(_ context.Context, path []Identity) error {
+			if path[len(path)-1].Name() == "checkout" {
+				return injected
+			}
+			return nil
+		})
+		defer SetFaultHook(nil)
+
+		seq := NewSequence[int](NewIdentity("checkout", ""))
+		if _, err := seq.Process(context.Background(), 1); !errors.Is(err, injected) {
+			t.Fatalf("expected injected error, got %v", err)
+		}
+	})
+
+	t.Run("No Hook Runs Normally", func(t *testing.T) {
+		SetFaultHook(nil)
+		double := Transform(NewIdentity("double", ""), func(_ context.Context, v int) int { return v * 2 })
+		if got, err := double.Process(context.Background(), 2); err != nil || got != 4 {
+			t.Fatalf("expected 4, got %d, %v", got, err)
+		}
+	})
+}
@@ -0,0 +1,322 @@
+package pipz
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDecrypt is wrapped by errors from FieldDecrypt for values that are not
+// well-formed ciphertext or fail authentication, such as ones tampered with
+// or sealed for another tenant or field.
+var ErrDecrypt = errors.New("decrypt failed")
+
+// ErrKeyNotFound is returned by MemoryKeyProvider for tenants or key IDs
+// it has no key for.
+var ErrKeyNotFound = errors.New("encryption key not found")
+
+// ciphertextPrefix marks field values sealed by FieldEncrypt.
+const ciphertextPrefix = "pipz:enc:v1:"
+
+// KeyProvider resolves tenant-scoped encryption keys for FieldEncrypt and
+// FieldDecrypt, typically backed by a KMS or secrets manager. Keys are
+// AES keys of 16, 24, or 32 bytes.
+//
+// Each ciphertext records the ID of the key that sealed it, so keys can be
+// rotated: new values are sealed with the tenant's current key, while
+// values sealed with earlier keys still decrypt for as long as Key
+// resolves them.
+type KeyProvider interface {
+	// CurrentKey returns the ID and bytes of the key new values for tenant
+	// are sealed with.
+	CurrentKey(ctx context.Context, tenant string) (id string, key []byte, err error)
+	// Key returns the key with the given ID for tenant.
+	Key(ctx context.Context, tenant, id string) ([]byte, error)
+}
+
+// EncryptedField is a string field of T protected by FieldEncrypt and
+// FieldDecrypt. Name identifies the field in errors and is bound into the
+// ciphertext, so a value cannot be moved to another field and decrypted.
+type EncryptedField[T any] struct {
+	Get  func(T) string
+	Set  func(T, string) T
+	Name string
+}
+
+// FieldEncrypt creates a Processor sealing fields with AES-GCM under the
+// current key of the item's tenant, as returned by tenant, so sensitive
+// attributes stay protected between stages, in logs, and in DLQ storage.
+// Only the stages that need a value decrypt it, with FieldDecrypt.
+//
+// Sealed values are self-describing strings recording the key ID, and are
+// bound to the tenant and field name. Empty values and values that already
+// open under one of the tenant's keys are left as they are, so retried
+// items are not sealed twice; any other value is sealed, even one that
+// merely looks sealed. If the item fails, the error carries it with the
+// fields cleared rather than in plaintext.
+//
+// Re-encrypting with FieldDecrypt followed by FieldEncrypt moves values
+// onto the tenant's current key after a rotation.
+//
+// Example:
+//
+//	var SealPatientID = pipz.NewIdentity("seal-patient", "Encrypts patient identifiers per tenant")
+//	ssn := pipz.EncryptedField[Patient]{
+//	    Name: "ssn",
+//	    Get:  func(p Patient) string { return p.SSN },
+//	    Set:  func(p Patient, s string) Patient { p.SSN = s; return p },
+//	}
+//	seal := pipz.FieldEncrypt(SealPatientID, keys,
+//	    func(_ context.Context, p Patient) string { return p.TenantID }, ssn)
+func FieldEncrypt[T any](identity Identity, provider KeyProvider, tenant func(context.Context, T) string, fields ...EncryptedField[T]) Processor[T] {
+	return Processor[T]{
+		identity: identity,
+		fn: func(ctx context.Context, value T) (result T, err error) {
+			defer recoverFromPanic(&result, &err, identity, cleared(value, fields))
+			tenantID := tenant(ctx, value)
+
+			var keyID string
+			var aead cipher.AEAD
+			opener := newFieldOpener(provider, tenantID)
+			result = value
+			for _, f := range fields {
+				plaintext := f.Get(result)
+				if plaintext == "" {
+					continue
+				}
+				if strings.HasPrefix(plaintext, ciphertextPrefix) {
+					if _, err := opener.open(ctx, f.Name, plaintext); err == nil {
+						continue
+					}
+				}
+				if aead == nil {
+					var key []byte
+					if keyID, key, err = provider.CurrentKey(ctx, tenantID); err == nil {
+						aead, err = newAEAD(key)
+					}
+					if err != nil {
+						return value, cryptError(identity, cleared(value, fields),
+							fmt.Errorf("resolving key for tenant %q: %w", tenantID, err))
+					}
+				}
+				sealed, err := sealField(aead, keyID, tenantID, f.Name, plaintext)
+				if err != nil {
+					return value, cryptError(identity, cleared(value, fields),
+						fmt.Errorf("encrypting %s: %w", f.Name, err))
+				}
+				result = f.Set(result, sealed)
+			}
+			return result, nil
+		},
+	}
+}
+
+// FieldDecrypt creates a Processor opening fields sealed by FieldEncrypt
+// for the item's tenant, resolving each value's key by the ID recorded in
+// it. Empty values are left as they are; any other value that is not
+// sealed, or fails authentication, fails the item with an error wrapping
+// ErrDecrypt.
+func FieldDecrypt[T any](identity Identity, provider KeyProvider, tenant func(context.Context, T) string, fields ...EncryptedField[T]) Processor[T] {
+	return Processor[T]{
+		identity: identity,
+		fn: func(ctx context.Context, value T) (result T, err error) {
+			defer recoverFromPanic(&result, &err, identity, value)
+			tenantID := tenant(ctx, value)
+
+			opener := newFieldOpener(provider, tenantID)
+			result = value
+			for _, f := range fields {
+				sealed := f.Get(result)
+				if sealed == "" {
+					continue
+				}
+				plaintext, err := opener.open(ctx, f.Name, sealed)
+				if err != nil {
+					return value, cryptError(identity, value, err)
+				}
+				result = f.Set(result, plaintext)
+			}
+			return result, nil
+		},
+	}
+}
+
+// fieldOpener opens sealed values for one tenant, resolving each key once.
+type fieldOpener struct {
+	provider KeyProvider
+	aeads    map[string]cipher.AEAD
+	tenant   string
+}
+
+// newFieldOpener returns a fieldOpener for tenant's keys.
+func newFieldOpener(provider KeyProvider, tenant string) *fieldOpener {
+	return &fieldOpener{provider: provider, tenant: tenant, aeads: make(map[string]cipher.AEAD)}
+}
+
+// open authenticates and decrypts the sealed value of field.
+func (o *fieldOpener) open(ctx context.Context, field, sealed string) (string, error) {
+	keyID, ciphertext, err := parseSealed(sealed)
+	if err != nil {
+		return "", fmt.Errorf("decrypting %s: %w", field, err)
+	}
+	aead, ok := o.aeads[keyID]
+	if !ok {
+		key, err := o.provider.Key(ctx, o.tenant, keyID)
+		if err == nil {
+			aead, err = newAEAD(key)
+		}
+		if err != nil {
+			return "", fmt.Errorf("resolving key %q for tenant %q: %w", keyID, o.tenant, err)
+		}
+		o.aeads[keyID] = aead
+	}
+	plaintext, err := openField(aead, keyID, o.tenant, field, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("decrypting %s: %w", field, err)
+	}
+	return plaintext, nil
+}
+
+// newAEAD returns an AES-GCM cipher for key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealAD returns the additional data binding a ciphertext to its key,
+// tenant, and field. Lengths are included so no two tuples collide.
+func sealAD(keyID, tenant, field string) []byte {
+	return fmt.Appendf(nil, "%d:%s%d:%s%d:%s", len(keyID), keyID, len(tenant), tenant, len(field), field)
+}
+
+// sealField encrypts plaintext and encodes it with its key ID.
+func sealField(aead cipher.AEAD, keyID, tenant, field, plaintext string) (string, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	out := aead.Seal(nonce, nonce, []byte(plaintext), sealAD(keyID, tenant, field))
+	return ciphertextPrefix + keyID + ":" + base64.RawURLEncoding.EncodeToString(out), nil
+}
+
+// parseSealed splits a sealed value into its key ID and ciphertext. The
+// key ID may itself contain colons; the encoded ciphertext cannot.
+func parseSealed(sealed string) (string, []byte, error) {
+	rest, ok := strings.CutPrefix(sealed, ciphertextPrefix)
+	i := strings.LastIndexByte(rest, ':')
+	if !ok || i < 0 {
+		return "", nil, fmt.Errorf("%w: value is not encrypted", ErrDecrypt)
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(rest[i+1:])
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: malformed ciphertext", ErrDecrypt)
+	}
+	return rest[:i], ciphertext, nil
+}
+
+// openField decrypts a ciphertext produced by sealField.
+func openField(aead cipher.AEAD, keyID, tenant, field string, ciphertext []byte) (string, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return "", fmt.Errorf("%w: malformed ciphertext", ErrDecrypt)
+	}
+	nonce, body := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, body, sealAD(keyID, tenant, field))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrDecrypt, err)
+	}
+	return string(plaintext), nil
+}
+
+// cleared returns value with fields emptied, so errors from FieldEncrypt do
+// not carry the plaintext it was meant to protect.
+func cleared[T any](value T, fields []EncryptedField[T]) T {
+	for _, f := range fields {
+		if !strings.HasPrefix(f.Get(value), ciphertextPrefix) {
+			value = f.Set(value, "")
+		}
+	}
+	return value
+}
+
+// cryptError wraps err in an Error at identity.
+func cryptError[T any](identity Identity, value T, err error) *Error[T] {
+	return &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(value),
+		Err:       err,
+		Path:      []Identity{identity},
+	}
+}
+
+// MemoryKeyProvider is an in-process KeyProvider for tests and
+// single-process tools, holding every key it was given in memory.
+type MemoryKeyProvider struct {
+	keys    map[string]map[string][]byte
+	current map[string]string
+	mu      sync.RWMutex
+}
+
+// NewMemoryKeyProvider creates an empty MemoryKeyProvider.
+func NewMemoryKeyProvider() *MemoryKeyProvider {
+	return &MemoryKeyProvider{
+		keys:    make(map[string]map[string][]byte),
+		current: make(map[string]string),
+	}
+}
+
+// AddKey adds a key for tenant and makes it the tenant's current key,
+// rotating new values onto it. Earlier keys are kept for decryption.
+func (p *MemoryKeyProvider) AddKey(tenant, id string, key []byte) *MemoryKeyProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.keys[tenant] == nil {
+		p.keys[tenant] = make(map[string][]byte)
+	}
+	p.keys[tenant][id] = append([]byte(nil), key...)
+	p.current[tenant] = id
+	return p
+}
+
+// RemoveKey removes a retired key. Values still sealed with it no longer
+// decrypt.
+func (p *MemoryKeyProvider) RemoveKey(tenant, id string) *MemoryKeyProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.keys[tenant], id)
+	if p.current[tenant] == id {
+		delete(p.current, tenant)
+	}
+	return p
+}
+
+// CurrentKey implements KeyProvider.
+func (p *MemoryKeyProvider) CurrentKey(_ context.Context, tenant string) (string, []byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	id, ok := p.current[tenant]
+	if !ok {
+		return "", nil, ErrKeyNotFound
+	}
+	return id, p.keys[tenant][id], nil
+}
+
+// Key implements KeyProvider.
+func (p *MemoryKeyProvider) Key(_ context.Context, tenant, id string) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	key, ok := p.keys[tenant][id]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
@@ -0,0 +1,182 @@
+package pipz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type patientRecord struct {
+	Tenant string
+	SSN    string
+	Notes  string
+}
+
+func TestFieldCrypt(t *testing.T) {
+	ssn := EncryptedField[patientRecord]{
+		Name: "ssn",
+		Get:  func(p patientRecord) string { return p.SSN },
+		Set:  func(p patientRecord, s string) patientRecord { p.SSN = s; return p },
+	}
+	notes := EncryptedField[patientRecord]{
+		Name: "notes",
+		Get:  func(p patientRecord) string { return p.Notes },
+		Set:  func(p patientRecord, s string) patientRecord { p.Notes = s; return p },
+	}
+	tenant := func(_ context.Context, p patientRecord) string { return p.Tenant }
+	newKeys := func() *MemoryKeyProvider {
+		return NewMemoryKeyProvider().
+			AddKey("acme", "k1", bytes.Repeat([]byte{1}, 32)).
+			AddKey("globex", "k1", bytes.Repeat([]byte{2}, 32))
+	}
+	ctx := context.Background()
+
+	t.Run("Round Trip", func(t *testing.T) {
+		keys := newKeys()
+		encrypt := FieldEncrypt(testIdentity("encrypt"), keys, tenant, ssn, notes)
+		decrypt := FieldDecrypt(testIdentity("decrypt"), keys, tenant, ssn, notes)
+
+		in := patientRecord{Tenant: "acme", SSN: "123-45-6789", Notes: "allergic to penicillin"}
+		sealed, err := encrypt.Process(ctx, in)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(sealed.SSN, "6789") || !strings.HasPrefix(sealed.SSN, ciphertextPrefix+"k1:") {
+			t.Errorf("expected sealed SSN, got %q", sealed.SSN)
+		}
+		opened, err := decrypt.Process(ctx, sealed)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if opened != in {
+			t.Errorf("expected %+v, got %+v", in, opened)
+		}
+	})
+
+	t.Run("Skips Empty And Sealed Values", func(t *testing.T) {
+		encrypt := FieldEncrypt(testIdentity("encrypt"), newKeys(), tenant, ssn, notes)
+		sealed, err := encrypt.Process(ctx, patientRecord{Tenant: "acme", SSN: "123-45-6789"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sealed.Notes != "" {
+			t.Errorf("expected empty notes to stay empty, got %q", sealed.Notes)
+		}
+		again, err := encrypt.Process(ctx, sealed)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if again.SSN != sealed.SSN {
+			t.Error("expected sealed value not to be sealed twice")
+		}
+	})
+
+	t.Run("Seals Forged Prefix", func(t *testing.T) {
+		keys := newKeys()
+		encrypt := FieldEncrypt(testIdentity("encrypt"), keys, tenant, ssn)
+		decrypt := FieldDecrypt(testIdentity("decrypt"), keys, tenant, ssn)
+
+		forged := ciphertextPrefix + "k1:123-45-6789"
+		sealed, err := encrypt.Process(ctx, patientRecord{Tenant: "acme", SSN: forged})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sealed.SSN == forged || strings.Contains(sealed.SSN, "6789") {
+			t.Errorf("expected the forged value sealed, got %q", sealed.SSN)
+		}
+		opened, err := decrypt.Process(ctx, sealed)
+		if err != nil || opened.SSN != forged {
+			t.Errorf("expected the forged value back, got %q (%v)", opened.SSN, err)
+		}
+
+		other, err := encrypt.Process(ctx, patientRecord{Tenant: "globex", SSN: "123-45-6789"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		moved, err := encrypt.Process(ctx, patientRecord{Tenant: "acme", SSN: other.SSN})
+		if err != nil || moved.SSN == other.SSN {
+			t.Errorf("expected a value sealed for another tenant sealed again, got %q (%v)", moved.SSN, err)
+		}
+	})
+
+	t.Run("Bound To Tenant And Field", func(t *testing.T) {
+		keys := NewMemoryKeyProvider().
+			AddKey("acme", "k1", bytes.Repeat([]byte{1}, 32)).
+			AddKey("globex", "k1", bytes.Repeat([]byte{1}, 32))
+		encrypt := FieldEncrypt(testIdentity("encrypt"), keys, tenant, ssn)
+		decrypt := FieldDecrypt(testIdentity("decrypt"), keys, tenant, ssn, notes)
+
+		sealed, err := encrypt.Process(ctx, patientRecord{Tenant: "acme", SSN: "123-45-6789"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		moved := sealed
+		moved.Tenant = "globex"
+		if _, err := decrypt.Process(ctx, moved); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("expected decrypt failure under another tenant, got %v", err)
+		}
+		swapped := patientRecord{Tenant: "acme", Notes: sealed.SSN}
+		if _, err := decrypt.Process(ctx, swapped); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("expected decrypt failure in another field, got %v", err)
+		}
+	})
+
+	t.Run("Rotation", func(t *testing.T) {
+		keys := newKeys()
+		encrypt := FieldEncrypt(testIdentity("encrypt"), keys, tenant, ssn)
+		decrypt := FieldDecrypt(testIdentity("decrypt"), keys, tenant, ssn)
+
+		old, err := encrypt.Process(ctx, patientRecord{Tenant: "acme", SSN: "123-45-6789"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		keys.AddKey("acme", "k2", bytes.Repeat([]byte{3}, 32))
+
+		opened, err := decrypt.Process(ctx, old)
+		if err != nil || opened.SSN != "123-45-6789" {
+			t.Fatalf("expected old value to decrypt after rotation, got %q, %v", opened.SSN, err)
+		}
+		resealed, err := encrypt.Process(ctx, opened)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(resealed.SSN, ciphertextPrefix+"k2:") {
+			t.Errorf("expected value sealed with k2, got %q", resealed.SSN)
+		}
+
+		keys.RemoveKey("acme", "k1")
+		if _, err := decrypt.Process(ctx, old); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("expected retired key to be missing, got %v", err)
+		}
+	})
+
+	t.Run("Rejects Plaintext", func(t *testing.T) {
+		decrypt := FieldDecrypt(testIdentity("decrypt"), newKeys(), tenant, ssn)
+		_, err := decrypt.Process(ctx, patientRecord{Tenant: "acme", SSN: "123-45-6789"})
+		if !errors.Is(err, ErrDecrypt) {
+			t.Errorf("expected ErrDecrypt, got %v", err)
+		}
+	})
+
+	t.Run("Errors Do Not Carry Plaintext", func(t *testing.T) {
+		encrypt := FieldEncrypt(testIdentity("encrypt"), newKeys(), tenant, ssn)
+		_, err := encrypt.Process(ctx, patientRecord{Tenant: "initech", SSN: "123-45-6789"})
+		var pipeErr *Error[patientRecord]
+		if !errors.As(err, &pipeErr) || !errors.Is(err, ErrKeyNotFound) {
+			t.Fatalf("expected missing key error, got %v", err)
+		}
+		if pipeErr.InputData.SSN != "" || pipeErr.InputData.Tenant != "initech" {
+			t.Errorf("expected SSN cleared from error input, got %+v", pipeErr.InputData)
+		}
+	})
+
+	t.Run("Invalid Key Size", func(t *testing.T) {
+		keys := NewMemoryKeyProvider().AddKey("acme", "short", []byte("too short"))
+		encrypt := FieldEncrypt(testIdentity("encrypt"), keys, tenant, ssn)
+		if _, err := encrypt.Process(ctx, patientRecord{Tenant: "acme", SSN: "x"}); err == nil {
+			t.Error("expected error for invalid key size")
+		}
+	})
+}
@@ -76,6 +76,11 @@ func NewFilter[T any](identity Identity, condition func(context.Context, T) bool
 func (f *Filter[T]) Process(ctx context.Context, data T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, f.identity, data)
 
+	ctx, guardErr := enterDepth(ctx, f, f.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
 	f.mu.RLock()
 	condition := f.condition
 	processor := f.processor
@@ -102,13 +107,13 @@ func (f *Filter[T]) Process(ctx context.Context, data T) (result T, err error) {
 		// Prepend this filter's identity to the error path
 		var pipeErr *Error[T]
 		if errors.As(err, &pipeErr) {
-			pipeErr.Path = append([]Identity{f.identity}, pipeErr.Path...)
+			pipeErr.prependPath(ctx, f.identity)
 			return result, pipeErr
 		}
 		// Wrap non-pipeline errors
 		return data, &Error[T]{
 			Timestamp: time.Now(),
-			InputData: data,
+			InputData: errorInput(data),
 			Err:       err,
 			Path:      []Identity{f.identity},
 		}
@@ -129,6 +134,7 @@ func (f *Filter[T]) SetCondition(condition func(context.Context, T) bool) *Filte
 // SetProcessor updates the processor to execute when condition is true.
 // This allows for dynamic processor changes at runtime.
 func (f *Filter[T]) SetProcessor(processor Chainable[T]) *Filter[T] {
+	noteChildren(f.identity, processor)
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	f.processor = processor
@@ -0,0 +1,273 @@
+package pipz
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+// Message normalization replaces the parts of an error message that vary
+// between occurrences of the same failure with placeholders. Patterns are
+// applied in order, so identifiers are replaced before their digits.
+var messageNormalizers = []struct {
+	pattern     *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`), "<str>"},
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	{regexp.MustCompile(`\b0[xX][0-9a-fA-F]+\b|\b[0-9a-fA-F]{8,}\b`), "<hex>"},
+	{regexp.MustCompile(`\d+(?:\.\d+)?`), "<n>"},
+}
+
+// NormalizeErrorMessage returns msg with the values that vary between
+// occurrences of the same failure replaced by placeholders: quoted values
+// become <str>, UUIDs <uuid>, 0x literals and runs of eight or more hex
+// digits, such as hashes and trace IDs, <hex>, and other numbers,
+// including those in durations, ports, and IDs, <n>. So
+// `user "ann" not found after 3.2ms` and `user "bob" not found after 41ms`
+// both normalize to `user <str> not found after <n>ms`.
+func NormalizeErrorMessage(msg string) string {
+	for _, n := range messageNormalizers {
+		msg = n.pattern.ReplaceAllString(msg, n.placeholder)
+	}
+	return msg
+}
+
+// Fingerprint identifies the kind of failure e is, so repeated occurrences
+// can be grouped and alerted on once. It is a hash of the path the error
+// took, its category, and its normalized underlying message (see
+// NormalizeErrorMessage), and is stable across processes and restarts:
+// the path is hashed by processor name, and the input, timing, and
+// values within the message do not contribute.
+func (e *Error[T]) Fingerprint() string {
+	if e == nil {
+		return ""
+	}
+	return fingerprint(e.pathString(), e.Category(), e.normalizedMessage())
+}
+
+// normalizedMessage returns the normalized message of the underlying error.
+func (e *Error[T]) normalizedMessage() string {
+	if e.Err == nil {
+		return ""
+	}
+	return NormalizeErrorMessage(e.Err.Error())
+}
+
+// fingerprint hashes the parts of a failure that identify its kind.
+func fingerprint(path, category, message string) string {
+	h := fnv.New64a()
+	for _, part := range []string{path, category, message} {
+		_, _ = h.Write([]byte(part)) //nolint:errcheck // hash writes never fail
+		_, _ = h.Write([]byte{0})    //nolint:errcheck // hash writes never fail
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// fingerprinted is implemented by every *Error[T], whatever its T.
+type fingerprinted interface {
+	error
+	Fingerprint() string
+	Category() string
+	pathString() string
+	normalizedMessage() string
+}
+
+// ErrorFingerprint returns the fingerprint of err: that of the first
+// *Error[T] in its chain, or for other errors a hash of the normalized
+// message alone. It returns "" for a nil err.
+func ErrorFingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	var pipeErr fingerprinted
+	if errors.As(err, &pipeErr) {
+		return pipeErr.Fingerprint()
+	}
+	return fingerprint("", ErrorCategoryError, NormalizeErrorMessage(err.Error()))
+}
+
+// ErrorGroup aggregates the occurrences of one kind of failure, as
+// identified by its fingerprint. Count covers the current window only;
+// Total covers every occurrence since the group was first seen.
+type ErrorGroup struct {
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	WindowStart time.Time `json:"window_start"`
+	Fingerprint string    `json:"fingerprint"`
+	Category    string    `json:"category"`
+	Path        string    `json:"path,omitempty"`
+	Message     string    `json:"message"`
+	Count       int64     `json:"count"`
+	Total       int64     `json:"total"`
+}
+
+// ErrorGrouper aggregates errors by fingerprint over time windows, so a
+// failure repeated thousands of times appears as one group with a count
+// rather than thousands of log lines or alerts. Each group counts its
+// occurrences within a window that starts at the first occurrence after
+// the previous window ended, alongside its total and when it was first
+// and last seen. Alert when Record returns a group's first occurrence in
+// a window, or when a window's count crosses a threshold.
+//
+// Groups are kept in memory. At most 1000 are held by default, dropping
+// those seen least recently; see SetMaxGroups and SetRetention.
+//
+// ErrorGrouper is safe for concurrent use.
+//
+// Example:
+//
+//	grouper := pipz.NewErrorGrouper(5 * time.Minute)
+//	if _, err := pipeline.Process(ctx, order); err != nil {
+//	    if group, ok := grouper.Record(err); ok && group.Count == 1 {
+//	        alerts.Send(group.Fingerprint, group.Path, group.Message)
+//	    }
+//	}
+type ErrorGrouper struct {
+	clock  clockz.Clock
+	groups *keyedState[*ErrorGroup]
+	window time.Duration
+	mu     sync.Mutex
+}
+
+// NewErrorGrouper creates an ErrorGrouper counting occurrences per window.
+// A window of zero or less never resets the count.
+func NewErrorGrouper(window time.Duration) *ErrorGrouper {
+	groups := newKeyedState[*ErrorGroup]()
+	groups.maxKeys = 1000
+	return &ErrorGrouper{
+		groups: groups,
+		window: window,
+	}
+}
+
+// Record adds err to its group and returns a copy of the group after the
+// occurrence. It ignores nil errors and skips (see ErrSkip), returning
+// false.
+func (g *ErrorGrouper) Record(err error) (ErrorGroup, bool) {
+	if err == nil || IsSkip(err) {
+		return ErrorGroup{}, false
+	}
+	occurrence := ErrorGroup{
+		Category: ErrorCategoryError,
+		Message:  NormalizeErrorMessage(err.Error()),
+	}
+	var pipeErr fingerprinted
+	if errors.As(err, &pipeErr) {
+		occurrence.Category = pipeErr.Category()
+		occurrence.Path = pipeErr.pathString()
+		occurrence.Message = pipeErr.normalizedMessage()
+	}
+	occurrence.Fingerprint = fingerprint(occurrence.Path, occurrence.Category, occurrence.Message)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.getClock().Now()
+	group, ok := g.groups.get(occurrence.Fingerprint, now)
+	if !ok {
+		occurrence.FirstSeen, occurrence.WindowStart = now, now
+		group = &occurrence
+	}
+	if g.window > 0 && now.Sub(group.WindowStart) >= g.window {
+		group.WindowStart, group.Count = now, 0
+	}
+	group.Count++
+	group.Total++
+	group.LastSeen = now
+	g.groups.put(occurrence.Fingerprint, group, now)
+	return *group, true
+}
+
+// Groups returns a copy of every group held, most frequent in the current
+// window first. Groups whose window has ended without a new occurrence
+// report a Count of zero.
+func (g *ErrorGrouper) Groups() []ErrorGroup {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.getClock().Now()
+	g.groups.compact(now)
+	groups := make([]ErrorGroup, 0, g.groups.order.Len())
+	for el := g.groups.order.Front(); el != nil; el = el.Next() {
+		group := *el.Value.(*keyedEntry[*ErrorGroup]).value
+		if g.window > 0 && now.Sub(group.WindowStart) >= g.window {
+			group.Count = 0
+		}
+		groups = append(groups, group)
+	}
+	slices.SortStableFunc(groups, func(a, b ErrorGroup) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), b.LastSeen.Compare(a.LastSeen))
+	})
+	return groups
+}
+
+// Group returns a copy of the group with the given fingerprint, if held.
+func (g *ErrorGrouper) Group(fingerprint string) (ErrorGroup, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.getClock().Now()
+	group, ok := g.groups.get(fingerprint, now)
+	if !ok {
+		return ErrorGroup{}, false
+	}
+	snapshot := *group
+	if g.window > 0 && now.Sub(snapshot.WindowStart) >= g.window {
+		snapshot.Count = 0
+	}
+	return snapshot, true
+}
+
+// SetMaxGroups bounds how many groups are held, dropping those seen least
+// recently beyond it. Zero holds every group.
+func (g *ErrorGrouper) SetMaxGroups(n int) *ErrorGrouper {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.groups.maxKeys = max(n, 0)
+	g.groups.compact(g.getClock().Now())
+	return g
+}
+
+// SetRetention drops groups not seen for longer than d. Zero, the
+// default, keeps groups until displaced by SetMaxGroups.
+func (g *ErrorGrouper) SetRetention(d time.Duration) *ErrorGrouper {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.groups.ttl = max(d, 0)
+	return g
+}
+
+// Stats returns the number of groups held and dropped.
+func (g *ErrorGrouper) Stats() KeyStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.groups.keyStats()
+}
+
+// Reset drops every group.
+func (g *ErrorGrouper) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.groups.clear()
+}
+
+// WithClock sets the clock implementation for testing purposes.
+func (g *ErrorGrouper) WithClock(clock clockz.Clock) *ErrorGrouper {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.clock = clock
+	return g
+}
+
+// getClock returns the clock to use.
+func (g *ErrorGrouper) getClock() clockz.Clock {
+	if g.clock == nil {
+		return clockz.RealClock
+	}
+	return g.clock
+}
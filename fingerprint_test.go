@@ -0,0 +1,162 @@
+package pipz
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+func TestNormalizeErrorMessage(t *testing.T) {
+	tests := []struct {
+		msg, want string
+	}{
+		{`user "ann" not found after 3.2ms`, `user <str> not found after <n>ms`},
+		{`order 'A-17' rejected`, `order <str> rejected`},
+		{"request 8f14e45f-ceea-467f-a0e6-1a2b3c4d5e6f failed", "request <uuid> failed"},
+		{"bad pointer 0xc000123abc, trace 4bf92f3577b34da6", "bad pointer <hex>, trace <hex>"},
+		{"dial tcp 10.0.0.7:5432: connection refused", "dial tcp <n>.<n>:<n>: connection refused"},
+		{"connection refused", "connection refused"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeErrorMessage(tt.msg); got != tt.want {
+			t.Errorf("NormalizeErrorMessage(%q) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	pipeline := testIdentity("pipeline")
+	lookup := testIdentity("lookup")
+	newErr := func(err error, path ...Identity) *Error[int] {
+		return &Error[int]{Err: err, Path: path, InputData: 1, Duration: time.Millisecond}
+	}
+
+	t.Run("Same Failure Same Fingerprint", func(t *testing.T) {
+		a := newErr(fmt.Errorf("user %q not found", "ann"), pipeline, lookup)
+		b := newErr(fmt.Errorf("user %q not found", "bob"), pipeline, lookup)
+		b.InputData, b.Duration = 2, time.Second
+		if a.Fingerprint() != b.Fingerprint() {
+			t.Error("expected occurrences of one failure to share a fingerprint")
+		}
+		if len(a.Fingerprint()) != 16 {
+			t.Errorf("expected 16 hex digits, got %q", a.Fingerprint())
+		}
+	})
+
+	t.Run("Different Failures Differ", func(t *testing.T) {
+		base := newErr(errors.New("not found"), pipeline, lookup)
+		timedOut := newErr(errors.New("not found"), pipeline, lookup)
+		timedOut.Timeout = true
+		others := []*Error[int]{
+			newErr(errors.New("not found"), pipeline),
+			newErr(errors.New("forbidden"), pipeline, lookup),
+			timedOut,
+		}
+		for i, other := range others {
+			if other.Fingerprint() == base.Fingerprint() {
+				t.Errorf("failure %d: expected a distinct fingerprint", i)
+			}
+		}
+	})
+
+	t.Run("Wrapped And Plain Errors", func(t *testing.T) {
+		pipeErr := newErr(errors.New("not found"), pipeline, lookup)
+		if got := ErrorFingerprint(fmt.Errorf("handling order: %w", pipeErr)); got != pipeErr.Fingerprint() {
+			t.Errorf("expected the wrapped error's fingerprint, got %q", got)
+		}
+		if ErrorFingerprint(errors.New("timeout after 3s")) != ErrorFingerprint(errors.New("timeout after 10s")) {
+			t.Error("expected plain errors to be fingerprinted by normalized message")
+		}
+		if ErrorFingerprint(nil) != "" || (*Error[int])(nil).Fingerprint() != "" {
+			t.Error("expected empty fingerprints for nil errors")
+		}
+	})
+}
+
+func TestErrorGrouper(t *testing.T) {
+	lookup := testIdentity("lookup")
+	notFound := func(user string) error {
+		return &Error[string]{Err: fmt.Errorf("user %q not found", user), Path: []Identity{lookup}, InputData: user}
+	}
+
+	t.Run("Groups By Fingerprint", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		grouper := NewErrorGrouper(time.Minute).WithClock(clock)
+		start := clock.Now()
+		grouper.Record(notFound("ann"))
+		clock.Advance(time.Second)
+		group, ok := grouper.Record(notFound("bob"))
+		grouper.Record(errors.New("disk full"))
+
+		if !ok || group.Count != 2 || group.Total != 2 {
+			t.Fatalf("expected two occurrences, got %+v", group)
+		}
+		if !group.FirstSeen.Equal(start) || !group.LastSeen.Equal(start.Add(time.Second)) {
+			t.Errorf("unexpected first and last seen %v, %v", group.FirstSeen, group.LastSeen)
+		}
+		if group.Path != "lookup" || group.Message != "user <str> not found" || group.Category != ErrorCategoryError {
+			t.Errorf("unexpected group %+v", group)
+		}
+		groups := grouper.Groups()
+		if len(groups) != 2 || groups[0].Fingerprint != group.Fingerprint || groups[1].Count != 1 {
+			t.Errorf("expected two groups, most frequent first, got %+v", groups)
+		}
+	})
+
+	t.Run("Counts Per Window", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		grouper := NewErrorGrouper(time.Minute).WithClock(clock)
+		grouper.Record(notFound("ann"))
+		grouper.Record(notFound("bob"))
+		clock.Advance(time.Minute)
+
+		fp := ErrorFingerprint(notFound("ann"))
+		if group, _ := grouper.Group(fp); group.Count != 0 || group.Total != 2 {
+			t.Errorf("expected an ended window to count zero, got %+v", group)
+		}
+		group, _ := grouper.Record(notFound("cid"))
+		if group.Count != 1 || group.Total != 3 || !group.WindowStart.Equal(clock.Now()) {
+			t.Errorf("expected a new window, got %+v", group)
+		}
+	})
+
+	t.Run("Ignores Nil And Skips", func(t *testing.T) {
+		grouper := NewErrorGrouper(time.Minute)
+		if _, ok := grouper.Record(nil); ok {
+			t.Error("expected nil to be ignored")
+		}
+		if _, ok := grouper.Record(&Error[int]{Err: ErrSkip}); ok {
+			t.Error("expected skips to be ignored")
+		}
+		if len(grouper.Groups()) != 0 {
+			t.Error("expected no groups")
+		}
+	})
+
+	t.Run("Bounds Groups", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		grouper := NewErrorGrouper(time.Minute).WithClock(clock).SetMaxGroups(2).SetRetention(time.Hour)
+		for _, msg := range []string{"disk full", "forbidden", "conflict"} {
+			grouper.Record(errors.New(msg))
+			clock.Advance(time.Second)
+		}
+		if _, ok := grouper.Group(ErrorFingerprint(errors.New("disk full"))); ok {
+			t.Error("expected the least recently seen group to be dropped")
+		}
+		clock.Advance(2 * time.Hour)
+		if groups := grouper.Groups(); len(groups) != 0 {
+			t.Errorf("expected groups past retention to be dropped, got %+v", groups)
+		}
+		if stats := grouper.Stats(); stats.Displaced != 1 || stats.Expired != 2 {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+		grouper.Record(errors.New("conflict"))
+		grouper.Reset()
+		if len(grouper.Groups()) != 0 {
+			t.Error("expected Reset to drop every group")
+		}
+	})
+}
@@ -0,0 +1,268 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// FlagProvider reports whether a feature flag is enabled.
+// Implementations typically wrap a feature-flag service (LaunchDarkly,
+// Unleash, a config table) and may use the context to evaluate per-user or
+// per-tenant targeting.
+type FlagProvider interface {
+	IsEnabled(ctx context.Context, flag string) (bool, error)
+}
+
+// FlagProviderFunc adapts a function to the FlagProvider interface.
+type FlagProviderFunc func(ctx context.Context, flag string) (bool, error)
+
+// IsEnabled implements FlagProvider.
+func (f FlagProviderFunc) IsEnabled(ctx context.Context, flag string) (bool, error) {
+	return f(ctx, flag)
+}
+
+// Flagged routes between two processors based on a feature flag.
+// When the flag is enabled the enabled processor runs; otherwise the
+// disabled processor runs. A nil disabled processor passes data through
+// unchanged, making Flagged a drop-in for optional steps.
+//
+// By default the provider is consulted on every call, which supports
+// per-request targeting through the context. SetRefreshInterval caches the
+// flag value for an interval instead, for providers that are expensive to
+// query or flags that are global.
+//
+// Provider errors never fail processing: the last known value is used
+// (disabled if the flag has never been read) and a flag.error signal is
+// emitted. Flag changes emit a flag.changed signal.
+//
+// Example:
+//
+//	var NewPricingID = pipz.NewIdentity("new-pricing", "Pricing engine rollout")
+//	pricing := pipz.NewFlagged(NewPricingID, "pricing-v2",
+//	    pricingV2, pricingV1,
+//	    pipz.FlagProviderFunc(func(ctx context.Context, flag string) (bool, error) {
+//	        return flags.Enabled(ctx, flag)
+//	    }),
+//	)
+type Flagged[T any] struct {
+	enabled     Chainable[T]
+	disabled    Chainable[T]
+	provider    FlagProvider
+	clock       clockz.Clock
+	lastRefresh time.Time
+	identity    Identity
+	flag        string
+	refresh     time.Duration
+	value       bool
+	known       bool
+	mu          sync.RWMutex
+	stateMu     sync.Mutex
+	closeOnce   sync.Once
+	closeErr    error
+}
+
+// NewFlagged creates a Flagged connector for the named flag.
+func NewFlagged[T any](identity Identity, flag string, enabled, disabled Chainable[T], provider FlagProvider) *Flagged[T] {
+	if disabled == nil {
+		disabled = passthrough[T](flag)
+	}
+	return &Flagged[T]{
+		identity: identity,
+		flag:     flag,
+		enabled:  enabled,
+		disabled: disabled,
+		provider: provider,
+	}
+}
+
+// passthrough returns a processor that returns its input unchanged.
+func passthrough[T any](flag string) Chainable[T] {
+	return Transform(NewIdentity(flag+"-off", "Pass-through when flag is disabled"), func(_ context.Context, data T) T {
+		return data
+	})
+}
+
+// Process implements the Chainable interface.
+func (f *Flagged[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, f.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, f, f.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	f.mu.RLock()
+	enabled := f.enabled
+	disabled := f.disabled
+	f.mu.RUnlock()
+
+	processor := disabled
+	if f.evaluate(ctx) {
+		processor = enabled
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, f.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{f.identity},
+		}
+	}
+	return result, nil
+}
+
+// evaluate returns the current flag value, consulting the provider when
+// caching is disabled or the cached value has expired.
+func (f *Flagged[T]) evaluate(ctx context.Context) bool {
+	f.mu.RLock()
+	provider := f.provider
+	flag := f.flag
+	refresh := f.refresh
+	clock := f.getClock()
+	f.mu.RUnlock()
+
+	f.stateMu.Lock()
+	if refresh > 0 && f.known && clock.Since(f.lastRefresh) < refresh {
+		value := f.value
+		f.stateMu.Unlock()
+		return value
+	}
+	f.stateMu.Unlock()
+
+	value, err := provider.IsEnabled(ctx, flag)
+
+	f.stateMu.Lock()
+	defer f.stateMu.Unlock()
+	if err != nil {
+		capitan.Warn(ctx, SignalFlagError,
+			FieldName.Field(f.identity.Name()),
+			FieldIdentityID.Field(f.identity.ID().String()),
+			FieldFlag.Field(flag),
+			FieldError.Field(err.Error()),
+		)
+		return f.value
+	}
+	if f.known && value != f.value {
+		capitan.Info(ctx, SignalFlagChanged,
+			FieldName.Field(f.identity.Name()),
+			FieldIdentityID.Field(f.identity.ID().String()),
+			FieldFlag.Field(flag),
+			FieldEnabled.Field(value),
+		)
+	}
+	f.value = value
+	f.known = true
+	f.lastRefresh = clock.Now()
+	return value
+}
+
+// SetRefreshInterval caches the flag value for the given interval.
+// Zero (the default) consults the provider on every call.
+func (f *Flagged[T]) SetRefreshInterval(interval time.Duration) *Flagged[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.refresh = interval
+	return f
+}
+
+// SetProvider updates the flag provider.
+func (f *Flagged[T]) SetProvider(provider FlagProvider) *Flagged[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.provider = provider
+	return f
+}
+
+// SetEnabled updates the processor used when the flag is enabled.
+func (f *Flagged[T]) SetEnabled(processor Chainable[T]) *Flagged[T] {
+	noteChildren(f.identity, processor)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.enabled = processor
+	return f
+}
+
+// SetDisabled updates the processor used when the flag is disabled.
+// A nil processor passes data through unchanged.
+func (f *Flagged[T]) SetDisabled(processor Chainable[T]) *Flagged[T] {
+	noteChildren(f.identity, processor)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if processor == nil {
+		processor = passthrough[T](f.flag)
+	}
+	f.disabled = processor
+	return f
+}
+
+// WithClock sets a custom clock for testing.
+func (f *Flagged[T]) WithClock(clock clockz.Clock) *Flagged[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clock = clock
+	return f
+}
+
+// getClock returns the clock to use.
+func (f *Flagged[T]) getClock() clockz.Clock {
+	if f.clock == nil {
+		return clockz.RealClock
+	}
+	return f.clock
+}
+
+// Flag returns the name of the controlling flag.
+func (f *Flagged[T]) Flag() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.flag
+}
+
+// Identity returns the identity of this connector.
+func (f *Flagged[T]) Identity() Identity {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (f *Flagged[T]) Schema() Node {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return Node{
+		Identity: f.identity,
+		Type:     "flagged",
+		Flow: FlaggedFlow{
+			Enabled:  f.enabled.Schema(),
+			Disabled: f.disabled.Schema(),
+		},
+		Metadata: map[string]any{
+			"flag":             f.flag,
+			"refresh_interval": f.refresh.String(),
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and both child processors.
+// Close is idempotent - multiple calls return the same result.
+func (f *Flagged[T]) Close() error {
+	f.closeOnce.Do(func() {
+		f.mu.RLock()
+		defer f.mu.RUnlock()
+		f.closeErr = errors.Join(f.disabled.Close(), f.enabled.Close())
+	})
+	return f.closeErr
+}
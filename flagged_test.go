@@ -0,0 +1,169 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// testFlags is a FlagProvider backed by an atomic value.
+type testFlags struct {
+	err     error
+	calls   atomic.Int32
+	enabled atomic.Bool
+}
+
+func (f *testFlags) IsEnabled(_ context.Context, _ string) (bool, error) {
+	f.calls.Add(1)
+	return f.enabled.Load(), f.err
+}
+
+func TestFlagged(t *testing.T) {
+	add := func(name string, n int) Chainable[int] {
+		return Transform(NewIdentity(name, ""), func(_ context.Context, v int) int { return v + n })
+	}
+
+	t.Run("Routes By Flag", func(t *testing.T) {
+		flags := &testFlags{}
+		f := NewFlagged(NewIdentity("flagged", ""), "v2", add("on", 100), add("off", 1), flags)
+
+		result, err := f.Process(context.Background(), 1)
+		if err != nil || result != 2 {
+			t.Errorf("expected disabled path result 2, got %d (%v)", result, err)
+		}
+		flags.enabled.Store(true)
+		result, err = f.Process(context.Background(), 1)
+		if err != nil || result != 101 {
+			t.Errorf("expected enabled path result 101, got %d (%v)", result, err)
+		}
+	})
+
+	t.Run("Nil Disabled Passes Through", func(t *testing.T) {
+		f := NewFlagged(NewIdentity("flagged", ""), "v2", add("on", 100), nil, &testFlags{})
+		result, err := f.Process(context.Background(), 5)
+		if err != nil || result != 5 {
+			t.Errorf("expected pass-through 5, got %d (%v)", result, err)
+		}
+	})
+
+	t.Run("Per Call By Default", func(t *testing.T) {
+		flags := &testFlags{}
+		f := NewFlagged(NewIdentity("flagged", ""), "v2", add("on", 100), nil, flags)
+		for i := 0; i < 3; i++ {
+			_, _ = f.Process(context.Background(), 0) //nolint:errcheck // counting calls
+		}
+		if flags.calls.Load() != 3 {
+			t.Errorf("expected 3 provider calls, got %d", flags.calls.Load())
+		}
+	})
+
+	t.Run("Refresh Interval Caches", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		flags := &testFlags{}
+		f := NewFlagged(NewIdentity("flagged", ""), "v2", add("on", 100), nil, flags).
+			SetRefreshInterval(time.Minute).
+			WithClock(clock)
+
+		_, _ = f.Process(context.Background(), 0) //nolint:errcheck // prime cache
+		flags.enabled.Store(true)
+		if result, _ := f.Process(context.Background(), 0); result != 0 {
+			t.Errorf("expected cached disabled value, got %d", result)
+		}
+		if flags.calls.Load() != 1 {
+			t.Errorf("expected 1 provider call, got %d", flags.calls.Load())
+		}
+
+		clock.Advance(time.Minute)
+		if result, _ := f.Process(context.Background(), 0); result != 100 {
+			t.Errorf("expected refreshed enabled value, got %d", result)
+		}
+	})
+
+	t.Run("Provider Error Uses Last Known Value", func(t *testing.T) {
+		var flagName string
+		listener := capitan.Hook(SignalFlagError, func(_ context.Context, e *capitan.Event) {
+			flagName, _ = FieldFlag.From(e)
+		})
+		defer listener.Close()
+
+		flags := &testFlags{}
+		flags.enabled.Store(true)
+		f := NewFlagged(NewIdentity("flagged", ""), "v2", add("on", 100), nil, flags)
+		_, _ = f.Process(context.Background(), 0) //nolint:errcheck // prime last known value
+
+		flags.enabled.Store(false)
+		flags.err = errors.New("provider down")
+		result, err := f.Process(context.Background(), 0)
+		if err != nil || result != 100 {
+			t.Errorf("expected last known enabled value, got %d (%v)", result, err)
+		}
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if flagName != "v2" {
+			t.Errorf("expected flag.error for v2, got %q", flagName)
+		}
+	})
+
+	t.Run("Emits Changed Signal", func(t *testing.T) {
+		var changes []bool
+		listener := capitan.Hook(SignalFlagChanged, func(_ context.Context, e *capitan.Event) {
+			v, _ := FieldEnabled.From(e)
+			changes = append(changes, v)
+		})
+		defer listener.Close()
+
+		flags := &testFlags{}
+		f := NewFlagged(NewIdentity("flagged", ""), "v2", add("on", 100), nil, flags)
+		_, _ = f.Process(context.Background(), 0) //nolint:errcheck // initial read
+		flags.enabled.Store(true)
+		_, _ = f.Process(context.Background(), 0) //nolint:errcheck // flip on
+		_, _ = f.Process(context.Background(), 0) //nolint:errcheck // unchanged
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if len(changes) != 1 || !changes[0] {
+			t.Errorf("expected one change to enabled, got %v", changes)
+		}
+	})
+
+	t.Run("Error Path", func(t *testing.T) {
+		failing := Apply(NewIdentity("fail", ""), func(_ context.Context, v int) (int, error) {
+			return v, errors.New("boom")
+		})
+		flags := &testFlags{}
+		flags.enabled.Store(true)
+		f := NewFlagged(NewIdentity("flagged", ""), "v2", failing, nil, flags)
+		_, err := f.Process(context.Background(), 0)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "flagged" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		f := NewFlagged(NewIdentity("flagged", ""), "v2", add("on", 100), nil, &testFlags{})
+		schema := f.Schema()
+		flow, ok := FlaggedKey.From(schema)
+		if !ok || schema.Metadata["flag"] != "v2" {
+			t.Fatalf("unexpected schema: %+v", schema)
+		}
+		if flow.Enabled.Identity.Name() != "on" || flow.Disabled.Identity.Name() != "v2-off" {
+			t.Errorf("unexpected flow: %+v", flow)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		f := NewFlagged(NewIdentity("flagged", ""), "v2", add("on", 100), add("off", 1), &testFlags{})
+		if err := f.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,280 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// Group runs all processors in parallel with errgroup semantics: the first
+// failure cancels the remaining processors and is returned, and success
+// means every processor succeeded.
+//
+// Group fills the gap between Concurrent, which runs everything and ignores
+// individual failures, and Race, which returns the first success. Use it
+// when a set of parallel operations must all succeed, such as reserving
+// inventory, authorizing payment, and validating an address before an
+// order can proceed.
+//
+// Each processor receives its own clone of the input and a context that is
+// canceled as soon as any processor fails or the parent context is done.
+// On success the original input is returned, or, if a reducer is set, the
+// reducer's merge of all results. Without a reducer, a T implementing
+// Mergeable folds each result into the input. SetLimit caps how many
+// processors run at once, like errgroup.SetLimit.
+//
+// Example:
+//
+//	var PrepareOrderID = pipz.NewIdentity("prepare-order", "All checks must pass")
+//	prepare := pipz.NewGroup(PrepareOrderID,
+//	    reserveInventory,
+//	    authorizePayment,
+//	    validateAddress,
+//	)
+type Group[T Cloner[T]] struct {
+	reducer    func(original T, results map[Identity]T) T
+	identity   Identity
+	processors []Chainable[T]
+	limit      int
+	mu         sync.RWMutex
+	closeOnce  sync.Once
+	closeErr   error
+}
+
+// NewGroup creates a Group running the given processors.
+func NewGroup[T Cloner[T]](identity Identity, processors ...Chainable[T]) *Group[T] {
+	return &Group[T]{
+		identity:   identity,
+		processors: processors,
+	}
+}
+
+// Process implements the Chainable interface.
+func (g *Group[T]) Process(ctx context.Context, input T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, g.identity, input)
+
+	ctx, guardErr := enterDepth(ctx, g, g.identity, input)
+	if guardErr != nil {
+		return input, guardErr
+	}
+
+	start := time.Now()
+
+	g.mu.RLock()
+	processors := make([]Chainable[T], len(g.processors))
+	copy(processors, g.processors)
+	reducer := g.reducer
+	limit := g.limit
+	g.mu.RUnlock()
+
+	if len(processors) == 0 {
+		return input, nil
+	}
+
+	groupCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		results  = make(map[Identity]T, len(processors))
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	if capped := allowedConcurrency(ctx, g.identity, len(processors)); capped > 0 && (limit <= 0 || capped < limit) {
+		limit = capped
+	}
+
+	var sem chan struct{}
+	if limit > 0 {
+		sem = make(chan struct{}, limit)
+	}
+
+	for _, processor := range processors {
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			case <-groupCtx.Done():
+			}
+			if groupCtx.Err() != nil {
+				break
+			}
+		}
+		wg.Add(1)
+		go func(p Chainable[T]) {
+			defer wg.Done()
+			if sem != nil {
+				defer func() { <-sem }()
+			}
+			defer func() {
+				if r := recover(); r != nil {
+					fail(&Error[T]{
+						Path:      []Identity{p.Identity()},
+						InputData: errorInput(input),
+						Err:       newPanicError(p.Identity(), r),
+						Timestamp: time.Now(),
+					})
+				}
+			}()
+
+			res, err := p.Process(groupCtx, branchInput(p, input))
+			if err != nil {
+				fail(err)
+				return
+			}
+			mu.Lock()
+			results[p.Identity()] = res
+			mu.Unlock()
+		}(processor)
+	}
+	wg.Wait()
+
+	// Processors skipped because the group was already failing leave
+	// firstErr set; a parent cancellation with no processor failure is
+	// reported as the context error.
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+
+	if firstErr != nil {
+		capitan.Warn(ctx, SignalGroupFailed,
+			FieldName.Field(g.identity.Name()),
+			FieldIdentityID.Field(g.identity.ID().String()),
+			FieldProcessorCount.Field(len(processors)),
+			FieldError.Field(firstErr.Error()),
+			FieldDuration.Field(time.Since(start).Seconds()),
+		)
+		var pipeErr *Error[T]
+		if errors.As(firstErr, &pipeErr) {
+			pipeErr.prependPath(ctx, g.identity)
+			return input, pipeErr
+		}
+		return input, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(input),
+			Err:       firstErr,
+			Path:      []Identity{g.identity},
+			Timeout:   errors.Is(firstErr, context.DeadlineExceeded),
+			Canceled:  errors.Is(firstErr, context.Canceled),
+		}
+	}
+
+	if reducer != nil {
+		return reducer(input, results), nil
+	}
+	return mergeBranches(input, processors, results), nil
+}
+
+// SetReducer sets a function that merges all results into the output
+// when every processor succeeds. Without a reducer the input is returned,
+// merged with every result when T implements Mergeable.
+func (g *Group[T]) SetReducer(reducer func(original T, results map[Identity]T) T) *Group[T] {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.reducer = reducer
+	return g
+}
+
+// SetLimit caps the number of processors running at once.
+// Zero or negative means no limit.
+func (g *Group[T]) SetLimit(n int) *Group[T] {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.limit = n
+	return g
+}
+
+// Add appends a processor to the group.
+func (g *Group[T]) Add(processor Chainable[T]) *Group[T] {
+	noteChildren(g.identity, processor)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.processors = append(g.processors, processor)
+	return g
+}
+
+// Remove removes the processor at the specified index.
+func (g *Group[T]) Remove(index int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if index < 0 || index >= len(g.processors) {
+		return ErrIndexOutOfBounds
+	}
+
+	g.processors = append(g.processors[:index], g.processors[index+1:]...)
+	return nil
+}
+
+// Len returns the number of processors.
+func (g *Group[T]) Len() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.processors)
+}
+
+// SetProcessors replaces all processors atomically.
+func (g *Group[T]) SetProcessors(processors ...Chainable[T]) *Group[T] {
+	noteChildren(g.identity, processors...)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.processors = make([]Chainable[T], len(processors))
+	copy(g.processors, processors)
+	return g
+}
+
+// Identity returns the identity of this connector.
+func (g *Group[T]) Identity() Identity {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (g *Group[T]) Schema() Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	tasks := make([]Node, len(g.processors))
+	for i, proc := range g.processors {
+		tasks[i] = proc.Schema()
+	}
+
+	return Node{
+		Identity: g.identity,
+		Type:     "group",
+		Flow:     GroupFlow{Tasks: tasks},
+		Metadata: map[string]any{
+			"limit": g.limit,
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and all its child processors.
+// Close is idempotent - multiple calls return the same result.
+func (g *Group[T]) Close() error {
+	g.closeOnce.Do(func() {
+		g.mu.RLock()
+		defer g.mu.RUnlock()
+
+		var errs []error
+		for i := len(g.processors) - 1; i >= 0; i-- {
+			if err := g.processors[i].Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		g.closeErr = errors.Join(errs...)
+	})
+	return g.closeErr
+}
@@ -0,0 +1,162 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestGroup(t *testing.T) {
+	ok := func(name string) Chainable[clonableInt] {
+		return Transform(NewIdentity(name, ""), func(_ context.Context, v clonableInt) clonableInt { return v + 1 })
+	}
+
+	t.Run("All Succeed", func(t *testing.T) {
+		g := NewGroup(NewIdentity("group", ""), ok("a"), ok("b"), ok("c"))
+		result, err := g.Process(context.Background(), 5)
+		if err != nil || result != 5 {
+			t.Errorf("expected original input 5, got %d (%v)", result, err)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		g := NewGroup[clonableInt](NewIdentity("group", ""))
+		if result, err := g.Process(context.Background(), 5); err != nil || result != 5 {
+			t.Errorf("expected pass-through, got %d (%v)", result, err)
+		}
+	})
+
+	t.Run("First Error Cancels Others", func(t *testing.T) {
+		var canceled atomic.Bool
+		slow := Apply(NewIdentity("slow", ""), func(ctx context.Context, v clonableInt) (clonableInt, error) {
+			select {
+			case <-ctx.Done():
+				canceled.Store(true)
+				return v, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return v, nil
+			}
+		})
+		failing := Apply(NewIdentity("failing", ""), func(_ context.Context, v clonableInt) (clonableInt, error) {
+			return v, errors.New("boom")
+		})
+
+		g := NewGroup(NewIdentity("group", ""), slow, failing)
+		start := time.Now()
+		_, err := g.Process(context.Background(), 1)
+		if time.Since(start) > time.Second {
+			t.Error("expected group to return promptly after failure")
+		}
+		var pipeErr *Error[clonableInt]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected pipz error, got %v", err)
+		}
+		if len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "group" || pipeErr.Path[1].Name() != "failing" {
+			t.Errorf("unexpected path: %v", pipeErr.Path)
+		}
+		if !canceled.Load() {
+			t.Error("expected slow processor to observe cancellation")
+		}
+	})
+
+	t.Run("Reducer", func(t *testing.T) {
+		g := NewGroup(NewIdentity("group", ""), ok("a"), ok("b")).
+			SetReducer(func(original clonableInt, results map[Identity]clonableInt) clonableInt {
+				sum := original
+				for _, r := range results {
+					sum += r
+				}
+				return sum
+			})
+		result, err := g.Process(context.Background(), 1)
+		if err != nil || result != 5 {
+			t.Errorf("expected 1+2+2=5, got %d (%v)", result, err)
+		}
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		var running, peak atomic.Int32
+		track := func(name string) Chainable[clonableInt] {
+			return Apply(NewIdentity(name, ""), func(_ context.Context, v clonableInt) (clonableInt, error) {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				running.Add(-1)
+				return v, nil
+			})
+		}
+		g := NewGroup(NewIdentity("group", ""), track("a"), track("b"), track("c"), track("d")).SetLimit(2)
+		if _, err := g.Process(context.Background(), 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if peak.Load() > 2 {
+			t.Errorf("expected at most 2 concurrent, saw %d", peak.Load())
+		}
+	})
+
+	t.Run("Parent Cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		g := NewGroup(NewIdentity("group", ""), ok("a"))
+		_, err := g.Process(ctx, 1)
+		var pipeErr *Error[clonableInt]
+		if !errors.As(err, &pipeErr) || !pipeErr.Canceled {
+			t.Errorf("expected canceled error, got %v", err)
+		}
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		boom := Transform(NewIdentity("boom", ""), func(_ context.Context, _ clonableInt) clonableInt { panic("bad") })
+		g := NewGroup(NewIdentity("group", ""), ok("a"), boom)
+		if _, err := g.Process(context.Background(), 1); err == nil {
+			t.Error("expected panic to fail the group")
+		}
+	})
+
+	t.Run("Emits Signal", func(t *testing.T) {
+		var name string
+		listener := capitan.Hook(SignalGroupFailed, func(_ context.Context, e *capitan.Event) {
+			name, _ = FieldName.From(e)
+		})
+		defer listener.Close()
+
+		failing := Apply(NewIdentity("failing", ""), func(_ context.Context, v clonableInt) (clonableInt, error) {
+			return v, errors.New("boom")
+		})
+		_, _ = NewGroup(NewIdentity("group", ""), failing).Process(context.Background(), 1) //nolint:errcheck // failure expected
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if name != "group" {
+			t.Errorf("expected signal from group, got %q", name)
+		}
+	})
+
+	t.Run("Modification And Schema", func(t *testing.T) {
+		g := NewGroup(NewIdentity("group", ""), ok("a"))
+		g.Add(ok("b"))
+		if g.Len() != 2 {
+			t.Errorf("expected 2 processors, got %d", g.Len())
+		}
+		if err := g.Remove(5); !errors.Is(err, ErrIndexOutOfBounds) {
+			t.Errorf("expected ErrIndexOutOfBounds, got %v", err)
+		}
+		flow, found := GroupKey.From(g.Schema())
+		if !found || len(flow.Tasks) != 2 {
+			t.Errorf("unexpected schema flow: %+v", flow)
+		}
+		if err := g.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	})
+}
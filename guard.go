@@ -0,0 +1,148 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrGuardRejected is wrapped by errors from a Guard whose check failed.
+var ErrGuardRejected = errors.New("rejected by guard")
+
+// Guard is a cheap admission-control stage that runs a list of checks
+// before expensive downstream work. Checks run in order and the first
+// failure rejects the item with an error wrapping ErrGuardRejected and the
+// check's error; data that passes every check continues unchanged.
+//
+// Stock checks cover the common limits: MaxBytes for []byte and string
+// payloads, MaxSliceLen for slices, and NonZero for required values. Any
+// func(T) error can be used alongside them.
+//
+// Example:
+//
+//	var AdmitID = pipz.NewIdentity("admit-upload", "Rejects oversized or empty uploads")
+//	admit := pipz.NewGuard(AdmitID,
+//	    pipz.NonZero[[]byte](),
+//	    pipz.MaxBytes[[]byte](5<<20),
+//	)
+//
+//	pipeline := pipz.NewSequence(UploadID, admit, scanForViruses, transcode)
+type Guard[T any] struct {
+	identity Identity
+	checks   []func(T) error
+	mu       sync.RWMutex
+}
+
+// NewGuard creates a Guard running checks in order.
+func NewGuard[T any](identity Identity, checks ...func(T) error) *Guard[T] {
+	return &Guard[T]{
+		identity: identity,
+		checks:   checks,
+	}
+}
+
+// Process implements the Chainable interface.
+func (g *Guard[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, g.identity, data)
+
+	g.mu.RLock()
+	checks := g.checks
+	g.mu.RUnlock()
+
+	for _, check := range checks {
+		if check == nil {
+			continue
+		}
+		if checkErr := check(data); checkErr != nil {
+			capitan.Warn(ctx, SignalGuardRejected,
+				FieldName.Field(g.identity.Name()),
+				FieldIdentityID.Field(g.identity.ID().String()),
+				FieldError.Field(checkErr.Error()),
+			)
+			return data, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(data),
+				Err:       fmt.Errorf("%w: %w", ErrGuardRejected, checkErr),
+				Path:      []Identity{g.identity},
+			}
+		}
+	}
+	return data, nil
+}
+
+// AddCheck appends a check to the guard.
+func (g *Guard[T]) AddCheck(check func(T) error) *Guard[T] {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	checks := make([]func(T) error, len(g.checks), len(g.checks)+1)
+	copy(checks, g.checks)
+	g.checks = append(checks, check)
+	return g
+}
+
+// Identity returns the identity of this guard.
+func (g *Guard[T]) Identity() Identity {
+	return g.identity
+}
+
+// Schema returns a Node representing this guard in the pipeline schema.
+func (g *Guard[T]) Schema() Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return Node{
+		Identity: g.identity,
+		Type:     "guard",
+		Metadata: map[string]any{
+			"checks": len(g.checks),
+		},
+	}
+}
+
+// Close implements the Chainable interface. Guards hold no resources.
+func (*Guard[T]) Close() error {
+	return nil
+}
+
+// MaxBytes returns a check rejecting payloads longer than n bytes.
+func MaxBytes[T ~[]byte | ~string](n int) func(T) error {
+	return func(v T) error {
+		if len(v) > n {
+			return fmt.Errorf("payload is %d bytes, limit is %d", len(v), n)
+		}
+		return nil
+	}
+}
+
+// MaxSliceLen returns a check rejecting slices with more than n elements.
+func MaxSliceLen[S ~[]E, E any](n int) func(S) error {
+	return func(v S) error {
+		if len(v) > n {
+			return fmt.Errorf("has %d elements, limit is %d", len(v), n)
+		}
+		return nil
+	}
+}
+
+// NonZero returns a check rejecting the zero value of T, including nil
+// and empty slices and maps.
+func NonZero[T any]() func(T) error {
+	return func(v T) error {
+		rv := reflect.ValueOf(&v).Elem()
+		if rv.IsZero() {
+			return fmt.Errorf("%T is zero", v)
+		}
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Map:
+			if rv.Len() == 0 {
+				return fmt.Errorf("%T is empty", v)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestGuard(t *testing.T) {
+	t.Run("Passes When All Checks Pass", func(t *testing.T) {
+		g := NewGuard(NewIdentity("admit", ""), NonZero[string](), MaxBytes[string](5))
+		result, err := g.Process(context.Background(), "hello")
+		if err != nil || result != "hello" {
+			t.Fatalf("unexpected result: %q, %v", result, err)
+		}
+	})
+
+	t.Run("First Failure Rejects", func(t *testing.T) {
+		var ran bool
+		g := NewGuard(NewIdentity("admit", ""),
+			MaxBytes[[]byte](2),
+			func([]byte) error { ran = true; return nil },
+		)
+		_, err := g.Process(context.Background(), []byte("abc"))
+		if !errors.Is(err, ErrGuardRejected) {
+			t.Fatalf("expected ErrGuardRejected, got %v", err)
+		}
+		if ran {
+			t.Error("expected later checks to be skipped")
+		}
+		var pipeErr *Error[[]byte]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "admit" {
+			t.Errorf("unexpected error path: %v", err)
+		}
+		if !strings.Contains(err.Error(), "payload is 3 bytes, limit is 2") {
+			t.Errorf("unexpected message: %v", err)
+		}
+	})
+
+	t.Run("MaxSliceLen", func(t *testing.T) {
+		check := MaxSliceLen[[]int](2)
+		if check([]int{1, 2}) != nil {
+			t.Error("expected slice at limit to pass")
+		}
+		if check([]int{1, 2, 3}) == nil {
+			t.Error("expected oversized slice to fail")
+		}
+	})
+
+	t.Run("NonZero", func(t *testing.T) {
+		if NonZero[int]()(0) == nil || NonZero[int]()(1) != nil {
+			t.Error("unexpected int result")
+		}
+		if NonZero[[]int]()([]int{}) == nil {
+			t.Error("expected empty slice to fail")
+		}
+		if NonZero[map[string]int]()(nil) == nil {
+			t.Error("expected nil map to fail")
+		}
+		if NonZero[TestData]()(TestData{Value: 1}) != nil {
+			t.Error("expected populated struct to pass")
+		}
+	})
+
+	t.Run("AddCheck", func(t *testing.T) {
+		g := NewGuard[int](NewIdentity("admit", "")).AddCheck(NonZero[int]())
+		if _, err := g.Process(context.Background(), 0); !errors.Is(err, ErrGuardRejected) {
+			t.Fatalf("expected rejection, got %v", err)
+		}
+	})
+
+	t.Run("Emits Rejected Signal", func(t *testing.T) {
+		var name string
+		listener := capitan.Hook(SignalGuardRejected, func(_ context.Context, e *capitan.Event) {
+			name, _ = FieldName.From(e)
+		})
+		defer listener.Close()
+
+		_, _ = NewGuard(NewIdentity("admit", ""), NonZero[int]()).Process(context.Background(), 0) //nolint:errcheck // result checked elsewhere
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if name != "admit" {
+			t.Errorf("expected guard name 'admit', got %q", name)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := NewGuard(NewIdentity("admit", ""), NonZero[int]()).Schema()
+		if node.Type != "guard" || node.Metadata["checks"] != 1 {
+			t.Errorf("unexpected schema: %+v", node)
+		}
+	})
+}
@@ -69,6 +69,11 @@ func NewHandle[T any](identity Identity, processor Chainable[T], errorHandler Ch
 func (h *Handle[T]) Process(ctx context.Context, input T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, h.identity, input)
 
+	ctx, guardErr := enterDepth(ctx, h, h.identity, input)
+	if guardErr != nil {
+		return input, guardErr
+	}
+
 	// Take a snapshot of processor and errorHandler to prevent race conditions
 	h.mu.RLock()
 	processor := h.processor
@@ -77,11 +82,19 @@ func (h *Handle[T]) Process(ctx context.Context, input T) (result T, err error)
 
 	// Use the snapshots instead of accessing fields directly
 	result, err = processor.Process(ctx, input)
+	if isControl(err) {
+		// Skips and early exits are deliberate, not failures for the handler
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, h.identity)
+		}
+		return result, err
+	}
 	if err != nil {
 		var pipeErr *Error[T]
 		if errors.As(err, &pipeErr) {
 			// Prepend this handle's identity to the path
-			pipeErr.Path = append([]Identity{h.identity}, pipeErr.Path...)
+			pipeErr.prependPath(ctx, h.identity)
 
 			// Emit error handled signal
 			capitan.Warn(ctx, SignalHandleErrorHandled,
@@ -100,7 +113,7 @@ func (h *Handle[T]) Process(ctx context.Context, input T) (result T, err error)
 		processorIdentity := processor.Identity()
 		wrappedErr := &Error[T]{
 			Timestamp: time.Now(),
-			InputData: input,
+			InputData: errorInput(input),
 			Err:       err,
 			Path:      []Identity{h.identity, processorIdentity},
 		}
@@ -121,6 +134,7 @@ func (h *Handle[T]) Process(ctx context.Context, input T) (result T, err error)
 
 // SetProcessor updates the main processor.
 func (h *Handle[T]) SetProcessor(processor Chainable[T]) *Handle[T] {
+	noteChildren(h.identity, processor)
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.processor = processor
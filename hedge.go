@@ -0,0 +1,252 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// Hedge cuts tail latency by starting a duplicate attempt when the first
+// is slow. If the processor has not finished after delay, Hedge launches
+// another attempt on a clone of the input, up to maxHedges, and returns
+// the first success, canceling the rest. Failures are not hedged: an
+// attempt that fails quickly is left to Retry, and Hedge fails once every
+// launched attempt has failed, returning the last error.
+//
+// Hedging duplicates work, so give it an AttemptBudget shared with the
+// retries in front of the same dependency: during a partial outage, when
+// every attempt is slow, the budget stops hedges and retries together from
+// multiplying load.
+//
+// The input type T must implement Cloner[T], as each attempt processes its
+// own copy.
+//
+// Example:
+//
+//	var LookupHedgeID = pipz.NewIdentity("lookup-hedge", "Hedges slow lookups after the p95")
+//	hedge := pipz.NewHedge(LookupHedgeID, lookup, 40*time.Millisecond).
+//	    SetBudget(lookupBudget)
+type Hedge[T Cloner[T]] struct {
+	processor Chainable[T]
+	clock     clockz.Clock
+	budget    *AttemptBudget
+	identity  Identity
+	delay     time.Duration
+	maxHedges int
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewHedge creates a Hedge launching one duplicate attempt once processor
+// has run for delay.
+func NewHedge[T Cloner[T]](identity Identity, processor Chainable[T], delay time.Duration) *Hedge[T] {
+	return &Hedge[T]{
+		identity:  identity,
+		processor: processor,
+		delay:     delay,
+		maxHedges: 1,
+	}
+}
+
+// Process implements the Chainable interface.
+func (h *Hedge[T]) Process(ctx context.Context, input T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, h.identity, input)
+
+	ctx, guardErr := enterDepth(ctx, h, h.identity, input)
+	if guardErr != nil {
+		return input, guardErr
+	}
+
+	h.mu.RLock()
+	processor := h.processor
+	budget := h.budget
+	delay := h.delay
+	maxHedges := h.maxHedges
+	clock := h.getClock()
+	h.mu.RUnlock()
+
+	if budget != nil {
+		ctx = budget.earn(ctx)
+	}
+
+	type hedgeResult struct {
+		data T
+		err  error
+	}
+	results := make(chan hedgeResult, maxHedges+1)
+	hedgeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	launch := func() {
+		attemptInput := branchInput(processor, input)
+		go func() {
+			data, err := processor.Process(hedgeCtx, attemptInput)
+			results <- hedgeResult{data: data, err: err}
+		}()
+	}
+
+	launch()
+	launched, pending := 1, 1
+	hedgeTimer := clock.After(delay)
+	if maxHedges < 1 {
+		hedgeTimer = nil
+	}
+
+	var lastErr error
+	for pending > 0 {
+		select {
+		case res := <-results:
+			pending--
+			if res.err == nil || isControl(res.err) {
+				return res.data, res.err
+			}
+			lastErr = res.err
+
+		case <-hedgeTimer:
+			hedgeTimer = nil
+			if budget != nil && !budget.spend() {
+				emitBudgetExhausted(ctx, h.identity, launched)
+				continue
+			}
+			launched++
+			pending++
+			capitan.Info(ctx, SignalHedgeLaunched,
+				FieldName.Field(h.identity.Name()),
+				FieldIdentityID.Field(h.identity.ID().String()),
+				FieldAttempt.Field(launched),
+				FieldDelay.Field(delay.Seconds()),
+			)
+			launch()
+			if launched <= maxHedges {
+				hedgeTimer = clock.After(delay)
+			}
+
+		case <-ctx.Done():
+			return input, &Error[T]{
+				Err:       ctx.Err(),
+				InputData: errorInput(input),
+				Path:      []Identity{h.identity},
+				Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
+				Canceled:  errors.Is(ctx.Err(), context.Canceled),
+				Timestamp: time.Now(),
+			}
+		}
+	}
+
+	var pipeErr *Error[T]
+	if errors.As(lastErr, &pipeErr) {
+		pipeErr.prependPath(ctx, h.identity)
+		return input, pipeErr
+	}
+	return input, &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(input),
+		Err:       lastErr,
+		Path:      []Identity{h.identity},
+	}
+}
+
+// SetDelay sets how long an attempt may run before the next is launched,
+// typically around the processor's p95 latency.
+func (h *Hedge[T]) SetDelay(delay time.Duration) *Hedge[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.delay = delay
+	return h
+}
+
+// SetMaxHedges sets how many duplicate attempts may be launched per item,
+// one delay apart. Zero disables hedging.
+func (h *Hedge[T]) SetMaxHedges(n int) *Hedge[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.maxHedges = max(n, 0)
+	return h
+}
+
+// SetBudget makes hedges spend from budget, shared with the Retry and
+// Backoff connectors in front of the same dependency. Once it is spent,
+// Hedge waits for the attempts already running. Nil removes the budget.
+func (h *Hedge[T]) SetBudget(budget *AttemptBudget) *Hedge[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.budget = budget
+	return h
+}
+
+// SetProcessor updates the hedged processor.
+func (h *Hedge[T]) SetProcessor(processor Chainable[T]) *Hedge[T] {
+	noteChildren(h.identity, processor)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.processor = processor
+	return h
+}
+
+// GetDelay returns the hedging delay.
+func (h *Hedge[T]) GetDelay() time.Duration {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.delay
+}
+
+// GetMaxHedges returns the maximum duplicate attempts per item.
+func (h *Hedge[T]) GetMaxHedges() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.maxHedges
+}
+
+// WithClock sets a custom clock for testing.
+func (h *Hedge[T]) WithClock(clock clockz.Clock) *Hedge[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clock = clock
+	return h
+}
+
+// getClock returns the clock to use.
+func (h *Hedge[T]) getClock() clockz.Clock {
+	if h.clock == nil {
+		return clockz.RealClock
+	}
+	return h.clock
+}
+
+// Identity returns the identity of this connector.
+func (h *Hedge[T]) Identity() Identity {
+	return h.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (h *Hedge[T]) Schema() Node {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return Node{
+		Identity: h.identity,
+		Type:     "hedge",
+		Flow:     HedgeFlow{Processor: h.processor.Schema()},
+		Metadata: map[string]any{
+			"delay":      h.delay.String(),
+			"max_hedges": h.maxHedges,
+			"budgeted":   h.budget != nil,
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (h *Hedge[T]) Close() error {
+	h.closeOnce.Do(func() {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+		h.closeErr = h.processor.Close()
+	})
+	return h.closeErr
+}
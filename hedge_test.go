@@ -0,0 +1,99 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedge(t *testing.T) {
+	// slowFirst returns a processor whose first attempt hangs until canceled
+	// and whose later attempts succeed at once, counting the attempts.
+	slowFirst := func(calls *atomic.Int32) Chainable[clonableInt] {
+		return Apply(testIdentity("lookup"), func(ctx context.Context, n clonableInt) (clonableInt, error) {
+			if calls.Add(1) == 1 {
+				<-ctx.Done()
+				return n, ctx.Err()
+			}
+			return n * 2, nil
+		})
+	}
+
+	t.Run("Hedges Slow Attempt", func(t *testing.T) {
+		var calls atomic.Int32
+		hedge := NewHedge(testIdentity("hedge"), slowFirst(&calls), 5*time.Millisecond)
+		result, err := hedge.Process(context.Background(), 21)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != 42 || calls.Load() != 2 {
+			t.Errorf("expected hedge to win with 42 after 2 attempts, got %d after %d", result, calls.Load())
+		}
+	})
+
+	t.Run("Fast Attempt Not Hedged", func(t *testing.T) {
+		var calls atomic.Int32
+		fast := Transform(testIdentity("fast"), func(_ context.Context, n clonableInt) clonableInt {
+			calls.Add(1)
+			return n
+		})
+		if _, err := NewHedge(testIdentity("hedge"), fast, time.Second).Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("expected 1 attempt, got %d", calls.Load())
+		}
+	})
+
+	t.Run("Failures Not Hedged", func(t *testing.T) {
+		var calls atomic.Int32
+		failing := Apply(testIdentity("failing"), func(_ context.Context, n clonableInt) (clonableInt, error) {
+			calls.Add(1)
+			return n, errors.New("boom")
+		})
+		_, err := NewHedge(testIdentity("hedge"), failing, time.Second).Process(context.Background(), 1)
+		var pipeErr *Error[clonableInt]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "hedge" {
+			t.Errorf("expected path through hedge, got %v", err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("expected 1 attempt, got %d", calls.Load())
+		}
+	})
+
+	t.Run("Budget Denies Hedge", func(t *testing.T) {
+		var calls atomic.Int32
+		budget := NewAttemptBudget(0)
+		hedge := NewHedge(testIdentity("hedge"), slowFirst(&calls), 5*time.Millisecond).SetBudget(budget)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := hedge.Process(ctx, 1)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the unhedged attempt to time out, got %v", err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("expected 1 attempt, got %d", calls.Load())
+		}
+		if stats := budget.Stats(); stats.Denied != 1 || stats.Items != 1 {
+			t.Errorf("expected 1 item and 1 denial, got %+v", stats)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := NewHedge(testIdentity("hedge"), Transform(testIdentity("t"), func(_ context.Context, n clonableInt) clonableInt { return n }), 40*time.Millisecond).
+			SetMaxHedges(2).
+			Schema()
+		if node.Type != "hedge" {
+			t.Errorf("expected hedge type, got %s", node.Type)
+		}
+		if _, ok := node.Flow.(HedgeFlow); !ok {
+			t.Errorf("expected HedgeFlow, got %T", node.Flow)
+		}
+		if node.Metadata["delay"] != "40ms" || node.Metadata["max_hedges"] != 2 || node.Metadata["budgeted"] != false {
+			t.Errorf("unexpected metadata: %v", node.Metadata)
+		}
+	})
+}
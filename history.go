@@ -0,0 +1,292 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+// HistoryEntry records one execution seen by a History. Processor names
+// the node that failed, the innermost on the error's path, or the wrapped
+// processor for executions that succeeded.
+type HistoryEntry struct {
+	Timestamp   time.Time     `json:"timestamp"`
+	Error       string        `json:"error,omitempty"`
+	Category    string        `json:"category,omitempty"`
+	Processor   string        `json:"processor"`
+	ProcessorID string        `json:"processor_id"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// History records the last executions of a processor, such as a
+// CircuitBreaker, in a fixed-size ring buffer, so after an incident the
+// recent failures through it can be inspected without external logging.
+// Memory stays bounded: once full, each new entry replaces the oldest.
+// Entries are JSON-encodable for serving from an operator endpoint.
+//
+// By default every execution is recorded; SetFailuresOnly keeps the
+// buffer for failures, so it holds the last size failures however much
+// traffic succeeds in between. Entries follow the process-wide
+// RetentionPolicy, or the one set with SetRetention: error messages are
+// redacted and truncated, only sampled executions are recorded, and
+// entries past the TTL are no longer returned.
+//
+// CRITICAL: History is STATEFUL. Create it once and reuse it.
+//
+// Example:
+//
+//	var PaymentsHistoryID = pipz.NewIdentity("payments-history", "Last 50 failures through the payments breaker")
+//	history := pipz.NewHistory(PaymentsHistoryID, paymentsBreaker, 50).SetFailuresOnly(true)
+//
+//	// After an incident
+//	for _, entry := range history.Entries() {
+//	    fmt.Printf("%s %s after %v: %s\n", entry.Timestamp, entry.Processor, entry.Duration, entry.Error)
+//	}
+type History[T any] struct {
+	processor    Chainable[T]
+	clock        clockz.Clock
+	identity     Identity
+	entries      []HistoryEntry
+	next         int
+	full         bool
+	retention    *RetentionPolicy
+	sampler      sampler
+	failuresOnly bool
+	mu           sync.RWMutex
+	closeOnce    sync.Once
+	closeErr     error
+}
+
+// NewHistory creates a History recording the last size executions of
+// processor. A size below one is treated as one.
+func NewHistory[T any](identity Identity, processor Chainable[T], size int) *History[T] {
+	return &History[T]{
+		identity:  identity,
+		processor: processor,
+		entries:   make([]HistoryEntry, max(size, 1)),
+	}
+}
+
+// Process implements the Chainable interface.
+// Runs the processor and records the execution.
+func (h *History[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, h.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, h, h.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	h.mu.RLock()
+	processor := h.processor
+	clock := h.getClock()
+	h.mu.RUnlock()
+
+	started := clock.Now()
+	result, err = processor.Process(ctx, data)
+	entry := HistoryEntry{
+		Timestamp:   started,
+		Duration:    clock.Since(started),
+		Processor:   processor.Identity().Name(),
+		ProcessorID: processor.Identity().ID().String(),
+	}
+	if err == nil {
+		h.record(entry, false)
+		return result, nil
+	}
+
+	entry.Error = err.Error()
+	var pipeErr *Error[T]
+	if errors.As(err, &pipeErr) {
+		entry.Category = pipeErr.Category()
+		if len(pipeErr.Path) > 0 {
+			failed := pipeErr.Path[len(pipeErr.Path)-1]
+			entry.Processor = failed.Name()
+			entry.ProcessorID = failed.ID().String()
+		}
+		h.record(entry, true)
+		pipeErr.prependPath(ctx, h.identity)
+		return result, pipeErr
+	}
+	entry.Category = ErrorCategoryError
+	h.record(entry, true)
+	return result, &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(data),
+		Err:       err,
+		Path:      []Identity{h.identity},
+		Duration:  entry.Duration,
+	}
+}
+
+// record adds entry to the ring unless only failures are kept.
+func (h *History[T]) record(entry HistoryEntry, failed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.failuresOnly && !failed {
+		return
+	}
+	policy := h.policyLocked()
+	if !h.sampler.take(policy.SampleRate) {
+		return
+	}
+	if entry.Error != "" {
+		entry.Error = policy.retainMessage(entry.Error)
+	}
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Entries returns the recorded executions, oldest first, omitting those
+// past the retention TTL.
+func (h *History[T]) Entries() []HistoryEntry {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	entries := h.entriesLocked()
+	policy := h.policyLocked()
+	if policy.TTL <= 0 {
+		return entries
+	}
+	now := h.getClock().Now()
+	return slices.DeleteFunc(entries, func(entry HistoryEntry) bool {
+		return policy.expired(entry.Timestamp, now)
+	})
+}
+
+// policyLocked returns the RetentionPolicy in effect; the caller holds mu.
+func (h *History[T]) policyLocked() RetentionPolicy {
+	if h.retention != nil {
+		return *h.retention
+	}
+	return CurrentRetentionPolicy()
+}
+
+// entriesLocked returns the ring's entries in order; the caller holds mu.
+func (h *History[T]) entriesLocked() []HistoryEntry {
+	if !h.full {
+		return append([]HistoryEntry(nil), h.entries[:h.next]...)
+	}
+	entries := make([]HistoryEntry, 0, len(h.entries))
+	entries = append(entries, h.entries[h.next:]...)
+	return append(entries, h.entries[:h.next]...)
+}
+
+// Failures returns the recorded failed executions, oldest first.
+func (h *History[T]) Failures() []HistoryEntry {
+	var failures []HistoryEntry
+	for _, entry := range h.Entries() {
+		if entry.Error != "" {
+			failures = append(failures, entry)
+		}
+	}
+	return failures
+}
+
+// Clear drops every recorded entry.
+func (h *History[T]) Clear() *History[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	clear(h.entries)
+	h.next = 0
+	h.full = false
+	return h
+}
+
+// SetSize changes how many entries are kept, retaining the newest.
+// A size below one is treated as one.
+func (h *History[T]) SetSize(size int) *History[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	current := h.entriesLocked()
+	size = max(size, 1)
+	if len(current) > size {
+		current = current[len(current)-size:]
+	}
+	h.entries = make([]HistoryEntry, size)
+	copy(h.entries, current)
+	h.next = len(current) % size
+	h.full = len(current) == size
+	return h
+}
+
+// SetRetention sets the RetentionPolicy for this History in place of the
+// process-wide one, such as a shorter TTL for a buffer served to
+// operators. Entries already recorded keep their redaction.
+func (h *History[T]) SetRetention(policy RetentionPolicy) *History[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.retention = &policy
+	return h
+}
+
+// SetFailuresOnly records only failed executions when enabled.
+func (h *History[T]) SetFailuresOnly(enabled bool) *History[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failuresOnly = enabled
+	return h
+}
+
+// SetProcessor updates the recorded processor.
+func (h *History[T]) SetProcessor(processor Chainable[T]) *History[T] {
+	noteChildren(h.identity, processor)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.processor = processor
+	return h
+}
+
+// WithClock sets a custom clock for testing.
+func (h *History[T]) WithClock(clock clockz.Clock) *History[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clock = clock
+	return h
+}
+
+// getClock returns the clock to use.
+func (h *History[T]) getClock() clockz.Clock {
+	if h.clock == nil {
+		return clockz.RealClock
+	}
+	return h.clock
+}
+
+// Identity returns the identity of this connector.
+func (h *History[T]) Identity() Identity {
+	return h.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (h *History[T]) Schema() Node {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return Node{
+		Identity: h.identity,
+		Type:     "history",
+		Flow:     HistoryFlow{Processor: h.processor.Schema()},
+		Metadata: map[string]any{
+			"size":          len(h.entries),
+			"failures_only": h.failuresOnly,
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (h *History[T]) Close() error {
+	h.closeOnce.Do(func() {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+		h.closeErr = h.processor.Close()
+	})
+	return h.closeErr
+}
@@ -0,0 +1,162 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	call := Apply(NewIdentity("call", ""), func(_ context.Context, n int) (int, error) {
+		if n < 0 {
+			return n, errors.New("upstream down")
+		}
+		return n, nil
+	})
+
+	t.Run("Keeps Last Entries", func(t *testing.T) {
+		history := NewHistory(NewIdentity("history", ""), call, 3)
+		for i := 1; i <= 5; i++ {
+			_, _ = history.Process(ctx, i) //nolint:errcheck // recording under test
+		}
+		entries := history.Entries()
+		if len(entries) != 3 {
+			t.Fatalf("entries = %d, want 3", len(entries))
+		}
+		for i := 1; i < len(entries); i++ {
+			if entries[i].Timestamp.Before(entries[i-1].Timestamp) {
+				t.Errorf("entries out of order: %+v", entries)
+			}
+		}
+		if entries[0].Processor != "call" || entries[0].Error != "" {
+			t.Errorf("unexpected entry: %+v", entries[0])
+		}
+	})
+
+	t.Run("Records Failing Node", func(t *testing.T) {
+		breaker := NewCircuitBreaker(NewIdentity("breaker", ""), call, 2, time.Minute)
+		history := NewHistory(NewIdentity("history", ""), breaker, 10)
+		for i := 0; i < 3; i++ {
+			_, _ = history.Process(ctx, -1) //nolint:errcheck // recording under test
+		}
+		failures := history.Failures()
+		if len(failures) != 3 {
+			t.Fatalf("failures = %d, want 3", len(failures))
+		}
+		if failures[0].Processor != "call" || !strings.Contains(failures[0].Error, "upstream down") {
+			t.Errorf("first failure = %+v", failures[0])
+		}
+		if failures[2].Processor != "breaker" || !strings.Contains(failures[2].Error, "circuit breaker is open") {
+			t.Errorf("breaker rejection = %+v", failures[2])
+		}
+		if failures[0].Category != ErrorCategoryError {
+			t.Errorf("category = %q", failures[0].Category)
+		}
+	})
+
+	t.Run("Failures Only", func(t *testing.T) {
+		history := NewHistory(NewIdentity("history", ""), call, 2).SetFailuresOnly(true)
+		_, _ = history.Process(ctx, -1) //nolint:errcheck // recording under test
+		for i := 0; i < 5; i++ {
+			_, _ = history.Process(ctx, 1) //nolint:errcheck // recording under test
+		}
+		if entries := history.Entries(); len(entries) != 1 || entries[0].Error == "" {
+			t.Errorf("expected the failure retained, got %+v", entries)
+		}
+	})
+
+	t.Run("Resize Keeps Newest", func(t *testing.T) {
+		history := NewHistory(NewIdentity("history", ""), call, 4)
+		for _, n := range []int{1, -1, 2, -2} {
+			_, _ = history.Process(ctx, n) //nolint:errcheck // recording under test
+		}
+		history.SetSize(2)
+		entries := history.Entries()
+		if len(entries) != 2 || entries[0].Error != "" || entries[1].Error == "" {
+			t.Errorf("after shrink: %+v", entries)
+		}
+		history.SetSize(3)
+		_, _ = history.Process(ctx, 3) //nolint:errcheck // recording under test
+		if entries := history.Entries(); len(entries) != 3 || entries[2].Error != "" {
+			t.Errorf("after grow: %+v", entries)
+		}
+		if len(history.Clear().Entries()) != 0 {
+			t.Error("expected no entries after Clear")
+		}
+	})
+
+	t.Run("Retention Policy", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		history := NewHistory(NewIdentity("history", ""), call, 10).WithClock(clock).SetRetention(RetentionPolicy{
+			Redact: func(v any) any {
+				if msg, ok := v.(string); ok {
+					return strings.ReplaceAll(msg, "upstream", "<host>")
+				}
+				return v
+			},
+			TTL:        time.Minute,
+			SampleRate: 0.5,
+		})
+		for _, n := range []int{-1, 2, -3, 4} {
+			_, _ = history.Process(ctx, n) //nolint:errcheck // recording under test
+		}
+		entries := history.Entries()
+		if len(entries) != 2 || entries[1].Error == "" {
+			t.Fatalf("expected every other execution, got %+v", entries)
+		}
+		for _, entry := range entries {
+			if !strings.Contains(entry.Error, "<host> down") || strings.Contains(entry.Error, "upstream") {
+				t.Errorf("expected a redacted message, got %q", entry.Error)
+			}
+		}
+		clock.Advance(2 * time.Minute)
+		if entries := history.Entries(); len(entries) != 0 {
+			t.Errorf("expected entries past the TTL dropped, got %+v", entries)
+		}
+	})
+
+	t.Run("Process-Wide Retention Policy", func(t *testing.T) {
+		SetRetentionPolicy(RetentionPolicy{MaxBytes: 4})
+		defer SetRetentionPolicy(RetentionPolicy{})
+		history := NewHistory(NewIdentity("history", ""), call, 3)
+		_, _ = history.Process(ctx, -1) //nolint:errcheck // recording under test
+		if entries := history.Entries(); len(entries) != 1 || len(entries[0].Error) != 4 {
+			t.Errorf("expected the message truncated to 4 bytes, got %+v", entries)
+		}
+	})
+
+	t.Run("Error Path", func(t *testing.T) {
+		history := NewHistory(NewIdentity("history", ""), call, 2)
+		_, err := history.Process(ctx, -1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "history" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Entries Encode As JSON", func(t *testing.T) {
+		history := NewHistory(NewIdentity("history", ""), call, 2)
+		_, _ = history.Process(ctx, -1) //nolint:errcheck // recording under test
+		data, err := json.Marshal(history.Entries())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"processor":"call"`) {
+			t.Errorf("unexpected encoding: %s", data)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := NewHistory(NewIdentity("history", ""), call, 5).Schema()
+		flow, ok := HistoryKey.From(node)
+		if !ok || flow.Processor.Identity.Name() != "call" || node.Metadata["size"] != 5 {
+			t.Errorf("unexpected schema: %+v", node)
+		}
+	})
+}
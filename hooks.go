@@ -0,0 +1,41 @@
+package pipz
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// processorHooks are the optional checks a Processor makes before running
+// its function: the panic circuit, the FaultHook, and the simulated and
+// calibration runs in progress. All are off in production, where the
+// active hooks are nil and every processor call costs one pointer load.
+type processorHooks struct {
+	fault        *FaultHook
+	panicCircuit bool
+	simulations  int32
+	calibrations int32
+}
+
+var (
+	// activeHooks is the hooks in effect; nil when every hook is off.
+	activeHooks atomic.Pointer[processorHooks]
+	// hooksMu serializes updates of activeHooks.
+	hooksMu sync.Mutex
+)
+
+// updateHooks applies update to a copy of the active hooks and puts the
+// copy in effect.
+func updateHooks(update func(*processorHooks)) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	var next processorHooks
+	if current := activeHooks.Load(); current != nil {
+		next = *current
+	}
+	update(&next)
+	if next == (processorHooks{}) {
+		activeHooks.Store(nil)
+		return
+	}
+	activeHooks.Store(&next)
+}
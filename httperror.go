@@ -0,0 +1,212 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+)
+
+// ErrNotFound marks a failure because the item a processor looked up does
+// not exist. Processors wrap it so API adapters can answer 404.
+var ErrNotFound = errors.New("not found")
+
+// StatusClientClosedRequest is the non-standard status reported for
+// requests whose caller gave up before the pipeline finished.
+const StatusClientClosedRequest = 499
+
+// ProblemContentType is the media type of HTTPProblem bodies.
+const ProblemContentType = "application/problem+json"
+
+// HTTPProblem is an RFC 9457 problem details body. Code is a stable,
+// machine-readable name for the kind of failure, such as "rate-limited".
+type HTTPProblem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	Category string `json:"category,omitempty"`
+	Status   int    `json:"status"`
+}
+
+// httpRule maps errors matching target to a status and problem code.
+type httpRule struct {
+	target error
+	code   string
+	title  string
+	status int
+}
+
+// defaultHTTPRules are the built-in mappings, checked in order after any
+// custom rules.
+var defaultHTTPRules = []httpRule{
+	{ErrNotFound, "not-found", "Not Found", http.StatusNotFound},
+	{ErrUnauthenticated, "unauthenticated", "Unauthorized", http.StatusUnauthorized},
+	{ErrForbidden, "forbidden", "Forbidden", http.StatusForbidden},
+	{ErrNoConsent, "forbidden", "Forbidden", http.StatusForbidden},
+	{ErrApprovalDenied, "forbidden", "Forbidden", http.StatusForbidden},
+	{ErrPendingApproval, "pending-approval", "Accepted", http.StatusAccepted},
+	{ErrRateLimited, "rate-limited", "Too Many Requests", http.StatusTooManyRequests},
+	{ErrPacerQueueFull, "rate-limited", "Too Many Requests", http.StatusTooManyRequests},
+	{ErrBatchBudgetExhausted, "rate-limited", "Too Many Requests", http.StatusTooManyRequests},
+	{ErrCircuitOpen, "breaker-open", "Service Unavailable", http.StatusServiceUnavailable},
+	{ErrShed, "overloaded", "Service Unavailable", http.StatusServiceUnavailable},
+	{ErrProcessorQuarantined, "unavailable", "Service Unavailable", http.StatusServiceUnavailable},
+	{ErrNotReady, "unavailable", "Service Unavailable", http.StatusServiceUnavailable},
+	{ErrOutsideWindow, "unavailable", "Service Unavailable", http.StatusServiceUnavailable},
+}
+
+// HTTPErrorMapper derives HTTP status codes and problem+json bodies from
+// pipeline errors, so every service built on pipz answers with the same
+// API error semantics. Errors are matched, in order, against rules added
+// with Map, the built-in sentinel rules, and finally the error's Category:
+//
+//	ErrNotFound                              404 not-found
+//	ErrUnauthenticated                       401 unauthenticated
+//	ErrForbidden, ErrNoConsent,
+//	ErrApprovalDenied                        403 forbidden
+//	ErrPendingApproval                       202 pending-approval
+//	ErrRateLimited, ErrPacerQueueFull,
+//	ErrBatchBudgetExhausted                  429 rate-limited
+//	ErrCircuitOpen                           503 breaker-open
+//	ErrProcessorQuarantined, ErrNotReady,
+//	ErrOutsideWindow                         503 unavailable
+//	other rejections (validation, guards)    422 validation
+//	timeouts                                 504 timeout
+//	cancellations                            499 canceled
+//	anything else                            500 internal
+//
+// Problem bodies carry the underlying error message as Detail only for
+// client errors and pending approvals; server errors omit it so internal
+// failures are not exposed to callers. A mapper is safe for concurrent use
+// once configured.
+//
+// Example:
+//
+//	mapper := pipz.NewHTTPErrorMapper().
+//	    SetTypeBase("https://errors.example.com/").
+//	    Map(ErrDuplicateOrder, http.StatusConflict, "duplicate-order", "Conflict")
+//
+//	result, err := pipeline.Process(r.Context(), order)
+//	if err != nil {
+//	    mapper.Write(w, err)
+//	    return
+//	}
+type HTTPErrorMapper struct {
+	typeBase string
+	rules    []httpRule
+}
+
+// NewHTTPErrorMapper creates a mapper with the built-in rules.
+func NewHTTPErrorMapper() *HTTPErrorMapper {
+	return &HTTPErrorMapper{}
+}
+
+// Map adds a rule mapping errors matching target, by errors.Is, to status
+// with the given problem code and title. Rules added with Map take
+// precedence over the built-in rules, in the order they were added.
+func (m *HTTPErrorMapper) Map(target error, status int, code, title string) *HTTPErrorMapper {
+	m.rules = append(m.rules, httpRule{target: target, code: code, title: title, status: status})
+	return m
+}
+
+// SetTypeBase sets the URI prefix for problem types: each problem's Type is
+// the base followed by its code. Without a base Type is "about:blank".
+func (m *HTTPErrorMapper) SetTypeBase(base string) *HTTPErrorMapper {
+	m.typeBase = base
+	return m
+}
+
+// Status returns the HTTP status code for err, or 200 for nil.
+func (m *HTTPErrorMapper) Status(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return m.classify(err).status
+}
+
+// Problem returns the problem details body for err.
+func (m *HTTPErrorMapper) Problem(err error) HTTPProblem {
+	rule := m.classify(err)
+	problem := HTTPProblem{
+		Type:   "about:blank",
+		Title:  rule.title,
+		Code:   rule.code,
+		Status: rule.status,
+	}
+	if m.typeBase != "" {
+		problem.Type = m.typeBase + rule.code
+	}
+
+	var categorized interface{ Category() string }
+	if errors.As(err, &categorized) {
+		problem.Category = categorized.Category()
+	}
+	if rule.status < http.StatusInternalServerError {
+		problem.Detail = problemDetail(err, categorized)
+	}
+	return problem
+}
+
+// Write writes err's problem details to w as application/problem+json with
+// its status code.
+func (m *HTTPErrorMapper) Write(w http.ResponseWriter, err error) error {
+	problem := m.Problem(err)
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	return json.NewEncoder(w).Encode(problem)
+}
+
+// classify returns the first rule matching err.
+func (m *HTTPErrorMapper) classify(err error) httpRule {
+	for _, rules := range [][]httpRule{m.rules, defaultHTTPRules} {
+		for _, rule := range rules {
+			if errors.Is(err, rule.target) {
+				return rule
+			}
+		}
+	}
+
+	category := ""
+	var categorized interface{ Category() string }
+	if errors.As(err, &categorized) {
+		category = categorized.Category()
+	}
+	switch {
+	case category == ErrorCategoryRejected || slices.ContainsFunc(rejectionErrors, func(target error) bool {
+		return errors.Is(err, target)
+	}):
+		return httpRule{code: "validation", title: "Unprocessable Entity", status: http.StatusUnprocessableEntity}
+	case category == ErrorCategoryTimeout || errors.Is(err, context.DeadlineExceeded):
+		return httpRule{code: "timeout", title: "Gateway Timeout", status: http.StatusGatewayTimeout}
+	case category == ErrorCategoryCanceled || errors.Is(err, context.Canceled):
+		return httpRule{code: "canceled", title: "Client Closed Request", status: StatusClientClosedRequest}
+	}
+	return httpRule{code: "internal", title: "Internal Server Error", status: http.StatusInternalServerError}
+}
+
+// problemDetail returns the failure's own message, without the pipeline
+// path a pipz Error adds.
+func problemDetail(err error, categorized interface{ Category() string }) string {
+	if wrapper, ok := categorized.(error); ok {
+		if cause := errors.Unwrap(wrapper); cause != nil {
+			return cause.Error()
+		}
+	}
+	return err.Error()
+}
+
+// defaultHTTPErrorMapper backs HTTPStatus and WriteProblem.
+var defaultHTTPErrorMapper = NewHTTPErrorMapper()
+
+// HTTPStatus returns the HTTP status code for err using the built-in rules.
+func HTTPStatus(err error) int {
+	return defaultHTTPErrorMapper.Status(err)
+}
+
+// WriteProblem writes err's problem details to w using the built-in rules.
+func WriteProblem(w http.ResponseWriter, err error) error {
+	return defaultHTTPErrorMapper.Write(w, err)
+}
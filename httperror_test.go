@@ -0,0 +1,104 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPErrorMapper(t *testing.T) {
+	ctx := context.Background()
+	failWith := func(err error) error {
+		_, got := Apply(NewIdentity("step", ""), func(_ context.Context, n int) (int, error) {
+			return n, err
+		}).Process(ctx, 1)
+		return got
+	}
+
+	t.Run("Built In Statuses", func(t *testing.T) {
+		cases := []struct {
+			err    error
+			status int
+		}{
+			{nil, http.StatusOK},
+			{failWith(fmt.Errorf("order 7: %w", ErrNotFound)), http.StatusNotFound},
+			{failWith(ErrUnauthenticated), http.StatusUnauthorized},
+			{failWith(ErrForbidden), http.StatusForbidden},
+			{failWith(ErrValidationFailed), http.StatusUnprocessableEntity},
+			{ErrGuardRejected, http.StatusUnprocessableEntity},
+			{failWith(ErrPacerQueueFull), http.StatusTooManyRequests},
+			{failWith(context.DeadlineExceeded), http.StatusGatewayTimeout},
+			{context.Canceled, StatusClientClosedRequest},
+			{failWith(errors.New("disk full")), http.StatusInternalServerError},
+		}
+		for _, c := range cases {
+			if got := HTTPStatus(c.err); got != c.status {
+				t.Errorf("HTTPStatus(%v) = %d, want %d", c.err, got, c.status)
+			}
+		}
+	})
+
+	t.Run("Connector Errors", func(t *testing.T) {
+		step := Apply(NewIdentity("call", ""), func(_ context.Context, n int) (int, error) { return n, errors.New("down") })
+		breaker := NewCircuitBreaker(NewIdentity("breaker", ""), step, 1, time.Minute)
+		_, _ = breaker.Process(ctx, 1) //nolint:errcheck // opens the breaker
+		_, err := breaker.Process(ctx, 1)
+		if problem := NewHTTPErrorMapper().Problem(err); problem.Status != http.StatusServiceUnavailable || problem.Code != "breaker-open" {
+			t.Errorf("breaker problem = %+v", problem)
+		}
+
+		limiter := NewRateLimiter(NewIdentity("limiter", ""), 0, 0, step).SetMode("drop")
+		_, err = limiter.Process(ctx, 1)
+		if problem := NewHTTPErrorMapper().Problem(err); problem.Status != http.StatusTooManyRequests || problem.Code != "rate-limited" {
+			t.Errorf("limiter problem = %+v", problem)
+		}
+	})
+
+	t.Run("Detail Only For Client Errors", func(t *testing.T) {
+		mapper := NewHTTPErrorMapper()
+		problem := mapper.Problem(failWith(fmt.Errorf("order 7: %w", ErrNotFound)))
+		if problem.Detail != "order 7: not found" || problem.Category != ErrorCategoryError {
+			t.Errorf("client problem = %+v", problem)
+		}
+		if problem := mapper.Problem(failWith(errors.New("db password rejected"))); problem.Detail != "" {
+			t.Errorf("server error detail leaked: %q", problem.Detail)
+		}
+	})
+
+	t.Run("Custom Rules Take Precedence", func(t *testing.T) {
+		errDuplicate := fmt.Errorf("duplicate order: %w", ErrGuardRejected)
+		mapper := NewHTTPErrorMapper().
+			SetTypeBase("https://errors.example.com/").
+			Map(errDuplicate, http.StatusConflict, "duplicate-order", "Conflict")
+
+		problem := mapper.Problem(failWith(errDuplicate))
+		if problem.Status != http.StatusConflict || problem.Type != "https://errors.example.com/duplicate-order" {
+			t.Errorf("custom problem = %+v", problem)
+		}
+		if problem := mapper.Problem(failWith(ErrGuardRejected)); problem.Code != "validation" {
+			t.Errorf("unmatched error used custom rule: %+v", problem)
+		}
+	})
+
+	t.Run("Write Problem", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		if err := WriteProblem(rec, failWith(ErrForbidden)); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusForbidden || rec.Header().Get("Content-Type") != ProblemContentType {
+			t.Errorf("status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+		}
+		var body HTTPProblem
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Type != "about:blank" || body.Title != "Forbidden" || body.Status != http.StatusForbidden || body.Category != ErrorCategoryRejected {
+			t.Errorf("body = %+v", body)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// idempotencyKey is the context key for the idempotency key.
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a context carrying key as the idempotency key
+// of the logical request, for example one received in an Idempotency-Key
+// header. An Idempotency connector processing with this context keeps it
+// rather than generating its own.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key of the logical
+// request being processed. Returns the key and true if present, or "" and
+// false otherwise.
+//
+// Example:
+//
+//	charge := pipz.Apply(ChargeID, func(ctx context.Context, p Payment) (Payment, error) {
+//	    key, _ := pipz.IdempotencyKeyFromContext(ctx)
+//	    return p, gateway.Charge(ctx, p, key)
+//	})
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	key, ok := ctx.Value(idempotencyKey{}).(string)
+	return key, ok
+}
+
+// Idempotency gives each logical request an idempotency key, exposed to
+// processors through IdempotencyKeyFromContext, so retried side effects such
+// as payment or API calls can be deduplicated by the server receiving them.
+// Place it outside Retry or Backoff: every attempt then runs under the same
+// context and sends the same key.
+//
+// The key is, in order of preference, one already carried by the context
+// (from WithIdempotencyKey or an enclosing Idempotency), one derived from
+// the data by the key function, or a new random UUID.
+//
+// Example:
+//
+//	var PayID = pipz.NewIdentity("pay", "Charges each order exactly once")
+//	pay := pipz.NewIdempotency(PayID,
+//	    pipz.NewBackoff(ChargeRetryID, charge, 5, 100*time.Millisecond),
+//	).SetKeyFunc(func(_ context.Context, o Order) string {
+//	    return "order-" + o.ID
+//	})
+type Idempotency[T any] struct {
+	processor Chainable[T]
+	keyFunc   func(context.Context, T) string
+	identity  Identity
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewIdempotency creates an Idempotency connector that generates a random
+// key per logical request.
+func NewIdempotency[T any](identity Identity, processor Chainable[T]) *Idempotency[T] {
+	return &Idempotency[T]{
+		identity:  identity,
+		processor: processor,
+	}
+}
+
+// Process implements the Chainable interface.
+// Ensures the context carries an idempotency key, then runs the processor.
+func (i *Idempotency[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, i.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, i, i.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	i.mu.RLock()
+	processor := i.processor
+	keyFunc := i.keyFunc
+	i.mu.RUnlock()
+
+	if _, ok := IdempotencyKeyFromContext(ctx); !ok {
+		var key string
+		if keyFunc != nil {
+			key = keyFunc(ctx, data)
+		}
+		if key == "" {
+			key = uuid.NewString()
+		}
+		ctx = WithIdempotencyKey(ctx, key)
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, i.identity)
+			return result, pipeErr
+		}
+		return data, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{i.identity},
+		}
+	}
+	return result, nil
+}
+
+// SetKeyFunc derives the key from the data, so redelivered copies of the
+// same message share a key. An empty result falls back to a random key.
+func (i *Idempotency[T]) SetKeyFunc(keyFunc func(context.Context, T) string) *Idempotency[T] {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keyFunc = keyFunc
+	return i
+}
+
+// SetProcessor updates the wrapped processor.
+func (i *Idempotency[T]) SetProcessor(processor Chainable[T]) *Idempotency[T] {
+	noteChildren(i.identity, processor)
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.processor = processor
+	return i
+}
+
+// Identity returns the identity of this connector.
+func (i *Idempotency[T]) Identity() Identity {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (i *Idempotency[T]) Schema() Node {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	keySource := "generated"
+	if i.keyFunc != nil {
+		keySource = "data"
+	}
+	return Node{
+		Identity: i.identity,
+		Type:     "idempotency",
+		Flow:     IdempotencyFlow{Processor: i.processor.Schema()},
+		Metadata: map[string]any{"key_source": keySource},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (i *Idempotency[T]) Close() error {
+	i.closeOnce.Do(func() {
+		i.mu.RLock()
+		defer i.mu.RUnlock()
+		i.closeErr = i.processor.Close()
+	})
+	return i.closeErr
+}
@@ -0,0 +1,120 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIdempotency(t *testing.T) {
+	t.Run("Key Is Stable Across Retry Attempts", func(t *testing.T) {
+		var keys []string
+		flaky := Apply(NewIdentity("charge", ""), func(ctx context.Context, v int) (int, error) {
+			key, ok := IdempotencyKeyFromContext(ctx)
+			if !ok {
+				return v, errors.New("missing key")
+			}
+			keys = append(keys, key)
+			if len(keys) < 3 {
+				return v, errors.New("transient")
+			}
+			return v, nil
+		})
+		pay := NewIdempotency(NewIdentity("pay", ""), NewRetry(NewIdentity("retry", ""), flaky, 3))
+
+		if _, err := pay.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(keys) != 3 || keys[0] == "" || keys[0] != keys[1] || keys[1] != keys[2] {
+			t.Errorf("expected one key across 3 attempts, got %v", keys)
+		}
+	})
+
+	t.Run("Each Request Gets A New Key", func(t *testing.T) {
+		var keys []string
+		record := Effect(NewIdentity("record", ""), func(ctx context.Context, _ int) error {
+			key, _ := IdempotencyKeyFromContext(ctx)
+			keys = append(keys, key)
+			return nil
+		})
+		pay := NewIdempotency(NewIdentity("pay", ""), record)
+		for range 2 {
+			if _, err := pay.Process(context.Background(), 1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if keys[0] == keys[1] {
+			t.Errorf("expected distinct keys per request, got %v", keys)
+		}
+	})
+
+	t.Run("Existing Key Is Kept", func(t *testing.T) {
+		var got string
+		record := Effect(NewIdentity("record", ""), func(ctx context.Context, _ int) error {
+			got, _ = IdempotencyKeyFromContext(ctx)
+			return nil
+		})
+		pay := NewIdempotency(NewIdentity("pay", ""), record).
+			SetKeyFunc(func(context.Context, int) string { return "from-data" })
+
+		ctx := WithIdempotencyKey(context.Background(), "from-header")
+		if _, err := pay.Process(ctx, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "from-header" {
+			t.Errorf("expected caller's key, got %q", got)
+		}
+	})
+
+	t.Run("Key Derived From Data", func(t *testing.T) {
+		var got string
+		record := Effect(NewIdentity("record", ""), func(ctx context.Context, _ int) error {
+			got, _ = IdempotencyKeyFromContext(ctx)
+			return nil
+		})
+		pay := NewIdempotency(NewIdentity("pay", ""), record).
+			SetKeyFunc(func(_ context.Context, v int) string {
+				if v == 0 {
+					return ""
+				}
+				return "order-7"
+			})
+
+		if _, err := pay.Process(context.Background(), 7); err != nil || got != "order-7" {
+			t.Errorf("expected derived key, got %q, %v", got, err)
+		}
+		if _, err := pay.Process(context.Background(), 0); err != nil || got == "" {
+			t.Errorf("expected generated key for empty derivation, got %q, %v", got, err)
+		}
+	})
+
+	t.Run("Error Path", func(t *testing.T) {
+		boom := errors.New("boom")
+		fail := Apply(NewIdentity("charge", ""), func(_ context.Context, v int) (int, error) { return v, boom })
+		pay := NewIdempotency(NewIdentity("pay", ""), fail)
+
+		_, err := pay.Process(context.Background(), 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "pay" {
+			t.Fatalf("expected path pay -> charge, got %v", err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		noop := Transform(NewIdentity("noop", ""), func(_ context.Context, v int) int { return v })
+		node := NewIdempotency(NewIdentity("pay", ""), noop).Schema()
+		flow, ok := IdempotencyKey.From(node)
+		if !ok || flow.Processor.Identity.Name() != "noop" {
+			t.Fatalf("expected IdempotencyFlow wrapping noop, got %+v", node.Flow)
+		}
+		if node.Metadata["key_source"] != "generated" {
+			t.Errorf("expected generated key source, got %v", node.Metadata["key_source"])
+		}
+	})
+
+	t.Run("Missing Key", func(t *testing.T) {
+		if _, ok := IdempotencyKeyFromContext(context.Background()); ok {
+			t.Error("expected no key outside an Idempotency connector")
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package pipz
+
+import (
+	"context"
+	"fmt"
+)
+
+// dependencyKey is a per-type context key for injected dependencies.
+type dependencyKey[D any] struct{}
+
+// Provide returns a context carrying a dependency of type D for processors
+// to retrieve with Use. The dependency's type is its key, so each type holds
+// one value; wrap shared types to distinguish instances (for example,
+// type ReadDB *sql.DB and type WriteDB *sql.DB).
+//
+// Providing dependencies through the context keeps processor closures free
+// of package-level globals and lets tests inject fakes per call.
+//
+// Example:
+//
+//	ctx = pipz.Provide(ctx, paymentClient)
+//	ctx = pipz.Provide(ctx, logger)
+//	result, err := pipeline.Process(ctx, order)
+func Provide[D any](ctx context.Context, dependency D) context.Context {
+	return context.WithValue(ctx, dependencyKey[D]{}, dependency)
+}
+
+// Use retrieves a dependency of type D provided with Provide.
+// Returns the dependency and true if present, or the zero value and false otherwise.
+//
+// Example:
+//
+//	charge := pipz.Apply(ChargeID, func(ctx context.Context, o Order) (Order, error) {
+//	    client, ok := pipz.Use[PaymentClient](ctx)
+//	    if !ok {
+//	        return o, errors.New("payment client not provided")
+//	    }
+//	    return o, client.Charge(ctx, o.Total)
+//	})
+func Use[D any](ctx context.Context) (D, bool) {
+	var zero D
+	if ctx == nil {
+		return zero, false
+	}
+	d, ok := ctx.Value(dependencyKey[D]{}).(D)
+	return d, ok
+}
+
+// MustUse retrieves a dependency of type D, panicking if it was not provided.
+// Inside a processor the panic is recovered and reported as a processor
+// error, so a missing dependency fails the item rather than the program.
+func MustUse[D any](ctx context.Context) D {
+	d, ok := Use[D](ctx)
+	if !ok {
+		var zero D
+		panic(fmt.Sprintf("pipz: dependency %T not provided", zero))
+	}
+	return d
+}
@@ -0,0 +1,74 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type testClient interface {
+	Lookup(id string) string
+}
+
+type fakeClient struct{ prefix string }
+
+func (f fakeClient) Lookup(id string) string { return f.prefix + id }
+
+type readDB string
+type writeDB string
+
+func TestProvideUse(t *testing.T) {
+	t.Run("Round Trip", func(t *testing.T) {
+		ctx := Provide[testClient](context.Background(), fakeClient{prefix: "x-"})
+		client, ok := Use[testClient](ctx)
+		if !ok || client.Lookup("1") != "x-1" {
+			t.Errorf("expected injected client, got %v (ok=%v)", client, ok)
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		if _, ok := Use[testClient](context.Background()); ok {
+			t.Error("expected missing dependency")
+		}
+		var nilCtx context.Context
+		if _, ok := Use[testClient](nilCtx); ok {
+			t.Error("expected missing dependency for nil context")
+		}
+	})
+
+	t.Run("Distinct Types Do Not Collide", func(t *testing.T) {
+		ctx := Provide(context.Background(), readDB("replica"))
+		ctx = Provide(ctx, writeDB("primary"))
+		r, _ := Use[readDB](ctx)
+		w, _ := Use[writeDB](ctx)
+		if r != "replica" || w != "primary" {
+			t.Errorf("expected replica and primary, got %q and %q", r, w)
+		}
+	})
+
+	t.Run("Used From Processor", func(t *testing.T) {
+		lookup := Apply(NewIdentity("lookup", ""), func(ctx context.Context, id string) (string, error) {
+			client, ok := Use[testClient](ctx)
+			if !ok {
+				return id, errors.New("client not provided")
+			}
+			return client.Lookup(id), nil
+		})
+		ctx := Provide[testClient](context.Background(), fakeClient{prefix: "fake-"})
+		result, err := lookup.Process(ctx, "42")
+		if err != nil || result != "fake-42" {
+			t.Errorf("expected fake-42, got %q (%v)", result, err)
+		}
+	})
+
+	t.Run("MustUse Panic Becomes Processor Error", func(t *testing.T) {
+		lookup := Transform(NewIdentity("lookup", ""), func(ctx context.Context, id string) string {
+			return MustUse[testClient](ctx).Lookup(id)
+		})
+		_, err := lookup.Process(context.Background(), "42")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected processor error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package pipz
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Interner shares structurally identical sub-pipelines, so services that
+// build a pipeline per tenant or per configuration keep one copy of each
+// common subtree instead of thousands. Intern returns the first pipeline
+// it saw with the same structure, letting the duplicate be collected.
+//
+// Two pipelines are identical when their schemas are equal: the same
+// identities composed with the same connectors and metadata. Interning
+// therefore relies on identities being declared once per behavior, as
+// package-level variables; a processor that closes over per-tenant values
+// must not reuse one identity for every tenant, or tenants would share the
+// first tenant's closure.
+//
+// Interning shares state as well as memory: every holder of an interned
+// subtree uses the same circuit breakers, rate limiters, and caches.
+// Intern only subtrees whose stateful connectors should be shared, and
+// build per-tenant state outside them.
+//
+// Example:
+//
+//	var shared = pipz.NewInterner[Order]()
+//
+//	func tenantPipeline(cfg TenantConfig) pipz.Chainable[Order] {
+//	    return pipz.NewSequence(TenantPipelineID,
+//	        tenantPricing(cfg),                  // Varies per tenant
+//	        shared.Intern(standardFulfillment()), // Built once in memory
+//	    )
+//	}
+type Interner[T any] struct {
+	byHash map[uint64][]internEntry[T]
+	hits   int64
+	unique int
+	mu     sync.Mutex
+}
+
+// internEntry is one interned pipeline with the schema it was matched by.
+type internEntry[T any] struct {
+	processor Chainable[T]
+	schema    Node
+}
+
+// InternStats counts the pipelines an Interner has seen.
+type InternStats struct {
+	// Unique is the number of distinct pipelines held.
+	Unique int `json:"unique"`
+	// Hits is the number of duplicates replaced by a held pipeline.
+	Hits int64 `json:"hits"`
+}
+
+// NewInterner creates an empty Interner.
+func NewInterner[T any]() *Interner[T] {
+	return &Interner[T]{byHash: make(map[uint64][]internEntry[T])}
+}
+
+// Intern returns the held pipeline identical to processor, or holds and
+// returns processor if there is none. A replaced duplicate is not closed,
+// as it may share children with the held pipeline; close it only if it
+// owns resources of its own.
+func (in *Interner[T]) Intern(processor Chainable[T]) Chainable[T] {
+	schema := processor.Schema()
+	hash := DeepHash(schema)
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	for _, entry := range in.byHash[hash] {
+		// Hashes can collide, so confirm the match structurally
+		if reflect.DeepEqual(entry.schema, schema) {
+			in.hits++
+			return entry.processor
+		}
+	}
+	in.byHash[hash] = append(in.byHash[hash], internEntry[T]{processor: processor, schema: schema})
+	in.unique++
+	return processor
+}
+
+// Stats returns the Interner's counters.
+func (in *Interner[T]) Stats() InternStats {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return InternStats{Unique: in.unique, Hits: in.hits}
+}
+
+// Reset forgets every held pipeline without closing it, so later calls
+// to Intern start afresh. Pipelines already handed out are unaffected.
+func (in *Interner[T]) Reset() {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.byHash = make(map[uint64][]internEntry[T])
+	in.unique = 0
+	in.hits = 0
+}
@@ -0,0 +1,70 @@
+package pipz
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInterner(t *testing.T) {
+	var (
+		fulfillID = testIdentity("fulfill")
+		pickID    = testIdentity("pick")
+		shipID    = testIdentity("ship")
+		timeoutID = testIdentity("ship-timeout")
+	)
+	pick := Transform(pickID, func(_ context.Context, n int) int { return n + 1 })
+	ship := Transform(shipID, func(_ context.Context, n int) int { return n * 10 })
+
+	// fulfillment builds a fresh copy of the shared subtree, as a
+	// per-tenant constructor would.
+	fulfillment := func(shipTimeout time.Duration) Chainable[int] {
+		return NewSequence(fulfillID, pick, NewTimeout(timeoutID, ship, shipTimeout))
+	}
+
+	t.Run("Shares Identical Subtrees", func(t *testing.T) {
+		in := NewInterner[int]()
+		first := in.Intern(fulfillment(time.Second))
+		second := in.Intern(fulfillment(time.Second))
+		if first != second {
+			t.Error("expected identical subtrees to be shared")
+		}
+		if out, err := second.Process(context.Background(), 1); err != nil || out != 20 {
+			t.Errorf("expected 20, got %d, %v", out, err)
+		}
+		if stats := in.Stats(); stats.Unique != 1 || stats.Hits != 1 {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	})
+
+	t.Run("Keeps Different Structures Apart", func(t *testing.T) {
+		in := NewInterner[int]()
+		base := in.Intern(fulfillment(time.Second))
+		variants := []Chainable[int]{
+			fulfillment(2 * time.Second),
+			NewSequence(fulfillID, pick, ship),
+			NewSequence(fulfillID, ship, pick),
+			NewSequence(testIdentity("fulfill"), pick, NewTimeout(timeoutID, ship, time.Second)),
+		}
+		for i, v := range variants {
+			if got := in.Intern(v); got == base || got != v {
+				t.Errorf("variant %d: expected its own entry", i)
+			}
+		}
+		if stats := in.Stats(); stats.Unique != 5 || stats.Hits != 0 {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		in := NewInterner[int]()
+		first := in.Intern(fulfillment(time.Second))
+		in.Reset()
+		if in.Intern(fulfillment(time.Second)) == first {
+			t.Error("expected Reset to forget held pipelines")
+		}
+		if stats := in.Stats(); stats.Unique != 1 || stats.Hits != 0 {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package pipz
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrIsolationViolated is returned when Policy.CheckIsolation is enabled and
+// a processor running on a clone mutated state shared with the original
+// input, meaning the type's Clone method is too shallow.
+var ErrIsolationViolated = errors.New("clone isolation violated: original input was mutated")
+
+// DeepHash returns a hash of everything reachable from v: struct fields
+// (exported or not), slice and array elements, map entries (independent of
+// iteration order), and the targets of pointers and interfaces. Two values
+// with equal deep contents hash equally, so comparing hashes taken before
+// and after an operation reveals mutation through shared references.
+// Functions and channels are hashed by identity. Cycles are followed once.
+func DeepHash(v any) uint64 {
+	h := fnv.New64a()
+	hashValue(h, reflect.ValueOf(v), make(map[visitKey]bool))
+	return h.Sum64()
+}
+
+// visitKey identifies a reference already hashed, to break cycles.
+type visitKey struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+func hashValue(h hash.Hash64, v reflect.Value, visited map[visitKey]bool) {
+	var buf [8]byte
+	writeUint := func(u uint64) {
+		binary.LittleEndian.PutUint64(buf[:], u)
+		_, _ = h.Write(buf[:]) //nolint:errcheck // hash writes never fail
+	}
+
+	if !v.IsValid() {
+		writeUint(0)
+		return
+	}
+	writeUint(uint64(v.Kind()))
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int())) //nolint:gosec // bit pattern is what is hashed
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeUint(math.Float64bits(real(c)))
+		writeUint(math.Float64bits(imag(c)))
+	case reflect.String:
+		writeUint(uint64(v.Len()))
+		_, _ = h.Write([]byte(v.String())) //nolint:errcheck // hash writes never fail
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashValue(h, v.Index(i), visited)
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			writeUint(0)
+			return
+		}
+		key := visitKey{typ: v.Type(), ptr: v.Pointer(), len: v.Len()}
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		writeUint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			hashValue(h, v.Index(i), visited)
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			writeUint(0)
+			return
+		}
+		key := visitKey{typ: v.Type(), ptr: v.Pointer()}
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		writeUint(uint64(v.Len()))
+		// Sum per-entry hashes so the result is independent of map order.
+		var sum uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := fnv.New64a()
+			hashValue(entry, iter.Key(), visited)
+			hashValue(entry, iter.Value(), visited)
+			sum += entry.Sum64()
+		}
+		writeUint(sum)
+	case reflect.Pointer:
+		if v.IsNil() {
+			writeUint(0)
+			return
+		}
+		key := visitKey{typ: v.Type(), ptr: v.Pointer()}
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		hashValue(h, v.Elem(), visited)
+	case reflect.Interface:
+		if v.IsNil() {
+			writeUint(0)
+			return
+		}
+		_, _ = h.Write([]byte(v.Elem().Type().String())) //nolint:errcheck // hash writes never fail
+		hashValue(h, v.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			hashValue(h, v.Field(i), visited)
+		}
+	default:
+		// Func, Chan, and UnsafePointer are compared by identity.
+		writeUint(uint64(v.Pointer()))
+	}
+}
+
+// isolationSnapshot hashes input when the policy in ctx enables isolation
+// checks, reporting whether a check should follow.
+func isolationSnapshot[T any](ctx context.Context, input T) (uint64, bool) {
+	if !PolicyFromContext(ctx).CheckIsolation {
+		return 0, false
+	}
+	return DeepHash(input), true
+}
+
+// checkIsolation fails with ErrIsolationViolated if input no longer hashes
+// to before, emitting SignalIsolationViolated.
+func checkIsolation[T any](ctx context.Context, identity Identity, input T, before uint64) *Error[T] {
+	if DeepHash(input) == before {
+		return nil
+	}
+	capitan.Error(ctx, SignalIsolationViolated,
+		FieldName.Field(identity.Name()),
+		FieldIdentityID.Field(identity.ID().String()),
+	)
+	return &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(input),
+		Err:       fmt.Errorf("%w: Clone of %T shares mutable state", ErrIsolationViolated, input),
+		Path:      []Identity{identity},
+	}
+}
@@ -0,0 +1,117 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+// shallowOrder has a Clone that forgets to copy its slice and map.
+type shallowOrder struct {
+	Meta  map[string]string
+	Items []string
+}
+
+func (o shallowOrder) Clone() shallowOrder { return o }
+
+// deepOrder clones its references properly.
+type deepOrder struct {
+	Meta  map[string]string
+	Items []string
+}
+
+func (o deepOrder) Clone() deepOrder {
+	meta := make(map[string]string, len(o.Meta))
+	for k, v := range o.Meta {
+		meta[k] = v
+	}
+	return deepOrder{Meta: meta, Items: append([]string(nil), o.Items...)}
+}
+
+func TestDeepHash(t *testing.T) {
+	type node struct {
+		Next  *node
+		Value int
+	}
+
+	t.Run("Equal Contents Hash Equally", func(t *testing.T) {
+		a := map[string][]int{"x": {1, 2}, "y": {3}}
+		b := map[string][]int{"y": {3}, "x": {1, 2}}
+		if DeepHash(a) != DeepHash(b) {
+			t.Error("expected equal hashes for equal maps")
+		}
+	})
+
+	t.Run("Reachable Changes Alter Hash", func(t *testing.T) {
+		inner := &node{Value: 1}
+		outer := node{Next: inner}
+		before := DeepHash(outer)
+		inner.Value = 2
+		if DeepHash(outer) == before {
+			t.Error("expected hash to change through pointer")
+		}
+	})
+
+	t.Run("Cycles Terminate", func(t *testing.T) {
+		n := &node{Value: 1}
+		n.Next = n
+		if DeepHash(n) == 0 {
+			t.Error("expected a hash for cyclic value")
+		}
+	})
+}
+
+func TestIsolationChecks(t *testing.T) {
+	ctx := WithPolicy(context.Background(), Policy{CheckIsolation: true})
+	appendShallow := Transform(NewIdentity("append", ""), func(_ context.Context, o shallowOrder) shallowOrder {
+		o.Meta["touched"] = "yes"
+		return o
+	})
+	appendDeep := Transform(NewIdentity("append", ""), func(_ context.Context, o deepOrder) deepOrder {
+		o.Meta["touched"] = "yes"
+		return o
+	})
+
+	t.Run("Concurrent Detects Shallow Clone", func(t *testing.T) {
+		var name string
+		listener := capitan.Hook(SignalIsolationViolated, func(_ context.Context, e *capitan.Event) {
+			name, _ = FieldName.From(e)
+		})
+		defer listener.Close()
+
+		c := NewConcurrent(NewIdentity("fanout", ""), nil, appendShallow)
+		_, err := c.Process(ctx, shallowOrder{Meta: map[string]string{}})
+		if !errors.Is(err, ErrIsolationViolated) {
+			t.Fatalf("expected ErrIsolationViolated, got %v", err)
+		}
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if name != "fanout" {
+			t.Errorf("expected signal from 'fanout', got %q", name)
+		}
+	})
+
+	t.Run("Concurrent Passes Deep Clone", func(t *testing.T) {
+		c := NewConcurrent(NewIdentity("fanout", ""), nil, appendDeep)
+		if _, err := c.Process(ctx, deepOrder{Meta: map[string]string{}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Race Detects Shallow Clone", func(t *testing.T) {
+		r := NewRace(NewIdentity("race", ""), appendShallow)
+		if _, err := r.Process(ctx, shallowOrder{Meta: map[string]string{}}); !errors.Is(err, ErrIsolationViolated) {
+			t.Fatalf("expected ErrIsolationViolated, got %v", err)
+		}
+	})
+
+	t.Run("Disabled By Default", func(t *testing.T) {
+		c := NewConcurrent(NewIdentity("fanout", ""), nil, appendShallow)
+		if _, err := c.Process(context.Background(), shallowOrder{Meta: map[string]string{}}); err != nil {
+			t.Fatalf("expected no check without policy, got %v", err)
+		}
+	})
+}
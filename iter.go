@@ -0,0 +1,97 @@
+package pipz
+
+import (
+	"context"
+	"iter"
+)
+
+// Each processes every value of seq with processor, in order, stopping at
+// the first error. Use it to drain a sequence through a pipeline for its
+// effects; use Iter to consume the results.
+//
+// Example:
+//
+//	err := pipz.Each(ctx, slices.Values(orders), fulfil)
+func Each[T any](ctx context.Context, seq iter.Seq[T], processor Chainable[T]) error {
+	for _, err := range Iter(ctx, seq, processor) {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Each2 is Each for sequences that can fail, such as rows scanned from a
+// database. It stops at the first error from seq or from processor.
+func Each2[T any](ctx context.Context, seq iter.Seq2[T, error], processor Chainable[T]) error {
+	for _, err := range Iter2(ctx, seq, processor) {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Iter returns a sequence of the results of processing each value of seq
+// with processor. Processing is lazy: each value is processed when the
+// consumer asks for its result, and stopping early leaves the rest of seq
+// unread. A failed value yields its error and iteration continues; the
+// consumer decides whether to stop. When ctx ends, its error is yielded
+// once and the sequence ends.
+//
+// Example:
+//
+//	for user, err := range pipz.Iter(ctx, slices.Values(users), normalize) {
+//	    if err != nil {
+//	        log.Printf("skipping user: %v", err)
+//	        continue
+//	    }
+//	    save(user)
+//	}
+func Iter[T any](ctx context.Context, seq iter.Seq[T], processor Chainable[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for value := range seq {
+			if err := ctx.Err(); err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yield(processor.Process(ctx, value)) {
+				return
+			}
+		}
+	}
+}
+
+// Iter2 is Iter for sequences that can fail. Errors from seq are yielded
+// as they are, without processing.
+func Iter2[T any](ctx context.Context, seq iter.Seq2[T, error], processor Chainable[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for value, err := range seq {
+			if err == nil {
+				err = ctx.Err()
+				if err != nil {
+					var zero T
+					yield(zero, err)
+					return
+				}
+				value, err = processor.Process(ctx, value)
+			}
+			if !yield(value, err) {
+				return
+			}
+		}
+	}
+}
+
+// Iter returns a sequence of the pipeline's results for each value of seq.
+// See the package-level Iter.
+func (p *Pipeline[T]) Iter(ctx context.Context, seq iter.Seq[T]) iter.Seq2[T, error] {
+	return Iter[T](ctx, seq, p)
+}
+
+// Iter2 returns a sequence of the pipeline's results for each value of a
+// sequence that can fail. See the package-level Iter2.
+func (p *Pipeline[T]) Iter2(ctx context.Context, seq iter.Seq2[T, error]) iter.Seq2[T, error] {
+	return Iter2[T](ctx, seq, p)
+}
@@ -0,0 +1,151 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIter(t *testing.T) {
+	upper := Apply(NewIdentity("upper", ""), func(_ context.Context, s string) (string, error) {
+		if s == "" {
+			return s, errors.New("empty")
+		}
+		return strings.ToUpper(s), nil
+	})
+
+	t.Run("Yields Results In Order", func(t *testing.T) {
+		var got []string
+		for v, err := range Iter(context.Background(), slices.Values([]string{"a", "b", "c"}), upper) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got = append(got, v)
+		}
+		if !slices.Equal(got, []string{"A", "B", "C"}) {
+			t.Errorf("expected [A B C], got %v", got)
+		}
+	})
+
+	t.Run("Continues Past Failures", func(t *testing.T) {
+		var failed, ok int
+		for _, err := range Iter(context.Background(), slices.Values([]string{"a", "", "c"}), upper) {
+			if err != nil {
+				failed++
+			} else {
+				ok++
+			}
+		}
+		if failed != 1 || ok != 2 {
+			t.Errorf("expected 1 failure and 2 results, got %d and %d", failed, ok)
+		}
+	})
+
+	t.Run("Stops Early Without Reading Rest", func(t *testing.T) {
+		var read int
+		seq := func(yield func(string) bool) {
+			for _, s := range []string{"a", "b", "c"} {
+				read++
+				if !yield(s) {
+					return
+				}
+			}
+		}
+		for range Iter(context.Background(), seq, upper) {
+			break
+		}
+		if read != 1 {
+			t.Errorf("expected one value read, got %d", read)
+		}
+	})
+
+	t.Run("Canceled Context Ends Sequence", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var errs []error
+		for _, err := range Iter(ctx, slices.Values([]string{"a", "b"}), upper) {
+			errs = append(errs, err)
+		}
+		if len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+			t.Errorf("expected a single cancellation error, got %v", errs)
+		}
+	})
+
+	t.Run("Iter2 Passes Source Errors Through", func(t *testing.T) {
+		source := errors.New("scan failed")
+		seq := func(yield func(string, error) bool) {
+			if !yield("a", nil) {
+				return
+			}
+			yield("", source)
+		}
+		var got []string
+		var errs []error
+		for v, err := range Iter2(context.Background(), seq, upper) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			got = append(got, v)
+		}
+		if !slices.Equal(got, []string{"A"}) || len(errs) != 1 || !errors.Is(errs[0], source) {
+			t.Errorf("expected [A] and the source error, got %v and %v", got, errs)
+		}
+		var pipeErr *Error[string]
+		if errors.As(errs[0], &pipeErr) {
+			t.Error("expected source error to be unwrapped by pipz")
+		}
+	})
+
+	t.Run("Each Stops At First Error", func(t *testing.T) {
+		var seen []string
+		record := Apply(NewIdentity("record", ""), func(ctx context.Context, s string) (string, error) {
+			seen = append(seen, s)
+			return upper.Process(ctx, s)
+		})
+		err := Each(context.Background(), slices.Values([]string{"a", "", "c"}), record)
+		if err == nil || !slices.Equal(seen, []string{"a", ""}) {
+			t.Errorf("expected to stop at the empty value, got %v after %v", err, seen)
+		}
+		if err := Each(context.Background(), slices.Values([]string{"a", "b"}), upper); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Each2 Stops At Source Error", func(t *testing.T) {
+		source := errors.New("scan failed")
+		seq := func(yield func(string, error) bool) {
+			if yield("", source) {
+				t.Error("expected Each2 to stop after the source error")
+			}
+		}
+		if err := Each2(context.Background(), seq, upper); !errors.Is(err, source) {
+			t.Errorf("expected source error, got %v", err)
+		}
+	})
+
+	t.Run("Pipeline Iter", func(t *testing.T) {
+		pipeline := NewPipeline(NewIdentity("p", ""), upper)
+		var got []string
+		for v, err := range pipeline.Iter(context.Background(), slices.Values([]string{"x", "y"})) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got = append(got, v)
+		}
+		if !slices.Equal(got, []string{"X", "Y"}) {
+			t.Errorf("expected [X Y], got %v", got)
+		}
+		if pipeline.Stats().Processed != 2 {
+			t.Errorf("expected pipeline to count 2 runs, got %d", pipeline.Stats().Processed)
+		}
+		seq := func(yield func(string, error) bool) { yield("z", nil) }
+		for v, err := range pipeline.Iter2(context.Background(), seq) {
+			if err != nil || v != "Z" {
+				t.Errorf("expected Z, got %q, %v", v, err)
+			}
+		}
+	})
+}
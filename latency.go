@@ -0,0 +1,238 @@
+package pipz
+
+import (
+	"fmt"
+	"time"
+)
+
+// UnboundedLatency reports a node whose latency no configuration bounds:
+// a processor outside every Timeout, or a connector that may wait without
+// limit, such as a Poll without a maximum wait.
+type UnboundedLatency struct {
+	Identity Identity
+	Path     string
+	Type     string
+	Reason   string
+}
+
+// LatencyEstimate bounds how long a pipeline may take, from its configured
+// timeouts, retries, and delays.
+//
+// Both are upper bounds. Best bounds the path where every first attempt
+// succeeds, each within its timeout, so retries, fallbacks, and error
+// handlers never run. Worst bounds the path where failures use every
+// retry, backoff delay, and fallback, each attempt running to its timeout.
+// Waits that do not depend on failures, such as a Pacer's full queue or a
+// Poll's maximum wait, count in both. A processor declares no running time
+// of its own: the Timeout around it gives it its bound.
+//
+// Unbounded lists the nodes whose latency nothing caps. When it is
+// non-empty, Worst covers only the bounded parts and understates the true
+// worst case.
+//
+// Expected is the typical latency on the success path, from processor
+// costs measured by Calibrate; it is zero for estimates from configuration
+// alone. Waits for queues, schedules, and polls are not included.
+type LatencyEstimate struct {
+	Unbounded []UnboundedLatency
+	Best      time.Duration
+	Worst     time.Duration
+	Expected  time.Duration
+}
+
+// Bounded reports whether the configuration caps the worst case.
+func (e LatencyEstimate) Bounded() bool {
+	return len(e.Unbounded) == 0
+}
+
+// String summarizes the estimate, as "best 5s, worst 18s", prefixed with
+// the expected latency when measured.
+func (e LatencyEstimate) String() string {
+	summary := fmt.Sprintf("best %v, worst %v", e.Best, e.Worst)
+	if !e.Bounded() {
+		summary = fmt.Sprintf("best %v, worst unbounded (nodes without a bound: %d)", e.Best, len(e.Unbounded))
+	}
+	if e.Expected > 0 {
+		summary = fmt.Sprintf("expected %v, %s", e.Expected, summary)
+	}
+	return summary
+}
+
+// EstimateLatency walks processor's schema and estimates its best and
+// worst-case latency from the configured Timeouts, Retry and Backoff
+// attempts, backoff delays, and waiting connectors (Poll, Pacer, Hedge,
+// Stagger, RateLimiter), flagging every node with unbounded latency. It is
+// a design-review tool: it reads configuration only and runs nothing.
+//
+// Bounds known only at runtime, such as a Deadline read from the data or a
+// BudgetTimeout's share of a batch, are not counted: put a Timeout inside
+// them to give the estimate a bound.
+//
+// Example:
+//
+//	estimate := pipz.EstimateLatency(checkout)
+//	fmt.Println(estimate) // best 2s, worst unbounded (nodes without a bound: 1)
+//	for _, u := range estimate.Unbounded {
+//	    fmt.Printf("%s: %s\n", u.Path, u.Reason)
+//	}
+func EstimateLatency[T any](processor Chainable[T]) LatencyEstimate {
+	return EstimateSchemaLatency(NewSchema(processor.Schema()))
+}
+
+// EstimateSchemaLatency estimates the latency of a schema, as
+// EstimateLatency does for a pipeline, for tooling that already holds a
+// Schema, as CheckTimeouts does.
+func EstimateSchemaLatency(schema Schema) LatencyEstimate {
+	return estimateLatency(schema.Root, schema.Root.Identity.Name(), nil)
+}
+
+// estimateLatency estimates node, located at path, from its children's
+// estimates. measured holds the mean latency of processors by path.
+func estimateLatency(node Node, path string, measured map[string]time.Duration) LatencyEstimate {
+	children := nodeChildren(node)
+	estimates := make([]LatencyEstimate, len(children))
+	for i, childPath := range childPaths(path, children) {
+		estimates[i] = estimateLatency(children[i], childPath, measured)
+	}
+	unbounded := func(reason string) UnboundedLatency {
+		return UnboundedLatency{Identity: node.Identity, Path: path, Type: node.Type, Reason: reason}
+	}
+
+	switch node.Flow.(type) {
+	case nil:
+		return LatencyEstimate{
+			Unbounded: []UnboundedLatency{unbounded("no timeout bounds it")},
+			Expected:  measured[path],
+		}
+	case RaceFlow:
+		estimate := spanLatency(estimates)
+		for i, e := range estimates {
+			if i == 0 || e.Best < estimate.Best {
+				estimate.Best = e.Best
+			}
+			if i == 0 || e.Expected < estimate.Expected {
+				estimate.Expected = e.Expected
+			}
+		}
+		return estimate
+	case TimeoutFlow:
+		if d, ok := metadataDuration(node, "duration"); ok && d > 0 {
+			return capLatency(estimates[0], d)
+		}
+		return estimates[0]
+	case WorkerpoolFlow:
+		estimate := spanLatency(estimates)
+		if d, ok := metadataDuration(node, "timeout"); ok && d > 0 {
+			return capLatency(estimate, d)
+		}
+		return estimate
+	case RetryFlow:
+		estimate := estimates[0]
+		estimate.Worst *= time.Duration(metadataInt(node, "max_attempts"))
+		return estimate
+	case BackoffFlow:
+		estimate := estimates[0]
+		attempts := metadataInt(node, "max_attempts")
+		estimate.Worst *= time.Duration(attempts)
+		if delay, ok := metadataDuration(node, "base_delay"); ok {
+			for i := 1; i < attempts; i++ {
+				estimate.Worst += delay
+				delay *= 2
+			}
+		}
+		return estimate
+	case PollFlow:
+		estimate := estimates[0]
+		maxWait, _ := metadataDuration(node, "max_wait")
+		if maxWait <= 0 {
+			estimate.Unbounded = append(estimate.Unbounded, unbounded("polls until the context ends"))
+			return estimate
+		}
+		estimate.Best += maxWait
+		estimate.Worst += maxWait
+		return estimate
+	case PacerFlow:
+		estimate := estimates[0]
+		interval, _ := metadataDuration(node, "interval")
+		if interval <= 0 {
+			return estimate
+		}
+		maxQueue := metadataInt(node, "max_queue")
+		if maxQueue <= 0 {
+			estimate.Unbounded = append(estimate.Unbounded, unbounded("queue is unbounded"))
+			return estimate
+		}
+		estimate.Best += time.Duration(maxQueue) * interval
+		estimate.Worst += time.Duration(maxQueue) * interval
+		return estimate
+	case HedgeFlow:
+		estimate := estimates[0]
+		if delay, ok := metadataDuration(node, "delay"); ok {
+			estimate.Worst += time.Duration(metadataInt(node, "max_hedges")) * delay
+		}
+		return estimate
+	case StaggerFlow:
+		estimate := estimates[0]
+		if spread, ok := metadataDuration(node, "spread"); ok && spread > 0 {
+			estimate.Best += spread
+			estimate.Worst += spread
+		}
+		return estimate
+	case RateLimiterFlow:
+		estimate := estimates[0]
+		if mode, _ := node.Metadata["mode"].(string); mode == string(RateLimitWait) {
+			estimate.Unbounded = append(estimate.Unbounded, unbounded("waits for rate limit tokens"))
+		}
+		return estimate
+	case ScaffoldFlow:
+		return LatencyEstimate{} // fire-and-forget: the caller never waits
+	case SequenceFlow, ComposeFlow, CollectErrorsFlow, VerifyFlow:
+		return sumLatency(estimates)
+	case FallbackFlow, HandleFlow:
+		// Later children run only after the first fails.
+		estimate := sumLatency(estimates)
+		if len(estimates) > 0 {
+			estimate.Best = estimates[0].Best
+			estimate.Expected = estimates[0].Expected
+		}
+		return estimate
+	}
+	return spanLatency(estimates)
+}
+
+// capLatency bounds an estimate by a timeout of d, which also caps every
+// unbounded node beneath it.
+func capLatency(estimate LatencyEstimate, d time.Duration) LatencyEstimate {
+	estimate.Expected = min(estimate.Expected, d)
+	if !estimate.Bounded() {
+		return LatencyEstimate{Best: d, Worst: d, Expected: estimate.Expected}
+	}
+	estimate.Best = min(estimate.Best, d)
+	estimate.Worst = min(estimate.Worst, d)
+	return estimate
+}
+
+// sumLatency combines children that run one after another.
+func sumLatency(estimates []LatencyEstimate) LatencyEstimate {
+	var sum LatencyEstimate
+	for _, e := range estimates {
+		sum.Best += e.Best
+		sum.Worst += e.Worst
+		sum.Expected += e.Expected
+		sum.Unbounded = append(sum.Unbounded, e.Unbounded...)
+	}
+	return sum
+}
+
+// spanLatency combines children that run side by side, or of which one is
+// chosen at runtime, by taking the slowest.
+func spanLatency(estimates []LatencyEstimate) LatencyEstimate {
+	var span LatencyEstimate
+	for _, e := range estimates {
+		span.Best = max(span.Best, e.Best)
+		span.Worst = max(span.Worst, e.Worst)
+		span.Expected = max(span.Expected, e.Expected)
+		span.Unbounded = append(span.Unbounded, e.Unbounded...)
+	}
+	return span
+}
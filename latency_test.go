@@ -0,0 +1,95 @@
+package pipz
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEstimateLatency(t *testing.T) {
+	call := Transform(testIdentity("call"), func(_ context.Context, v int) int { return v })
+	bounded := func(name string, d time.Duration) Chainable[int] {
+		return NewTimeout(testIdentity(name), call, d)
+	}
+
+	t.Run("Unbounded Processor", func(t *testing.T) {
+		estimate := EstimateLatency(NewSequence(testIdentity("checkout"), bounded("reserve", time.Second), call))
+		if estimate.Bounded() || len(estimate.Unbounded) != 1 {
+			t.Fatalf("expected one unbounded node, got %+v", estimate.Unbounded)
+		}
+		if u := estimate.Unbounded[0]; u.Path != "checkout/call" || u.Type != "processor" {
+			t.Errorf("unexpected unbounded node %+v", u)
+		}
+		if estimate.Best != time.Second || estimate.Worst != time.Second {
+			t.Errorf("expected the bounded part, 1s, got %v", estimate)
+		}
+	})
+
+	t.Run("Backoff Worst Case", func(t *testing.T) {
+		b := NewBackoff(testIdentity("backoff"), bounded("charge", 5*time.Second), 3, time.Second)
+		estimate := EstimateLatency(b)
+		// Best: one 5s attempt. Worst: three 5s attempts + 1s + 2s delays.
+		if !estimate.Bounded() || estimate.Best != 5*time.Second || estimate.Worst != 18*time.Second {
+			t.Errorf("expected best 5s, worst 18s, got %v", estimate)
+		}
+		if estimate.String() != "best 5s, worst 18s" {
+			t.Errorf("unexpected summary %q", estimate.String())
+		}
+	})
+
+	t.Run("Outer Timeout Caps", func(t *testing.T) {
+		retry := NewRetry(testIdentity("retry"), call, 5)
+		estimate := EstimateLatency(NewTimeout(testIdentity("outer"), retry, 3*time.Second))
+		if !estimate.Bounded() || estimate.Best != 3*time.Second || estimate.Worst != 3*time.Second {
+			t.Errorf("expected the outer timeout to cap both bounds, got %v", estimate)
+		}
+		inner := NewRetry(testIdentity("retry"), bounded("charge", time.Second), 2)
+		estimate = EstimateLatency(NewTimeout(testIdentity("outer"), inner, 3*time.Second))
+		if estimate.Best != time.Second || estimate.Worst != 2*time.Second {
+			t.Errorf("expected the nested bounds under the cap, got %v", estimate)
+		}
+	})
+
+	t.Run("Fallback And Race", func(t *testing.T) {
+		fallback := NewFallback(testIdentity("fallback"), bounded("primary", time.Second), bounded("secondary", 2*time.Second))
+		if estimate := EstimateLatency(fallback); estimate.Best != time.Second || estimate.Worst != 3*time.Second {
+			t.Errorf("expected fallback best 1s, worst 3s, got %v", estimate)
+		}
+		race := NewRace(testIdentity("race"), clonableTimeout(time.Second), clonableTimeout(4*time.Second))
+		if estimate := EstimateLatency(race); estimate.Best != time.Second || estimate.Worst != 4*time.Second {
+			t.Errorf("expected race best 1s, worst 4s, got %v", estimate)
+		}
+	})
+
+	t.Run("Waiting Connectors", func(t *testing.T) {
+		isDone := func(int) bool { return true }
+		estimate := EstimateLatency(NewSequence(testIdentity("sync"),
+			NewPoll(testIdentity("settle"), bounded("status", time.Second), isDone, time.Second, 0),
+			NewPacer(testIdentity("pace"), 100*time.Millisecond, bounded("push", time.Second)),
+			NewRateLimiter(testIdentity("limit"), 10, 1, bounded("send", time.Second)),
+		))
+		reasons := map[string]string{}
+		for _, u := range estimate.Unbounded {
+			reasons[u.Path] = u.Reason
+		}
+		if len(reasons) != 3 || reasons["sync/settle"] == "" || reasons["sync/pace"] == "" || reasons["sync/limit"] == "" {
+			t.Errorf("expected the poll, pacer, and rate limiter flagged, got %v", reasons)
+		}
+
+		bounded := EstimateLatency(NewSequence(testIdentity("sync"),
+			NewPoll(testIdentity("settle"), bounded("status", time.Second), isDone, time.Second, time.Minute),
+			NewPacer(testIdentity("pace"), 100*time.Millisecond, bounded("push", time.Second)).SetMaxQueue(10),
+		))
+		// Poll: 1m + a final 1s check. Pacer: 10 x 100ms queued + 1s.
+		if !bounded.Bounded() || bounded.Worst != 63*time.Second {
+			t.Errorf("expected worst 63s, got %v", bounded)
+		}
+	})
+
+	t.Run("Scaffold Is Not Waited For", func(t *testing.T) {
+		scaffold := NewScaffold(testIdentity("notify"), clonableTimeout(time.Minute))
+		if estimate := EstimateSchemaLatency(NewSchema(scaffold.Schema())); !estimate.Bounded() || estimate.Worst != 0 {
+			t.Errorf("expected no latency from a scaffold, got %v", estimate)
+		}
+	})
+}
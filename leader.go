@@ -0,0 +1,154 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// LeaderElector reports whether this instance currently holds leadership.
+// Implementations wrap whatever election the deployment uses, such as a
+// Kubernetes lease or a Redis lock, and should answer from cached state:
+// IsLeader is called on every Process.
+type LeaderElector interface {
+	IsLeader(ctx context.Context) (bool, error)
+}
+
+// LeaderElectorFunc adapts a function to the LeaderElector interface.
+type LeaderElectorFunc func(ctx context.Context) (bool, error)
+
+// IsLeader implements LeaderElector.
+func (f LeaderElectorFunc) IsLeader(ctx context.Context) (bool, error) {
+	return f(ctx)
+}
+
+// LeaderOnly runs its processor only on the instance holding leadership and
+// passes data through unchanged everywhere else. Scheduled pipelines in
+// replicated deployments use it so singleton work (sending a daily digest,
+// compacting a table) happens once rather than once per replica.
+//
+// If the elector fails, LeaderOnly fails rather than guessing: running
+// singleton work on several replicas is usually worse than skipping a run.
+//
+// Example:
+//
+//	var DigestID = pipz.NewIdentity("daily-digest", "Sends the digest from the leader only")
+//	digest := pipz.NewLeaderOnly(DigestID, leaseElector, sendDigest)
+type LeaderOnly[T any] struct {
+	processor Chainable[T]
+	elector   LeaderElector
+	identity  Identity
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewLeaderOnly creates a LeaderOnly gating processor on elector.
+func NewLeaderOnly[T any](identity Identity, elector LeaderElector, processor Chainable[T]) *LeaderOnly[T] {
+	return &LeaderOnly[T]{
+		identity:  identity,
+		elector:   elector,
+		processor: processor,
+	}
+}
+
+// Process implements the Chainable interface.
+func (l *LeaderOnly[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, l.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, l, l.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	l.mu.RLock()
+	processor := l.processor
+	elector := l.elector
+	l.mu.RUnlock()
+
+	leader, electErr := elector.IsLeader(ctx)
+	if electErr != nil {
+		return data, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       fmt.Errorf("leader election: %w", electErr),
+			Path:      []Identity{l.identity},
+			Timeout:   errors.Is(electErr, context.DeadlineExceeded),
+			Canceled:  errors.Is(electErr, context.Canceled),
+		}
+	}
+	if !leader {
+		capitan.Info(ctx, SignalLeaderSkipped,
+			FieldName.Field(l.identity.Name()),
+			FieldIdentityID.Field(l.identity.ID().String()),
+		)
+		return data, nil
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, l.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{l.identity},
+		}
+	}
+	return result, nil
+}
+
+// SetElector updates the leader elector.
+func (l *LeaderOnly[T]) SetElector(elector LeaderElector) *LeaderOnly[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.elector = elector
+	return l
+}
+
+// SetProcessor updates the gated processor.
+func (l *LeaderOnly[T]) SetProcessor(processor Chainable[T]) *LeaderOnly[T] {
+	noteChildren(l.identity, processor)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.processor = processor
+	return l
+}
+
+// Identity returns the identity of this connector.
+func (l *LeaderOnly[T]) Identity() Identity {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (l *LeaderOnly[T]) Schema() Node {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return Node{
+		Identity: l.identity,
+		Type:     "leaderonly",
+		Flow:     LeaderOnlyFlow{Processor: l.processor.Schema()},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (l *LeaderOnly[T]) Close() error {
+	l.closeOnce.Do(func() {
+		l.mu.RLock()
+		defer l.mu.RUnlock()
+		l.closeErr = l.processor.Close()
+	})
+	return l.closeErr
+}
@@ -0,0 +1,119 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestLeaderOnly(t *testing.T) {
+	var leader atomic.Bool
+	elector := LeaderElectorFunc(func(context.Context) (bool, error) {
+		return leader.Load(), nil
+	})
+	var runs atomic.Int32
+	double := Transform(NewIdentity("double", ""), func(_ context.Context, v int) int {
+		runs.Add(1)
+		return v * 2
+	})
+
+	t.Run("Runs On Leader", func(t *testing.T) {
+		leader.Store(true)
+		runs.Store(0)
+		l := NewLeaderOnly(NewIdentity("leader", ""), elector, double)
+		result, err := l.Process(context.Background(), 4)
+		if err != nil || result != 8 || runs.Load() != 1 {
+			t.Errorf("expected processor to run, got %d (%v), runs %d", result, err, runs.Load())
+		}
+	})
+
+	t.Run("Passes Through On Follower", func(t *testing.T) {
+		leader.Store(false)
+		runs.Store(0)
+		l := NewLeaderOnly(NewIdentity("leader", ""), elector, double)
+		result, err := l.Process(context.Background(), 4)
+		if err != nil || result != 4 || runs.Load() != 0 {
+			t.Errorf("expected pass-through, got %d (%v), runs %d", result, err, runs.Load())
+		}
+	})
+
+	t.Run("Elector Error Fails", func(t *testing.T) {
+		errLease := errors.New("lease store unreachable")
+		runs.Store(0)
+		l := NewLeaderOnly(NewIdentity("leader", ""), LeaderElectorFunc(func(context.Context) (bool, error) {
+			return false, errLease
+		}), double)
+		_, err := l.Process(context.Background(), 4)
+
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !errors.Is(err, errLease) {
+			t.Fatalf("expected wrapped elector error, got %v", err)
+		}
+		if pipeErr.Path[0].Name() != "leader" || runs.Load() != 0 {
+			t.Errorf("unexpected path %v or runs %d", pipeErr.Path, runs.Load())
+		}
+	})
+
+	t.Run("Processor Error Path", func(t *testing.T) {
+		leader.Store(true)
+		failing := Apply(NewIdentity("failing", ""), func(_ context.Context, v int) (int, error) {
+			return v, errors.New("boom")
+		})
+		_, err := NewLeaderOnly(NewIdentity("leader", ""), elector, failing).Process(context.Background(), 1)
+
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected pipz error, got %v", err)
+		}
+		if len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "leader" || pipeErr.Path[1].Name() != "failing" {
+			t.Errorf("unexpected path: %v", pipeErr.Path)
+		}
+	})
+
+	t.Run("Emits Skipped Signal", func(t *testing.T) {
+		leader.Store(false)
+		var name string
+		listener := capitan.Hook(SignalLeaderSkipped, func(_ context.Context, e *capitan.Event) {
+			name, _ = FieldName.From(e)
+		})
+		defer listener.Close()
+
+		_, _ = NewLeaderOnly(NewIdentity("digest", ""), elector, double).Process(context.Background(), 1) //nolint:errcheck // signal checked below
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if name != "digest" {
+			t.Errorf("expected name 'digest', got %q", name)
+		}
+	})
+
+	t.Run("Setters And Schema", func(t *testing.T) {
+		leader.Store(false)
+		l := NewLeaderOnly(NewIdentity("leader", ""), elector, double).
+			SetElector(LeaderElectorFunc(func(context.Context) (bool, error) { return true, nil })).
+			SetProcessor(Transform(NewIdentity("inc", ""), func(_ context.Context, v int) int { return v + 1 }))
+		if result, err := l.Process(context.Background(), 1); err != nil || result != 2 {
+			t.Errorf("expected setters applied, got %d (%v)", result, err)
+		}
+
+		node := l.Schema()
+		flow, ok := LeaderOnlyKey.From(node)
+		if !ok || node.Type != "leaderonly" || flow.Processor.Identity.Name() != "inc" {
+			t.Errorf("unexpected schema: %+v", node)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		l := NewLeaderOnly(NewIdentity("leader", ""), elector, double)
+		if err := l.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+		if err := l.Close(); err != nil {
+			t.Errorf("expected idempotent close, got %v", err)
+		}
+	})
+}
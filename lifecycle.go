@@ -0,0 +1,105 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Starter is implemented by processors that wrap background resources,
+// such as connection pools or message consumers, which must be started
+// before they can serve items. Sequence, Pipeline, and Supervisor forward
+// Start to the children that implement it, so a whole pipeline is started,
+// health-checked, and closed as a unit.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// StarterFunc adapts a function to the Starter interface.
+type StarterFunc func(ctx context.Context) error
+
+// Start implements Starter.
+func (f StarterFunc) Start(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthChecker is implemented by processors that can report whether
+// their background resources are healthy. Health returns nil when healthy.
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
+
+// HealthCheckerFunc adapts a function to the HealthChecker interface.
+type HealthCheckerFunc func(ctx context.Context) error
+
+// Health implements HealthChecker.
+func (f HealthCheckerFunc) Health(ctx context.Context) error {
+	return f(ctx)
+}
+
+// StartAll starts starters in order, stopping at the first failure, since
+// later resources commonly depend on earlier ones. Nil starters are
+// skipped. Starters already started are left running; close them to
+// release their resources.
+//
+// Example:
+//
+//	if err := pipz.StartAll(ctx, dbPool, pipeline); err != nil {
+//	    log.Fatalf("start-up failed: %v", err)
+//	}
+//	defer pipeline.Close()
+func StartAll(ctx context.Context, starters ...Starter) error {
+	for _, s := range starters {
+		if s == nil {
+			continue
+		}
+		if err := recoverCall(ctx, func() error { return s.Start(ctx) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckHealth checks every checker and returns their joined errors. Nil
+// checkers are skipped.
+func CheckHealth(ctx context.Context, checkers ...HealthChecker) error {
+	var errs []error
+	for _, c := range checkers {
+		if c == nil {
+			continue
+		}
+		errs = append(errs, recoverCall(ctx, func() error { return c.Health(ctx) }))
+	}
+	return errors.Join(errs...)
+}
+
+// startChildren starts each child implementing Starter in order, naming the
+// failing child in the returned error.
+func startChildren[T any](ctx context.Context, owner Identity, children []Chainable[T]) error {
+	for _, child := range children {
+		starter, ok := child.(Starter)
+		if !ok {
+			continue
+		}
+		if err := StartAll(ctx, starter); err != nil {
+			return fmt.Errorf("%s: starting %s: %w", owner.Name(), child.Identity().Name(), err)
+		}
+	}
+	return nil
+}
+
+// checkChildren checks each child implementing HealthChecker, naming every
+// unhealthy child in the returned error.
+func checkChildren[T any](ctx context.Context, owner Identity, children []Chainable[T]) error {
+	var errs []error
+	for _, child := range children {
+		checker, ok := child.(HealthChecker)
+		if !ok {
+			continue
+		}
+		if err := CheckHealth(ctx, checker); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s unhealthy: %w", owner.Name(), child.Identity().Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
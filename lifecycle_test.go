@@ -0,0 +1,155 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// lifecycleProcessor is a processor with a background resource.
+type lifecycleProcessor struct {
+	Processor[int]
+	startErr  error
+	healthErr error
+	started   *[]string
+}
+
+func (p lifecycleProcessor) Start(_ context.Context) error {
+	*p.started = append(*p.started, p.Identity().Name())
+	return p.startErr
+}
+
+func (p lifecycleProcessor) Health(_ context.Context) error {
+	return p.healthErr
+}
+
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	resource := func(name string, started *[]string) lifecycleProcessor {
+		return lifecycleProcessor{
+			Processor: Transform(NewIdentity(name, ""), func(_ context.Context, n int) int { return n }),
+			started:   started,
+		}
+	}
+
+	t.Run("Sequence Starts Children In Order", func(t *testing.T) {
+		var started []string
+		inner := NewSequence(NewIdentity("inner", ""), resource("consumer", &started))
+		seq := NewSequence(NewIdentity("flow", ""),
+			resource("pool", &started),
+			Transform(NewIdentity("plain", ""), func(_ context.Context, n int) int { return n }),
+			inner,
+		)
+		pipeline := NewPipeline(NewIdentity("app", ""), seq)
+		if err := StartAll(ctx, pipeline); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Join(started, ",") != "pool,consumer" {
+			t.Errorf("started = %v", started)
+		}
+	})
+
+	t.Run("Start Stops At First Failure", func(t *testing.T) {
+		var started []string
+		failing := resource("pool", &started)
+		failing.startErr = errors.New("connection refused")
+		seq := NewSequence(NewIdentity("flow", ""), failing, resource("consumer", &started))
+
+		err := seq.Start(ctx)
+		if err == nil || !strings.Contains(err.Error(), "flow: starting pool") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(started) != 1 {
+			t.Errorf("started after failure: %v", started)
+		}
+	})
+
+	t.Run("Health Joins Unhealthy Children", func(t *testing.T) {
+		var started []string
+		sick := resource("pool", &started)
+		sick.healthErr = errors.New("pool exhausted")
+		seq := NewSequence(NewIdentity("flow", ""), sick, resource("consumer", &started))
+		pipeline := NewPipeline(NewIdentity("app", ""), seq)
+
+		err := CheckHealth(ctx, pipeline)
+		if err == nil || !strings.Contains(err.Error(), "pool unhealthy: pool exhausted") {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := seq.Remove(sick.Identity()); err != nil {
+			t.Fatal(err)
+		}
+		if err := pipeline.Health(ctx); err != nil {
+			t.Errorf("expected healthy, got %v", err)
+		}
+	})
+
+	t.Run("Panicking Starter", func(t *testing.T) {
+		err := StartAll(ctx, StarterFunc(func(context.Context) error { panic("boom") }))
+		if !errors.Is(err, ErrPanic) {
+			t.Errorf("expected panic error, got %v", err)
+		}
+	})
+
+	t.Run("Supervisor Starts Workers Before Running", func(t *testing.T) {
+		var events []string
+		worker := struct {
+			RunnerFunc
+			StarterFunc
+		}{
+			RunnerFunc: func(context.Context) error {
+				events = append(events, "run")
+				return nil
+			},
+			StarterFunc: func(context.Context) error {
+				events = append(events, "start")
+				return nil
+			},
+		}
+		sup := NewSupervisor(NewIdentity("sup", "")).Add(NewIdentity("worker", ""), worker)
+		if err := sup.Run(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Join(events, ",") != "start,run" {
+			t.Errorf("events = %v", events)
+		}
+	})
+
+	t.Run("Supervisor Start Failure Runs Nothing", func(t *testing.T) {
+		ran := false
+		worker := struct {
+			RunnerFunc
+			StarterFunc
+		}{
+			RunnerFunc: func(context.Context) error {
+				ran = true
+				return nil
+			},
+			StarterFunc: func(context.Context) error { return errors.New("no broker") },
+		}
+		sup := NewSupervisor(NewIdentity("sup", "")).Add(NewIdentity("worker", ""), worker)
+		if err := sup.Run(ctx); err == nil || !strings.Contains(err.Error(), "starting worker worker") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ran {
+			t.Error("worker ran after start failure")
+		}
+		if err := sup.Health(ctx); err == nil || !strings.Contains(err.Error(), "no broker") {
+			t.Errorf("expected failed worker in health, got %v", err)
+		}
+	})
+
+	t.Run("Supervisor Health Checks Workers", func(t *testing.T) {
+		worker := struct {
+			RunnerFunc
+			HealthCheckerFunc
+		}{
+			RunnerFunc:        func(context.Context) error { return nil },
+			HealthCheckerFunc: func(context.Context) error { return errors.New("lagging") },
+		}
+		sup := NewSupervisor(NewIdentity("sup", "")).Add(NewIdentity("consumer", ""), worker)
+		if err := sup.Health(ctx); err == nil || !strings.Contains(err.Error(), "consumer unhealthy: lagging") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,250 @@
+package pipz
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// Limiter caps the Pipeline executions running at once across a whole
+// process, protecting resources every pipeline shares, such as CPU, memory,
+// and the garbage collector, in services hosting many pipelines. Install
+// one with SetProcessLimiter and every Pipeline.Process waits for a slot
+// before it runs; nested pipelines run in their caller's slot.
+//
+// While executions are waiting, freed slots are shared fairly between
+// pipelines by weight: each goes to the waiting pipeline with the fewest
+// executions in flight per unit of weight, so a pipeline with weight 2 runs
+// twice as many executions as one with weight 1 under contention, and a
+// burst in one pipeline cannot starve the others. Without contention any
+// pipeline may use every slot.
+//
+// Example:
+//
+//	limiter := pipz.NewLimiter(64).
+//	    SetWeight(CheckoutPipelineID, 4). // Interactive traffic first
+//	    SetWeight(ReportsPipelineID, 1)
+//	pipz.SetProcessLimiter(limiter)
+type Limiter struct {
+	weights  map[uuid.UUID]int
+	shares   map[uuid.UUID]*limiterShare
+	capacity int
+	inFlight int
+	waiting  int
+	arrivals uint64
+	mu       sync.Mutex
+}
+
+// limiterShare is one pipeline's executions in a Limiter.
+type limiterShare struct {
+	waiters  []limiterWaiter
+	inFlight int
+}
+
+// limiterWaiter is one execution waiting for a slot.
+type limiterWaiter struct {
+	ready   chan struct{}
+	arrival uint64
+}
+
+// processLimiter is the installed Limiter; nil leaves executions unlimited.
+var processLimiter atomic.Pointer[Limiter]
+
+// limiterSlotKey is the context key marking an execution holding a slot.
+type limiterSlotKey struct{}
+
+// SetProcessLimiter installs a process-wide Limiter consulted by every
+// Pipeline before it executes, or removes it when limiter is nil.
+// Executions already running or waiting keep the limiter they started
+// with. With no limiter installed the check costs one atomic load.
+func SetProcessLimiter(limiter *Limiter) {
+	processLimiter.Store(limiter)
+}
+
+// NewLimiter creates a Limiter allowing capacity executions at once,
+// every pipeline with weight 1. A capacity below one is treated as one.
+func NewLimiter(capacity int) *Limiter {
+	return &Limiter{
+		capacity: max(capacity, 1),
+		weights:  make(map[uuid.UUID]int),
+		shares:   make(map[uuid.UUID]*limiterShare),
+	}
+}
+
+// SetWeight sets the pipeline's share of contended slots relative to other
+// pipelines. Weights below one are treated as one.
+func (l *Limiter) SetWeight(pipeline Identity, weight int) *Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.weights[pipeline.ID()] = max(weight, 1)
+	return l
+}
+
+// SetCapacity updates how many executions may run at once. Executions
+// already running above a lowered capacity finish normally.
+func (l *Limiter) SetCapacity(capacity int) *Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.capacity = max(capacity, 1)
+	l.grantLocked()
+	return l
+}
+
+// InFlight returns the number of executions holding a slot.
+func (l *Limiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
+
+// Waiting returns the number of executions waiting for a slot.
+func (l *Limiter) Waiting() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.waiting
+}
+
+// InFlightFor returns the number of the pipeline's executions holding a
+// slot.
+func (l *Limiter) InFlightFor(pipeline Identity) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if share, ok := l.shares[pipeline.ID()]; ok {
+		return share.inFlight
+	}
+	return 0
+}
+
+// Acquire waits for a slot for one execution of pipeline and returns the
+// function releasing it. Pipelines acquire slots themselves; Acquire is
+// for work outside a Pipeline that should count against the same cap.
+// It returns the context's error if ctx ends first.
+func (l *Limiter) Acquire(ctx context.Context, pipeline Identity) (release func(), err error) {
+	id := pipeline.ID()
+	var once sync.Once
+	release = func() { once.Do(func() { l.release(id) }) }
+
+	l.mu.Lock()
+	share := l.shareLocked(id)
+	if l.waiting == 0 && l.inFlight < l.capacity {
+		l.inFlight++
+		share.inFlight++
+		l.mu.Unlock()
+		return release, nil
+	}
+	l.arrivals++
+	ready := make(chan struct{})
+	share.waiters = append(share.waiters, limiterWaiter{ready: ready, arrival: l.arrivals})
+	l.waiting++
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		return release, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if i := slices.IndexFunc(share.waiters, func(w limiterWaiter) bool { return w.ready == ready }); i >= 0 {
+			share.waiters = slices.Delete(share.waiters, i, i+1)
+			l.waiting--
+			l.forgetLocked(id, share)
+		} else {
+			// Granted while giving up; pass the slot on
+			l.releaseLocked(id, share)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// release frees a slot held by pipeline id.
+func (l *Limiter) release(id uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.releaseLocked(id, l.shares[id])
+}
+
+// releaseLocked frees a slot held by share and grants freed slots. The
+// caller holds mu.
+func (l *Limiter) releaseLocked(id uuid.UUID, share *limiterShare) {
+	l.inFlight--
+	share.inFlight--
+	l.forgetLocked(id, share)
+	l.grantLocked()
+}
+
+// grantLocked hands free slots to waiters, each to the pipeline with the
+// fewest executions in flight per unit of weight, earliest arrival first
+// among equals. The caller holds mu.
+func (l *Limiter) grantLocked() {
+	for l.waiting > 0 && l.inFlight < l.capacity {
+		var next *limiterShare
+		var nextWeight int
+		for id, share := range l.shares {
+			if len(share.waiters) == 0 {
+				continue
+			}
+			weight := l.weightLocked(id)
+			if next == nil {
+				next, nextWeight = share, weight
+				continue
+			}
+			// Compare inFlight/weight without division
+			lhs, rhs := share.inFlight*nextWeight, next.inFlight*weight
+			if lhs < rhs || lhs == rhs && share.waiters[0].arrival < next.waiters[0].arrival {
+				next, nextWeight = share, weight
+			}
+		}
+		w := next.waiters[0]
+		next.waiters = next.waiters[1:]
+		l.waiting--
+		l.inFlight++
+		next.inFlight++
+		close(w.ready)
+	}
+}
+
+// shareLocked returns the share of pipeline id, creating it if needed.
+// The caller holds mu.
+func (l *Limiter) shareLocked(id uuid.UUID) *limiterShare {
+	share, ok := l.shares[id]
+	if !ok {
+		share = &limiterShare{}
+		l.shares[id] = share
+	}
+	return share
+}
+
+// forgetLocked drops share once it has nothing in flight or waiting, so
+// pipelines that stop running hold no state. The caller holds mu.
+func (l *Limiter) forgetLocked(id uuid.UUID, share *limiterShare) {
+	if share.inFlight == 0 && len(share.waiters) == 0 {
+		delete(l.shares, id)
+	}
+}
+
+// weightLocked returns the weight of pipeline id. The caller holds mu.
+func (l *Limiter) weightLocked(id uuid.UUID) int {
+	if weight, ok := l.weights[id]; ok {
+		return weight
+	}
+	return 1
+}
+
+// acquireSlot takes a slot from the process limiter for an execution of
+// pipeline, returning the context marked as holding it and the function
+// releasing it. Executions already holding a slot, and all executions
+// when no limiter is installed, return immediately.
+func acquireSlot(ctx context.Context, pipeline Identity) (context.Context, func(), error) {
+	limiter := processLimiter.Load()
+	if limiter == nil || ctx.Value(limiterSlotKey{}) != nil {
+		return ctx, func() {}, nil
+	}
+	release, err := limiter.Acquire(ctx, pipeline)
+	if err != nil {
+		return ctx, nil, err
+	}
+	return context.WithValue(ctx, limiterSlotKey{}, limiter), release, nil
+}
@@ -0,0 +1,156 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	// waitFor polls cond until it holds.
+	waitFor := func(t *testing.T, what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	t.Run("Caps In Flight", func(t *testing.T) {
+		l := NewLimiter(2)
+		a := testIdentity("a")
+		r1, _ := l.Acquire(ctx, a)
+		r2, _ := l.Acquire(ctx, a)
+
+		acquired := make(chan struct{})
+		go func() {
+			release, err := l.Acquire(ctx, a)
+			if err == nil {
+				defer release()
+			}
+			close(acquired)
+		}()
+		waitFor(t, "waiter", func() bool { return l.Waiting() == 1 })
+		if l.InFlight() != 2 {
+			t.Errorf("expected 2 in flight, got %d", l.InFlight())
+		}
+		r1()
+		r1() // Releasing twice frees one slot
+		<-acquired
+		r2()
+		if l.InFlight() != 0 || l.Waiting() != 0 {
+			t.Errorf("expected limiter to drain, got %d in flight and %d waiting", l.InFlight(), l.Waiting())
+		}
+	})
+
+	t.Run("Weighted Fair Share", func(t *testing.T) {
+		heavy, light := testIdentity("heavy"), testIdentity("light")
+		l := NewLimiter(3).SetWeight(heavy, 2)
+
+		// The light pipeline fills every slot before the others queue.
+		var held []func()
+		for range 3 {
+			r, _ := l.Acquire(ctx, light)
+			held = append(held, r)
+		}
+		grants := make(chan string, 12)
+		queue := func(id Identity, n int) {
+			for range n {
+				go func() {
+					release, err := l.Acquire(ctx, id)
+					if err != nil {
+						return
+					}
+					grants <- id.Name()
+					_ = release
+				}()
+			}
+		}
+		queue(light, 6)
+		waitFor(t, "light waiters", func() bool { return l.Waiting() == 6 })
+		queue(heavy, 6)
+		waitFor(t, "heavy waiters", func() bool { return l.Waiting() == 12 })
+
+		// Free the light pipeline's slots one at a time: each goes to the
+		// pipeline furthest below its share, so heavy takes the first two.
+		for i, r := range held {
+			r()
+			want := "heavy"
+			if i == 2 {
+				want = "light"
+			}
+			if name := <-grants; name != want {
+				t.Errorf("expected freed slot %d to go to %s, got %s", i, want, name)
+			}
+		}
+		if l.InFlightFor(heavy) != 2 || l.InFlightFor(light) != 1 {
+			t.Errorf("unexpected shares: heavy %d, light %d", l.InFlightFor(heavy), l.InFlightFor(light))
+		}
+
+		// With more capacity, heavy settles at twice light's slots.
+		l.SetCapacity(9)
+		for range 6 {
+			<-grants
+		}
+		if l.InFlightFor(heavy) != 6 || l.InFlightFor(light) != 3 {
+			t.Errorf("expected 6:3 split, got heavy %d, light %d", l.InFlightFor(heavy), l.InFlightFor(light))
+		}
+	})
+
+	t.Run("Canceled Waiter", func(t *testing.T) {
+		l := NewLimiter(1)
+		a := testIdentity("a")
+		release, _ := l.Acquire(ctx, a)
+		defer release()
+
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		if _, err := l.Acquire(waitCtx, a); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+		if l.Waiting() != 0 {
+			t.Errorf("expected canceled waiter removed, got %d waiting", l.Waiting())
+		}
+	})
+
+	t.Run("Limits Pipelines", func(t *testing.T) {
+		l := NewLimiter(1)
+		SetProcessLimiter(l)
+		defer SetProcessLimiter(nil)
+
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		block := Effect(testIdentity("block"), func(context.Context, int) error {
+			entered <- struct{}{}
+			<-release
+			return nil
+		})
+		slow := NewPipeline(testIdentity("slow"), block)
+		inner := NewPipeline(testIdentity("inner"), Transform(testIdentity("inc"), func(_ context.Context, n int) int { return n + 1 }))
+		outer := NewPipeline(testIdentity("outer"), Chainable[int](inner))
+
+		go slow.Process(ctx, 0) //nolint:errcheck
+		<-entered
+
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := outer.Process(waitCtx, 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.Timeout || pipeErr.Path[0].Name() != "outer" {
+			t.Fatalf("expected outer to time out waiting for a slot, got %v", err)
+		}
+
+		close(release)
+		waitFor(t, "slot release", func() bool { return l.InFlight() == 0 })
+		// The nested pipeline runs in its caller's slot.
+		if result, err := outer.Process(ctx, 1); err != nil || result != 2 {
+			t.Errorf("expected nested pipelines to share one slot, got %v, %v", result, err)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package pipz
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LineageEntry records one processor setting one field: which value it
+// produced and which fields it derived the value from.
+type LineageEntry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Value       any       `json:"value,omitempty"`
+	Field       string    `json:"field"`
+	Processor   string    `json:"processor"`
+	ProcessorID string    `json:"processor_id"`
+	Inputs      []string  `json:"inputs,omitempty"`
+}
+
+// Lineage accumulates the entries recorded while processing one item, to
+// explain afterwards how a value such as a fraud score or a price was
+// produced. Create one per Process call with WithLineage; processors add
+// to it with RecordLineage. It is safe for concurrent use, so processors
+// running under Concurrent or Race can record into the same Lineage.
+type Lineage struct {
+	entries []LineageEntry
+	mu      sync.Mutex
+}
+
+// lineageKey is the context key for the active Lineage.
+type lineageKey struct{}
+
+// WithLineage returns a context that collects lineage entries and the
+// Lineage they are collected into, readable once Process returns.
+//
+// Example:
+//
+//	ctx, lineage := pipz.WithLineage(ctx)
+//	decision, err := fraudPipeline.Process(ctx, txn)
+//	for _, entry := range lineage.Explain("fraud_score") {
+//	    log.Printf("%s set %s=%v from %v", entry.Processor, entry.Field, entry.Value, entry.Inputs)
+//	}
+func WithLineage(ctx context.Context) (context.Context, *Lineage) {
+	lineage := &Lineage{}
+	return context.WithValue(ctx, lineageKey{}, lineage), lineage
+}
+
+// LineageFromContext returns the Lineage collecting entries for ctx.
+func LineageFromContext(ctx context.Context) (*Lineage, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	lineage, ok := ctx.Value(lineageKey{}).(*Lineage)
+	return lineage, ok
+}
+
+// RecordLineage records that source set field to value, derived from the
+// named input fields. Without a Lineage in ctx it records nothing and
+// returns false, so processors can record unconditionally at negligible
+// cost.
+//
+// Example:
+//
+//	score := pipz.Apply(ScoreID, func(ctx context.Context, t Txn) (Txn, error) {
+//	    t.FraudScore = model.Score(t.Amount, t.Velocity)
+//	    pipz.RecordLineage(ctx, ScoreID, "fraud_score", t.FraudScore, "amount", "velocity")
+//	    return t, nil
+//	})
+func RecordLineage(ctx context.Context, source Identity, field string, value any, inputs ...string) bool {
+	lineage, ok := LineageFromContext(ctx)
+	if !ok {
+		return false
+	}
+	lineage.mu.Lock()
+	defer lineage.mu.Unlock()
+	lineage.entries = append(lineage.entries, LineageEntry{
+		Timestamp:   time.Now(),
+		Value:       value,
+		Field:       field,
+		Processor:   source.Name(),
+		ProcessorID: source.ID().String(),
+		Inputs:      inputs,
+	})
+	return true
+}
+
+// Entries returns every recorded entry in recording order.
+func (l *Lineage) Entries() []LineageEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entries := make([]LineageEntry, len(l.entries))
+	copy(entries, l.entries)
+	return entries
+}
+
+// Field returns the entries that set field, in recording order, tracing
+// each change to the value.
+func (l *Lineage) Field(field string) []LineageEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var entries []LineageEntry
+	for _, entry := range l.entries {
+		if entry.Field == field {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Explain returns the entries that produced field's final value: its last
+// entry, then the last entry before it of each field it was derived from,
+// and so on transitively, in recording order. Fields with no entry are
+// treated as original inputs.
+func (l *Lineage) Explain(field string) []LineageEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	included := make(map[int]bool)
+	var visit func(field string, before int)
+	visit = func(field string, before int) {
+		for i := before - 1; i >= 0; i-- {
+			if l.entries[i].Field != field {
+				continue
+			}
+			if !included[i] {
+				included[i] = true
+				for _, input := range l.entries[i].Inputs {
+					visit(input, i)
+				}
+			}
+			return
+		}
+	}
+	visit(field, len(l.entries))
+
+	var entries []LineageEntry
+	for i, entry := range l.entries {
+		if included[i] {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
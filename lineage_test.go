@@ -0,0 +1,90 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLineage(t *testing.T) {
+	type txn struct {
+		Amount     float64
+		Velocity   int
+		FraudScore float64
+		Price      float64
+	}
+	var (
+		velocityID = NewIdentity("velocity", "")
+		scoreID    = NewIdentity("score", "")
+		adjustID   = NewIdentity("adjust", "")
+		priceID    = NewIdentity("price", "")
+	)
+	pipeline := NewPipeline(NewIdentity("fraud", ""), NewSequence(NewIdentity("steps", ""),
+		Transform(velocityID, func(ctx context.Context, t txn) txn {
+			t.Velocity = 3
+			RecordLineage(ctx, velocityID, "velocity", t.Velocity)
+			return t
+		}),
+		Transform(priceID, func(ctx context.Context, t txn) txn {
+			t.Price = t.Amount * 1.1
+			RecordLineage(ctx, priceID, "price", t.Price, "amount")
+			return t
+		}),
+		Transform(scoreID, func(ctx context.Context, t txn) txn {
+			t.FraudScore = t.Amount / 100 * float64(t.Velocity)
+			RecordLineage(ctx, scoreID, "fraud_score", t.FraudScore, "amount", "velocity")
+			return t
+		}),
+		Transform(adjustID, func(ctx context.Context, t txn) txn {
+			t.FraudScore /= 2
+			RecordLineage(ctx, adjustID, "fraud_score", t.FraudScore, "fraud_score")
+			return t
+		}),
+	))
+
+	t.Run("Explain Traces Derivation", func(t *testing.T) {
+		ctx, lineage := WithLineage(context.Background())
+		if _, err := pipeline.Process(ctx, txn{Amount: 50}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := len(lineage.Entries()); got != 4 {
+			t.Fatalf("entries = %d, want 4", got)
+		}
+
+		var sources []string
+		for _, entry := range lineage.Explain("fraud_score") {
+			sources = append(sources, entry.Processor)
+		}
+		if strings.Join(sources, ",") != "velocity,score,adjust" {
+			t.Errorf("explain sources = %v", sources)
+		}
+		if trail := lineage.Field("fraud_score"); len(trail) != 2 || trail[1].Value != 0.75 {
+			t.Errorf("fraud_score trail = %+v", trail)
+		}
+		if got := lineage.Explain("amount"); len(got) != 0 {
+			t.Errorf("original input should have no entries, got %+v", got)
+		}
+	})
+
+	t.Run("No Lineage Records Nothing", func(t *testing.T) {
+		if RecordLineage(context.Background(), scoreID, "fraud_score", 1) {
+			t.Error("expected false without a lineage")
+		}
+		if _, err := pipeline.Process(context.Background(), txn{Amount: 50}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Entries Encode As JSON", func(t *testing.T) {
+		ctx, lineage := WithLineage(context.Background())
+		RecordLineage(ctx, scoreID, "fraud_score", 0.5, "amount")
+		data, err := json.Marshal(lineage.Entries())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"processor":"score"`) || !strings.Contains(string(data), `"inputs":["amount"]`) {
+			t.Errorf("unexpected encoding: %s", data)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package loadtest
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// subBucketBits controls histogram precision. Each power-of-two range is
+// split into 2^(subBucketBits-1) linear sub-buckets, bounding the relative
+// error of any recorded value to roughly 1.5%.
+const (
+	subBucketBits  = 7
+	subBucketCount = 1 << subBucketBits
+	subBucketHalf  = subBucketCount / 2
+)
+
+// Histogram is an HDR-style latency histogram using log-linear buckets.
+// Values are recorded in nanoseconds with bounded relative error, so memory
+// use stays constant regardless of how many samples are recorded while
+// percentiles remain accurate across microsecond to minute latencies.
+//
+// Histogram is safe for concurrent use.
+type Histogram struct {
+	counts []uint64
+	total  uint64
+	sum    float64
+	min    uint64
+	max    uint64
+	mu     sync.Mutex
+}
+
+// NewHistogram creates an empty Histogram.
+func NewHistogram() *Histogram {
+	return &Histogram{
+		counts: make([]uint64, subBucketCount+(64-subBucketBits)*subBucketHalf),
+		min:    math.MaxUint64,
+	}
+}
+
+// bucketIndex maps a value to its log-linear bucket.
+func bucketIndex(v uint64) int {
+	if v < subBucketCount {
+		return int(v)
+	}
+	shift := bits.Len64(v) - subBucketBits
+	sub := v >> uint(shift)
+	return subBucketCount + (shift-1)*subBucketHalf + int(sub-subBucketHalf)
+}
+
+// bucketValue returns the representative (upper bound) value of a bucket.
+func bucketValue(idx int) uint64 {
+	if idx < subBucketCount {
+		return uint64(idx)
+	}
+	idx -= subBucketCount
+	shift := idx/subBucketHalf + 1
+	sub := uint64(idx%subBucketHalf + subBucketHalf)
+	return ((sub + 1) << uint(shift)) - 1
+}
+
+// Record adds a latency sample to the histogram.
+// Negative durations are recorded as zero.
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	v := uint64(d)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[bucketIndex(v)]++
+	h.total++
+	h.sum += float64(v)
+	if v < h.min {
+		h.min = v
+	}
+	if v > h.max {
+		h.max = v
+	}
+}
+
+// Count returns the number of recorded samples.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.total
+}
+
+// Min returns the smallest recorded latency, or zero if empty.
+func (h *Histogram) Min() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.total == 0 {
+		return 0
+	}
+	return time.Duration(h.min)
+}
+
+// Max returns the largest recorded latency, or zero if empty.
+func (h *Histogram) Max() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return time.Duration(h.max)
+}
+
+// Mean returns the arithmetic mean latency, or zero if empty.
+func (h *Histogram) Mean() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.total == 0 {
+		return 0
+	}
+	return time.Duration(h.sum / float64(h.total))
+}
+
+// Quantile returns the latency at quantile q (0 <= q <= 1).
+// For example, Quantile(0.99) returns the p99 latency. The result is
+// clamped to the observed min and max, so exact extremes are preserved.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.total == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return time.Duration(h.min)
+	}
+	if q >= 1 {
+		return time.Duration(h.max)
+	}
+
+	target := uint64(math.Ceil(q * float64(h.total)))
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= target {
+			v := bucketValue(i)
+			if v > h.max {
+				v = h.max
+			}
+			if v < h.min {
+				v = h.min
+			}
+			return time.Duration(v)
+		}
+	}
+	return time.Duration(h.max)
+}
+
+// Percentiles returns the common reporting percentiles keyed by label
+// (p50, p90, p99, p999).
+func (h *Histogram) Percentiles() map[string]time.Duration {
+	return map[string]time.Duration{
+		"p50":  h.Quantile(0.50),
+		"p90":  h.Quantile(0.90),
+		"p99":  h.Quantile(0.99),
+		"p999": h.Quantile(0.999),
+	}
+}
@@ -0,0 +1,100 @@
+package loadtest
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHistogram_Empty(t *testing.T) {
+	h := NewHistogram()
+	if h.Count() != 0 {
+		t.Errorf("expected 0 samples, got %d", h.Count())
+	}
+	if h.Quantile(0.5) != 0 || h.Min() != 0 || h.Max() != 0 || h.Mean() != 0 {
+		t.Error("expected zero statistics for empty histogram")
+	}
+}
+
+func TestHistogram_Quantiles(t *testing.T) {
+	h := NewHistogram()
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	if h.Count() != 1000 {
+		t.Fatalf("expected 1000 samples, got %d", h.Count())
+	}
+	if h.Min() != time.Millisecond {
+		t.Errorf("expected min 1ms, got %v", h.Min())
+	}
+	if h.Max() != time.Second {
+		t.Errorf("expected max 1s, got %v", h.Max())
+	}
+
+	tests := []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.50, 500 * time.Millisecond},
+		{0.90, 900 * time.Millisecond},
+		{0.99, 990 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		got := h.Quantile(tt.q)
+		diff := float64(got-tt.want) / float64(tt.want)
+		if diff < 0 || diff > 0.02 {
+			t.Errorf("quantile %v: expected ~%v, got %v", tt.q, tt.want, got)
+		}
+	}
+
+	if h.Quantile(0) != h.Min() || h.Quantile(1) != h.Max() {
+		t.Error("expected extreme quantiles to equal min and max")
+	}
+
+	mean := h.Mean()
+	if mean < 500*time.Millisecond || mean > 501*time.Millisecond {
+		t.Errorf("expected mean ~500.5ms, got %v", mean)
+	}
+}
+
+func TestHistogram_BucketRoundTrip(t *testing.T) {
+	values := []uint64{0, 1, 127, 128, 129, 1000, 123456, 1 << 40, 1<<63 + 12345}
+	for _, v := range values {
+		idx := bucketIndex(v)
+		upper := bucketValue(idx)
+		if upper < v {
+			t.Errorf("value %d: bucket upper bound %d below value", v, upper)
+		}
+		if v >= subBucketCount && float64(upper-v)/float64(v) > 0.02 {
+			t.Errorf("value %d: relative error too large (upper %d)", v, upper)
+		}
+	}
+}
+
+func TestHistogram_NegativeAndConcurrent(t *testing.T) {
+	h := NewHistogram()
+	h.Record(-time.Second)
+	if h.Max() != 0 {
+		t.Errorf("expected negative sample recorded as zero, got %v", h.Max())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				h.Record(time.Microsecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if h.Count() != 1001 {
+		t.Errorf("expected 1001 samples, got %d", h.Count())
+	}
+	if len(h.Percentiles()) != 4 {
+		t.Error("expected four reporting percentiles")
+	}
+}
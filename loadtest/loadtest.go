@@ -0,0 +1,263 @@
+// Package loadtest drives a pipz Chainable at a target request rate and
+// reports latency distributions and error breakdowns for capacity planning.
+//
+// The runner uses open-loop arrival: requests are started on a fixed
+// schedule derived from the target rate regardless of whether earlier
+// requests have completed. This mirrors real traffic, where clients do not
+// wait for the service to catch up, and avoids the coordinated-omission
+// problem of closed-loop benchmarks that hide queueing delay.
+//
+// Replay sends a recorded trace of timestamped inputs instead, optionally
+// time-compressed, and reports how breakers, rate limiters, and timeouts
+// behaved along the way.
+//
+// Example:
+//
+//	report, err := loadtest.Run(ctx, apiClient, func(i int) Request {
+//	    return Request{ID: fmt.Sprintf("req-%d", i)}
+//	}, loadtest.Config{
+//	    RPS:      200,
+//	    Warmup:   5 * time.Second,
+//	    Duration: 30 * time.Second,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(report)
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/pipz"
+)
+
+// Configuration errors.
+var (
+	ErrInvalidRate     = errors.New("loadtest: RPS must be greater than zero")
+	ErrInvalidDuration = errors.New("loadtest: duration must be greater than zero")
+)
+
+// unknownPath labels errors that did not carry a pipz.Error path.
+const unknownPath = "unknown"
+
+// Config controls a load test run.
+type Config struct {
+	// RPS is the target arrival rate in requests per second.
+	RPS float64
+
+	// Duration is the measured portion of the run. Requests started during
+	// this window are recorded in the report.
+	Duration time.Duration
+
+	// Warmup is an unmeasured period before Duration during which requests
+	// are sent at the same rate but excluded from the report. Use it to prime
+	// caches, connection pools, and circuit breaker state.
+	Warmup time.Duration
+
+	// MaxInFlight caps the number of concurrently executing requests.
+	// Arrivals beyond the cap are counted as dropped rather than queued,
+	// keeping the arrival schedule open-loop. Zero means unlimited.
+	MaxInFlight int
+}
+
+// Report summarizes a load test run.
+type Report struct {
+	// Latency holds the distribution of measured request latencies,
+	// including failed requests.
+	Latency *Histogram
+
+	// ErrorsByPath counts failures keyed by the pipz error path
+	// (e.g. "api -> retry -> fetch"). Non-pipz errors are keyed as "unknown".
+	ErrorsByPath map[string]int
+
+	// Requests is the number of measured requests started.
+	Requests int
+
+	// Successes is the number of measured requests that completed without error.
+	Successes int
+
+	// Errors is the number of measured requests that returned an error.
+	Errors int
+
+	// Dropped is the number of measured arrivals skipped because MaxInFlight
+	// was reached.
+	Dropped int
+
+	// Elapsed is the wall time of the measured window, including the time
+	// spent waiting for in-flight requests to drain.
+	Elapsed time.Duration
+
+	// TargetRPS echoes the configured arrival rate.
+	TargetRPS float64
+}
+
+// AchievedRPS returns the rate of completed measured requests.
+func (r *Report) AchievedRPS() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Successes+r.Errors) / r.Elapsed.Seconds()
+}
+
+// ErrorRate returns the fraction of measured requests that failed.
+func (r *Report) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// String renders a human-readable summary of the report.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "requests=%d successes=%d errors=%d dropped=%d\n",
+		r.Requests, r.Successes, r.Errors, r.Dropped)
+	fmt.Fprintf(&b, "target_rps=%.1f achieved_rps=%.1f elapsed=%v\n",
+		r.TargetRPS, r.AchievedRPS(), r.Elapsed)
+	fmt.Fprintf(&b, "latency min=%v mean=%v p50=%v p90=%v p99=%v p999=%v max=%v\n",
+		r.Latency.Min(), r.Latency.Mean(),
+		r.Latency.Quantile(0.50), r.Latency.Quantile(0.90),
+		r.Latency.Quantile(0.99), r.Latency.Quantile(0.999),
+		r.Latency.Max())
+
+	paths := make([]string, 0, len(r.ErrorsByPath))
+	for p := range r.ErrorsByPath {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		fmt.Fprintf(&b, "error path=%q count=%d\n", p, r.ErrorsByPath[p])
+	}
+	return b.String()
+}
+
+// Run drives the chainable at cfg.RPS for cfg.Warmup plus cfg.Duration,
+// generating each input with gen (called with a monotonically increasing
+// request index). Run waits for all in-flight requests to finish before
+// returning the report.
+//
+// Canceling ctx stops new arrivals; requests already started receive the
+// canceled context and the partial report is returned along with ctx.Err().
+func Run[T any](ctx context.Context, chainable pipz.Chainable[T], gen func(i int) T, cfg Config) (*Report, error) {
+	if cfg.RPS <= 0 {
+		return nil, ErrInvalidRate
+	}
+	if cfg.Duration <= 0 {
+		return nil, ErrInvalidDuration
+	}
+
+	interval := time.Duration(float64(time.Second) / cfg.RPS)
+	if interval <= 0 {
+		interval = time.Nanosecond
+	}
+
+	report := &Report{
+		Latency:      NewHistogram(),
+		ErrorsByPath: make(map[string]int),
+		TargetRPS:    cfg.RPS,
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		inFlight atomic.Int64
+	)
+
+	start := time.Now()
+	measureFrom := start.Add(cfg.Warmup)
+	end := measureFrom.Add(cfg.Duration)
+
+	var runErr error
+	for i := 0; ; i++ {
+		// Open-loop schedule: arrival i is due at start + i*interval.
+		due := start.Add(time.Duration(i) * interval)
+		if !due.Before(end) {
+			break
+		}
+		if wait := time.Until(due); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+			}
+		}
+		if ctx.Err() != nil {
+			runErr = ctx.Err()
+			break
+		}
+
+		measured := !due.Before(measureFrom)
+
+		if cfg.MaxInFlight > 0 && inFlight.Load() >= int64(cfg.MaxInFlight) {
+			if measured {
+				mu.Lock()
+				report.Dropped++
+				mu.Unlock()
+			}
+			continue
+		}
+
+		input := gen(i)
+		inFlight.Add(1)
+		wg.Add(1)
+		if measured {
+			mu.Lock()
+			report.Requests++
+			mu.Unlock()
+		}
+
+		go func(data T, measured bool) {
+			defer wg.Done()
+			defer inFlight.Add(-1)
+
+			began := time.Now()
+			_, err := chainable.Process(ctx, data)
+			latency := time.Since(began)
+
+			if !measured {
+				return
+			}
+			report.Latency.Record(latency)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				report.Errors++
+				report.ErrorsByPath[errorPath[T](err)]++
+				return
+			}
+			report.Successes++
+		}(input, measured)
+	}
+
+	wg.Wait()
+
+	measuredStart := measureFrom
+	if now := time.Now(); now.Before(measuredStart) {
+		measuredStart = now
+	}
+	report.Elapsed = time.Since(measuredStart)
+	return report, runErr
+}
+
+// errorPath extracts a stable path label from an error.
+func errorPath[T any](err error) string {
+	var pipeErr *pipz.Error[T]
+	if !errors.As(err, &pipeErr) || len(pipeErr.Path) == 0 {
+		return unknownPath
+	}
+	names := make([]string, len(pipeErr.Path))
+	for i, id := range pipeErr.Path {
+		names[i] = id.Name()
+	}
+	return strings.Join(names, " -> ")
+}
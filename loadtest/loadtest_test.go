@@ -0,0 +1,129 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/pipz"
+)
+
+func TestRun_InvalidConfig(t *testing.T) {
+	proc := pipz.Transform(pipz.NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })
+	gen := func(i int) int { return i }
+
+	if _, err := Run[int](context.Background(), proc, gen, Config{Duration: time.Second}); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("expected ErrInvalidRate, got %v", err)
+	}
+	if _, err := Run[int](context.Background(), proc, gen, Config{RPS: 10}); !errors.Is(err, ErrInvalidDuration) {
+		t.Errorf("expected ErrInvalidDuration, got %v", err)
+	}
+}
+
+func TestRun_SuccessAndWarmup(t *testing.T) {
+	proc := pipz.Transform(pipz.NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })
+
+	report, err := Run[int](context.Background(), proc, func(i int) int { return i }, Config{
+		RPS:      200,
+		Warmup:   50 * time.Millisecond,
+		Duration: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 100ms at 200 RPS is 20 measured arrivals; warmup arrivals are excluded.
+	if report.Requests != 20 {
+		t.Errorf("expected 20 measured requests, got %d", report.Requests)
+	}
+	if report.Successes != report.Requests || report.Errors != 0 {
+		t.Errorf("expected all successes, got %d successes %d errors", report.Successes, report.Errors)
+	}
+	if report.Latency.Count() != uint64(report.Requests) {
+		t.Errorf("expected %d latency samples, got %d", report.Requests, report.Latency.Count())
+	}
+	if report.AchievedRPS() <= 0 {
+		t.Error("expected positive achieved RPS")
+	}
+	if report.ErrorRate() != 0 {
+		t.Errorf("expected zero error rate, got %v", report.ErrorRate())
+	}
+}
+
+func TestRun_ErrorBreakdownByPath(t *testing.T) {
+	fail := pipz.Apply(pipz.NewIdentity("fetch", ""), func(_ context.Context, n int) (int, error) {
+		if n%2 == 0 {
+			return n, errors.New("upstream unavailable")
+		}
+		return n, nil
+	})
+	seq := pipz.NewSequence(pipz.NewIdentity("api", ""), fail)
+
+	report, err := Run[int](context.Background(), seq, func(i int) int { return i }, Config{
+		RPS:      100,
+		Duration: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Errors == 0 {
+		t.Fatal("expected errors to be recorded")
+	}
+	if got := report.ErrorsByPath["api -> fetch"]; got != report.Errors {
+		t.Errorf("expected %d errors at path 'api -> fetch', got %d (%v)", report.Errors, got, report.ErrorsByPath)
+	}
+	if !strings.Contains(report.String(), `error path="api -> fetch"`) {
+		t.Errorf("expected report summary to include error path, got:\n%s", report)
+	}
+}
+
+func TestRun_MaxInFlightDrops(t *testing.T) {
+	release := make(chan struct{})
+	slow := pipz.Effect(pipz.NewIdentity("slow", ""), func(ctx context.Context, _ int) error {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+
+	go func() {
+		time.Sleep(120 * time.Millisecond)
+		close(release)
+	}()
+
+	report, err := Run[int](context.Background(), slow, func(i int) int { return i }, Config{
+		RPS:         100,
+		Duration:    100 * time.Millisecond,
+		MaxInFlight: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Requests != 2 {
+		t.Errorf("expected 2 admitted requests, got %d", report.Requests)
+	}
+	if report.Dropped == 0 {
+		t.Error("expected arrivals beyond MaxInFlight to be dropped")
+	}
+}
+
+func TestRun_ContextCanceled(t *testing.T) {
+	proc := pipz.Transform(pipz.NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	report, err := Run[int](ctx, proc, func(i int) int { return i }, Config{
+		RPS:      100,
+		Duration: time.Second,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if report == nil || report.Requests >= 100 {
+		t.Errorf("expected partial report, got %+v", report)
+	}
+}
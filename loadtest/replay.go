@@ -0,0 +1,239 @@
+package loadtest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/pipz"
+)
+
+// ErrInvalidSpeed is returned by Replay for a negative speed multiplier.
+var ErrInvalidSpeed = errors.New("loadtest: speed must not be negative")
+
+// TraceEntry is one recorded input and the time it originally arrived.
+type TraceEntry[T any] struct {
+	At    time.Time `json:"at"`
+	Input T         `json:"input"`
+}
+
+// DecodeTrace reads a trace written as JSON lines, one TraceEntry per
+// line. Blank lines are skipped.
+func DecodeTrace[T any](r io.Reader) ([]TraceEntry[T], error) {
+	var trace []TraceEntry[T]
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry TraceEntry[T]
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("loadtest: trace line %d: %w", line, err)
+		}
+		trace = append(trace, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("loadtest: reading trace: %w", err)
+	}
+	return trace, nil
+}
+
+// ReplayConfig controls a trace replay.
+type ReplayConfig struct {
+	// Speed compresses the trace's timeline: at 10 an hour of recorded
+	// traffic replays in six minutes, arriving ten times as densely. Zero
+	// replays in real time.
+	Speed float64
+
+	// MaxInFlight caps the number of concurrently executing requests, as
+	// in Config. Zero means unlimited.
+	MaxInFlight int
+}
+
+// ReplayReport summarizes a trace replay: the load test report for every
+// replayed input, plus how the pipeline's protective connectors behaved.
+type ReplayReport struct {
+	Report
+
+	// Behavior counts the breaker, rate limiter, and timeout signals the
+	// replay triggered, keyed "connector: signal", such as
+	// "payments-breaker: circuitbreaker.opened".
+	Behavior map[string]int
+
+	// TraceSpan is the time between the trace's first and last arrivals.
+	TraceSpan time.Duration
+
+	// Speed echoes the effective speed multiplier.
+	Speed float64
+}
+
+// String renders a human-readable summary of the replay.
+func (r *ReplayReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "trace_span=%v speed=%.1fx\n", r.TraceSpan, r.Speed)
+	b.WriteString(r.Report.String())
+
+	keys := make([]string, 0, len(r.Behavior))
+	for k := range r.Behavior {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "behavior %q count=%d\n", k, r.Behavior[k])
+	}
+	return b.String()
+}
+
+// replaySignals are the signals counted into ReplayReport.Behavior.
+var replaySignals = []capitan.Signal{
+	pipz.SignalCircuitBreakerOpened,
+	pipz.SignalCircuitBreakerHalfOpen,
+	pipz.SignalCircuitBreakerClosed,
+	pipz.SignalCircuitBreakerRejected,
+	pipz.SignalRateLimiterThrottled,
+	pipz.SignalRateLimiterDropped,
+	pipz.SignalTimeoutTriggered,
+}
+
+// replayKey marks the contexts of one replay, so its signals can be told
+// apart from those of other traffic.
+type replayKey struct{}
+
+// Replay sends a recorded trace through chainable, preserving the gaps
+// between the original arrivals divided by cfg.Speed, and reports how the
+// pipeline coped. It validates configuration changes against real traffic
+// shapes offline: replay the same trace through the current and proposed
+// pipelines and compare where breakers open, limiters throttle, and
+// timeouts fire.
+//
+// Only arrivals are compressed. Breaker reset timeouts, limiter rates,
+// and timeouts keep running in real time, so a faster replay shows how the
+// pipeline would behave under proportionally heavier traffic. Like Run,
+// arrivals are open-loop, and Replay waits for in-flight requests before
+// returning. Canceling ctx stops new arrivals and returns the partial
+// report with ctx.Err().
+//
+// Example:
+//
+//	f, _ := os.Open("orders-trace.jsonl")
+//	trace, err := loadtest.DecodeTrace[Order](f)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	report, err := loadtest.Replay(ctx, proposedPipeline, trace, loadtest.ReplayConfig{Speed: 20})
+//	fmt.Println(report)
+func Replay[T any](ctx context.Context, chainable pipz.Chainable[T], trace []TraceEntry[T], cfg ReplayConfig) (*ReplayReport, error) {
+	if cfg.Speed < 0 {
+		return nil, ErrInvalidSpeed
+	}
+	speed := cfg.Speed
+	if speed == 0 {
+		speed = 1
+	}
+
+	ordered := append([]TraceEntry[T](nil), trace...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].At.Before(ordered[j].At) })
+
+	report := &ReplayReport{
+		Report: Report{
+			Latency:      NewHistogram(),
+			ErrorsByPath: make(map[string]int),
+		},
+		Behavior: make(map[string]int),
+		Speed:    speed,
+	}
+	if len(ordered) == 0 {
+		return report, nil
+	}
+	report.TraceSpan = ordered[len(ordered)-1].At.Sub(ordered[0].At)
+	if span := time.Duration(float64(report.TraceSpan) / speed); span > 0 {
+		report.TargetRPS = float64(len(ordered)) / span.Seconds()
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		inFlight atomic.Int64
+	)
+
+	marker := new(int)
+	ctx = context.WithValue(ctx, replayKey{}, marker)
+	observer := capitan.Observe(func(_ context.Context, e *capitan.Event) {
+		if eventCtx := e.Context(); eventCtx == nil || eventCtx.Value(replayKey{}) != marker {
+			return
+		}
+		name, _ := pipz.FieldName.From(e)
+		mu.Lock()
+		report.Behavior[name+": "+e.Signal().Name()]++
+		mu.Unlock()
+	}, replaySignals...)
+	defer observer.Close()
+
+	start := time.Now()
+	origin := ordered[0].At
+
+	var runErr error
+	for _, entry := range ordered {
+		due := start.Add(time.Duration(float64(entry.At.Sub(origin)) / speed))
+		if wait := time.Until(due); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+			}
+		}
+		if ctx.Err() != nil {
+			runErr = ctx.Err()
+			break
+		}
+
+		if cfg.MaxInFlight > 0 && inFlight.Load() >= int64(cfg.MaxInFlight) {
+			mu.Lock()
+			report.Dropped++
+			mu.Unlock()
+			continue
+		}
+
+		inFlight.Add(1)
+		wg.Add(1)
+		mu.Lock()
+		report.Requests++
+		mu.Unlock()
+
+		go func(data T) {
+			defer wg.Done()
+			defer inFlight.Add(-1)
+
+			began := time.Now()
+			_, err := chainable.Process(ctx, data)
+			report.Latency.Record(time.Since(began))
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				report.Errors++
+				report.ErrorsByPath[errorPath[T](err)]++
+				return
+			}
+			report.Successes++
+		}(entry.Input)
+	}
+
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+	if err := observer.Drain(context.WithoutCancel(ctx)); err != nil && runErr == nil {
+		runErr = err
+	}
+	return report, runErr
+}
@@ -0,0 +1,145 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/pipz"
+)
+
+func TestReplay_InvalidSpeed(t *testing.T) {
+	proc := pipz.Transform(pipz.NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })
+
+	if _, err := Replay[int](context.Background(), proc, nil, ReplayConfig{Speed: -1}); !errors.Is(err, ErrInvalidSpeed) {
+		t.Errorf("expected ErrInvalidSpeed, got %v", err)
+	}
+}
+
+func TestReplay_EmptyTrace(t *testing.T) {
+	proc := pipz.Transform(pipz.NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })
+
+	report, err := Replay[int](context.Background(), proc, nil, ReplayConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Requests != 0 || report.Speed != 1 {
+		t.Errorf("expected empty report at 1x, got %d requests at %vx", report.Requests, report.Speed)
+	}
+}
+
+func TestReplay_CompressesTimeline(t *testing.T) {
+	var seen []int
+	proc := pipz.Effect(pipz.NewIdentity("record", ""), func(_ context.Context, n int) error {
+		seen = append(seen, n)
+		return nil
+	})
+
+	origin := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Recorded out of order; replay follows the timestamps.
+	trace := []TraceEntry[int]{
+		{At: origin.Add(2 * time.Second), Input: 3},
+		{At: origin, Input: 1},
+		{At: origin.Add(time.Second), Input: 2},
+	}
+
+	report, err := Replay[int](context.Background(), proc, trace, ReplayConfig{Speed: 20, MaxInFlight: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Two seconds of trace at 20x replays in about 100ms.
+	if report.Elapsed < 90*time.Millisecond || report.Elapsed > time.Second {
+		t.Errorf("expected about 100ms elapsed, got %v", report.Elapsed)
+	}
+	if report.TraceSpan != 2*time.Second {
+		t.Errorf("expected 2s trace span, got %v", report.TraceSpan)
+	}
+	if report.Requests != 3 || report.Successes != 3 {
+		t.Errorf("expected 3 successful requests, got %d requests %d successes", report.Requests, report.Successes)
+	}
+	if len(seen) != 3 || seen[0] != 1 || seen[1] != 2 || seen[2] != 3 {
+		t.Errorf("expected inputs in timestamp order, got %v", seen)
+	}
+}
+
+func TestReplay_CountsBreakerBehavior(t *testing.T) {
+	fail := pipz.Apply(pipz.NewIdentity("upstream", ""), func(_ context.Context, n int) (int, error) {
+		return n, errors.New("upstream unavailable")
+	})
+	breaker := pipz.NewCircuitBreaker(pipz.NewIdentity("replay-breaker", ""), fail, 2, time.Hour)
+
+	origin := time.Now()
+	trace := make([]TraceEntry[int], 5)
+	for i := range trace {
+		trace[i] = TraceEntry[int]{At: origin.Add(time.Duration(i) * 10 * time.Millisecond), Input: i}
+	}
+
+	report, err := Replay[int](context.Background(), breaker, trace, ReplayConfig{Speed: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Errors != 5 {
+		t.Errorf("expected 5 errors, got %d", report.Errors)
+	}
+	if got := report.Behavior["replay-breaker: circuitbreaker.opened"]; got != 1 {
+		t.Errorf("expected breaker to open once, got %d (behavior %v)", got, report.Behavior)
+	}
+	if got := report.Behavior["replay-breaker: circuitbreaker.rejected"]; got != 3 {
+		t.Errorf("expected 3 rejections, got %d (behavior %v)", got, report.Behavior)
+	}
+	if !strings.Contains(report.String(), `behavior "replay-breaker: circuitbreaker.opened" count=1`) {
+		t.Errorf("expected behavior in summary, got:\n%s", report.String())
+	}
+}
+
+func TestReplay_ContextCanceled(t *testing.T) {
+	proc := pipz.Transform(pipz.NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })
+
+	origin := time.Now()
+	trace := []TraceEntry[int]{
+		{At: origin, Input: 1},
+		{At: origin.Add(time.Hour), Input: 2},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	report, err := Replay[int](ctx, proc, trace, ReplayConfig{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if report.Requests != 1 {
+		t.Errorf("expected 1 request before cancellation, got %d", report.Requests)
+	}
+}
+
+func TestDecodeTrace(t *testing.T) {
+	t.Run("Decodes JSON Lines", func(t *testing.T) {
+		input := `{"at":"2024-01-01T12:00:00Z","input":1}
+
+{"at":"2024-01-01T12:00:01Z","input":2}
+`
+		trace, err := DecodeTrace[int](strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(trace) != 2 || trace[1].Input != 2 {
+			t.Fatalf("expected 2 entries, got %+v", trace)
+		}
+		if got := trace[1].At.Sub(trace[0].At); got != time.Second {
+			t.Errorf("expected 1s between entries, got %v", got)
+		}
+	})
+
+	t.Run("Reports Bad Line", func(t *testing.T) {
+		input := "{\"at\":\"2024-01-01T12:00:00Z\",\"input\":1}\nnot json\n"
+		_, err := DecodeTrace[int](strings.NewReader(input))
+		if err == nil || !strings.Contains(err.Error(), "trace line 2") {
+			t.Errorf("expected error for line 2, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,245 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CodedError is a failure identified by a stable code, such as "ORDER_OOS",
+// with the arguments that describe this occurrence. Its Error message is
+// an internal diagnostic; an ErrorLocalizer turns the code and arguments
+// into the message customers see.
+//
+// Two CodedErrors match by errors.Is when their codes are equal, so a
+// code-only CodedError works as a sentinel, for HTTPErrorMapper.Map among
+// others.
+type CodedError struct {
+	// Cause is the internal error behind the failure, if any. It appears
+	// in diagnostics and is reachable with errors.Is and errors.As, but
+	// never in localized messages.
+	Cause error
+	Code  string
+	Args  []any
+}
+
+// Coded returns a CodedError with code and args, for processors to return
+// failures that customers may see.
+//
+// Example:
+//
+//	if stock < order.Quantity {
+//	    return order, pipz.Coded("ORDER_OOS", order.SKU, stock)
+//	}
+func Coded(code string, args ...any) *CodedError {
+	return &CodedError{Code: code, Args: args}
+}
+
+// Wrap attaches the internal error behind the failure and returns e.
+func (e *CodedError) Wrap(cause error) *CodedError {
+	e.Cause = cause
+	return e
+}
+
+// Error returns the code with its arguments and cause, for logs.
+func (e *CodedError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Code)
+	if len(e.Args) > 0 {
+		fmt.Fprintf(&b, " %v", e.Args)
+	}
+	if e.Cause != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Cause.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the cause.
+func (e *CodedError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is a CodedError with the same code.
+func (e *CodedError) Is(target error) bool {
+	var coded *CodedError
+	return errors.As(target, &coded) && coded != nil && coded.Code == e.Code
+}
+
+// ErrorCode returns the code of the first CodedError in err's chain.
+func ErrorCode(err error) (string, bool) {
+	var coded *CodedError
+	if !errors.As(err, &coded) {
+		return "", false
+	}
+	return coded.Code, true
+}
+
+// localeKey is the context key for the caller's locale.
+type localeKey struct{}
+
+// WithLocale returns a context carrying the caller's locale, as a BCP 47
+// tag such as "fr-CA", for ErrorLocalizer to pick messages in.
+//
+// Example:
+//
+//	ctx = pipz.WithLocale(ctx, r.Header.Get("Content-Language"))
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale set by WithLocale.
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	locale, ok := ctx.Value(localeKey{}).(string)
+	return locale, ok && locale != ""
+}
+
+// DefaultGenericMessage is the message ErrorLocalizer returns for failures
+// it has no message for, unless replaced with SetGeneric.
+const DefaultGenericMessage = "Something went wrong. Please try again later."
+
+// messagePlaceholder matches the {0}, {1}, ... placeholders of templates.
+var messagePlaceholder = regexp.MustCompile(`\{(\d+)\}`)
+
+// ErrorLocalizer maps coded errors to localized, user-safe messages at the
+// boundary of a pipeline, such as an API handler or a support tool,
+// keeping internal diagnostics out of what customers see.
+//
+// Messages are templates registered per locale and code, where {0}, {1},
+// ... stand for the CodedError's arguments, so translations may order them
+// freely. The locale comes from the context (see WithLocale) and falls
+// back from the exact tag to its language ("fr-CA" to "fr") and then to
+// the default locale. Errors without a code, or with a code that has no
+// message in any of these locales, get the generic message: their text is
+// never shown.
+//
+// Example:
+//
+//	localizer := pipz.NewErrorLocalizer("en").
+//	    Add("en", "ORDER_OOS", "Sorry, {0} is out of stock ({1} left).").
+//	    Add("fr", "ORDER_OOS", "Désolé, {0} est en rupture de stock ({1} restant).")
+//
+//	_, err := checkout.Process(pipz.WithLocale(ctx, "fr-CA"), order)
+//	if err != nil {
+//	    log.Printf("checkout failed: %v", err) // ORDER_OOS [SKU-42 0]
+//	    problem := mapper.Problem(err)
+//	    problem.Detail = localizer.Message(ctx, err) // Désolé, SKU-42 est en rupture de stock (0 restant).
+//	}
+type ErrorLocalizer struct {
+	messages      map[string]map[string]string
+	generic       map[string]string
+	defaultLocale string
+	mu            sync.RWMutex
+}
+
+// NewErrorLocalizer creates a localizer falling back to defaultLocale.
+func NewErrorLocalizer(defaultLocale string) *ErrorLocalizer {
+	return &ErrorLocalizer{
+		messages:      make(map[string]map[string]string),
+		generic:       make(map[string]string),
+		defaultLocale: normalizeLocale(defaultLocale),
+	}
+}
+
+// Add registers the message template for code in locale.
+func (l *ErrorLocalizer) Add(locale, code, template string) *ErrorLocalizer {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	locale = normalizeLocale(locale)
+	if l.messages[locale] == nil {
+		l.messages[locale] = make(map[string]string)
+	}
+	l.messages[locale][code] = template
+	return l
+}
+
+// AddCatalog registers the message templates of locale, by code.
+func (l *ErrorLocalizer) AddCatalog(locale string, templates map[string]string) *ErrorLocalizer {
+	for code, template := range templates {
+		l.Add(locale, code, template)
+	}
+	return l
+}
+
+// SetGeneric sets the message of locale for failures without a message.
+func (l *ErrorLocalizer) SetGeneric(locale, message string) *ErrorLocalizer {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.generic[normalizeLocale(locale)] = message
+	return l
+}
+
+// Message returns the user-safe message for err in the locale of ctx, or
+// in the default locale if ctx carries none. It returns "" for nil.
+func (l *ErrorLocalizer) Message(ctx context.Context, err error) string {
+	locale, _ := LocaleFromContext(ctx)
+	message, _ := l.Localize(locale, err)
+	return message
+}
+
+// Localize returns the user-safe message for err in locale, reporting
+// whether it is the message for err's code rather than the generic one.
+func (l *ErrorLocalizer) Localize(locale string, err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	candidates := l.localeCandidates(locale)
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		for _, candidate := range candidates {
+			if template, ok := l.messages[candidate][coded.Code]; ok {
+				return formatMessage(template, coded.Args), true
+			}
+		}
+	}
+	for _, candidate := range candidates {
+		if message, ok := l.generic[candidate]; ok {
+			return message, false
+		}
+	}
+	return DefaultGenericMessage, false
+}
+
+// localeCandidates returns the locales to look messages up in, from the
+// most to the least specific.
+func (l *ErrorLocalizer) localeCandidates(locale string) []string {
+	locale = normalizeLocale(locale)
+	var candidates []string
+	for locale != "" {
+		candidates = append(candidates, locale)
+		i := strings.LastIndexByte(locale, '-')
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return append(candidates, l.defaultLocale)
+}
+
+// normalizeLocale returns locale in the lowercase, hyphenated form
+// messages are keyed by, so "fr_CA" and "fr-ca" find "fr-CA".
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// formatMessage substitutes args for the placeholders of template, leaving
+// placeholders without an argument as they are.
+func formatMessage(template string, args []any) string {
+	return messagePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		i, err := strconv.Atoi(placeholder[1 : len(placeholder)-1])
+		if err != nil || i >= len(args) {
+			return placeholder
+		}
+		return fmt.Sprint(args[i])
+	})
+}
@@ -0,0 +1,124 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCodedError(t *testing.T) {
+	t.Run("Diagnostic Message", func(t *testing.T) {
+		err := Coded("ORDER_OOS", "SKU-42", 0).Wrap(errors.New("inventory: 0 on hand"))
+		if got := err.Error(); got != "ORDER_OOS [SKU-42 0]: inventory: 0 on hand" {
+			t.Errorf("unexpected message %q", got)
+		}
+		if got := Coded("PAYMENT_DECLINED").Error(); got != "PAYMENT_DECLINED" {
+			t.Errorf("unexpected message %q", got)
+		}
+	})
+
+	t.Run("Matches By Code", func(t *testing.T) {
+		err := fmt.Errorf("checkout: %w", Coded("ORDER_OOS", "SKU-42"))
+		if !errors.Is(err, Coded("ORDER_OOS")) {
+			t.Error("expected a match by code")
+		}
+		if errors.Is(err, Coded("PAYMENT_DECLINED")) {
+			t.Error("expected no match for another code")
+		}
+		if code, ok := ErrorCode(err); !ok || code != "ORDER_OOS" {
+			t.Errorf("expected ORDER_OOS, got %q", code)
+		}
+		if _, ok := ErrorCode(errors.New("boom")); ok {
+			t.Error("expected no code for a plain error")
+		}
+	})
+
+	t.Run("Cause Is Reachable", func(t *testing.T) {
+		err := Coded("ITEM_MISSING").Wrap(ErrNotFound)
+		if !errors.Is(err, ErrNotFound) {
+			t.Error("expected the cause to match")
+		}
+	})
+
+	t.Run("Through Pipeline Errors", func(t *testing.T) {
+		reserve := Apply(testIdentity("reserve"), func(_ context.Context, n int) (int, error) {
+			return n, Coded("ORDER_OOS", "SKU-42", n)
+		})
+		_, err := NewSequence(testIdentity("checkout"), reserve).Process(context.Background(), 3)
+		if code, ok := ErrorCode(err); !ok || code != "ORDER_OOS" {
+			t.Errorf("expected the code through the pipeline error, got %v", err)
+		}
+		mapper := NewHTTPErrorMapper().Map(Coded("ORDER_OOS"), http.StatusConflict, "out-of-stock", "Conflict")
+		if status := mapper.Status(err); status != http.StatusConflict {
+			t.Errorf("expected a mapped status, got %d", status)
+		}
+	})
+}
+
+func TestLocale(t *testing.T) {
+	if _, ok := LocaleFromContext(context.Background()); ok {
+		t.Error("expected no locale")
+	}
+	if locale, ok := LocaleFromContext(WithLocale(context.Background(), "fr-CA")); !ok || locale != "fr-CA" {
+		t.Errorf("expected fr-CA, got %q", locale)
+	}
+}
+
+func TestErrorLocalizer(t *testing.T) {
+	localizer := NewErrorLocalizer("en").
+		Add("en", "ORDER_OOS", "Sorry, {0} is out of stock ({1} left).").
+		AddCatalog("fr", map[string]string{
+			"ORDER_OOS":        "Désolé, il reste {1} {0}.",
+			"PAYMENT_DECLINED": "Paiement refusé.",
+		}).
+		Add("fr-CA", "PAYMENT_DECLINED", "Paiement refusé par votre institution.")
+	oos := fmt.Errorf("checkout: %w", Coded("ORDER_OOS", "SKU-42", 0))
+
+	t.Run("Locale From Context", func(t *testing.T) {
+		ctx := WithLocale(context.Background(), "fr")
+		if got := localizer.Message(ctx, oos); got != "Désolé, il reste 0 SKU-42." {
+			t.Errorf("unexpected message %q", got)
+		}
+		if got := localizer.Message(context.Background(), oos); got != "Sorry, SKU-42 is out of stock (0 left)." {
+			t.Errorf("expected the default locale without one in context, got %q", got)
+		}
+	})
+
+	t.Run("Falls Back To Language Then Default", func(t *testing.T) {
+		declined := Coded("PAYMENT_DECLINED")
+		if got, ok := localizer.Localize("fr_ca", declined); !ok || got != "Paiement refusé par votre institution." {
+			t.Errorf("expected the exact locale, got %q", got)
+		}
+		if got, _ := localizer.Localize("fr-CA", oos); got != "Désolé, il reste 0 SKU-42." {
+			t.Errorf("expected the language's message, got %q", got)
+		}
+		if got, _ := localizer.Localize("de-DE", oos); got != "Sorry, SKU-42 is out of stock (0 left)." {
+			t.Errorf("expected the default locale's message, got %q", got)
+		}
+	})
+
+	t.Run("Generic Message Hides Diagnostics", func(t *testing.T) {
+		internal := errors.New("pq: connection refused to 10.0.0.7")
+		if got, ok := localizer.Localize("en", internal); ok || got != DefaultGenericMessage {
+			t.Errorf("expected the generic message, got %q", got)
+		}
+		if got, ok := localizer.Localize("en", Coded("UNKNOWN_CODE")); ok || got != DefaultGenericMessage {
+			t.Errorf("expected the generic message for an unknown code, got %q", got)
+		}
+		localizer := NewErrorLocalizer("en").SetGeneric("fr", "Une erreur est survenue.")
+		if got, _ := localizer.Localize("fr-CA", internal); got != "Une erreur est survenue." {
+			t.Errorf("expected the locale's generic message, got %q", got)
+		}
+		if got, _ := localizer.Localize("en", nil); got != "" {
+			t.Errorf("expected no message for nil, got %q", got)
+		}
+	})
+
+	t.Run("Missing Arguments", func(t *testing.T) {
+		if got, _ := localizer.Localize("en", Coded("ORDER_OOS", "SKU-42")); got != "Sorry, SKU-42 is out of stock ({1} left)." {
+			t.Errorf("expected the placeholder kept, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package pipz
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync/atomic"
+
+	"github.com/zoobzio/capitan"
+)
+
+// LogOption configures a LogBridge.
+type LogOption func(*logConfig)
+
+type logConfig struct {
+	levels   map[capitan.Signal]slog.Level
+	sampling map[capitan.Signal]uint64
+	signals  []capitan.Signal
+	minLevel slog.Level
+}
+
+// WithLogLevel logs signal at level instead of the level derived from the
+// event's severity.
+func WithLogLevel(signal capitan.Signal, level slog.Level) LogOption {
+	return func(c *logConfig) {
+		c.levels[signal] = level
+	}
+}
+
+// WithLogSampling logs only one in every n events of signal, for
+// high-volume signals such as ratelimiter.allowed. Sampled records carry a
+// sample_rate attribute. Values of n below 2 disable sampling.
+func WithLogSampling(signal capitan.Signal, n int) LogOption {
+	return func(c *logConfig) {
+		if n < 2 {
+			delete(c.sampling, signal)
+			return
+		}
+		c.sampling[signal] = uint64(n)
+	}
+}
+
+// WithLogSignals restricts the bridge to the given signals. By default every
+// signal is logged, including signals created after the bridge.
+func WithLogSignals(signals ...capitan.Signal) LogOption {
+	return func(c *logConfig) {
+		c.signals = append(c.signals, signals...)
+	}
+}
+
+// WithMinLogLevel drops records below level, after per-signal levels are
+// applied. The logger's handler may filter further.
+func WithMinLogLevel(level slog.Level) LogOption {
+	return func(c *logConfig) {
+		c.minLevel = level
+	}
+}
+
+// LogBridge forwards capitan events to a slog.Logger. Each event becomes a
+// record whose message is the signal name and whose attributes are the
+// event's fields, including those attached with AddSignalFields, sorted by
+// key. Create one with BridgeLogs.
+type LogBridge struct {
+	logger   *slog.Logger
+	observer *capitan.Observer
+	levels   map[capitan.Signal]slog.Level
+	sampling map[capitan.Signal]uint64
+	counts   map[capitan.Signal]*atomic.Uint64
+	minLevel slog.Level
+}
+
+// BridgeLogs subscribes logger to pipz signals so operational logs need no
+// per-signal Hook handlers. Levels default to the event severity (DEBUG,
+// INFO, WARN, ERROR) and can be overridden per signal; high-volume signals
+// can be sampled. Close the bridge to unsubscribe.
+//
+// Example:
+//
+//	bridge := pipz.BridgeLogs(slog.Default(),
+//	    pipz.WithLogLevel(pipz.SignalRetryAttemptStart, slog.LevelDebug),
+//	    pipz.WithLogSampling(pipz.SignalRateLimiterAllowed, 100),
+//	    pipz.WithMinLogLevel(slog.LevelInfo),
+//	)
+//	defer bridge.Close()
+func BridgeLogs(logger *slog.Logger, opts ...LogOption) *LogBridge {
+	cfg := logConfig{
+		levels:   make(map[capitan.Signal]slog.Level),
+		sampling: make(map[capitan.Signal]uint64),
+		minLevel: slog.LevelDebug,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	b := &LogBridge{
+		logger:   logger,
+		levels:   cfg.levels,
+		sampling: cfg.sampling,
+		counts:   make(map[capitan.Signal]*atomic.Uint64, len(cfg.sampling)),
+		minLevel: cfg.minLevel,
+	}
+	for signal := range cfg.sampling {
+		b.counts[signal] = new(atomic.Uint64)
+	}
+	b.observer = capitan.Observe(b.log, cfg.signals...)
+	return b
+}
+
+// log converts an event to a slog record.
+func (b *LogBridge) log(ctx context.Context, e *capitan.Event) {
+	signal := e.Signal()
+	level, ok := b.levels[signal]
+	if !ok {
+		level = severityLevel(e.Severity())
+	}
+	minLevel := b.minLevel
+	if o := callOverridesFrom(e.Context()); o != nil && o.logLevel != nil {
+		minLevel = min(minLevel, *o.logLevel)
+	}
+	if level < minLevel || !b.logger.Enabled(ctx, level) {
+		return
+	}
+
+	rate, sampled := b.sampling[signal]
+	if sampled && (b.counts[signal].Add(1)-1)%rate != 0 {
+		return
+	}
+
+	fields := SignalFields(e)
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Key().Name() < fields[j].Key().Name()
+	})
+	attrs := make([]slog.Attr, 0, len(fields)+1)
+	for _, f := range fields {
+		attrs = append(attrs, slog.Any(f.Key().Name(), f.Value()))
+	}
+	if sampled {
+		attrs = append(attrs, slog.Uint64("sample_rate", rate))
+	}
+
+	b.logger.LogAttrs(ctx, level, signal.Name(), attrs...)
+}
+
+// Drain blocks until all events queued before the call have been logged.
+func (b *LogBridge) Drain(ctx context.Context) error {
+	return b.observer.Drain(ctx)
+}
+
+// Close unsubscribes the bridge. Close is idempotent.
+func (b *LogBridge) Close() {
+	b.observer.Close()
+}
+
+// severityLevel maps a capitan severity to a slog level.
+func severityLevel(s capitan.Severity) slog.Level {
+	switch s {
+	case capitan.SeverityDebug:
+		return slog.LevelDebug
+	case capitan.SeverityWarn:
+		return slog.LevelWarn
+	case capitan.SeverityError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,126 @@
+package pipz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes.
+type syncBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) records(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+func newTestLogger(buf *syncBuffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestLogBridge(t *testing.T) {
+	t.Run("Logs Signal With Fields At Severity Level", func(t *testing.T) {
+		var buf syncBuffer
+		bridge := BridgeLogs(newTestLogger(&buf), WithLogSignals(SignalGuardRejected))
+		defer bridge.Close()
+
+		_, _ = NewGuard(NewIdentity("admit", ""), NonZero[int]()).Process(context.Background(), 0) //nolint:errcheck // logging under test
+
+		if err := bridge.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		recs := buf.records(t)
+		if len(recs) != 1 {
+			t.Fatalf("expected 1 record, got %d", len(recs))
+		}
+		if recs[0]["msg"] != "guard.rejected" || recs[0]["level"] != "WARN" || recs[0]["name"] != "admit" {
+			t.Errorf("unexpected record: %v", recs[0])
+		}
+	})
+
+	t.Run("Per Signal Level And Minimum", func(t *testing.T) {
+		signal := capitan.NewSignal("test.logbridge.level", "")
+		quiet := capitan.NewSignal("test.logbridge.quiet", "")
+		var buf syncBuffer
+		bridge := BridgeLogs(newTestLogger(&buf),
+			WithLogSignals(signal, quiet),
+			WithLogLevel(signal, slog.LevelError),
+			WithMinLogLevel(slog.LevelInfo),
+		)
+		defer bridge.Close()
+
+		capitan.Info(context.Background(), signal)
+		capitan.Debug(context.Background(), quiet)
+
+		if err := bridge.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		recs := buf.records(t)
+		if len(recs) != 1 || recs[0]["level"] != "ERROR" {
+			t.Fatalf("expected one ERROR record, got %v", recs)
+		}
+	})
+
+	t.Run("Sampling", func(t *testing.T) {
+		signal := capitan.NewSignal("test.logbridge.sampled", "")
+		var buf syncBuffer
+		bridge := BridgeLogs(newTestLogger(&buf), WithLogSignals(signal), WithLogSampling(signal, 5))
+		defer bridge.Close()
+
+		for i := 0; i < 12; i++ {
+			capitan.Info(context.Background(), signal)
+		}
+
+		if err := bridge.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		recs := buf.records(t)
+		if len(recs) != 3 {
+			t.Fatalf("expected 3 sampled records, got %d", len(recs))
+		}
+		if recs[0]["sample_rate"] != float64(5) {
+			t.Errorf("expected sample_rate 5, got %v", recs[0]["sample_rate"])
+		}
+	})
+
+	t.Run("Close Stops Logging", func(t *testing.T) {
+		signal := capitan.NewSignal("test.logbridge.closed", "")
+		var buf syncBuffer
+		bridge := BridgeLogs(newTestLogger(&buf), WithLogSignals(signal))
+		bridge.Close()
+		bridge.Close()
+
+		capitan.Info(context.Background(), signal)
+		if recs := buf.records(t); len(recs) != 0 {
+			t.Errorf("expected no records after close, got %v", recs)
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrMailboxClosed is returned by Send once a Mailbox is closed, and by
+// Receive once it is closed and drained.
+var ErrMailboxClosed = errors.New("mailbox closed")
+
+// Mailbox is a bounded, typed queue for handing items from one pipeline to
+// another without a message bus. Send blocks while the mailbox is full, so
+// a slow consumer applies backpressure to the producing pipeline; both Send
+// and Receive give up when their context is done.
+//
+// Pair it with SendTo in the producing pipeline and Run (or ReceiveFrom) on
+// the consuming side to build multi-stage asynchronous architectures in
+// process, with each stage free to use its own data type.
+//
+// Example:
+//
+//	var ShipmentsID = pipz.NewIdentity("shipments", "Orders awaiting fulfilment")
+//	shipments := pipz.NewMailbox[Shipment](ShipmentsID, 100)
+//
+//	orders := pipz.NewSequence(OrdersID,
+//	    validate,
+//	    charge,
+//	    pipz.SendTo(HandoffID, shipments, func(_ context.Context, o Order) (Shipment, error) {
+//	        return Shipment{OrderID: o.ID, Address: o.Address}, nil
+//	    }),
+//	)
+//
+//	go shipments.Run(ctx, fulfilment)
+type Mailbox[T any] struct {
+	identity  Identity
+	items     chan T
+	done      chan struct{}
+	mu        sync.RWMutex
+	closeOnce sync.Once
+}
+
+// NewMailbox creates a Mailbox buffering up to capacity items. A capacity
+// of zero or less makes every Send wait for a Receive.
+func NewMailbox[T any](identity Identity, capacity int) *Mailbox[T] {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &Mailbox[T]{
+		identity: identity,
+		items:    make(chan T, capacity),
+		done:     make(chan struct{}),
+	}
+}
+
+// Send delivers item, blocking until there is room, ctx is done, or the
+// mailbox is closed.
+func (m *Mailbox[T]) Send(ctx context.Context, item T) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	select {
+	case <-m.done:
+		return ErrMailboxClosed
+	default:
+	}
+
+	select {
+	case m.items <- item:
+		return nil
+	case <-m.done:
+		return ErrMailboxClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Receive returns the next item, blocking until one arrives or ctx is
+// done. Items sent before Close are still delivered; after that Receive
+// returns ErrMailboxClosed.
+func (m *Mailbox[T]) Receive(ctx context.Context) (T, error) {
+	select {
+	case item, ok := <-m.items:
+		if !ok {
+			var zero T
+			return zero, ErrMailboxClosed
+		}
+		return item, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Run receives items and processes each with processor until ctx is
+// canceled or the mailbox is closed and drained, then returns nil. Items
+// are processed one at a time, in order. Failures emit a mailbox.failed
+// signal and do not stop Run; wrap processor with Handle to act on them.
+// Items dropped with ErrSkip or finished early with Done are not reported.
+func (m *Mailbox[T]) Run(ctx context.Context, processor Chainable[T]) error {
+	for {
+		item, err := m.Receive(ctx)
+		if err != nil {
+			return nil
+		}
+		if _, processErr := processor.Process(ctx, item); processErr != nil && !isControl(processErr) {
+			capitan.Error(context.WithoutCancel(ctx), SignalMailboxFailed,
+				FieldName.Field(m.identity.Name()),
+				FieldIdentityID.Field(m.identity.ID().String()),
+				FieldError.Field(processErr.Error()),
+			)
+		}
+	}
+}
+
+// Len returns the number of items waiting to be received.
+func (m *Mailbox[T]) Len() int {
+	return len(m.items)
+}
+
+// Cap returns the mailbox capacity.
+func (m *Mailbox[T]) Cap() int {
+	return cap(m.items)
+}
+
+// Identity returns the identity of this mailbox.
+func (m *Mailbox[T]) Identity() Identity {
+	return m.identity
+}
+
+// Close stops the mailbox accepting items. Pending Sends fail with
+// ErrMailboxClosed; buffered items remain available to Receive.
+// Close is idempotent.
+func (m *Mailbox[T]) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.done)
+		// Wait for in-flight Sends to observe done before closing items.
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		close(m.items)
+	})
+	return nil
+}
+
+// SendTo creates a Processor that maps each item with mapper and sends the
+// result to mailbox, handing it to another pipeline of a possibly different
+// type. The original item passes through unchanged. SendTo waits for room
+// in the mailbox, so a full mailbox slows the sending pipeline; bound the
+// wait with Timeout when dropping or failing is preferable.
+//
+// Example:
+//
+//	handoff := pipz.SendTo(HandoffID, shipments, func(_ context.Context, o Order) (Shipment, error) {
+//	    return Shipment{OrderID: o.ID}, nil
+//	})
+func SendTo[T, U any](identity Identity, mailbox *Mailbox[U], mapper func(context.Context, T) (U, error)) Processor[T] {
+	return Processor[T]{
+		identity: identity,
+		fn: func(ctx context.Context, value T) (result T, err error) {
+			defer recoverFromPanic(&result, &err, identity, value)
+			start := time.Now()
+
+			mapped, mapErr := mapper(ctx, value)
+			if mapErr != nil {
+				return value, &Error[T]{
+					Path:      []Identity{identity},
+					InputData: errorInput(value),
+					Err:       fmt.Errorf("map: %w", mapErr),
+					Timestamp: time.Now(),
+					Duration:  time.Since(start),
+				}
+			}
+			if sendErr := mailbox.Send(ctx, mapped); sendErr != nil {
+				return value, &Error[T]{
+					Path:      []Identity{identity},
+					InputData: errorInput(value),
+					Err:       fmt.Errorf("send to %s: %w", mailbox.Identity().Name(), sendErr),
+					Timestamp: time.Now(),
+					Duration:  time.Since(start),
+					Timeout:   errors.Is(sendErr, context.DeadlineExceeded),
+					Canceled:  errors.Is(sendErr, context.Canceled),
+				}
+			}
+			return value, nil
+		},
+	}
+}
+
+// ReceiveFrom creates a Processor that replaces its input with the next
+// item from mailbox, blocking until one arrives. It lets a consuming
+// pipeline pull from a mailbox as its first stage when the caller drives
+// the loop; Mailbox.Run covers the common case. Once the mailbox is closed
+// and drained, ReceiveFrom fails with an error wrapping ErrMailboxClosed.
+func ReceiveFrom[T any](identity Identity, mailbox *Mailbox[T]) Processor[T] {
+	return Processor[T]{
+		identity: identity,
+		fn: func(ctx context.Context, value T) (T, error) {
+			start := time.Now()
+			item, err := mailbox.Receive(ctx)
+			if err != nil {
+				return value, &Error[T]{
+					Path:      []Identity{identity},
+					InputData: errorInput(value),
+					Err:       fmt.Errorf("receive from %s: %w", mailbox.Identity().Name(), err),
+					Timestamp: time.Now(),
+					Duration:  time.Since(start),
+					Timeout:   errors.Is(err, context.DeadlineExceeded),
+					Canceled:  errors.Is(err, context.Canceled),
+				}
+			}
+			return item, nil
+		},
+	}
+}
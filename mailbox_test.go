@@ -0,0 +1,210 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestMailbox(t *testing.T) {
+	t.Run("Send And Receive In Order", func(t *testing.T) {
+		box := NewMailbox[int](NewIdentity("box", ""), 3)
+		for i := 1; i <= 3; i++ {
+			if err := box.Send(context.Background(), i); err != nil {
+				t.Fatalf("send %d failed: %v", i, err)
+			}
+		}
+		if box.Len() != 3 || box.Cap() != 3 {
+			t.Errorf("expected len 3 cap 3, got %d %d", box.Len(), box.Cap())
+		}
+		for want := 1; want <= 3; want++ {
+			got, err := box.Receive(context.Background())
+			if err != nil || got != want {
+				t.Errorf("expected %d, got %d (%v)", want, got, err)
+			}
+		}
+	})
+
+	t.Run("Send Blocks When Full", func(t *testing.T) {
+		box := NewMailbox[int](NewIdentity("box", ""), 1)
+		_ = box.Send(context.Background(), 1) //nolint:errcheck // filling the mailbox
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := box.Send(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("Receive Honors Context", func(t *testing.T) {
+		box := NewMailbox[int](NewIdentity("box", ""), 1)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := box.Receive(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected canceled, got %v", err)
+		}
+	})
+
+	t.Run("Close Drains Then Fails", func(t *testing.T) {
+		box := NewMailbox[int](NewIdentity("box", ""), 2)
+		_ = box.Send(context.Background(), 7) //nolint:errcheck // buffered before close
+		if err := box.Close(); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+		if err := box.Close(); err != nil {
+			t.Errorf("expected idempotent close, got %v", err)
+		}
+		if err := box.Send(context.Background(), 8); !errors.Is(err, ErrMailboxClosed) {
+			t.Errorf("expected ErrMailboxClosed on send, got %v", err)
+		}
+		if got, err := box.Receive(context.Background()); err != nil || got != 7 {
+			t.Errorf("expected buffered item 7, got %d (%v)", got, err)
+		}
+		if _, err := box.Receive(context.Background()); !errors.Is(err, ErrMailboxClosed) {
+			t.Errorf("expected ErrMailboxClosed after drain, got %v", err)
+		}
+	})
+
+	t.Run("Close Releases Blocked Senders", func(t *testing.T) {
+		box := NewMailbox[int](NewIdentity("box", ""), 0)
+		errs := make(chan error, 3)
+		for i := 0; i < 3; i++ {
+			go func() { errs <- box.Send(context.Background(), i) }()
+		}
+		time.Sleep(10 * time.Millisecond)
+		_ = box.Close() //nolint:errcheck // always nil
+		for i := 0; i < 3; i++ {
+			select {
+			case err := <-errs:
+				if !errors.Is(err, ErrMailboxClosed) {
+					t.Errorf("expected ErrMailboxClosed, got %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("blocked sender not released by Close")
+			}
+		}
+	})
+}
+
+func TestMailboxHandoff(t *testing.T) {
+	t.Run("Pipelines Of Different Types", func(t *testing.T) {
+		box := NewMailbox[string](NewIdentity("labels", ""), 4)
+		producer := NewSequence(NewIdentity("orders", ""),
+			Transform(NewIdentity("double", ""), func(_ context.Context, v int) int { return v * 2 }),
+			SendTo(NewIdentity("handoff", ""), box, func(_ context.Context, v int) (string, error) {
+				return "order-" + strconv.Itoa(v), nil
+			}),
+		)
+
+		var (
+			mu       sync.Mutex
+			received []string
+		)
+		consumer := Effect(NewIdentity("print", ""), func(_ context.Context, s string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, s)
+			return nil
+		})
+		done := make(chan error)
+		go func() { done <- box.Run(context.Background(), consumer) }()
+
+		for i := 1; i <= 3; i++ {
+			result, err := producer.Process(context.Background(), i)
+			if err != nil || result != i*2 {
+				t.Fatalf("expected pass-through %d, got %d (%v)", i*2, result, err)
+			}
+		}
+		_ = box.Close() //nolint:errcheck // always nil
+		if err := <-done; err != nil {
+			t.Fatalf("run returned %v", err)
+		}
+
+		want := []string{"order-2", "order-4", "order-6"}
+		if len(received) != len(want) {
+			t.Fatalf("expected %v, got %v", want, received)
+		}
+		for i := range want {
+			if received[i] != want[i] {
+				t.Errorf("expected %v, got %v", want, received)
+			}
+		}
+	})
+
+	t.Run("SendTo Mapper Error", func(t *testing.T) {
+		box := NewMailbox[string](NewIdentity("labels", ""), 1)
+		errBad := errors.New("bad order")
+		send := SendTo(NewIdentity("handoff", ""), box, func(_ context.Context, _ int) (string, error) {
+			return "", errBad
+		})
+		_, err := send.Process(context.Background(), 1)
+
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !errors.Is(err, errBad) || pipeErr.Path[0].Name() != "handoff" {
+			t.Errorf("expected wrapped mapper error, got %v", err)
+		}
+		if box.Len() != 0 {
+			t.Error("expected nothing sent on mapper error")
+		}
+	})
+
+	t.Run("SendTo Full Mailbox Times Out", func(t *testing.T) {
+		box := NewMailbox[int](NewIdentity("box", ""), 0)
+		send := SendTo(NewIdentity("handoff", ""), box, func(_ context.Context, v int) (int, error) { return v, nil })
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := send.Process(ctx, 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.IsTimeout() {
+			t.Errorf("expected timeout error, got %v", err)
+		}
+	})
+
+	t.Run("ReceiveFrom", func(t *testing.T) {
+		box := NewMailbox[int](NewIdentity("box", ""), 1)
+		_ = box.Send(context.Background(), 42) //nolint:errcheck // buffered
+		consumer := NewSequence(NewIdentity("consumer", ""),
+			ReceiveFrom(NewIdentity("receive", ""), box),
+			Transform(NewIdentity("inc", ""), func(_ context.Context, v int) int { return v + 1 }),
+		)
+		if result, err := consumer.Process(context.Background(), 0); err != nil || result != 43 {
+			t.Errorf("expected 43, got %d (%v)", result, err)
+		}
+
+		_ = box.Close() //nolint:errcheck // always nil
+		if _, err := consumer.Process(context.Background(), 0); !errors.Is(err, ErrMailboxClosed) {
+			t.Errorf("expected ErrMailboxClosed, got %v", err)
+		}
+	})
+
+	t.Run("Run Emits Failure Signal", func(t *testing.T) {
+		var name string
+		listener := capitan.Hook(SignalMailboxFailed, func(_ context.Context, e *capitan.Event) {
+			name, _ = FieldName.From(e)
+		})
+		defer listener.Close()
+
+		box := NewMailbox[int](NewIdentity("jobs", ""), 1)
+		_ = box.Send(context.Background(), 1) //nolint:errcheck // buffered
+		_ = box.Close()                       //nolint:errcheck // always nil
+		failing := Apply(NewIdentity("fail", ""), func(_ context.Context, v int) (int, error) {
+			return v, errors.New("boom")
+		})
+		if err := box.Run(context.Background(), failing); err != nil {
+			t.Fatalf("run returned %v", err)
+		}
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if name != "jobs" {
+			t.Errorf("expected name 'jobs', got %q", name)
+		}
+	})
+}
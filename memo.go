@@ -0,0 +1,141 @@
+package pipz
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+// memoEntry is one memoized result.
+type memoEntry[R any] struct {
+	expires time.Time
+	value   R
+}
+
+// Memo memoizes an expensive classification, such as an AI query-type
+// detection, so it runs once per logical entity even when several
+// connectors consult it. Results are keyed by the key function and kept
+// for the TTL; share one Memo between the Switch conditions and Filter
+// predicates that need the classification.
+//
+// An empty key bypasses the memo. Concurrent misses for the same key may
+// each run the classification; the last result is kept. A zero TTL keeps
+// results until Reset. To share a value only within one Process call, use
+// Derived, which needs neither key nor TTL.
+//
+// Example:
+//
+//	var queryType = pipz.NewMemo(func(q Query) string { return q.ID }, time.Minute,
+//	    func(ctx context.Context, q Query) string { return classifier.Classify(ctx, q.Text) })
+//
+//	router := pipz.NewSwitch(RouteID, queryType.Get).
+//	    AddRoute("search", search).
+//	    AddRoute("chat", chat)
+//	audit := pipz.NewFilter(AuditID, func(ctx context.Context, q Query) bool {
+//	    return queryType.Get(ctx, q) == "sensitive"
+//	}, auditLog)
+type Memo[T, R any] struct {
+	fn      func(context.Context, T) R
+	key     func(T) string
+	entries map[string]memoEntry[R]
+	clock   clockz.Clock
+	ttl     time.Duration
+	mu      sync.Mutex
+}
+
+// NewMemo creates a Memo caching fn's results by key for ttl.
+func NewMemo[T, R any](key func(T) string, ttl time.Duration, fn func(context.Context, T) R) *Memo[T, R] {
+	return &Memo[T, R]{
+		fn:      fn,
+		key:     key,
+		ttl:     ttl,
+		entries: make(map[string]memoEntry[R]),
+	}
+}
+
+// Get returns the memoized result for data, running the classification if
+// there is no live entry for its key or the call asked to BypassCache, in
+// which case the fresh result replaces the memoized one. Its signature
+// matches Condition when R is string, so it can be passed to NewSwitch
+// directly.
+func (m *Memo[T, R]) Get(ctx context.Context, data T) R {
+	key := m.key(data)
+	if key == "" {
+		return m.fn(ctx, data)
+	}
+
+	m.mu.Lock()
+	now := m.getClock().Now()
+	if entry, ok := m.entries[key]; ok && !CacheBypassed(ctx) {
+		if entry.expires.IsZero() || now.Before(entry.expires) {
+			m.mu.Unlock()
+			return entry.value
+		}
+		delete(m.entries, key)
+	}
+	m.mu.Unlock()
+
+	value := m.fn(ctx, data)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := memoEntry[R]{value: value}
+	if m.ttl > 0 {
+		entry.expires = now.Add(m.ttl)
+	}
+	m.entries[key] = entry
+	return value
+}
+
+// Forget drops the memoized result for key, so the next Get reclassifies.
+func (m *Memo[T, R]) Forget(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+// Purge drops every expired result and returns how many remain.
+func (m *Memo[T, R]) Purge() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.getClock().Now()
+	for key, entry := range m.entries {
+		if !entry.expires.IsZero() && !now.Before(entry.expires) {
+			delete(m.entries, key)
+		}
+	}
+	return len(m.entries)
+}
+
+// Reset drops every memoized result.
+func (m *Memo[T, R]) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.entries)
+}
+
+// Len returns the number of memoized results, including expired ones not
+// yet purged.
+func (m *Memo[T, R]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// WithClock sets a custom clock for testing.
+func (m *Memo[T, R]) WithClock(clock clockz.Clock) *Memo[T, R] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clock = clock
+	return m
+}
+
+// getClock returns the clock to use.
+func (m *Memo[T, R]) getClock() clockz.Clock {
+	if m.clock == nil {
+		return clockz.RealClock
+	}
+	return m.clock
+}
@@ -0,0 +1,107 @@
+package pipz
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+func TestMemo(t *testing.T) {
+	newClassifier := func(calls *atomic.Int64) func(context.Context, string) string {
+		return func(_ context.Context, q string) string {
+			calls.Add(1)
+			if strings.HasPrefix(q, "how") {
+				return "question"
+			}
+			return "statement"
+		}
+	}
+	byText := func(q string) string { return q }
+
+	t.Run("Classifies Once Across Connectors", func(t *testing.T) {
+		var calls atomic.Int64
+		memo := NewMemo(byText, time.Minute, newClassifier(&calls))
+		tag := Transform(NewIdentity("tag", ""), func(_ context.Context, q string) string { return q + "?" })
+		pipeline := NewSequence(NewIdentity("seq", ""),
+			NewFilter(NewIdentity("questions", ""), func(ctx context.Context, q string) bool {
+				return memo.Get(ctx, q) == "question"
+			}, Transform(NewIdentity("noop", ""), func(_ context.Context, q string) string { return q })),
+			NewSwitch(NewIdentity("route", ""), memo.Get).AddRoute("question", tag),
+		)
+
+		result, err := pipeline.Process(context.Background(), "how")
+		if err != nil || result != "how?" {
+			t.Fatalf("expected routed result, got %q, %v", result, err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("expected one classification, got %d", calls.Load())
+		}
+	})
+
+	t.Run("Entries Expire", func(t *testing.T) {
+		var calls atomic.Int64
+		clock := clockz.NewFakeClock()
+		memo := NewMemo(byText, time.Minute, newClassifier(&calls)).WithClock(clock)
+
+		memo.Get(context.Background(), "how")
+		clock.Advance(30 * time.Second)
+		memo.Get(context.Background(), "how")
+		if calls.Load() != 1 {
+			t.Fatalf("expected cached result within TTL, got %d calls", calls.Load())
+		}
+		clock.Advance(time.Minute)
+		memo.Get(context.Background(), "how")
+		if calls.Load() != 2 {
+			t.Errorf("expected reclassification after TTL, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("Empty Key Bypasses", func(t *testing.T) {
+		var calls atomic.Int64
+		memo := NewMemo(func(string) string { return "" }, time.Minute, newClassifier(&calls))
+		memo.Get(context.Background(), "how")
+		memo.Get(context.Background(), "how")
+		if calls.Load() != 2 || memo.Len() != 0 {
+			t.Errorf("expected no memoization, got %d calls and %d entries", calls.Load(), memo.Len())
+		}
+	})
+
+	t.Run("Forget Purge And Reset", func(t *testing.T) {
+		var calls atomic.Int64
+		clock := clockz.NewFakeClock()
+		memo := NewMemo(byText, time.Minute, newClassifier(&calls)).WithClock(clock)
+		memo.Get(context.Background(), "a")
+		memo.Get(context.Background(), "b")
+
+		memo.Forget("a")
+		if memo.Len() != 1 {
+			t.Fatalf("expected 1 entry after Forget, got %d", memo.Len())
+		}
+		clock.Advance(30 * time.Second)
+		memo.Get(context.Background(), "c")
+		clock.Advance(45 * time.Second)
+		if remaining := memo.Purge(); remaining != 1 {
+			t.Errorf("expected only the fresh entry to survive Purge, got %d", remaining)
+		}
+		memo.Reset()
+		if memo.Len() != 0 {
+			t.Errorf("expected empty memo after Reset, got %d", memo.Len())
+		}
+	})
+
+	t.Run("Zero TTL Never Expires", func(t *testing.T) {
+		var calls atomic.Int64
+		clock := clockz.NewFakeClock()
+		memo := NewMemo(byText, 0, newClassifier(&calls)).WithClock(clock)
+		memo.Get(context.Background(), "how")
+		clock.Advance(24 * time.Hour)
+		memo.Get(context.Background(), "how")
+		if calls.Load() != 1 {
+			t.Errorf("expected result kept without TTL, got %d calls", calls.Load())
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package pipz
+
+// Mergeable lets a type define how annotations added by parallel branches
+// fold back into the value a fan-out connector returns. Clone carries
+// cross-cutting metadata such as trace IDs or bag entries into each branch;
+// Merge carries what the branches added back out.
+//
+// Concurrent and Group return their original input when no reducer is set.
+// If T implements Mergeable, they instead return the input merged with each
+// successful branch result in turn, in processor order:
+//
+//	result := input.Merge(branch1).Merge(branch2)...
+//
+// A reducer, when set, takes precedence. Race and Contest return the
+// winning branch's clone, which already carries everything Clone copied.
+//
+// Example:
+//
+//	func (o Order) Merge(other Order) Order {
+//	    tags := make(map[string]string, len(o.Tags)+len(other.Tags))
+//	    maps.Copy(tags, o.Tags)
+//	    maps.Copy(tags, other.Tags)
+//	    o.Tags = tags
+//	    return o
+//	}
+type Mergeable[T any] interface {
+	Merge(other T) T
+}
+
+// isMergeable reports whether values of T can be merged.
+func isMergeable[T any](value T) bool {
+	_, ok := any(value).(Mergeable[T])
+	return ok
+}
+
+// mergeBranches folds the results of successful branches into original in
+// processor order when T implements Mergeable, and returns original
+// unchanged otherwise.
+func mergeBranches[T any](original T, processors []Chainable[T], results map[Identity]T) T {
+	if !isMergeable(original) {
+		return original
+	}
+	merged := original
+	seen := make(map[Identity]bool, len(results))
+	for _, p := range processors {
+		id := p.Identity()
+		res, ok := results[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		mergeable, ok := any(merged).(Mergeable[T])
+		if !ok {
+			break
+		}
+		merged = mergeable.Merge(res)
+	}
+	return merged
+}
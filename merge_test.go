@@ -0,0 +1,113 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+)
+
+// taggedOrder carries tags that parallel branches add to.
+type taggedOrder struct {
+	Tags  map[string]string
+	Trace string
+}
+
+func (o taggedOrder) Clone() taggedOrder {
+	return taggedOrder{Tags: maps.Clone(o.Tags), Trace: o.Trace}
+}
+
+func (o taggedOrder) Merge(other taggedOrder) taggedOrder {
+	tags := maps.Clone(o.Tags)
+	maps.Copy(tags, other.Tags)
+	o.Tags = tags
+	return o
+}
+
+func tagWith(name, key, value string) Chainable[taggedOrder] {
+	return Transform(NewIdentity(name, ""), func(_ context.Context, o taggedOrder) taggedOrder {
+		if o.Trace == "" {
+			return o
+		}
+		o.Tags[key] = value
+		return o
+	})
+}
+
+func TestMergeable(t *testing.T) {
+	input := func() taggedOrder {
+		return taggedOrder{Tags: map[string]string{"origin": "web"}, Trace: "trace-1"}
+	}
+
+	t.Run("Concurrent Merges Branches", func(t *testing.T) {
+		c := NewConcurrent(NewIdentity("concurrent", ""), nil,
+			tagWith("fraud", "fraud", "clear"),
+			tagWith("tax", "tax", "exempt"),
+		)
+		result, err := c.Process(context.Background(), input())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]string{"origin": "web", "fraud": "clear", "tax": "exempt"}
+		if !maps.Equal(result.Tags, want) {
+			t.Errorf("expected tags %v, got %v", want, result.Tags)
+		}
+	})
+
+	t.Run("Later Branches Win In Processor Order", func(t *testing.T) {
+		g := NewGroup(NewIdentity("group", ""),
+			tagWith("first", "status", "first"),
+			tagWith("second", "status", "second"),
+		)
+		result, err := g.Process(context.Background(), input())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Tags["status"] != "second" || result.Tags["origin"] != "web" {
+			t.Errorf("unexpected tags: %v", result.Tags)
+		}
+	})
+
+	t.Run("Failed Branches Are Not Merged", func(t *testing.T) {
+		failing := Apply(NewIdentity("failing", ""), func(_ context.Context, o taggedOrder) (taggedOrder, error) {
+			o.Tags["failed"] = "yes"
+			return o, errors.New("branch failed")
+		})
+		c := NewConcurrent(NewIdentity("concurrent", ""), nil, failing, tagWith("ok", "ok", "yes"))
+		result, err := c.Process(context.Background(), input())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := result.Tags["failed"]; ok || result.Tags["ok"] != "yes" {
+			t.Errorf("expected only successful branch merged, got %v", result.Tags)
+		}
+	})
+
+	t.Run("Reducer Takes Precedence", func(t *testing.T) {
+		reducer := func(original taggedOrder, _ map[Identity]taggedOrder, _ map[Identity]error) taggedOrder {
+			return original
+		}
+		c := NewConcurrent(NewIdentity("concurrent", ""), reducer, tagWith("fraud", "fraud", "clear"))
+		result, err := c.Process(context.Background(), input())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := result.Tags["fraud"]; ok {
+			t.Errorf("expected reducer result without merge, got %v", result.Tags)
+		}
+	})
+
+	t.Run("Non Mergeable Input Is Unchanged", func(t *testing.T) {
+		var counter int32
+		c := NewConcurrent(NewIdentity("concurrent", ""), nil,
+			Transform(NewIdentity("bump", ""), func(_ context.Context, d TestData) TestData {
+				d.Value++
+				return d
+			}),
+		)
+		result, err := c.Process(context.Background(), TestData{Value: 1, Counter: &counter})
+		if err != nil || result.Value != 1 {
+			t.Errorf("expected original value 1, got %d (%v)", result.Value, err)
+		}
+	})
+}
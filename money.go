@@ -0,0 +1,295 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrPrecisionLoss is wrapped by errors from Money helpers for amounts
+// with more precision than the currency's minor unit, such as 12.345 USD,
+// which would otherwise be silently rounded.
+var ErrPrecisionLoss = errors.New("precision loss")
+
+// ErrCurrencyMismatch is wrapped by errors from Money arithmetic on
+// amounts in different currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is an exact amount of a currency, held as an integer number of the
+// currency's minor units, such as cents, so totals never accumulate
+// floating point error. Currency is an upper case ISO 4217 code; the
+// number of minor unit digits follows ToMinorUnits.
+type Money struct {
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+}
+
+// MoneyLocale describes how amounts are written: the decimal separator
+// and the separator grouping thousands, zero for none.
+type MoneyLocale struct {
+	Decimal rune
+	Group   rune
+}
+
+// Common money locales.
+var (
+	// MoneyLocaleEN writes 1,234.56.
+	MoneyLocaleEN = MoneyLocale{Decimal: '.', Group: ','}
+	// MoneyLocaleDE writes 1.234,56.
+	MoneyLocaleDE = MoneyLocale{Decimal: ',', Group: '.'}
+	// MoneyLocaleFR writes 1 234,56 with a narrow no-break space. Any
+	// space is accepted as a group separator when parsing.
+	MoneyLocaleFR = MoneyLocale{Decimal: ',', Group: '\u202f'}
+)
+
+// RateProvider resolves exchange rates for MoneyConvert, such as from a
+// rates API or a table refreshed daily.
+type RateProvider interface {
+	// Rate returns how many units of to one unit of from is worth.
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+// RateFunc adapts a function to the RateProvider interface.
+type RateFunc func(ctx context.Context, from, to string) (float64, error)
+
+// Rate implements the RateProvider interface.
+func (f RateFunc) Rate(ctx context.Context, from, to string) (float64, error) {
+	return f(ctx, from, to)
+}
+
+// ParseMoney parses text written in locale as an amount of currency, such
+// as "1,234.56" or "-0.5" for USD. Digits beyond the currency's minor unit
+// must be zero, otherwise the error wraps ErrPrecisionLoss; malformed text
+// and unknown currency codes return an error wrapping ErrInvalidAmount.
+func ParseMoney(text, currency string, locale MoneyLocale) (Money, error) {
+	currency, err := checkCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	invalid := func() (Money, error) {
+		return Money{}, fmt.Errorf("%w: %q %s", ErrInvalidAmount, text, currency)
+	}
+
+	s := strings.TrimSpace(text)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+	var whole, frac []byte
+	fraction := false
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9' && fraction:
+			frac = append(frac, byte(r))
+		case r >= '0' && r <= '9':
+			whole = append(whole, byte(r))
+		case r == locale.Decimal && !fraction:
+			fraction = true
+		case !fraction && len(whole) > 0 && (r == locale.Group || unicode.IsSpace(r) && unicode.IsSpace(locale.Group)):
+		default:
+			return invalid()
+		}
+	}
+	if len(whole) == 0 && len(frac) == 0 {
+		return invalid()
+	}
+
+	exponent := currencyExponent(currency)
+	if len(frac) > exponent {
+		if strings.Trim(string(frac[exponent:]), "0") != "" {
+			return Money{}, fmt.Errorf("%w: %q has more than %d decimals for %s", ErrPrecisionLoss, text, exponent, currency)
+		}
+		frac = frac[:exponent]
+	}
+	digits := string(whole) + string(frac) + strings.Repeat("0", exponent-len(frac))
+	if negative {
+		digits = "-" + digits
+	}
+	amount, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return invalid()
+	}
+	return Money{Currency: currency, Amount: amount}, nil
+}
+
+// MoneyFromFloat converts a float64 amount of currency, as found at API
+// boundaries, to Money. Unlike ToMinorUnits it does not round: an amount
+// finer than the currency's minor unit, beyond float64 noise, returns an
+// error wrapping ErrPrecisionLoss.
+func MoneyFromFloat(amount float64, currency string) (Money, error) {
+	currency, err := checkCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	units, err := ToMinorUnits(amount, currency)
+	if err != nil {
+		return Money{}, err
+	}
+	if exact := amount * math.Pow10(currencyExponent(currency)); math.Abs(exact-float64(units)) > 1e-6 {
+		return Money{}, fmt.Errorf("%w: %v has more than %d decimals for %s", ErrPrecisionLoss, amount, currencyExponent(currency), currency)
+	}
+	return Money{Currency: currency, Amount: units}, nil
+}
+
+// checkCurrency returns currency in upper case, or an error wrapping
+// ErrInvalidAmount if it is not a three letter code.
+func checkCurrency(currency string) (string, error) {
+	code := strings.ToUpper(currency)
+	if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", fmt.Errorf("%w: currency %q is not an ISO 4217 code", ErrInvalidAmount, currency)
+	}
+	return code, nil
+}
+
+// Add returns the sum of m and other, which must be in the same currency.
+// Overflow returns an error wrapping ErrInvalidAmount.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	sum := m.Amount + other.Amount
+	if (sum > m.Amount) != (other.Amount > 0) {
+		return Money{}, fmt.Errorf("%w: %s + %s overflows", ErrInvalidAmount, m, other)
+	}
+	return Money{Currency: m.Currency, Amount: sum}, nil
+}
+
+// Convert returns m in currency to at rate, rounding half away from zero
+// to the nearest minor unit of to. Converting to m's own currency returns
+// m. Rates that are negative or not finite, and results out of range,
+// return an error wrapping ErrInvalidAmount.
+func (m Money) Convert(to string, rate float64) (Money, error) {
+	to, err := checkCurrency(to)
+	if err != nil {
+		return Money{}, err
+	}
+	if to == m.Currency {
+		return m, nil
+	}
+	if rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return Money{}, fmt.Errorf("%w: rate %v from %s to %s", ErrInvalidAmount, rate, m.Currency, to)
+	}
+	scale := math.Pow10(currencyExponent(to) - currencyExponent(m.Currency))
+	units := math.Round(float64(m.Amount) * rate * scale)
+	if units >= math.MaxInt64 || units < math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: %s at rate %v overflows %s", ErrInvalidAmount, m, rate, to)
+	}
+	return Money{Currency: to, Amount: int64(units)}, nil
+}
+
+// Format returns the amount written in locale, with the currency's minor
+// unit digits and without the currency, such as "1.234,50" for 123450 EUR
+// in MoneyLocaleDE.
+func (m Money) Format(locale MoneyLocale) string {
+	exponent := currencyExponent(m.Currency)
+	digits := strconv.FormatUint(absAmount(m.Amount), 10)
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-exponent], digits[len(digits)-exponent:]
+
+	var b strings.Builder
+	if m.Amount < 0 {
+		b.WriteByte('-')
+	}
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 && locale.Group != 0 {
+			b.WriteRune(locale.Group)
+		}
+		b.WriteRune(d)
+	}
+	if exponent > 0 {
+		b.WriteRune(locale.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// absAmount returns the magnitude of amount, correct for math.MinInt64.
+func absAmount(amount int64) uint64 {
+	if amount < 0 {
+		return uint64(-(amount + 1)) + 1
+	}
+	return uint64(amount)
+}
+
+// String returns the amount and currency, such as "1234.50 EUR".
+func (m Money) String() string {
+	return m.Format(MoneyLocale{Decimal: '.'}) + " " + m.Currency
+}
+
+// MoneyParse creates a Processor parsing an amount written in locale with
+// ParseMoney and storing it on the item, so later stages work with exact
+// minor units. text returns the amount text and its currency code. Items
+// whose amount is malformed or too precise fail with an error wrapping
+// ErrInvalidAmount or ErrPrecisionLoss.
+//
+// Example:
+//
+//	var ParseTotalID = pipz.NewIdentity("parse-total", "Parses order totals from the storefront")
+//	parse := pipz.MoneyParse(ParseTotalID, pipz.MoneyLocaleEN,
+//	    func(o Order) (string, string) { return o.TotalText, o.Currency },
+//	    func(o Order, m pipz.Money) Order { o.Total = m; return o },
+//	)
+func MoneyParse[T any](identity Identity, locale MoneyLocale, text func(T) (amount, currency string), set func(T, Money) T) Processor[T] {
+	return moneyProcessor(identity, func(_ context.Context, value T) (T, error) {
+		amount, currency := text(value)
+		m, err := ParseMoney(amount, currency, locale)
+		if err != nil {
+			return value, err
+		}
+		return set(value, m), nil
+	})
+}
+
+// MoneyConvert creates a Processor converting an amount to currency to at
+// the rate rates returns, rounding to the nearest minor unit. Amounts
+// already in to are left as they are without looking up a rate.
+func MoneyConvert[T any](identity Identity, rates RateProvider, to string, get func(T) Money, set func(T, Money) T) Processor[T] {
+	return moneyProcessor(identity, func(ctx context.Context, value T) (T, error) {
+		m := get(value)
+		if strings.EqualFold(m.Currency, to) {
+			return value, nil
+		}
+		rate, err := rates.Rate(ctx, m.Currency, strings.ToUpper(to))
+		if err != nil {
+			return value, fmt.Errorf("rate from %s to %s: %w", m.Currency, strings.ToUpper(to), err)
+		}
+		converted, err := m.Convert(to, rate)
+		if err != nil {
+			return value, err
+		}
+		return set(value, converted), nil
+	})
+}
+
+// MoneyFormat creates a Processor writing an amount in locale with
+// Money.Format and storing the text on the item, such as for an invoice
+// or a storefront in the customer's locale.
+func MoneyFormat[T any](identity Identity, locale MoneyLocale, get func(T) Money, set func(T, string) T) Processor[T] {
+	return moneyProcessor(identity, func(_ context.Context, value T) (T, error) {
+		return set(value, get(value).Format(locale)), nil
+	})
+}
+
+// moneyProcessor wraps fn in a Processor at identity.
+func moneyProcessor[T any](identity Identity, fn func(context.Context, T) (T, error)) Processor[T] {
+	return Processor[T]{
+		identity: identity,
+		fn: func(ctx context.Context, value T) (result T, err error) {
+			defer recoverFromPanic(&result, &err, identity, value)
+			if result, err = fn(ctx, value); err != nil {
+				return value, &Error[T]{
+					Timestamp: time.Now(),
+					InputData: errorInput(value),
+					Err:       err,
+					Path:      []Identity{identity},
+				}
+			}
+			return result, nil
+		},
+	}
+}
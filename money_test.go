@@ -0,0 +1,168 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	t.Run("Parse", func(t *testing.T) {
+		cases := []struct {
+			text     string
+			currency string
+			locale   MoneyLocale
+			want     Money
+		}{
+			{"1,234.56", "usd", MoneyLocaleEN, Money{"USD", 123456}},
+			{"-0.5", "USD", MoneyLocaleEN, Money{"USD", -50}},
+			{"1.234,5", "EUR", MoneyLocaleDE, Money{"EUR", 123450}},
+			{"1 234,50", "EUR", MoneyLocaleFR, Money{"EUR", 123450}},
+			{"500", "JPY", MoneyLocaleEN, Money{"JPY", 500}},
+			{"1.500", "KWD", MoneyLocaleEN, Money{"KWD", 1500}},
+			{"12.3400", "USD", MoneyLocaleEN, Money{"USD", 1234}},
+			{".75", "USD", MoneyLocaleEN, Money{"USD", 75}},
+		}
+		for _, c := range cases {
+			got, err := ParseMoney(c.text, c.currency, c.locale)
+			if err != nil || got != c.want {
+				t.Errorf("ParseMoney(%q, %s) = %v, %v; want %v", c.text, c.currency, got, err, c.want)
+			}
+		}
+	})
+
+	t.Run("Parse Rejects", func(t *testing.T) {
+		if _, err := ParseMoney("12.345", "USD", MoneyLocaleEN); !errors.Is(err, ErrPrecisionLoss) {
+			t.Errorf("expected precision loss, got %v", err)
+		}
+		if _, err := ParseMoney("12.5", "JPY", MoneyLocaleEN); !errors.Is(err, ErrPrecisionLoss) {
+			t.Errorf("expected precision loss for JPY, got %v", err)
+		}
+		for _, text := range []string{"", "abc", "1.2.3", ",12", "12.3,4", "99999999999999999999"} {
+			if _, err := ParseMoney(text, "USD", MoneyLocaleEN); !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("ParseMoney(%q): expected ErrInvalidAmount, got %v", text, err)
+			}
+		}
+		if _, err := ParseMoney("1", "dollars", MoneyLocaleEN); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("expected invalid currency, got %v", err)
+		}
+	})
+
+	t.Run("From Float", func(t *testing.T) {
+		m, err := MoneyFromFloat(0.1+0.2, "USD")
+		if err != nil || m != (Money{"USD", 30}) {
+			t.Errorf("expected 30 cents, got %v, %v", m, err)
+		}
+		if _, err := MoneyFromFloat(19.999, "USD"); !errors.Is(err, ErrPrecisionLoss) {
+			t.Errorf("expected precision loss, got %v", err)
+		}
+		if _, err := MoneyFromFloat(math.NaN(), "USD"); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("expected invalid amount, got %v", err)
+		}
+	})
+
+	t.Run("Format", func(t *testing.T) {
+		cases := []struct {
+			money  Money
+			locale MoneyLocale
+			want   string
+		}{
+			{Money{"USD", 123456789}, MoneyLocaleEN, "1,234,567.89"},
+			{Money{"EUR", 123450}, MoneyLocaleDE, "1.234,50"},
+			{Money{"EUR", 123450}, MoneyLocaleFR, "1\u202f234,50"},
+			{Money{"USD", -5}, MoneyLocaleEN, "-0.05"},
+			{Money{"JPY", 1000}, MoneyLocaleEN, "1,000"},
+			{Money{"KWD", 1500}, MoneyLocaleEN, "1.500"},
+		}
+		for _, c := range cases {
+			if got := c.money.Format(c.locale); got != c.want {
+				t.Errorf("%v.Format() = %q; want %q", c.money, got, c.want)
+			}
+		}
+		if got := (Money{"USD", 123450}).String(); got != "1234.50 USD" {
+			t.Errorf("unexpected String() %q", got)
+		}
+		lowest := Money{"USD", math.MinInt64}
+		if back, err := ParseMoney(lowest.Format(MoneyLocaleEN), "USD", MoneyLocaleEN); err != nil || back != lowest {
+			t.Errorf("expected MinInt64 to round trip, got %v, %v", back, err)
+		}
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		sum, err := Money{"USD", 150}.Add(Money{"USD", -50})
+		if err != nil || sum != (Money{"USD", 100}) {
+			t.Errorf("expected 100, got %v, %v", sum, err)
+		}
+		if _, err := (Money{"USD", 1}).Add(Money{"EUR", 1}); !errors.Is(err, ErrCurrencyMismatch) {
+			t.Errorf("expected currency mismatch, got %v", err)
+		}
+		if _, err := (Money{"USD", math.MaxInt64}).Add(Money{"USD", 1}); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("expected overflow, got %v", err)
+		}
+	})
+
+	t.Run("Convert", func(t *testing.T) {
+		yen, err := Money{"USD", 1234}.Convert("JPY", 150.5)
+		if err != nil || yen != (Money{"JPY", 1857}) {
+			t.Errorf("expected 1857 JPY, got %v, %v", yen, err)
+		}
+		dinar, err := Money{"JPY", 1000}.Convert("kwd", 0.002)
+		if err != nil || dinar != (Money{"KWD", 2000}) {
+			t.Errorf("expected 2.000 KWD, got %v, %v", dinar, err)
+		}
+		if _, err := (Money{"USD", 1}).Convert("EUR", math.Inf(1)); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("expected invalid rate, got %v", err)
+		}
+	})
+
+	t.Run("Processors", func(t *testing.T) {
+		type order struct {
+			TotalText string
+			Currency  string
+			Display   string
+			Total     Money
+		}
+		var lookups int
+		rates := RateFunc(func(_ context.Context, from, to string) (float64, error) {
+			lookups++
+			if from == "EUR" && to == "USD" {
+				return 1.1, nil
+			}
+			return 0, errors.New("no rate")
+		})
+		pipeline := NewSequence(testIdentity("checkout"),
+			MoneyParse(testIdentity("parse"), MoneyLocaleDE,
+				func(o order) (string, string) { return o.TotalText, o.Currency },
+				func(o order, m Money) order { o.Total = m; return o }),
+			MoneyConvert(testIdentity("convert"), rates, "USD",
+				func(o order) Money { return o.Total },
+				func(o order, m Money) order { o.Total = m; return o }),
+			MoneyFormat(testIdentity("format"), MoneyLocaleEN,
+				func(o order) Money { return o.Total },
+				func(o order, s string) order { o.Display = s; return o }),
+		)
+
+		out, err := pipeline.Process(context.Background(), order{TotalText: "1.000,00", Currency: "EUR"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Total != (Money{"USD", 110000}) || out.Display != "1,100.00" {
+			t.Errorf("unexpected order %+v", out)
+		}
+
+		out, err = pipeline.Process(context.Background(), order{TotalText: "5,00", Currency: "usd"})
+		if err != nil || lookups != 1 || out.Display != "5.00" {
+			t.Errorf("expected USD to skip the rate lookup, got %+v, %v after %d lookups", out, err, lookups)
+		}
+
+		_, err = pipeline.Process(context.Background(), order{TotalText: "1,005", Currency: "EUR"})
+		var pipeErr *Error[order]
+		if !errors.Is(err, ErrPrecisionLoss) || !errors.As(err, &pipeErr) || pipeErr.Path[len(pipeErr.Path)-1].Name() != "parse" {
+			t.Errorf("expected precision loss at parse, got %v", err)
+		}
+		if _, err := pipeline.Process(context.Background(), order{TotalText: "1", Currency: "GBP"}); err == nil {
+			t.Error("expected missing rate to fail")
+		}
+	})
+}
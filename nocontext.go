@@ -0,0 +1,63 @@
+package pipz
+
+import (
+	"context"
+)
+
+// ApplyNoCtx creates a Processor from a function that takes no context,
+// such as an existing business function of the form func(T) (T, error),
+// so it can join a pipeline unchanged during gradual adoption.
+//
+// Since fn cannot observe cancellation, the processor checks the context
+// before calling it: a context that has already ended fails with its error,
+// marked as a timeout or cancellation, without running fn. Once running, fn
+// runs to completion; a Timeout around it reports the deadline but cannot
+// stop it. Otherwise it behaves exactly like Apply.
+//
+// Example:
+//
+//	// Legacy: func NormalizeAddress(a Address) (Address, error)
+//	var NormalizeID = pipz.NewIdentity("normalize-address", "Normalizes postal addresses")
+//	normalize := pipz.ApplyNoCtx(NormalizeID, NormalizeAddress)
+func ApplyNoCtx[T any](identity Identity, fn func(T) (T, error)) Processor[T] {
+	return Apply(identity, func(ctx context.Context, data T) (T, error) {
+		if err := ctx.Err(); err != nil {
+			return data, err
+		}
+		return fn(data)
+	})
+}
+
+// TransformNoCtx creates a Processor from a pure transformation that takes
+// no context, such as func(T) T. Unlike Transform it fails when the
+// context has already ended, as ApplyNoCtx does, without running fn.
+//
+// Example:
+//
+//	// Legacy: func ApplyTax(o Order) Order
+//	var TaxID = pipz.NewIdentity("apply-tax", "Adds sales tax to the order total")
+//	tax := pipz.TransformNoCtx(TaxID, ApplyTax)
+func TransformNoCtx[T any](identity Identity, fn func(T) T) Processor[T] {
+	return ApplyNoCtx(identity, func(data T) (T, error) {
+		return fn(data), nil
+	})
+}
+
+// EffectNoCtx creates a Processor from a side effect that takes no
+// context, such as func(T) error. Like Effect it passes the data through
+// unchanged and is ReadOnly; like ApplyNoCtx it fails without running fn
+// when the context has already ended.
+//
+// Example:
+//
+//	// Legacy: func ValidateOrder(o Order) error
+//	var ValidateID = pipz.NewIdentity("validate-order", "Rejects malformed orders")
+//	validate := pipz.EffectNoCtx(ValidateID, ValidateOrder)
+func EffectNoCtx[T any](identity Identity, fn func(T) error) Processor[T] {
+	return Effect(identity, func(ctx context.Context, data T) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fn(data)
+	})
+}
@@ -0,0 +1,68 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestNoCtxAdapters(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("ApplyNoCtx", func(t *testing.T) {
+		parse := ApplyNoCtx(testIdentity("parse"), func(s string) (string, error) {
+			n, err := strconv.Atoi(s)
+			return strconv.Itoa(n * 2), err
+		})
+		if out, err := parse.Process(context.Background(), "21"); err != nil || out != "42" {
+			t.Errorf("expected 42, got %q, %v", out, err)
+		}
+		_, err := parse.Process(context.Background(), "x")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "parse" {
+			t.Errorf("expected a pipeline error from the processor, got %v", err)
+		}
+	})
+
+	t.Run("TransformNoCtx", func(t *testing.T) {
+		double := TransformNoCtx(testIdentity("double"), func(n int) int { return n * 2 })
+		if out, err := double.Process(context.Background(), 4); err != nil || out != 8 {
+			t.Errorf("expected 8, got %d, %v", out, err)
+		}
+	})
+
+	t.Run("EffectNoCtx", func(t *testing.T) {
+		var seen int
+		record := EffectNoCtx(testIdentity("record"), func(n int) error {
+			seen = n
+			return nil
+		})
+		if out, err := record.Process(context.Background(), 4); err != nil || out != 4 || seen != 4 {
+			t.Errorf("expected 4 passed through and seen, got %d, %d, %v", out, seen, err)
+		}
+		if !record.ReadOnly() {
+			t.Error("expected EffectNoCtx to be read-only")
+		}
+	})
+
+	t.Run("Ended Context Skips Function", func(t *testing.T) {
+		called := false
+		processors := []Processor[int]{
+			ApplyNoCtx(testIdentity("apply"), func(n int) (int, error) { called = true; return n, nil }),
+			TransformNoCtx(testIdentity("transform"), func(n int) int { called = true; return n }),
+			EffectNoCtx(testIdentity("effect"), func(int) error { called = true; return nil }),
+		}
+		for _, p := range processors {
+			_, err := p.Process(canceled, 1)
+			var pipeErr *Error[int]
+			if !errors.As(err, &pipeErr) || !pipeErr.Canceled || !errors.Is(err, context.Canceled) {
+				t.Errorf("%s: expected a canceled error, got %v", p.Identity().Name(), err)
+			}
+		}
+		if called {
+			t.Error("expected no function to run on an ended context")
+		}
+	})
+}
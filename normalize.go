@@ -0,0 +1,147 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrInvalidAmount is wrapped by errors from ToMinorUnits for amounts that
+// cannot be represented in minor units.
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// Normalizer is one step of a Normalize stage, standardizing a single field
+// of the record. Name identifies the field in errors.
+type Normalizer[T any] struct {
+	Apply func(T) (T, error)
+	Name  string
+}
+
+// NormalizeField returns a Normalizer rewriting the field field points at
+// with fn. The accessor receives a pointer to the record being normalized,
+// such as func(u *User) *string { return &u.Email }.
+func NormalizeField[T, F any](name string, field func(*T) *F, fn func(F) (F, error)) Normalizer[T] {
+	return Normalizer[T]{
+		Name: name,
+		Apply: func(data T) (T, error) {
+			ptr := field(&data)
+			v, err := fn(*ptr)
+			if err != nil {
+				return data, err
+			}
+			*ptr = v
+			return data, nil
+		},
+	}
+}
+
+// NormalizeString returns a Normalizer applying fns in order to a string
+// field, such as strings.TrimSpace followed by strings.ToLower.
+func NormalizeString[T any](name string, field func(*T) *string, fns ...func(string) string) Normalizer[T] {
+	return NormalizeField(name, field, func(s string) (string, error) {
+		for _, fn := range fns {
+			s = fn(s)
+		}
+		return s, nil
+	})
+}
+
+// NormalizeTime returns a Normalizer converting a time field to loc, such
+// as time.UTC. Zero times are left unset.
+func NormalizeTime[T any](name string, field func(*T) *time.Time, loc *time.Location) Normalizer[T] {
+	return NormalizeField(name, field, func(t time.Time) (time.Time, error) {
+		if t.IsZero() {
+			return t, nil
+		}
+		return t.In(loc), nil
+	})
+}
+
+// NormalizeMinorUnits returns a Normalizer storing a decimal amount in the
+// currency's minor units, such as 12.34 USD as 1234 cents, so later stages
+// do integer arithmetic. See ToMinorUnits.
+func NormalizeMinorUnits[T any](name string, amount func(T) float64, currency func(T) string, minor func(*T) *int64) Normalizer[T] {
+	return Normalizer[T]{
+		Name: name,
+		Apply: func(data T) (T, error) {
+			units, err := ToMinorUnits(amount(data), currency(data))
+			if err != nil {
+				return data, err
+			}
+			*minor(&data) = units
+			return data, nil
+		},
+	}
+}
+
+// currencyExponents lists the ISO 4217 currencies whose minor unit is not
+// a hundredth.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// currencyExponent returns the number of minor unit digits of currency,
+// two for codes not in currencyExponents.
+func currencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// ToMinorUnits converts amount in currency, an ISO 4217 code, to the
+// currency's minor units, rounding to the nearest unit: 12.34 USD is 1234,
+// 500 JPY is 500, and 1.5 KWD is 1500. Unknown codes use hundredths. NaN,
+// infinite, and out of range amounts return an error wrapping
+// ErrInvalidAmount.
+func ToMinorUnits(amount float64, currency string) (int64, error) {
+	units := math.Round(amount * math.Pow10(currencyExponent(currency)))
+	if math.IsNaN(units) || units >= math.MaxInt64 || units < math.MinInt64 {
+		return 0, fmt.Errorf("%w: %v %s", ErrInvalidAmount, amount, currency)
+	}
+	return int64(units), nil
+}
+
+// Normalize creates a Processor standardizing records with normalizers,
+// applied in order, so validation stages see one canonical form: trimmed,
+// lowercased identifiers, amounts in minor units, timestamps in UTC. If a
+// normalizer fails, the item fails with an error naming the field.
+//
+// Example:
+//
+//	var NormalizeSignupID = pipz.NewIdentity("normalize-signup", "Canonicalizes signup fields")
+//	normalize := pipz.Normalize(NormalizeSignupID,
+//	    pipz.NormalizeString("email", func(s *Signup) *string { return &s.Email },
+//	        strings.TrimSpace, strings.ToLower),
+//	    pipz.NormalizeTime("created_at", func(s *Signup) *time.Time { return &s.CreatedAt }, time.UTC),
+//	    pipz.NormalizeMinorUnits("deposit",
+//	        func(s Signup) float64 { return s.Deposit },
+//	        func(s Signup) string { return s.Currency },
+//	        func(s *Signup) *int64 { return &s.DepositMinor }),
+//	)
+func Normalize[T any](identity Identity, normalizers ...Normalizer[T]) Processor[T] {
+	return Processor[T]{
+		identity: identity,
+		fn: func(_ context.Context, value T) (result T, err error) {
+			defer recoverFromPanic(&result, &err, identity, value)
+			result = value
+			for _, n := range normalizers {
+				if result, err = n.Apply(result); err != nil {
+					return value, &Error[T]{
+						Timestamp: time.Now(),
+						InputData: errorInput(value),
+						Err:       fmt.Errorf("normalizing %s: %w", n.Name, err),
+						Path:      []Identity{identity},
+					}
+				}
+			}
+			return result, nil
+		},
+	}
+}
@@ -0,0 +1,114 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type signup struct {
+	CreatedAt    time.Time
+	Email        string
+	Currency     string
+	Deposit      float64
+	DepositMinor int64
+}
+
+func TestNormalize(t *testing.T) {
+	normalize := Normalize(testIdentity("normalize"),
+		NormalizeString("email", func(s *signup) *string { return &s.Email }, strings.TrimSpace, strings.ToLower),
+		NormalizeTime("created_at", func(s *signup) *time.Time { return &s.CreatedAt }, time.UTC),
+		NormalizeMinorUnits("deposit",
+			func(s signup) float64 { return s.Deposit },
+			func(s signup) string { return s.Currency },
+			func(s *signup) *int64 { return &s.DepositMinor }),
+	)
+
+	t.Run("Normalizes Fields", func(t *testing.T) {
+		tokyo := time.FixedZone("JST", 9*60*60)
+		created := time.Date(2024, 3, 1, 9, 0, 0, 0, tokyo)
+		result, err := normalize.Process(context.Background(), signup{
+			Email:     "  Ada@Example.COM ",
+			CreatedAt: created,
+			Deposit:   12.345,
+			Currency:  "usd",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Email != "ada@example.com" {
+			t.Errorf("expected normalized email, got %q", result.Email)
+		}
+		if result.CreatedAt.Location() != time.UTC || !result.CreatedAt.Equal(created) {
+			t.Errorf("expected the same instant in UTC, got %v", result.CreatedAt)
+		}
+		if result.DepositMinor != 1235 {
+			t.Errorf("expected 1235 cents, got %d", result.DepositMinor)
+		}
+	})
+
+	t.Run("Leaves Zero Time", func(t *testing.T) {
+		result, err := normalize.Process(context.Background(), signup{Currency: "USD"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.CreatedAt.IsZero() {
+			t.Errorf("expected zero time to stay unset, got %v", result.CreatedAt)
+		}
+	})
+
+	t.Run("Names Failing Field", func(t *testing.T) {
+		input := signup{Email: " A@B.C ", Deposit: math.Inf(1), Currency: "USD"}
+		result, err := normalize.Process(context.Background(), input)
+		if !errors.Is(err, ErrInvalidAmount) || !strings.Contains(err.Error(), "normalizing deposit") {
+			t.Fatalf("expected deposit normalization error, got %v", err)
+		}
+		if result.Email != input.Email {
+			t.Errorf("expected the original input on failure, got %q", result.Email)
+		}
+	})
+
+	t.Run("Custom Field", func(t *testing.T) {
+		reject := Normalize(testIdentity("normalize"),
+			NormalizeField("email", func(s *signup) *string { return &s.Email }, func(s string) (string, error) {
+				if !strings.Contains(s, "@") {
+					return s, errors.New("not an email")
+				}
+				return s, nil
+			}),
+		)
+		if _, err := reject.Process(context.Background(), signup{Email: "nobody"}); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestToMinorUnits(t *testing.T) {
+	tests := []struct {
+		currency string
+		amount   float64
+		want     int64
+	}{
+		{"USD", 12.34, 1234},
+		{"eur", 0.1 + 0.2, 30},
+		{"JPY", 500, 500},
+		{"KWD", 1.5, 1500},
+		{"XXX", 1, 100},
+		{"USD", -3.5, -350},
+	}
+	for _, tt := range tests {
+		got, err := ToMinorUnits(tt.amount, tt.currency)
+		if err != nil || got != tt.want {
+			t.Errorf("ToMinorUnits(%v, %s) = %d, %v; want %d", tt.amount, tt.currency, got, err, tt.want)
+		}
+	}
+	if _, err := ToMinorUnits(math.NaN(), "USD"); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("expected ErrInvalidAmount for NaN, got %v", err)
+	}
+	if _, err := ToMinorUnits(1e20, "USD"); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("expected ErrInvalidAmount for overflow, got %v", err)
+	}
+}
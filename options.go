@@ -0,0 +1,327 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// ErrInvalidOption is returned by option-based constructors when an option
+// carries an invalid value, is not applicable to the connector being built,
+// or a required option is missing.
+var ErrInvalidOption = errors.New("invalid option")
+
+// ErrInvalidMode is returned when parsing a connector mode from a string
+// that names no valid mode.
+var ErrInvalidMode = errors.New("invalid mode")
+
+// optionKey identifies which Config field an option sets so constructors can
+// reject options that do not apply to them.
+type optionKey uint16
+
+const (
+	optFailureThreshold optionKey = 1 << iota
+	optSuccessThreshold
+	optResetTimeout
+	optRate
+	optBurst
+	optMode
+	optMaxAttempts
+	optBaseDelay
+	optDuration
+	optWorkers
+	optTaskTimeout
+	optClock
+)
+
+// optionNames maps option keys to their constructor function names for errors.
+var optionNames = map[optionKey]string{
+	optFailureThreshold: "WithFailureThreshold",
+	optSuccessThreshold: "WithSuccessThreshold",
+	optResetTimeout:     "WithResetTimeout",
+	optRate:             "WithRate",
+	optBurst:            "WithBurst",
+	optMode:             "WithMode",
+	optMaxAttempts:      "WithMaxAttempts",
+	optBaseDelay:        "WithBaseDelay",
+	optDuration:         "WithDuration",
+	optWorkers:          "WithWorkers",
+	optTaskTimeout:      "WithTaskTimeout",
+	optClock:            "WithClock",
+}
+
+// Config is the structured configuration assembled from Options by the
+// option-based constructors (NewCircuitBreakerWithOptions,
+// NewRateLimiterWithOptions, and so on). Each constructor reads only the
+// fields relevant to its connector and rejects options that do not apply.
+type Config struct {
+	Clock            clockz.Clock
+	Mode             RateLimitMode
+	ResetTimeout     time.Duration
+	BaseDelay        time.Duration
+	Duration         time.Duration
+	TaskTimeout      time.Duration
+	Rate             float64
+	FailureThreshold int
+	SuccessThreshold int
+	MaxAttempts      int
+	Burst            int
+	Workers          int
+	set              optionKey
+}
+
+// Option configures a connector built by an option-based constructor.
+// Options validate their argument when applied and return an error wrapping
+// ErrInvalidOption instead of silently clamping bad values.
+type Option func(*Config) error
+
+// Reconfigurable is implemented by connectors whose tuning parameters can be
+// changed while they serve traffic. Reconfigure validates every option before
+// applying any of them, so a rejected update leaves the connector untouched.
+// A config hot-reload loop can hold connectors as Reconfigurable and push
+// updated options without knowing their concrete types.
+//
+// Example:
+//
+//	var tunable = map[string]pipz.Reconfigurable{
+//	    "payments.retry":   paymentsRetry,
+//	    "payments.breaker": paymentsBreaker,
+//	}
+//
+//	func onReload(ctx context.Context, key string, opts ...pipz.Option) error {
+//	    return tunable[key].Reconfigure(ctx, opts...)
+//	}
+type Reconfigurable interface {
+	Reconfigure(ctx context.Context, opts ...Option) error
+}
+
+// newConfig applies opts over defaults, rejecting any option outside allowed
+// and any required option that was not supplied.
+func newConfig(kind string, defaults Config, allowed, required optionKey, opts []Option) (Config, error) {
+	cfg := defaults
+	cfg.set = 0
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		before := cfg.set
+		if err := opt(&cfg); err != nil {
+			return Config{}, fmt.Errorf("%s: %w", kind, err)
+		}
+		if added := cfg.set &^ before; added&^allowed != 0 {
+			return Config{}, fmt.Errorf("%s: %w: %s is not applicable", kind, ErrInvalidOption, optionNames[added&^allowed])
+		}
+	}
+	if missing := required &^ cfg.set; missing != 0 {
+		for key := optionKey(1); key <= optClock; key <<= 1 {
+			if missing&key != 0 {
+				return Config{}, fmt.Errorf("%s: %w: %s is required", kind, ErrInvalidOption, optionNames[key])
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// names returns the comma-separated option names recorded in set.
+func (c *Config) names() string {
+	var names []string
+	for key := optionKey(1); key <= optClock; key <<= 1 {
+		if c.set&key != 0 {
+			names = append(names, optionNames[key])
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// emitReconfigured reports a successful runtime reconfiguration.
+func emitReconfigured(ctx context.Context, identity Identity, cfg Config) {
+	capitan.Info(ctx, SignalReconfigured,
+		FieldName.Field(identity.Name()),
+		FieldIdentityID.Field(identity.ID().String()),
+		FieldOptions.Field(cfg.names()),
+	)
+}
+
+// invalidOption builds an error for an option rejected during validation.
+func invalidOption(key optionKey, format string, args ...any) error {
+	return fmt.Errorf("%w: %s: %s", ErrInvalidOption, optionNames[key], fmt.Sprintf(format, args...))
+}
+
+// parseMode returns the mode among allowed named s, or an error wrapping
+// ErrInvalidMode that lists the allowed names.
+func parseMode[M ~string](kind, s string, allowed ...M) (M, error) {
+	for _, mode := range allowed {
+		if string(mode) == s {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s mode %q: must be one of %s", ErrInvalidMode, kind, s, modeNames(allowed))
+}
+
+// modeNames lists modes as quoted, comma-separated names for errors.
+func modeNames[M ~string](modes []M) string {
+	names := make([]string, len(modes))
+	for i, mode := range modes {
+		names[i] = fmt.Sprintf("%q", mode)
+	}
+	return strings.Join(names, ", ")
+}
+
+// WithFailureThreshold sets how many consecutive failures open a CircuitBreaker.
+// The threshold must be at least 1.
+func WithFailureThreshold(n int) Option {
+	return func(c *Config) error {
+		if n < 1 {
+			return invalidOption(optFailureThreshold, "must be at least 1, got %d", n)
+		}
+		c.FailureThreshold = n
+		c.set |= optFailureThreshold
+		return nil
+	}
+}
+
+// WithSuccessThreshold sets how many successes close a half-open CircuitBreaker.
+// The threshold must be at least 1.
+func WithSuccessThreshold(n int) Option {
+	return func(c *Config) error {
+		if n < 1 {
+			return invalidOption(optSuccessThreshold, "must be at least 1, got %d", n)
+		}
+		c.SuccessThreshold = n
+		c.set |= optSuccessThreshold
+		return nil
+	}
+}
+
+// WithResetTimeout sets how long an open CircuitBreaker waits before probing
+// for recovery. The timeout must be positive.
+func WithResetTimeout(d time.Duration) Option {
+	return func(c *Config) error {
+		if d <= 0 {
+			return invalidOption(optResetTimeout, "must be positive, got %s", d)
+		}
+		c.ResetTimeout = d
+		c.set |= optResetTimeout
+		return nil
+	}
+}
+
+// WithRate sets the sustained RateLimiter rate in requests per second.
+// The rate must be positive and not NaN; math.Inf(1) disables limiting.
+func WithRate(perSecond float64) Option {
+	return func(c *Config) error {
+		if math.IsNaN(perSecond) || perSecond <= 0 {
+			return invalidOption(optRate, "must be positive, got %v", perSecond)
+		}
+		c.Rate = perSecond
+		c.set |= optRate
+		return nil
+	}
+}
+
+// WithBurst sets the RateLimiter burst capacity. The burst must be at least 1.
+func WithBurst(n int) Option {
+	return func(c *Config) error {
+		if n < 1 {
+			return invalidOption(optBurst, "must be at least 1, got %d", n)
+		}
+		c.Burst = n
+		c.set |= optBurst
+		return nil
+	}
+}
+
+// WithMode sets the RateLimiter mode, either RateLimitWait or RateLimitDrop.
+func WithMode(mode RateLimitMode) Option {
+	return func(c *Config) error {
+		if !slices.Contains(rateLimitModes, mode) {
+			return invalidOption(optMode, "must be one of %s, got %q", modeNames(rateLimitModes), mode)
+		}
+		c.Mode = mode
+		c.set |= optMode
+		return nil
+	}
+}
+
+// WithMaxAttempts sets the total attempts made by Retry or Backoff.
+// The count must be at least 1.
+func WithMaxAttempts(n int) Option {
+	return func(c *Config) error {
+		if n < 1 {
+			return invalidOption(optMaxAttempts, "must be at least 1, got %d", n)
+		}
+		c.MaxAttempts = n
+		c.set |= optMaxAttempts
+		return nil
+	}
+}
+
+// WithBaseDelay sets the initial Backoff delay, doubled after each failure.
+// The delay must not be negative.
+func WithBaseDelay(d time.Duration) Option {
+	return func(c *Config) error {
+		if d < 0 {
+			return invalidOption(optBaseDelay, "must not be negative, got %s", d)
+		}
+		c.BaseDelay = d
+		c.set |= optBaseDelay
+		return nil
+	}
+}
+
+// WithDuration sets the Timeout deadline. The duration must be positive.
+func WithDuration(d time.Duration) Option {
+	return func(c *Config) error {
+		if d <= 0 {
+			return invalidOption(optDuration, "must be positive, got %s", d)
+		}
+		c.Duration = d
+		c.set |= optDuration
+		return nil
+	}
+}
+
+// WithWorkers sets the WorkerPool concurrency limit. The count must be at least 1.
+func WithWorkers(n int) Option {
+	return func(c *Config) error {
+		if n < 1 {
+			return invalidOption(optWorkers, "must be at least 1, got %d", n)
+		}
+		c.Workers = n
+		c.set |= optWorkers
+		return nil
+	}
+}
+
+// WithTaskTimeout sets the per-task WorkerPool timeout. Zero disables it;
+// negative values are rejected.
+func WithTaskTimeout(d time.Duration) Option {
+	return func(c *Config) error {
+		if d < 0 {
+			return invalidOption(optTaskTimeout, "must not be negative, got %s", d)
+		}
+		c.TaskTimeout = d
+		c.set |= optTaskTimeout
+		return nil
+	}
+}
+
+// WithClock sets the clock used by time-dependent connectors, typically a
+// fake clock in tests. The clock must not be nil.
+func WithClock(clock clockz.Clock) Option {
+	return func(c *Config) error {
+		if clock == nil {
+			return invalidOption(optClock, "must not be nil")
+		}
+		c.Clock = clock
+		c.set |= optClock
+		return nil
+	}
+}
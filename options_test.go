@@ -0,0 +1,242 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+func TestOptionValidation(t *testing.T) {
+	cases := map[string]Option{
+		"WithFailureThreshold": WithFailureThreshold(0),
+		"WithSuccessThreshold": WithSuccessThreshold(-1),
+		"WithResetTimeout":     WithResetTimeout(0),
+		"WithRate":             WithRate(math.NaN()),
+		"WithBurst":            WithBurst(0),
+		"WithMode":             WithMode("block"),
+		"WithMaxAttempts":      WithMaxAttempts(0),
+		"WithBaseDelay":        WithBaseDelay(-time.Second),
+		"WithDuration":         WithDuration(-time.Second),
+		"WithWorkers":          WithWorkers(0),
+		"WithTaskTimeout":      WithTaskTimeout(-time.Second),
+		"WithClock":            WithClock(nil),
+	}
+	for name, opt := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := opt(&Config{})
+			if !errors.Is(err, ErrInvalidOption) {
+				t.Fatalf("expected ErrInvalidOption, got %v", err)
+			}
+			if !strings.Contains(err.Error(), name) {
+				t.Errorf("expected error to name %s, got %q", name, err.Error())
+			}
+		})
+	}
+}
+
+func TestParseModes(t *testing.T) {
+	t.Run("Valid Names", func(t *testing.T) {
+		if mode, err := ParseRateLimitMode("drop"); err != nil || mode != RateLimitDrop {
+			t.Errorf("expected RateLimitDrop, got %q (%v)", mode, err)
+		}
+		if mode, err := ParseReadyMode("fail"); err != nil || mode != ReadyFail {
+			t.Errorf("expected ReadyFail, got %q (%v)", mode, err)
+		}
+		if mode, err := ParseConsentMode("anonymize"); err != nil || mode != ConsentAnonymize {
+			t.Errorf("expected ConsentAnonymize, got %q (%v)", mode, err)
+		}
+		if action, err := ParsePIIAction("mask"); err != nil || action != PIIMask {
+			t.Errorf("expected PIIMask, got %q (%v)", action, err)
+		}
+	})
+
+	t.Run("Invalid Name Lists Allowed Values", func(t *testing.T) {
+		_, err := ParseRateLimitMode("wiat")
+		if !errors.Is(err, ErrInvalidMode) {
+			t.Fatalf("expected ErrInvalidMode, got %v", err)
+		}
+		if want := `invalid mode: rate limiter mode "wiat": must be one of "wait", "drop"`; err.Error() != want {
+			t.Errorf("expected %q, got %q", want, err.Error())
+		}
+		if _, err := ParseReadyMode("WAIT"); !errors.Is(err, ErrInvalidMode) {
+			t.Errorf("expected names to be case-sensitive, got %v", err)
+		}
+	})
+
+	t.Run("WithMode Lists Allowed Values", func(t *testing.T) {
+		err := WithMode("block")(&Config{})
+		if err == nil || !strings.Contains(err.Error(), `must be one of "wait", "drop", got "block"`) {
+			t.Errorf("expected allowed values in error, got %v", err)
+		}
+	})
+}
+
+func TestOptionConstructors(t *testing.T) {
+	proc := Transform(NewIdentity("noop", ""), func(_ context.Context, v int) int { return v })
+
+	t.Run("CircuitBreaker Defaults And Overrides", func(t *testing.T) {
+		cb, err := NewCircuitBreakerWithOptions(NewIdentity("cb", ""), proc)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cb.GetFailureThreshold() != 5 || cb.GetSuccessThreshold() != 1 || cb.GetResetTimeout() != 30*time.Second {
+			t.Errorf("unexpected defaults: %d %d %s", cb.GetFailureThreshold(), cb.GetSuccessThreshold(), cb.GetResetTimeout())
+		}
+
+		clock := clockz.NewFakeClock()
+		cb, err = NewCircuitBreakerWithOptions(NewIdentity("cb", ""), proc,
+			WithFailureThreshold(2),
+			WithSuccessThreshold(3),
+			WithResetTimeout(time.Minute),
+			WithClock(clock),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cb.GetFailureThreshold() != 2 || cb.GetSuccessThreshold() != 3 || cb.GetResetTimeout() != time.Minute {
+			t.Errorf("options not applied: %d %d %s", cb.GetFailureThreshold(), cb.GetSuccessThreshold(), cb.GetResetTimeout())
+		}
+		if cb.getClock() != clock {
+			t.Error("expected clock to be applied")
+		}
+	})
+
+	t.Run("RateLimiter Requires Rate", func(t *testing.T) {
+		_, err := NewRateLimiterWithOptions(NewIdentity("rl", ""), proc, WithBurst(5))
+		if !errors.Is(err, ErrInvalidOption) || !strings.Contains(err.Error(), "WithRate is required") {
+			t.Fatalf("expected missing rate error, got %v", err)
+		}
+
+		rl, err := NewRateLimiterWithOptions(NewIdentity("rl", ""), proc, WithRate(50), WithBurst(5), WithMode("drop"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rl.GetRate() != 50 || rl.GetBurst() != 5 || rl.GetMode() != "drop" {
+			t.Errorf("options not applied: %v %d %s", rl.GetRate(), rl.GetBurst(), rl.GetMode())
+		}
+	})
+
+	t.Run("Rejects Inapplicable Option", func(t *testing.T) {
+		_, err := NewRetryWithOptions(NewIdentity("retry", ""), proc, WithMode("wait"))
+		if !errors.Is(err, ErrInvalidOption) || !strings.Contains(err.Error(), "WithMode is not applicable") {
+			t.Fatalf("expected inapplicable option error, got %v", err)
+		}
+	})
+
+	t.Run("Propagates Option Error", func(t *testing.T) {
+		_, err := NewBackoffWithOptions(NewIdentity("backoff", ""), proc, WithMaxAttempts(0))
+		if !errors.Is(err, ErrInvalidOption) || !strings.HasPrefix(err.Error(), "backoff:") {
+			t.Fatalf("expected prefixed option error, got %v", err)
+		}
+	})
+
+	t.Run("Retry And Backoff", func(t *testing.T) {
+		r, err := NewRetryWithOptions(NewIdentity("retry", ""), proc, WithMaxAttempts(7))
+		if err != nil || r.GetMaxAttempts() != 7 {
+			t.Fatalf("unexpected retry: %v %v", r, err)
+		}
+		b, err := NewBackoffWithOptions(NewIdentity("backoff", ""), proc, WithBaseDelay(time.Second))
+		if err != nil || b.GetMaxAttempts() != 3 || b.GetBaseDelay() != time.Second {
+			t.Fatalf("unexpected backoff: %v %v", b, err)
+		}
+	})
+
+	t.Run("Timeout Requires Duration", func(t *testing.T) {
+		if _, err := NewTimeoutWithOptions(NewIdentity("timeout", ""), proc); !errors.Is(err, ErrInvalidOption) {
+			t.Fatalf("expected missing duration error, got %v", err)
+		}
+		to, err := NewTimeoutWithOptions(NewIdentity("timeout", ""), proc, WithDuration(time.Second))
+		if err != nil || to.GetDuration() != time.Second {
+			t.Fatalf("unexpected timeout: %v %v", to, err)
+		}
+	})
+
+	t.Run("WorkerPool", func(t *testing.T) {
+		wp, err := NewWorkerPoolWithOptions(NewIdentity("pool", ""), []Chainable[TestData]{},
+			WithWorkers(4), WithTaskTimeout(time.Second))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if wp.GetWorkerCount() != 4 {
+			t.Errorf("expected 4 workers, got %d", wp.GetWorkerCount())
+		}
+	})
+}
+
+func TestReconfigure(t *testing.T) {
+	proc := Transform(NewIdentity("noop", ""), func(_ context.Context, v int) int { return v })
+	ctx := context.Background()
+
+	t.Run("CircuitBreaker Preserves Unspecified", func(t *testing.T) {
+		cb := NewCircuitBreaker(NewIdentity("cb", ""), proc, 5, time.Second)
+		if err := cb.Reconfigure(ctx, WithFailureThreshold(2)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cb.GetFailureThreshold() != 2 || cb.GetResetTimeout() != time.Second {
+			t.Errorf("unexpected config: %d %s", cb.GetFailureThreshold(), cb.GetResetTimeout())
+		}
+	})
+
+	t.Run("Rejected Update Is Atomic", func(t *testing.T) {
+		b := NewBackoff(NewIdentity("backoff", ""), proc, 3, time.Second)
+		err := b.Reconfigure(ctx, WithMaxAttempts(9), WithBaseDelay(-time.Second))
+		if !errors.Is(err, ErrInvalidOption) {
+			t.Fatalf("expected ErrInvalidOption, got %v", err)
+		}
+		if b.GetMaxAttempts() != 3 || b.GetBaseDelay() != time.Second {
+			t.Errorf("expected no partial update, got %d %s", b.GetMaxAttempts(), b.GetBaseDelay())
+		}
+	})
+
+	t.Run("Rejects Construction-Only Options", func(t *testing.T) {
+		to := NewTimeout(NewIdentity("timeout", ""), proc, time.Second)
+		if err := to.Reconfigure(ctx, WithClock(clockz.NewFakeClock())); !errors.Is(err, ErrInvalidOption) {
+			t.Fatalf("expected ErrInvalidOption, got %v", err)
+		}
+	})
+
+	t.Run("Via Interface", func(t *testing.T) {
+		targets := []Reconfigurable{
+			NewRetry(NewIdentity("retry", ""), proc, 1),
+			NewBackoff(NewIdentity("backoff", ""), proc, 1, time.Millisecond),
+		}
+		for _, target := range targets {
+			if err := target.Reconfigure(ctx, WithMaxAttempts(4)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if got := targets[0].(*Retry[int]).GetMaxAttempts(); got != 4 {
+			t.Errorf("expected 4 attempts, got %d", got)
+		}
+	})
+
+	t.Run("Emits Reconfigured Signal", func(t *testing.T) {
+		var name, options string
+		listener := capitan.Hook(SignalReconfigured, func(_ context.Context, e *capitan.Event) {
+			name, _ = FieldName.From(e)
+			options, _ = FieldOptions.From(e)
+		})
+		defer listener.Close()
+
+		to := NewTimeout(NewIdentity("tuned", ""), proc, time.Second)
+		if err := to.Reconfigure(ctx, WithDuration(5*time.Second)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if name != "tuned" || options != "WithDuration" {
+			t.Errorf("unexpected signal fields: %q %q", name, options)
+		}
+		if to.GetDuration() != 5*time.Second {
+			t.Errorf("expected 5s, got %s", to.GetDuration())
+		}
+	})
+}
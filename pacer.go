@@ -0,0 +1,254 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// ErrPacerQueueFull is returned by a Pacer when the number of items waiting
+// for a slot has reached its maximum queue depth.
+var ErrPacerQueueFull = errors.New("pacer queue full")
+
+// Pacer spaces executions of a processor evenly, at most one per interval,
+// like a leaky bucket. Where RateLimiter lets a burst through up to its
+// token capacity and then throttles or drops, Pacer never bursts: items
+// arriving together are queued and released one interval apart, smoothing
+// the traffic that reaches providers that penalize spikes.
+//
+// Waiting honors the context: an item whose context ends while queued fails
+// with the context's error and gives its slot back if no later item holds
+// one. SetMaxQueue bounds the number of queued items; beyond it items fail
+// immediately with ErrPacerQueueFull instead of waiting.
+//
+// CRITICAL: Pacer is STATEFUL - it tracks the next free slot. Create it
+// once and reuse it.
+//
+// Example:
+//
+//	var GeocodeID = pipz.NewIdentity("geocode-pacer", "Paces geocoding calls to 5/s")
+//	var geocode = pipz.NewPacer(GeocodeID, 200*time.Millisecond,
+//	    pipz.Apply(LookupID, lookupAddress),
+//	).SetMaxQueue(50)
+type Pacer[T any] struct {
+	processor Chainable[T]
+	next      time.Time
+	clock     clockz.Clock
+	identity  Identity
+	interval  time.Duration
+	maxQueue  int
+	queued    int
+	mu        sync.Mutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewPacer creates a Pacer releasing at most one item per interval to
+// processor, with an unbounded queue.
+func NewPacer[T any](identity Identity, interval time.Duration, processor Chainable[T]) *Pacer[T] {
+	return &Pacer[T]{
+		identity:  identity,
+		interval:  interval,
+		processor: processor,
+	}
+}
+
+// Process implements the Chainable interface.
+// Waits for the item's slot, then runs the processor.
+func (p *Pacer[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, p.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, p, p.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	p.mu.Lock()
+	clock := p.getClock()
+	processor := p.processor
+	now := clock.Now()
+	if p.maxQueue > 0 && p.queued >= p.maxQueue && p.next.After(now) {
+		queued := p.queued
+		p.mu.Unlock()
+		capitan.Error(ctx, SignalPacerRejected,
+			FieldName.Field(p.identity.Name()),
+			FieldIdentityID.Field(p.identity.ID().String()),
+			FieldQueued.Field(queued),
+		)
+		return data, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       fmt.Errorf("%w: %d items waiting", ErrPacerQueueFull, queued),
+			Path:      []Identity{p.identity},
+		}
+	}
+	slot := p.next
+	if slot.Before(now) {
+		slot = now
+	}
+	if p.interval > 0 {
+		p.next = slot.Add(p.interval)
+	}
+	wait := slot.Sub(now)
+	if wait > 0 {
+		p.queued++
+	}
+	queued := p.queued
+	p.mu.Unlock()
+
+	if wait > 0 {
+		capitan.Info(ctx, SignalPacerDelayed,
+			FieldName.Field(p.identity.Name()),
+			FieldIdentityID.Field(p.identity.ID().String()),
+			FieldWaitTime.Field(wait.Seconds()),
+			FieldQueued.Field(queued),
+		)
+		select {
+		case <-clock.After(wait):
+			p.mu.Lock()
+			p.queued--
+			p.mu.Unlock()
+		case <-ctx.Done():
+			p.release(slot)
+			return data, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(data),
+				Err:       ctx.Err(),
+				Path:      []Identity{p.identity},
+				Duration:  clock.Since(now),
+				Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
+				Canceled:  errors.Is(ctx.Err(), context.Canceled),
+			}
+		}
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, p.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{p.identity},
+		}
+	}
+	return result, nil
+}
+
+// release dequeues an item that gave up waiting for slot, freeing the slot
+// if it is the latest one reserved.
+func (p *Pacer[T]) release(slot time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queued--
+	if p.next.Equal(slot.Add(p.interval)) {
+		p.next = slot
+	}
+}
+
+// SetInterval updates the spacing between executions. Slots already
+// reserved are kept. Zero or negative disables pacing.
+func (p *Pacer[T]) SetInterval(interval time.Duration) *Pacer[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.interval = interval
+	return p
+}
+
+// SetMaxQueue bounds how many items may wait for a slot. Zero or negative
+// means unbounded.
+func (p *Pacer[T]) SetMaxQueue(n int) *Pacer[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxQueue = n
+	return p
+}
+
+// SetProcessor updates the paced processor.
+func (p *Pacer[T]) SetProcessor(processor Chainable[T]) *Pacer[T] {
+	noteChildren(p.identity, processor)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.processor = processor
+	return p
+}
+
+// GetInterval returns the spacing between executions.
+func (p *Pacer[T]) GetInterval() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.interval
+}
+
+// GetMaxQueue returns the maximum queue depth, or zero if unbounded.
+func (p *Pacer[T]) GetMaxQueue() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.maxQueue
+}
+
+// Queued returns the number of items currently waiting for a slot.
+func (p *Pacer[T]) Queued() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.queued
+}
+
+// WithClock sets a custom clock for testing.
+func (p *Pacer[T]) WithClock(clock clockz.Clock) *Pacer[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clock = clock
+	return p
+}
+
+// getClock returns the clock to use.
+func (p *Pacer[T]) getClock() clockz.Clock {
+	if p.clock == nil {
+		return clockz.RealClock
+	}
+	return p.clock
+}
+
+// Identity returns the identity of this connector.
+func (p *Pacer[T]) Identity() Identity {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (p *Pacer[T]) Schema() Node {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return Node{
+		Identity: p.identity,
+		Type:     "pacer",
+		Flow:     PacerFlow{Processor: p.processor.Schema()},
+		Metadata: map[string]any{
+			"interval":  p.interval.String(),
+			"max_queue": p.maxQueue,
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (p *Pacer[T]) Close() error {
+	p.closeOnce.Do(func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.closeErr = p.processor.Close()
+	})
+	return p.closeErr
+}
@@ -0,0 +1,192 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+// countingClock is a FakeClock counting the timers registered with After,
+// so tests can advance it once every queued item is waiting.
+type countingClock struct {
+	*clockz.FakeClock
+	timers atomic.Int32
+}
+
+// After implements clockz.Clock.
+func (c *countingClock) After(d time.Duration) <-chan time.Time {
+	ch := c.FakeClock.After(d)
+	c.timers.Add(1)
+	return ch
+}
+
+// waitTimers polls until n timers have been registered on clock.
+func waitTimers(t *testing.T, clock *countingClock, n int32) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for clock.timers.Load() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d timers, got %d", n, clock.timers.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitQueued polls until the pacer has n items waiting.
+func waitQueued[T any](t *testing.T, p *Pacer[T], n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for p.Queued() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d queued items, got %d", n, p.Queued())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPacer(t *testing.T) {
+	t.Run("Spaces Burst Evenly", func(t *testing.T) {
+		clock := &countingClock{FakeClock: clockz.NewFakeClock()}
+		start := clock.Now()
+		var mu sync.Mutex
+		var offsets []time.Duration
+		record := Effect(NewIdentity("call", ""), func(context.Context, int) error {
+			mu.Lock()
+			defer mu.Unlock()
+			offsets = append(offsets, clock.Now().Sub(start))
+			return nil
+		})
+		pacer := NewPacer(NewIdentity("pacer", ""), 100*time.Millisecond, record).WithClock(clock)
+
+		var wg sync.WaitGroup
+		for i := range 3 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := pacer.Process(context.Background(), i); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}()
+		}
+		waitTimers(t, clock, 2)
+		clock.Advance(100 * time.Millisecond)
+		clock.BlockUntilReady()
+		waitQueued(t, pacer, 1)
+		clock.Advance(100 * time.Millisecond)
+		clock.BlockUntilReady()
+		wg.Wait()
+
+		if len(offsets) != 3 || offsets[0] != 0 || offsets[1] != 100*time.Millisecond || offsets[2] != 200*time.Millisecond {
+			t.Errorf("expected executions at 0, 100ms, 200ms, got %v", offsets)
+		}
+	})
+
+	t.Run("Idle Pacer Runs Immediately", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		noop := Transform(NewIdentity("noop", ""), func(_ context.Context, v int) int { return v })
+		pacer := NewPacer(NewIdentity("pacer", ""), 100*time.Millisecond, noop).WithClock(clock)
+
+		if _, err := pacer.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		clock.Advance(time.Second)
+		if _, err := pacer.Process(context.Background(), 2); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pacer.Queued() != 0 {
+			t.Errorf("expected no queued items, got %d", pacer.Queued())
+		}
+	})
+
+	t.Run("Full Queue Rejects", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		noop := Transform(NewIdentity("noop", ""), func(_ context.Context, v int) int { return v })
+		pacer := NewPacer(NewIdentity("pacer", ""), 100*time.Millisecond, noop).WithClock(clock).SetMaxQueue(1)
+
+		if _, err := pacer.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		done := make(chan error, 1)
+		go func() {
+			_, err := pacer.Process(context.Background(), 2)
+			done <- err
+		}()
+		waitQueued(t, pacer, 1)
+
+		_, err := pacer.Process(context.Background(), 3)
+		if !errors.Is(err, ErrPacerQueueFull) {
+			t.Fatalf("expected ErrPacerQueueFull, got %v", err)
+		}
+		advanceWhenWaiting(t, clock, 100*time.Millisecond)
+		if err := <-done; err != nil {
+			t.Errorf("queued item failed: %v", err)
+		}
+	})
+
+	t.Run("Cancellation Releases Slot", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		start := clock.Now()
+		noop := Transform(NewIdentity("noop", ""), func(_ context.Context, v int) int { return v })
+		pacer := NewPacer(NewIdentity("pacer", ""), 100*time.Millisecond, noop).WithClock(clock)
+
+		if _, err := pacer.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			_, err := pacer.Process(ctx, 2)
+			done <- err
+		}()
+		waitQueued(t, pacer, 1)
+		cancel()
+
+		err := <-done
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.Canceled {
+			t.Fatalf("expected canceled error, got %v", err)
+		}
+		if pacer.Queued() != 0 {
+			t.Errorf("expected empty queue, got %d", pacer.Queued())
+		}
+		pacer.mu.Lock()
+		next := pacer.next
+		pacer.mu.Unlock()
+		if !next.Equal(start.Add(100 * time.Millisecond)) {
+			t.Errorf("expected the canceled slot to be released, next slot at %v", next.Sub(start))
+		}
+	})
+
+	t.Run("Error Path", func(t *testing.T) {
+		boom := errors.New("boom")
+		fail := Apply(NewIdentity("call", ""), func(_ context.Context, v int) (int, error) { return v, boom })
+		pacer := NewPacer(NewIdentity("pacer", ""), time.Millisecond, fail)
+
+		_, err := pacer.Process(context.Background(), 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "pacer" {
+			t.Fatalf("expected path pacer -> call, got %v", err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		noop := Transform(NewIdentity("noop", ""), func(_ context.Context, v int) int { return v })
+		pacer := NewPacer(NewIdentity("pacer", ""), 200*time.Millisecond, noop).SetMaxQueue(5)
+
+		node := pacer.Schema()
+		if _, ok := PacerKey.From(node); !ok {
+			t.Fatalf("expected PacerFlow, got %T", node.Flow)
+		}
+		if node.Metadata["interval"] != "200ms" || node.Metadata["max_queue"] != 5 {
+			t.Errorf("unexpected metadata: %v", node.Metadata)
+		}
+		if pacer.GetInterval() != 200*time.Millisecond || pacer.GetMaxQueue() != 5 {
+			t.Errorf("unexpected getters: %v, %d", pacer.GetInterval(), pacer.GetMaxQueue())
+		}
+	})
+}
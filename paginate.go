@@ -0,0 +1,214 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// Paginate reasons reported by SignalPaginateCompleted.
+const (
+	paginateExhausted = "exhausted"
+	paginateMaxPages  = "max_pages"
+)
+
+// Paginate drives a chunked upstream fetch: the "sync all records from API
+// X" job. Starting from the page request it is given, Paginate fetches a
+// page, feeds each of its items to a per-item pipeline in order, and asks
+// next for the following page request, typically by copying the cursor out
+// of the page just fetched. It stops when next reports no more pages or the
+// page budget set with SetMaxPages is spent, and returns the last page
+// fetched, so the caller can persist its cursor and resume later.
+//
+// The first error from a page fetch or an item fails the run; wrap the item
+// pipeline in Handle or Fallback to tolerate bad records. SetPageRetries
+// retries failed fetches with backoff before giving up.
+//
+// Example:
+//
+//	var SyncContactsID = pipz.NewIdentity("sync-contacts", "Syncs all contacts from the CRM")
+//	sync := pipz.NewPaginate(SyncContactsID, fetchContactsPage,
+//	    func(p ContactPage) []Contact { return p.Contacts },
+//	    func(p ContactPage) (ContactPage, bool) {
+//	        return ContactPage{Cursor: p.NextCursor}, p.NextCursor != ""
+//	    },
+//	    upsertContact,
+//	).SetPageRetries(3, time.Second)
+//	last, err := sync.Process(ctx, ContactPage{Cursor: savedCursor})
+type Paginate[P, I any] struct {
+	fetch     Chainable[P]
+	retried   Chainable[P]
+	items     func(P) []I
+	next      func(P) (P, bool)
+	process   Chainable[I]
+	identity  Identity
+	maxPages  int
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewPaginate creates a Paginate fetching pages with fetch, extracting
+// their items with items, and processing each item with process. next
+// returns the request for the page after the one given, and false once
+// there are no more pages.
+func NewPaginate[P, I any](identity Identity, fetch Chainable[P], items func(P) []I, next func(P) (P, bool), process Chainable[I]) *Paginate[P, I] {
+	return &Paginate[P, I]{
+		identity: identity,
+		fetch:    fetch,
+		items:    items,
+		next:     next,
+		process:  process,
+	}
+}
+
+// Process implements the Chainable interface, returning the last page
+// fetched.
+func (p *Paginate[P, I]) Process(ctx context.Context, request P) (result P, err error) {
+	defer recoverFromPanic(&result, &err, p.identity, request)
+
+	ctx, guardErr := enterDepth(ctx, p, p.identity, request)
+	if guardErr != nil {
+		return request, guardErr
+	}
+
+	p.mu.RLock()
+	fetch := p.fetch
+	if p.retried != nil {
+		fetch = p.retried
+	}
+	items := p.items
+	next := p.next
+	process := p.process
+	maxPages := p.maxPages
+	p.mu.RUnlock()
+
+	start := time.Now()
+	pages, processed := 0, 0
+	reason := paginateExhausted
+	for {
+		page, err := fetch.Process(ctx, request)
+		if err != nil {
+			return request, p.wrapErr(ctx, request, err)
+		}
+		pages++
+
+		for _, item := range items(page) {
+			if _, err := process.Process(ctx, item); err != nil && !IsSkip(err) {
+				return page, p.wrapErr(ctx, page, err)
+			}
+			processed++
+		}
+
+		var more bool
+		result = page
+		request, more = next(page)
+		if !more {
+			break
+		}
+		if maxPages > 0 && pages >= maxPages {
+			reason = paginateMaxPages
+			break
+		}
+	}
+
+	capitan.Info(ctx, SignalPaginateCompleted,
+		FieldName.Field(p.identity.Name()),
+		FieldIdentityID.Field(p.identity.ID().String()),
+		FieldPages.Field(pages),
+		FieldItems.Field(processed),
+		FieldReason.Field(reason),
+		FieldDuration.Field(time.Since(start).Seconds()),
+	)
+	return result, nil
+}
+
+// wrapErr attributes err to this connector, keeping data as the page being
+// worked on when it failed.
+func (p *Paginate[P, I]) wrapErr(ctx context.Context, data P, err error) error {
+	if isControl(err) {
+		return err
+	}
+	var pageErr *Error[P]
+	if errors.As(err, &pageErr) {
+		pageErr.prependPath(ctx, p.identity)
+		return pageErr
+	}
+	return &Error[P]{
+		Timestamp: time.Now(),
+		InputData: errorInput(data),
+		Err:       err,
+		Path:      []Identity{p.identity},
+	}
+}
+
+// SetMaxPages limits how many pages one run fetches. The run then returns
+// the last page fetched without error, so the caller can resume from its
+// cursor. Zero or less means no limit.
+func (p *Paginate[P, I]) SetMaxPages(n int) *Paginate[P, I] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxPages = max(n, 0)
+	return p
+}
+
+// SetPageRetries retries a failed page fetch with exponential backoff,
+// making up to attempts attempts per page starting baseDelay apart.
+// Attempts of one or less disable retries.
+func (p *Paginate[P, I]) SetPageRetries(attempts int, baseDelay time.Duration) *Paginate[P, I] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if attempts <= 1 {
+		p.retried = nil
+		return p
+	}
+	retryID := NewIdentity(p.identity.Name()+"-page-retry", "Retries failed page fetches")
+	p.retried = NewBackoff(retryID, p.fetch, attempts, baseDelay)
+	return p
+}
+
+// GetMaxPages returns the page limit per run, zero meaning no limit.
+func (p *Paginate[P, I]) GetMaxPages() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.maxPages
+}
+
+// Identity returns the identity of this connector.
+func (p *Paginate[P, I]) Identity() Identity {
+	return p.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (p *Paginate[P, I]) Schema() Node {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	fetch := p.fetch
+	if p.retried != nil {
+		fetch = p.retried
+	}
+	return Node{
+		Identity: p.identity,
+		Type:     "paginate",
+		Flow:     PaginateFlow{Fetch: fetch.Schema(), Process: p.process.Schema()},
+		Metadata: map[string]any{
+			"max_pages": p.maxPages,
+		},
+	}
+}
+
+// Close gracefully shuts down the connector, its fetch processor, and its
+// item pipeline.
+// Close is idempotent - multiple calls return the same result.
+func (p *Paginate[P, I]) Close() error {
+	p.closeOnce.Do(func() {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		p.closeErr = errors.Join(p.process.Close(), p.fetch.Close())
+	})
+	return p.closeErr
+}
@@ -0,0 +1,132 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testPage is a page of an upstream listing, addressed by cursor.
+type testPage struct {
+	Items  []int
+	Cursor int
+	Next   int
+}
+
+func TestPaginate(t *testing.T) {
+	// listing serves pages of two items from a list of total items,
+	// failing the first failures fetches.
+	listing := func(total int, failures int32, fetches *atomic.Int32) Chainable[testPage] {
+		return Apply(testIdentity("fetch"), func(_ context.Context, p testPage) (testPage, error) {
+			if fetches.Add(1) <= failures {
+				return p, errors.New("upstream unavailable")
+			}
+			end := min(p.Cursor+2, total)
+			for i := p.Cursor; i < end; i++ {
+				p.Items = append(p.Items, i)
+			}
+			if end < total {
+				p.Next = end
+			}
+			return p, nil
+		})
+	}
+	items := func(p testPage) []int { return p.Items }
+	next := func(p testPage) (testPage, bool) { return testPage{Cursor: p.Next}, p.Next != 0 }
+	collect := func(seen *[]int) Chainable[int] {
+		return Effect(testIdentity("store"), func(_ context.Context, n int) error {
+			*seen = append(*seen, n)
+			return nil
+		})
+	}
+
+	t.Run("Processes Every Page", func(t *testing.T) {
+		var fetches atomic.Int32
+		var seen []int
+		paginate := NewPaginate(testIdentity("sync"), listing(5, 0, &fetches), items, next, collect(&seen))
+		last, err := paginate.Process(context.Background(), testPage{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(seen) != 5 || seen[4] != 4 || fetches.Load() != 3 {
+			t.Errorf("expected 5 items from 3 pages, got %v from %d", seen, fetches.Load())
+		}
+		if last.Cursor != 4 || last.Next != 0 {
+			t.Errorf("expected the last page, got %+v", last)
+		}
+	})
+
+	t.Run("Stops At Page Budget", func(t *testing.T) {
+		var fetches atomic.Int32
+		var seen []int
+		paginate := NewPaginate(testIdentity("sync"), listing(10, 0, &fetches), items, next, collect(&seen)).
+			SetMaxPages(2)
+		last, err := paginate.Process(context.Background(), testPage{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(seen) != 4 || last.Next != 4 {
+			t.Errorf("expected 2 pages and a cursor to resume from, got %v and %+v", seen, last)
+		}
+	})
+
+	t.Run("Retries Pages", func(t *testing.T) {
+		var fetches atomic.Int32
+		var seen []int
+		paginate := NewPaginate(testIdentity("sync"), listing(3, 2, &fetches), items, next, collect(&seen)).
+			SetPageRetries(3, time.Millisecond)
+		if _, err := paginate.Process(context.Background(), testPage{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(seen) != 3 || fetches.Load() != 4 {
+			t.Errorf("expected 3 items after 2 failed fetches, got %v after %d fetches", seen, fetches.Load())
+		}
+	})
+
+	t.Run("Fetch Failure", func(t *testing.T) {
+		var fetches atomic.Int32
+		var seen []int
+		paginate := NewPaginate(testIdentity("sync"), listing(3, 1, &fetches), items, next, collect(&seen))
+		_, err := paginate.Process(context.Background(), testPage{})
+		var pipeErr *Error[testPage]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "sync" {
+			t.Errorf("expected path through sync, got %v", err)
+		}
+	})
+
+	t.Run("Item Failure", func(t *testing.T) {
+		var fetches atomic.Int32
+		failing := Apply(testIdentity("store"), func(_ context.Context, n int) (int, error) {
+			if n == 3 {
+				return n, errors.New("bad record")
+			}
+			return n, nil
+		})
+		last, err := NewPaginate(testIdentity("sync"), listing(6, 0, &fetches), items, next, failing).
+			Process(context.Background(), testPage{})
+		var itemErr *Error[int]
+		if !errors.As(err, &itemErr) || itemErr.InputData != 3 {
+			t.Fatalf("expected the item error, got %v", err)
+		}
+		if last.Cursor != 2 {
+			t.Errorf("expected the failing page, got %+v", last)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		var fetches atomic.Int32
+		var seen []int
+		node := NewPaginate(testIdentity("sync"), listing(1, 0, &fetches), items, next, collect(&seen)).
+			SetPageRetries(2, time.Second).
+			Schema()
+		flow, ok := node.Flow.(PaginateFlow)
+		if node.Type != "paginate" || !ok {
+			t.Fatalf("expected paginate node, got %s %T", node.Type, node.Flow)
+		}
+		if flow.Fetch.Type != "backoff" || flow.Process.Identity.Name() != "store" {
+			t.Errorf("unexpected flow: %+v", flow)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/capitan"
+)
+
+// ErrProcessorQuarantined is wrapped by errors from a processor the panic
+// circuit has disabled.
+var ErrProcessorQuarantined = errors.New("quarantined processor")
+
+// panicRecord tracks the recent panics of one processor identity.
+type panicRecord struct {
+	identity    Identity
+	panics      []time.Time
+	quarantined bool
+}
+
+// panicCircuit is the process-wide panic circuit state.
+var panicCircuit = struct {
+	records   map[uuid.UUID]*panicRecord
+	optional  map[uuid.UUID]bool
+	threshold int
+	window    time.Duration
+	mu        sync.RWMutex
+}{
+	records:  make(map[uuid.UUID]*panicRecord),
+	optional: make(map[uuid.UUID]bool),
+}
+
+// SetPanicCircuit enables the panic circuit: a processor that panics
+// threshold times within window is quarantined. A quarantined processor
+// marked with MarkOptional is bypassed, passing its input through
+// unchanged; any other fails fast with ErrProcessorQuarantined instead of
+// running, so one bad deploy or poison input cannot keep crashing through
+// recover on every item. SignalProcessorQuarantined alerts operators, and
+// EnableProcessor restores the processor once it is fixed.
+//
+// The circuit is keyed by identity, so every Processor sharing an identity
+// shares a circuit. A threshold below one disables the circuit and
+// re-enables every quarantined processor. With the circuit disabled the
+// check costs one atomic load per processor call.
+//
+// Example:
+//
+//	pipz.SetPanicCircuit(5, time.Minute)
+//	pipz.MarkOptional(RecommendID) // recommendations can be skipped
+func SetPanicCircuit(threshold int, window time.Duration) {
+	panicCircuit.mu.Lock()
+	defer panicCircuit.mu.Unlock()
+	panicCircuit.threshold = threshold
+	panicCircuit.window = window
+	updateHooks(func(h *processorHooks) { h.panicCircuit = threshold >= 1 })
+	if threshold < 1 {
+		clear(panicCircuit.records)
+	}
+}
+
+// MarkOptional marks processors whose work can be skipped, so the panic
+// circuit bypasses them once quarantined instead of failing the item.
+func MarkOptional(identities ...Identity) {
+	panicCircuit.mu.Lock()
+	defer panicCircuit.mu.Unlock()
+	for _, identity := range identities {
+		panicCircuit.optional[identity.ID()] = true
+	}
+}
+
+// QuarantinedProcessors returns the identities the panic circuit has
+// disabled, sorted by name.
+func QuarantinedProcessors() []Identity {
+	panicCircuit.mu.RLock()
+	defer panicCircuit.mu.RUnlock()
+	var quarantined []Identity
+	for _, record := range panicCircuit.records {
+		if record.quarantined {
+			quarantined = append(quarantined, record.identity)
+		}
+	}
+	sort.Slice(quarantined, func(i, j int) bool {
+		return quarantined[i].Name() < quarantined[j].Name()
+	})
+	return quarantined
+}
+
+// EnableProcessor re-enables a quarantined processor and clears its panic
+// history. It returns false if the processor was not quarantined.
+func EnableProcessor(identity Identity) bool {
+	panicCircuit.mu.Lock()
+	record, ok := panicCircuit.records[identity.ID()]
+	if ok {
+		delete(panicCircuit.records, identity.ID())
+	}
+	panicCircuit.mu.Unlock()
+	if !ok || !record.quarantined {
+		return false
+	}
+	capitan.Info(context.Background(), SignalProcessorReenabled,
+		FieldName.Field(identity.Name()),
+		FieldIdentityID.Field(identity.ID().String()),
+	)
+	return true
+}
+
+// panicCircuitEnabled reports whether the panic circuit is enabled.
+func panicCircuitEnabled() bool {
+	hooks := activeHooks.Load()
+	return hooks != nil && hooks.panicCircuit
+}
+
+// checkPanicCircuit reports whether identity is quarantined: bypass is true
+// for an optional processor, and err is set for any other.
+func checkPanicCircuit[T any](identity Identity, data T) (bypass bool, err *Error[T]) {
+	if !panicCircuitEnabled() {
+		return false, nil
+	}
+	panicCircuit.mu.RLock()
+	record, ok := panicCircuit.records[identity.ID()]
+	quarantined := ok && record.quarantined
+	optional := panicCircuit.optional[identity.ID()]
+	panicCircuit.mu.RUnlock()
+
+	if !quarantined {
+		return false, nil
+	}
+	if optional {
+		return true, nil
+	}
+	return false, &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(data),
+		Err:       fmt.Errorf("%w %q: disabled after repeated panics", ErrProcessorQuarantined, identity.Name()),
+		Path:      []Identity{identity},
+	}
+}
+
+// notePanic records a panic raised by identity's own code, quarantining it
+// once the threshold is reached.
+func notePanic(identity Identity) {
+	if !panicCircuitEnabled() {
+		return
+	}
+
+	panicCircuit.mu.Lock()
+	threshold := panicCircuit.threshold
+	if threshold < 1 {
+		panicCircuit.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	record, ok := panicCircuit.records[identity.ID()]
+	if !ok {
+		record = &panicRecord{identity: identity}
+		panicCircuit.records[identity.ID()] = record
+	}
+	if record.quarantined {
+		panicCircuit.mu.Unlock()
+		return
+	}
+	recent := record.panics[:0]
+	for _, at := range record.panics {
+		if panicCircuit.window <= 0 || now.Sub(at) <= panicCircuit.window {
+			recent = append(recent, at)
+		}
+	}
+	record.panics = append(recent, now)
+	panics := len(record.panics)
+	if panics >= threshold {
+		record.quarantined = true
+		record.panics = nil
+	}
+	optional := panicCircuit.optional[identity.ID()]
+	panicCircuit.mu.Unlock()
+
+	if panics < threshold {
+		return
+	}
+	mode := "fail"
+	if optional {
+		mode = "bypass"
+	}
+	capitan.Error(context.Background(), SignalProcessorQuarantined,
+		FieldName.Field(identity.Name()),
+		FieldIdentityID.Field(identity.ID().String()),
+		FieldFailures.Field(panics),
+		FieldMode.Field(mode),
+	)
+}
@@ -0,0 +1,127 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPanicCircuit(t *testing.T) {
+	ctx := context.Background()
+	panicky := func(name string) (Processor[int], Identity) {
+		id := NewIdentity(name, "")
+		return Transform(id, func(_ context.Context, n int) int {
+			if n < 0 {
+				panic("negative input")
+			}
+			return n * 2
+		}), id
+	}
+
+	t.Run("Disabled By Default", func(t *testing.T) {
+		processor, _ := panicky("disabled")
+		for i := 0; i < 5; i++ {
+			if _, err := processor.Process(ctx, -1); !errors.Is(err, ErrPanic) {
+				t.Fatalf("expected panic error, got %v", err)
+			}
+		}
+		if got, err := processor.Process(ctx, 2); err != nil || got != 4 {
+			t.Errorf("processor disabled without a circuit: %d, %v", got, err)
+		}
+	})
+
+	t.Run("Quarantines After Threshold", func(t *testing.T) {
+		SetPanicCircuit(3, time.Minute)
+		t.Cleanup(func() { SetPanicCircuit(0, 0) })
+		processor, id := panicky("quarantine-me")
+
+		for i := 0; i < 2; i++ {
+			_, _ = processor.Process(ctx, -1) //nolint:errcheck // recording panics
+		}
+		if got, err := processor.Process(ctx, 2); err != nil || got != 4 {
+			t.Fatalf("quarantined below threshold: %d, %v", got, err)
+		}
+		_, _ = processor.Process(ctx, -1) //nolint:errcheck // third panic
+
+		_, err := processor.Process(ctx, 2)
+		if !errors.Is(err, ErrProcessorQuarantined) {
+			t.Fatalf("expected ErrProcessorQuarantined, got %v", err)
+		}
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "quarantine-me" {
+			t.Errorf("unexpected error path: %v", err)
+		}
+		if q := QuarantinedProcessors(); len(q) != 1 || q[0].ID() != id.ID() {
+			t.Errorf("quarantined = %v", q)
+		}
+
+		if !EnableProcessor(id) {
+			t.Fatal("expected EnableProcessor to report a quarantined processor")
+		}
+		if EnableProcessor(id) {
+			t.Error("second EnableProcessor should report false")
+		}
+		if got, err := processor.Process(ctx, 2); err != nil || got != 4 {
+			t.Errorf("not re-enabled: %d, %v", got, err)
+		}
+	})
+
+	t.Run("Optional Is Bypassed", func(t *testing.T) {
+		SetPanicCircuit(1, time.Minute)
+		t.Cleanup(func() { SetPanicCircuit(0, 0) })
+		processor, id := panicky("optional")
+		MarkOptional(id)
+
+		_, _ = processor.Process(ctx, -1) //nolint:errcheck // trips the circuit
+		got, err := processor.Process(ctx, 7)
+		if err != nil || got != 7 {
+			t.Errorf("expected input passed through, got %d, %v", got, err)
+		}
+
+		pipeline := NewSequence(NewIdentity("flow", ""), processor, Transform(NewIdentity("inc", ""), func(_ context.Context, n int) int { return n + 1 }))
+		if got, err := pipeline.Process(ctx, -5); err != nil || got != -4 {
+			t.Errorf("pipeline did not skip bypassed step: %d, %v", got, err)
+		}
+	})
+
+	t.Run("Nested Panics Count Once", func(t *testing.T) {
+		SetPanicCircuit(1, time.Minute)
+		t.Cleanup(func() { SetPanicCircuit(0, 0) })
+		inner, innerID := panicky("inner")
+		seqID := NewIdentity("outer", "")
+		seq := NewSequence(seqID, inner)
+
+		_, _ = seq.Process(ctx, -1) //nolint:errcheck // trips the inner circuit
+		q := QuarantinedProcessors()
+		if len(q) != 1 || q[0].ID() != innerID.ID() {
+			t.Errorf("expected only the panicking processor quarantined, got %v", q)
+		}
+	})
+
+	t.Run("Window Forgets Old Panics", func(t *testing.T) {
+		SetPanicCircuit(2, time.Millisecond)
+		t.Cleanup(func() { SetPanicCircuit(0, 0) })
+		processor, _ := panicky("windowed")
+
+		_, _ = processor.Process(ctx, -1) //nolint:errcheck // first panic
+		time.Sleep(5 * time.Millisecond)
+		_, _ = processor.Process(ctx, -1) //nolint:errcheck // outside the window
+		if len(QuarantinedProcessors()) != 0 {
+			t.Error("panics outside the window should not quarantine")
+		}
+	})
+
+	t.Run("Disabling Clears Quarantine", func(t *testing.T) {
+		SetPanicCircuit(1, time.Minute)
+		processor, _ := panicky("cleared")
+		_, _ = processor.Process(ctx, -1) //nolint:errcheck // trips the circuit
+		SetPanicCircuit(0, 0)
+		if len(QuarantinedProcessors()) != 0 {
+			t.Error("expected quarantine cleared")
+		}
+		if _, err := processor.Process(ctx, 1); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
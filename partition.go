@@ -0,0 +1,185 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PartitionStats holds the execution counters of one Partition cell.
+type PartitionStats struct {
+	Requests int64 `json:"requests"`
+	Errors   int64 `json:"errors"`
+}
+
+// partitionCell is one isolated cell and its counters.
+type partitionCell[T any] struct {
+	chainable Chainable[T]
+	requests  atomic.Int64
+	errors    atomic.Int64
+}
+
+// Partition shards traffic into a fixed number of isolated cells by key.
+// Each cell is built once by the factory, so stateful connectors inside it
+// (CircuitBreaker, RateLimiter, WorkerPool) are private to the cell: a
+// poison-pill key or noisy tenant trips only its own cell's breaker, and
+// the other cells keep serving. Keys are hashed, so each key always lands
+// in the same cell.
+//
+// Where TenantRouter builds one variant per tenant, Partition bounds the
+// number of instances regardless of key cardinality, trading perfect
+// isolation for a fixed footprint: a failing key affects roughly 1/N of
+// the traffic.
+//
+// CRITICAL: Partition is STATEFUL - its cells hold their own state. Create
+// it once and reuse it.
+//
+// Example:
+//
+//	var CellsID = pipz.NewIdentity("payment-cells", "Isolates payment failures by merchant")
+//	cells := pipz.NewPartition(CellsID, 8,
+//	    func(_ context.Context, p Payment) string { return p.MerchantID },
+//	    func(cell int) pipz.Chainable[Payment] {
+//	        id := pipz.NewIdentity(fmt.Sprintf("payment-breaker-%d", cell), "Breaker for one cell")
+//	        return pipz.NewCircuitBreaker(id, chargeGateway, 5, 30*time.Second)
+//	    },
+//	)
+type Partition[T any] struct {
+	key       func(context.Context, T) string
+	identity  Identity
+	cells     []*partitionCell[T]
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewPartition creates a Partition of n cells, building each with factory
+// and assigning items to cells by the hash of key. An n below one is
+// treated as one.
+func NewPartition[T any](identity Identity, n int, key func(context.Context, T) string, factory func(cell int) Chainable[T]) *Partition[T] {
+	cells := make([]*partitionCell[T], max(n, 1))
+	for i := range cells {
+		cells[i] = &partitionCell[T]{chainable: factory(i)}
+	}
+	return &Partition[T]{
+		identity: identity,
+		key:      key,
+		cells:    cells,
+	}
+}
+
+// Process implements the Chainable interface.
+// Routes the item to the cell owning its key.
+func (p *Partition[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, p.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, p, p.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	p.mu.RLock()
+	key := p.key
+	p.mu.RUnlock()
+
+	cell := p.cells[cellIndex(key(ctx, data), len(p.cells))]
+	cell.requests.Add(1)
+
+	result, err = cell.chainable.Process(ctx, data)
+	if err != nil {
+		if !isControl(err) {
+			cell.errors.Add(1)
+		}
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, p.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{p.identity},
+		}
+	}
+	return result, nil
+}
+
+// cellIndex hashes key onto one of n cells.
+func cellIndex(key string, n int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key)) //nolint:errcheck // hash writes never fail
+	return int(h.Sum32() % uint32(n))
+}
+
+// Cell returns the index of the cell that items with key are routed to.
+func (p *Partition[T]) Cell(key string) int {
+	return cellIndex(key, len(p.cells))
+}
+
+// Cells returns the number of cells.
+func (p *Partition[T]) Cells() int {
+	return len(p.cells)
+}
+
+// Stats returns the counters of the cell at index. It returns false if
+// there is no such cell.
+func (p *Partition[T]) Stats(cell int) (PartitionStats, bool) {
+	if cell < 0 || cell >= len(p.cells) {
+		return PartitionStats{}, false
+	}
+	return PartitionStats{
+		Requests: p.cells[cell].requests.Load(),
+		Errors:   p.cells[cell].errors.Load(),
+	}, true
+}
+
+// SetKeyFunc updates the function extracting each item's partition key.
+func (p *Partition[T]) SetKeyFunc(key func(context.Context, T) string) *Partition[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.key = key
+	return p
+}
+
+// Identity returns the identity of this connector.
+func (p *Partition[T]) Identity() Identity {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (p *Partition[T]) Schema() Node {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	cells := make([]Node, len(p.cells))
+	for i, cell := range p.cells {
+		cells[i] = cell.chainable.Schema()
+	}
+	return Node{
+		Identity: p.identity,
+		Type:     "partition",
+		Flow:     PartitionFlow{Cells: cells},
+		Metadata: map[string]any{"cells": len(p.cells)},
+	}
+}
+
+// Close gracefully shuts down the connector and every cell.
+// Close is idempotent - multiple calls return the same result.
+func (p *Partition[T]) Close() error {
+	p.closeOnce.Do(func() {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		errs := make([]error, 0, len(p.cells))
+		for _, cell := range p.cells {
+			errs = append(errs, cell.chainable.Close())
+		}
+		p.closeErr = errors.Join(errs...)
+	})
+	return p.closeErr
+}
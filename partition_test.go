@@ -0,0 +1,108 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPartition(t *testing.T) {
+	type item struct {
+		key    string
+		poison bool
+	}
+	byKey := func(_ context.Context, i item) string { return i.key }
+	newCells := func(n int) *Partition[item] {
+		return NewPartition(NewIdentity("cells", ""), n, byKey, func(cell int) Chainable[item] {
+			call := Apply(NewIdentity("call", ""), func(_ context.Context, i item) (item, error) {
+				if i.poison {
+					return i, errors.New("poison")
+				}
+				return i, nil
+			})
+			return NewCircuitBreaker(NewIdentity(fmt.Sprintf("breaker-%d", cell), ""), call, 2, time.Minute)
+		})
+	}
+
+	// keyInOtherCell finds a key routed to a different cell than key.
+	keyInOtherCell := func(p *Partition[item], key string) string {
+		for i := 0; ; i++ {
+			other := fmt.Sprintf("key-%d", i)
+			if p.Cell(other) != p.Cell(key) {
+				return other
+			}
+		}
+	}
+
+	t.Run("Poison Key Trips Only Its Cell", func(t *testing.T) {
+		cells := newCells(4)
+		bad := "merchant-bad"
+		good := keyInOtherCell(cells, bad)
+
+		for range 3 {
+			_, _ = cells.Process(context.Background(), item{key: bad, poison: true}) //nolint:errcheck // tripping the breaker
+		}
+		if _, err := cells.Process(context.Background(), item{key: bad}); err == nil {
+			t.Fatal("expected the poisoned cell's breaker to be open")
+		}
+		if _, err := cells.Process(context.Background(), item{key: good}); err != nil {
+			t.Errorf("expected other cells to keep serving, got %v", err)
+		}
+	})
+
+	t.Run("Keys Map To Stable Cells", func(t *testing.T) {
+		cells := newCells(8)
+		for _, key := range []string{"a", "b", "c"} {
+			if cells.Cell(key) != cells.Cell(key) || cells.Cell(key) >= cells.Cells() {
+				t.Errorf("unstable or out-of-range cell for %q", key)
+			}
+		}
+	})
+
+	t.Run("Stats Per Cell", func(t *testing.T) {
+		cells := newCells(4)
+		_, _ = cells.Process(context.Background(), item{key: "a"})               //nolint:errcheck // counted below
+		_, _ = cells.Process(context.Background(), item{key: "a", poison: true}) //nolint:errcheck // counted below
+		stats, ok := cells.Stats(cells.Cell("a"))
+		if !ok || stats.Requests != 2 || stats.Errors != 1 {
+			t.Errorf("expected 2 requests and 1 error, got %+v", stats)
+		}
+		if _, ok := cells.Stats(4); ok {
+			t.Error("expected no stats for a missing cell")
+		}
+	})
+
+	t.Run("Errors Carry Path", func(t *testing.T) {
+		cells := newCells(2)
+		_, err := cells.Process(context.Background(), item{key: "a", poison: true})
+		var pipeErr *Error[item]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "cells" {
+			t.Errorf("expected path to start at the partition, got %v", err)
+		}
+	})
+
+	t.Run("At Least One Cell", func(t *testing.T) {
+		if cells := newCells(0); cells.Cells() != 1 {
+			t.Errorf("expected 1 cell, got %d", cells.Cells())
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := newCells(3).Schema()
+		flow, ok := PartitionKey.From(node)
+		if !ok {
+			t.Fatalf("expected PartitionFlow, got %T", node.Flow)
+		}
+		if len(flow.Cells) != 3 || flow.Cells[2].Identity.Name() != "breaker-2" {
+			t.Errorf("expected 3 cells in order, got %+v", flow.Cells)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		if err := newCells(2).Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,340 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrPIIDetected is returned (wrapped in a PIIViolationError) when a
+// PIIBlock policy finds personally identifiable information.
+var ErrPIIDetected = errors.New("pii detected")
+
+// PIIKind identifies a category of personally identifiable information.
+type PIIKind string
+
+// Built-in PII kinds.
+const (
+	PIIEmail      PIIKind = "email"
+	PIISSN        PIIKind = "ssn"
+	PIICardNumber PIIKind = "card_number"
+)
+
+// PIIAction determines what EnforcePII does when PII is found.
+type PIIAction string
+
+// PII enforcement actions.
+const (
+	// PIIBlock fails processing with a PIIViolationError.
+	PIIBlock PIIAction = "block"
+	// PIIMask replaces each match with a redaction marker and continues.
+	PIIMask PIIAction = "mask"
+	// PIITag leaves data intact and passes findings to the policy's Tag function.
+	PIITag PIIAction = "tag"
+)
+
+// ParsePIIAction returns the PII enforcement action named s. Unknown names
+// return an error wrapping ErrInvalidMode that lists the valid names.
+func ParsePIIAction(s string) (PIIAction, error) {
+	return parseMode("pii", s, PIIBlock, PIIMask, PIITag)
+}
+
+// PIIPattern describes how to recognize one kind of PII.
+// Validate, when set, filters regexp matches to reduce false positives
+// (e.g. a Luhn check for card numbers).
+type PIIPattern struct {
+	Regexp   *regexp.Regexp
+	Validate func(match string) bool
+	Kind     PIIKind
+}
+
+// DefaultPIIPatterns returns patterns for emails, US social security
+// numbers, and payment card numbers (Luhn-validated).
+func DefaultPIIPatterns() []PIIPattern {
+	return []PIIPattern{
+		{Kind: PIIEmail, Regexp: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+		{Kind: PIISSN, Regexp: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+		{Kind: PIICardNumber, Regexp: regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`), Validate: luhnValid},
+	}
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// PIIFinding records one PII match.
+type PIIFinding struct {
+	Field string
+	Kind  PIIKind
+}
+
+// PIIViolationError reports PII found by a PIIBlock policy.
+// It unwraps to ErrPIIDetected. Matched values are deliberately not
+// included so the error itself cannot leak the data it reports.
+type PIIViolationError struct {
+	Findings []PIIFinding
+}
+
+func (e *PIIViolationError) Error() string {
+	parts := make([]string, len(e.Findings))
+	for i, f := range e.Findings {
+		parts[i] = fmt.Sprintf("%s in %s", f.Kind, f.Field)
+	}
+	return fmt.Sprintf("pii detected: %s", strings.Join(parts, ", "))
+}
+
+// Unwrap returns ErrPIIDetected.
+func (*PIIViolationError) Unwrap() error {
+	return ErrPIIDetected
+}
+
+// PIIField registers an accessor for a string field of T.
+// Set is required for PIIMask; it must return a modified copy.
+type PIIField[T any] struct {
+	Get  func(T) string
+	Set  func(T, string) T
+	Name string
+}
+
+// PIIPolicy configures EnforcePII.
+//
+// When Fields is empty, exported string fields of T (including nested
+// structs and string slices) are discovered by reflection. Registered
+// Fields avoid reflection and are preferred on hot paths. When T is a
+// pointer, reflective masking modifies the pointed-to value in place.
+type PIIPolicy[T any] struct {
+	// Tag receives the data and findings when Action is PIITag,
+	// returning the annotated data.
+	Tag func(T, []PIIFinding) T
+	// Mask returns the replacement for a match when Action is PIIMask.
+	// Defaults to "[REDACTED:<kind>]".
+	Mask     func(kind PIIKind, match string) string
+	Action   PIIAction
+	Fields   []PIIField[T]
+	Patterns []PIIPattern
+}
+
+// EnforcePII creates a Processor that scans string fields of T for PII and
+// applies the policy's action: block the item, mask the matches, or tag the
+// item with its findings. Every detection emits a pii.detected signal
+// listing the kinds and field names found (never the values).
+//
+// Example:
+//
+//	var ScrubTicketID = pipz.NewIdentity("scrub-ticket", "Masks PII in support tickets")
+//	scrub := pipz.EnforcePII(ScrubTicketID, pipz.PIIPolicy[Ticket]{
+//	    Action: pipz.PIIMask,
+//	    Fields: []pipz.PIIField[Ticket]{{
+//	        Name: "body",
+//	        Get:  func(t Ticket) string { return t.Body },
+//	        Set:  func(t Ticket, s string) Ticket { t.Body = s; return t },
+//	    }},
+//	})
+func EnforcePII[T any](identity Identity, policy PIIPolicy[T]) Processor[T] {
+	patterns := policy.Patterns
+	if len(patterns) == 0 {
+		patterns = DefaultPIIPatterns()
+	}
+	mask := policy.Mask
+	if mask == nil {
+		mask = func(kind PIIKind, _ string) string { return "[REDACTED:" + string(kind) + "]" }
+	}
+	action := policy.Action
+	if action == "" {
+		action = PIIBlock
+	}
+
+	return Processor[T]{
+		identity: identity,
+		fn: func(ctx context.Context, value T) (result T, err error) {
+			defer recoverFromPanic(&result, &err, identity, value)
+			start := time.Now()
+
+			masking := action == PIIMask
+			var findings []PIIFinding
+			scan := func(field, s string) (string, bool) {
+				out, found := scanPII(s, patterns, masking, mask)
+				for _, k := range found {
+					findings = append(findings, PIIFinding{Field: field, Kind: k})
+				}
+				return out, masking && len(found) > 0
+			}
+
+			result = value
+			if len(policy.Fields) > 0 {
+				for _, f := range policy.Fields {
+					if out, changed := scan(f.Name, f.Get(result)); changed {
+						if f.Set == nil {
+							return value, &Error[T]{
+								Path:      []Identity{identity},
+								InputData: errorInput(value),
+								Err:       fmt.Errorf("pii field %q has no setter for masking", f.Name),
+								Timestamp: time.Now(),
+								Duration:  time.Since(start),
+							}
+						}
+						result = f.Set(result, out)
+					}
+				}
+			} else {
+				result = scanPIIReflect(value, scan)
+			}
+
+			if len(findings) == 0 {
+				return value, nil
+			}
+
+			capitan.Warn(ctx, SignalPIIDetected,
+				FieldName.Field(identity.Name()),
+				FieldIdentityID.Field(identity.ID().String()),
+				FieldMode.Field(string(action)),
+				FieldPIIKinds.Field(piiKindList(findings)),
+				FieldPIIFields.Field(piiFieldList(findings)),
+			)
+
+			switch action {
+			case PIIMask:
+				return result, nil
+			case PIITag:
+				if policy.Tag != nil {
+					return policy.Tag(value, findings), nil
+				}
+				return value, nil
+			default:
+				var zero T
+				return zero, &Error[T]{
+					Path:      []Identity{identity},
+					InputData: errorInput(value),
+					Err:       &PIIViolationError{Findings: findings},
+					Timestamp: time.Now(),
+					Duration:  time.Since(start),
+				}
+			}
+		},
+	}
+}
+
+// scanPII finds PII kinds in s and optionally masks them.
+func scanPII(s string, patterns []PIIPattern, masking bool, mask func(PIIKind, string) string) (string, []PIIKind) {
+	var kinds []PIIKind
+	for _, p := range patterns {
+		matched := false
+		replaced := p.Regexp.ReplaceAllStringFunc(s, func(m string) string {
+			if p.Validate != nil && !p.Validate(m) {
+				return m
+			}
+			matched = true
+			if masking {
+				return mask(p.Kind, m)
+			}
+			return m
+		})
+		if matched {
+			kinds = append(kinds, p.Kind)
+			s = replaced
+		}
+	}
+	return s, kinds
+}
+
+// scanPIIReflect walks exported string fields of data, scanning each.
+// It operates on a copy for value types so masking never touches the input.
+func scanPIIReflect[T any](data T, scan func(field, s string) (string, bool)) T {
+	v := reflect.ValueOf(&data).Elem()
+	walkPIIValue(v, "", scan)
+	return data
+}
+
+func walkPIIValue(v reflect.Value, path string, scan func(field, s string) (string, bool)) {
+	switch v.Kind() {
+	case reflect.String:
+		name := path
+		if name == "" {
+			name = "value"
+		}
+		if out, changed := scan(name, v.String()); changed && v.CanSet() {
+			v.SetString(out)
+		}
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkPIIValue(v.Elem(), path, scan)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			name := t.Field(i).Name
+			if path != "" {
+				name = path + "." + name
+			}
+			walkPIIValue(v.Field(i), name, scan)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String || v.Len() == 0 {
+			return
+		}
+		// Copy before scanning so masking cannot alias the caller's slice.
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(cp, v)
+		for i := 0; i < cp.Len(); i++ {
+			walkPIIValue(cp.Index(i), fmt.Sprintf("%s[%d]", path, i), scan)
+		}
+		if v.CanSet() {
+			v.Set(cp)
+		}
+	}
+}
+
+func piiKindList(findings []PIIFinding) string {
+	seen := make(map[PIIKind]bool)
+	var kinds []string
+	for _, f := range findings {
+		if !seen[f.Kind] {
+			seen[f.Kind] = true
+			kinds = append(kinds, string(f.Kind))
+		}
+	}
+	sort.Strings(kinds)
+	return strings.Join(kinds, ",")
+}
+
+func piiFieldList(findings []PIIFinding) string {
+	seen := make(map[string]bool)
+	var fields []string
+	for _, f := range findings {
+		if !seen[f.Field] {
+			seen[f.Field] = true
+			fields = append(fields, f.Field)
+		}
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ",")
+}
@@ -0,0 +1,207 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+type piiTicket struct {
+	Subject string
+	Body    string
+	Tags    []string
+	Contact piiContact
+	Flagged bool
+	secret  string
+}
+
+type piiContact struct {
+	Email string
+}
+
+func TestEnforcePII(t *testing.T) {
+	t.Run("Clean Data Passes", func(t *testing.T) {
+		p := EnforcePII(NewIdentity("pii", ""), PIIPolicy[piiTicket]{})
+		in := piiTicket{Subject: "hello", Body: "order 12345 is late"}
+		out, err := p.Process(context.Background(), in)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Subject != in.Subject || out.Body != in.Body {
+			t.Errorf("expected data unchanged, got %+v", out)
+		}
+	})
+
+	t.Run("Block Is Default", func(t *testing.T) {
+		p := EnforcePII(NewIdentity("pii", ""), PIIPolicy[piiTicket]{})
+		_, err := p.Process(context.Background(), piiTicket{Body: "my ssn is 123-45-6789"})
+		if !errors.Is(err, ErrPIIDetected) {
+			t.Fatalf("expected ErrPIIDetected, got %v", err)
+		}
+		var violation *PIIViolationError
+		if !errors.As(err, &violation) {
+			t.Fatal("expected PIIViolationError")
+		}
+		if len(violation.Findings) != 1 || violation.Findings[0].Kind != PIISSN || violation.Findings[0].Field != "Body" {
+			t.Errorf("unexpected findings: %+v", violation.Findings)
+		}
+		if strings.Contains(err.Error(), "123-45-6789") {
+			t.Error("error message must not contain the PII value")
+		}
+		var pipeErr *Error[piiTicket]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "pii" {
+			t.Errorf("expected pipz error path, got %v", err)
+		}
+	})
+
+	t.Run("Reflection Finds Nested And Slice Fields", func(t *testing.T) {
+		p := EnforcePII(NewIdentity("pii", ""), PIIPolicy[piiTicket]{})
+		_, err := p.Process(context.Background(), piiTicket{
+			Tags:    []string{"ok", "card 4111 1111 1111 1111"},
+			Contact: piiContact{Email: "jane@example.com"},
+			secret:  "987-65-4321",
+		})
+		var violation *PIIViolationError
+		if !errors.As(err, &violation) {
+			t.Fatalf("expected violation, got %v", err)
+		}
+		fields := map[string]PIIKind{}
+		for _, f := range violation.Findings {
+			fields[f.Field] = f.Kind
+		}
+		if fields["Tags[1]"] != PIICardNumber {
+			t.Errorf("expected card number in Tags[1], got %+v", fields)
+		}
+		if fields["Contact.Email"] != PIIEmail {
+			t.Errorf("expected email in Contact.Email, got %+v", fields)
+		}
+		if _, ok := fields["secret"]; ok {
+			t.Error("unexported fields should be skipped")
+		}
+	})
+
+	t.Run("Card Numbers Require Luhn", func(t *testing.T) {
+		p := EnforcePII(NewIdentity("pii", ""), PIIPolicy[string]{})
+		if _, err := p.Process(context.Background(), "ref 1234 5678 9012 3456"); err != nil {
+			t.Errorf("non-Luhn number should pass, got %v", err)
+		}
+		if _, err := p.Process(context.Background(), "ref 4111111111111111"); !errors.Is(err, ErrPIIDetected) {
+			t.Errorf("Luhn-valid card should be detected, got %v", err)
+		}
+	})
+
+	t.Run("Mask Reflection", func(t *testing.T) {
+		p := EnforcePII(NewIdentity("pii", ""), PIIPolicy[piiTicket]{Action: PIIMask})
+		in := piiTicket{
+			Body: "email jane@example.com or call",
+			Tags: []string{"123-45-6789"},
+		}
+		out, err := p.Process(context.Background(), in)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Body != "email [REDACTED:email] or call" {
+			t.Errorf("unexpected masked body: %q", out.Body)
+		}
+		if out.Tags[0] != "[REDACTED:ssn]" {
+			t.Errorf("unexpected masked tag: %q", out.Tags[0])
+		}
+		if in.Tags[0] != "123-45-6789" {
+			t.Error("masking must not modify the caller's slice")
+		}
+	})
+
+	t.Run("Mask Registered Fields", func(t *testing.T) {
+		p := EnforcePII(NewIdentity("pii", ""), PIIPolicy[piiTicket]{
+			Action: PIIMask,
+			Mask:   func(_ PIIKind, _ string) string { return "***" },
+			Fields: []PIIField[piiTicket]{{
+				Name: "body",
+				Get:  func(t piiTicket) string { return t.Body },
+				Set:  func(t piiTicket, s string) piiTicket { t.Body = s; return t },
+			}},
+		})
+		out, err := p.Process(context.Background(), piiTicket{
+			Subject: "jane@example.com",
+			Body:    "jane@example.com",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Body != "***" {
+			t.Errorf("expected masked body, got %q", out.Body)
+		}
+		if out.Subject != "jane@example.com" {
+			t.Error("unregistered fields should not be scanned")
+		}
+	})
+
+	t.Run("Mask Without Setter Fails", func(t *testing.T) {
+		p := EnforcePII(NewIdentity("pii", ""), PIIPolicy[piiTicket]{
+			Action: PIIMask,
+			Fields: []PIIField[piiTicket]{{
+				Name: "body",
+				Get:  func(t piiTicket) string { return t.Body },
+			}},
+		})
+		if _, err := p.Process(context.Background(), piiTicket{Body: "jane@example.com"}); err == nil {
+			t.Error("expected error when masking without a setter")
+		}
+	})
+
+	t.Run("Tag", func(t *testing.T) {
+		p := EnforcePII(NewIdentity("pii", ""), PIIPolicy[piiTicket]{
+			Action: PIITag,
+			Tag: func(t piiTicket, findings []PIIFinding) piiTicket {
+				t.Flagged = len(findings) > 0
+				return t
+			},
+		})
+		out, err := p.Process(context.Background(), piiTicket{Body: "jane@example.com"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !out.Flagged || out.Body != "jane@example.com" {
+			t.Errorf("expected flagged, unmasked data, got %+v", out)
+		}
+	})
+
+	t.Run("Custom Patterns", func(t *testing.T) {
+		p := EnforcePII(NewIdentity("pii", ""), PIIPolicy[string]{
+			Patterns: []PIIPattern{{Kind: "employee_id", Regexp: regexp.MustCompile(`EMP-\d{6}`)}},
+		})
+		if _, err := p.Process(context.Background(), "jane@example.com"); err != nil {
+			t.Errorf("default patterns should be replaced, got %v", err)
+		}
+		if _, err := p.Process(context.Background(), "EMP-123456"); !errors.Is(err, ErrPIIDetected) {
+			t.Errorf("expected custom pattern detection, got %v", err)
+		}
+	})
+
+	t.Run("Emits Signal", func(t *testing.T) {
+		var kinds, fields, mode string
+		listener := capitan.Hook(SignalPIIDetected, func(_ context.Context, e *capitan.Event) {
+			kinds, _ = FieldPIIKinds.From(e)
+			fields, _ = FieldPIIFields.From(e)
+			mode, _ = FieldMode.From(e)
+		})
+		defer listener.Close()
+
+		p := EnforcePII(NewIdentity("pii", ""), PIIPolicy[piiTicket]{Action: PIITag})
+		_, _ = p.Process(context.Background(), piiTicket{ //nolint:errcheck // tag never fails
+			Subject: "123-45-6789",
+			Body:    "jane@example.com",
+		})
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if kinds != "email,ssn" || fields != "Body,Subject" || mode != "tag" {
+			t.Errorf("unexpected signal fields: kinds=%q fields=%q mode=%q", kinds, fields, mode)
+		}
+	})
+}
@@ -2,6 +2,10 @@ package pipz
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -31,8 +35,15 @@ import (
 //	    log.Printf("Execution %s completed", execID)
 //	}
 type Pipeline[T any] struct {
-	identity Identity
-	root     Chainable[T]
+	identity     Identity
+	root         Chainable[T]
+	policy       *Policy
+	sanitizer    *SanitizerPolicy
+	maxPathDepth int
+	mu           sync.RWMutex
+	processed    atomic.Int64
+	failed       atomic.Int64
+	skipped      atomic.Int64
 }
 
 // NewPipeline creates a Pipeline that wraps a Chainable with execution context.
@@ -48,11 +59,161 @@ func NewPipeline[T any](identity Identity, root Chainable[T]) *Pipeline[T] {
 // Process executes the wrapped Chainable with execution context.
 // Each call generates a unique execution ID and injects both the
 // execution ID and pipeline ID into the context before delegating
-// to the root Chainable.
+// to the root Chainable. If the pipeline has a Policy it is attached
+// to the context, and the effective Policy's DefaultTimeout is applied
+// when the caller's context has no deadline. The outermost Pipeline also
+// opens the compute scope in which Derived values are shared.
+//
+// Errors leaving the pipeline carry the execution ID and pipeline counters
+// at the moment of failure, and build the schema when reported; see
+// Error.Report.
 func (p *Pipeline[T]) Process(ctx context.Context, data T) (T, error) {
-	ctx = context.WithValue(ctx, executionIDKey{}, uuid.New())
+	return p.process(ctx, p.root, data)
+}
+
+// process runs root with the pipeline's execution context, counters, and
+// error snapshots.
+func (p *Pipeline[T]) process(ctx context.Context, root Chainable[T], data T) (T, error) {
+	executionID := uuid.New()
+	ctx = context.WithValue(ctx, executionIDKey{}, executionID)
 	ctx = context.WithValue(ctx, pipelineIDKey{}, p.identity.ID())
-	return p.root.Process(ctx, data)
+	ctx = withCorrelation(ctx, executionID.String())
+	ctx = WithComputeScope(ctx)
+	ctx = withSignalFieldScope(ctx)
+
+	p.mu.RLock()
+	policy := p.policy
+	sanitizer := p.sanitizer
+	maxPathDepth := p.maxPathDepth
+	p.mu.RUnlock()
+	if policy != nil {
+		ctx = WithPolicy(ctx, *policy)
+	}
+	if d := PolicyFromContext(ctx).DefaultTimeout; d > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, d)
+			defer cancel()
+		}
+	}
+
+	var (
+		result T
+		err    error
+	)
+	ctx, release, slotErr := acquireSlot(ctx, p.identity)
+	if slotErr != nil {
+		err = &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       slotErr,
+			Path:      []Identity{p.identity},
+			Timeout:   errors.Is(slotErr, context.DeadlineExceeded),
+			Canceled:  errors.Is(slotErr, context.Canceled),
+		}
+	} else {
+		defer release()
+		ctx, faultErr := checkFault(ctx, p.identity, data)
+		if faultErr != nil {
+			err = faultErr
+		} else {
+			result, err = root.Process(ctx, data)
+		}
+	}
+	if err != nil {
+		if value, ok := doneValue[T](err); ok {
+			result, err = value, nil
+		}
+	}
+	processed := p.processed.Add(1)
+	if IsSkip(err) {
+		p.skipped.Add(1)
+		return result, err
+	}
+	if err != nil {
+		if sanitizer != nil {
+			applySanitizer(err, *sanitizer)
+		}
+		failed := p.failed.Add(1)
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.truncatePath(maxPathDepth)
+			pipeErr.snapshot = &errorSnapshot[T]{
+				root:        p,
+				pipeline:    p.identity,
+				executionID: executionID,
+				stats:       PipelineStats{Processed: processed, Failed: failed},
+			}
+		}
+	}
+	return result, err
+}
+
+// ProcessWith processes data with per-call overrides, such as NoRetries,
+// ForceRoute, BypassCache, or LogVerbosity, leaving every other call
+// unaffected. It suits support and debug tooling replaying a problematic
+// request.
+//
+// Example:
+//
+//	result, err := pipeline.ProcessWith(ctx, order,
+//	    pipz.NoRetries(),
+//	    pipz.ForceRoute(PaymentRouterID, "manual-review"),
+//	    pipz.LogVerbosity(slog.LevelDebug),
+//	)
+func (p *Pipeline[T]) ProcessWith(ctx context.Context, data T, opts ...CallOption) (T, error) {
+	return p.Process(WithCallOptions(ctx, opts...), data)
+}
+
+// Stats returns the pipeline's execution counters.
+func (p *Pipeline[T]) Stats() PipelineStats {
+	return PipelineStats{Processed: p.processed.Load(), Failed: p.failed.Load(), Skipped: p.skipped.Load()}
+}
+
+// SetPolicy attaches a Policy that overrides the process-wide default for
+// every execution of this pipeline.
+func (p *Pipeline[T]) SetPolicy(policy Policy) *Pipeline[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.policy = &policy
+	return p
+}
+
+// SetSanitizer sets how panics recovered during this pipeline's executions
+// are rendered in the returned error, overriding the secure default. The
+// innermost Pipeline with a policy wins when pipelines are nested.
+func (p *Pipeline[T]) SetSanitizer(policy SanitizerPolicy) *Pipeline[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.sanitizer != nil && p.sanitizer.KeepStack {
+		stackKeepers.Add(-1)
+	}
+	if policy.KeepStack {
+		stackKeepers.Add(1)
+	}
+	p.sanitizer = &policy
+	return p
+}
+
+// SetMaxPathDepth caps the Path of errors returned by this pipeline at n
+// frames. Longer paths keep their outermost and innermost frames; the
+// frames between are dropped and counted in Error.PathTruncated. Zero or
+// negative means no cap.
+func (p *Pipeline[T]) SetMaxPathDepth(n int) *Pipeline[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxPathDepth = n
+	return p
+}
+
+// Policy returns the pipeline's Policy and whether one has been set.
+func (p *Pipeline[T]) Policy() (Policy, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.policy == nil {
+		return Policy{}, false
+	}
+	return *p.policy, true
 }
 
 // Identity returns the pipeline's identity.
@@ -70,6 +231,16 @@ func (p *Pipeline[T]) Schema() Node {
 	}
 }
 
+// Start starts the wrapped Chainable if it implements Starter.
+func (p *Pipeline[T]) Start(ctx context.Context) error {
+	return startChildren(ctx, p.identity, []Chainable[T]{p.root})
+}
+
+// Health checks the wrapped Chainable if it implements HealthChecker.
+func (p *Pipeline[T]) Health(ctx context.Context) error {
+	return checkChildren(ctx, p.identity, []Chainable[T]{p.root})
+}
+
 // Close gracefully shuts down the wrapped Chainable.
 func (p *Pipeline[T]) Close() error {
 	return p.root.Close()
@@ -0,0 +1,158 @@
+package pipz
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// Policy rule names reported in SignalPolicyViolation.
+const (
+	PolicyRuleMaxTotalAttempts = "max_total_attempts"
+	PolicyRuleMaxConcurrency   = "max_concurrency"
+)
+
+// Policy holds execution limits that nested connectors consult at runtime.
+// Unless noted otherwise, a zero value for a field means "no limit".
+//
+// Limits are enforced where connectors compose, so problems that no single
+// connector can see are caught. The classic case is retries inside retries:
+// a Retry of 3 wrapping a Backoff of 5 wrapping another Retry of 3 makes 45
+// calls per item. With MaxTotalAttempts set, each nested Retry or Backoff is
+// capped so the product of attempts along any path stays within budget.
+//
+// A Policy is resolved from the context first (see WithPolicy and
+// Pipeline.SetPolicy) and falls back to the process-wide default set with
+// SetDefaultPolicy. Whenever a connector is capped, SignalPolicyViolation is
+// emitted so the misconfiguration is visible rather than silently absorbed.
+//
+// Example:
+//
+//	pipz.SetDefaultPolicy(pipz.Policy{
+//	    MaxTotalAttempts: 10,
+//	    DefaultTimeout:   30 * time.Second,
+//	    MaxConcurrency:   16,
+//	})
+//
+//	// Batch jobs get a looser budget
+//	batch := pipz.NewPipeline(BatchID, root).SetPolicy(pipz.Policy{
+//	    MaxTotalAttempts: 50,
+//	    DefaultTimeout:   10 * time.Minute,
+//	})
+type Policy struct {
+	// MaxTotalAttempts caps the product of Retry and Backoff attempts along
+	// any path through the pipeline.
+	MaxTotalAttempts int
+	// DefaultTimeout is applied by Pipeline when the caller's context has
+	// no deadline.
+	DefaultTimeout time.Duration
+	// MaxConcurrency caps how many processors Concurrent and Group run in
+	// parallel.
+	MaxConcurrency int
+	// MaxDepth caps connector nesting depth. Zero uses DefaultMaxDepth.
+	MaxDepth int
+	// AllowRecursion permits a connector to be re-entered while it is
+	// already on the processing path, for deliberately recursive
+	// compositions that terminate on their data. MaxDepth still applies.
+	AllowRecursion bool
+	// CheckIsolation is a debug mode for Concurrent and Race: the original
+	// input is hashed with DeepHash before fan-out and again afterwards,
+	// and the item fails with ErrIsolationViolated if a processor mutated
+	// state its clone shared with the original. It costs two deep walks
+	// of the input per item, so enable it in tests and staging.
+	CheckIsolation bool
+	// LogAssertions turns failed Assert checks into warnings: the violation
+	// is signaled and the item continues unchanged. Leave it off in tests so
+	// violations fail the item, and enable it in production where a broken
+	// contract should be seen rather than stop traffic.
+	LogAssertions bool
+}
+
+// defaultPolicy is the process-wide Policy used when the context carries none.
+var defaultPolicy atomic.Pointer[Policy]
+
+// SetDefaultPolicy sets the process-wide Policy used when no Policy is
+// attached to the context. It is safe to call concurrently with processing;
+// in-flight calls keep the policy they resolved.
+func SetDefaultPolicy(policy Policy) {
+	defaultPolicy.Store(&policy)
+}
+
+// DefaultPolicy returns the process-wide Policy. The zero Policy (no limits)
+// is returned if none has been set.
+func DefaultPolicy() Policy {
+	if p := defaultPolicy.Load(); p != nil {
+		return *p
+	}
+	return Policy{}
+}
+
+// policyKey is the context key for a Policy override.
+type policyKey struct{}
+
+// attemptScaleKey is the context key for the product of enclosing attempts.
+type attemptScaleKey struct{}
+
+// WithPolicy returns a context carrying policy, overriding the process-wide
+// default for everything processed with it.
+func WithPolicy(ctx context.Context, policy Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, policy)
+}
+
+// PolicyFromContext returns the Policy in effect for ctx: the one attached
+// with WithPolicy or Pipeline.SetPolicy, or the process-wide default.
+func PolicyFromContext(ctx context.Context) Policy {
+	if ctx != nil {
+		if p, ok := ctx.Value(policyKey{}).(Policy); ok {
+			return p
+		}
+	}
+	return DefaultPolicy()
+}
+
+// allowedAttempts returns how many attempts a retrying connector may make
+// under the policy in ctx, and a context recording the new attempt scale
+// for nested connectors. A violation signal is emitted when capping.
+func allowedAttempts(ctx context.Context, identity Identity, requested int) (int, context.Context) {
+	if o := callOverridesFrom(ctx); o != nil && o.noRetries {
+		requested = 1
+	}
+	scale, ok := ctx.Value(attemptScaleKey{}).(int)
+	if !ok || scale < 1 {
+		scale = 1
+	}
+
+	allowed := requested
+	if limit := PolicyFromContext(ctx).MaxTotalAttempts; limit > 0 && scale*requested > limit {
+		allowed = limit / scale
+		if allowed < 1 {
+			allowed = 1
+		}
+		emitPolicyViolation(ctx, identity, PolicyRuleMaxTotalAttempts, scale*requested, scale*allowed)
+	}
+	return allowed, context.WithValue(ctx, attemptScaleKey{}, scale*allowed)
+}
+
+// allowedConcurrency returns the parallelism a fan-out connector may use
+// for n processors under the policy in ctx. Zero means unbounded.
+func allowedConcurrency(ctx context.Context, identity Identity, n int) int {
+	limit := PolicyFromContext(ctx).MaxConcurrency
+	if limit <= 0 || n <= limit {
+		return 0
+	}
+	emitPolicyViolation(ctx, identity, PolicyRuleMaxConcurrency, n, limit)
+	return limit
+}
+
+// emitPolicyViolation reports a connector capped by policy.
+func emitPolicyViolation(ctx context.Context, identity Identity, rule string, requested, allowed int) {
+	capitan.Warn(ctx, SignalPolicyViolation,
+		FieldName.Field(identity.Name()),
+		FieldIdentityID.Field(identity.ID().String()),
+		FieldPolicyRule.Field(rule),
+		FieldRequested.Field(requested),
+		FieldAllowed.Field(allowed),
+	)
+}
@@ -0,0 +1,197 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestPolicyResolution(t *testing.T) {
+	t.Cleanup(func() { SetDefaultPolicy(Policy{}) })
+
+	if got := PolicyFromContext(context.Background()); got != (Policy{}) {
+		t.Errorf("expected zero policy, got %+v", got)
+	}
+
+	SetDefaultPolicy(Policy{MaxTotalAttempts: 10})
+	if got := PolicyFromContext(context.Background()).MaxTotalAttempts; got != 10 {
+		t.Errorf("expected process default, got %d", got)
+	}
+
+	ctx := WithPolicy(context.Background(), Policy{MaxTotalAttempts: 3})
+	if got := PolicyFromContext(ctx).MaxTotalAttempts; got != 3 {
+		t.Errorf("expected context override, got %d", got)
+	}
+}
+
+func TestPolicyMaxTotalAttempts(t *testing.T) {
+	var calls atomic.Int32
+	failing := Apply(NewIdentity("flaky", ""), func(_ context.Context, v int) (int, error) {
+		calls.Add(1)
+		return v, errors.New("unavailable")
+	})
+
+	t.Run("Caps Nested Retries", func(t *testing.T) {
+		calls.Store(0)
+		inner := NewRetry(NewIdentity("inner", ""), failing, 5)
+		outer := NewRetry(NewIdentity("outer", ""), inner, 4)
+
+		ctx := WithPolicy(context.Background(), Policy{MaxTotalAttempts: 8})
+		if _, err := outer.Process(ctx, 1); err == nil {
+			t.Fatal("expected error")
+		}
+		// Outer gets 4 attempts; inner is capped to 8/4 = 2 per outer attempt.
+		if got := calls.Load(); got != 8 {
+			t.Errorf("expected 8 total calls, got %d", got)
+		}
+	})
+
+	t.Run("Unlimited Without Policy", func(t *testing.T) {
+		calls.Store(0)
+		inner := NewRetry(NewIdentity("inner", ""), failing, 5)
+		outer := NewRetry(NewIdentity("outer", ""), inner, 4)
+
+		if _, err := outer.Process(context.Background(), 1); err == nil {
+			t.Fatal("expected error")
+		}
+		if got := calls.Load(); got != 20 {
+			t.Errorf("expected 20 total calls, got %d", got)
+		}
+	})
+
+	t.Run("Applies To Backoff", func(t *testing.T) {
+		calls.Store(0)
+		backoff := NewBackoff(NewIdentity("backoff", ""), failing, 5, time.Millisecond)
+
+		ctx := WithPolicy(context.Background(), Policy{MaxTotalAttempts: 2})
+		if _, err := backoff.Process(ctx, 1); err == nil {
+			t.Fatal("expected error")
+		}
+		if got := calls.Load(); got != 2 {
+			t.Errorf("expected 2 calls, got %d", got)
+		}
+	})
+
+	t.Run("Emits Violation Signal", func(t *testing.T) {
+		var rule string
+		var requested, allowed int
+		listener := capitan.Hook(SignalPolicyViolation, func(_ context.Context, e *capitan.Event) {
+			rule, _ = FieldPolicyRule.From(e)
+			requested, _ = FieldRequested.From(e)
+			allowed, _ = FieldAllowed.From(e)
+		})
+		defer listener.Close()
+
+		retry := NewRetry(NewIdentity("retry", ""), failing, 6)
+		ctx := WithPolicy(context.Background(), Policy{MaxTotalAttempts: 4})
+		_, _ = retry.Process(ctx, 1) //nolint:errcheck // failure expected
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if rule != PolicyRuleMaxTotalAttempts || requested != 6 || allowed != 4 {
+			t.Errorf("unexpected violation fields: %q %d %d", rule, requested, allowed)
+		}
+	})
+}
+
+func TestPolicyMaxConcurrency(t *testing.T) {
+	var active, peak atomic.Int32
+	track := func(name string) Chainable[TestData] {
+		return Apply(NewIdentity(name, ""), func(_ context.Context, d TestData) (TestData, error) {
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			active.Add(-1)
+			return d, nil
+		})
+	}
+	processors := []Chainable[TestData]{track("a"), track("b"), track("c"), track("d")}
+	ctx := WithPolicy(context.Background(), Policy{MaxConcurrency: 2})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		peak.Store(0)
+		c := NewConcurrent(NewIdentity("concurrent", ""), nil, processors...)
+		if _, err := c.Process(ctx, TestData{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := peak.Load(); got > 2 {
+			t.Errorf("expected at most 2 parallel, got %d", got)
+		}
+	})
+
+	t.Run("Group", func(t *testing.T) {
+		peak.Store(0)
+		g := NewGroup(NewIdentity("group", ""), processors...)
+		if _, err := g.Process(ctx, TestData{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := peak.Load(); got > 2 {
+			t.Errorf("expected at most 2 parallel, got %d", got)
+		}
+	})
+}
+
+func TestPipelinePolicy(t *testing.T) {
+	t.Cleanup(func() { SetDefaultPolicy(Policy{}) })
+
+	var deadline time.Time
+	var resolved Policy
+	probe := Effect(NewIdentity("probe", ""), func(ctx context.Context, _ int) error {
+		deadline, _ = ctx.Deadline()
+		resolved = PolicyFromContext(ctx)
+		return nil
+	})
+
+	t.Run("Applies Default Timeout", func(t *testing.T) {
+		SetDefaultPolicy(Policy{DefaultTimeout: time.Minute})
+		pipeline := NewPipeline(NewIdentity("pipeline", ""), probe)
+
+		deadline = time.Time{}
+		if _, err := pipeline.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if deadline.IsZero() {
+			t.Error("expected default timeout deadline")
+		}
+	})
+
+	t.Run("Keeps Caller Deadline", func(t *testing.T) {
+		SetDefaultPolicy(Policy{DefaultTimeout: time.Hour})
+		pipeline := NewPipeline(NewIdentity("pipeline", ""), probe)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		want, _ := ctx.Deadline()
+		if _, err := pipeline.Process(ctx, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !deadline.Equal(want) {
+			t.Errorf("expected caller deadline %v, got %v", want, deadline)
+		}
+	})
+
+	t.Run("Override Takes Precedence", func(t *testing.T) {
+		SetDefaultPolicy(Policy{MaxTotalAttempts: 10})
+		pipeline := NewPipeline(NewIdentity("pipeline", ""), probe).SetPolicy(Policy{MaxTotalAttempts: 2})
+
+		if _, err := pipeline.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resolved.MaxTotalAttempts != 2 {
+			t.Errorf("expected pipeline policy, got %+v", resolved)
+		}
+		if p, ok := pipeline.Policy(); !ok || p.MaxTotalAttempts != 2 {
+			t.Errorf("unexpected Policy(): %+v %v", p, ok)
+		}
+	})
+}
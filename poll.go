@@ -0,0 +1,242 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// ErrPollExpired is wrapped by errors from a Poll whose condition did not
+// hold before its maximum wait.
+var ErrPollExpired = errors.New("poll condition not met")
+
+// Poll waits for an external process to finish by repeatedly running a
+// status check until a condition holds. Each check receives the previous
+// check's result, so it can refresh the item's status in place, and Poll
+// returns the first result satisfying until.
+//
+// Checks run interval apart until maxWait has passed since the first one,
+// with a final check at the deadline. If the condition still does not hold,
+// Poll fails with an error wrapping ErrPollExpired that carries the last
+// result. A maxWait of zero or less polls until the context ends. An error
+// from the check fails the poll immediately; wrap the check in Retry or
+// Backoff to ride out transient failures.
+//
+// Example:
+//
+//	var SettlementPollID = pipz.NewIdentity("settlement-poll", "Waits for the payment to settle")
+//	settled := pipz.NewPoll(SettlementPollID, fetchPaymentStatus,
+//	    func(p Payment) bool { return p.Status == "settled" },
+//	    2*time.Second, 5*time.Minute,
+//	)
+type Poll[T any] struct {
+	check     Chainable[T]
+	until     func(T) bool
+	clock     clockz.Clock
+	identity  Identity
+	interval  time.Duration
+	maxWait   time.Duration
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewPoll creates a Poll running check every interval until until reports
+// true for its result or maxWait expires.
+func NewPoll[T any](identity Identity, check Chainable[T], until func(T) bool, interval, maxWait time.Duration) *Poll[T] {
+	return &Poll[T]{
+		identity: identity,
+		check:    check,
+		until:    until,
+		interval: interval,
+		maxWait:  maxWait,
+	}
+}
+
+// Process implements the Chainable interface.
+func (p *Poll[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, p.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, p, p.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	p.mu.RLock()
+	check := p.check
+	until := p.until
+	interval := p.interval
+	maxWait := p.maxWait
+	clock := p.getClock()
+	p.mu.RUnlock()
+
+	start := clock.Now()
+	current := data
+	for attempt := 1; ; attempt++ {
+		result, err := check.Process(ctx, current)
+		if err != nil {
+			if isControl(err) {
+				return result, err
+			}
+			var pipeErr *Error[T]
+			if errors.As(err, &pipeErr) {
+				pipeErr.prependPath(ctx, p.identity)
+				return result, pipeErr
+			}
+			return result, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(current),
+				Err:       err,
+				Path:      []Identity{p.identity},
+			}
+		}
+		if until(result) {
+			return result, nil
+		}
+		current = result
+
+		// Wait for the next check, shortened to land on the deadline
+		wait := interval
+		elapsed := clock.Since(start)
+		if maxWait > 0 {
+			if elapsed >= maxWait {
+				capitan.Warn(ctx, SignalPollExpired,
+					FieldName.Field(p.identity.Name()),
+					FieldIdentityID.Field(p.identity.ID().String()),
+					FieldAttempt.Field(attempt),
+					FieldElapsed.Field(elapsed.Seconds()),
+				)
+				return current, &Error[T]{
+					Timestamp: time.Now(),
+					InputData: errorInput(current),
+					Err:       ErrPollExpired,
+					Path:      []Identity{p.identity},
+					Timeout:   true,
+					Duration:  elapsed,
+				}
+			}
+			wait = min(wait, maxWait-elapsed)
+		}
+
+		capitan.Debug(ctx, SignalPollWaiting,
+			FieldName.Field(p.identity.Name()),
+			FieldIdentityID.Field(p.identity.ID().String()),
+			FieldAttempt.Field(attempt),
+			FieldElapsed.Field(elapsed.Seconds()),
+			FieldDelay.Field(wait.Seconds()),
+		)
+
+		select {
+		case <-clock.After(wait):
+		case <-ctx.Done():
+			return current, &Error[T]{
+				Err:       ctx.Err(),
+				InputData: errorInput(current),
+				Path:      []Identity{p.identity},
+				Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
+				Canceled:  errors.Is(ctx.Err(), context.Canceled),
+				Timestamp: time.Now(),
+			}
+		}
+	}
+}
+
+// SetInterval sets the time between checks.
+func (p *Poll[T]) SetInterval(interval time.Duration) *Poll[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.interval = interval
+	return p
+}
+
+// SetMaxWait sets how long to poll before giving up. Zero or less polls
+// until the context ends.
+func (p *Poll[T]) SetMaxWait(maxWait time.Duration) *Poll[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxWait = maxWait
+	return p
+}
+
+// SetCheck updates the status check processor.
+func (p *Poll[T]) SetCheck(check Chainable[T]) *Poll[T] {
+	noteChildren(p.identity, check)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.check = check
+	return p
+}
+
+// SetUntil updates the condition that ends polling.
+func (p *Poll[T]) SetUntil(until func(T) bool) *Poll[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.until = until
+	return p
+}
+
+// GetInterval returns the time between checks.
+func (p *Poll[T]) GetInterval() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.interval
+}
+
+// GetMaxWait returns how long to poll before giving up.
+func (p *Poll[T]) GetMaxWait() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.maxWait
+}
+
+// WithClock sets a custom clock for testing.
+func (p *Poll[T]) WithClock(clock clockz.Clock) *Poll[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clock = clock
+	return p
+}
+
+// getClock returns the clock to use.
+func (p *Poll[T]) getClock() clockz.Clock {
+	if p.clock == nil {
+		return clockz.RealClock
+	}
+	return p.clock
+}
+
+// Identity returns the identity of this connector.
+func (p *Poll[T]) Identity() Identity {
+	return p.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (p *Poll[T]) Schema() Node {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return Node{
+		Identity: p.identity,
+		Type:     "poll",
+		Flow:     PollFlow{Check: p.check.Schema()},
+		Metadata: map[string]any{
+			"interval": p.interval.String(),
+			"max_wait": p.maxWait.String(),
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its status check.
+// Close is idempotent - multiple calls return the same result.
+func (p *Poll[T]) Close() error {
+	p.closeOnce.Do(func() {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		p.closeErr = p.check.Close()
+	})
+	return p.closeErr
+}
@@ -0,0 +1,82 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPoll(t *testing.T) {
+	// countUp increments the item on each check, standing in for a status
+	// that advances until the external process finishes.
+	countUp := Transform(testIdentity("status"), func(_ context.Context, n int) int { return n + 1 })
+	reaches := func(target int) func(int) bool {
+		return func(n int) bool { return n >= target }
+	}
+
+	t.Run("Polls Until Condition", func(t *testing.T) {
+		poll := NewPoll(testIdentity("poll"), countUp, reaches(5), time.Millisecond, time.Second)
+		result, err := poll.Process(context.Background(), 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != 5 {
+			t.Errorf("expected 5 checks, got %d", result)
+		}
+	})
+
+	t.Run("Expires", func(t *testing.T) {
+		poll := NewPoll(testIdentity("poll"), countUp, reaches(1<<30), 2*time.Millisecond, 20*time.Millisecond)
+		result, err := poll.Process(context.Background(), 0)
+		if !errors.Is(err, ErrPollExpired) {
+			t.Fatalf("expected ErrPollExpired, got %v", err)
+		}
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.IsTimeout() || pipeErr.InputData != result {
+			t.Errorf("expected a timeout carrying the last result, got %v", err)
+		}
+		if result < 2 {
+			t.Errorf("expected several checks before expiring, got %d", result)
+		}
+	})
+
+	t.Run("Check Error Stops Polling", func(t *testing.T) {
+		checks := 0
+		failing := Apply(testIdentity("status"), func(_ context.Context, n int) (int, error) {
+			checks++
+			return n, errors.New("status unavailable")
+		})
+		_, err := NewPoll(testIdentity("poll"), failing, reaches(1), time.Millisecond, time.Second).Process(context.Background(), 0)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "poll" {
+			t.Errorf("expected path through poll, got %v", err)
+		}
+		if checks != 1 {
+			t.Errorf("expected 1 check, got %d", checks)
+		}
+	})
+
+	t.Run("Context Cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		poll := NewPoll(testIdentity("poll"), countUp, reaches(1<<30), time.Hour, 0)
+		_, err := poll.Process(ctx, 0)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := NewPoll(testIdentity("poll"), countUp, reaches(1), time.Second, time.Minute).Schema()
+		if node.Type != "poll" {
+			t.Errorf("expected poll type, got %s", node.Type)
+		}
+		if _, ok := node.Flow.(PollFlow); !ok {
+			t.Errorf("expected PollFlow, got %T", node.Flow)
+		}
+		if node.Metadata["interval"] != "1s" || node.Metadata["max_wait"] != "1m0s" {
+			t.Errorf("unexpected metadata: %v", node.Metadata)
+		}
+	})
+}
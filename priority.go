@@ -0,0 +1,101 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPriority is returned by ParsePriority for names that are not a
+// priority class.
+var ErrInvalidPriority = errors.New("invalid priority")
+
+// Priority is the QoS class of an item, carried on its context with
+// WithPriority so connectors anywhere in the pipeline can favor important
+// work under load. Higher classes compare greater; the zero value is
+// PriorityNormal, so items without a priority are treated as normal.
+//
+// Connectors that honor priority:
+//   - Shed never sheds critical items and sheds bulk items first.
+//   - RateLimiter never drops critical items, making them wait for a token
+//     instead, and holds back reserved tokens from bulk items.
+//   - WorkerPool holds back reserved workers from bulk items.
+type Priority int
+
+// Priority classes.
+const (
+	// PriorityBulk marks deferrable background work, the first to be
+	// shed or throttled.
+	PriorityBulk Priority = -1
+	// PriorityNormal is the default for items without a priority.
+	PriorityNormal Priority = 0
+	// PriorityHigh marks work that should be favored over normal traffic.
+	PriorityHigh Priority = 1
+	// PriorityCritical marks work that must never be shed, such as
+	// payments or health checks.
+	PriorityCritical Priority = 2
+)
+
+// priorities lists the priority classes from lowest to highest.
+var priorities = []Priority{PriorityBulk, PriorityNormal, PriorityHigh, PriorityCritical}
+
+// String returns the priority's name, such as "bulk" or "critical".
+func (p Priority) String() string {
+	switch p {
+	case PriorityBulk:
+		return "bulk"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	}
+	return fmt.Sprintf("priority(%d)", int(p))
+}
+
+// class returns the defined priority class nearest p.
+func (p Priority) class() Priority {
+	return min(max(p, PriorityBulk), PriorityCritical)
+}
+
+// ParsePriority returns the priority class named s, such as "bulk" read
+// from a request header or config file. Unknown names return an error
+// wrapping ErrInvalidPriority that lists the valid names.
+func ParsePriority(s string) (Priority, error) {
+	names := make([]string, len(priorities))
+	for i, p := range priorities {
+		if p.String() == s {
+			return p, nil
+		}
+		names[i] = p.String()
+	}
+	return PriorityNormal, fmt.Errorf("%w: %q: must be one of %s", ErrInvalidPriority, s, modeNames(names))
+}
+
+// priorityKey is the context key for an item's priority.
+type priorityKey struct{}
+
+// WithPriority returns a context carrying priority for everything
+// processed with it.
+//
+// Example:
+//
+//	// Nightly exports yield to interactive traffic
+//	ctx = pipz.WithPriority(ctx, pipz.PriorityBulk)
+//	_, err := pipeline.Process(ctx, export)
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFromContext returns the priority carried by ctx, or
+// PriorityNormal if there is none.
+func PriorityFromContext(ctx context.Context) Priority {
+	if ctx == nil {
+		return PriorityNormal
+	}
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityNormal
+}
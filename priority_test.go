@@ -0,0 +1,173 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+func TestPriority(t *testing.T) {
+	t.Run("Defaults To Normal", func(t *testing.T) {
+		if p := PriorityFromContext(context.Background()); p != PriorityNormal {
+			t.Errorf("expected normal, got %v", p)
+		}
+		ctx := WithPriority(context.Background(), PriorityBulk)
+		if p := PriorityFromContext(ctx); p != PriorityBulk {
+			t.Errorf("expected bulk, got %v", p)
+		}
+	})
+
+	t.Run("Parse And String", func(t *testing.T) {
+		for _, p := range []Priority{PriorityBulk, PriorityNormal, PriorityHigh, PriorityCritical} {
+			parsed, err := ParsePriority(p.String())
+			if err != nil || parsed != p {
+				t.Errorf("round trip of %v gave %v (%v)", p, parsed, err)
+			}
+		}
+		_, err := ParsePriority("urgent")
+		if !errors.Is(err, ErrInvalidPriority) {
+			t.Fatalf("expected ErrInvalidPriority, got %v", err)
+		}
+		if want := `invalid priority: "urgent": must be one of "bulk", "normal", "high", "critical"`; err.Error() != want {
+			t.Errorf("expected %q, got %q", want, err.Error())
+		}
+		if s := Priority(7).String(); s != "priority(7)" {
+			t.Errorf("unexpected name %q", s)
+		}
+	})
+}
+
+func TestRateLimiterPriority(t *testing.T) {
+	t.Run("Critical Items Wait Instead Of Dropping", func(t *testing.T) {
+		limiter := NewRateLimiter(testIdentity("limiter"), 100, 1, passthroughInt()).SetMode(RateLimitDrop)
+		if _, err := limiter.Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := limiter.Process(context.Background(), 2); !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("expected normal item to be dropped, got %v", err)
+		}
+
+		ctx := WithPriority(context.Background(), PriorityCritical)
+		if _, err := limiter.Process(ctx, 3); err != nil {
+			t.Errorf("expected critical item to wait for a token, got %v", err)
+		}
+	})
+
+	t.Run("Reserve Holds Tokens Back From Bulk", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		limiter := NewRateLimiter(testIdentity("limiter"), 1, 5, passthroughInt()).
+			WithClock(clock).
+			SetMode(RateLimitDrop).
+			SetReserve(2)
+		bulk := WithPriority(context.Background(), PriorityBulk)
+
+		// Bulk items take tokens only while more than 2 remain
+		for i := 0; i < 3; i++ {
+			if _, err := limiter.Process(bulk, i); err != nil {
+				t.Fatalf("bulk item %d: unexpected error: %v", i, err)
+			}
+		}
+		if _, err := limiter.Process(bulk, 3); !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("expected bulk item to be dropped at the reserve, got %v", err)
+		}
+
+		// The reserve still serves normal traffic
+		for i := 0; i < 2; i++ {
+			if _, err := limiter.Process(context.Background(), i); err != nil {
+				t.Errorf("normal item %d: unexpected error: %v", i, err)
+			}
+		}
+		if limiter.GetReserve() != 2 {
+			t.Errorf("expected reserve 2, got %d", limiter.GetReserve())
+		}
+	})
+
+	t.Run("Reserve Capped Below Burst", func(t *testing.T) {
+		limiter := NewRateLimiter(testIdentity("limiter"), 1, 2, passthroughInt()).
+			SetMode(RateLimitDrop).
+			SetReserve(10)
+		bulk := WithPriority(context.Background(), PriorityBulk)
+		if _, err := limiter.Process(bulk, 1); err != nil {
+			t.Errorf("expected bulk item to use a full bucket, got %v", err)
+		}
+	})
+}
+
+func TestWorkerPoolPriority(t *testing.T) {
+	t.Run("Reserve Limits Bulk Concurrency", func(t *testing.T) {
+		var mu sync.Mutex
+		active := 0
+		release := make(chan struct{})
+		slow := Effect(testIdentity("slow"), func(_ context.Context, _ clonableInt) error {
+			mu.Lock()
+			active++
+			mu.Unlock()
+			<-release
+			mu.Lock()
+			active--
+			mu.Unlock()
+			return nil
+		})
+		pool := NewWorkerPool(testIdentity("pool"), 3, slow, slow, slow).SetReserve(2)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := pool.Process(WithPriority(context.Background(), PriorityBulk), clonableInt(1))
+			done <- err
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		bulkActive := active
+		mu.Unlock()
+		if bulkActive != 1 {
+			t.Errorf("expected 1 bulk task at a time, got %d", bulkActive)
+		}
+
+		// Normal items can still use the reserved workers
+		normalDone := make(chan error, 1)
+		go func() {
+			_, err := pool.Process(context.Background(), clonableInt(2))
+			normalDone <- err
+		}()
+		time.Sleep(20 * time.Millisecond)
+		if pool.GetActiveWorkers() != 3 {
+			t.Errorf("expected all 3 workers active, got %d", pool.GetActiveWorkers())
+		}
+
+		close(release)
+		if err := <-done; err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := <-normalDone; err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Normal Items Use All Workers", func(t *testing.T) {
+		var mu sync.Mutex
+		active, peak := 0, 0
+		slow := Effect(testIdentity("slow"), func(_ context.Context, _ clonableInt) error {
+			mu.Lock()
+			active++
+			peak = max(peak, active)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			active--
+			mu.Unlock()
+			return nil
+		})
+		pool := NewWorkerPool(testIdentity("pool"), 3, slow, slow, slow).SetReserve(2)
+		if _, err := pool.Process(context.Background(), clonableInt(1)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if peak != 3 {
+			t.Errorf("expected 3 concurrent tasks, got %d", peak)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package pipz
+
+import (
+	"sync"
+	"time"
+)
+
+// Profile is a named bundle of resilience settings, so a codebase can
+// standardize how it retries, times out, and breaks circuits instead of
+// choosing numbers at every call site. WithProfile applies a profile to any
+// Chainable.
+//
+// Zero fields disable their layer: no Timeout means attempts are not
+// bounded, MaxAttempts below two means no retries, and a zero
+// FailureThreshold means no circuit breaker.
+type Profile struct {
+	// Name identifies the profile and prefixes the identities of the
+	// connectors WithProfile creates.
+	Name string
+	// Timeout bounds each attempt.
+	Timeout time.Duration
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// BaseDelay is the first backoff delay, doubling per attempt. Zero
+	// retries immediately.
+	BaseDelay time.Duration
+	// FailureThreshold is the number of consecutive failed attempts that
+	// opens the circuit breaker.
+	FailureThreshold int
+	// ResetTimeout is how long the breaker stays open before probing.
+	ResetTimeout time.Duration
+}
+
+// Built-in profiles.
+var (
+	// ProfileFastInteractive suits calls on a user-facing request path:
+	// short attempts and a single immediate retry, failing fast rather
+	// than making the user wait.
+	ProfileFastInteractive = Profile{
+		Name:             "fast-interactive",
+		Timeout:          500 * time.Millisecond,
+		MaxAttempts:      2,
+		FailureThreshold: 10,
+		ResetTimeout:     10 * time.Second,
+	}
+
+	// ProfileBackgroundBatch suits background jobs where latency matters
+	// less than finishing: long attempts and patient retries.
+	ProfileBackgroundBatch = Profile{
+		Name:        "background-batch",
+		Timeout:     5 * time.Minute,
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+	}
+
+	// ProfileExternalFlaky suits unreliable third-party dependencies:
+	// backed-off retries behind a breaker that stops hammering the
+	// dependency while it is down.
+	ProfileExternalFlaky = Profile{
+		Name:             "external-flaky",
+		Timeout:          10 * time.Second,
+		MaxAttempts:      4,
+		BaseDelay:        200 * time.Millisecond,
+		FailureThreshold: 5,
+		ResetTimeout:     30 * time.Second,
+	}
+)
+
+// profiles is the registry consulted by LookupProfile.
+var profiles = struct {
+	byName map[string]Profile
+	mu     sync.RWMutex
+}{
+	byName: map[string]Profile{
+		ProfileFastInteractive.Name: ProfileFastInteractive,
+		ProfileBackgroundBatch.Name: ProfileBackgroundBatch,
+		ProfileExternalFlaky.Name:   ProfileExternalFlaky,
+	},
+}
+
+// RegisterProfile adds profile to the registry under its name, replacing
+// any profile already registered with that name, including the built-ins.
+// Registering at start-up lets a service tune a profile in one place for
+// every pipeline that looks it up.
+func RegisterProfile(profile Profile) {
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	profiles.byName[profile.Name] = profile
+}
+
+// LookupProfile returns the registered profile with the given name.
+func LookupProfile(name string) (Profile, bool) {
+	profiles.mu.RLock()
+	defer profiles.mu.RUnlock()
+	profile, ok := profiles.byName[name]
+	return profile, ok
+}
+
+// WithProfile wraps inner in the connectors profile describes, from the
+// inside out: a Timeout bounding each attempt, a CircuitBreaker counting
+// failed attempts, and a Retry, or a Backoff when BaseDelay is set,
+// around both. Retrying outside the breaker means an open breaker fails
+// the remaining attempts fast instead of calling the dependency. Layers
+// whose settings are zero are left out, so a zero Profile returns inner
+// unchanged.
+//
+// Each connector is named after inner and the profile, such as
+// "charge.external-flaky.backoff". The breaker is stateful, so build the
+// wrapped chain once and reuse it.
+//
+// Example:
+//
+//	var charge = pipz.WithProfile(pipz.Apply(ChargeID, callStripe), pipz.ProfileExternalFlaky)
+//
+//	// Or with a profile tuned centrally via RegisterProfile
+//	profile, _ := pipz.LookupProfile("external-flaky")
+//	charge := pipz.WithProfile(pipz.Apply(ChargeID, callStripe), profile)
+func WithProfile[T any](inner Chainable[T], profile Profile) Chainable[T] {
+	name := func(layer string) Identity {
+		return NewIdentity(inner.Identity().Name()+"."+profile.Name+"."+layer,
+			"Applies the "+profile.Name+" "+layer+" to "+inner.Identity().Name())
+	}
+
+	wrapped := inner
+	if profile.Timeout > 0 {
+		wrapped = NewTimeout(name("timeout"), wrapped, profile.Timeout)
+	}
+	if profile.FailureThreshold > 0 {
+		wrapped = NewCircuitBreaker(name("breaker"), wrapped, profile.FailureThreshold, profile.ResetTimeout)
+	}
+	if profile.MaxAttempts > 1 {
+		if profile.BaseDelay > 0 {
+			wrapped = NewBackoff(name("backoff"), wrapped, profile.MaxAttempts, profile.BaseDelay)
+		} else {
+			wrapped = NewRetry(name("retry"), wrapped, profile.MaxAttempts)
+		}
+	}
+	return wrapped
+}
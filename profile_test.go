@@ -0,0 +1,96 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProfile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Layers Nest Inside Out", func(t *testing.T) {
+		inner := Transform(NewIdentity("charge", ""), func(_ context.Context, n int) int { return n })
+		wrapped := WithProfile(inner, ProfileExternalFlaky)
+
+		node := wrapped.Schema()
+		var types, names []string
+		for {
+			types = append(types, node.Type)
+			names = append(names, node.Identity.Name())
+			children := nodeChildren(node)
+			if len(children) == 0 {
+				break
+			}
+			node = children[0]
+		}
+		want := []string{"backoff", "circuitbreaker", "timeout", "processor"}
+		if len(types) != len(want) {
+			t.Fatalf("types = %v, want %v", types, want)
+		}
+		for i := range want {
+			if types[i] != want[i] {
+				t.Fatalf("types = %v, want %v", types, want)
+			}
+		}
+		if names[0] != "charge.external-flaky.backoff" {
+			t.Errorf("outer name = %q", names[0])
+		}
+	})
+
+	t.Run("Zero Profile Returns Inner", func(t *testing.T) {
+		inner := Transform(NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })
+		if got := WithProfile(inner, Profile{Name: "none"}); got.Identity() != inner.Identity() {
+			t.Errorf("expected inner returned unchanged, got %q", got.Identity().Name())
+		}
+	})
+
+	t.Run("Retries Then Opens Breaker", func(t *testing.T) {
+		calls := 0
+		failing := Apply(NewIdentity("flaky", ""), func(_ context.Context, n int) (int, error) {
+			calls++
+			return n, errors.New("unavailable")
+		})
+		wrapped := WithProfile(failing, Profile{Name: "test", MaxAttempts: 3, FailureThreshold: 2, ResetTimeout: time.Minute})
+
+		if _, err := wrapped.Process(ctx, 1); err == nil {
+			t.Fatal("expected failure")
+		}
+		if calls != 2 {
+			t.Errorf("expected the breaker to stop the third attempt, got %d calls", calls)
+		}
+		_, err := wrapped.Process(ctx, 1)
+		if !errors.Is(err, ErrCircuitOpen) || calls != 2 {
+			t.Errorf("expected open breaker without calls, got %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("Timeout Bounds Attempts", func(t *testing.T) {
+		slow := Apply(NewIdentity("slow", ""), func(ctx context.Context, n int) (int, error) {
+			<-ctx.Done()
+			return n, ctx.Err()
+		})
+		_, err := WithProfile(slow, Profile{Name: "test", Timeout: 5 * time.Millisecond}).Process(ctx, 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.IsTimeout() {
+			t.Errorf("expected timeout, got %v", err)
+		}
+	})
+
+	t.Run("Registry", func(t *testing.T) {
+		if p, ok := LookupProfile("fast-interactive"); !ok || p != ProfileFastInteractive {
+			t.Errorf("built-in lookup = %+v, %v", p, ok)
+		}
+		if _, ok := LookupProfile("missing"); ok {
+			t.Error("expected unknown profile to be missing")
+		}
+		tuned := ProfileExternalFlaky
+		tuned.MaxAttempts = 6
+		RegisterProfile(tuned)
+		t.Cleanup(func() { RegisterProfile(ProfileExternalFlaky) })
+		if p, _ := LookupProfile("external-flaky"); p.MaxAttempts != 6 {
+			t.Errorf("override not registered: %+v", p)
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// Terminal progress events sent by ProgressStream.Finish.
+const (
+	ProgressEventComplete = "complete"
+	ProgressEventFailed   = "failed"
+)
+
+// progressBuffer bounds the events queued for a slow client. Further events
+// are dropped and counted rather than blocking the pipeline.
+const progressBuffer = 64
+
+// progressKey marks a context as belonging to a ProgressStream.
+type progressKey struct{}
+
+// ProgressEvent is one status update sent to a client: a pipz signal
+// emitted while processing its request, or the terminal complete/failed
+// event.
+type ProgressEvent struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Fields    map[string]any `json:"fields,omitempty"`
+	Signal    string         `json:"signal"`
+	Severity  string         `json:"severity"`
+}
+
+// ProgressStream forwards the signals emitted while processing a single
+// request to that request's client, giving live step-by-step status of a
+// long pipeline run behind an HTTP endpoint. Events are correlated by
+// context: only signals emitted with the context returned by StreamProgress,
+// or one derived from it, are forwarded.
+//
+// Serve the stream as Server-Sent Events with ServeSSE, or read Events to
+// bridge it to a websocket. A slow client never blocks the pipeline; events
+// beyond the buffer are dropped and the count is reported in the terminal
+// event.
+//
+// Example:
+//
+//	func handleOrder(w http.ResponseWriter, r *http.Request) {
+//	    ctx, progress := pipz.StreamProgress(r.Context())
+//	    defer progress.Close()
+//
+//	    go func() {
+//	        _, err := orderPipeline.Process(ctx, decodeOrder(r))
+//	        progress.Finish(err)
+//	    }()
+//
+//	    _ = progress.ServeSSE(w, r)
+//	}
+type ProgressStream struct {
+	observer  *capitan.Observer
+	events    chan ProgressEvent
+	mu        sync.Mutex
+	dropped   atomic.Int64
+	closed    bool
+	closeOnce sync.Once
+}
+
+// StreamProgress starts a ProgressStream and returns the context to process
+// the request with. With no signals every pipz signal is forwarded;
+// otherwise only the listed ones are. Call Finish when processing ends, and
+// Close when the client goes away.
+func StreamProgress(ctx context.Context, signals ...capitan.Signal) (context.Context, *ProgressStream) {
+	s := &ProgressStream{
+		events: make(chan ProgressEvent, progressBuffer),
+	}
+	s.observer = capitan.Observe(s.forward, signals...)
+	return context.WithValue(ctx, progressKey{}, s), s
+}
+
+// forward queues events emitted for this stream's request.
+func (s *ProgressStream) forward(ctx context.Context, e *capitan.Event) {
+	if stream, ok := ctx.Value(progressKey{}).(*ProgressStream); !ok || stream != s {
+		return
+	}
+
+	fields := SignalFields(e)
+	event := ProgressEvent{
+		Signal:    e.Signal().Name(),
+		Severity:  string(e.Severity()),
+		Timestamp: e.Timestamp(),
+	}
+	if len(fields) > 0 {
+		event.Fields = make(map[string]any, len(fields))
+		for _, f := range fields {
+			value := f.Value()
+			if err, ok := value.(error); ok {
+				value = err.Error()
+			}
+			event.Fields[f.Key().Name()] = value
+		}
+	}
+	s.send(event, false)
+}
+
+// send queues event without blocking. One slot is kept free for the
+// terminal event.
+func (s *ProgressStream) send(event ProgressEvent, terminal bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if !terminal && len(s.events) >= cap(s.events)-1 {
+		s.dropped.Add(1)
+		return
+	}
+	s.events <- event
+}
+
+// Events returns the channel of progress events, closed after the terminal
+// event or on Close. Use it to bridge the stream to a websocket.
+func (s *ProgressStream) Events() <-chan ProgressEvent {
+	return s.events
+}
+
+// Finish waits for signals already emitted for the request to be
+// forwarded, then sends a terminal event, complete or failed with the
+// error, and ends the stream. It is safe to call once processing returns;
+// later calls do nothing.
+func (s *ProgressStream) Finish(err error) {
+	_ = s.observer.Drain(context.Background()) //nolint:errcheck // background context never ends
+
+	event := ProgressEvent{
+		Signal:    ProgressEventComplete,
+		Severity:  string(capitan.SeverityInfo),
+		Timestamp: time.Now(),
+	}
+	if err != nil {
+		event.Signal = ProgressEventFailed
+		event.Severity = string(capitan.SeverityError)
+		event.Fields = map[string]any{"error": err.Error()}
+		var categorized interface{ Category() string }
+		if errors.As(err, &categorized) {
+			event.Fields["category"] = categorized.Category()
+		}
+	}
+	if dropped := s.dropped.Load(); dropped > 0 {
+		if event.Fields == nil {
+			event.Fields = make(map[string]any, 1)
+		}
+		event.Fields["dropped"] = dropped
+	}
+	s.send(event, true)
+	s.Close()
+}
+
+// Close stops forwarding signals and closes the Events channel without a
+// terminal event. Close is idempotent.
+func (s *ProgressStream) Close() {
+	s.closeOnce.Do(func() {
+		s.observer.Close()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closed = true
+		close(s.events)
+	})
+}
+
+// ServeSSE writes the stream to w as Server-Sent Events until the stream
+// ends or the client disconnects. Each event is sent with the signal name
+// as its event type and the ProgressEvent as JSON data:
+//
+//	event: retry.attempt-fail
+//	data: {"timestamp":"…","fields":{"attempt":1,…},"signal":"retry.attempt-fail","severity":"WARN"}
+//
+// It returns the request context's error if the client went away first, or
+// an error if w does not support flushing.
+func (s *ProgressStream) ServeSSE(w http.ResponseWriter, r *http.Request) error {
+	rc := http.NewResponseController(w)
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return fmt.Errorf("progress stream: %w", err)
+	}
+
+	for {
+		select {
+		case event, ok := <-s.events:
+			if !ok {
+				return nil
+			}
+			if err := writeSSE(w, event); err != nil {
+				return fmt.Errorf("progress stream: %w", err)
+			}
+			if err := rc.Flush(); err != nil {
+				return fmt.Errorf("progress stream: %w", err)
+			}
+		case <-r.Context().Done():
+			return r.Context().Err()
+		}
+	}
+}
+
+// writeSSE writes one event in text/event-stream format.
+func writeSSE(w http.ResponseWriter, event ProgressEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		// Fall back to the event without fields that cannot be encoded.
+		keys := make([]string, 0, len(event.Fields))
+		for k := range event.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		event.Fields = map[string]any{"unencodable_fields": keys}
+		data, err = json.Marshal(event)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Signal, data)
+	return err
+}
@@ -0,0 +1,128 @@
+package pipz
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestProgressStream(t *testing.T) {
+	flaky := func() Chainable[int] {
+		var calls atomic.Int32
+		return NewRetry(NewIdentity("retry", ""), Apply(NewIdentity("flaky", ""), func(_ context.Context, v int) (int, error) {
+			if calls.Add(1) == 1 {
+				return v, errors.New("transient")
+			}
+			return v, nil
+		}), 3)
+	}
+
+	t.Run("Forwards Request Signals Then Completes", func(t *testing.T) {
+		ctx, stream := StreamProgress(context.Background(), SignalRetryAttemptFail)
+		defer stream.Close()
+
+		// Another request's signals must not leak into this stream.
+		if _, err := flaky().Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err := flaky().Process(ctx, 1)
+		stream.Finish(err)
+
+		var events []ProgressEvent
+		for event := range stream.Events() {
+			events = append(events, event)
+		}
+		if len(events) != 2 {
+			t.Fatalf("expected 2 events, got %+v", events)
+		}
+		if events[0].Signal != SignalRetryAttemptFail.Name() || events[0].Fields["error"] == nil {
+			t.Errorf("unexpected first event: %+v", events[0])
+		}
+		if events[1].Signal != ProgressEventComplete {
+			t.Errorf("expected terminal complete event, got %+v", events[1])
+		}
+	})
+
+	t.Run("Failure Reports Error And Category", func(t *testing.T) {
+		ctx, stream := StreamProgress(context.Background(), SignalRetryAttemptFail)
+		failing := Apply(NewIdentity("failing", ""), func(_ context.Context, v int) (int, error) {
+			return v, errors.New("boom")
+		})
+		_, err := failing.Process(ctx, 1)
+		stream.Finish(err)
+
+		var last ProgressEvent
+		for event := range stream.Events() {
+			last = event
+		}
+		if last.Signal != ProgressEventFailed || last.Fields["category"] != ErrorCategoryError {
+			t.Errorf("unexpected terminal event: %+v", last)
+		}
+	})
+
+	t.Run("Slow Client Drops Without Blocking", func(t *testing.T) {
+		ctx, stream := StreamProgress(context.Background(), SignalRetryAttemptFail)
+		event := capitan.NewEvent(SignalRetryAttemptFail, capitan.SeverityWarn, time.Now())
+		for i := 0; i < progressBuffer+10; i++ {
+			stream.forward(ctx, event)
+		}
+		stream.Finish(nil)
+
+		var count int
+		var last ProgressEvent
+		for event := range stream.Events() {
+			count++
+			last = event
+		}
+		if count != progressBuffer || last.Signal != ProgressEventComplete || last.Fields["dropped"] != int64(11) {
+			t.Errorf("expected %d events ending in complete with 11 dropped, got %d (%+v)", progressBuffer, count, last)
+		}
+	})
+
+	t.Run("Serves Server Sent Events", func(t *testing.T) {
+		ctx, stream := StreamProgress(context.Background(), SignalRetryAttemptFail)
+		go func() {
+			_, err := flaky().Process(ctx, 1)
+			stream.Finish(err)
+		}()
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/orders/1/progress", nil)
+		if err := stream.ServeSSE(rec, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+			t.Errorf("expected event stream content type, got %q", got)
+		}
+
+		var types []string
+		scanner := bufio.NewScanner(rec.Body)
+		for scanner.Scan() {
+			if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				types = append(types, name)
+			}
+		}
+		if strings.Join(types, ",") != "retry.attempt-fail,complete" {
+			t.Errorf("unexpected event sequence: %v", types)
+		}
+	})
+
+	t.Run("Client Disconnect Ends Serving", func(t *testing.T) {
+		_, stream := StreamProgress(context.Background())
+		defer stream.Close()
+
+		reqCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req := httptest.NewRequest("GET", "/", nil).WithContext(reqCtx)
+		if err := stream.ServeSSE(httptest.NewRecorder(), req); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context canceled, got %v", err)
+		}
+	})
+}
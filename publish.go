@@ -0,0 +1,143 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Publisher sends an encoded payload to a topic on a message bus. Adapt
+// Kafka producers, NATS connections, or in-process buses to this interface
+// to publish from a pipeline.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface.
+type PublisherFunc func(ctx context.Context, topic string, payload []byte) error
+
+// Publish calls f.
+func (f PublisherFunc) Publish(ctx context.Context, topic string, payload []byte) error {
+	return f(ctx, topic, payload)
+}
+
+// Encoder converts data to a message payload.
+type Encoder[T any] func(T) ([]byte, error)
+
+// JSONEncoder returns an Encoder that marshals data as JSON.
+func JSONEncoder[T any]() Encoder[T] {
+	return func(v T) ([]byte, error) {
+		return json.Marshal(v)
+	}
+}
+
+// PublishError reports a failed publish with the resolved topic and encoded
+// payload, so an error handler can dead-letter or republish the message
+// without re-encoding it. Payload is nil when encoding failed.
+type PublishError struct {
+	Err     error
+	Topic   string
+	Payload []byte
+}
+
+// Error implements the error interface.
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("publish to %q: %v", e.Topic, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
+
+// Publish creates a Processor that emits data as a domain event. Data is
+// encoded with encode (JSON when nil) and sent to topic; the original data
+// passes through unchanged.
+//
+// The topic may be a text/template evaluated against the data, such as
+// "orders.{{.Region}}.created", to route events by their fields. A topic
+// that fails to parse fails every item with the parse error.
+//
+// Failures are returned as *Error[T] wrapping a *PublishError, so Publish
+// composes with Retry for transient broker errors and with Handle to route
+// undeliverable messages to a dead-letter queue.
+//
+// Example:
+//
+//	var PublishOrderID = pipz.NewIdentity("publish-order", "Emits order.created events")
+//	publish := pipz.NewRetry(RetryPublishID,
+//	    pipz.Publish(PublishOrderID, natsPublisher, "orders.{{.Region}}.created", nil),
+//	    3,
+//	)
+//
+//	deadLetter := pipz.NewHandle(DeadLetterID, publish,
+//	    pipz.Effect(SendToDLQID, func(ctx context.Context, e *pipz.Error[Order]) error {
+//	        var pubErr *pipz.PublishError
+//	        if errors.As(e, &pubErr) {
+//	            return dlq.Publish(ctx, "dlq."+pubErr.Topic, pubErr.Payload)
+//	        }
+//	        return nil
+//	    }),
+//	)
+func Publish[T any](identity Identity, publisher Publisher, topic string, encode Encoder[T]) Processor[T] {
+	if encode == nil {
+		encode = JSONEncoder[T]()
+	}
+
+	var tmpl *template.Template
+	var parseErr error
+	if strings.Contains(topic, "{{") {
+		tmpl, parseErr = template.New(identity.Name()).Option("missingkey=error").Parse(topic)
+		if parseErr != nil {
+			parseErr = fmt.Errorf("invalid topic template %q: %w", topic, parseErr)
+		}
+	}
+
+	return Processor[T]{
+		identity: identity,
+		fn: func(ctx context.Context, value T) (result T, err error) {
+			defer recoverFromPanic(&result, &err, identity, value)
+			start := time.Now()
+
+			if pubErr := publish(ctx, publisher, topic, tmpl, parseErr, encode, value); pubErr != nil {
+				return value, &Error[T]{
+					Path:      []Identity{identity},
+					InputData: errorInput(value),
+					Err:       pubErr,
+					Timestamp: time.Now(),
+					Duration:  time.Since(start),
+					Timeout:   errors.Is(pubErr, context.DeadlineExceeded),
+					Canceled:  errors.Is(pubErr, context.Canceled),
+				}
+			}
+			return value, nil
+		},
+	}
+}
+
+// publish resolves the topic, encodes value, and sends it.
+func publish[T any](ctx context.Context, publisher Publisher, topic string, tmpl *template.Template, parseErr error, encode Encoder[T], value T) error {
+	if parseErr != nil {
+		return parseErr
+	}
+	if tmpl != nil {
+		var sb strings.Builder
+		if execErr := tmpl.Execute(&sb, value); execErr != nil {
+			return fmt.Errorf("resolve topic %q: %w", topic, execErr)
+		}
+		topic = sb.String()
+	}
+
+	payload, err := encode(value)
+	if err != nil {
+		return &PublishError{Topic: topic, Err: fmt.Errorf("encode: %w", err)}
+	}
+	if sendErr := publisher.Publish(ctx, topic, payload); sendErr != nil {
+		return &PublishError{Topic: topic, Payload: payload, Err: sendErr}
+	}
+	return nil
+}
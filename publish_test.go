@@ -0,0 +1,96 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type publishedEvent struct {
+	Region string `json:"region"`
+	ID     int    `json:"id"`
+}
+
+type recordingPublisher struct {
+	err      error
+	topics   []string
+	payloads []string
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, topic string, payload []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.topics = append(p.topics, topic)
+	p.payloads = append(p.payloads, string(payload))
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	t.Run("Publishes JSON To Templated Topic", func(t *testing.T) {
+		pub := &recordingPublisher{}
+		proc := Publish[publishedEvent](NewIdentity("publish", ""), pub, "orders.{{.Region}}.created", nil)
+		in := publishedEvent{Region: "eu", ID: 7}
+		result, err := proc.Process(context.Background(), in)
+		if err != nil || result != in {
+			t.Fatalf("unexpected result: %+v, %v", result, err)
+		}
+		if len(pub.topics) != 1 || pub.topics[0] != "orders.eu.created" {
+			t.Fatalf("unexpected topics: %v", pub.topics)
+		}
+		if pub.payloads[0] != `{"region":"eu","id":7}` {
+			t.Errorf("unexpected payload: %s", pub.payloads[0])
+		}
+	})
+
+	t.Run("Static Topic And Custom Encoder", func(t *testing.T) {
+		var topic string
+		pub := PublisherFunc(func(_ context.Context, tp string, _ []byte) error {
+			topic = tp
+			return nil
+		})
+		proc := Publish(NewIdentity("publish", ""), pub, "events", func(s string) ([]byte, error) { return []byte(s), nil })
+		if _, err := proc.Process(context.Background(), "hi"); err != nil || topic != "events" {
+			t.Fatalf("unexpected result: %q, %v", topic, err)
+		}
+	})
+
+	t.Run("Publish Failure Carries Topic And Payload", func(t *testing.T) {
+		broker := errors.New("broker unavailable")
+		proc := Publish[publishedEvent](NewIdentity("publish", ""), &recordingPublisher{err: broker}, "orders.{{.Region}}", nil)
+		_, err := proc.Process(context.Background(), publishedEvent{Region: "us"})
+		if !errors.Is(err, broker) {
+			t.Fatalf("expected broker error, got %v", err)
+		}
+		var pubErr *PublishError
+		if !errors.As(err, &pubErr) || pubErr.Topic != "orders.us" || len(pubErr.Payload) == 0 {
+			t.Errorf("unexpected publish error: %+v", pubErr)
+		}
+	})
+
+	t.Run("Template Errors", func(t *testing.T) {
+		bad := Publish[publishedEvent](NewIdentity("publish", ""), &recordingPublisher{}, "orders.{{.Region", nil)
+		if _, err := bad.Process(context.Background(), publishedEvent{}); err == nil {
+			t.Error("expected parse error")
+		}
+		missing := Publish[publishedEvent](NewIdentity("publish", ""), &recordingPublisher{}, "orders.{{.Country}}", nil)
+		if _, err := missing.Process(context.Background(), publishedEvent{}); err == nil {
+			t.Error("expected execution error")
+		}
+	})
+
+	t.Run("Composes With Retry", func(t *testing.T) {
+		attempts := 0
+		pub := PublisherFunc(func(_ context.Context, _ string, _ []byte) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("transient")
+			}
+			return nil
+		})
+		retry := NewRetry(NewIdentity("retry", ""), Publish[int](NewIdentity("publish", ""), pub, "n", nil), 3)
+		if _, err := retry.Process(context.Background(), 1); err != nil || attempts != 3 {
+			t.Fatalf("expected success on third attempt, got %d attempts, %v", attempts, err)
+		}
+	})
+}
@@ -0,0 +1,353 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// Quarantine protects a pipeline from poison inputs: items that fail every
+// time, however often they are retried or redelivered. It counts failures
+// per item key and, once an item has failed threshold times, routes it to
+// the quarantine sink (a dead-letter queue, a review table) instead of the
+// processor. The quarantining call returns the sink's result without error,
+// so a consumer acknowledges the message instead of redelivering it
+// forever.
+//
+// Items are keyed by the key function, such as a message ID; without one
+// they are keyed by DeepHash of their content. A success clears the key's
+// count. Quarantined keys stay quarantined until Release, or until their
+// last failure is older than the window set with SetWindow.
+//
+// SetMaxKeys bounds the keys tracked, forgetting the least recently failed
+// first, and SetCompaction evicts expired keys in the background between
+// items; see KeyStats.
+//
+// Place Quarantine outside Retry and Backoff so one count covers a whole
+// delivery, or inside a redelivering consumer so each delivery counts.
+//
+// Quarantine retains only item keys and failure counts; the sink stores
+// the items. Have it apply the RetentionPolicy with ApplyRetention, so
+// dead-lettered items are redacted and sampled like everything else pipz
+// retains.
+//
+// CRITICAL: Quarantine is STATEFUL - it tracks failures across calls.
+// Create it once and reuse it.
+//
+// Example:
+//
+//	var PoisonID = pipz.NewIdentity("poison-guard", "Parks orders that fail three deliveries")
+//	guard := pipz.NewQuarantine(PoisonID, 3, processOrder, deadLetter).
+//	    SetKeyFunc(func(_ context.Context, m OrderMessage) string { return m.MessageID }).
+//	    SetWindow(24 * time.Hour)
+type Quarantine[T any] struct {
+	processor Chainable[T]
+	sink      Chainable[T]
+	key       func(context.Context, T) string
+	clock     clockz.Clock
+	failures  *keyedState[int]
+	compactor *compactor
+	identity  Identity
+	threshold int
+	mu        sync.Mutex
+	parked    atomic.Int64
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewQuarantine creates a Quarantine routing items that have failed
+// threshold times in processor to sink. A threshold below one is treated
+// as one.
+func NewQuarantine[T any](identity Identity, threshold int, processor, sink Chainable[T]) *Quarantine[T] {
+	return &Quarantine[T]{
+		identity:  identity,
+		threshold: max(threshold, 1),
+		processor: processor,
+		sink:      sink,
+		failures:  newKeyedState[int](),
+	}
+}
+
+// Process implements the Chainable interface.
+// Runs the processor, or the sink once the item is quarantined.
+func (q *Quarantine[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, q.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, q, q.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	q.mu.Lock()
+	processor := q.processor
+	keyFunc := q.key
+	threshold := q.threshold
+	q.mu.Unlock()
+
+	key := q.itemKey(ctx, keyFunc, data)
+	if failures := q.count(key); failures >= threshold {
+		return q.quarantine(ctx, data, key, failures, nil)
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err == nil {
+		q.mu.Lock()
+		q.failures.remove(key)
+		q.mu.Unlock()
+		return result, nil
+	}
+	if !isControl(err) {
+		if failures := q.record(ctx, key); failures >= threshold {
+			return q.quarantine(ctx, data, key, failures, err)
+		}
+	}
+
+	var pipeErr *Error[T]
+	if errors.As(err, &pipeErr) {
+		pipeErr.prependPath(ctx, q.identity)
+		return result, pipeErr
+	}
+	return result, &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(data),
+		Err:       err,
+		Path:      []Identity{q.identity},
+	}
+}
+
+// itemKey returns the item's key, hashing its content if there is no key
+// function.
+func (*Quarantine[T]) itemKey(ctx context.Context, keyFunc func(context.Context, T) string, data T) string {
+	if keyFunc != nil {
+		return keyFunc(ctx, data)
+	}
+	return strconv.FormatUint(DeepHash(data), 16)
+}
+
+// count returns the key's failures, ignoring them if they have expired.
+func (q *Quarantine[T]) count(key string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	failures, _ := q.failures.get(key, q.getClock().Now())
+	return failures
+}
+
+// record counts a failure of key and returns its total, evicting expired
+// keys and keys beyond the key limit.
+func (q *Quarantine[T]) record(ctx context.Context, key string) int {
+	q.mu.Lock()
+	now := q.getClock().Now()
+	failures, _ := q.failures.get(key, now)
+	failures++
+	expired, displaced := q.failures.put(key, failures, now)
+	keys := len(q.failures.entries)
+	q.mu.Unlock()
+	emitEvicted(ctx, q.identity, expired, displaced, keys)
+	return failures
+}
+
+// quarantine hands the item to the sink. cause is the failure that reached
+// the threshold, or nil for an item already quarantined.
+func (q *Quarantine[T]) quarantine(ctx context.Context, data T, key string, failures int, cause error) (T, error) {
+	q.mu.Lock()
+	sink := q.sink
+	q.mu.Unlock()
+
+	fields := []capitan.Field{
+		FieldName.Field(q.identity.Name()),
+		FieldIdentityID.Field(q.identity.ID().String()),
+		FieldItemKey.Field(key),
+		FieldFailures.Field(failures),
+	}
+	if cause != nil {
+		fields = append(fields, FieldError.Field(cause.Error()))
+	}
+	capitan.Warn(ctx, SignalQuarantined, fields...)
+
+	result, err := sink.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, q.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{q.identity},
+		}
+	}
+	q.parked.Add(1)
+	return result, nil
+}
+
+// Failures returns the number of recorded failures for key.
+func (q *Quarantine[T]) Failures(key string) int {
+	return q.count(key)
+}
+
+// Release clears key's failures, so its next item runs the processor
+// again. Returns false if the key had none.
+func (q *Quarantine[T]) Release(key string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.failures.remove(key)
+}
+
+// Quarantined returns the number of items handed to the sink.
+func (q *Quarantine[T]) Quarantined() int64 {
+	return q.parked.Load()
+}
+
+// SetThreshold updates how many failures quarantine an item. Values below
+// one are treated as one.
+func (q *Quarantine[T]) SetThreshold(threshold int) *Quarantine[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.threshold = max(threshold, 1)
+	return q
+}
+
+// SetWindow forgets a key's failures once its last failure is older than
+// window, releasing quarantined keys. Zero keeps failures until Release.
+func (q *Quarantine[T]) SetWindow(window time.Duration) *Quarantine[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.failures.ttl = window
+	return q
+}
+
+// SetMaxKeys bounds the number of keys tracked, forgetting the least
+// recently failed keys beyond it, which releases them if quarantined. Zero
+// tracks any number of keys.
+func (q *Quarantine[T]) SetMaxKeys(n int) *Quarantine[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.failures.maxKeys = max(n, 0)
+	return q
+}
+
+// SetCompaction runs Compact every interval in the background until Close,
+// so expired keys are evicted even when no items fail. Zero stops
+// background compaction.
+func (q *Quarantine[T]) SetCompaction(interval time.Duration) *Quarantine[T] {
+	q.mu.Lock()
+	previous := q.compactor
+	q.compactor = nil
+	if interval > 0 {
+		q.compactor = startCompactor(q.getClock(), interval, func() { q.Compact() })
+	}
+	q.mu.Unlock()
+	previous.Stop()
+	return q
+}
+
+// Compact evicts expired keys and keys beyond the key limit, returning the
+// number evicted. Keys are also evicted as failures are recorded.
+func (q *Quarantine[T]) Compact() int {
+	q.mu.Lock()
+	expired, displaced := q.failures.compact(q.getClock().Now())
+	keys := len(q.failures.entries)
+	q.mu.Unlock()
+	emitEvicted(context.Background(), q.identity, expired, displaced, keys)
+	return expired + displaced
+}
+
+// KeyStats returns the number of keys tracked and evicted.
+func (q *Quarantine[T]) KeyStats() KeyStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.failures.keyStats()
+}
+
+// SetKeyFunc sets the function identifying repeated deliveries of an item.
+// A nil function keys items by DeepHash of their content.
+func (q *Quarantine[T]) SetKeyFunc(key func(context.Context, T) string) *Quarantine[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.key = key
+	return q
+}
+
+// SetProcessor updates the protected processor.
+func (q *Quarantine[T]) SetProcessor(processor Chainable[T]) *Quarantine[T] {
+	noteChildren(q.identity, processor)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.processor = processor
+	return q
+}
+
+// SetSink updates the processor receiving quarantined items.
+func (q *Quarantine[T]) SetSink(sink Chainable[T]) *Quarantine[T] {
+	noteChildren(q.identity, sink)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.sink = sink
+	return q
+}
+
+// WithClock sets a custom clock for testing.
+func (q *Quarantine[T]) WithClock(clock clockz.Clock) *Quarantine[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.clock = clock
+	return q
+}
+
+// getClock returns the clock to use.
+func (q *Quarantine[T]) getClock() clockz.Clock {
+	if q.clock == nil {
+		return clockz.RealClock
+	}
+	return q.clock
+}
+
+// Identity returns the identity of this connector.
+func (q *Quarantine[T]) Identity() Identity {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (q *Quarantine[T]) Schema() Node {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return Node{
+		Identity: q.identity,
+		Type:     "quarantine",
+		Flow: QuarantineFlow{
+			Processor: q.processor.Schema(),
+			Sink:      q.sink.Schema(),
+		},
+		Metadata: map[string]any{
+			"threshold": q.threshold,
+			"window":    q.failures.ttl.String(),
+			"max_keys":  q.failures.maxKeys,
+		},
+	}
+}
+
+// Close gracefully shuts down the connector, its processor, and its sink,
+// and stops background compaction.
+// Close is idempotent - multiple calls return the same result.
+func (q *Quarantine[T]) Close() error {
+	q.closeOnce.Do(func() {
+		q.mu.Lock()
+		compactor := q.compactor
+		q.compactor = nil
+		q.closeErr = errors.Join(q.processor.Close(), q.sink.Close())
+		q.mu.Unlock()
+		compactor.Stop()
+	})
+	return q.closeErr
+}
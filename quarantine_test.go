@@ -0,0 +1,160 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+func TestQuarantine(t *testing.T) {
+	type message struct {
+		ID     string
+		Poison bool
+	}
+	newGuard := func(threshold int) (*Quarantine[message], *atomic.Int64, *atomic.Int64) {
+		var calls, sunk atomic.Int64
+		processor := Apply(NewIdentity("handle", ""), func(_ context.Context, m message) (message, error) {
+			calls.Add(1)
+			if m.Poison {
+				return m, errors.New("cannot parse")
+			}
+			return m, nil
+		})
+		sink := Effect(NewIdentity("dead-letter", ""), func(context.Context, message) error {
+			sunk.Add(1)
+			return nil
+		})
+		guard := NewQuarantine(NewIdentity("poison-guard", ""), threshold, processor, sink).
+			SetKeyFunc(func(_ context.Context, m message) string { return m.ID })
+		return guard, &calls, &sunk
+	}
+
+	t.Run("Quarantines After Threshold", func(t *testing.T) {
+		guard, calls, sunk := newGuard(3)
+		poison := message{ID: "m1", Poison: true}
+
+		for i := range 2 {
+			if _, err := guard.Process(context.Background(), poison); err == nil {
+				t.Fatalf("delivery %d: expected failure", i+1)
+			}
+		}
+		if _, err := guard.Process(context.Background(), poison); err != nil {
+			t.Fatalf("expected third failure to be quarantined, got %v", err)
+		}
+		if sunk.Load() != 1 || guard.Quarantined() != 1 {
+			t.Fatalf("expected one quarantined item, got %d", sunk.Load())
+		}
+		if _, err := guard.Process(context.Background(), poison); err != nil {
+			t.Fatalf("expected redelivery to go to the sink, got %v", err)
+		}
+		if calls.Load() != 3 || sunk.Load() != 2 {
+			t.Errorf("expected processor skipped once quarantined, got %d calls and %d sunk", calls.Load(), sunk.Load())
+		}
+	})
+
+	t.Run("Success Resets Count", func(t *testing.T) {
+		guard, _, sunk := newGuard(2)
+		_, _ = guard.Process(context.Background(), message{ID: "m1", Poison: true}) //nolint:errcheck // counted
+		_, _ = guard.Process(context.Background(), message{ID: "m1"})               //nolint:errcheck // succeeds
+		if guard.Failures("m1") != 0 {
+			t.Fatalf("expected success to clear failures, got %d", guard.Failures("m1"))
+		}
+		_, _ = guard.Process(context.Background(), message{ID: "m1", Poison: true}) //nolint:errcheck // counted
+		if sunk.Load() != 0 {
+			t.Errorf("expected no quarantine after reset, got %d", sunk.Load())
+		}
+	})
+
+	t.Run("Keys Are Independent", func(t *testing.T) {
+		guard, _, sunk := newGuard(1)
+		_, _ = guard.Process(context.Background(), message{ID: "bad", Poison: true}) //nolint:errcheck // quarantined
+		if _, err := guard.Process(context.Background(), message{ID: "good"}); err != nil {
+			t.Errorf("expected other keys to run, got %v", err)
+		}
+		if sunk.Load() != 1 {
+			t.Errorf("expected only the bad key quarantined, got %d", sunk.Load())
+		}
+	})
+
+	t.Run("Content Hash Without Key Func", func(t *testing.T) {
+		guard, _, sunk := newGuard(2)
+		guard.SetKeyFunc(nil)
+		poison := message{ID: "x", Poison: true}
+		_, _ = guard.Process(context.Background(), poison) //nolint:errcheck // counted
+		_, _ = guard.Process(context.Background(), poison) //nolint:errcheck // quarantined
+		if sunk.Load() != 1 {
+			t.Errorf("expected identical content to share a count, got %d quarantined", sunk.Load())
+		}
+	})
+
+	t.Run("Window Releases Keys", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		guard, calls, _ := newGuard(1)
+		guard.WithClock(clock).SetWindow(time.Hour)
+		poison := message{ID: "m1", Poison: true}
+		_, _ = guard.Process(context.Background(), poison) //nolint:errcheck // quarantined
+		clock.Advance(2 * time.Hour)
+		_, _ = guard.Process(context.Background(), poison) //nolint:errcheck // retried after window
+		if calls.Load() != 2 {
+			t.Errorf("expected processor retried after window, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		guard, calls, _ := newGuard(1)
+		_, _ = guard.Process(context.Background(), message{ID: "m1", Poison: true}) //nolint:errcheck // quarantined
+		if !guard.Release("m1") || guard.Release("m1") {
+			t.Fatal("expected Release to clear the key once")
+		}
+		_, _ = guard.Process(context.Background(), message{ID: "m1"}) //nolint:errcheck // runs
+		if calls.Load() != 2 {
+			t.Errorf("expected processor to run after release, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("Sink Failure Is Reported", func(t *testing.T) {
+		guard, _, _ := newGuard(1)
+		guard.SetSink(Effect(NewIdentity("dead-letter", ""), func(context.Context, message) error {
+			return errors.New("queue down")
+		}))
+		_, err := guard.Process(context.Background(), message{ID: "m1", Poison: true})
+		var pipeErr *Error[message]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[1].Name() != "dead-letter" {
+			t.Errorf("expected sink error, got %v", err)
+		}
+	})
+
+	t.Run("Emits Quarantined Signal", func(t *testing.T) {
+		var key string
+		var failures int
+		listener := capitan.Hook(SignalQuarantined, func(_ context.Context, e *capitan.Event) {
+			key, _ = FieldItemKey.From(e)
+			failures, _ = FieldFailures.From(e)
+		})
+		defer listener.Close()
+
+		guard, _, _ := newGuard(1)
+		_, _ = guard.Process(context.Background(), message{ID: "m9", Poison: true}) //nolint:errcheck // quarantined
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if key != "m9" || failures != 1 {
+			t.Errorf("unexpected signal fields: %q, %d", key, failures)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		guard, _, _ := newGuard(3)
+		node := guard.Schema()
+		flow, ok := QuarantineKey.From(node)
+		if !ok || flow.Sink.Identity.Name() != "dead-letter" || node.Metadata["threshold"] != 3 {
+			t.Errorf("unexpected schema: %+v", node)
+		}
+	})
+}
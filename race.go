@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
 )
 
 // Race runs all processors in parallel and returns the result of the first
@@ -38,6 +39,9 @@ import (
 //   - Useful for reducing p99 latencies
 //   - Can increase load (all processors run)
 //
+// SetBranchRetry lets branches retry failures within the race, such as a
+// provider retrying once quickly, without nesting a Retry in each branch.
+//
 // Example:
 //
 //	// UserQuery must implement Cloner[UserQuery]
@@ -48,7 +52,9 @@ import (
 //	)
 type Race[T Cloner[T]] struct {
 	identity   Identity
+	clock      clockz.Clock
 	processors []Chainable[T]
+	retries    branchRetries
 	mu         sync.RWMutex
 	closeOnce  sync.Once
 	closeErr   error
@@ -66,11 +72,21 @@ func NewRace[T Cloner[T]](identity Identity, processors ...Chainable[T]) *Race[T
 func (r *Race[T]) Process(ctx context.Context, input T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, r.identity, input)
 
+	ctx, guardErr := enterDepth(ctx, r, r.identity, input)
+	if guardErr != nil {
+		return input, guardErr
+	}
+
 	start := time.Now()
 
 	r.mu.RLock()
 	processors := make([]Chainable[T], len(r.processors))
 	copy(processors, r.processors)
+	policies := make([]BranchRetry, len(processors))
+	for i, p := range processors {
+		policies[i] = r.retries.policy(p.Identity())
+	}
+	clock := r.getClock()
 	r.mu.RUnlock()
 
 	if len(processors) == 0 {
@@ -78,7 +94,7 @@ func (r *Race[T]) Process(ctx context.Context, input T) (result T, err error) {
 		return zero, &Error[T]{
 			Path:      []Identity{r.identity},
 			Err:       fmt.Errorf("no processors provided to Race"),
-			InputData: input,
+			InputData: errorInput(input),
 			Timestamp: time.Now(),
 			Duration:  0,
 		}
@@ -92,6 +108,8 @@ func (r *Race[T]) Process(ctx context.Context, input T) (result T, err error) {
 		name string
 	}
 
+	before, checkIsolated := isolationSnapshot(ctx, input)
+
 	resultCh := make(chan raceResult, len(processors))
 	// Create a cancellable context to stop other processors when one wins
 	// This derives from the original context, preserving trace data
@@ -101,11 +119,8 @@ func (r *Race[T]) Process(ctx context.Context, input T) (result T, err error) {
 	// Launch all processors
 	for i, processor := range processors {
 		go func(idx int, p Chainable[T]) {
-			// Create an isolated copy using the Clone method
-			inputCopy := input.Clone()
-
-			// Process
-			data, err := p.Process(raceCtx, inputCopy)
+			// Each attempt gets an isolated copy unless the branch is read-only
+			data, err := runBranch(raceCtx, clock, r.identity, p, input, policies[idx])
 			select {
 			case resultCh <- raceResult{data: data, err: err, idx: idx, name: p.Identity().Name()}:
 			case <-raceCtx.Done():
@@ -122,6 +137,14 @@ func (r *Race[T]) Process(ctx context.Context, input T) (result T, err error) {
 				// First success wins
 				cancel() // Cancel other goroutines
 
+				// Losers may still be unwinding; mutations they make after
+				// this point go unnoticed.
+				if checkIsolated {
+					if isoErr := checkIsolation(ctx, r.identity, input, before); isoErr != nil {
+						return input, isoErr
+					}
+				}
+
 				// Emit winner signal
 				capitan.Info(ctx, SignalRaceWinner,
 					FieldName.Field(r.identity.Name()),
@@ -140,17 +163,22 @@ func (r *Race[T]) Process(ctx context.Context, input T) (result T, err error) {
 	}
 
 	// All failed - return the last error
+	if checkIsolated {
+		if isoErr := checkIsolation(ctx, r.identity, input, before); isoErr != nil {
+			return input, isoErr
+		}
+	}
 	if lastErr != nil {
 		var pipeErr *Error[T]
 		if errors.As(lastErr, &pipeErr) {
 			// Prepend this race's identity to the path
-			pipeErr.Path = append([]Identity{r.identity}, pipeErr.Path...)
+			pipeErr.prependPath(ctx, r.identity)
 			return input, pipeErr
 		}
 		// Handle non-pipeline errors by wrapping them
 		return input, &Error[T]{
 			Timestamp: time.Now(),
-			InputData: input,
+			InputData: errorInput(input),
 			Err:       lastErr,
 			Path:      []Identity{r.identity},
 		}
@@ -160,6 +188,7 @@ func (r *Race[T]) Process(ctx context.Context, input T) (result T, err error) {
 
 // Add appends a processor to the race execution list.
 func (r *Race[T]) Add(processor Chainable[T]) *Race[T] {
+	noteChildren(r.identity, processor)
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.processors = append(r.processors, processor)
@@ -179,6 +208,32 @@ func (r *Race[T]) Remove(index int) error {
 	return nil
 }
 
+// SetBranchRetry sets the retry policy of the given branches, or of every
+// branch without a policy of its own when no branches are given. The zero
+// BranchRetry disables retrying.
+func (r *Race[T]) SetBranchRetry(policy BranchRetry, branches ...Identity) *Race[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retries.set(policy, branches)
+	return r
+}
+
+// WithClock sets a custom clock for testing.
+func (r *Race[T]) WithClock(clock clockz.Clock) *Race[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = clock
+	return r
+}
+
+// getClock returns the clock to use.
+func (r *Race[T]) getClock() clockz.Clock {
+	if r.clock == nil {
+		return clockz.RealClock
+	}
+	return r.clock
+}
+
 // Len returns the number of processors.
 func (r *Race[T]) Len() int {
 	r.mu.RLock()
@@ -196,6 +251,7 @@ func (r *Race[T]) Clear() *Race[T] {
 
 // SetProcessors replaces all processors atomically.
 func (r *Race[T]) SetProcessors(processors ...Chainable[T]) *Race[T] {
+	noteChildren(r.identity, processors...)
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.processors = make([]Chainable[T], len(processors))
@@ -220,11 +276,15 @@ func (r *Race[T]) Schema() Node {
 		competitors[i] = proc.Schema()
 	}
 
-	return Node{
+	node := Node{
 		Identity: r.identity,
 		Type:     "race",
 		Flow:     RaceFlow{Competitors: competitors},
 	}
+	if attempts := branchAttempts(&r.retries, r.processors); attempts != nil {
+		node.Metadata = map[string]any{"branch_attempts": attempts}
+	}
+	return node
 }
 
 // Close gracefully shuts down the connector and all its child processors.
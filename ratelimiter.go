@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"sync"
 	"time"
 
@@ -12,12 +13,32 @@ import (
 	"github.com/zoobzio/clockz"
 )
 
-// Mode constants.
+// ErrRateLimited is wrapped by errors from a RateLimiter in drop mode that
+// has no tokens available.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitMode determines what a RateLimiter does when no token is
+// available.
+type RateLimitMode string
+
+// Rate limiting modes.
 const (
-	modeWait = "wait"
-	modeDrop = "drop"
+	// RateLimitWait blocks until a token is available.
+	RateLimitWait RateLimitMode = "wait"
+	// RateLimitDrop fails immediately with an error wrapping ErrRateLimited.
+	RateLimitDrop RateLimitMode = "drop"
 )
 
+// rateLimitModes lists the valid rate limiting modes.
+var rateLimitModes = []RateLimitMode{RateLimitWait, RateLimitDrop}
+
+// ParseRateLimitMode returns the rate limiting mode named s, such as "drop"
+// read from a config file. Unknown names return an error wrapping
+// ErrInvalidMode that lists the valid names.
+func ParseRateLimitMode(s string) (RateLimitMode, error) {
+	return parseMode("rate limiter", s, rateLimitModes...)
+}
+
 // RateLimiter controls the rate of processing to protect downstream services.
 // RateLimiter wraps a processor and uses a token bucket algorithm to enforce
 // rate limits, allowing controlled bursts while maintaining a steady average rate.
@@ -43,8 +64,12 @@ const (
 //	}
 //
 // The limiter operates in two modes:
-//   - "wait": Blocks until a token is available (default)
-//   - "drop": Returns an error immediately if no tokens available
+//   - RateLimitWait: Blocks until a token is available (default)
+//   - RateLimitDrop: Returns an error immediately if no tokens available
+//
+// RateLimiter honors the priority on the context (see WithPriority):
+// critical items are never dropped, waiting for a token even in drop mode,
+// and SetReserve holds back tokens that bulk items cannot take.
 //
 // RateLimiter is particularly useful for:
 //   - API client implementations with rate limits
@@ -84,11 +109,12 @@ type RateLimiter[T any] struct {
 	lastRefill time.Time     // last refill time
 	clock      clockz.Clock  // clock interface
 	identity   Identity      // identity struct
-	mode       string        // "wait" or "drop"
+	mode       RateLimitMode // RateLimitWait or RateLimitDrop
 	rate       float64       // tokens per second
 	tokens     float64       // current tokens
 	mu         sync.Mutex    // mutex
 	burst      int           // maximum tokens
+	reserve    int           // tokens bulk items cannot take
 	closeOnce  sync.Once     // ensures Close is idempotent
 	closeErr   error         // cached close error
 }
@@ -106,11 +132,41 @@ func NewRateLimiter[T any](identity Identity, ratePerSecond float64, burst int,
 		burst:      burst,
 		tokens:     float64(burst), // Start with full bucket
 		lastRefill: now,
-		mode:       modeWait, // Default to wait mode
+		mode:       RateLimitWait, // Default to wait mode
 		clock:      clockz.RealClock,
 	}
 }
 
+// NewRateLimiterWithOptions creates a RateLimiter from functional options.
+// WithRate is required; WithBurst (default 1), WithMode (default RateLimitWait), and
+// WithClock are optional. Invalid, missing, or inapplicable options return an
+// error wrapping ErrInvalidOption.
+//
+// Example:
+//
+//	limiter, err := pipz.NewRateLimiterWithOptions(LimiterID, apiCall,
+//	    pipz.WithRate(100),
+//	    pipz.WithBurst(10),
+//	    pipz.WithMode(pipz.RateLimitDrop),
+//	)
+func NewRateLimiterWithOptions[T any](identity Identity, processor Chainable[T], opts ...Option) (*RateLimiter[T], error) {
+	cfg, err := newConfig("rate limiter", Config{
+		Burst: 1,
+		Mode:  RateLimitWait,
+	}, optRate|optBurst|optMode|optClock, optRate, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	r := NewRateLimiter(identity, cfg.Rate, cfg.Burst, processor)
+	r.mode = cfg.Mode
+	if cfg.Clock != nil {
+		r.clock = cfg.Clock
+		r.lastRefill = cfg.Clock.Now()
+	}
+	return r, nil
+}
+
 // refillTokens updates the token bucket based on elapsed time since last refill.
 // Formula: tokens = min(burst, tokens + elapsed_seconds * rate)
 // Must be called with mutex held.
@@ -129,12 +185,21 @@ func (r *RateLimiter[T]) refillTokens() {
 	r.tokens = math.Min(float64(r.burst), r.tokens+elapsed*r.rate)
 }
 
-// canTakeToken checks if a token is available and takes it if so.
+// needed returns how many tokens must be available for an item of
+// priority to take one. Must be called with mutex held.
+func (r *RateLimiter[T]) needed(priority Priority) float64 {
+	if priority < PriorityNormal {
+		return 1.0 + float64(max(min(r.reserve, r.burst-1), 0))
+	}
+	return 1.0
+}
+
+// canTakeToken checks if a token is available for priority and takes it if so.
 // Returns true if token was taken, false otherwise.
 // Must be called with mutex held.
-func (r *RateLimiter[T]) canTakeToken() bool {
+func (r *RateLimiter[T]) canTakeToken(priority Priority) bool {
 	r.refillTokens()
-	if r.tokens >= 1.0 {
+	if r.tokens >= r.needed(priority) {
 		r.tokens -= 1.0
 		return true
 	}
@@ -142,16 +207,16 @@ func (r *RateLimiter[T]) canTakeToken() bool {
 }
 
 // calculateWaitTime returns the duration to wait for the next token.
-// Formula: waitTime = (1 - currentTokens) / rate * time.Second
+// Formula: waitTime = (needed - currentTokens) / rate * time.Second
 // Must be called with mutex held after refillTokens().
-func (r *RateLimiter[T]) calculateWaitTime() time.Duration {
+func (r *RateLimiter[T]) calculateWaitTime(priority Priority) time.Duration {
 	// Handle zero rate - block forever
 	if r.rate == 0 {
 		return time.Duration(math.MaxInt64)
 	}
 
 	// Calculate time needed for next token
-	needed := 1.0 - r.tokens
+	needed := r.needed(priority) - r.tokens
 	if needed <= 0 {
 		return 0
 	}
@@ -163,10 +228,19 @@ func (r *RateLimiter[T]) calculateWaitTime() time.Duration {
 func (r *RateLimiter[T]) Process(ctx context.Context, data T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, r.identity, data)
 
+	ctx, guardErr := enterDepth(ctx, r, r.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	priority := PriorityFromContext(ctx)
 	for {
 		r.mu.Lock()
 		mode := r.mode
-		if r.canTakeToken() {
+		if priority >= PriorityCritical {
+			mode = RateLimitWait // Critical items are never dropped
+		}
+		if r.canTakeToken(priority) {
 			// Emit allowed signal
 			capitan.Info(ctx, SignalRateLimiterAllowed,
 				FieldName.Field(r.identity.Name()),
@@ -183,12 +257,12 @@ func (r *RateLimiter[T]) Process(ctx context.Context, data T) (result T, err err
 			if err != nil {
 				var pipeErr *Error[T]
 				if errors.As(err, &pipeErr) {
-					pipeErr.Path = append([]Identity{r.identity}, pipeErr.Path...)
+					pipeErr.prependPath(ctx, r.identity)
 					return result, pipeErr
 				}
 				return result, &Error[T]{
 					Timestamp: time.Now(),
-					InputData: data,
+					InputData: errorInput(data),
 					Err:       err,
 					Path:      []Identity{r.identity},
 				}
@@ -197,8 +271,8 @@ func (r *RateLimiter[T]) Process(ctx context.Context, data T) (result T, err err
 		}
 
 		switch mode {
-		case modeWait:
-			waitTime := r.calculateWaitTime()
+		case RateLimitWait:
+			waitTime := r.calculateWaitTime(priority)
 
 			// Emit throttled signal
 			capitan.Warn(ctx, SignalRateLimiterThrottled,
@@ -217,7 +291,7 @@ func (r *RateLimiter[T]) Process(ctx context.Context, data T) (result T, err err
 				<-ctx.Done()
 				return data, &Error[T]{
 					Err:       ctx.Err(),
-					InputData: data,
+					InputData: errorInput(data),
 					Path:      []Identity{r.identity},
 					Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
 					Canceled:  errors.Is(ctx.Err(), context.Canceled),
@@ -232,7 +306,7 @@ func (r *RateLimiter[T]) Process(ctx context.Context, data T) (result T, err err
 			case <-ctx.Done():
 				return data, &Error[T]{
 					Err:       ctx.Err(),
-					InputData: data,
+					InputData: errorInput(data),
 					Path:      []Identity{r.identity},
 					Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
 					Canceled:  errors.Is(ctx.Err(), context.Canceled),
@@ -240,7 +314,7 @@ func (r *RateLimiter[T]) Process(ctx context.Context, data T) (result T, err err
 				}
 			}
 
-		case modeDrop:
+		case RateLimitDrop:
 			// Emit dropped signal
 			capitan.Error(ctx, SignalRateLimiterDropped,
 				FieldName.Field(r.identity.Name()),
@@ -248,14 +322,14 @@ func (r *RateLimiter[T]) Process(ctx context.Context, data T) (result T, err err
 				FieldTokens.Field(r.tokens),
 				FieldRate.Field(r.rate),
 				FieldBurst.Field(r.burst),
-				FieldMode.Field(mode),
+				FieldMode.Field(string(mode)),
 				FieldTimestamp.Field(float64(r.clock.Now().Unix())),
 			)
 
 			r.mu.Unlock()
 			return data, &Error[T]{
-				Err:       fmt.Errorf("rate limit exceeded"),
-				InputData: data,
+				Err:       ErrRateLimited,
+				InputData: errorInput(data),
 				Path:      []Identity{r.identity},
 				Timestamp: r.clock.Now(),
 			}
@@ -264,7 +338,7 @@ func (r *RateLimiter[T]) Process(ctx context.Context, data T) (result T, err err
 			r.mu.Unlock()
 			return data, &Error[T]{
 				Err:       fmt.Errorf("invalid rate limiter mode: %s", mode),
-				InputData: data,
+				InputData: errorInput(data),
 				Path:      []Identity{r.identity},
 				Timestamp: r.clock.Now(),
 			}
@@ -296,9 +370,11 @@ func (r *RateLimiter[T]) SetBurst(burst int) *RateLimiter[T] {
 	return r
 }
 
-// SetMode sets the rate limiting mode ("wait" or "drop").
-func (r *RateLimiter[T]) SetMode(mode string) *RateLimiter[T] {
-	if mode != modeWait && mode != modeDrop {
+// SetMode sets the rate limiting mode, RateLimitWait or RateLimitDrop.
+// Invalid modes are ignored; use ParseRateLimitMode to validate modes read
+// from configuration.
+func (r *RateLimiter[T]) SetMode(mode RateLimitMode) *RateLimiter[T] {
+	if !slices.Contains(rateLimitModes, mode) {
 		// Invalid mode, ignore
 		return r
 	}
@@ -308,6 +384,17 @@ func (r *RateLimiter[T]) SetMode(mode string) *RateLimiter[T] {
 	return r
 }
 
+// SetReserve holds back tokens from bulk-priority items: they only take a
+// token while more than reserve remain, leaving headroom for normal and
+// higher priorities. The reserve is capped below the burst so bulk items
+// can still proceed when the bucket is full.
+func (r *RateLimiter[T]) SetReserve(tokens int) *RateLimiter[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reserve = max(tokens, 0)
+	return r
+}
+
 // GetRate returns the current rate limit.
 func (r *RateLimiter[T]) GetRate() float64 {
 	r.mu.Lock()
@@ -322,8 +409,15 @@ func (r *RateLimiter[T]) GetBurst() int {
 	return r.burst
 }
 
-// GetMode returns the current mode ("wait" or "drop").
-func (r *RateLimiter[T]) GetMode() string {
+// GetReserve returns the number of tokens held back from bulk items.
+func (r *RateLimiter[T]) GetReserve() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reserve
+}
+
+// GetMode returns the current mode.
+func (r *RateLimiter[T]) GetMode() RateLimitMode {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	return r.mode
@@ -348,7 +442,7 @@ func (r *RateLimiter[T]) Schema() Node {
 		Metadata: map[string]any{
 			"rate":  r.rate,
 			"burst": r.burst,
-			"mode":  r.mode,
+			"mode":  string(r.mode),
 		},
 	}
 }
@@ -372,6 +466,44 @@ func (r *RateLimiter[T]) GetAvailableTokens() float64 {
 	return r.tokens
 }
 
+// RateLimiterState is a snapshot of a RateLimiter's token bucket.
+type RateLimiterState struct {
+	LastRefill time.Time `json:"last_refill"`
+	Tokens     float64   `json:"tokens"`
+}
+
+// Snapshot returns the limiter's current token bucket for persistence.
+func (r *RateLimiter[T]) Snapshot() RateLimiterState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return RateLimiterState{
+		LastRefill: r.lastRefill,
+		Tokens:     r.tokens,
+	}
+}
+
+// Restore replaces the limiter's token bucket with a snapshot. Tokens refill
+// from LastRefill as usual, so a snapshot restored after a restart accounts
+// for the downtime. Tokens above the current burst are capped; negative or
+// NaN tokens return an error wrapping ErrInvalidSnapshot and leave the
+// limiter unchanged.
+func (r *RateLimiter[T]) Restore(state RateLimiterState) error {
+	if math.IsNaN(state.Tokens) || state.Tokens < 0 {
+		return fmt.Errorf("%w: tokens must be non-negative, got %v", ErrInvalidSnapshot, state.Tokens)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = math.Min(float64(r.burst), state.Tokens)
+	r.lastRefill = state.LastRefill
+	if now := r.clock.Now(); r.lastRefill.After(now) {
+		// A refill time ahead of this clock (skew between hosts) would
+		// drain tokens on the next refill.
+		r.lastRefill = now
+	}
+	return nil
+}
+
 // Close gracefully shuts down the connector and its wrapped processor.
 // Close is idempotent - multiple calls return the same result.
 func (r *RateLimiter[T]) Close() error {
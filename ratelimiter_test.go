@@ -20,6 +20,27 @@ func testIdentity(name string) Identity {
 	return NewIdentity(name, "")
 }
 
+// waitForWaiters polls until a timer is registered on clock.
+func waitForWaiters(t *testing.T, clock *clockz.FakeClock) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !clock.HasWaiters() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for a timer on the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// advanceWhenWaiting advances clock by d once a timer is registered on it,
+// then delivers the timers that fired.
+func advanceWhenWaiting(t *testing.T, clock *clockz.FakeClock, d time.Duration) {
+	t.Helper()
+	waitForWaiters(t, clock)
+	clock.Advance(d)
+	clock.BlockUntilReady()
+}
+
 // passthroughInt creates a passthrough processor for int values.
 func passthroughInt() Chainable[int] {
 	return Transform(testIdentity("passthrough"), func(_ context.Context, x int) int { return x })
@@ -505,7 +526,7 @@ func TestRateLimiter_ConcurrentAccess(t *testing.T) {
 			for i := 0; i < 20; i++ {
 				limiter.SetRate(float64(500 + i*10))
 				limiter.SetBurst(50 + i)
-				limiter.SetMode([]string{"wait", "drop"}[i%2])
+				limiter.SetMode(rateLimitModes[i%2])
 				time.Sleep(time.Millisecond)
 			}
 		}()
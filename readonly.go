@@ -0,0 +1,59 @@
+package pipz
+
+// ReadOnly is implemented by processors that never modify their input.
+// Fan-out connectors (Concurrent, Race, Contest, and Group) pass such
+// branches the input itself instead of a clone, which saves the cost of
+// Clone for side-effect-heavy fan-outs. Processors created with Effect are
+// read-only; mark any other Chainable with AsReadOnly.
+//
+// A read-only branch shares the caller's input, including any maps, slices,
+// and pointers it holds. It must not modify them, and it may still be
+// reading them after the connector returns if it loses a Race or is
+// abandoned on cancellation. Enable Policy.CheckIsolation in tests to catch
+// branches that break the promise.
+type ReadOnly interface {
+	ReadOnly() bool
+}
+
+// ReadOnly reports whether the processor promises not to modify its input.
+// It is true for processors created with Effect.
+func (p Processor[T]) ReadOnly() bool {
+	return p.readOnly
+}
+
+// readOnlyChainable marks a Chainable as read-only.
+type readOnlyChainable[T any] struct {
+	Chainable[T]
+}
+
+// ReadOnly implements the ReadOnly interface.
+func (readOnlyChainable[T]) ReadOnly() bool {
+	return true
+}
+
+// AsReadOnly marks processor as never modifying its input, so fan-out
+// connectors run it on the input itself rather than a clone. Identity,
+// Schema, and Close are those of processor.
+//
+// Example:
+//
+//	notify := pipz.AsReadOnly(pipz.NewSequence(NotifyID, renderEmail, sendEmail))
+//	fanout := pipz.NewConcurrent(FanoutID, nil, notify, publishEvent, recordMetrics)
+func AsReadOnly[T any](processor Chainable[T]) Chainable[T] {
+	return readOnlyChainable[T]{Chainable: processor}
+}
+
+// isReadOnly reports whether processor promises not to modify its input.
+func isReadOnly[T any](processor Chainable[T]) bool {
+	r, ok := processor.(ReadOnly)
+	return ok && r.ReadOnly()
+}
+
+// branchInput returns the input for a fan-out branch: the input itself for
+// a read-only processor, otherwise an isolated clone.
+func branchInput[T Cloner[T]](processor Chainable[T], input T) T {
+	if isReadOnly(processor) {
+		return input
+	}
+	return input.Clone()
+}
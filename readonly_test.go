@@ -0,0 +1,117 @@
+package pipz
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+// countedData counts its clones.
+type countedData struct {
+	clones *atomic.Int64
+	Tags   []string
+}
+
+func (d countedData) Clone() countedData {
+	d.clones.Add(1)
+	d.Tags = append([]string(nil), d.Tags...)
+	return d
+}
+
+func TestReadOnly(t *testing.T) {
+	effect := Effect(NewIdentity("notify", ""), func(context.Context, countedData) error { return nil })
+	apply := Apply(NewIdentity("enrich", ""), func(_ context.Context, d countedData) (countedData, error) {
+		d.Tags = append(d.Tags, "enriched")
+		return d, nil
+	})
+
+	t.Run("Effect Is Read Only", func(t *testing.T) {
+		if !effect.ReadOnly() || apply.ReadOnly() {
+			t.Errorf("expected only Effect to be read-only, got %v and %v", effect.ReadOnly(), apply.ReadOnly())
+		}
+	})
+
+	t.Run("AsReadOnly Keeps Identity And Schema", func(t *testing.T) {
+		seq := NewSequence(NewIdentity("steps", ""), apply)
+		marked := AsReadOnly[countedData](seq)
+		if !isReadOnly(marked) {
+			t.Fatal("expected marked chainable to be read-only")
+		}
+		if marked.Identity() != seq.Identity() || marked.Schema().Type != "sequence" {
+			t.Errorf("expected identity and schema of the sequence, got %v", marked.Schema())
+		}
+	})
+
+	fanouts := map[string]func(...Chainable[countedData]) Chainable[countedData]{
+		"Concurrent": func(p ...Chainable[countedData]) Chainable[countedData] {
+			return NewConcurrent(NewIdentity("fanout", ""), nil, p...)
+		},
+		"Race": func(p ...Chainable[countedData]) Chainable[countedData] {
+			return NewRace(NewIdentity("fanout", ""), p...)
+		},
+		"Contest": func(p ...Chainable[countedData]) Chainable[countedData] {
+			return NewContest(NewIdentity("fanout", ""), func(context.Context, countedData) bool { return true }, p...)
+		},
+		"Group": func(p ...Chainable[countedData]) Chainable[countedData] {
+			return NewGroup(NewIdentity("fanout", ""), p...)
+		},
+	}
+	for name, build := range fanouts {
+		t.Run(name+" Skips Clone For Read Only Branches", func(t *testing.T) {
+			data := countedData{clones: &atomic.Int64{}}
+			fanout := build(effect, effect, AsReadOnly[countedData](apply))
+			if _, err := fanout.Process(context.Background(), data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n := data.clones.Load(); n != 0 {
+				t.Errorf("expected no clones, got %d", n)
+			}
+		})
+
+		t.Run(name+" Clones Writable Branches", func(t *testing.T) {
+			// Only writable branches, so even a Race's winner has cloned
+			// by the time it returns.
+			data := countedData{clones: &atomic.Int64{}}
+			fanout := build(apply, apply)
+			if _, err := fanout.Process(context.Background(), data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n := data.clones.Load(); n < 1 {
+				t.Errorf("expected the writable branch to be cloned, got %d clones", n)
+			}
+		})
+	}
+}
+
+func BenchmarkReadOnlyFanOut(b *testing.B) {
+	ctx := context.Background()
+	data := countedData{clones: &atomic.Int64{}, Tags: make([]string, 64)}
+	effects := make([]Chainable[countedData], 8)
+	for i := range effects {
+		effects[i] = Effect(NewIdentity("notify", ""), func(context.Context, countedData) error { return nil })
+	}
+	writable := make([]Chainable[countedData], 8)
+	for i := range writable {
+		writable[i] = Apply(NewIdentity("notify", ""), func(_ context.Context, d countedData) (countedData, error) { return d, nil })
+	}
+
+	b.Run("Cloned", func(b *testing.B) {
+		fanout := NewConcurrent(NewIdentity("fanout", ""), nil, writable...)
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := fanout.Process(ctx, data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Read_Only", func(b *testing.B) {
+		fanout := NewConcurrent(NewIdentity("fanout", ""), nil, effects...)
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := fanout.Process(ctx, data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,318 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrNotReady is returned by a Ready gate in fail mode when processing is
+// attempted before warm-up has completed.
+var ErrNotReady = errors.New("not ready")
+
+// ReadyMode determines how a Ready gate handles processing before
+// readiness.
+type ReadyMode string
+
+// Ready gate modes.
+const (
+	// ReadyWait blocks until ready or the context is done.
+	ReadyWait ReadyMode = "wait"
+	// ReadyFail fails immediately with ErrNotReady.
+	ReadyFail ReadyMode = "fail"
+)
+
+// readyModes lists the valid Ready gate modes.
+var readyModes = []ReadyMode{ReadyWait, ReadyFail}
+
+// ParseReadyMode returns the Ready gate mode named s. Unknown names return
+// an error wrapping ErrInvalidMode that lists the valid names.
+func ParseReadyMode(s string) (ReadyMode, error) {
+	return parseMode("ready", s, readyModes...)
+}
+
+// Warmer is implemented by processors and resources that need preparation
+// before serving traffic, such as priming caches, establishing connection
+// pools, or loading models. Warm should be safe to call more than once.
+type Warmer interface {
+	Warm(ctx context.Context) error
+}
+
+// WarmerFunc adapts a function to the Warmer interface.
+type WarmerFunc func(ctx context.Context) error
+
+// Warm implements Warmer.
+func (f WarmerFunc) Warm(ctx context.Context) error {
+	return f(ctx)
+}
+
+// WarmAll warms all warmers concurrently and returns their joined errors.
+// Nil warmers are skipped.
+//
+// Example:
+//
+//	if err := pipz.WarmAll(ctx, cache, dbPool, modelLoader); err != nil {
+//	    log.Fatalf("warm-up failed: %v", err)
+//	}
+func WarmAll(ctx context.Context, warmers ...Warmer) error {
+	errs := make([]error, len(warmers))
+	var wg sync.WaitGroup
+	for i, w := range warmers {
+		if w == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, w Warmer) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("warm-up panicked: %v", r)
+				}
+			}()
+			errs[i] = w.Warm(ctx)
+		}(i, w)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Ready gates a processor behind warm-up. Until Warm succeeds, processing
+// either waits for readiness (the default ReadyWait mode, bounded by the
+// caller's context) or fails immediately with ErrNotReady (ReadyFail mode),
+// which suits load balancer health checks that should route elsewhere.
+//
+// Warm runs every registered warmer plus the wrapped processor itself if it
+// implements Warmer. Ready also implements Warmer, so gates can be nested
+// and warmed from the top with a single call. Failed warm-ups leave the gate
+// closed and may be retried.
+//
+// Example:
+//
+//	var ServeID = pipz.NewIdentity("serve", "Serves once caches are primed")
+//	gate := pipz.NewReady(ServeID, pipeline, productCache, pricingClient)
+//
+//	go func() {
+//	    if err := gate.Warm(ctx); err != nil {
+//	        log.Printf("warm-up failed: %v", err)
+//	    }
+//	}()
+//	http.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+//	    if !gate.IsReady() {
+//	        w.WriteHeader(http.StatusServiceUnavailable)
+//	    }
+//	})
+type Ready[T any] struct {
+	processor Chainable[T]
+	readyCh   chan struct{}
+	identity  Identity
+	mode      ReadyMode
+	warmers   []Warmer
+	readyOnce sync.Once
+	warmMu    sync.Mutex
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewReady creates a Ready gate around processor that opens once all
+// warmers (and the processor, if it is a Warmer) have warmed successfully.
+func NewReady[T any](identity Identity, processor Chainable[T], warmers ...Warmer) *Ready[T] {
+	return &Ready[T]{
+		identity:  identity,
+		processor: processor,
+		warmers:   warmers,
+		readyCh:   make(chan struct{}),
+		mode:      ReadyWait,
+	}
+}
+
+// Process implements the Chainable interface.
+func (r *Ready[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, r.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, r, r.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	r.mu.RLock()
+	processor := r.processor
+	mode := r.mode
+	r.mu.RUnlock()
+
+	if !r.IsReady() {
+		var waitErr error
+		if mode == ReadyFail {
+			waitErr = ErrNotReady
+		} else {
+			select {
+			case <-r.readyCh:
+			case <-ctx.Done():
+				waitErr = fmt.Errorf("%w: %w", ErrNotReady, ctx.Err())
+			}
+		}
+		if waitErr != nil {
+			capitan.Warn(ctx, SignalReadyRejected,
+				FieldName.Field(r.identity.Name()),
+				FieldIdentityID.Field(r.identity.ID().String()),
+				FieldMode.Field(string(mode)),
+			)
+			var zero T
+			return zero, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(data),
+				Err:       waitErr,
+				Path:      []Identity{r.identity},
+				Timeout:   errors.Is(waitErr, context.DeadlineExceeded),
+				Canceled:  errors.Is(waitErr, context.Canceled),
+			}
+		}
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, r.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{r.identity},
+		}
+	}
+	return result, nil
+}
+
+// Warm runs all warmers and opens the gate on success.
+// Once the gate is open, further calls return nil without re-warming.
+// Concurrent calls are serialized.
+func (r *Ready[T]) Warm(ctx context.Context) error {
+	r.warmMu.Lock()
+	defer r.warmMu.Unlock()
+
+	if r.IsReady() {
+		return nil
+	}
+
+	r.mu.RLock()
+	warmers := make([]Warmer, 0, len(r.warmers)+1)
+	warmers = append(warmers, r.warmers...)
+	if w, ok := r.processor.(Warmer); ok {
+		warmers = append(warmers, w)
+	}
+	r.mu.RUnlock()
+
+	start := time.Now()
+	if err := WarmAll(ctx, warmers...); err != nil {
+		return err
+	}
+
+	r.readyOnce.Do(func() { close(r.readyCh) })
+	capitan.Info(ctx, SignalReadyWarmed,
+		FieldName.Field(r.identity.Name()),
+		FieldIdentityID.Field(r.identity.ID().String()),
+		FieldDuration.Field(time.Since(start).Seconds()),
+	)
+	return nil
+}
+
+// IsReady reports whether warm-up has completed.
+func (r *Ready[T]) IsReady() bool {
+	select {
+	case <-r.readyCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait blocks until the gate opens or ctx is done.
+func (r *Ready[T]) Wait(ctx context.Context) error {
+	select {
+	case <-r.readyCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AddWarmer registers an additional warmer. It only affects warm-ups
+// that have not yet succeeded.
+func (r *Ready[T]) AddWarmer(w Warmer) *Ready[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.warmers = append(r.warmers, w)
+	return r
+}
+
+// SetMode sets how processing behaves before readiness: ReadyWait blocks
+// until ready or the context is done; ReadyFail returns ErrNotReady
+// immediately. Invalid modes are ignored.
+func (r *Ready[T]) SetMode(mode ReadyMode) *Ready[T] {
+	if !slices.Contains(readyModes, mode) {
+		return r
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mode = mode
+	return r
+}
+
+// GetMode returns the current mode.
+func (r *Ready[T]) GetMode() ReadyMode {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.mode
+}
+
+// SetProcessor updates the gated processor.
+func (r *Ready[T]) SetProcessor(processor Chainable[T]) *Ready[T] {
+	noteChildren(r.identity, processor)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.processor = processor
+	return r
+}
+
+// Identity returns the identity of this connector.
+func (r *Ready[T]) Identity() Identity {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (r *Ready[T]) Schema() Node {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return Node{
+		Identity: r.identity,
+		Type:     "ready",
+		Flow:     ReadyFlow{Processor: r.processor.Schema()},
+		Metadata: map[string]any{
+			"mode":    string(r.mode),
+			"warmers": len(r.warmers),
+			"ready":   r.IsReady(),
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (r *Ready[T]) Close() error {
+	r.closeOnce.Do(func() {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		r.closeErr = r.processor.Close()
+	})
+	return r.closeErr
+}
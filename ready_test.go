@@ -0,0 +1,194 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// warmingProcessor is a Chainable that also implements Warmer.
+type warmingProcessor struct {
+	Processor[int]
+	warmed atomic.Bool
+}
+
+func (w *warmingProcessor) Warm(_ context.Context) error {
+	w.warmed.Store(true)
+	return nil
+}
+
+func TestWarmAll(t *testing.T) {
+	t.Run("Runs All And Joins Errors", func(t *testing.T) {
+		var calls atomic.Int32
+		ok := WarmerFunc(func(_ context.Context) error { calls.Add(1); return nil })
+		errA := errors.New("cache unavailable")
+		bad := WarmerFunc(func(_ context.Context) error { calls.Add(1); return errA })
+
+		err := WarmAll(context.Background(), ok, bad, nil, ok)
+		if !errors.Is(err, errA) {
+			t.Errorf("expected joined error, got %v", err)
+		}
+		if calls.Load() != 3 {
+			t.Errorf("expected 3 warmers to run, got %d", calls.Load())
+		}
+	})
+
+	t.Run("Recovers Panics", func(t *testing.T) {
+		boom := WarmerFunc(func(_ context.Context) error { panic("bad") })
+		if err := WarmAll(context.Background(), boom); err == nil {
+			t.Error("expected panic to surface as error")
+		}
+	})
+}
+
+func TestReady(t *testing.T) {
+	double := Transform(NewIdentity("double", ""), func(_ context.Context, v int) int { return v * 2 })
+
+	t.Run("Wait Mode Blocks Until Warm", func(t *testing.T) {
+		gate := NewReady(NewIdentity("gate", ""), double)
+		done := make(chan int)
+		go func() {
+			result, _ := gate.Process(context.Background(), 4) //nolint:errcheck // result checked below
+			done <- result
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("processing should block before warm-up")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		if err := gate.Warm(context.Background()); err != nil {
+			t.Fatalf("unexpected warm error: %v", err)
+		}
+		select {
+		case result := <-done:
+			if result != 8 {
+				t.Errorf("expected 8, got %d", result)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("processing did not resume after warm-up")
+		}
+	})
+
+	t.Run("Wait Mode Honors Context", func(t *testing.T) {
+		gate := NewReady(NewIdentity("gate", ""), double)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := gate.Process(ctx, 1)
+		var pipeErr *Error[int]
+		if !errors.Is(err, ErrNotReady) || !errors.As(err, &pipeErr) || !pipeErr.Timeout {
+			t.Errorf("expected not-ready timeout, got %v", err)
+		}
+	})
+
+	t.Run("Fail Mode", func(t *testing.T) {
+		gate := NewReady(NewIdentity("gate", ""), double).SetMode("fail")
+		if gate.GetMode() != "fail" {
+			t.Errorf("expected fail mode, got %s", gate.GetMode())
+		}
+		if _, err := gate.Process(context.Background(), 1); !errors.Is(err, ErrNotReady) {
+			t.Errorf("expected ErrNotReady, got %v", err)
+		}
+		gate.SetMode("bogus")
+		if gate.GetMode() != "fail" {
+			t.Error("invalid mode should be ignored")
+		}
+	})
+
+	t.Run("Failed Warm Keeps Gate Closed", func(t *testing.T) {
+		fail := true
+		w := WarmerFunc(func(_ context.Context) error {
+			if fail {
+				return errors.New("not yet")
+			}
+			return nil
+		})
+		gate := NewReady(NewIdentity("gate", ""), double, w)
+		if err := gate.Warm(context.Background()); err == nil {
+			t.Fatal("expected warm error")
+		}
+		if gate.IsReady() {
+			t.Fatal("gate should remain closed")
+		}
+		fail = false
+		if err := gate.Warm(context.Background()); err != nil || !gate.IsReady() {
+			t.Errorf("expected retry to open gate, got %v", err)
+		}
+	})
+
+	t.Run("Warms Processor Implementing Warmer", func(t *testing.T) {
+		proc := &warmingProcessor{Processor: Transform(NewIdentity("p", ""), func(_ context.Context, v int) int { return v })}
+		gate := NewReady[int](NewIdentity("gate", ""), proc)
+		if err := gate.Warm(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !proc.warmed.Load() {
+			t.Error("expected processor to be warmed")
+		}
+	})
+
+	t.Run("Nested Gates", func(t *testing.T) {
+		inner := NewReady(NewIdentity("inner", ""), double).SetMode("fail")
+		outer := NewReady[int](NewIdentity("outer", ""), inner)
+		if err := outer.Warm(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !inner.IsReady() {
+			t.Error("expected inner gate warmed through outer")
+		}
+		if result, err := outer.Process(context.Background(), 2); err != nil || result != 4 {
+			t.Errorf("expected 4, got %d (%v)", result, err)
+		}
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		gate := NewReady(NewIdentity("gate", ""), double)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := gate.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+		_ = gate.Warm(context.Background()) //nolint:errcheck // no warmers
+		if err := gate.Wait(context.Background()); err != nil {
+			t.Errorf("expected ready, got %v", err)
+		}
+	})
+
+	t.Run("Emits Signals", func(t *testing.T) {
+		var warmed, rejected atomic.Int32
+		l1 := capitan.Hook(SignalReadyWarmed, func(_ context.Context, _ *capitan.Event) { warmed.Add(1) })
+		defer l1.Close()
+		l2 := capitan.Hook(SignalReadyRejected, func(_ context.Context, _ *capitan.Event) { rejected.Add(1) })
+		defer l2.Close()
+
+		gate := NewReady(NewIdentity("gate", ""), double).SetMode("fail")
+		_, _ = gate.Process(context.Background(), 1) //nolint:errcheck // rejection expected
+		_ = gate.Warm(context.Background())          //nolint:errcheck // no warmers
+
+		if err := l1.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if err := l2.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if warmed.Load() != 1 || rejected.Load() != 1 {
+			t.Errorf("expected 1 warmed and 1 rejected, got %d and %d", warmed.Load(), rejected.Load())
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		gate := NewReady(NewIdentity("gate", ""), double)
+		schema := gate.Schema()
+		if _, ok := ReadyKey.From(schema); !ok || schema.Metadata["ready"] != false {
+			t.Errorf("unexpected schema: %+v", schema)
+		}
+		if err := gate.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	})
+}
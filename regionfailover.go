@@ -0,0 +1,509 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// RegionFailover sends traffic to one of several regional deployments of
+// the same backend, health-probing every region in the background so it
+// can move traffic before callers see failures. Regions are given in order
+// of preference, the first being the home region.
+//
+// Traffic is sticky: it stays on the active region while that region is
+// healthy. A region is marked unhealthy after SetFailureThreshold
+// consecutive failures, counting both probes and live items; traffic then
+// moves to the healthiest remaining region, the one with the fewest recent
+// failures, preferring earlier regions on ties. Once a more preferred
+// region has succeeded SetFailBack consecutive times, traffic fails back
+// to it automatically.
+//
+// An item that fails in the active region is tried in the other healthy
+// regions, as with Fallback. If no region is healthy, every region is
+// tried in order of preference, since probes may lag a recovery.
+//
+// Regions are probed with the function given to SetProbe, by processing a
+// small synthetic input given to SetProbeInput, or, by default, through
+// Health on regions implementing HealthChecker. Regions without a probe
+// are judged by live traffic alone. Start runs a first round of probes and
+// begins probing every SetProbeInterval; Close stops probing.
+//
+// CRITICAL: RegionFailover is STATEFUL. Create it once and reuse it.
+//
+// Example:
+//
+//	var CatalogFailoverID = pipz.NewIdentity("catalog-failover", "Routes catalog reads to a healthy region")
+//	catalog := pipz.NewRegionFailover(CatalogFailoverID, euWest, euCentral, usEast).
+//	    SetProbe(func(ctx context.Context, region pipz.Identity) error {
+//	        return pingRegion(ctx, region.Name())
+//	    }).
+//	    SetProbeInterval(5 * time.Second)
+//	if err := pipz.StartAll(ctx, catalog); err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer catalog.Close()
+type RegionFailover[T any] struct {
+	clock         clockz.Clock
+	probe         func(ctx context.Context, region Chainable[T]) error
+	prober        *compactor
+	identity      Identity
+	regions       []*regionState[T]
+	active        int
+	interval      time.Duration
+	probeTimeout  time.Duration
+	failThreshold int
+	failBack      int
+	mu            sync.RWMutex
+	closeOnce     sync.Once
+	closeErr      error
+}
+
+// regionState tracks the health of one region.
+type regionState[T any] struct {
+	processor Chainable[T]
+	lastErr   error
+	latency   time.Duration
+	failures  int
+	successes int
+	healthy   bool
+}
+
+// RegionStatus describes the health of one region of a RegionFailover.
+type RegionStatus struct {
+	// LastError is the most recent probe or item failure.
+	LastError error
+	// Identity identifies the region's processor.
+	Identity Identity
+	// Latency is the smoothed probe latency, zero until a probe succeeds.
+	Latency time.Duration
+	// Failures is the number of consecutive failures.
+	Failures int
+	// Healthy reports whether the region is eligible for traffic.
+	Healthy bool
+	// Active reports whether the region is serving traffic.
+	Active bool
+}
+
+// NewRegionFailover creates a RegionFailover over regions in order of
+// preference. Every region starts healthy, with traffic on the first. By
+// default a region is unhealthy after 3 consecutive failures, probes run
+// every 10 seconds with a 2 second timeout, and traffic fails back after 3
+// consecutive successes.
+func NewRegionFailover[T any](identity Identity, regions ...Chainable[T]) *RegionFailover[T] {
+	states := make([]*regionState[T], len(regions))
+	for i, region := range regions {
+		states[i] = &regionState[T]{processor: region, healthy: true}
+	}
+	return &RegionFailover[T]{
+		identity:      identity,
+		regions:       states,
+		interval:      10 * time.Second,
+		probeTimeout:  2 * time.Second,
+		failThreshold: 3,
+		failBack:      3,
+	}
+}
+
+// Process implements the Chainable interface.
+func (r *RegionFailover[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, r.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, r, r.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	candidates := r.candidates()
+	if len(candidates) == 0 {
+		return data, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       fmt.Errorf("no regions provided to RegionFailover"),
+			Path:      []Identity{r.identity},
+		}
+	}
+
+	var lastErr error
+	for _, i := range candidates {
+		r.mu.RLock()
+		processor := r.regions[i].processor
+		r.mu.RUnlock()
+
+		result, err := processor.Process(ctx, data)
+		if err == nil {
+			r.record(ctx, i, nil, 0)
+			return result, nil
+		}
+		if isControl(err) {
+			var pipeErr *Error[T]
+			if errors.As(err, &pipeErr) {
+				pipeErr.prependPath(ctx, r.identity)
+			}
+			return result, err
+		}
+		lastErr = err
+		// A caller giving up says nothing about the region's health
+		if ctx.Err() != nil {
+			break
+		}
+		r.record(ctx, i, err, 0)
+	}
+
+	var pipeErr *Error[T]
+	if errors.As(lastErr, &pipeErr) {
+		pipeErr.prependPath(ctx, r.identity)
+		return data, pipeErr
+	}
+	return data, &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(data),
+		Err:       lastErr,
+		Path:      []Identity{r.identity},
+		Timeout:   errors.Is(lastErr, context.DeadlineExceeded),
+		Canceled:  errors.Is(lastErr, context.Canceled),
+	}
+}
+
+// candidates returns the indexes of the regions to try, in order: the
+// active region, then the other healthy regions from healthiest, or every
+// region in order of preference when none is healthy.
+func (r *RegionFailover[T]) candidates() []int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.regions) == 0 {
+		return nil
+	}
+	if !r.regions[r.active].healthy {
+		all := make([]int, len(r.regions))
+		for i := range all {
+			all[i] = i
+		}
+		return all
+	}
+	order := []int{r.active}
+	for len(order) < len(r.regions) {
+		next := r.healthiestLocked(order)
+		if next < 0 {
+			break
+		}
+		order = append(order, next)
+	}
+	return order
+}
+
+// healthiestLocked returns the index of the healthy region with the fewest
+// consecutive failures, excluding skip, or -1 if there is none. Ties go to
+// the more preferred region. The caller holds mu.
+func (r *RegionFailover[T]) healthiestLocked(skip []int) int {
+	best := -1
+	for i, region := range r.regions {
+		if !region.healthy || slices.Contains(skip, i) {
+			continue
+		}
+		if best < 0 || region.failures < r.regions[best].failures {
+			best = i
+		}
+	}
+	return best
+}
+
+// record updates region i's health with the outcome of a probe or item,
+// moving traffic if the active region became unhealthy or a preferred
+// region recovered. Latency is only recorded for probes.
+func (r *RegionFailover[T]) record(ctx context.Context, i int, err error, latency time.Duration) {
+	r.mu.Lock()
+	region := r.regions[i]
+	if err == nil {
+		region.failures = 0
+		region.successes++
+		region.healthy = true
+		if latency > 0 {
+			if region.latency == 0 {
+				region.latency = latency
+			} else {
+				region.latency = (region.latency*4 + latency) / 5
+			}
+		}
+	} else {
+		region.successes = 0
+		region.failures++
+		region.lastErr = err
+		if region.failures >= r.failThreshold {
+			region.healthy = false
+		}
+	}
+
+	from := r.active
+	failback := false
+	if !r.regions[r.active].healthy {
+		if next := r.healthiestLocked(nil); next >= 0 {
+			r.active = next
+		}
+	} else if r.failBack > 0 {
+		for j := 0; j < r.active; j++ {
+			if r.regions[j].healthy && r.regions[j].successes >= r.failBack {
+				r.active = j
+				failback = true
+				break
+			}
+		}
+	}
+	to := r.active
+	r.mu.Unlock()
+
+	if from == to {
+		return
+	}
+	fields := []capitan.Field{
+		FieldName.Field(r.identity.Name()),
+		FieldIdentityID.Field(r.identity.ID().String()),
+		FieldRegion.Field(r.regions[to].processor.Identity().Name()),
+		FieldFromRegion.Field(r.regions[from].processor.Identity().Name()),
+	}
+	if failback {
+		capitan.Info(ctx, SignalRegionFailback, fields...)
+	} else {
+		capitan.Warn(ctx, SignalRegionFailover, fields...)
+	}
+}
+
+// Probe probes every region concurrently, each within the probe timeout,
+// and records the results. Start calls it once and then on every interval;
+// call it directly to probe on demand.
+func (r *RegionFailover[T]) Probe(ctx context.Context) {
+	r.mu.RLock()
+	probe := r.probe
+	timeout := r.probeTimeout
+	regions := make([]Chainable[T], len(r.regions))
+	for i, region := range r.regions {
+		regions[i] = region.processor
+	}
+	clock := r.getClock()
+	r.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		check := probe
+		if check == nil {
+			checker, ok := region.(HealthChecker)
+			if !ok {
+				continue
+			}
+			check = func(ctx context.Context, _ Chainable[T]) error { return checker.Health(ctx) }
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := clock.WithTimeout(ctx, timeout)
+			defer cancel()
+			start := clock.Now()
+			err := recoverCall(probeCtx, func() error { return check(probeCtx, region) })
+			if err == nil && probeCtx.Err() != nil {
+				err = probeCtx.Err()
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			r.record(ctx, i, err, max(clock.Since(start), time.Nanosecond))
+		}()
+	}
+	wg.Wait()
+}
+
+// Start probes every region once, so traffic starts on a healthy region,
+// and then keeps probing every interval until Close. Calling Start again
+// restarts probing with the current interval.
+func (r *RegionFailover[T]) Start(ctx context.Context) error {
+	r.Probe(ctx)
+
+	r.mu.Lock()
+	prober := r.prober
+	r.prober = nil
+	r.mu.Unlock()
+	prober.Stop()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prober = startCompactor(r.getClock(), r.interval, func() {
+		r.Probe(context.Background())
+	})
+	return nil
+}
+
+// Health returns an error if no region is healthy.
+func (r *RegionFailover[T]) Health(_ context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var errs []error
+	for _, region := range r.regions {
+		if region.healthy {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", region.processor.Identity().Name(), region.lastErr))
+	}
+	return fmt.Errorf("%s: no healthy region: %w", r.identity.Name(), errors.Join(errs...))
+}
+
+// Active returns the identity of the region serving traffic.
+func (r *RegionFailover[T]) Active() Identity {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.regions) == 0 {
+		return Identity{}
+	}
+	return r.regions[r.active].processor.Identity()
+}
+
+// Regions returns the health of every region, in order of preference.
+func (r *RegionFailover[T]) Regions() []RegionStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	statuses := make([]RegionStatus, len(r.regions))
+	for i, region := range r.regions {
+		statuses[i] = RegionStatus{
+			Identity:  region.processor.Identity(),
+			Healthy:   region.healthy,
+			Active:    i == r.active,
+			Failures:  region.failures,
+			Latency:   region.latency,
+			LastError: region.lastErr,
+		}
+	}
+	return statuses
+}
+
+// SetProbe sets the function probing each region, called with the
+// region's identity. It returns nil when the region is healthy.
+func (r *RegionFailover[T]) SetProbe(probe func(ctx context.Context, region Identity) error) *RegionFailover[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.probe = func(ctx context.Context, region Chainable[T]) error {
+		return probe(ctx, region.Identity())
+	}
+	return r
+}
+
+// SetProbeInput probes each region by processing input, which should be a
+// small synthetic value that is cheap and safe to process repeatedly. A
+// region is healthy when processing succeeds.
+func (r *RegionFailover[T]) SetProbeInput(input T) *RegionFailover[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.probe = func(ctx context.Context, region Chainable[T]) error {
+		_, err := region.Process(ctx, input)
+		return err
+	}
+	return r
+}
+
+// SetProbeInterval sets how often regions are probed after Start. It takes
+// effect the next time Start is called.
+func (r *RegionFailover[T]) SetProbeInterval(interval time.Duration) *RegionFailover[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if interval > 0 {
+		r.interval = interval
+	}
+	return r
+}
+
+// SetProbeTimeout sets how long a probe may run before it counts as a
+// failure.
+func (r *RegionFailover[T]) SetProbeTimeout(timeout time.Duration) *RegionFailover[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if timeout > 0 {
+		r.probeTimeout = timeout
+	}
+	return r
+}
+
+// SetFailureThreshold sets how many consecutive failures mark a region
+// unhealthy. Values below one are treated as one.
+func (r *RegionFailover[T]) SetFailureThreshold(n int) *RegionFailover[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failThreshold = max(n, 1)
+	return r
+}
+
+// SetFailBack sets how many consecutive successes, usually probes, a more
+// preferred region needs before traffic returns to it. Zero disables automatic
+// fail-back, leaving traffic on its current region while it stays healthy.
+func (r *RegionFailover[T]) SetFailBack(n int) *RegionFailover[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failBack = max(n, 0)
+	return r
+}
+
+// WithClock sets a custom clock for testing.
+func (r *RegionFailover[T]) WithClock(clock clockz.Clock) *RegionFailover[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = clock
+	return r
+}
+
+// getClock returns the clock to use.
+func (r *RegionFailover[T]) getClock() clockz.Clock {
+	if r.clock == nil {
+		return clockz.RealClock
+	}
+	return r.clock
+}
+
+// Identity returns the identity of this connector.
+func (r *RegionFailover[T]) Identity() Identity {
+	return r.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (r *RegionFailover[T]) Schema() Node {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	regions := make([]Node, len(r.regions))
+	for i, region := range r.regions {
+		regions[i] = region.processor.Schema()
+	}
+	return Node{
+		Identity: r.identity,
+		Type:     "region-failover",
+		Flow:     RegionFailoverFlow{Regions: regions},
+		Metadata: map[string]any{
+			"probe_interval":    r.interval.String(),
+			"failure_threshold": r.failThreshold,
+			"fail_back":         r.failBack,
+		},
+	}
+}
+
+// Close stops probing and closes every region, returning their joined
+// errors. Close is idempotent - multiple calls return the same result.
+func (r *RegionFailover[T]) Close() error {
+	r.closeOnce.Do(func() {
+		r.mu.Lock()
+		prober := r.prober
+		r.prober = nil
+		r.mu.Unlock()
+		prober.Stop()
+
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		var errs []error
+		for _, region := range r.regions {
+			errs = append(errs, region.processor.Close())
+		}
+		r.closeErr = errors.Join(errs...)
+	})
+	return r.closeErr
+}
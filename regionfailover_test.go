@@ -0,0 +1,296 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// testRegions simulates regional backends that can be taken down.
+type testRegions struct {
+	down   map[string]bool
+	served map[string]int
+	mu     sync.Mutex
+}
+
+func newTestRegions() *testRegions {
+	return &testRegions{down: map[string]bool{}, served: map[string]int{}}
+}
+
+func (r *testRegions) setDown(name string, down bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.down[name] = down
+}
+
+func (r *testRegions) ping(_ context.Context, region Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.down[region.Name()] {
+		return errors.New(region.Name() + " down")
+	}
+	return nil
+}
+
+func (r *testRegions) region(name string) Chainable[string] {
+	return Apply(testIdentity(name), func(ctx context.Context, s string) (string, error) {
+		if err := r.ping(ctx, testIdentity(name)); err != nil {
+			return s, err
+		}
+		r.mu.Lock()
+		r.served[name]++
+		r.mu.Unlock()
+		return s + "@" + name, nil
+	})
+}
+
+func TestRegionFailover(t *testing.T) {
+	ctx := context.Background()
+
+	newFailover := func(regions *testRegions) *RegionFailover[string] {
+		return NewRegionFailover(testIdentity("failover"),
+			regions.region("eu"), regions.region("us"), regions.region("ap"),
+		).SetProbe(regions.ping).SetFailureThreshold(2).SetFailBack(2)
+	}
+
+	t.Run("Sticks To Home Region", func(t *testing.T) {
+		regions := newTestRegions()
+		rf := newFailover(regions)
+		for range 3 {
+			if out, err := rf.Process(ctx, "req"); err != nil || out != "req@eu" {
+				t.Fatalf("expected home region, got %q, %v", out, err)
+			}
+		}
+		if rf.Active().Name() != "eu" {
+			t.Errorf("expected eu active, got %s", rf.Active().Name())
+		}
+	})
+
+	t.Run("Failed Item Tries Other Healthy Regions", func(t *testing.T) {
+		regions := newTestRegions()
+		rf := newFailover(regions)
+		regions.setDown("eu", true)
+
+		out, err := rf.Process(ctx, "req")
+		if err != nil || out != "req@us" {
+			t.Fatalf("expected us to serve, got %q, %v", out, err)
+		}
+		if status := rf.Regions()[0]; !status.Healthy || status.Failures != 1 {
+			t.Errorf("expected eu still healthy after one failure, got %+v", status)
+		}
+	})
+
+	t.Run("Probes Fail Over And Back", func(t *testing.T) {
+		var failovers, failbacks atomic.Int32
+		failoverListener := capitan.Hook(SignalRegionFailover, func(_ context.Context, e *capitan.Event) {
+			if to, _ := FieldRegion.From(e); to == "us" {
+				failovers.Add(1)
+			}
+		})
+		defer failoverListener.Close()
+		failbackListener := capitan.Hook(SignalRegionFailback, func(_ context.Context, e *capitan.Event) {
+			if from, _ := FieldFromRegion.From(e); from == "us" {
+				failbacks.Add(1)
+			}
+		})
+		defer failbackListener.Close()
+
+		regions := newTestRegions()
+		rf := newFailover(regions)
+		regions.setDown("eu", true)
+		rf.Probe(ctx)
+		rf.Probe(ctx)
+		if rf.Active().Name() != "us" || rf.Regions()[0].Healthy {
+			t.Fatalf("expected failover to us, got %+v", rf.Regions())
+		}
+		if out, _ := rf.Process(ctx, "req"); out != "req@us" {
+			t.Errorf("expected us to serve, got %q", out)
+		}
+
+		regions.setDown("eu", false)
+		rf.Probe(ctx)
+		if rf.Active().Name() != "us" {
+			t.Error("expected traffic to stay on us until eu passes the fail-back threshold")
+		}
+		rf.Probe(ctx)
+		if rf.Active().Name() != "eu" {
+			t.Errorf("expected fail-back to eu, got %s", rf.Active().Name())
+		}
+
+		if err := failoverListener.Drain(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := failbackListener.Drain(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if failovers.Load() != 1 || failbacks.Load() != 1 {
+			t.Errorf("expected one failover and one failback signal, got %d and %d", failovers.Load(), failbacks.Load())
+		}
+	})
+
+	t.Run("Fails Over To Healthiest Region", func(t *testing.T) {
+		regions := newTestRegions()
+		rf := newFailover(regions).SetFailureThreshold(3)
+		regions.setDown("eu", true)
+		regions.setDown("us", true)
+		rf.Probe(ctx)
+		rf.Probe(ctx)
+		if rf.Active().Name() != "eu" {
+			t.Fatal("expected eu to stay active below the failure threshold")
+		}
+		if out, err := rf.Process(ctx, "req"); err != nil || out != "req@ap" {
+			t.Fatalf("expected ap to serve, got %q, %v", out, err)
+		}
+		if rf.Active().Name() != "ap" {
+			t.Errorf("expected failover to ap, which has not failed, got %s", rf.Active().Name())
+		}
+	})
+
+	t.Run("Fail Back Disabled", func(t *testing.T) {
+		regions := newTestRegions()
+		rf := newFailover(regions).SetFailBack(0)
+		regions.setDown("eu", true)
+		rf.Probe(ctx)
+		rf.Probe(ctx)
+		regions.setDown("eu", false)
+		for range 5 {
+			rf.Probe(ctx)
+		}
+		if rf.Active().Name() != "us" {
+			t.Errorf("expected traffic to stay on us, got %s", rf.Active().Name())
+		}
+	})
+
+	t.Run("No Healthy Region", func(t *testing.T) {
+		regions := newTestRegions()
+		rf := newFailover(regions)
+		for _, name := range []string{"eu", "us", "ap"} {
+			regions.setDown(name, true)
+		}
+		rf.Probe(ctx)
+		rf.Probe(ctx)
+		if err := rf.Health(ctx); err == nil {
+			t.Error("expected unhealthy")
+		}
+
+		// Probes may lag a recovery, so every region is still tried
+		regions.setDown("ap", false)
+		if out, err := rf.Process(ctx, "req"); err != nil || out != "req@ap" {
+			t.Errorf("expected ap to serve, got %q, %v", out, err)
+		}
+		if err := rf.Health(ctx); err != nil {
+			t.Errorf("expected ap healthy after serving, got %v", err)
+		}
+
+		regions.setDown("ap", true)
+		_, err := rf.Process(ctx, "req")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "failover" {
+			t.Errorf("expected pipeline error from failover, got %v", err)
+		}
+	})
+
+	t.Run("Canceled Item Does Not Count Against Region", func(t *testing.T) {
+		regions := newTestRegions()
+		slow := Apply(testIdentity("eu"), func(ctx context.Context, s string) (string, error) {
+			<-ctx.Done()
+			return s, ctx.Err()
+		})
+		rf := NewRegionFailover(testIdentity("failover"), slow, regions.region("us"))
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := rf.Process(canceled, "req")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) || !pipeErr.IsCanceled() {
+			t.Errorf("expected canceled error, got %v", err)
+		}
+		if status := rf.Regions()[0]; status.Failures != 0 || regions.served["us"] != 0 {
+			t.Errorf("expected no failure recorded and no failover, got %+v", status)
+		}
+	})
+
+	t.Run("Default And Synthetic Probes", func(t *testing.T) {
+		var healthy atomic.Bool
+		checked := struct {
+			Chainable[string]
+			HealthChecker
+		}{
+			Transform(testIdentity("eu"), func(_ context.Context, s string) string { return s }),
+			HealthCheckerFunc(func(context.Context) error {
+				if healthy.Load() {
+					return nil
+				}
+				return errors.New("unhealthy")
+			}),
+		}
+		regions := newTestRegions()
+		rf := NewRegionFailover(testIdentity("failover"), Chainable[string](checked), regions.region("us")).
+			SetFailureThreshold(1)
+		rf.Probe(ctx)
+		if rf.Active().Name() != "us" {
+			t.Errorf("expected Health to be probed by default, got %s", rf.Active().Name())
+		}
+
+		regions = newTestRegions()
+		rf = NewRegionFailover(testIdentity("failover"), regions.region("eu"), regions.region("us")).
+			SetProbeInput("probe").
+			SetFailureThreshold(1)
+		rf.Probe(ctx)
+		if regions.served["eu"] != 1 || regions.served["us"] != 1 {
+			t.Errorf("expected synthetic input processed by every region, got %v", regions.served)
+		}
+	})
+
+	t.Run("Start Probes On Interval", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		var probes atomic.Int32
+		rf := NewRegionFailover(testIdentity("failover"), newTestRegions().region("eu")).
+			WithClock(clock).
+			SetProbeInterval(time.Minute).
+			SetProbe(func(context.Context, Identity) error {
+				probes.Add(1)
+				return nil
+			})
+		if err := StartAll(ctx, rf); err != nil {
+			t.Fatal(err)
+		}
+		if probes.Load() != 1 {
+			t.Errorf("expected Start to probe once, got %d", probes.Load())
+		}
+
+		advanceWhenWaiting(t, clock, time.Minute)
+		deadline := time.Now().Add(time.Second)
+		for probes.Load() < 2 {
+			if time.Now().After(deadline) {
+				t.Fatal("expected a probe after the interval")
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		if err := rf.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	})
+
+	t.Run("Schema And Close", func(t *testing.T) {
+		regions := newTestRegions()
+		rf := newFailover(regions)
+		node := rf.Schema()
+		flow, ok := RegionKey.From(node)
+		if !ok || len(flow.Regions) != 3 || node.Metadata["fail_back"] != 2 {
+			t.Errorf("unexpected schema %+v", node)
+		}
+		if err := rf.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+		if _, err := NewRegionFailover[string](testIdentity("empty")).Process(ctx, "req"); err == nil {
+			t.Error("expected error without regions")
+		}
+	})
+}
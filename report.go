@@ -0,0 +1,151 @@
+package pipz
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// redactedValue replaces input data that cannot be safely included in a report.
+const redactedValue = "[redacted]"
+
+// Redactable is implemented by types that can produce a copy of themselves
+// safe to include in error reports, with secrets and personal data removed.
+// Input data that does not implement Redactable is omitted from reports.
+//
+// Example:
+//
+//	func (o Order) Redacted() any {
+//	    return struct {
+//	        ID    string  `json:"id"`
+//	        Total float64 `json:"total"`
+//	    }{o.ID, o.Total}
+//	}
+type Redactable interface {
+	Redacted() any
+}
+
+// PipelineStats holds execution counters for a Pipeline.
+// Skipped counts items dropped with ErrSkip, which are not failures.
+type PipelineStats struct {
+	Processed int64 `json:"processed"`
+	Failed    int64 `json:"failed"`
+	Skipped   int64 `json:"skipped"`
+}
+
+// errorSnapshot is the pipeline state captured when an Error leaves a
+// Pipeline. The schema is built from root only when a report is made.
+type errorSnapshot[T any] struct {
+	root        Chainable[T]
+	pipeline    Identity
+	executionID uuid.UUID
+	stats       PipelineStats
+}
+
+// Report is a serializable postmortem snapshot of a pipeline failure,
+// produced by Error.Report for attaching to incident tickets.
+type Report struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Input     ReportInput    `json:"input"`
+	Pipeline  *ReportContext `json:"pipeline,omitempty"`
+	Schema    *Schema        `json:"schema,omitempty"`
+	Error     string         `json:"error"`
+	Path      []ReportStep   `json:"path"`
+	Duration  string         `json:"duration"`
+	Timeout   bool           `json:"timeout"`
+	Canceled  bool           `json:"canceled"`
+}
+
+// ReportStep describes one connector or processor on the error path.
+// Type and Config are populated when the failure passed through a Pipeline,
+// from its schema as of the report.
+type ReportStep struct {
+	Config map[string]any `json:"config,omitempty"`
+	ID     string         `json:"id"`
+	Name   string         `json:"name"`
+	Type   string         `json:"type,omitempty"`
+}
+
+// ReportInput describes the input that caused the failure. Value holds the
+// Redacted form when the input implements Redactable and "[redacted]" otherwise.
+type ReportInput struct {
+	Value any    `json:"value"`
+	Type  string `json:"type"`
+}
+
+// ReportContext identifies the pipeline execution that failed.
+type ReportContext struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	ExecutionID string        `json:"execution_id,omitempty"`
+	Stats       PipelineStats `json:"stats"`
+}
+
+// Snapshot builds the postmortem Report for this error. Errors returned
+// through a Pipeline carry the execution ID and counters captured at failure
+// time, and the pipeline schema as of this call; other errors report the
+// path and input only.
+func (e *Error[T]) Snapshot() Report {
+	if e == nil {
+		return Report{}
+	}
+
+	report := Report{
+		Timestamp: e.Timestamp,
+		Duration:  e.Duration.String(),
+		Timeout:   e.Timeout,
+		Canceled:  e.Canceled,
+		Input:     ReportInput{Type: fmt.Sprintf("%T", e.InputData), Value: redactedValue},
+		Path:      make([]ReportStep, len(e.Path)),
+	}
+	if e.Err != nil {
+		report.Error = e.Err.Error()
+	}
+	if r, ok := any(e.InputData).(Redactable); ok {
+		report.Input.Value = r.Redacted()
+	}
+
+	var nodes map[uuid.UUID]Node
+	if e.snapshot != nil {
+		schema := NewSchema(e.snapshot.root.Schema())
+		report.Schema = &schema
+		nodes = make(map[uuid.UUID]Node)
+		schema.Walk(func(n Node) { nodes[n.Identity.ID()] = n })
+
+		report.Pipeline = &ReportContext{
+			ID:    e.snapshot.pipeline.ID().String(),
+			Name:  e.snapshot.pipeline.Name(),
+			Stats: e.snapshot.stats,
+		}
+		if e.snapshot.executionID != uuid.Nil {
+			report.Pipeline.ExecutionID = e.snapshot.executionID.String()
+		}
+	}
+
+	for i, id := range e.Path {
+		step := ReportStep{ID: id.ID().String(), Name: id.Name()}
+		if n, ok := nodes[id.ID()]; ok {
+			step.Type = n.Type
+			step.Config = n.Metadata
+		}
+		report.Path[i] = step
+	}
+	return report
+}
+
+// Report serializes the error's postmortem Snapshot as indented JSON.
+//
+// Example:
+//
+//	if _, err := pipeline.Process(ctx, order); err != nil {
+//	    var pipeErr *pipz.Error[Order]
+//	    if errors.As(err, &pipeErr) {
+//	        report, _ := pipeErr.Report()
+//	        incidents.Attach(ticketID, "pipeline-failure.json", report)
+//	    }
+//	}
+func (e *Error[T]) Report() ([]byte, error) {
+	return json.MarshalIndent(e.Snapshot(), "", "  ")
+}
@@ -0,0 +1,104 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type reportOrder struct {
+	ID     string
+	Secret string
+}
+
+func (o reportOrder) Redacted() any {
+	return map[string]string{"id": o.ID}
+}
+
+func TestErrorReport(t *testing.T) {
+	failing := Apply(NewIdentity("charge", "Charges the card"), func(_ context.Context, o reportOrder) (reportOrder, error) {
+		return o, errors.New("card declined")
+	})
+
+	t.Run("Pipeline Snapshot", func(t *testing.T) {
+		breaker := NewCircuitBreaker(NewIdentity("breaker", ""), failing, 3, time.Minute)
+		pipeline := NewPipeline(NewIdentity("checkout", ""), NewSequence(NewIdentity("steps", ""), breaker))
+
+		_, err := pipeline.Process(context.Background(), reportOrder{ID: "o-1", Secret: "4111"})
+		var pipeErr *Error[reportOrder]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected *Error, got %v", err)
+		}
+
+		data, err := pipeErr.Report()
+		if err != nil {
+			t.Fatalf("report failed: %v", err)
+		}
+		if strings.Contains(string(data), "4111") {
+			t.Error("report leaked unredacted input")
+		}
+
+		var decoded map[string]any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if _, ok := decoded["schema"]; !ok {
+			t.Error("expected schema in report")
+		}
+
+		report := pipeErr.Snapshot()
+		if report.Error != "card declined" {
+			t.Errorf("unexpected error: %q", report.Error)
+		}
+		if report.Pipeline == nil || report.Pipeline.Name != "checkout" || report.Pipeline.ExecutionID == "" {
+			t.Fatalf("missing pipeline context: %+v", report.Pipeline)
+		}
+		if report.Pipeline.Stats.Processed != 1 || report.Pipeline.Stats.Failed != 1 {
+			t.Errorf("unexpected stats: %+v", report.Pipeline.Stats)
+		}
+		if len(report.Path) != 3 {
+			t.Fatalf("expected 3 path steps, got %d", len(report.Path))
+		}
+		step := report.Path[1]
+		if step.Name != "breaker" || step.Type != "circuitbreaker" || step.Config["failure_threshold"] != 3 {
+			t.Errorf("unexpected breaker step: %+v", step)
+		}
+		if report.Input.Value.(map[string]string)["id"] != "o-1" {
+			t.Errorf("expected redacted input, got %v", report.Input.Value)
+		}
+	})
+
+	t.Run("Without Pipeline", func(t *testing.T) {
+		_, err := failing.Process(context.Background(), reportOrder{ID: "o-2"})
+		var pipeErr *Error[reportOrder]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected *Error, got %v", err)
+		}
+
+		report := pipeErr.Snapshot()
+		if report.Pipeline != nil || report.Schema != nil {
+			t.Error("expected no pipeline context")
+		}
+		if len(report.Path) != 1 || report.Path[0].Name != "charge" || report.Path[0].Type != "" {
+			t.Errorf("unexpected path: %+v", report.Path)
+		}
+	})
+
+	t.Run("Non Redactable Input Omitted", func(t *testing.T) {
+		pipeErr := &Error[string]{Err: errors.New("boom"), InputData: "ssn=123-45-6789"}
+		report := pipeErr.Snapshot()
+		if report.Input.Value != redactedValue || report.Input.Type != "string" {
+			t.Errorf("unexpected input: %+v", report.Input)
+		}
+	})
+
+	t.Run("Nil Error", func(t *testing.T) {
+		var pipeErr *Error[int]
+		if report := pipeErr.Snapshot(); report.Error != "" {
+			t.Errorf("expected empty report, got %+v", report)
+		}
+	})
+}
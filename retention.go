@@ -0,0 +1,172 @@
+package pipz
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// RetentionPolicy governs the data pipz itself retains about the items it
+// processes: the input captured in every Error[T] and the entries kept by
+// History. Set it once with SetRetentionPolicy, and apply it with
+// ApplyRetention wherever your own code stores items for pipz, such as the
+// dead-letter sink of a Quarantine, so one policy covers everything the
+// pipeline might store. The zero value retains everything.
+//
+// Processing order is sampling, then Redact, then MaxBytes. Errors omit
+// their input altogether with OmitInput.
+// Error handlers that read InputData (for compensation in a Handle, for
+// example) see the retained value, so redact only what they do not need.
+//
+// Example:
+//
+//	pipz.SetRetentionPolicy(pipz.RetentionPolicy{
+//	    Redact: func(v any) any {
+//	        switch v := v.(type) {
+//	        case Order:
+//	            v.CardNumber = ""
+//	            return v
+//	        case string: // error messages
+//	            return emailPattern.ReplaceAllString(v, "<email>")
+//	        }
+//	        return v
+//	    },
+//	    MaxBytes:         4096,
+//	    MaxMessageLength: 512,
+//	    TTL:              72 * time.Hour,
+//	    SampleRate:       0.1,
+//	})
+type RetentionPolicy struct {
+	// Redact rewrites every value before it is retained: input data, and
+	// error messages as strings. It must return a value of the same type;
+	// any other result retains the zero value instead. Return values of
+	// types it does not handle unchanged.
+	Redact func(any) any
+	// MaxBytes truncates retained string and []byte values (including
+	// named types over them) and error messages to this many bytes. Zero
+	// means no limit.
+	MaxBytes int
+	// TTL drops retained records once they are older than this. Zero keeps
+	// them until displaced. Errors are not stored by pipz, so TTL applies
+	// to History entries only.
+	TTL time.Duration
+	// SampleRate retains this fraction of records, evenly spaced and
+	// starting with the first: 0.1 keeps the 1st, 11th, 21st, and so on.
+	// Records not sampled are dropped, and errors not sampled capture the
+	// zero value as input. Zero, or one and above, retains every record.
+	SampleRate float64
+	// MaxMessageLength truncates the messages printed by Error[T] to this
+	// many characters, when Error() is called. Zero means no limit.
+	MaxMessageLength int
+	// OmitInput captures the zero value instead of the input in errors.
+	OmitInput bool
+}
+
+// retentionPolicy is the process-wide RetentionPolicy; nil means retain
+// everything.
+var retentionPolicy atomic.Pointer[RetentionPolicy]
+
+// inputSampler samples the inputs captured by errors.
+var inputSampler sampler
+
+// SetRetentionPolicy sets the process-wide RetentionPolicy. It is safe to
+// call concurrently with processing; data already retained is unaffected,
+// except for History entries past the TTL, which are dropped when read, and
+// error messages, which are truncated when printed.
+func SetRetentionPolicy(policy RetentionPolicy) {
+	if policy.isZero() {
+		retentionPolicy.Store(nil)
+		return
+	}
+	retentionPolicy.Store(&policy)
+}
+
+// CurrentRetentionPolicy returns the process-wide RetentionPolicy.
+func CurrentRetentionPolicy() RetentionPolicy {
+	if policy := retentionPolicy.Load(); policy != nil {
+		return *policy
+	}
+	return RetentionPolicy{}
+}
+
+// ApplyRetention applies the process-wide RetentionPolicy to data about to
+// be stored, returning the value to store, redacted and truncated, and
+// whether the policy's sampling retains it at all. Calls share one
+// sampling sequence, so call it once per record.
+//
+// Example:
+//
+//	deadLetter := pipz.Effect(DeadLetterID, func(ctx context.Context, o Order) error {
+//	    if o, ok := pipz.ApplyRetention(o); ok {
+//	        return dlq.Store(ctx, o)
+//	    }
+//	    return nil
+//	})
+func ApplyRetention[T any](data T) (T, bool) {
+	policy := retentionPolicy.Load()
+	if policy == nil {
+		return data, true
+	}
+	if !retentionSampler.take(policy.SampleRate) {
+		var zero T
+		return zero, false
+	}
+	return retainValue(*policy, data), true
+}
+
+// retentionSampler samples the records passed to ApplyRetention.
+var retentionSampler sampler
+
+// isZero reports whether the policy retains everything.
+func (p RetentionPolicy) isZero() bool {
+	return p.Redact == nil && p.MaxBytes <= 0 && p.TTL <= 0 && !p.samples() &&
+		p.MaxMessageLength <= 0 && !p.OmitInput
+}
+
+// samples reports whether the policy drops some records.
+func (p RetentionPolicy) samples() bool {
+	return p.SampleRate > 0 && p.SampleRate < 1
+}
+
+// expired reports whether a record retained at timestamp is past the TTL.
+func (p RetentionPolicy) expired(timestamp, now time.Time) bool {
+	return p.TTL > 0 && now.Sub(timestamp) > p.TTL
+}
+
+// retainMessage applies the policy's redaction and size limit to an error
+// message.
+func (p RetentionPolicy) retainMessage(msg string) string {
+	return retainValue(p, msg)
+}
+
+// retainValue applies the policy's redaction and size limit to data.
+func retainValue[T any](p RetentionPolicy, data T) T {
+	if p.Redact != nil {
+		redacted, ok := p.Redact(data).(T)
+		if !ok {
+			var zero T
+			return zero
+		}
+		data = redacted
+	}
+	if p.MaxBytes > 0 {
+		data = truncateInput(data, p.MaxBytes)
+	}
+	return data
+}
+
+// sampler keeps an evenly spaced fraction of the records offered to it.
+type sampler struct {
+	offered atomic.Uint64
+}
+
+// take reports whether the next record is kept at rate. Record n, counted
+// from one, is kept when ceil(n*rate) moves past ceil((n-1)*rate), so the
+// first record is always kept.
+func (s *sampler) take(rate float64) bool {
+	if rate <= 0 || rate >= 1 {
+		return true
+	}
+	n := float64(s.offered.Add(1))
+	return math.Ceil(n*rate) > math.Ceil((n-1)*rate)
+}
@@ -0,0 +1,135 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRetentionPolicy(t *testing.T) {
+	redactEmail := func(v any) any {
+		if u, ok := v.(errorDataUser); ok {
+			u.Email = "[redacted]"
+			return u
+		}
+		return v
+	}
+
+	t.Run("Sampler Keeps Evenly Spaced Records", func(t *testing.T) {
+		var s sampler
+		var kept []int
+		for n := 1; n <= 10; n++ {
+			if s.take(0.25) {
+				kept = append(kept, n)
+			}
+		}
+		if len(kept) != 3 || kept[0] != 1 || kept[1] != 5 || kept[2] != 9 {
+			t.Errorf("expected records 1, 5, and 9, got %v", kept)
+		}
+		for _, rate := range []float64{0, 1, 2} {
+			if !s.take(rate) {
+				t.Errorf("expected rate %v to keep every record", rate)
+			}
+		}
+	})
+
+	t.Run("Error Input Capture", func(t *testing.T) {
+		SetRetentionPolicy(RetentionPolicy{Redact: redactEmail})
+		defer SetRetentionPolicy(RetentionPolicy{})
+		failing := Apply(NewIdentity("apply", ""), func(_ context.Context, u errorDataUser) (errorDataUser, error) {
+			return u, errors.New("boom")
+		})
+		_, err := failing.Process(context.Background(), errorDataUser{Name: "ann", Email: "ann@example.com"})
+		var pipeErr *Error[errorDataUser]
+		if !errors.As(err, &pipeErr) || pipeErr.InputData.Email != "[redacted]" || pipeErr.InputData.Name != "ann" {
+			t.Errorf("expected redacted input data, got %+v", pipeErr)
+		}
+	})
+
+	t.Run("Error Input Limits", func(t *testing.T) {
+		SetRetentionPolicy(RetentionPolicy{MaxBytes: 4})
+		defer SetRetentionPolicy(RetentionPolicy{})
+		if got := errorInput("abcdefgh"); got != "abcd" {
+			t.Errorf("expected the input truncated, got %q", got)
+		}
+		SetRetentionPolicy(RetentionPolicy{Redact: redactEmail, OmitInput: true})
+		if got := errorInput(errorDataUser{Name: "ann"}); got != (errorDataUser{}) {
+			t.Errorf("expected the input omitted, got %+v", got)
+		}
+	})
+
+	t.Run("Sampled Error Input", func(t *testing.T) {
+		SetRetentionPolicy(RetentionPolicy{SampleRate: 0.5})
+		defer SetRetentionPolicy(RetentionPolicy{})
+		captured := 0
+		for range 10 {
+			if errorInput("payload") != "" {
+				captured++
+			}
+		}
+		if captured != 5 {
+			t.Errorf("expected half the inputs captured, got %d", captured)
+		}
+	})
+
+	t.Run("Sampled Step Error Keeps Input And Last Good Together", func(t *testing.T) {
+		SetRetentionPolicy(RetentionPolicy{SampleRate: 0.5})
+		defer SetRetentionPolicy(RetentionPolicy{})
+		seq := NewSequence(NewIdentity("seq", ""),
+			Transform(NewIdentity("suffix", ""), func(_ context.Context, s string) string { return s + "!" }),
+			Apply(NewIdentity("fail", ""), func(_ context.Context, s string) (string, error) {
+				return s, errors.New("boom")
+			}),
+		)
+		captured := 0
+		for range 10 {
+			_, err := seq.Process(context.Background(), "payload")
+			var pipeErr *Error[string]
+			if !errors.As(err, &pipeErr) {
+				t.Fatalf("expected a pipeline error, got %v", err)
+			}
+			if (pipeErr.InputData == "") != (pipeErr.LastGood == "") {
+				t.Errorf("expected input and last good kept or dropped together, got %q and %q", pipeErr.InputData, pipeErr.LastGood)
+			}
+			if pipeErr.LastGood != "" {
+				captured++
+			}
+		}
+		if captured != 5 {
+			t.Errorf("expected half the errors captured, got %d", captured)
+		}
+	})
+
+	t.Run("Apply Retention", func(t *testing.T) {
+		if got, ok := ApplyRetention("abcdefgh"); !ok || got != "abcdefgh" {
+			t.Errorf("expected data retained unchanged without a policy, got %q", got)
+		}
+		SetRetentionPolicy(RetentionPolicy{Redact: redactEmail, MaxBytes: 3, SampleRate: 0.5})
+		defer SetRetentionPolicy(RetentionPolicy{})
+		retained := 0
+		for range 4 {
+			got, ok := ApplyRetention(errorDataUser{Name: "ann", Email: "ann@example.com"})
+			if ok {
+				retained++
+				if got.Email != "[redacted]" {
+					t.Errorf("expected redacted data, got %+v", got)
+				}
+			}
+		}
+		if retained != 2 {
+			t.Errorf("expected half the records retained, got %d", retained)
+		}
+	})
+
+	t.Run("Current Retention Policy", func(t *testing.T) {
+		defer SetRetentionPolicy(RetentionPolicy{})
+		SetRetentionPolicy(RetentionPolicy{SampleRate: 1})
+		if retentionPolicy.Load() != nil {
+			t.Error("expected a policy retaining everything to be stored as none")
+		}
+		SetRetentionPolicy(RetentionPolicy{MaxBytes: 8})
+		if got := CurrentRetentionPolicy(); got.MaxBytes != 8 {
+			t.Errorf("expected stored policy, got %+v", got)
+		}
+	})
+}
@@ -3,10 +3,12 @@ package pipz
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
 )
 
 // Retry attempts the processor up to maxAttempts times.
@@ -27,7 +29,10 @@ import (
 //   - Any operation with intermittent failures
 //
 // For operations needing delay between retries, use RetryWithBackoff.
-// For trying different approaches, use Fallback instead.
+// For trying different approaches, use Fallback instead. When a failed
+// attempt returns a RetryAfterError, Retry waits the requested delay before
+// the next attempt. With SetStash, the input is stashed, compressed or
+// spilled to disk, during such waits.
 //
 // Example:
 //
@@ -39,6 +44,9 @@ import (
 //	)
 type Retry[T any] struct {
 	processor   Chainable[T]
+	clock       clockz.Clock
+	stash       Stash[T]
+	budget      *AttemptBudget
 	identity    Identity
 	maxAttempts int
 	mu          sync.RWMutex
@@ -59,14 +67,38 @@ func NewRetry[T any](identity Identity, processor Chainable[T], maxAttempts int)
 	}
 }
 
+// NewRetryWithOptions creates a Retry from functional options.
+// It accepts WithMaxAttempts (default 3). Invalid or inapplicable options
+// return an error wrapping ErrInvalidOption.
+func NewRetryWithOptions[T any](identity Identity, processor Chainable[T], opts ...Option) (*Retry[T], error) {
+	cfg, err := newConfig("retry", Config{MaxAttempts: 3}, optMaxAttempts, 0, opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewRetry(identity, processor, cfg.MaxAttempts), nil
+}
+
 // Process implements the Chainable interface.
 func (r *Retry[T]) Process(ctx context.Context, data T) (result T, err error) {
-	defer recoverFromPanic(&result, &err, r.identity, data)
+	defer recoverFromInputPanic(&result, &err, r.identity, &data)
+
+	ctx, guardErr := enterDepth(ctx, r, r.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
 	r.mu.RLock()
 	processor := r.processor
 	maxAttempts := r.maxAttempts
+	stash := r.stash
+	budget := r.budget
+	clock := r.getClock()
 	r.mu.RUnlock()
 
+	maxAttempts, ctx = allowedAttempts(ctx, r.identity, maxAttempts)
+	if budget != nil {
+		ctx = budget.earn(ctx)
+	}
+
 	var lastErr error
 	var lastResult T
 	name := r.identity.Name()
@@ -88,6 +120,11 @@ func (r *Retry[T]) Process(ctx context.Context, data T) (result T, err error) {
 			return result, nil
 		}
 
+		// Skips and early exits are deliberate outcomes, not failures to retry
+		if isControl(err) {
+			return result, err
+		}
+
 		// Attempt failed
 		lastErr = err
 		lastResult = result
@@ -106,13 +143,66 @@ func (r *Retry[T]) Process(ctx context.Context, data T) (result T, err error) {
 			// Context canceled/timed out - return error
 			return data, &Error[T]{
 				Err:       ctx.Err(),
-				InputData: data,
+				InputData: errorInput(data),
 				Path:      []Identity{r.identity},
 				Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
 				Canceled:  errors.Is(ctx.Err(), context.Canceled),
 				Timestamp: time.Now(),
 			}
 		}
+
+		// Stop retrying once the shared attempt budget is spent
+		if attempt < maxAttempts && budget != nil && !budget.spend() {
+			emitBudgetExhausted(ctx, r.identity, attempt)
+			break
+		}
+
+		// Honor a delay requested by the upstream before the next attempt
+		delay, ok := retryAfterDelay(err)
+		if !ok || delay == 0 || attempt == maxAttempts {
+			continue
+		}
+		capitan.Warn(ctx, SignalRetryWaiting,
+			FieldName.Field(name),
+			FieldIdentityID.Field(r.identity.ID().String()),
+			FieldAttempt.Field(attempt),
+			FieldMaxAttempts.Field(maxAttempts),
+			FieldDelay.Field(delay.Seconds()),
+		)
+
+		// Stash the input while waiting; the failed attempt's result and
+		// error may hold it too, and are superseded by the next attempt
+		restore := stashInput(stash, &data)
+		if restore != nil {
+			var zero T
+			lastErr, lastResult = nil, zero
+		}
+
+		select {
+		case <-clock.After(delay):
+		case <-ctx.Done():
+			if restore != nil {
+				data, _ = restore()
+			}
+			return data, &Error[T]{
+				Err:       ctx.Err(),
+				InputData: errorInput(data),
+				Path:      []Identity{r.identity},
+				Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
+				Canceled:  errors.Is(ctx.Err(), context.Canceled),
+				Timestamp: time.Now(),
+			}
+		}
+		if restore != nil {
+			var restoreErr error
+			if data, restoreErr = restore(); restoreErr != nil {
+				return data, &Error[T]{
+					Err:       fmt.Errorf("restoring stashed input: %w", restoreErr),
+					Path:      []Identity{r.identity},
+					Timestamp: time.Now(),
+				}
+			}
+		}
 	}
 
 	// All attempts failed - emit exhausted signal
@@ -128,13 +218,13 @@ func (r *Retry[T]) Process(ctx context.Context, data T) (result T, err error) {
 		var pipeErr *Error[T]
 		if errors.As(lastErr, &pipeErr) {
 			// Prepend this retry's identity to the path
-			pipeErr.Path = append([]Identity{r.identity}, pipeErr.Path...)
+			pipeErr.prependPath(ctx, r.identity)
 			return lastResult, pipeErr
 		}
 		// Handle non-pipeline errors by wrapping them
 		return lastResult, &Error[T]{
 			Timestamp: time.Now(),
-			InputData: data,
+			InputData: errorInput(data),
 			Err:       lastErr,
 			Path:      []Identity{r.identity},
 		}
@@ -153,6 +243,44 @@ func (r *Retry[T]) SetMaxAttempts(n int) *Retry[T] {
 	return r
 }
 
+// SetBudget makes retries spend from budget, shared with other Retry,
+// Backoff, and Hedge connectors in front of the same dependency. Once it
+// is spent, the last failure is returned without further attempts. Nil
+// removes the budget.
+func (r *Retry[T]) SetBudget(budget *AttemptBudget) *Retry[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.budget = budget
+	return r
+}
+
+// SetStash sets where the input is held while waiting for a delay the
+// upstream requested, such as NewCompressStash or NewSpillStash. Nil keeps
+// it in memory.
+func (r *Retry[T]) SetStash(stash Stash[T]) *Retry[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stash = stash
+	return r
+}
+
+// Reconfigure atomically updates the attempt limit from WithMaxAttempts.
+// In-flight calls finish with the limit they started with. Invalid or
+// inapplicable options return an error wrapping ErrInvalidOption.
+func (r *Retry[T]) Reconfigure(ctx context.Context, opts ...Option) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := newConfig("retry", Config{MaxAttempts: r.maxAttempts}, optMaxAttempts, 0, opts)
+	if err != nil {
+		return err
+	}
+
+	r.maxAttempts = cfg.MaxAttempts
+	emitReconfigured(ctx, r.identity, cfg)
+	return nil
+}
+
 // GetMaxAttempts returns the current maximum attempts setting.
 func (r *Retry[T]) GetMaxAttempts() int {
 	r.mu.RLock()
@@ -192,3 +320,19 @@ func (r *Retry[T]) Close() error {
 	})
 	return r.closeErr
 }
+
+// WithClock sets a custom clock for testing.
+func (r *Retry[T]) WithClock(clock clockz.Clock) *Retry[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = clock
+	return r
+}
+
+// getClock returns the clock to use.
+func (r *Retry[T]) getClock() clockz.Clock {
+	if r.clock == nil {
+		return clockz.RealClock
+	}
+	return r.clock
+}
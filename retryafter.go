@@ -0,0 +1,73 @@
+package pipz
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryAfterError wraps an error with the delay the upstream asked for
+// before the next attempt, such as an HTTP 429 or 503 Retry-After header or
+// a provider's rate-limit response. Retry waits Delay before retrying, and
+// Backoff waits Delay instead of its own exponential delay for that attempt,
+// so retry timing follows what the upstream actually requested.
+//
+// Example:
+//
+//	if resp.StatusCode == http.StatusTooManyRequests {
+//	    delay, _ := pipz.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+//	    return order, pipz.RetryAfter(errRateLimited, delay)
+//	}
+type RetryAfterError struct {
+	Err   error
+	Delay time.Duration
+}
+
+// RetryAfter wraps err with the delay to wait before retrying.
+func RetryAfter(err error, delay time.Duration) error {
+	return &RetryAfterError{Err: err, Delay: delay}
+}
+
+// Error implements the error interface.
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %v)", e.Err, e.Delay)
+}
+
+// Unwrap returns the underlying error.
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// ParseRetryAfter parses a Retry-After header value, given either as a
+// number of seconds or as an HTTP date, into a delay relative to now.
+// Dates in the past yield a zero delay. The second result is false when the
+// value is empty or malformed.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(at.Sub(now), 0), true
+}
+
+// retryAfterDelay extracts the requested delay from err, if any.
+func retryAfterDelay(err error) (time.Duration, bool) {
+	var ra *RetryAfterError
+	if !errors.As(err, &ra) || ra.Delay < 0 {
+		return 0, false
+	}
+	return ra.Delay, true
+}
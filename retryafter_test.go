@@ -0,0 +1,184 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"Seconds", "120", 2 * time.Minute, true},
+		{"Padded Seconds", " 5 ", 5 * time.Second, true},
+		{"HTTP Date", now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{"Past Date", now.Add(-time.Hour).Format(http.TimeFormat), 0, true},
+		{"Empty", "", 0, false},
+		{"Negative", "-1", 0, false},
+		{"Malformed", "soon", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ParseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRetryAfterError(t *testing.T) {
+	errLimited := errors.New("rate limited")
+	err := RetryAfter(errLimited, 2*time.Second)
+
+	if !errors.Is(err, errLimited) {
+		t.Error("expected RetryAfterError to unwrap to the cause")
+	}
+	if err.Error() != "rate limited (retry after 2s)" {
+		t.Errorf("unexpected message: %q", err.Error())
+	}
+	wrapped := &Error[int]{Err: err, Path: []Identity{NewIdentity("call", "")}}
+	if delay, ok := retryAfterDelay(wrapped); !ok || delay != 2*time.Second {
+		t.Errorf("expected delay found through pipz error, got %v (%v)", delay, ok)
+	}
+}
+
+// rateLimited fails with a RetryAfterError until it has been called limit
+// times.
+func rateLimited(calls *atomic.Int32, limit int32, delay time.Duration) Chainable[int] {
+	return Apply(NewIdentity("upstream", ""), func(_ context.Context, v int) (int, error) {
+		if calls.Add(1) <= limit {
+			return v, RetryAfter(errors.New("429"), delay)
+		}
+		return v * 2, nil
+	})
+}
+
+// waitForCalls advances clock by step once the retry waits on it, then
+// waits until calls reaches n.
+func waitForCalls(t *testing.T, clock *clockz.FakeClock, calls *atomic.Int32, n int32, step time.Duration) {
+	t.Helper()
+	advanceWhenWaiting(t, clock, step)
+	deadline := time.Now().Add(time.Second)
+	for calls.Load() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d calls, got %d", n, calls.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	t.Run("Waits Requested Delay", func(t *testing.T) {
+		var calls atomic.Int32
+		clock := clockz.NewFakeClock()
+		retry := NewRetry(NewIdentity("retry", ""), rateLimited(&calls, 1, 5*time.Second), 3).WithClock(clock)
+
+		done := make(chan int)
+		go func() {
+			result, _ := retry.Process(context.Background(), 4) //nolint:errcheck // result checked below
+			done <- result
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		select {
+		case <-done:
+			t.Fatal("expected retry to wait for the requested delay")
+		default:
+		}
+		if calls.Load() != 1 {
+			t.Fatalf("expected 1 call before the delay, got %d", calls.Load())
+		}
+		waitForCalls(t, clock, &calls, 2, 5*time.Second)
+		if result := <-done; result != 8 {
+			t.Errorf("expected 8, got %d", result)
+		}
+	})
+
+	t.Run("No Wait After Last Attempt", func(t *testing.T) {
+		var calls atomic.Int32
+		retry := NewRetry(NewIdentity("retry", ""), rateLimited(&calls, 5, time.Hour), 1).WithClock(clockz.NewFakeClock())
+		_, err := retry.Process(context.Background(), 1)
+		var ra *RetryAfterError
+		if !errors.As(err, &ra) || ra.Delay != time.Hour {
+			t.Errorf("expected RetryAfterError to surface, got %v", err)
+		}
+	})
+
+	t.Run("Context Cancels Wait", func(t *testing.T) {
+		var calls atomic.Int32
+		retry := NewRetry(NewIdentity("retry", ""), rateLimited(&calls, 5, time.Hour), 3).WithClock(clockz.NewFakeClock())
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := retry.Process(ctx, 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.IsTimeout() {
+			t.Fatalf("expected timeout error, got %v", err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("expected 1 call, got %d", calls.Load())
+		}
+	})
+
+	t.Run("Emits Waiting Signal", func(t *testing.T) {
+		var delay float64
+		listener := capitan.Hook(SignalRetryWaiting, func(_ context.Context, e *capitan.Event) {
+			delay, _ = FieldDelay.From(e)
+		})
+		defer listener.Close()
+
+		var calls atomic.Int32
+		clock := clockz.NewFakeClock()
+		retry := NewRetry(NewIdentity("retry", ""), rateLimited(&calls, 1, 3*time.Second), 2).WithClock(clock)
+		done := make(chan struct{})
+		go func() {
+			_, _ = retry.Process(context.Background(), 1) //nolint:errcheck // signal checked below
+			close(done)
+		}()
+		waitForCalls(t, clock, &calls, 2, 3*time.Second)
+		<-done
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if delay != 3 {
+			t.Errorf("expected delay 3, got %v", delay)
+		}
+	})
+}
+
+func TestBackoffHonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	clock := clockz.NewFakeClock()
+	backoff := NewBackoff(NewIdentity("backoff", ""), rateLimited(&calls, 1, time.Minute), 3, time.Millisecond).WithClock(clock)
+
+	done := make(chan int)
+	go func() {
+		result, _ := backoff.Process(context.Background(), 4) //nolint:errcheck // result checked below
+		done <- result
+	}()
+
+	// The base delay alone must not release the wait.
+	advanceWhenWaiting(t, clock, time.Second)
+	time.Sleep(10 * time.Millisecond)
+	if calls.Load() != 1 {
+		t.Fatalf("expected backoff to wait the requested minute, got %d calls", calls.Load())
+	}
+
+	waitForCalls(t, clock, &calls, 2, time.Minute)
+	if result := <-done; result != 8 {
+		t.Errorf("expected 8, got %d", result)
+	}
+}
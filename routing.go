@@ -0,0 +1,173 @@
+package pipz
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Routing kinds reported in RouteSpec.Kind.
+const (
+	RouteKindField  = "field"
+	RouteKindRegexp = "regexp"
+	RouteKindRange  = "range"
+)
+
+// RouteSpec describes a Router's logic so it appears in Switch schemas and
+// exported diagrams instead of being an opaque closure.
+type RouteSpec struct {
+	Kind    string      `json:"kind"`
+	Field   string      `json:"field,omitempty"`
+	Default string      `json:"default,omitempty"`
+	Rules   []RouteRule `json:"rules,omitempty"`
+}
+
+// RouteRule describes one rule of a RouteSpec: the regular expression or
+// lower bound that selects Route.
+type RouteRule struct {
+	Min     any    `json:"min,omitempty"`
+	Route   string `json:"route"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// Router is a Switch condition built from declarative rules. Unlike a plain
+// Condition it carries a RouteSpec describing its logic, which a Switch
+// created with NewSwitchRouter or updated with SetRouter includes in its
+// schema. Build one with RouteByField, RouteByRegexp, or RouteByRange.
+type Router[T any] struct {
+	route func(T) (string, bool)
+	spec  RouteSpec
+}
+
+// Condition returns the router as a Condition usable with NewSwitch or
+// anywhere else a Condition is accepted.
+func (r Router[T]) Condition() Condition[T] {
+	def := r.spec.Default
+	route := r.route
+	return func(_ context.Context, data T) string {
+		if key, ok := route(data); ok {
+			return key
+		}
+		return def
+	}
+}
+
+// Spec returns a description of the router's logic.
+func (r Router[T]) Spec() RouteSpec {
+	spec := r.spec
+	spec.Rules = slices.Clone(r.spec.Rules)
+	return spec
+}
+
+// WithDefault returns a copy of the router that yields route when no rule
+// matches (or, for RouteByField, when the field holds its zero value).
+func (r Router[T]) WithDefault(route string) Router[T] {
+	r.spec.Default = route
+	return r
+}
+
+// RouteByField routes on a field of the input: the route key is the value
+// returned by key, formatted with fmt.Sprint. field names the field in the
+// schema.
+//
+// Example:
+//
+//	router := pipz.NewSwitchRouter(RegionRouterID,
+//	    pipz.RouteByField("region", func(o Order) string { return o.Region }).
+//	        WithDefault("us"),
+//	)
+//	router.AddRoute("us", usProcessor)
+//	router.AddRoute("eu", euProcessor)
+func RouteByField[T any, K comparable](field string, key func(T) K) Router[T] {
+	return Router[T]{
+		route: func(data T) (string, bool) {
+			var zero K
+			k := key(data)
+			if k == zero {
+				return "", false
+			}
+			if s, ok := any(k).(string); ok {
+				return s, true
+			}
+			return fmt.Sprint(k), true
+		},
+		spec: RouteSpec{Kind: RouteKindField, Field: field},
+	}
+}
+
+// RegexpRoute maps payloads matching Pattern to Route.
+type RegexpRoute struct {
+	Pattern *regexp.Regexp
+	Route   string
+}
+
+// RouteByRegexp routes string payloads by regular expression. Rules are
+// tried in order and the first matching pattern wins.
+//
+// Example:
+//
+//	router := pipz.NewSwitchRouter(LogRouterID,
+//	    pipz.RouteByRegexp[string](
+//	        pipz.RegexpRoute{Pattern: regexp.MustCompile(`^ERROR`), Route: "alert"},
+//	        pipz.RegexpRoute{Pattern: regexp.MustCompile(`^WARN`), Route: "review"},
+//	    ).WithDefault("archive"),
+//	)
+func RouteByRegexp[T ~string](rules ...RegexpRoute) Router[T] {
+	rules = slices.Clone(rules)
+	spec := RouteSpec{Kind: RouteKindRegexp, Rules: make([]RouteRule, len(rules))}
+	for i, rule := range rules {
+		spec.Rules[i] = RouteRule{Route: rule.Route, Pattern: rule.Pattern.String()}
+	}
+	return Router[T]{
+		route: func(data T) (string, bool) {
+			for _, rule := range rules {
+				if rule.Pattern.MatchString(string(data)) {
+					return rule.Route, true
+				}
+			}
+			return "", false
+		},
+		spec: spec,
+	}
+}
+
+// RangeRoute maps values at or above Min to Route.
+type RangeRoute[N cmp.Ordered] struct {
+	Min   N
+	Route string
+}
+
+// RouteByRange routes on numeric thresholds. The value returned by value is
+// matched to the band with the highest Min not greater than it; values below
+// every band match nothing. field names the value in the schema.
+//
+// Example:
+//
+//	router := pipz.NewSwitchRouter(PaymentRouterID,
+//	    pipz.RouteByRange("amount", func(p Payment) float64 { return p.Amount },
+//	        pipz.RangeRoute[float64]{Min: 0, Route: "standard"},
+//	        pipz.RangeRoute[float64]{Min: 10000, Route: "high_value"},
+//	    ),
+//	)
+func RouteByRange[T any, N cmp.Ordered](field string, value func(T) N, bands ...RangeRoute[N]) Router[T] {
+	bands = slices.Clone(bands)
+	slices.SortStableFunc(bands, func(a, b RangeRoute[N]) int { return cmp.Compare(a.Min, b.Min) })
+	spec := RouteSpec{Kind: RouteKindRange, Field: field, Rules: make([]RouteRule, len(bands))}
+	for i, band := range bands {
+		spec.Rules[i] = RouteRule{Route: band.Route, Min: band.Min}
+	}
+	return Router[T]{
+		route: func(data T) (string, bool) {
+			v := value(data)
+			for i := len(bands) - 1; i >= 0; i-- {
+				if v >= bands[i].Min {
+					return bands[i].Route, true
+				}
+			}
+			return "", false
+		},
+		spec: spec,
+	}
+}
@@ -0,0 +1,104 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type routedOrder struct {
+	Region string
+	Amount float64
+	Tier   int
+}
+
+func TestRouting(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("RouteByField", func(t *testing.T) {
+		cond := RouteByField("region", func(o routedOrder) string { return o.Region }).WithDefault("us").Condition()
+		if got := cond(ctx, routedOrder{Region: "eu"}); got != "eu" {
+			t.Errorf("expected eu, got %q", got)
+		}
+		if got := cond(ctx, routedOrder{}); got != "us" {
+			t.Errorf("expected default us, got %q", got)
+		}
+		tier := RouteByField("tier", func(o routedOrder) int { return o.Tier }).Condition()
+		if got := tier(ctx, routedOrder{Tier: 2}); got != "2" {
+			t.Errorf("expected 2, got %q", got)
+		}
+	})
+
+	t.Run("RouteByRegexp", func(t *testing.T) {
+		cond := RouteByRegexp[string](
+			RegexpRoute{Pattern: regexp.MustCompile(`^ERROR`), Route: "alert"},
+			RegexpRoute{Pattern: regexp.MustCompile(`ERROR|WARN`), Route: "review"},
+		).WithDefault("archive").Condition()
+		cases := map[string]string{"ERROR disk": "alert", "WARN: ERROR soon": "review", "INFO ok": "archive"}
+		for in, want := range cases {
+			if got := cond(ctx, in); got != want {
+				t.Errorf("%q: expected %s, got %s", in, want, got)
+			}
+		}
+	})
+
+	t.Run("RouteByRange", func(t *testing.T) {
+		router := RouteByRange("amount", func(o routedOrder) float64 { return o.Amount },
+			RangeRoute[float64]{Min: 10000, Route: "high_value"},
+			RangeRoute[float64]{Min: 0, Route: "standard"},
+		)
+		cond := router.Condition()
+		cases := map[float64]string{-1: "", 0: "standard", 9999.99: "standard", 10000: "high_value"}
+		for in, want := range cases {
+			if got := cond(ctx, routedOrder{Amount: in}); got != want {
+				t.Errorf("%v: expected %q, got %q", in, want, got)
+			}
+		}
+		if spec := router.Spec(); spec.Rules[0].Route != "standard" {
+			t.Errorf("expected rules sorted by min, got %+v", spec.Rules)
+		}
+	})
+
+	t.Run("Switch Routes And Exposes Spec", func(t *testing.T) {
+		double := Transform(NewIdentity("double", ""), func(_ context.Context, o routedOrder) routedOrder {
+			o.Amount *= 2
+			return o
+		})
+		sw := NewSwitchRouter(NewIdentity("by-amount", ""),
+			RouteByRange("amount", func(o routedOrder) float64 { return o.Amount },
+				RangeRoute[float64]{Min: 100, Route: "big"},
+			),
+		).AddRoute("big", double)
+
+		result, err := sw.Process(ctx, routedOrder{Amount: 150})
+		if err != nil || result.Amount != 300 {
+			t.Fatalf("unexpected result: %+v, %v", result, err)
+		}
+
+		flow, ok := SwitchKey.From(sw.Schema())
+		if !ok || flow.Routing == nil || flow.Routing.Kind != RouteKindRange || flow.Routing.Field != "amount" {
+			t.Fatalf("unexpected routing: %+v", flow.Routing)
+		}
+		data, err := json.Marshal(sw.Schema())
+		if err != nil {
+			t.Fatalf("marshal failed: %v", err)
+		}
+		if !strings.Contains(string(data), `"routing":{"kind":"range","field":"amount","rules":[{"min":100,"route":"big"}]}`) {
+			t.Errorf("unexpected schema JSON: %s", data)
+		}
+	})
+
+	t.Run("SetCondition Clears Spec", func(t *testing.T) {
+		sw := NewSwitch(NewIdentity("sw", ""), func(_ context.Context, _ string) string { return "" })
+		sw.SetRouter(RouteByRegexp[string](RegexpRoute{Pattern: regexp.MustCompile(`a`), Route: "a"}))
+		if flow, _ := SwitchKey.From(sw.Schema()); flow.Routing == nil || flow.Routing.Kind != RouteKindRegexp {
+			t.Fatalf("expected regexp routing, got %+v", flow.Routing)
+		}
+		sw.SetCondition(func(_ context.Context, _ string) string { return "" })
+		if flow, _ := SwitchKey.From(sw.Schema()); flow.Routing != nil {
+			t.Errorf("expected routing cleared, got %+v", flow.Routing)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrPanic is wrapped by every error produced from a recovered panic, whether
+// recovered by a processor, a connector, Recover, or SafeGo.
+var ErrPanic = errors.New("panic recovered")
+
+// Recover runs fn and converts a panic into an error wrapping ErrPanic,
+// sanitized the same way processor panics are so memory addresses, file
+// paths, and stack traces never leak into logs. fn's own error is returned
+// unchanged. A panic.recovered signal is emitted for every recovered panic.
+//
+// Example:
+//
+//	err := pipz.Recover(func() error {
+//	    return legacyParser.Parse(payload) // may panic on malformed input
+//	})
+func Recover(fn func() error) error {
+	return recoverCall(context.Background(), fn)
+}
+
+// SafeGo runs fn in a new goroutine with the same panic recovery as Recover.
+// The returned channel receives fn's result (nil, fn's error, or the
+// recovered panic) and is then closed, so callers can wait on it or ignore
+// it. Use SafeGo instead of a bare go statement inside processors that fan
+// out work, so a panic fails the item rather than crashing the process.
+//
+// Example:
+//
+//	fetch := pipz.Apply(FetchID, func(ctx context.Context, o Order) (Order, error) {
+//	    stock := pipz.SafeGo(ctx, func(ctx context.Context) error { return loadStock(ctx, &o) })
+//	    price := pipz.SafeGo(ctx, func(ctx context.Context) error { return loadPrice(ctx, &o) })
+//	    return o, errors.Join(<-stock, <-price)
+//	})
+func SafeGo(ctx context.Context, fn func(context.Context) error) <-chan error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	done := make(chan error, 1)
+	go func() {
+		defer close(done)
+		done <- recoverCall(ctx, func() error { return fn(ctx) })
+	}()
+	return done
+}
+
+// recoverCall runs fn, converting a panic into a sanitized panicError.
+func recoverCall(ctx context.Context, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			pe := newPanicError(Identity{}, r)
+			capitan.Error(ctx, SignalPanicRecovered,
+				FieldError.Field(pe.sanitized),
+			)
+			err = pe
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,75 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestRecover(t *testing.T) {
+	t.Run("Returns Function Error", func(t *testing.T) {
+		want := errors.New("parse failed")
+		if err := Recover(func() error { return want }); !errors.Is(err, want) {
+			t.Errorf("expected %v, got %v", want, err)
+		}
+	})
+
+	t.Run("Converts Panic", func(t *testing.T) {
+		err := Recover(func() error { panic("bad input") })
+		if !errors.Is(err, ErrPanic) {
+			t.Fatalf("expected ErrPanic, got %v", err)
+		}
+		if err.Error() != "panic occurred: bad input" {
+			t.Errorf("unexpected message: %q", err.Error())
+		}
+	})
+
+	t.Run("Sanitizes Panic", func(t *testing.T) {
+		err := Recover(func() error { panic("failed at /home/app/secret.go") })
+		if strings.Contains(err.Error(), "/home") {
+			t.Errorf("expected sanitized message, got %q", err.Error())
+		}
+	})
+}
+
+func TestSafeGo(t *testing.T) {
+	t.Run("Delivers Result", func(t *testing.T) {
+		err := <-SafeGo(context.Background(), func(_ context.Context) error { return nil })
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Recovers Panic And Emits Signal", func(t *testing.T) {
+		var msg string
+		listener := capitan.Hook(SignalPanicRecovered, func(_ context.Context, e *capitan.Event) {
+			msg, _ = FieldError.From(e)
+		})
+		defer listener.Close()
+
+		done := SafeGo(context.Background(), func(_ context.Context) error { panic("boom") })
+		if err := <-done; !errors.Is(err, ErrPanic) {
+			t.Fatalf("expected ErrPanic, got %v", err)
+		}
+		if _, open := <-done; open {
+			t.Error("expected channel to be closed")
+		}
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if msg != "panic occurred: boom" {
+			t.Errorf("unexpected signal message: %q", msg)
+		}
+	})
+
+	t.Run("Processor Panics Wrap ErrPanic", func(t *testing.T) {
+		proc := Transform(NewIdentity("explode", ""), func(_ context.Context, _ int) int { panic("boom") })
+		if _, err := proc.Process(context.Background(), 1); !errors.Is(err, ErrPanic) {
+			t.Errorf("expected ErrPanic, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package pipz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultMaxPanicLength is the longest panic message kept by the default
+// SanitizerPolicy.
+const defaultMaxPanicLength = 200
+
+// SanitizerPolicy controls how recovered panic messages are rendered in
+// errors. The zero value is the secure default used everywhere unless a
+// Pipeline sets a policy: memory addresses are masked, and messages that
+// contain file paths, stack traces, or runtime package names, or that
+// exceed 200 bytes, are replaced with a generic notice.
+//
+// Security-sensitive deployments can add Redactors for secrets the default
+// rules would miss; debug environments can keep details and the stack.
+//
+// Example:
+//
+//	// Production: also scrub tokens.
+//	tokens := regexp.MustCompile(`tok_[A-Za-z0-9]+`)
+//	pipeline.SetSanitizer(pipz.SanitizerPolicy{
+//	    Redactors: []func(string) string{
+//	        func(msg string) string { return tokens.ReplaceAllString(msg, "tok_***") },
+//	    },
+//	})
+//
+//	// Development: show everything.
+//	pipeline.SetSanitizer(pipz.SanitizerPolicy{KeepDetails: true, KeepStack: true, MaxLength: -1})
+type SanitizerPolicy struct {
+	// Redactors rewrite the panic message, in order, before any other rule.
+	Redactors []func(string) string
+
+	// MaxLength caps the message length. Zero keeps the default: messages
+	// over 200 bytes are replaced entirely. A positive value truncates the
+	// message to that many bytes instead; a negative value keeps it whole.
+	MaxLength int
+
+	// KeepDetails disables the built-in rules that mask memory addresses
+	// and replace messages mentioning file paths or stack traces.
+	KeepDetails bool
+
+	// KeepStack appends the stack of the panicking goroutine to the message.
+	KeepStack bool
+}
+
+// stackKeepers counts the pipelines whose SanitizerPolicy keeps the stack.
+// Capturing a stack is costly, so recovered panics capture one only while
+// some pipeline may render it.
+var stackKeepers atomic.Int32
+
+// sanitize renders a recovered panic value under the policy.
+func (p SanitizerPolicy) sanitize(value any, stack []byte) string {
+	msg := p.message(value)
+	if p.KeepStack && len(stack) > 0 {
+		msg += "\n" + string(stack)
+	}
+	return msg
+}
+
+// message renders the panic value without the stack.
+func (p SanitizerPolicy) message(value any) string {
+	if value == nil {
+		return "unknown panic (nil value)"
+	}
+
+	msg := fmt.Sprintf("%v", value)
+	for _, redact := range p.Redactors {
+		msg = redact(msg)
+	}
+
+	if !p.KeepDetails {
+		msg = maskAddresses(msg)
+
+		// Remove file paths that might contain sensitive directory names
+		if strings.Contains(msg, "/") || strings.Contains(msg, "\\") {
+			return "panic occurred (file path sanitized)"
+		}
+	}
+
+	switch {
+	case p.MaxLength == 0 && len(msg) > defaultMaxPanicLength:
+		// If message is very long, truncate to prevent excessive log spam
+		return "panic occurred (message truncated for security)"
+	case p.MaxLength > 0 && len(msg) > p.MaxLength:
+		msg = strings.ToValidUTF8(msg[:p.MaxLength], "") + truncatedSuffix
+	}
+
+	// Remove any potential stack trace information and package addresses
+	if !p.KeepDetails && (strings.Contains(msg, "goroutine") || strings.Contains(msg, "runtime.") ||
+		strings.Contains(msg, "sync.") || strings.Contains(msg, "net.") ||
+		strings.Contains(msg, "os.") || strings.Contains(msg, "fmt.") ||
+		strings.Contains(msg, "io.")) {
+		return "panic occurred (stack trace sanitized)"
+	}
+
+	return fmt.Sprintf("panic occurred: %s", msg)
+}
+
+// maskAddresses replaces the hex digits of memory addresses with ***.
+func maskAddresses(msg string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(msg, "0x")
+		if start < 0 {
+			b.WriteString(msg)
+			return b.String()
+		}
+		end := start + 2
+		// Find end of hex address
+		for end < len(msg) && ((msg[end] >= '0' && msg[end] <= '9') ||
+			(msg[end] >= 'a' && msg[end] <= 'f') ||
+			(msg[end] >= 'A' && msg[end] <= 'F')) {
+			end++
+		}
+		b.WriteString(msg[:start])
+		if end > start+2 {
+			b.WriteString("0x***")
+		} else {
+			b.WriteString("0x")
+		}
+		msg = msg[end:]
+	}
+}
+
+// applySanitizer re-renders the recovered panic in err under policy. The
+// innermost Pipeline with a policy wins.
+func applySanitizer(err error, policy SanitizerPolicy) {
+	var pe *panicError
+	if !errors.As(err, &pe) || pe.policied {
+		return
+	}
+	pe.sanitized = policy.sanitize(pe.value, pe.stack)
+	pe.policied = true
+}
@@ -0,0 +1,116 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSanitizerPolicy(t *testing.T) {
+	panicking := func(value any) Chainable[int] {
+		return Apply(NewIdentity("boom", ""), func(context.Context, int) (int, error) {
+			panic(value)
+		})
+	}
+	panicMessage := func(t *testing.T, err error) string {
+		t.Helper()
+		if !errors.Is(err, ErrPanic) {
+			t.Fatalf("expected panic error, got %v", err)
+		}
+		var pe *panicError
+		if !errors.As(err, &pe) {
+			t.Fatalf("expected panicError in %v", err)
+		}
+		return pe.sanitized
+	}
+
+	t.Run("Default Rules", func(t *testing.T) {
+		cases := map[string]string{
+			"bad state":                   "panic occurred: bad state",
+			"at 0xc000123 and 0xABCDEF":   "panic occurred: at 0x*** and 0x***",
+			"open /etc/secrets/key":       "panic occurred (file path sanitized)",
+			"goroutine 7 [running]":       "panic occurred (stack trace sanitized)",
+			strings.Repeat("x", 201):      "panic occurred (message truncated for security)",
+			"0x without digits":           "panic occurred: 0x without digits",
+			"runtime.gopanic called here": "panic occurred (stack trace sanitized)",
+		}
+		for input, want := range cases {
+			if got := sanitizePanicMessage(input); got != want {
+				t.Errorf("sanitize(%q): expected %q, got %q", input, want, got)
+			}
+		}
+		if got := sanitizePanicMessage(nil); got != "unknown panic (nil value)" {
+			t.Errorf("unexpected nil message: %q", got)
+		}
+	})
+
+	t.Run("Default Outside Pipeline", func(t *testing.T) {
+		_, err := panicking("open /srv/app/config.yaml").Process(context.Background(), 1)
+		if got := panicMessage(t, err); got != "panic occurred (file path sanitized)" {
+			t.Errorf("unexpected message: %q", got)
+		}
+	})
+
+	t.Run("Redactors Run First", func(t *testing.T) {
+		p := NewPipeline(NewIdentity("p", ""), panicking("token tok_abc123 rejected")).
+			SetSanitizer(SanitizerPolicy{Redactors: []func(string) string{
+				func(msg string) string { return strings.ReplaceAll(msg, "tok_abc123", "tok_***") },
+			}})
+		_, err := p.Process(context.Background(), 1)
+		if got := panicMessage(t, err); got != "panic occurred: token tok_*** rejected" {
+			t.Errorf("unexpected message: %q", got)
+		}
+		if !strings.Contains(err.Error(), "tok_***") || strings.Contains(err.Error(), "abc123") {
+			t.Errorf("expected redacted error text, got %q", err.Error())
+		}
+	})
+
+	t.Run("Debug Keeps Details And Stack", func(t *testing.T) {
+		p := NewPipeline(NewIdentity("p", ""), panicking("open /srv/app/config.yaml at 0xc000123")).
+			SetSanitizer(SanitizerPolicy{KeepDetails: true, KeepStack: true, MaxLength: -1})
+		_, err := p.Process(context.Background(), 1)
+		got := panicMessage(t, err)
+		if !strings.HasPrefix(got, "panic occurred: open /srv/app/config.yaml at 0xc000123\n") {
+			t.Errorf("expected raw message, got %q", got)
+		}
+		if !strings.Contains(got, "goroutine") || !strings.Contains(got, "sanitize_test.go") {
+			t.Errorf("expected stack with the panicking frame, got %q", got)
+		}
+		p.SetSanitizer(SanitizerPolicy{})
+	})
+
+	t.Run("Stack Captured Only While Kept", func(t *testing.T) {
+		if pe := newPanicError(Identity{}, "boom"); pe.stack != nil {
+			t.Error("expected no stack without a policy keeping it")
+		}
+		p := NewPipeline(NewIdentity("p", ""), panicking("boom")).SetSanitizer(SanitizerPolicy{KeepStack: true})
+		if pe := newPanicError(Identity{}, "boom"); pe.stack == nil {
+			t.Error("expected a stack while a policy keeps it")
+		}
+		p.SetSanitizer(SanitizerPolicy{})
+		if pe := newPanicError(Identity{}, "boom"); pe.stack != nil {
+			t.Error("expected no stack once the policy is replaced")
+		}
+	})
+
+	t.Run("Max Length Truncates", func(t *testing.T) {
+		p := NewPipeline(NewIdentity("p", ""), panicking(strings.Repeat("y", 300))).
+			SetSanitizer(SanitizerPolicy{MaxLength: 10})
+		_, err := p.Process(context.Background(), 1)
+		if got := panicMessage(t, err); got != "panic occurred: yyyyyyyyyy"+truncatedSuffix {
+			t.Errorf("unexpected message: %q", got)
+		}
+	})
+
+	t.Run("Innermost Pipeline Wins", func(t *testing.T) {
+		inner := NewPipeline(NewIdentity("inner", ""), panicking("open /tmp/x")).
+			SetSanitizer(SanitizerPolicy{KeepDetails: true})
+		outer := NewPipeline(NewIdentity("outer", ""), Chainable[int](inner)).
+			SetSanitizer(SanitizerPolicy{})
+		_, err := outer.Process(context.Background(), 1)
+		if got := panicMessage(t, err); got != "panic occurred: open /tmp/x" {
+			t.Errorf("expected inner policy message, got %q", got)
+		}
+	})
+}
@@ -37,6 +37,7 @@ import (
 //   - Original input always returned unchanged
 //   - No error reporting from background processors
 //   - Trace context is preserved (but cancellation is not)
+//   - WithMaxConcurrency bounds how many processors run at once per call
 //
 // Example:
 //
@@ -52,6 +53,7 @@ import (
 type Scaffold[T Cloner[T]] struct {
 	identity   Identity
 	processors []Chainable[T]
+	limit      int
 	mu         sync.RWMutex
 	closeOnce  sync.Once
 	closeErr   error
@@ -69,9 +71,15 @@ func NewScaffold[T Cloner[T]](identity Identity, processors ...Chainable[T]) *Sc
 func (s *Scaffold[T]) Process(ctx context.Context, input T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, s.identity, input)
 
+	ctx, guardErr := enterDepth(ctx, s, s.identity, input)
+	if guardErr != nil {
+		return input, guardErr
+	}
+
 	s.mu.RLock()
 	processors := make([]Chainable[T], len(s.processors))
 	copy(processors, s.processors)
+	limit := s.limit
 	s.mu.RUnlock()
 
 	if len(processors) == 0 {
@@ -81,18 +89,34 @@ func (s *Scaffold[T]) Process(ctx context.Context, input T) (result T, err error
 	// Create context that won't be canceled when parent is
 	bgCtx := context.WithoutCancel(ctx)
 
-	// Launch all processors in background without waiting
-	for _, processor := range processors {
-		go func(p Chainable[T]) {
-			// Create an isolated copy using the Clone method
-			inputCopy := input.Clone()
+	run := func(p Chainable[T]) {
+		// Create an isolated copy using the Clone method
+		inputCopy := input.Clone()
+
+		// Process with isolated context - continues even if parent canceled
+		if _, err := p.Process(bgCtx, inputCopy); err != nil {
+			// Fire-and-forget: errors are not reported back
+			_ = err
+		}
+	}
 
-			// Process with isolated context - continues even if parent canceled
-			if _, err := p.Process(bgCtx, inputCopy); err != nil {
-				// Fire-and-forget: errors are not reported back
-				_ = err
+	if limit > 0 && limit < len(processors) {
+		// Dispatch in the background so the caller still returns at once
+		go func() {
+			sem := make(chan struct{}, limit)
+			for _, processor := range processors {
+				sem <- struct{}{}
+				go func(p Chainable[T]) {
+					defer func() { <-sem }()
+					run(p)
+				}(processor)
 			}
-		}(processor)
+		}()
+	} else {
+		// Launch all processors in background without waiting
+		for _, processor := range processors {
+			go run(processor)
+		}
 	}
 
 	// Emit dispatched signal
@@ -106,8 +130,20 @@ func (s *Scaffold[T]) Process(ctx context.Context, input T) (result T, err error
 	return input, nil
 }
 
+// WithMaxConcurrency caps the number of processors running at once for each
+// dispatched input. Process still returns immediately; processors beyond the
+// limit start in the background as earlier ones finish. Zero or negative
+// means no limit.
+func (s *Scaffold[T]) WithMaxConcurrency(n int) *Scaffold[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = n
+	return s
+}
+
 // Add appends a processor to the scaffold execution list.
 func (s *Scaffold[T]) Add(processor Chainable[T]) *Scaffold[T] {
+	noteChildren(s.identity, processor)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.processors = append(s.processors, processor)
@@ -144,6 +180,7 @@ func (s *Scaffold[T]) Clear() *Scaffold[T] {
 
 // SetProcessors replaces all processors atomically.
 func (s *Scaffold[T]) SetProcessors(processors ...Chainable[T]) *Scaffold[T] {
+	noteChildren(s.identity, processors...)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.processors = make([]Chainable[T], len(processors))
@@ -172,6 +209,9 @@ func (s *Scaffold[T]) Schema() Node {
 		Identity: s.identity,
 		Type:     "scaffold",
 		Flow:     ScaffoldFlow{Processors: processors},
+		Metadata: map[string]any{
+			"max_concurrency": s.limit,
+		},
 	}
 }
 
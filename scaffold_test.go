@@ -3,6 +3,7 @@ package pipz
 import (
 	"context"
 	"errors"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -220,6 +221,40 @@ func TestScaffold(t *testing.T) {
 		// But we can verify that the main process completes successfully
 		// This test ensures panic recovery in the main process works correctly
 	})
+
+	t.Run("Max Concurrency Bounds Parallelism", func(t *testing.T) {
+		var running, peak atomic.Int32
+		var wg sync.WaitGroup
+		wg.Add(6)
+		track := Effect(NewIdentity("track", ""), func(_ context.Context, _ TestData) error {
+			defer wg.Done()
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+
+		scaffold := NewScaffold(NewIdentity("bounded", ""), track, track, track, track, track, track).
+			WithMaxConcurrency(2)
+		start := time.Now()
+		if _, err := scaffold.Process(context.Background(), TestData{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+			t.Errorf("expected Process to return immediately, took %v", elapsed)
+		}
+
+		wg.Wait()
+		if peak.Load() > 2 {
+			t.Errorf("expected at most 2 running at once, got %d", peak.Load())
+		}
+	})
 }
 
 func TestScaffoldClose(t *testing.T) {
@@ -1,6 +1,10 @@
 package pipz
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+)
 
 // FlowVariant is a discriminator for the Flow interface implementation type.
 // Used for runtime type identification when type assertions are needed.
@@ -25,6 +29,39 @@ const (
 	FlowVariantCircuitBreaker FlowVariant = "circuitbreaker"
 	FlowVariantWorkerpool     FlowVariant = "workerpool"
 	FlowVariantPipeline       FlowVariant = "pipeline"
+	FlowVariantDenyByDefault  FlowVariant = "denybydefault"
+	FlowVariantAuditSink      FlowVariant = "auditsink"
+	FlowVariantConsent        FlowVariant = "consent"
+	FlowVariantTenantRouter   FlowVariant = "tenantrouter"
+	FlowVariantFlagged        FlowVariant = "flagged"
+	FlowVariantCompose        FlowVariant = "compose"
+	FlowVariantGroup          FlowVariant = "group"
+	FlowVariantReady          FlowVariant = "ready"
+	FlowVariantVerify         FlowVariant = "verify"
+	FlowVariantCollectErrors  FlowVariant = "collecterrors"
+	FlowVariantEscalate       FlowVariant = "escalate"
+	FlowVariantLeaderOnly     FlowVariant = "leaderonly"
+	FlowVariantDeadline       FlowVariant = "deadline"
+	FlowVariantTombstone      FlowVariant = "tombstone"
+	FlowVariantIdempotency    FlowVariant = "idempotency"
+	FlowVariantPacer          FlowVariant = "pacer"
+	FlowVariantWindow         FlowVariant = "window"
+	FlowVariantBudgetTimeout  FlowVariant = "budgettimeout"
+	FlowVariantPartition      FlowVariant = "partition"
+	FlowVariantQuarantine     FlowVariant = "quarantine"
+	FlowVariantDataQuality    FlowVariant = "dataquality"
+	FlowVariantSLA            FlowVariant = "sla"
+	FlowVariantHistory        FlowVariant = "history"
+	FlowVariantShed           FlowVariant = "shed"
+	FlowVariantHedge          FlowVariant = "hedge"
+	FlowVariantPoll           FlowVariant = "poll"
+	FlowVariantDAG            FlowVariant = "dag"
+	FlowVariantPaginate       FlowVariant = "paginate"
+	FlowVariantCoordinator    FlowVariant = "coordinator"
+	FlowVariantAdaptive       FlowVariant = "adaptiveconcurrency"
+	FlowVariantContentHash    FlowVariant = "contenthash"
+	FlowVariantRegion         FlowVariant = "regionfailover"
+	FlowVariantStagger        FlowVariant = "stagger"
 
 	// Processors (leaf nodes).
 	FlowVariantApply     FlowVariant = "apply"
@@ -93,6 +130,39 @@ var (
 	CircuitBreakerKey = FlowKey[CircuitBreakerFlow]{variant: FlowVariantCircuitBreaker}
 	WorkerpoolKey     = FlowKey[WorkerpoolFlow]{variant: FlowVariantWorkerpool}
 	PipelineKey       = FlowKey[PipelineFlow]{variant: FlowVariantPipeline}
+	DenyByDefaultKey  = FlowKey[DenyByDefaultFlow]{variant: FlowVariantDenyByDefault}
+	AuditSinkKey      = FlowKey[AuditSinkFlow]{variant: FlowVariantAuditSink}
+	ConsentKey        = FlowKey[ConsentFlow]{variant: FlowVariantConsent}
+	TenantRouterKey   = FlowKey[TenantRouterFlow]{variant: FlowVariantTenantRouter}
+	FlaggedKey        = FlowKey[FlaggedFlow]{variant: FlowVariantFlagged}
+	ComposeKey        = FlowKey[ComposeFlow]{variant: FlowVariantCompose}
+	GroupKey          = FlowKey[GroupFlow]{variant: FlowVariantGroup}
+	ReadyKey          = FlowKey[ReadyFlow]{variant: FlowVariantReady}
+	VerifyKey         = FlowKey[VerifyFlow]{variant: FlowVariantVerify}
+	CollectErrorsKey  = FlowKey[CollectErrorsFlow]{variant: FlowVariantCollectErrors}
+	EscalateKey       = FlowKey[EscalateFlow]{variant: FlowVariantEscalate}
+	LeaderOnlyKey     = FlowKey[LeaderOnlyFlow]{variant: FlowVariantLeaderOnly}
+	DeadlineKey       = FlowKey[DeadlineFlow]{variant: FlowVariantDeadline}
+	TombstoneKey      = FlowKey[TombstoneFlow]{variant: FlowVariantTombstone}
+	IdempotencyKey    = FlowKey[IdempotencyFlow]{variant: FlowVariantIdempotency}
+	PacerKey          = FlowKey[PacerFlow]{variant: FlowVariantPacer}
+	WindowKey         = FlowKey[WindowFlow]{variant: FlowVariantWindow}
+	BudgetTimeoutKey  = FlowKey[BudgetTimeoutFlow]{variant: FlowVariantBudgetTimeout}
+	PartitionKey      = FlowKey[PartitionFlow]{variant: FlowVariantPartition}
+	QuarantineKey     = FlowKey[QuarantineFlow]{variant: FlowVariantQuarantine}
+	DataQualityKey    = FlowKey[DataQualityFlow]{variant: FlowVariantDataQuality}
+	SLAKey            = FlowKey[SLAFlow]{variant: FlowVariantSLA}
+	HistoryKey        = FlowKey[HistoryFlow]{variant: FlowVariantHistory}
+	ShedKey           = FlowKey[ShedFlow]{variant: FlowVariantShed}
+	HedgeKey          = FlowKey[HedgeFlow]{variant: FlowVariantHedge}
+	PollKey           = FlowKey[PollFlow]{variant: FlowVariantPoll}
+	DAGKey            = FlowKey[DAGFlow]{variant: FlowVariantDAG}
+	PaginateKey       = FlowKey[PaginateFlow]{variant: FlowVariantPaginate}
+	CoordinatorKey    = FlowKey[CoordinatorFlow]{variant: FlowVariantCoordinator}
+	AdaptiveKey       = FlowKey[AdaptiveConcurrencyFlow]{variant: FlowVariantAdaptive}
+	ContentHashKey    = FlowKey[ContentHashFlow]{variant: FlowVariantContentHash}
+	RegionKey         = FlowKey[RegionFailoverFlow]{variant: FlowVariantRegion}
+	StaggerKey        = FlowKey[StaggerFlow]{variant: FlowVariantStagger}
 )
 
 // -----------------------------------------------------------------------------
@@ -147,9 +217,11 @@ type ConcurrentFlow struct {
 func (ConcurrentFlow) Variant() FlowVariant { return FlowVariantConcurrent }
 
 // SwitchFlow represents conditional routing to different processors.
-// The condition determines which route key to use.
+// The condition determines which route key to use. Routing describes the
+// condition when the switch was built from a Router.
 type SwitchFlow struct {
-	Routes map[string]Node `json:"routes"`
+	Routing *RouteSpec      `json:"routing,omitempty"`
+	Routes  map[string]Node `json:"routes"`
 }
 
 // Variant implements Flow.
@@ -240,6 +312,299 @@ type PipelineFlow struct {
 // Variant implements Flow.
 func (PipelineFlow) Variant() FlowVariant { return FlowVariantPipeline }
 
+// DenyByDefaultFlow represents a processor guarded by authorization policies.
+type DenyByDefaultFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (DenyByDefaultFlow) Variant() FlowVariant { return FlowVariantDenyByDefault }
+
+// AuditSinkFlow represents a processor whose executions are recorded to an audit chain.
+type AuditSinkFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (AuditSinkFlow) Variant() FlowVariant { return FlowVariantAuditSink }
+
+// ConsentFlow represents a processor gated on data-subject consent.
+type ConsentFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (ConsentFlow) Variant() FlowVariant { return FlowVariantConsent }
+
+// TenantRouterFlow represents per-tenant pipeline variants keyed by tenant ID.
+type TenantRouterFlow struct {
+	Tenants map[string]Node `json:"tenants"`
+}
+
+// Variant implements Flow.
+func (TenantRouterFlow) Variant() FlowVariant { return FlowVariantTenantRouter }
+
+// FlaggedFlow represents a choice between two processors controlled by a feature flag.
+type FlaggedFlow struct {
+	Enabled  Node `json:"enabled"`
+	Disabled Node `json:"disabled"`
+}
+
+// Variant implements Flow.
+func (FlaggedFlow) Variant() FlowVariant { return FlowVariantFlagged }
+
+// ComposeFlow represents two typed stages run in order.
+type ComposeFlow struct {
+	First  Node `json:"first"`
+	Second Node `json:"second"`
+}
+
+// Variant implements Flow.
+func (ComposeFlow) Variant() FlowVariant { return FlowVariantCompose }
+
+// GroupFlow represents processors that must all succeed, canceling on first failure.
+type GroupFlow struct {
+	Tasks []Node `json:"tasks"`
+}
+
+// Variant implements Flow.
+func (GroupFlow) Variant() FlowVariant { return FlowVariantGroup }
+
+// ReadyFlow represents a processor gated behind warm-up.
+type ReadyFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (ReadyFlow) Variant() FlowVariant { return FlowVariantReady }
+
+// VerifyFlow represents a quality-checked processor with an optional fallback.
+type VerifyFlow struct {
+	Fallback  *Node `json:"fallback,omitempty"`
+	Processor Node  `json:"processor"`
+}
+
+// Variant implements Flow.
+func (VerifyFlow) Variant() FlowVariant { return FlowVariantVerify }
+
+// CollectErrorsFlow represents ordered steps that all run despite failures.
+type CollectErrorsFlow struct {
+	Steps []Node `json:"steps"`
+}
+
+// Variant implements Flow.
+func (CollectErrorsFlow) Variant() FlowVariant { return FlowVariantCollectErrors }
+
+// EscalateFlow represents an error handler invoked only past a threshold.
+type EscalateFlow struct {
+	Handler Node `json:"handler"`
+}
+
+// Variant implements Flow.
+func (EscalateFlow) Variant() FlowVariant { return FlowVariantEscalate }
+
+// LeaderOnlyFlow represents a processor run only on the elected leader.
+type LeaderOnlyFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (LeaderOnlyFlow) Variant() FlowVariant { return FlowVariantLeaderOnly }
+
+// DeadlineFlow represents a processor bounded by a deadline read from the data.
+type DeadlineFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (DeadlineFlow) Variant() FlowVariant { return FlowVariantDeadline }
+
+// TombstoneFlow represents a change stream split into live records and
+// deletions.
+type TombstoneFlow struct {
+	Live     Node `json:"live"`
+	Deletion Node `json:"deletion"`
+}
+
+// Variant implements Flow.
+func (TombstoneFlow) Variant() FlowVariant { return FlowVariantTombstone }
+
+// IdempotencyFlow represents a processor run under a stable idempotency key.
+type IdempotencyFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (IdempotencyFlow) Variant() FlowVariant { return FlowVariantIdempotency }
+
+// PacerFlow represents a processor whose executions are evenly spaced.
+type PacerFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (PacerFlow) Variant() FlowVariant { return FlowVariantPacer }
+
+// WindowFlow represents a processor restricted to time windows, with an
+// optional alternate for items arriving outside them.
+type WindowFlow struct {
+	Processor Node  `json:"processor"`
+	Alternate *Node `json:"alternate,omitempty"`
+}
+
+// Variant implements Flow.
+func (WindowFlow) Variant() FlowVariant { return FlowVariantWindow }
+
+// BudgetTimeoutFlow represents a processor bounded by its share of a batch
+// budget.
+type BudgetTimeoutFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (BudgetTimeoutFlow) Variant() FlowVariant { return FlowVariantBudgetTimeout }
+
+// PartitionFlow represents traffic sharded into isolated cells, in cell
+// order.
+type PartitionFlow struct {
+	Cells []Node `json:"cells"`
+}
+
+// Variant implements Flow.
+func (PartitionFlow) Variant() FlowVariant { return FlowVariantPartition }
+
+// QuarantineFlow represents a processor whose repeatedly failing items are
+// routed to a quarantine sink.
+type QuarantineFlow struct {
+	Processor Node `json:"processor"`
+	Sink      Node `json:"sink"`
+}
+
+// Variant implements Flow.
+func (QuarantineFlow) Variant() FlowVariant { return FlowVariantQuarantine }
+
+// DataQualityFlow represents a quality gate with an optional remediation
+// pipeline for records scoring below its threshold.
+type DataQualityFlow struct {
+	Remediation *Node `json:"remediation,omitempty"`
+}
+
+// Variant implements Flow.
+func (DataQualityFlow) Variant() FlowVariant { return FlowVariantDataQuality }
+
+// SLAFlow represents a processor whose elapsed time is watched against
+// alert thresholds.
+type SLAFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (SLAFlow) Variant() FlowVariant { return FlowVariantSLA }
+
+// HistoryFlow represents a processor whose recent executions are recorded.
+type HistoryFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (HistoryFlow) Variant() FlowVariant { return FlowVariantHistory }
+
+// ShedFlow represents a processor protected by priority-aware load shedding.
+type ShedFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (ShedFlow) Variant() FlowVariant { return FlowVariantShed }
+
+// HedgeFlow represents a processor given duplicate attempts when slow.
+type HedgeFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (HedgeFlow) Variant() FlowVariant { return FlowVariantHedge }
+
+// PollFlow represents a status check repeated until a condition holds.
+type PollFlow struct {
+	Check Node `json:"check"`
+}
+
+// Variant implements Flow.
+func (PollFlow) Variant() FlowVariant { return FlowVariantPoll }
+
+// DAGFlow represents processors run in dependency order, concurrently
+// where the dependencies allow. DependsOn maps a node's identity ID to the
+// IDs of its prerequisites.
+type DAGFlow struct {
+	DependsOn map[string][]string `json:"depends_on,omitempty"`
+	Nodes     []Node              `json:"nodes"`
+}
+
+// Variant implements Flow.
+func (DAGFlow) Variant() FlowVariant { return FlowVariantDAG }
+
+// PaginateFlow represents pages fetched in turn, each item of which is fed
+// to a per-item pipeline.
+type PaginateFlow struct {
+	Fetch   Node `json:"fetch"`
+	Process Node `json:"process"`
+}
+
+// Variant implements Flow.
+func (PaginateFlow) Variant() FlowVariant { return FlowVariantPaginate }
+
+// CoordinatorFlow represents participants prepared together, then all
+// committed or the prepared ones aborted.
+type CoordinatorFlow struct {
+	Participants []ParticipantFlow `json:"participants"`
+}
+
+// ParticipantFlow holds the phase pipelines of one coordinator
+// participant. Abort is nil when the participant has none.
+type ParticipantFlow struct {
+	Abort   *Node `json:"abort,omitempty"`
+	Prepare Node  `json:"prepare"`
+	Commit  Node  `json:"commit"`
+}
+
+// Variant implements Flow.
+func (CoordinatorFlow) Variant() FlowVariant { return FlowVariantCoordinator }
+
+// AdaptiveConcurrencyFlow represents a processor behind a concurrency
+// limit tuned from latency.
+type AdaptiveConcurrencyFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (AdaptiveConcurrencyFlow) Variant() FlowVariant { return FlowVariantAdaptive }
+
+// ContentHashFlow represents a processor skipped for unchanged content.
+type ContentHashFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (ContentHashFlow) Variant() FlowVariant { return FlowVariantContentHash }
+
+// RegionFailoverFlow represents health-probed regional processors, in
+// order of preference.
+type RegionFailoverFlow struct {
+	Regions []Node `json:"regions"`
+}
+
+// Variant implements Flow.
+func (RegionFailoverFlow) Variant() FlowVariant { return FlowVariantRegion }
+
+// StaggerFlow represents a processor delayed by a per-instance offset.
+type StaggerFlow struct {
+	Processor Node `json:"processor"`
+}
+
+// Variant implements Flow.
+func (StaggerFlow) Variant() FlowVariant { return FlowVariantStagger }
+
 // -----------------------------------------------------------------------------
 // Node
 // -----------------------------------------------------------------------------
@@ -282,18 +647,23 @@ type nodeJSON struct {
 	ID          string         `json:"id"`
 	Name        string         `json:"name"`
 	Description string         `json:"description,omitempty"`
+	Version     string         `json:"version,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
 	Type        string         `json:"type"`
 	Flow        Flow           `json:"flow,omitempty"`
 	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler.
-// It flattens the Identity into separate id, name, and description fields.
+// It flattens the Identity into separate id, name, description, version,
+// and tags fields.
 func (n Node) MarshalJSON() ([]byte, error) {
 	return json.Marshal(nodeJSON{
 		ID:          n.Identity.ID().String(),
 		Name:        n.Identity.Name(),
 		Description: n.Identity.Description(),
+		Version:     n.Identity.Version(),
+		Tags:        n.Identity.Tags(),
 		Type:        n.Type,
 		Flow:        n.Flow,
 		Metadata:    n.Metadata,
@@ -310,7 +680,7 @@ func (n *Node) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	n.Identity = NewIdentity(j.Name, j.Description)
+	n.Identity = NewIdentity(j.Name, j.Description).WithVersion(j.Version).WithTags(j.Tags...)
 	n.Type = j.Type
 	n.Metadata = j.Metadata
 	return nil
@@ -340,61 +710,144 @@ func (s Schema) Walk(fn func(Node)) {
 func walkNode(node Node, fn func(Node)) {
 	fn(node)
 
-	// Walk based on Flow type for semantic traversal
-	if node.Flow != nil {
-		switch f := node.Flow.(type) {
-		case SequenceFlow:
-			for _, step := range f.Steps {
-				walkNode(step, fn)
-			}
-		case FallbackFlow:
-			walkNode(f.Primary, fn)
-			for _, backup := range f.Backups {
-				walkNode(backup, fn)
-			}
-		case RaceFlow:
-			for _, comp := range f.Competitors {
-				walkNode(comp, fn)
-			}
-		case ContestFlow:
-			for _, comp := range f.Competitors {
-				walkNode(comp, fn)
-			}
-		case ConcurrentFlow:
-			for _, task := range f.Tasks {
-				walkNode(task, fn)
-			}
-		case SwitchFlow:
-			for _, route := range f.Routes {
-				walkNode(route, fn)
-			}
-		case FilterFlow:
-			walkNode(f.Processor, fn)
-		case HandleFlow:
-			walkNode(f.Processor, fn)
-			walkNode(f.ErrorHandler, fn)
-		case ScaffoldFlow:
-			for _, proc := range f.Processors {
-				walkNode(proc, fn)
-			}
-		case BackoffFlow:
-			walkNode(f.Processor, fn)
-		case RetryFlow:
-			walkNode(f.Processor, fn)
-		case TimeoutFlow:
-			walkNode(f.Processor, fn)
-		case RateLimiterFlow:
-			walkNode(f.Processor, fn)
-		case CircuitBreakerFlow:
-			walkNode(f.Processor, fn)
-		case WorkerpoolFlow:
-			for _, proc := range f.Processors {
-				walkNode(proc, fn)
+	for _, child := range nodeChildren(node) {
+		walkNode(child, fn)
+	}
+}
+
+// nodeChildren returns the direct children of a node in semantic order.
+// Keyed children (such as Switch routes) are ordered by key for determinism.
+func nodeChildren(node Node) []Node {
+	switch f := node.Flow.(type) {
+	case SequenceFlow:
+		return f.Steps
+	case FallbackFlow:
+		return append([]Node{f.Primary}, f.Backups...)
+	case RaceFlow:
+		return f.Competitors
+	case ContestFlow:
+		return f.Competitors
+	case ConcurrentFlow:
+		return f.Tasks
+	case SwitchFlow:
+		return sortedNodes(f.Routes)
+	case FilterFlow:
+		return []Node{f.Processor}
+	case HandleFlow:
+		return []Node{f.Processor, f.ErrorHandler}
+	case ScaffoldFlow:
+		return f.Processors
+	case BackoffFlow:
+		return []Node{f.Processor}
+	case RetryFlow:
+		return []Node{f.Processor}
+	case TimeoutFlow:
+		return []Node{f.Processor}
+	case RateLimiterFlow:
+		return []Node{f.Processor}
+	case CircuitBreakerFlow:
+		return []Node{f.Processor}
+	case WorkerpoolFlow:
+		return f.Processors
+	case PipelineFlow:
+		return []Node{f.Root}
+	case DenyByDefaultFlow:
+		return []Node{f.Processor}
+	case AuditSinkFlow:
+		return []Node{f.Processor}
+	case ConsentFlow:
+		return []Node{f.Processor}
+	case TenantRouterFlow:
+		return sortedNodes(f.Tenants)
+	case FlaggedFlow:
+		return []Node{f.Enabled, f.Disabled}
+	case ComposeFlow:
+		return []Node{f.First, f.Second}
+	case GroupFlow:
+		return f.Tasks
+	case ReadyFlow:
+		return []Node{f.Processor}
+	case VerifyFlow:
+		if f.Fallback != nil {
+			return []Node{f.Processor, *f.Fallback}
+		}
+		return []Node{f.Processor}
+	case CollectErrorsFlow:
+		return f.Steps
+	case EscalateFlow:
+		return []Node{f.Handler}
+	case LeaderOnlyFlow:
+		return []Node{f.Processor}
+	case DeadlineFlow:
+		return []Node{f.Processor}
+	case TombstoneFlow:
+		return []Node{f.Live, f.Deletion}
+	case IdempotencyFlow:
+		return []Node{f.Processor}
+	case PacerFlow:
+		return []Node{f.Processor}
+	case WindowFlow:
+		if f.Alternate != nil {
+			return []Node{f.Processor, *f.Alternate}
+		}
+		return []Node{f.Processor}
+	case BudgetTimeoutFlow:
+		return []Node{f.Processor}
+	case PartitionFlow:
+		return f.Cells
+	case QuarantineFlow:
+		return []Node{f.Processor, f.Sink}
+	case DataQualityFlow:
+		if f.Remediation != nil {
+			return []Node{*f.Remediation}
+		}
+	case SLAFlow:
+		return []Node{f.Processor}
+	case HistoryFlow:
+		return []Node{f.Processor}
+	case ShedFlow:
+		return []Node{f.Processor}
+	case HedgeFlow:
+		return []Node{f.Processor}
+	case PollFlow:
+		return []Node{f.Check}
+	case DAGFlow:
+		return f.Nodes
+	case PaginateFlow:
+		return []Node{f.Fetch, f.Process}
+	case AdaptiveConcurrencyFlow:
+		return []Node{f.Processor}
+	case ContentHashFlow:
+		return []Node{f.Processor}
+	case RegionFailoverFlow:
+		return f.Regions
+	case StaggerFlow:
+		return []Node{f.Processor}
+	case CoordinatorFlow:
+		var nodes []Node
+		for _, p := range f.Participants {
+			nodes = append(nodes, p.Prepare, p.Commit)
+			if p.Abort != nil {
+				nodes = append(nodes, *p.Abort)
 			}
-		case PipelineFlow:
-			walkNode(f.Root, fn)
 		}
+		return nodes
+	}
+	return nil
+}
+
+// sortedNodes returns the values of a keyed child map ordered by key.
+func sortedNodes(m map[string]Node) []Node {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
+	nodes := make([]Node, len(keys))
+	for i, k := range keys {
+		nodes[i] = m[k]
+	}
+	return nodes
 }
 
 // Find returns the first node matching the predicate, or nil if not found.
@@ -434,3 +887,85 @@ func (s Schema) Count() int {
 	})
 	return count
 }
+
+// SchemaNode is a node located within a schema tree.
+//
+// Path is a stable, human-readable address built from node names, such as
+// "checkout/payment/stripe". Siblings sharing a name are disambiguated in
+// order as "name", "name#2", "name#3". Paths depend only on names and
+// structure, not identity UUIDs, so they match across independently built
+// pipelines and process restarts, which makes them suitable keys for
+// metrics, debug endpoints, and diffs.
+type SchemaNode struct {
+	Parent     *Node
+	Path       string
+	parentPath string
+	Node       Node
+	Depth      int
+}
+
+// ParentPath returns the path of the node's parent, or "" for the root.
+func (n SchemaNode) ParentPath() string {
+	return n.parentPath
+}
+
+// Nodes returns every node with its path, parent, and depth, in the same
+// depth-first, pre-order sequence as Walk. The root has depth 0.
+func (s Schema) Nodes() []SchemaNode {
+	var nodes []SchemaNode
+	s.WalkPaths(func(n SchemaNode) {
+		nodes = append(nodes, n)
+	})
+	return nodes
+}
+
+// WalkPaths traverses the schema like Walk, also passing each node's path,
+// parent, and depth.
+func (s Schema) WalkPaths(fn func(SchemaNode)) {
+	walkPaths(s.Root, nil, "", s.Root.Identity.Name(), 0, fn)
+}
+
+func walkPaths(node Node, parent *Node, parentPath, path string, depth int, fn func(SchemaNode)) {
+	fn(SchemaNode{Node: node, Parent: parent, Path: path, parentPath: parentPath, Depth: depth})
+
+	children := nodeChildren(node)
+	for i, childPath := range childPaths(path, children) {
+		walkPaths(children[i], &node, path, childPath, depth+1, fn)
+	}
+}
+
+// childPaths returns the paths of children under path, disambiguating
+// siblings that share a name.
+func childPaths(path string, children []Node) []string {
+	paths := make([]string, len(children))
+	seen := make(map[string]int)
+	for i, child := range children {
+		name := child.Identity.Name()
+		seen[name]++
+		if seen[name] > 1 {
+			name = name + "#" + strconv.Itoa(seen[name])
+		}
+		paths[i] = path + "/" + name
+	}
+	return paths
+}
+
+// FindPath returns the node at path, as produced by Nodes.
+//
+// Example:
+//
+//	if n, ok := pipz.NewSchema(pipeline.Schema()).FindPath("checkout/payment/stripe"); ok {
+//	    fmt.Println(n.Node.Type, n.Depth)
+//	}
+func (s Schema) FindPath(path string) (SchemaNode, bool) {
+	var (
+		found SchemaNode
+		ok    bool
+	)
+	s.WalkPaths(func(n SchemaNode) {
+		if !ok && n.Path == path {
+			found, ok = n, true
+		}
+	})
+	return found, ok
+}
@@ -833,6 +833,90 @@ func TestSchema_Count(t *testing.T) {
 	}
 }
 
+func TestSchema_Nodes(t *testing.T) {
+	schema := NewSchema(Node{
+		Identity: NewIdentity("root", ""),
+		Flow: SequenceFlow{
+			Steps: []Node{
+				{Identity: NewIdentity("step", "")},
+				{
+					Identity: NewIdentity("step", ""),
+					Flow: FallbackFlow{
+						Primary: Node{Identity: NewIdentity("primary", "")},
+						Backups: []Node{{Identity: NewIdentity("backup", "")}},
+					},
+				},
+			},
+		},
+	})
+
+	nodes := schema.Nodes()
+	want := []struct {
+		path   string
+		parent string
+		depth  int
+	}{
+		{"root", "", 0},
+		{"root/step", "root", 1},
+		{"root/step#2", "root", 1},
+		{"root/step#2/primary", "root/step#2", 2},
+		{"root/step#2/backup", "root/step#2", 2},
+	}
+	if len(nodes) != len(want) {
+		t.Fatalf("Nodes() returned %d nodes, want %d", len(nodes), len(want))
+	}
+	for i, w := range want {
+		n := nodes[i]
+		if n.Path != w.path || n.ParentPath() != w.parent || n.Depth != w.depth {
+			t.Errorf("node %d = (%q, %q, %d), want (%q, %q, %d)", i, n.Path, n.ParentPath(), n.Depth, w.path, w.parent, w.depth)
+		}
+	}
+	if nodes[0].Parent != nil {
+		t.Error("root should have no parent")
+	}
+	if nodes[3].Parent == nil || nodes[3].Parent.Type != "" || nodes[3].Parent.Identity.Name() != "step" {
+		t.Errorf("unexpected parent for primary: %+v", nodes[3].Parent)
+	}
+}
+
+func TestSchema_FindPath(t *testing.T) {
+	pipeline := NewSequence(NewIdentity("checkout", ""),
+		Transform(NewIdentity("validate", ""), func(_ context.Context, v int) int { return v }),
+		NewFallback(NewIdentity("payment", ""),
+			Transform(NewIdentity("stripe", ""), func(_ context.Context, v int) int { return v }),
+			Transform(NewIdentity("paypal", ""), func(_ context.Context, v int) int { return v }),
+		),
+	)
+	schema := NewSchema(pipeline.Schema())
+
+	t.Run("Found", func(t *testing.T) {
+		n, ok := schema.FindPath("checkout/payment/paypal")
+		if !ok {
+			t.Fatal("expected node at path")
+		}
+		if n.Node.Identity.Name() != "paypal" || n.Depth != 2 || n.Parent.Type != "fallback" {
+			t.Errorf("unexpected node: %+v", n)
+		}
+	})
+
+	t.Run("Stable Across Builds", func(t *testing.T) {
+		rebuilt := NewSequence(NewIdentity("checkout", ""),
+			Transform(NewIdentity("validate", ""), func(_ context.Context, v int) int { return v }),
+		)
+		a, _ := schema.FindPath("checkout/validate")
+		b, ok := NewSchema(rebuilt.Schema()).FindPath("checkout/validate")
+		if !ok || a.Path != b.Path || a.Node.Identity.ID() == b.Node.Identity.ID() {
+			t.Error("expected the same path for independently built nodes")
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		if _, ok := schema.FindPath("checkout/shipping"); ok {
+			t.Error("expected missing path not found")
+		}
+	})
+}
+
 // -----------------------------------------------------------------------------
 // Integration Tests
 // -----------------------------------------------------------------------------
@@ -0,0 +1,44 @@
+package pipz
+
+import "context"
+
+// Script wraps a Chainable with context-free helpers for command-line
+// tools, ETL scripts, and examples, where there is no request context to
+// pass and an error can only end the run. It embeds the wrapped Chainable,
+// so a Script can still be composed and closed like any other processor.
+//
+// Servers should call Process with the request's context instead, so
+// cancellation and deadlines reach every stage.
+//
+// Example:
+//
+//	clean := pipz.AsScript(cleanupPipeline)
+//	defer clean.Close()
+//	for _, record := range records {
+//	    out = append(out, clean.MustProcess(record))
+//	}
+type Script[T any] struct {
+	Chainable[T]
+}
+
+// AsScript wraps processor in a Script.
+func AsScript[T any](processor Chainable[T]) Script[T] {
+	return Script[T]{Chainable: processor}
+}
+
+// ProcessBackground processes data with context.Background.
+func (s Script[T]) ProcessBackground(data T) (T, error) {
+	return s.Process(context.Background(), data)
+}
+
+// MustProcess processes data with context.Background and panics with the
+// error if processing fails, for scripts where any failure should stop the
+// run. The panic value is the error returned by Process, so a recover can
+// inspect it with errors.As.
+func (s Script[T]) MustProcess(data T) T {
+	result, err := s.ProcessBackground(data)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestScript(t *testing.T) {
+	parse := Apply(testIdentity("parse"), func(_ context.Context, n int) (int, error) {
+		if n < 0 {
+			return n, errors.New("negative")
+		}
+		return n * 2, nil
+	})
+	script := AsScript(parse)
+
+	t.Run("Process Background", func(t *testing.T) {
+		if out, err := script.ProcessBackground(2); err != nil || out != 4 {
+			t.Errorf("expected 4, got %d, %v", out, err)
+		}
+		if _, err := script.ProcessBackground(-1); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("Must Process", func(t *testing.T) {
+		if out := script.MustProcess(3); out != 6 {
+			t.Errorf("expected 6, got %d", out)
+		}
+	})
+
+	t.Run("Must Process Panics With Error", func(t *testing.T) {
+		defer func() {
+			err, ok := recover().(error)
+			var pipeErr *Error[int]
+			if !ok || !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "parse" {
+				t.Errorf("expected pipeline error panic, got %v", err)
+			}
+		}()
+		script.MustProcess(-1)
+		t.Error("expected panic")
+	})
+
+	t.Run("Composes As Chainable", func(t *testing.T) {
+		seq := NewSequence[int](testIdentity("seq"), script)
+		if out, err := seq.Process(context.Background(), 1); err != nil || out != 2 {
+			t.Errorf("expected 2, got %d, %v", out, err)
+		}
+		if script.Identity().Name() != "parse" || script.Close() != nil {
+			t.Error("expected identity and Close of the wrapped processor")
+		}
+	})
+}
@@ -0,0 +1,214 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSelfCheckFailed is wrapped by the error of a SelfCheckReport in which
+// any pipeline failed its check.
+var ErrSelfCheckFailed = errors.New("pipeline self-check failed")
+
+// Validator is implemented by processors that can check their own
+// configuration before processing, such as DAG.
+type Validator interface {
+	Validate() error
+}
+
+// PipelineCheck builds and checks one pipeline for VerifyPipelines. Create
+// it with CheckPipeline.
+type PipelineCheck struct {
+	run  func(ctx context.Context) PipelineCheckResult
+	Name string
+}
+
+// PipelineCheckResult is the outcome of one PipelineCheck.
+type PipelineCheckResult struct {
+	// Name is the name of the checked pipeline.
+	Name string
+	// Errors lists every problem found, empty when the pipeline passed.
+	Errors []error
+	// Effects lists the side effects the smoke inputs would have
+	// triggered, which the dry run stubbed out.
+	Effects []SimulatedEffect
+	// Smoke is the number of smoke inputs run.
+	Smoke int
+	// Duration is how long the check took.
+	Duration time.Duration
+}
+
+// Passed reports whether the pipeline passed its check.
+func (r PipelineCheckResult) Passed() bool {
+	return len(r.Errors) == 0
+}
+
+// SelfCheckReport is the consolidated outcome of VerifyPipelines.
+type SelfCheckReport struct {
+	Results []PipelineCheckResult
+}
+
+// Passed reports whether every pipeline passed its check.
+func (r SelfCheckReport) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns nil if every pipeline passed, or an error wrapping
+// ErrSelfCheckFailed and every problem found, each prefixed with the name
+// of its pipeline.
+func (r SelfCheckReport) Err() error {
+	var errs []error
+	for _, result := range r.Results {
+		for _, err := range result.Errors {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Name, err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrSelfCheckFailed, errors.Join(errs...))
+}
+
+// String returns one PASS or FAIL line per pipeline, with its problems
+// indented beneath it, followed by a summary line.
+func (r SelfCheckReport) String() string {
+	var b strings.Builder
+	failed := 0
+	for _, result := range r.Results {
+		status := "PASS"
+		if !result.Passed() {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Fprintf(&b, "%s %s (%d smoke, %d effects stubbed, %v)\n",
+			status, result.Name, result.Smoke, len(result.Effects), result.Duration.Round(time.Millisecond))
+		for _, err := range result.Errors {
+			fmt.Fprintf(&b, "    %v\n", err)
+		}
+	}
+	fmt.Fprintf(&b, "%d pipelines, %d failed\n", len(r.Results), failed)
+	return b.String()
+}
+
+// CheckPipeline describes how to check the pipeline called name. The check
+// calls build to construct a fresh pipeline, runs its Validate method if it
+// has one, checks its schema for Timeouts that can never be honored (see
+// CheckTimeouts), and runs each smoke input through it with Simulate, so
+// stages selected by sideEffects or wrapped with AsSideEffect are stubbed
+// out. A smoke input fails the check if processing it returns an error.
+// The pipeline is closed once checked.
+//
+// Build the pipeline the same way the service does, so the check covers
+// the configuration that will serve traffic.
+func CheckPipeline[T any](name string, build func() (Chainable[T], error), sideEffects Selector, smoke ...T) PipelineCheck {
+	return PipelineCheck{
+		Name: name,
+		run: func(ctx context.Context) (result PipelineCheckResult) {
+			result.Name = name
+			var processor Chainable[T]
+			err := recoverCall(ctx, func() error {
+				var err error
+				processor, err = build()
+				return err
+			})
+			if err == nil && processor == nil {
+				err = errors.New("build returned a nil pipeline")
+			}
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Errorf("build: %w", err))
+				return result
+			}
+			defer func() {
+				if err := processor.Close(); err != nil {
+					result.Errors = append(result.Errors, fmt.Errorf("close: %w", err))
+				}
+			}()
+
+			if v, ok := processor.(Validator); ok {
+				if err := recoverCall(ctx, v.Validate); err != nil {
+					result.Errors = append(result.Errors, fmt.Errorf("validate: %w", err))
+				}
+			}
+			for _, c := range CheckTimeouts(NewSchema(processor.Schema())) {
+				result.Errors = append(result.Errors, fmt.Errorf("timeout %s allows %v but nests %v", c.Timeout.Name(), c.Duration, c.Nested))
+			}
+			for i, input := range smoke {
+				if ctx.Err() != nil {
+					result.Errors = append(result.Errors, ctx.Err())
+					break
+				}
+				result.Smoke++
+				_, report, err := Simulate(ctx, processor, input, sideEffects)
+				result.Effects = append(result.Effects, report.Effects...)
+				if err != nil {
+					result.Errors = append(result.Errors, fmt.Errorf("smoke input %d: %w", i, err))
+				}
+			}
+			return result
+		},
+	}
+}
+
+// pipelineChecks is the registry consulted by VerifyPipelines.
+var pipelineChecks = struct {
+	checks []PipelineCheck
+	mu     sync.RWMutex
+}{}
+
+// RegisterPipelineCheck adds check to the checks VerifyPipelines runs by
+// default, replacing any check already registered with the same name.
+// Registering beside each pipeline's constructor keeps the self-check in
+// step with the pipelines the service runs.
+func RegisterPipelineCheck(check PipelineCheck) {
+	pipelineChecks.mu.Lock()
+	defer pipelineChecks.mu.Unlock()
+	for i, existing := range pipelineChecks.checks {
+		if existing.Name == check.Name {
+			pipelineChecks.checks[i] = check
+			return
+		}
+	}
+	pipelineChecks.checks = append(pipelineChecks.checks, check)
+}
+
+// VerifyPipelines runs checks in order, or every registered check when
+// none are given, and returns a consolidated report. Call it at start-up,
+// before the service accepts traffic, or from a command-line subcommand so
+// a deploy can be verified before it is rolled out.
+//
+// Example:
+//
+//	pipz.RegisterPipelineCheck(pipz.CheckPipeline("checkout", buildCheckout,
+//	    pipz.SelectTags("side-effect"), sampleOrder))
+//
+//	report := pipz.VerifyPipelines(ctx)
+//	fmt.Print(report)
+//	if err := report.Err(); err != nil {
+//	    log.Fatal(err)
+//	}
+func VerifyPipelines(ctx context.Context, checks ...PipelineCheck) SelfCheckReport {
+	if len(checks) == 0 {
+		pipelineChecks.mu.RLock()
+		checks = append([]PipelineCheck(nil), pipelineChecks.checks...)
+		pipelineChecks.mu.RUnlock()
+	}
+	report := SelfCheckReport{Results: make([]PipelineCheckResult, 0, len(checks))}
+	for _, check := range checks {
+		if check.run == nil {
+			continue
+		}
+		start := time.Now()
+		result := check.run(ctx)
+		result.Duration = time.Since(start)
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
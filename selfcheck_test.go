@@ -0,0 +1,113 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyPipelines(t *testing.T) {
+	ctx := context.Background()
+
+	// buildCheckout returns a checkout pipeline whose charge stage is a side
+	// effect, counting real charges.
+	buildCheckout := func(charged *int) func() (Chainable[int], error) {
+		return func() (Chainable[int], error) {
+			return NewSequence(testIdentity("checkout"),
+				Apply(testIdentity("validate"), func(_ context.Context, n int) (int, error) {
+					if n < 0 {
+						return n, errors.New("negative total")
+					}
+					return n, nil
+				}),
+				AsSideEffect[int](Effect(testIdentity("charge"), func(context.Context, int) error {
+					*charged++
+					return nil
+				})),
+			), nil
+		}
+	}
+
+	t.Run("Passing Pipeline Runs Smoke Inputs Dry", func(t *testing.T) {
+		charged := 0
+		report := VerifyPipelines(ctx, CheckPipeline("checkout", buildCheckout(&charged), nil, 10, 20))
+		if !report.Passed() || report.Err() != nil {
+			t.Fatalf("expected pass, got %v", report.Err())
+		}
+		result := report.Results[0]
+		if result.Smoke != 2 || len(result.Effects) != 2 {
+			t.Errorf("expected 2 smoke inputs and 2 stubbed effects, got %+v", result)
+		}
+		if charged != 0 {
+			t.Errorf("expected side effects to be stubbed, charged %d times", charged)
+		}
+		if s := report.String(); !strings.Contains(s, "PASS checkout") || !strings.Contains(s, "1 pipelines, 0 failed") {
+			t.Errorf("unexpected report:\n%s", s)
+		}
+	})
+
+	t.Run("Reports Every Failure", func(t *testing.T) {
+		charged := 0
+		invalidDAG := func() (Chainable[taggedOrder], error) {
+			return NewDAG[taggedOrder](testIdentity("dag")).
+				Add(Transform(testIdentity("step"), func(_ context.Context, o taggedOrder) taggedOrder { return o }), testIdentity("missing")), nil
+		}
+		slowRetry := func() (Chainable[int], error) {
+			call := Transform(testIdentity("call"), func(_ context.Context, n int) int { return n })
+			attempt := NewTimeout(testIdentity("attempt"), call, 2*time.Second)
+			return NewTimeout(testIdentity("outer"), NewRetry(testIdentity("retry"), attempt, 3), time.Second), nil
+		}
+		report := VerifyPipelines(ctx,
+			CheckPipeline("checkout", buildCheckout(&charged), nil, 10, -1),
+			CheckPipeline("dag", invalidDAG, nil),
+			CheckPipeline("slow", slowRetry, nil),
+			CheckPipeline("broken", func() (Chainable[int], error) { return nil, errors.New("missing config") }, nil),
+			CheckPipeline("panics", func() (Chainable[int], error) { panic("boom") }, nil),
+		)
+		if report.Passed() {
+			t.Fatal("expected failures")
+		}
+		for i, name := range []string{"checkout", "dag", "slow", "broken", "panics"} {
+			if r := report.Results[i]; r.Name != name || r.Passed() {
+				t.Errorf("expected %s to fail, got %+v", name, r)
+			}
+		}
+		err := report.Err()
+		if !errors.Is(err, ErrSelfCheckFailed) || !errors.Is(err, ErrInvalidDAG) {
+			t.Errorf("expected self-check and DAG errors, got %v", err)
+		}
+		for _, want := range []string{"smoke input 1", "timeout outer", "build: missing config", "panics: build:"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected %q in %v", want, err)
+			}
+		}
+		if !strings.Contains(report.String(), "5 pipelines, 5 failed") {
+			t.Errorf("unexpected report:\n%s", report)
+		}
+	})
+
+	t.Run("Registered Checks", func(t *testing.T) {
+		t.Cleanup(func() { pipelineChecks.checks = nil })
+		charged := 0
+		RegisterPipelineCheck(CheckPipeline("checkout", buildCheckout(&charged), nil, -1))
+		RegisterPipelineCheck(CheckPipeline("checkout", buildCheckout(&charged), nil, 1))
+		RegisterPipelineCheck(CheckPipeline("empty", buildCheckout(&charged), nil))
+
+		report := VerifyPipelines(ctx)
+		if len(report.Results) != 2 || !report.Passed() {
+			t.Errorf("expected the replacement check and one other to pass, got %+v", report)
+		}
+	})
+
+	t.Run("Canceled Context Stops Smoke Inputs", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		charged := 0
+		report := VerifyPipelines(canceled, CheckPipeline("checkout", buildCheckout(&charged), nil, 1, 2))
+		if r := report.Results[0]; r.Smoke != 0 || !errors.Is(report.Err(), context.Canceled) {
+			t.Errorf("expected cancellation before any smoke input, got %+v", r)
+		}
+	})
+}
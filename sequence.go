@@ -16,6 +16,8 @@ var (
 	ErrIndexOutOfBounds = errors.New("index out of bounds")
 	ErrEmptySequence    = errors.New("sequence is empty")
 	ErrInvalidRange     = errors.New("invalid range")
+	ErrStalePlan        = errors.New("sequence changed since plan was created")
+	ErrReentrantDetach  = errors.New("detach from within the sequence's own execution")
 )
 
 // Sequence provides a type-safe sequence for processing values of type T.
@@ -34,11 +36,13 @@ var (
 //
 // Sequence is the primary way to chain processors together.
 type Sequence[T any] struct {
-	identity   Identity
-	processors []Chainable[T]
-	mu         sync.RWMutex
-	closeOnce  sync.Once
-	closeErr   error
+	identity    Identity
+	processors  []Chainable[T]
+	epoch       *sequenceEpoch
+	upgradeOnly bool
+	mu          sync.RWMutex
+	closeOnce   sync.Once
+	closeErr    error
 }
 
 // NewSequence creates a new Sequence with optional initial processors.
@@ -68,9 +72,53 @@ func NewSequence[T any](identity Identity, processors ...Chainable[T]) *Sequence
 	return &Sequence[T]{
 		identity:   identity,
 		processors: slices.Clone(processors),
+		epoch:      newSequenceEpoch(),
 	}
 }
 
+// sequenceEpoch counts the executions started against one version of the
+// processor list, so Detach can wait for those that may still reach a
+// detached processor. It also counts them by correlation, so Detach can
+// refuse to wait for the execution calling it.
+type sequenceEpoch struct {
+	executions map[*correlation]int
+	inflight   sync.WaitGroup
+	mu         sync.Mutex
+}
+
+// newSequenceEpoch returns an epoch with no executions.
+func newSequenceEpoch() *sequenceEpoch {
+	return &sequenceEpoch{executions: make(map[*correlation]int)}
+}
+
+// enter counts in an execution of the epoch.
+func (e *sequenceEpoch) enter(execution *correlation) {
+	e.inflight.Add(1)
+	e.mu.Lock()
+	e.executions[execution]++
+	e.mu.Unlock()
+}
+
+// exit counts out an execution entered with enter.
+func (e *sequenceEpoch) exit(execution *correlation) {
+	e.mu.Lock()
+	if e.executions[execution]--; e.executions[execution] <= 0 {
+		delete(e.executions, execution)
+	}
+	e.mu.Unlock()
+	e.inflight.Done()
+}
+
+// running reports whether execution is in flight in the epoch.
+func (e *sequenceEpoch) running(execution *correlation) bool {
+	if execution == nil {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.executions[execution] > 0
+}
+
 // Register adds processors to this Sequence.
 // Processors are executed in the order they are registered.
 //
@@ -85,6 +133,7 @@ func NewSequence[T any](identity Identity, processors ...Chainable[T]) *Sequence
 //	    sequence.Register(requireApproval)
 //	}
 func (c *Sequence[T]) Register(processors ...Chainable[T]) {
+	noteChildren(c.identity, processors...)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.processors = append(c.processors, processors...)
@@ -113,11 +162,21 @@ func (c *Sequence[T]) Register(processors ...Chainable[T]) {
 func (c *Sequence[T]) Process(ctx context.Context, value T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, c.identity, value)
 
+	ctx, guardErr := enterDepth(ctx, c, c.identity, value)
+	if guardErr != nil {
+		return value, guardErr
+	}
+
 	start := time.Now()
 
 	c.mu.RLock()
 	processors := make([]Chainable[T], len(c.processors))
 	copy(processors, c.processors)
+	if epoch := c.epoch; epoch != nil {
+		execution := correlationFrom(ctx)
+		epoch.enter(execution)
+		defer epoch.exit(execution)
+	}
 	c.mu.RUnlock()
 
 	// Handle nil context
@@ -127,35 +186,61 @@ func (c *Sequence[T]) Process(ctx context.Context, value T) (result T, err error
 
 	result = value
 
-	for _, proc := range processors {
+	for i, proc := range processors {
 		// Check context before starting processor
 		select {
 		case <-ctx.Done():
 			// Context canceled/timed out - create appropriate error
-			return result, &Error[T]{
+			ctxErr := &Error[T]{
 				Err:       ctx.Err(),
-				InputData: value,
+				InputData: errorInput(value),
 				Path:      []Identity{c.identity},
 				Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
 				Canceled:  errors.Is(ctx.Err(), context.Canceled),
 				Timestamp: time.Now(),
 			}
+			ctxErr.setStep(i, result)
+			return result, ctxErr
 		default:
+			lastGood := result
 			result, err = proc.Process(ctx, result)
 			if err != nil {
+				if value, ok := doneValue[T](err); ok {
+					// A step finished the sequence early
+					capitan.Info(ctx, SignalSequenceDone,
+						FieldName.Field(c.identity.Name()),
+						FieldIdentityID.Field(c.identity.ID().String()),
+						FieldProcessorIndex.Field(i),
+						FieldProcessorName.Field(proc.Identity().Name()),
+					)
+					return value, nil
+				}
+				if IsSkip(err) {
+					// The item stops here as it was before the skipping step
+					result = lastGood
+					capitan.Info(ctx, SignalSequenceSkipped,
+						FieldName.Field(c.identity.Name()),
+						FieldIdentityID.Field(c.identity.ID().String()),
+						FieldProcessorIndex.Field(i),
+						FieldProcessorName.Field(proc.Identity().Name()),
+					)
+				}
 				var pipeErr *Error[T]
 				if errors.As(err, &pipeErr) {
 					// Prepend this sequence's identity to the path
-					pipeErr.Path = append([]Identity{c.identity}, pipeErr.Path...)
+					pipeErr.prependPath(ctx, c.identity)
+					pipeErr.setStep(i, lastGood)
 					return result, pipeErr
 				}
 				// Handle non-pipeline errors by wrapping them
-				return result, &Error[T]{
+				wrapped := &Error[T]{
 					Timestamp: time.Now(),
-					InputData: value,
+					InputData: errorInput(value),
 					Err:       err,
 					Path:      []Identity{c.identity},
 				}
+				wrapped.setStep(i, lastGood)
+				return result, wrapped
 			}
 		}
 	}
@@ -187,6 +272,7 @@ func (c *Sequence[T]) Clear() {
 
 // Unshift adds processors to the front of the Sequence (runs first).
 func (c *Sequence[T]) Unshift(processors ...Chainable[T]) {
+	noteChildren(c.identity, processors...)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.processors = slices.Insert(c.processors, 0, processors...)
@@ -194,6 +280,7 @@ func (c *Sequence[T]) Unshift(processors ...Chainable[T]) {
 
 // Push adds processors to the back of the Sequence (runs last).
 func (c *Sequence[T]) Push(processors ...Chainable[T]) {
+	noteChildren(c.identity, processors...)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.processors = append(c.processors, processors...)
@@ -248,7 +335,7 @@ func (c *Sequence[T]) Remove(id Identity) error {
 	defer c.mu.Unlock()
 
 	for i, proc := range c.processors {
-		if proc.Identity() == id {
+		if proc.Identity().ID() == id.ID() {
 			c.processors = slices.Delete(c.processors, i, i+1)
 			return nil
 		}
@@ -258,12 +345,20 @@ func (c *Sequence[T]) Remove(id Identity) error {
 }
 
 // Replace replaces the first processor with the specified identity.
+// With SetUpgradeOnly, replacing a versioned processor with an older or
+// unversioned one fails with an error wrapping ErrVersionDowngrade.
 func (c *Sequence[T]) Replace(id Identity, processor Chainable[T]) error {
+	noteChildren(c.identity, processor)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	for i, proc := range c.processors {
-		if proc.Identity() == id {
+		if proc.Identity().ID() == id.ID() {
+			if c.upgradeOnly {
+				if err := checkUpgrade(proc.Identity(), processor.Identity()); err != nil {
+					return err
+				}
+			}
 			c.processors[i] = processor
 			return nil
 		}
@@ -272,13 +367,106 @@ func (c *Sequence[T]) Replace(id Identity, processor Chainable[T]) error {
 	return fmt.Errorf("processor %q not found", id.Name())
 }
 
+// Detach takes the first processor with the specified identity out of the
+// live Sequence for maintenance, without pausing the rest of it. New
+// executions stop using the processor at once: replacement, if non-nil,
+// takes its place, otherwise it is removed. Detach then waits for the
+// executions already in flight, which may still reach it, to finish,
+// closes it, and returns it.
+//
+// If ctx ends before the in-flight executions finish, Detach returns the
+// detached processor unclosed with the context's error; the processor
+// stays out of the Sequence, and the caller may close it later. A Close
+// failure is returned with the processor. With SetUpgradeOnly, the
+// replacement must be an upgrade, as for Replace. Do not Detach a
+// processor registered elsewhere too: Detach closes it. Detach fails with
+// ErrReentrantDetach when ctx belongs to an execution running through the
+// Sequence, such as one of its processors, since it would wait for that
+// execution.
+//
+// Example:
+//
+//	// Swap the fraud check's model without stopping order processing
+//	old, err := orders.Detach(ctx, FraudCheckID, newFraudCheck)
+//	if err != nil {
+//	    log.Printf("detaching %s: %v", old.Identity().Name(), err)
+//	}
+func (c *Sequence[T]) Detach(ctx context.Context, id Identity, replacement Chainable[T]) (Chainable[T], error) {
+	noteChildren(c.identity, replacement)
+	start := time.Now()
+
+	c.mu.Lock()
+	if c.epoch != nil && c.epoch.running(correlationFrom(ctx)) {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("detaching processor %q: %w", id.Name(), ErrReentrantDetach)
+	}
+	i := slices.IndexFunc(c.processors, func(proc Chainable[T]) bool {
+		return proc.Identity().ID() == id.ID()
+	})
+	if i < 0 {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("processor %q not found", id.Name())
+	}
+	detached := c.processors[i]
+	if replacement != nil {
+		if c.upgradeOnly {
+			if err := checkUpgrade(detached.Identity(), replacement.Identity()); err != nil {
+				c.mu.Unlock()
+				return nil, err
+			}
+		}
+		c.processors[i] = replacement
+	} else {
+		c.processors = slices.Delete(c.processors, i, i+1)
+	}
+	draining := c.epoch
+	c.epoch = newSequenceEpoch()
+	c.mu.Unlock()
+
+	if draining != nil {
+		drained := make(chan struct{})
+		go func() {
+			draining.inflight.Wait()
+			close(drained)
+		}()
+		select {
+		case <-drained:
+		case <-ctx.Done():
+			return detached, fmt.Errorf("draining processor %q: %w", id.Name(), ctx.Err())
+		}
+	}
+
+	if err := detached.Close(); err != nil {
+		return detached, fmt.Errorf("closing processor %q: %w", id.Name(), err)
+	}
+	capitan.Info(ctx, SignalSequenceDetached,
+		FieldName.Field(c.identity.Name()),
+		FieldIdentityID.Field(c.identity.ID().String()),
+		FieldProcessorIndex.Field(i),
+		FieldProcessorName.Field(id.Name()),
+		FieldDuration.Field(time.Since(start).Seconds()),
+	)
+	return detached, nil
+}
+
+// SetUpgradeOnly makes Replace, in place or within Edit, accept only
+// replacements declaring the same or a newer version (see
+// Identity.WithVersion) for controlled runtime upgrades.
+func (c *Sequence[T]) SetUpgradeOnly(enabled bool) *Sequence[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.upgradeOnly = enabled
+	return c
+}
+
 // After inserts processors after the first processor with the specified identity.
 func (c *Sequence[T]) After(afterID Identity, processors ...Chainable[T]) error {
+	noteChildren(c.identity, processors...)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	for i, proc := range c.processors {
-		if proc.Identity() == afterID {
+		if proc.Identity().ID() == afterID.ID() {
 			c.processors = slices.Insert(c.processors, i+1, processors...)
 			return nil
 		}
@@ -289,11 +477,12 @@ func (c *Sequence[T]) After(afterID Identity, processors ...Chainable[T]) error
 
 // Before inserts processors before the first processor with the specified identity.
 func (c *Sequence[T]) Before(beforeID Identity, processors ...Chainable[T]) error {
+	noteChildren(c.identity, processors...)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	for i, proc := range c.processors {
-		if proc.Identity() == beforeID {
+		if proc.Identity().ID() == beforeID.ID() {
 			c.processors = slices.Insert(c.processors, i, processors...)
 			return nil
 		}
@@ -302,6 +491,220 @@ func (c *Sequence[T]) Before(beforeID Identity, processors ...Chainable[T]) erro
 	return fmt.Errorf("processor %q not found", beforeID.Name())
 }
 
+// SequenceTx is a batch of modifications applied atomically by Sequence.Edit.
+// Its methods mirror the Sequence modification methods but operate on a
+// private copy of the processor list.
+type SequenceTx[T any] struct {
+	processors  []Chainable[T]
+	upgradeOnly bool
+}
+
+// Edit applies a batch of modifications atomically. The function receives a
+// transaction holding a copy of the current processors; if it returns nil
+// the modified list replaces the sequence's processors in a single swap, and
+// if it returns an error nothing changes. Concurrent Process calls observe
+// either the old or the new processors, never an intermediate state.
+//
+// Edit holds the sequence's write lock while fn runs, so fn must not call
+// methods on the same Sequence.
+//
+// Example:
+//
+//	err := sequence.Edit(func(tx *pipz.SequenceTx[Order]) error {
+//	    if err := tx.Remove(LegacyTaxID); err != nil {
+//	        return err
+//	    }
+//	    if err := tx.After(ValidateID, fraudCheck); err != nil {
+//	        return err
+//	    }
+//	    return tx.Replace(NotifyID, newNotifier)
+//	})
+func (c *Sequence[T]) Edit(fn func(tx *SequenceTx[T]) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx := &SequenceTx[T]{processors: slices.Clone(c.processors), upgradeOnly: c.upgradeOnly}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	noteEdited(c.processors, tx.processors)
+	c.processors = tx.processors
+	return nil
+}
+
+// noteEdited stands in for noteChildren in Edit, whose lock rules out
+// walking the schemas of the processors it adds: adding any connector not
+// already in the sequence marks recursion as possible.
+func noteEdited[T any](before, after []Chainable[T]) {
+	if recursionPossible.Load() {
+		return
+	}
+	for _, proc := range after {
+		if _, ok := proc.(Processor[T]); ok {
+			continue
+		}
+		id := proc.Identity().ID()
+		if !slices.ContainsFunc(before, func(p Chainable[T]) bool { return p.Identity().ID() == id }) {
+			recursionPossible.Store(true)
+			return
+		}
+	}
+}
+
+// Len returns the number of processors in the transaction.
+func (tx *SequenceTx[T]) Len() int {
+	return len(tx.processors)
+}
+
+// Names returns the names of all processors in the transaction in order.
+func (tx *SequenceTx[T]) Names() []string {
+	names := make([]string, len(tx.processors))
+	for i, proc := range tx.processors {
+		names[i] = proc.Identity().Name()
+	}
+	return names
+}
+
+// Clear removes all processors.
+func (tx *SequenceTx[T]) Clear() {
+	tx.processors = tx.processors[:0]
+}
+
+// Push adds processors to the back.
+func (tx *SequenceTx[T]) Push(processors ...Chainable[T]) {
+	tx.processors = append(tx.processors, processors...)
+}
+
+// Unshift adds processors to the front.
+func (tx *SequenceTx[T]) Unshift(processors ...Chainable[T]) {
+	tx.processors = slices.Insert(tx.processors, 0, processors...)
+}
+
+// Remove removes the first processor with the specified identity.
+func (tx *SequenceTx[T]) Remove(id Identity) error {
+	i := tx.index(id)
+	if i < 0 {
+		return fmt.Errorf("processor %q not found", id.Name())
+	}
+	tx.processors = slices.Delete(tx.processors, i, i+1)
+	return nil
+}
+
+// Replace replaces the first processor with the specified identity.
+func (tx *SequenceTx[T]) Replace(id Identity, processor Chainable[T]) error {
+	i := tx.index(id)
+	if i < 0 {
+		return fmt.Errorf("processor %q not found", id.Name())
+	}
+	if tx.upgradeOnly {
+		if err := checkUpgrade(tx.processors[i].Identity(), processor.Identity()); err != nil {
+			return err
+		}
+	}
+	tx.processors[i] = processor
+	return nil
+}
+
+// After inserts processors after the first processor with the specified identity.
+func (tx *SequenceTx[T]) After(afterID Identity, processors ...Chainable[T]) error {
+	i := tx.index(afterID)
+	if i < 0 {
+		return fmt.Errorf("processor %q not found", afterID.Name())
+	}
+	tx.processors = slices.Insert(tx.processors, i+1, processors...)
+	return nil
+}
+
+// Before inserts processors before the first processor with the specified identity.
+func (tx *SequenceTx[T]) Before(beforeID Identity, processors ...Chainable[T]) error {
+	i := tx.index(beforeID)
+	if i < 0 {
+		return fmt.Errorf("processor %q not found", beforeID.Name())
+	}
+	tx.processors = slices.Insert(tx.processors, i, processors...)
+	return nil
+}
+
+// index returns the position of the first processor with the identity, or -1.
+func (tx *SequenceTx[T]) index(id Identity) int {
+	for i, proc := range tx.processors {
+		if proc.Identity().ID() == id.ID() {
+			return i
+		}
+	}
+	return -1
+}
+
+// SequencePlan is a reviewed migration from a Sequence's current processors
+// to a target list. Create one with Sequence.Plan and apply it with
+// Sequence.ApplyPlan.
+type SequencePlan[T any] struct {
+	// Diff describes the topology change the plan will make.
+	Diff   SchemaDiff
+	base   []Identity
+	target []Chainable[T]
+}
+
+// Plan computes the migration from the current processors to target without
+// modifying the sequence. Inspect the plan's Diff before applying it, for
+// example to log or approve a config hot-reload or blue/green switch.
+//
+// Example:
+//
+//	plan := sequence.Plan(buildFromConfig(newCfg)...)
+//	log.Printf("applying pipeline changes:\n%s", plan.Diff)
+//	if err := sequence.ApplyPlan(plan); errors.Is(err, pipz.ErrStalePlan) {
+//	    // Someone else changed the sequence; re-plan.
+//	}
+func (c *Sequence[T]) Plan(target ...Chainable[T]) *SequencePlan[T] {
+	c.mu.RLock()
+	current := slices.Clone(c.processors)
+	identity := c.identity
+	c.mu.RUnlock()
+
+	base := make([]Identity, len(current))
+	for i, proc := range current {
+		base[i] = proc.Identity()
+	}
+	schema := func(processors []Chainable[T]) Node {
+		steps := make([]Node, len(processors))
+		for i, proc := range processors {
+			steps[i] = proc.Schema()
+		}
+		return Node{Identity: identity, Type: "sequence", Flow: SequenceFlow{Steps: steps}}
+	}
+
+	return &SequencePlan[T]{
+		Diff:   DiffSchemas(schema(current), schema(target)),
+		base:   base,
+		target: slices.Clone(target),
+	}
+}
+
+// ApplyPlan atomically replaces the processors with the plan's target.
+// Concurrent Process calls observe either the old or the new topology,
+// never an intermediate one. Returns ErrStalePlan if the sequence's
+// processors changed since the plan was created.
+//
+// Removed processors are not closed, since they may still be running
+// in-flight requests; close them once they are drained.
+func (c *Sequence[T]) ApplyPlan(plan *SequencePlan[T]) error {
+	noteChildren(c.identity, plan.target...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(plan.base) != len(c.processors) {
+		return ErrStalePlan
+	}
+	for i, proc := range c.processors {
+		if proc.Identity().ID() != plan.base[i].ID() {
+			return ErrStalePlan
+		}
+	}
+	c.processors = slices.Clone(plan.target)
+	return nil
+}
+
 // Identity returns the identity of this sequence.
 func (c *Sequence[T]) Identity() Identity {
 	c.mu.RLock()
@@ -326,6 +729,25 @@ func (c *Sequence[T]) Schema() Node {
 	}
 }
 
+// Start starts every processor implementing Starter, in order, stopping at
+// the first failure. Processors already started are left running until
+// Close.
+func (c *Sequence[T]) Start(ctx context.Context) error {
+	c.mu.RLock()
+	processors := slices.Clone(c.processors)
+	c.mu.RUnlock()
+	return startChildren(ctx, c.identity, processors)
+}
+
+// Health checks every processor implementing HealthChecker and returns
+// their joined errors.
+func (c *Sequence[T]) Health(ctx context.Context) error {
+	c.mu.RLock()
+	processors := slices.Clone(c.processors)
+	c.mu.RUnlock()
+	return checkChildren(ctx, c.identity, processors)
+}
+
 // Close gracefully shuts down the connector and all its child processors.
 // Processors are closed in reverse order (LIFO) to mirror typical resource cleanup patterns.
 // Close is idempotent - multiple calls return the same result.
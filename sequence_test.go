@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -1393,3 +1394,370 @@ func TestSequenceClose(t *testing.T) {
 		}
 	})
 }
+
+func TestSequencePlan(t *testing.T) {
+	step := func(name string, n int) Chainable[int] {
+		return Transform(NewIdentity(name, ""), func(_ context.Context, v int) int { return v + n })
+	}
+
+	t.Run("Apply", func(t *testing.T) {
+		a, b, c := step("a", 1), step("b", 10), step("c", 100)
+		seq := NewSequence(NewIdentity("seq", ""), a, b)
+
+		plan := seq.Plan(a, c)
+		if len(plan.Diff.Changes) != 2 {
+			t.Fatalf("expected 2 changes, got:\n%s", plan.Diff)
+		}
+		if seq.Len() != 2 || seq.Names()[1] != "b" {
+			t.Error("planning must not modify the sequence")
+		}
+
+		if err := seq.ApplyPlan(plan); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result, err := seq.Process(context.Background(), 0)
+		if err != nil || result != 101 {
+			t.Errorf("expected 101, got %d (%v)", result, err)
+		}
+	})
+
+	t.Run("Stale Plan", func(t *testing.T) {
+		a, b := step("a", 1), step("b", 10)
+		seq := NewSequence(NewIdentity("seq", ""), a)
+		plan := seq.Plan(a, b)
+		seq.Push(step("other", 0))
+		if err := seq.ApplyPlan(plan); !errors.Is(err, ErrStalePlan) {
+			t.Errorf("expected ErrStalePlan, got %v", err)
+		}
+	})
+
+	t.Run("Target Is Copied", func(t *testing.T) {
+		a, b := step("a", 1), step("b", 10)
+		seq := NewSequence[int](NewIdentity("seq", ""))
+		target := []Chainable[int]{a, b}
+		plan := seq.Plan(target...)
+		target[1] = step("mutated", 1000)
+		if err := seq.ApplyPlan(plan); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if names := seq.Names(); names[1] != "b" {
+			t.Errorf("expected plan to be unaffected by caller mutation, got %v", names)
+		}
+	})
+}
+
+func TestSequenceEdit(t *testing.T) {
+	step := func(name string, n int) Chainable[int] {
+		return Transform(NewIdentity(name, ""), func(_ context.Context, v int) int { return v + n })
+	}
+
+	t.Run("Commits Batch", func(t *testing.T) {
+		a, b, c, d := step("a", 1), step("b", 10), step("c", 100), step("d", 1000)
+		seq := NewSequence(NewIdentity("seq", ""), a, b)
+
+		err := seq.Edit(func(tx *SequenceTx[int]) error {
+			if err := tx.Remove(a.Identity()); err != nil {
+				return err
+			}
+			if err := tx.After(b.Identity(), c); err != nil {
+				return err
+			}
+			if err := tx.Before(b.Identity(), d); err != nil {
+				return err
+			}
+			if tx.Len() != 3 {
+				t.Errorf("expected 3 processors in tx, got %d", tx.Len())
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		names := seq.Names()
+		if len(names) != 3 || names[0] != "d" || names[1] != "b" || names[2] != "c" {
+			t.Errorf("unexpected names: %v", names)
+		}
+	})
+
+	t.Run("Rolls Back On Error", func(t *testing.T) {
+		a, b := step("a", 1), step("b", 10)
+		seq := NewSequence(NewIdentity("seq", ""), a, b)
+
+		err := seq.Edit(func(tx *SequenceTx[int]) error {
+			tx.Clear()
+			tx.Push(step("x", 0))
+			return tx.Replace(NewIdentity("missing", ""), step("y", 0))
+		})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		names := seq.Names()
+		if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+			t.Errorf("expected sequence unchanged, got %v", names)
+		}
+	})
+
+	t.Run("No Intermediate States", func(t *testing.T) {
+		// Every edit swaps the two processors, so every observed result
+		// must include both increments.
+		a, b := step("a", 1), step("b", 10)
+		seq := NewSequence(NewIdentity("seq", ""), a, b)
+
+		var wg sync.WaitGroup
+		stop := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				_ = seq.Edit(func(tx *SequenceTx[int]) error { //nolint:errcheck // edit cannot fail
+					first := tx.processors[0]
+					if err := tx.Remove(first.Identity()); err != nil {
+						return err
+					}
+					tx.Push(first)
+					return nil
+				})
+			}
+		}()
+
+		for i := 0; i < 1000; i++ {
+			result, err := seq.Process(context.Background(), 0)
+			if err != nil || result != 11 {
+				t.Fatalf("observed intermediate state: %d (%v)", result, err)
+			}
+		}
+		close(stop)
+		wg.Wait()
+	})
+}
+
+func TestSequenceFailureStep(t *testing.T) {
+	add := func(name string, n int) Chainable[int] {
+		return Transform(NewIdentity(name, ""), func(_ context.Context, v int) int { return v + n })
+	}
+	errBoom := errors.New("boom")
+	fail := Apply(NewIdentity("fail", ""), func(_ context.Context, v int) (int, error) {
+		return v, errBoom
+	})
+
+	t.Run("Records Failing Step And Last Good Value", func(t *testing.T) {
+		seq := NewSequence(NewIdentity("seq", ""), add("one", 1), add("ten", 10), fail, add("never", 100))
+		_, err := seq.Process(context.Background(), 0)
+
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.HasStep() {
+			t.Fatalf("expected step information, got %v", err)
+		}
+		if pipeErr.StepIndex != 2 || pipeErr.LastGood != 11 {
+			t.Errorf("expected step 2 with last good 11, got %d and %d", pipeErr.StepIndex, pipeErr.LastGood)
+		}
+	})
+
+	t.Run("First Step Failure Keeps Input", func(t *testing.T) {
+		plain := Chainable[int](&errorChainable{err: errBoom})
+		_, err := NewSequence(NewIdentity("seq", ""), plain).Process(context.Background(), 5)
+
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || pipeErr.StepIndex != 0 || pipeErr.LastGood != 5 || !pipeErr.HasStep() {
+			t.Errorf("expected step 0 with input 5, got %+v", pipeErr)
+		}
+	})
+
+	t.Run("Nested Sequences Report Innermost", func(t *testing.T) {
+		inner := NewSequence(NewIdentity("inner", ""), add("hundred", 100), fail)
+		outer := NewSequence(NewIdentity("outer", ""), add("one", 1), add("two", 2), inner)
+		_, err := outer.Process(context.Background(), 0)
+
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected pipz error, got %v", err)
+		}
+		if pipeErr.StepIndex != 1 || pipeErr.LastGood != 103 {
+			t.Errorf("expected inner step 1 with last good 103, got %d and %d", pipeErr.StepIndex, pipeErr.LastGood)
+		}
+		if len(pipeErr.Path) != 3 || pipeErr.Path[0].Name() != "outer" {
+			t.Errorf("unexpected path: %v", pipeErr.Path)
+		}
+	})
+
+	t.Run("Canceled Between Steps", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelling := Transform(NewIdentity("cancel", ""), func(_ context.Context, v int) int {
+			cancel()
+			return v + 1
+		})
+		_, err := NewSequence(NewIdentity("seq", ""), cancelling, add("next", 1)).Process(ctx, 0)
+
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.IsCanceled() || pipeErr.StepIndex != 1 || pipeErr.LastGood != 1 {
+			t.Errorf("expected cancellation before step 1 with last good 1, got %+v", pipeErr)
+		}
+	})
+
+	t.Run("Errors Outside Sequences Have No Step", func(t *testing.T) {
+		_, err := fail.Process(context.Background(), 0)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || pipeErr.HasStep() {
+			t.Errorf("expected no step information, got %+v", pipeErr)
+		}
+	})
+}
+
+// errorChainable fails with a plain, unwrapped error.
+type errorChainable struct {
+	err error
+}
+
+func (e *errorChainable) Process(_ context.Context, v int) (int, error) { return v, e.err }
+func (*errorChainable) Identity() Identity                              { return NewIdentity("plain", "") }
+func (*errorChainable) Schema() Node                                    { return Node{} }
+func (*errorChainable) Close() error                                    { return nil }
+
+// closeCounter is a processor that counts how often it is closed.
+type closeCounter struct {
+	Processor[int]
+	closes atomic.Int32
+}
+
+func (c *closeCounter) Close() error {
+	c.closes.Add(1)
+	return nil
+}
+
+func TestSequenceDetach(t *testing.T) {
+	stageID := NewIdentity("stage", "")
+	newStage := func(add int) *closeCounter {
+		return &closeCounter{Processor: Transform(stageID, func(_ context.Context, v int) int { return v + add })}
+	}
+
+	t.Run("Drains In-Flight Executions", func(t *testing.T) {
+		entered, release := make(chan struct{}), make(chan struct{})
+		gate := Transform(NewIdentity("gate", ""), func(_ context.Context, v int) int {
+			if v == 1 {
+				close(entered)
+				<-release
+			}
+			return v
+		})
+		old, replacement := newStage(10), newStage(100)
+		seq := NewSequence(NewIdentity("seq", ""), gate, Chainable[int](old))
+
+		inFlight := make(chan int, 1)
+		go func() {
+			out, _ := seq.Process(context.Background(), 1)
+			inFlight <- out
+		}()
+		<-entered
+
+		detached := make(chan error, 1)
+		go func() {
+			_, err := seq.Detach(context.Background(), stageID, replacement)
+			detached <- err
+		}()
+		for {
+			if out, _ := seq.Process(context.Background(), 2); out == 102 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		select {
+		case err := <-detached:
+			t.Fatalf("expected Detach to wait for the in-flight execution, got %v", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+		if old.closes.Load() != 0 {
+			t.Error("expected the stage to stay open while in use")
+		}
+
+		close(release)
+		if out := <-inFlight; out != 11 {
+			t.Errorf("expected the in-flight execution to finish on the old stage, got %d", out)
+		}
+		if err := <-detached; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if old.closes.Load() != 1 || replacement.closes.Load() != 0 {
+			t.Errorf("expected only the detached stage closed, got %d and %d", old.closes.Load(), replacement.closes.Load())
+		}
+	})
+
+	t.Run("Removes Without Replacement", func(t *testing.T) {
+		old := newStage(10)
+		seq := NewSequence(NewIdentity("seq", ""), Chainable[int](old), Transform(NewIdentity("next", ""), func(_ context.Context, v int) int { return v + 1 }))
+		detached, err := seq.Detach(context.Background(), stageID, nil)
+		if err != nil || detached != Chainable[int](old) || old.closes.Load() != 1 {
+			t.Fatalf("expected the stage detached and closed, got %v", err)
+		}
+		if out, _ := seq.Process(context.Background(), 0); out != 1 || seq.Len() != 1 {
+			t.Errorf("expected the stage removed, got %d from %d processors", out, seq.Len())
+		}
+	})
+
+	t.Run("Context Ends While Draining", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		entered := make(chan struct{})
+		slow := Transform(NewIdentity("slow", ""), func(_ context.Context, v int) int {
+			close(entered)
+			<-release
+			return v
+		})
+		old := newStage(10)
+		seq := NewSequence(NewIdentity("seq", ""), slow, Chainable[int](old))
+		go seq.Process(context.Background(), 1) //nolint:errcheck // blocked until the test ends
+		<-entered
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		detached, err := seq.Detach(ctx, stageID, nil)
+		if !errors.Is(err, context.DeadlineExceeded) || detached != Chainable[int](old) {
+			t.Errorf("expected a deadline error with the detached stage, got %v", err)
+		}
+		if old.closes.Load() != 0 || seq.Len() != 1 {
+			t.Errorf("expected the stage detached but not closed, got %d closes", old.closes.Load())
+		}
+	})
+
+	t.Run("From Its Own Execution", func(t *testing.T) {
+		old := newStage(10)
+		var seq *Sequence[int]
+		var detachErr error
+		maintenance := Effect(NewIdentity("maintenance", ""), func(ctx context.Context, _ int) error {
+			_, detachErr = seq.Detach(ctx, stageID, nil)
+			return nil
+		})
+		seq = NewSequence(NewIdentity("seq", ""), maintenance, Chainable[int](old))
+		if out, err := seq.Process(context.Background(), 1); err != nil || out != 11 {
+			t.Fatalf("expected the execution to finish on the stage, got %d, %v", out, err)
+		}
+		if !errors.Is(detachErr, ErrReentrantDetach) {
+			t.Errorf("expected ErrReentrantDetach, got %v", detachErr)
+		}
+		if old.closes.Load() != 0 || seq.Len() != 2 {
+			t.Error("expected the sequence unchanged")
+		}
+	})
+
+	t.Run("Not Found And Downgrade", func(t *testing.T) {
+		passthrough := func(id Identity) Chainable[int] {
+			return Transform(id, func(_ context.Context, v int) int { return v })
+		}
+		current := NewIdentity("score", "").WithVersion("2.1.0")
+		seq := NewSequence(NewIdentity("seq", ""), passthrough(current)).SetUpgradeOnly(true)
+		if _, err := seq.Detach(context.Background(), NewIdentity("missing", ""), nil); err == nil {
+			t.Error("expected an error for a missing processor")
+		}
+		older := passthrough(NewIdentity("score", "").WithVersion("2.0.5"))
+		if _, err := seq.Detach(context.Background(), current, older); !errors.Is(err, ErrVersionDowngrade) {
+			t.Errorf("expected downgrade rejection, got %v", err)
+		}
+		if seq.Schema().Flow.(SequenceFlow).Steps[0].Identity.Version() != "2.1.0" {
+			t.Error("expected the sequence unchanged after a rejected replacement")
+		}
+	})
+}
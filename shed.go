@@ -0,0 +1,189 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrShed is wrapped by errors from a Shed that rejected an item because
+// too much work was already in flight for its priority.
+var ErrShed = errors.New("load shed")
+
+// Shed protects a processor from overload by rejecting items once too many
+// are in flight, lowest priorities first. Each priority class has its own
+// in-flight limit: by default bulk items are shed once half of maxInFlight
+// is reached, normal and high items at maxInFlight, and critical items are
+// never shed. Rejections fail fast with an error wrapping ErrShed, so
+// callers can return 503 or requeue the work instead of piling it up.
+//
+// The priority comes from the context; see WithPriority.
+//
+// CRITICAL: Shed is STATEFUL. Create it once and reuse it.
+//
+// Example:
+//
+//	var SearchShedID = pipz.NewIdentity("search-shed", "Sheds search load above 200 in flight")
+//	shed := pipz.NewShed(SearchShedID, searchPipeline, 200).
+//	    SetLimit(pipz.PriorityBulk, 50) // Crawlers yield early
+type Shed[T any] struct {
+	processor Chainable[T]
+	limits    map[Priority]int
+	identity  Identity
+	inFlight  atomic.Int64
+	shed      atomic.Int64
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewShed creates a Shed admitting up to maxInFlight concurrent items of
+// normal or high priority, half as many bulk items, and any number of
+// critical items.
+func NewShed[T any](identity Identity, processor Chainable[T], maxInFlight int) *Shed[T] {
+	maxInFlight = max(maxInFlight, 1)
+	return &Shed[T]{
+		identity:  identity,
+		processor: processor,
+		limits: map[Priority]int{
+			PriorityBulk:   max(maxInFlight/2, 1),
+			PriorityNormal: maxInFlight,
+			PriorityHigh:   maxInFlight,
+		},
+	}
+}
+
+// Process implements the Chainable interface.
+func (s *Shed[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, s.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, s, s.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	priority := PriorityFromContext(ctx)
+	s.mu.RLock()
+	processor := s.processor
+	limit := s.limitLocked(priority)
+	s.mu.RUnlock()
+
+	inFlight := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+
+	if limit > 0 && inFlight > int64(limit) {
+		s.shed.Add(1)
+		capitan.Warn(ctx, SignalShedRejected,
+			FieldName.Field(s.identity.Name()),
+			FieldIdentityID.Field(s.identity.ID().String()),
+			FieldPriority.Field(priority.String()),
+			FieldInFlight.Field(int(inFlight-1)),
+			FieldLimit.Field(limit),
+		)
+		return data, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       ErrShed,
+			Path:      []Identity{s.identity},
+		}
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, s.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{s.identity},
+		}
+	}
+	return result, nil
+}
+
+// limitLocked returns the in-flight limit for priority, zero meaning
+// unlimited. Priorities outside the defined classes use the nearest class;
+// the caller holds mu.
+func (s *Shed[T]) limitLocked(priority Priority) int {
+	return s.limits[priority.class()]
+}
+
+// SetLimit sets the in-flight limit for a priority class. Items of that
+// class are shed once limit items of any class are in flight. A limit of
+// zero or less means the class is never shed.
+func (s *Shed[T]) SetLimit(priority Priority, limit int) *Shed[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits[priority.class()] = max(limit, 0)
+	return s
+}
+
+// Limit returns the in-flight limit for a priority class, zero meaning the
+// class is never shed.
+func (s *Shed[T]) Limit(priority Priority) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.limitLocked(priority)
+}
+
+// InFlight returns the number of items currently being processed.
+func (s *Shed[T]) InFlight() int {
+	return int(s.inFlight.Load())
+}
+
+// Rejected returns the number of items shed so far.
+func (s *Shed[T]) Rejected() int64 {
+	return s.shed.Load()
+}
+
+// SetProcessor updates the protected processor.
+func (s *Shed[T]) SetProcessor(processor Chainable[T]) *Shed[T] {
+	noteChildren(s.identity, processor)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processor = processor
+	return s
+}
+
+// Identity returns the identity of this connector.
+func (s *Shed[T]) Identity() Identity {
+	return s.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (s *Shed[T]) Schema() Node {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	limits := make(map[string]int, len(s.limits))
+	for p, limit := range s.limits {
+		limits[p.String()] = limit
+	}
+	return Node{
+		Identity: s.identity,
+		Type:     "shed",
+		Flow:     ShedFlow{Processor: s.processor.Schema()},
+		Metadata: map[string]any{
+			"limits": limits,
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (s *Shed[T]) Close() error {
+	s.closeOnce.Do(func() {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		s.closeErr = s.processor.Close()
+	})
+	return s.closeErr
+}
@@ -0,0 +1,105 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestShed(t *testing.T) {
+	// blockingShed returns a Shed whose processor holds items until release
+	// is closed, and a function starting an item that waits until it is in
+	// flight.
+	blockingShed := func(maxInFlight int) (*Shed[int], chan struct{}, func(Priority)) {
+		release := make(chan struct{})
+		entered := make(chan struct{})
+		hold := Effect(testIdentity("hold"), func(_ context.Context, _ int) error {
+			entered <- struct{}{}
+			<-release
+			return nil
+		})
+		shed := NewShed(testIdentity("shed"), hold, maxInFlight)
+		start := func(p Priority) {
+			go shed.Process(WithPriority(context.Background(), p), 0) //nolint:errcheck
+			<-entered
+		}
+		return shed, release, start
+	}
+
+	t.Run("Sheds Bulk First", func(t *testing.T) {
+		shed, release, start := blockingShed(4)
+		defer close(release)
+		start(PriorityNormal)
+		start(PriorityNormal)
+
+		_, err := shed.Process(WithPriority(context.Background(), PriorityBulk), 1)
+		if !errors.Is(err, ErrShed) {
+			t.Fatalf("expected bulk item to be shed at 2 in flight, got %v", err)
+		}
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "shed" {
+			t.Errorf("expected error at shed, got %v", err)
+		}
+
+		start(PriorityNormal)
+		start(PriorityHigh)
+		if _, err := shed.Process(context.Background(), 1); !errors.Is(err, ErrShed) {
+			t.Errorf("expected normal item to be shed at 4 in flight, got %v", err)
+		}
+		if shed.Rejected() != 2 || shed.InFlight() != 4 {
+			t.Errorf("expected 2 rejected and 4 in flight, got %d and %d", shed.Rejected(), shed.InFlight())
+		}
+	})
+
+	t.Run("Never Sheds Critical", func(t *testing.T) {
+		shed, release, start := blockingShed(1)
+		start(PriorityNormal)
+		start(PriorityCritical)
+		start(PriorityCritical)
+		if shed.InFlight() != 3 {
+			t.Errorf("expected 3 in flight, got %d", shed.InFlight())
+		}
+		close(release)
+	})
+
+	t.Run("Custom Limits", func(t *testing.T) {
+		shed := NewShed(testIdentity("shed"), passthroughInt(), 10).
+			SetLimit(PriorityBulk, 3).
+			SetLimit(PriorityCritical, 20).
+			SetLimit(Priority(9), 25)
+		if shed.Limit(PriorityBulk) != 3 || shed.Limit(PriorityNormal) != 10 {
+			t.Errorf("unexpected limits: bulk %d normal %d", shed.Limit(PriorityBulk), shed.Limit(PriorityNormal))
+		}
+		if shed.Limit(PriorityCritical) != 25 {
+			t.Errorf("expected out-of-range priority to set the critical limit, got %d", shed.Limit(PriorityCritical))
+		}
+		if shed.Limit(Priority(-5)) != 3 {
+			t.Errorf("expected lower priorities to use the bulk limit, got %d", shed.Limit(Priority(-5)))
+		}
+	})
+
+	t.Run("Passes Through Errors", func(t *testing.T) {
+		failing := Apply(testIdentity("failing"), func(_ context.Context, n int) (int, error) {
+			return n, errors.New("boom")
+		})
+		_, err := NewShed(testIdentity("shed"), failing, 1).Process(context.Background(), 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "shed" {
+			t.Errorf("expected path through shed, got %v", err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := NewShed(testIdentity("shed"), passthroughInt(), 10).Schema()
+		if node.Type != "shed" {
+			t.Errorf("expected shed type, got %s", node.Type)
+		}
+		if _, ok := node.Flow.(ShedFlow); !ok {
+			t.Errorf("expected ShedFlow, got %T", node.Flow)
+		}
+		limits, _ := node.Metadata["limits"].(map[string]int)
+		if limits["bulk"] != 5 || limits["normal"] != 10 {
+			t.Errorf("unexpected limits metadata: %v", node.Metadata)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package pipz
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zoobzio/capitan"
+)
+
+// FieldEncoder encodes a domain value as signal fields, so events emitted
+// while processing it can carry its identifiers (an order ID, a tenant)
+// without redefining pipz signals. Implement it on domain types, or use
+// FieldEncoderFunc or StaticFields.
+type FieldEncoder interface {
+	SignalFields() []capitan.Field
+}
+
+// FieldEncoderFunc adapts a function to the FieldEncoder interface.
+type FieldEncoderFunc func() []capitan.Field
+
+// SignalFields implements FieldEncoder.
+func (f FieldEncoderFunc) SignalFields() []capitan.Field {
+	return f()
+}
+
+// StaticFields returns a FieldEncoder for fixed fields.
+func StaticFields(fields ...capitan.Field) FieldEncoder {
+	return FieldEncoderFunc(func() []capitan.Field { return fields })
+}
+
+// signalFieldsKey is the context key for the signal field set.
+type signalFieldsKey struct{}
+
+// signalFieldSet holds the encoders attached to one scope. Encoders added
+// to a set are visible to every event emitted within it, including events
+// emitted by connectors after the adding processor returns.
+type signalFieldSet struct {
+	parent   *signalFieldSet
+	encoders []FieldEncoder
+	mu       sync.Mutex
+	run      bool
+}
+
+// fields encodes the set's fields, outer scopes first.
+func (s *signalFieldSet) fields() []capitan.Field {
+	var fields []capitan.Field
+	if s.parent != nil {
+		fields = s.parent.fields()
+	}
+	s.mu.Lock()
+	encoders := s.encoders
+	s.mu.Unlock()
+	for _, encoder := range encoders {
+		fields = append(fields, encoder.SignalFields()...)
+	}
+	return fields
+}
+
+// withSignalFieldScope returns a context carrying a field set for one
+// Pipeline run. Fields added during the run stay out of the caller's
+// context, while a nested Pipeline adds to the fields of its root run.
+func withSignalFieldScope(ctx context.Context) context.Context {
+	parent, _ := ctx.Value(signalFieldsKey{}).(*signalFieldSet)
+	if parent != nil && parent.run {
+		return ctx
+	}
+	return context.WithValue(ctx, signalFieldsKey{}, &signalFieldSet{parent: parent, run: true})
+}
+
+// WithSignalFields returns a context whose signals carry the fields of
+// encoders, in addition to those of enclosing contexts. Fields added later
+// with AddSignalFields on the returned context stay within it.
+//
+// Example:
+//
+//	ctx = pipz.WithSignalFields(ctx, pipz.StaticFields(FieldTenant.Field(tenantID)))
+//	result, err := pipeline.Process(ctx, order)
+func WithSignalFields(ctx context.Context, encoders ...FieldEncoder) context.Context {
+	parent, _ := ctx.Value(signalFieldsKey{}).(*signalFieldSet)
+	return context.WithValue(ctx, signalFieldsKey{}, &signalFieldSet{
+		parent:   parent,
+		encoders: encoders,
+	})
+}
+
+// AddSignalFields attaches encoders to the current run from inside a
+// processor, which cannot return a new context: every signal emitted for
+// the rest of the run carries their fields. Pipeline opens a field scope
+// for each Process call; without one, AddSignalFields returns false and
+// nothing is attached.
+//
+// Example:
+//
+//	load := pipz.Apply(LoadID, func(ctx context.Context, o Order) (Order, error) {
+//	    pipz.AddSignalFields(ctx, o) // Order implements FieldEncoder
+//	    return repo.Load(ctx, o)
+//	})
+func AddSignalFields(ctx context.Context, encoders ...FieldEncoder) bool {
+	set, ok := ctx.Value(signalFieldsKey{}).(*signalFieldSet)
+	if !ok {
+		return false
+	}
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.encoders = append(set.encoders[:len(set.encoders):len(set.encoders)], encoders...)
+	return true
+}
+
+// SignalFieldsFromContext returns the execution's correlation ID, if any,
+// followed by the fields attached to ctx by WithSignalFields and
+// AddSignalFields.
+func SignalFieldsFromContext(ctx context.Context) []capitan.Field {
+	if ctx == nil {
+		return nil
+	}
+	var fields []capitan.Field
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		fields = append(fields, FieldCorrelationID.Field(id))
+	}
+	if set, ok := ctx.Value(signalFieldsKey{}).(*signalFieldSet); ok {
+		fields = append(fields, set.fields()...)
+	}
+	return fields
+}
+
+// SignalFields returns an event's fields followed by the fields attached
+// to the context it was emitted with. Where both define a key the event's
+// own field wins. Encoders run when SignalFields is called, so call it in
+// the listener rather than storing the event's context. LogBridge uses it
+// for every record.
+func SignalFields(e *capitan.Event) []capitan.Field {
+	fields := e.Fields()
+	extra := SignalFieldsFromContext(e.Context())
+	if len(extra) == 0 {
+		return fields
+	}
+	seen := make(map[string]bool, len(fields)+len(extra))
+	for _, f := range fields {
+		seen[f.Key().Name()] = true
+	}
+	for _, f := range extra {
+		if name := f.Key().Name(); !seen[name] {
+			seen[name] = true
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
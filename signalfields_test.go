@@ -0,0 +1,115 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+type fieldOrder struct {
+	ID string
+}
+
+var testFieldOrderID = capitan.NewStringKey("test_order_id")
+
+func (o fieldOrder) SignalFields() []capitan.Field {
+	return []capitan.Field{testFieldOrderID.Field(o.ID)}
+}
+
+func TestSignalFields(t *testing.T) {
+	tenant := capitan.NewStringKey("test_tenant")
+
+	t.Run("No Scope", func(t *testing.T) {
+		ctx := context.Background()
+		if AddSignalFields(ctx, fieldOrder{ID: "o1"}) {
+			t.Error("expected AddSignalFields to report no scope")
+		}
+		if fields := SignalFieldsFromContext(ctx); len(fields) != 0 {
+			t.Errorf("expected no fields, got %d", len(fields))
+		}
+	})
+
+	t.Run("With Signal Fields Nests", func(t *testing.T) {
+		outer := WithSignalFields(context.Background(), StaticFields(tenant.Field("acme")))
+		inner := WithSignalFields(outer, fieldOrder{ID: "o1"})
+		AddSignalFields(inner, StaticFields(capitan.NewIntKey("test_attempt").Field(2)))
+
+		if got := len(SignalFieldsFromContext(outer)); got != 1 {
+			t.Errorf("outer fields = %d, want 1", got)
+		}
+		fields := SignalFieldsFromContext(inner)
+		if len(fields) != 3 {
+			t.Fatalf("inner fields = %d, want 3", len(fields))
+		}
+		if fields[0].Key().Name() != "test_tenant" || fields[1].Value() != "o1" {
+			t.Errorf("unexpected order: %v, %v", fields[0].Key().Name(), fields[1].Value())
+		}
+	})
+
+	t.Run("Processor Fields Reach Later Signals", func(t *testing.T) {
+		var captured []capitan.Field
+		done := make(chan struct{})
+		listener := capitan.Hook(SignalSequenceCompleted, func(_ context.Context, e *capitan.Event) {
+			captured = SignalFields(e)
+			close(done)
+		})
+		defer listener.Close()
+
+		tag := Apply(NewIdentity("tag", ""), func(ctx context.Context, o fieldOrder) (fieldOrder, error) {
+			if !AddSignalFields(ctx, o) {
+				return o, errors.New("no field scope")
+			}
+			return o, nil
+		})
+		pipeline := NewPipeline(NewIdentity("orders", ""), NewSequence(NewIdentity("steps", ""), tag))
+		ctx := WithSignalFields(context.Background(), StaticFields(tenant.Field("acme")))
+		if _, err := pipeline.Process(ctx, fieldOrder{ID: "o42"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("sequence completed signal not received")
+		}
+		if got := testFieldOrderID.ExtractFromFields(captured); got != "o42" {
+			t.Errorf("order id = %q, want o42", got)
+		}
+		if got := tenant.ExtractFromFields(captured); got != "acme" {
+			t.Errorf("tenant = %q, want acme", got)
+		}
+		if got := FieldName.ExtractFromFields(captured); got != "steps" {
+			t.Errorf("name = %q, want steps", got)
+		}
+
+		if fields := SignalFieldsFromContext(ctx); len(fields) != 1 {
+			t.Errorf("run fields leaked to caller context: %d fields", len(fields))
+		}
+	})
+
+	t.Run("Event Fields Win", func(t *testing.T) {
+		signal := capitan.NewSignal("test.signalfields.shadow", "")
+		var captured []capitan.Field
+		done := make(chan struct{})
+		listener := capitan.Hook(signal, func(_ context.Context, e *capitan.Event) {
+			captured = SignalFields(e)
+			close(done)
+		})
+		defer listener.Close()
+
+		ctx := WithSignalFields(context.Background(), StaticFields(FieldName.Field("shadow"), tenant.Field("acme")))
+		capitan.Info(ctx, signal, FieldName.Field("steps"))
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("signal not received")
+		}
+		if len(captured) != 2 || FieldName.ExtractFromFields(captured) != "steps" {
+			t.Errorf("unexpected fields: %v", captured)
+		}
+	})
+}
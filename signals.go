@@ -22,6 +22,14 @@ var (
 		"circuitbreaker.rejected",
 		"Circuit breaker rejected a request because it is in open state",
 	)
+	SignalCircuitBreakerRemoteOpened = capitan.NewSignal(
+		"circuitbreaker.remote-opened",
+		"Circuit breaker opened pre-emptively because a replica reported opening",
+	)
+	SignalCircuitBreakerSyncFailed = capitan.NewSignal(
+		"circuitbreaker.sync-failed",
+		"Circuit breaker failed to publish a state transition to replicas",
+	)
 
 	// RateLimiter signals.
 	SignalRateLimiterThrottled = capitan.NewSignal(
@@ -64,12 +72,26 @@ var (
 		"retry.exhausted",
 		"Retry connector has exhausted all retry attempts and is failing",
 	)
+	SignalRetryWaiting = capitan.NewSignal(
+		"retry.waiting",
+		"Retry connector is waiting the delay requested by a RetryAfterError before the next attempt",
+	)
+
+	// Branch retry signals.
+	SignalBranchRetrying = capitan.NewSignal(
+		"branch.retrying",
+		"A Race or Contest branch failed and is retrying under its branch retry policy",
+	)
 
 	// Fallback signals.
 	SignalFallbackAttempt = capitan.NewSignal(
 		"fallback.attempt",
 		"Fallback connector is attempting to execute a processor in the fallback chain",
 	)
+	SignalFallbackServed = capitan.NewSignal(
+		"fallback.served",
+		"Fallback processor succeeded and served the result",
+	)
 	SignalFallbackFailed = capitan.NewSignal(
 		"fallback.failed",
 		"Fallback connector exhausted all processors without success",
@@ -80,6 +102,10 @@ var (
 		"timeout.triggered",
 		"Timeout connector canceled execution because the deadline was exceeded",
 	)
+	SignalTimeoutBudgetExceeded = capitan.NewSignal(
+		"timeout.budget-exceeded",
+		"Timeout wraps time-based connectors whose combined budget exceeds its duration",
+	)
 
 	// Backoff signals.
 	SignalBackoffWaiting = capitan.NewSignal(
@@ -92,6 +118,18 @@ var (
 		"sequence.completed",
 		"Sequence connector completed processing all processors successfully",
 	)
+	SignalSequenceDone = capitan.NewSignal(
+		"sequence.done",
+		"Sequence connector finished early because a processor returned Done",
+	)
+	SignalSequenceSkipped = capitan.NewSignal(
+		"sequence.skipped",
+		"Sequence connector stopped because a processor skipped the item",
+	)
+	SignalSequenceDetached = capitan.NewSignal(
+		"sequence.detached",
+		"Sequence connector detached, drained, and closed a processor",
+	)
 
 	// Concurrent signals.
 	SignalConcurrentCompleted = capitan.NewSignal(
@@ -134,6 +172,346 @@ var (
 		"handle.error-handled",
 		"Handle connector processed an error through the error handler",
 	)
+
+	// Authz signals.
+	SignalAuthzDenied = capitan.NewSignal(
+		"authz.denied",
+		"Authorization policy denied a request",
+	)
+
+	// AuditSink signals.
+	SignalAuditWriteFailed = capitan.NewSignal(
+		"audit.write-failed",
+		"Audit sink failed to persist an audit entry",
+	)
+
+	// PII signals.
+	SignalPIIDetected = capitan.NewSignal(
+		"pii.detected",
+		"Personally identifiable information detected in data",
+	)
+
+	// Consent signals.
+	SignalConsentSuppressed = capitan.NewSignal(
+		"consent.suppressed",
+		"Record lacked consent for the declared purpose",
+	)
+
+	// TenantRouter signals.
+	SignalTenantCreated = capitan.NewSignal(
+		"tenant.created",
+		"Tenant pipeline variant constructed",
+	)
+	SignalTenantEvicted = capitan.NewSignal(
+		"tenant.evicted",
+		"Idle tenant pipeline variant evicted",
+	)
+
+	// Flagged signals.
+	SignalFlagChanged = capitan.NewSignal(
+		"flag.changed",
+		"Feature flag value changed",
+	)
+	SignalFlagError = capitan.NewSignal(
+		"flag.error",
+		"Feature flag provider failed; last known value used",
+	)
+
+	// Group signals.
+	SignalGroupFailed = capitan.NewSignal(
+		"group.failed",
+		"Group processor failed and remaining processors were canceled",
+	)
+
+	// Ready signals.
+	SignalReadyWarmed = capitan.NewSignal(
+		"ready.warmed",
+		"Ready gate warm-up completed and gate opened",
+	)
+	SignalReadyRejected = capitan.NewSignal(
+		"ready.rejected",
+		"Processing rejected because the gate was not ready",
+	)
+
+	// Verify signals.
+	SignalVerifyRejected = capitan.NewSignal(
+		"verify.rejected",
+		"Verify result was unacceptable and the fallback was engaged",
+	)
+
+	// Panic recovery signals.
+	SignalPanicRecovered = capitan.NewSignal(
+		"panic.recovered",
+		"Panic in a user goroutine was recovered and converted to an error",
+	)
+
+	// Guard signals.
+	SignalGuardRejected = capitan.NewSignal(
+		"guard.rejected",
+		"Guard check failed and the item was rejected",
+	)
+
+	// Subscription signals.
+	SignalSubscriptionFailed = capitan.NewSignal(
+		"subscription.failed",
+		"Subscription failed to decode or process a message",
+	)
+	SignalSubscriptionBatchFailed = capitan.NewSignal(
+		"subscription.batch-failed",
+		"Batch subscription failed to decode, process, or commit messages in a batch",
+	)
+
+	// Isolation signals.
+	SignalIsolationViolated = capitan.NewSignal(
+		"isolation.violated",
+		"Processor mutated state shared between a clone and the original input",
+	)
+
+	// CollectErrors signals.
+	SignalCollectErrorsFailed = capitan.NewSignal(
+		"collecterrors.failed",
+		"CollectErrors finished with one or more processor failures",
+	)
+
+	// Escalate signals.
+	SignalEscalateTriggered = capitan.NewSignal(
+		"escalate.triggered",
+		"Error met the escalation policy and the handler was invoked",
+	)
+	SignalEscalateSuppressed = capitan.NewSignal(
+		"escalate.suppressed",
+		"Error was below the escalation threshold and the handler was skipped",
+	)
+
+	// LeaderOnly signals.
+	SignalLeaderSkipped = capitan.NewSignal(
+		"leader.skipped",
+		"Processing skipped because this instance is not the leader",
+	)
+
+	// Mailbox signals.
+	SignalMailboxFailed = capitan.NewSignal(
+		"mailbox.failed",
+		"Item received from a Mailbox failed processing",
+	)
+
+	// Supervisor signals.
+	SignalSupervisorRestarting = capitan.NewSignal(
+		"supervisor.restarting",
+		"Supervised worker failed and will be restarted after a backoff",
+	)
+	SignalSupervisorEscalated = capitan.NewSignal(
+		"supervisor.escalated",
+		"Supervised worker exceeded the restart limit and the supervisor stopped",
+	)
+
+	// Deadline signals.
+	SignalDeadlinePastDue = capitan.NewSignal(
+		"deadline.past-due",
+		"Item reached a Deadline connector after its own deadline and was rejected",
+	)
+
+	// Tombstone signals.
+	SignalTombstoneRouted = capitan.NewSignal(
+		"tombstone.routed",
+		"Tombstone connector routed a deletion to its deletion processor",
+	)
+
+	// Pacer signals.
+	SignalPacerDelayed = capitan.NewSignal(
+		"pacer.delayed",
+		"Pacer queued an item until its next evenly spaced slot",
+	)
+	SignalPacerRejected = capitan.NewSignal(
+		"pacer.rejected",
+		"Pacer rejected an item because its queue was full",
+	)
+
+	// Window signals.
+	SignalWindowDelayed = capitan.NewSignal(
+		"window.delayed",
+		"Window held an item until its next processing window opened",
+	)
+	SignalWindowRouted = capitan.NewSignal(
+		"window.routed",
+		"Window routed an item arriving outside its windows to the alternate processor",
+	)
+	SignalWindowRejected = capitan.NewSignal(
+		"window.rejected",
+		"Window rejected an item that could not wait for its next processing window",
+	)
+
+	// Batch budget signals.
+	SignalBatchBudgetExhausted = capitan.NewSignal(
+		"batchbudget.exhausted",
+		"BudgetTimeout rejected an item because its batch had no time left",
+	)
+
+	// Quarantine signals.
+	SignalQuarantined = capitan.NewSignal(
+		"quarantine.quarantined",
+		"Quarantine routed a repeatedly failing item to its quarantine sink",
+	)
+
+	// Approval signals.
+	SignalApprovalRequested = capitan.NewSignal(
+		"approval.requested",
+		"Approval requested external sign-off and paused the item",
+	)
+	SignalApprovalDenied = capitan.NewSignal(
+		"approval.denied",
+		"Approval found the item's request denied and rejected it",
+	)
+
+	// Change limit signals.
+	SignalChangeLimited = capitan.NewSignal(
+		"changelimit.limited",
+		"ChangeLimit rejected or clamped a value that moved too far since its key's last value",
+	)
+
+	// Data quality signals.
+	SignalQualityRemediated = capitan.NewSignal(
+		"dataquality.remediated",
+		"DataQuality routed a record scoring below its threshold to remediation",
+	)
+
+	// SLA signals.
+	SignalSLAWarning = capitan.NewSignal(
+		"sla.warning",
+		"SLA item has been processing longer than the warn threshold",
+	)
+	SignalSLACritical = capitan.NewSignal(
+		"sla.critical",
+		"SLA item has been processing longer than the critical threshold",
+	)
+
+	// Panic circuit signals.
+	SignalProcessorQuarantined = capitan.NewSignal(
+		"panic.quarantined",
+		"Processor panicked repeatedly and was disabled by the panic circuit",
+	)
+	SignalProcessorReenabled = capitan.NewSignal(
+		"panic.reenabled",
+		"Quarantined processor was re-enabled",
+	)
+
+	// Shed signals.
+	SignalShedRejected = capitan.NewSignal(
+		"shed.rejected",
+		"Shed rejected an item because in-flight work exceeded its priority's limit",
+	)
+
+	// Hedge signals.
+	SignalHedgeLaunched = capitan.NewSignal(
+		"hedge.launched",
+		"Hedge started a duplicate attempt because earlier attempts were slow",
+	)
+
+	// Attempt budget signals.
+	SignalAttemptBudgetExhausted = capitan.NewSignal(
+		"attemptbudget.exhausted",
+		"Retry or hedge was skipped because the shared attempt budget was spent",
+	)
+
+	// Poll signals.
+	SignalPollWaiting = capitan.NewSignal(
+		"poll.waiting",
+		"Poll condition not yet met, waiting before the next check",
+	)
+	SignalPollExpired = capitan.NewSignal(
+		"poll.expired",
+		"Poll gave up because its condition did not hold within the maximum wait",
+	)
+
+	// DAG signals.
+	SignalDAGCompleted = capitan.NewSignal(
+		"dag.completed",
+		"DAG connector completed its dependency-ordered processors",
+	)
+
+	// Paginate signals.
+	SignalPaginateCompleted = capitan.NewSignal(
+		"paginate.completed",
+		"Paginate fetched and processed every page, or its page budget",
+	)
+
+	// Coordinator signals.
+	SignalCoordinatorCommitted = capitan.NewSignal(
+		"coordinator.committed",
+		"Coordinator committed every participant after all prepared",
+	)
+	SignalCoordinatorAborted = capitan.NewSignal(
+		"coordinator.aborted",
+		"Coordinator aborted prepared participants because another failed to prepare",
+	)
+	SignalCoordinatorPartialCommit = capitan.NewSignal(
+		"coordinator.partial_commit",
+		"Coordinator participants failed to commit after all prepared",
+	)
+
+	// Adaptive concurrency signals.
+	SignalAdaptiveLimitChanged = capitan.NewSignal(
+		"adaptive.limit_changed",
+		"AdaptiveConcurrency adjusted its concurrency limit from observed latency",
+	)
+
+	// Content hash signals.
+	SignalContentUnchanged = capitan.NewSignal(
+		"contenthash.unchanged",
+		"ContentHash skipped an item whose content had not changed since it was last processed",
+	)
+
+	// Keyed state signals.
+	SignalKeysEvicted = capitan.NewSignal(
+		"keys.evicted",
+		"A keyed connector evicted per-key state that expired or exceeded its key limit",
+	)
+
+	// Region failover signals.
+	SignalRegionFailover = capitan.NewSignal(
+		"region.failover",
+		"RegionFailover moved traffic away from an unhealthy region",
+	)
+	SignalRegionFailback = capitan.NewSignal(
+		"region.failback",
+		"RegionFailover returned traffic to a preferred region that recovered",
+	)
+
+	// Batch size signals.
+	SignalBatchSizeChanged = capitan.NewSignal(
+		"batch.size_changed",
+		"A BatchSizeController adjusted the batch size from batch latency and failures",
+	)
+
+	// Checkpoint signals.
+	SignalCheckpointFailed = capitan.NewSignal(
+		"subscription.checkpoint_failed",
+		"Subscription failed to commit its checkpointed position and will retry on the next commit",
+	)
+
+	// Stagger signals.
+	SignalStaggerDelayed = capitan.NewSignal(
+		"stagger.delayed",
+		"Stagger delayed a run by this instance's offset to spread replicas apart",
+	)
+
+	// Assert signals.
+	SignalAssertionFailed = capitan.NewSignal(
+		"assert.failed",
+		"Assert found an invariant violated by the data passing through",
+	)
+
+	// Reconfiguration signals.
+	SignalReconfigured = capitan.NewSignal(
+		"connector.reconfigured",
+		"Connector parameters were updated at runtime",
+	)
+
+	// Policy signals.
+	SignalPolicyViolation = capitan.NewSignal(
+		"policy.violation",
+		"Connector configuration exceeded the active policy and was capped",
+	)
 )
 
 // Common field keys using capitan primitive types.
@@ -178,6 +556,7 @@ var (
 
 	// Timeout fields.
 	FieldDuration = capitan.NewFloat64Key("duration") // Timeout duration in seconds
+	FieldBudget   = capitan.NewFloat64Key("budget")   // Nested time budget in seconds
 
 	// Backoff fields.
 	FieldDelay     = capitan.NewFloat64Key("delay")      // Current backoff delay in seconds
@@ -198,4 +577,80 @@ var (
 
 	// Filter fields.
 	FieldPassed = capitan.NewBoolKey("passed") // Whether filter condition passed
+
+	// PII fields.
+	FieldPIIKinds  = capitan.NewStringKey("pii_kinds")  // Comma-separated PII kinds found
+	FieldPIIFields = capitan.NewStringKey("pii_fields") // Comma-separated fields containing PII
+
+	// Consent fields.
+	FieldPurpose = capitan.NewStringKey("purpose") // Declared processing purpose
+
+	// TenantRouter fields.
+	FieldTenant = capitan.NewStringKey("tenant") // Tenant identifier
+
+	// Flagged fields.
+	FieldFlag    = capitan.NewStringKey("flag")  // Feature flag name
+	FieldEnabled = capitan.NewBoolKey("enabled") // Whether the flag is enabled
+
+	// Reconfiguration fields.
+	FieldOptions = capitan.NewStringKey("options") // Comma-separated options applied
+
+	// Policy fields.
+	FieldPolicyRule = capitan.NewStringKey("policy_rule") // Policy rule that was exceeded
+	FieldRequested  = capitan.NewIntKey("requested")      // Value the configuration asked for
+	FieldAllowed    = capitan.NewIntKey("allowed")        // Value permitted by the policy
+
+	// Subscription fields.
+	FieldTopic     = capitan.NewStringKey("topic")   // Topic the message was received on
+	FieldBatchSize = capitan.NewIntKey("batch_size") // Number of messages in the batch
+
+	// Escalate fields.
+	FieldSevere = capitan.NewBoolKey("severe") // Whether the error matched the severity criteria
+
+	// Supervisor fields.
+	FieldWorker = capitan.NewStringKey("worker") // Name of the supervised worker
+
+	// Pacer fields.
+	FieldQueued = capitan.NewIntKey("queued") // Items waiting for a slot
+
+	// Quarantine fields.
+	FieldItemKey = capitan.NewStringKey("item_key") // Key identifying repeated deliveries of an item
+
+	// Approval fields.
+	FieldTicketID = capitan.NewStringKey("ticket_id") // Approval ticket awaiting a decision
+
+	// Change limit fields.
+	FieldPreviousValue = capitan.NewFloat64Key("previous_value") // Last accepted value of the key
+	FieldValue         = capitan.NewFloat64Key("value")          // Value that moved too far
+
+	// Data quality fields.
+	FieldQualityScore = capitan.NewFloat64Key("quality_score") // Weighted share of quality checks passed
+
+	// SLA fields.
+	FieldThreshold = capitan.NewFloat64Key("threshold") // SLA threshold in seconds
+	FieldElapsed   = capitan.NewFloat64Key("elapsed")   // Time since the item's clock started in seconds
+
+	// Priority fields.
+	FieldPriority = capitan.NewStringKey("priority") // Priority class of the item
+	FieldInFlight = capitan.NewIntKey("in_flight")   // Items currently being processed
+	FieldLimit    = capitan.NewIntKey("limit")       // In-flight limit for the item's priority
+
+	// Correlation fields.
+	FieldCorrelationID = capitan.NewStringKey("correlation_id") // ID shared by every signal of one root execution
+
+	// Paginate fields.
+	FieldPages  = capitan.NewIntKey("pages")     // Pages fetched
+	FieldItems  = capitan.NewIntKey("items")     // Items processed
+	FieldReason = capitan.NewStringKey("reason") // Why pagination stopped or keys were evicted
+
+	// Keyed state fields.
+	FieldEvicted = capitan.NewIntKey("evicted") // Keys evicted
+	FieldKeys    = capitan.NewIntKey("keys")    // Keys still held
+
+	// Region failover fields.
+	FieldRegion     = capitan.NewStringKey("region")      // Region now serving traffic
+	FieldFromRegion = capitan.NewStringKey("from_region") // Region traffic moved away from
+
+	// Stagger fields.
+	FieldInstance = capitan.NewStringKey("instance") // Instance ID the offset derives from
 )
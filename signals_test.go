@@ -13,6 +13,8 @@ func TestSignalsInitialized(t *testing.T) {
 		{"CircuitBreakerClosed", SignalCircuitBreakerClosed},
 		{"CircuitBreakerHalfOpen", SignalCircuitBreakerHalfOpen},
 		{"CircuitBreakerRejected", SignalCircuitBreakerRejected},
+		{"CircuitBreakerRemoteOpened", SignalCircuitBreakerRemoteOpened},
+		{"CircuitBreakerSyncFailed", SignalCircuitBreakerSyncFailed},
 		{"RateLimiterThrottled", SignalRateLimiterThrottled},
 		{"RateLimiterDropped", SignalRateLimiterDropped},
 		{"RateLimiterAllowed", SignalRateLimiterAllowed},
@@ -22,11 +24,17 @@ func TestSignalsInitialized(t *testing.T) {
 		{"RetryAttemptStart", SignalRetryAttemptStart},
 		{"RetryAttemptFail", SignalRetryAttemptFail},
 		{"RetryExhausted", SignalRetryExhausted},
+		{"RetryWaiting", SignalRetryWaiting},
 		{"FallbackAttempt", SignalFallbackAttempt},
+		{"FallbackServed", SignalFallbackServed},
 		{"FallbackFailed", SignalFallbackFailed},
 		{"TimeoutTriggered", SignalTimeoutTriggered},
+		{"TimeoutBudgetExceeded", SignalTimeoutBudgetExceeded},
 		{"BackoffWaiting", SignalBackoffWaiting},
 		{"SequenceCompleted", SignalSequenceCompleted},
+		{"SequenceDone", SignalSequenceDone},
+		{"SequenceSkipped", SignalSequenceSkipped},
+		{"SequenceDetached", SignalSequenceDetached},
 		{"ConcurrentCompleted", SignalConcurrentCompleted},
 		{"RaceWinner", SignalRaceWinner},
 		{"ContestWinner", SignalContestWinner},
@@ -34,6 +42,69 @@ func TestSignalsInitialized(t *testing.T) {
 		{"SwitchRouted", SignalSwitchRouted},
 		{"FilterEvaluated", SignalFilterEvaluated},
 		{"HandleErrorHandled", SignalHandleErrorHandled},
+		{"AuthzDenied", SignalAuthzDenied},
+		{"AuditWriteFailed", SignalAuditWriteFailed},
+		{"PIIDetected", SignalPIIDetected},
+		{"ConsentSuppressed", SignalConsentSuppressed},
+		{"TenantCreated", SignalTenantCreated},
+		{"TenantEvicted", SignalTenantEvicted},
+		{"FlagChanged", SignalFlagChanged},
+		{"FlagError", SignalFlagError},
+		{"GroupFailed", SignalGroupFailed},
+		{"ReadyWarmed", SignalReadyWarmed},
+		{"ReadyRejected", SignalReadyRejected},
+		{"VerifyRejected", SignalVerifyRejected},
+		{"PanicRecovered", SignalPanicRecovered},
+		{"GuardRejected", SignalGuardRejected},
+		{"SubscriptionFailed", SignalSubscriptionFailed},
+		{"SubscriptionBatchFailed", SignalSubscriptionBatchFailed},
+		{"IsolationViolated", SignalIsolationViolated},
+		{"CollectErrorsFailed", SignalCollectErrorsFailed},
+		{"EscalateTriggered", SignalEscalateTriggered},
+		{"EscalateSuppressed", SignalEscalateSuppressed},
+		{"LeaderSkipped", SignalLeaderSkipped},
+		{"MailboxFailed", SignalMailboxFailed},
+		{"SupervisorRestarting", SignalSupervisorRestarting},
+		{"SupervisorEscalated", SignalSupervisorEscalated},
+		{"DeadlinePastDue", SignalDeadlinePastDue},
+		{"TombstoneRouted", SignalTombstoneRouted},
+		{"PacerDelayed", SignalPacerDelayed},
+		{"PacerRejected", SignalPacerRejected},
+		{"WindowDelayed", SignalWindowDelayed},
+		{"WindowRouted", SignalWindowRouted},
+		{"WindowRejected", SignalWindowRejected},
+		{"BatchBudgetExhausted", SignalBatchBudgetExhausted},
+		{"Quarantined", SignalQuarantined},
+		{"ApprovalRequested", SignalApprovalRequested},
+		{"ApprovalDenied", SignalApprovalDenied},
+		{"ChangeLimited", SignalChangeLimited},
+		{"QualityRemediated", SignalQualityRemediated},
+		{"SLAWarning", SignalSLAWarning},
+		{"SLACritical", SignalSLACritical},
+		{"ProcessorQuarantined", SignalProcessorQuarantined},
+		{"ProcessorReenabled", SignalProcessorReenabled},
+		{"ShedRejected", SignalShedRejected},
+		{"HedgeLaunched", SignalHedgeLaunched},
+		{"AttemptBudgetExhausted", SignalAttemptBudgetExhausted},
+		{"PollWaiting", SignalPollWaiting},
+		{"PollExpired", SignalPollExpired},
+		{"DAGCompleted", SignalDAGCompleted},
+		{"PaginateCompleted", SignalPaginateCompleted},
+		{"CoordinatorCommitted", SignalCoordinatorCommitted},
+		{"CoordinatorAborted", SignalCoordinatorAborted},
+		{"CoordinatorPartialCommit", SignalCoordinatorPartialCommit},
+		{"AdaptiveLimitChanged", SignalAdaptiveLimitChanged},
+		{"ContentUnchanged", SignalContentUnchanged},
+		{"KeysEvicted", SignalKeysEvicted},
+		{"BranchRetrying", SignalBranchRetrying},
+		{"RegionFailover", SignalRegionFailover},
+		{"RegionFailback", SignalRegionFailback},
+		{"BatchSizeChanged", SignalBatchSizeChanged},
+		{"CheckpointFailed", SignalCheckpointFailed},
+		{"StaggerDelayed", SignalStaggerDelayed},
+		{"AssertionFailed", SignalAssertionFailed},
+		{"Reconfigured", SignalReconfigured},
+		{"PolicyViolation", SignalPolicyViolation},
 	}
 
 	for _, s := range signals {
@@ -73,6 +144,7 @@ func TestFieldKeysInitialized(t *testing.T) {
 		{"ProcessorIndex", FieldProcessorIndex},
 		{"ProcessorName", FieldProcessorName},
 		{"Duration", FieldDuration},
+		{"Budget", FieldBudget},
 		{"Delay", FieldDelay},
 		{"NextDelay", FieldNextDelay},
 		{"ProcessorCount", FieldProcessorCount},
@@ -81,6 +153,40 @@ func TestFieldKeysInitialized(t *testing.T) {
 		{"RouteKey", FieldRouteKey},
 		{"Matched", FieldMatched},
 		{"Passed", FieldPassed},
+		{"PIIKinds", FieldPIIKinds},
+		{"PIIFields", FieldPIIFields},
+		{"Purpose", FieldPurpose},
+		{"Tenant", FieldTenant},
+		{"Flag", FieldFlag},
+		{"Enabled", FieldEnabled},
+		{"Options", FieldOptions},
+		{"PolicyRule", FieldPolicyRule},
+		{"Requested", FieldRequested},
+		{"Allowed", FieldAllowed},
+		{"Topic", FieldTopic},
+		{"BatchSize", FieldBatchSize},
+		{"Severe", FieldSevere},
+		{"Worker", FieldWorker},
+		{"Queued", FieldQueued},
+		{"ItemKey", FieldItemKey},
+		{"TicketID", FieldTicketID},
+		{"PreviousValue", FieldPreviousValue},
+		{"Value", FieldValue},
+		{"QualityScore", FieldQualityScore},
+		{"Threshold", FieldThreshold},
+		{"Elapsed", FieldElapsed},
+		{"Priority", FieldPriority},
+		{"InFlight", FieldInFlight},
+		{"Limit", FieldLimit},
+		{"Pages", FieldPages},
+		{"Items", FieldItems},
+		{"Reason", FieldReason},
+		{"Evicted", FieldEvicted},
+		{"Keys", FieldKeys},
+		{"Region", FieldRegion},
+		{"FromRegion", FieldFromRegion},
+		{"Instance", FieldInstance},
+		{"CorrelationID", FieldCorrelationID},
 	}
 
 	for _, f := range fields {
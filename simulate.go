@@ -0,0 +1,123 @@
+package pipz
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SimulatedEffect is a side effect stubbed out during Simulate: the stage
+// that would have run and the value it would have received.
+type SimulatedEffect struct {
+	Timestamp time.Time
+	Input     any
+	Identity  Identity
+}
+
+// SimulationReport lists the side effects a simulated run stubbed out, in
+// the order they would have happened.
+type SimulationReport struct {
+	Effects []SimulatedEffect
+}
+
+// simulation is the state of a simulated run, carried on its context.
+type simulation struct {
+	sideEffects Selector
+	effects     []SimulatedEffect
+	mu          sync.Mutex
+}
+
+// simulationKey is the context key for the active simulation.
+type simulationKey struct{}
+
+// Simulate runs processor on data as a full-fidelity preview: pure
+// transforms, routing, and validation run as usual, while side-effecting
+// stages are replaced with no-ops that pass their input through and are
+// recorded in the report. It shows what a run would change and which
+// effects it would trigger, such as the charges and notifications of an
+// order, without triggering them.
+//
+// A processor is side-effecting if sideEffects selects its identity, such
+// as SelectTags("side-effect"), or if it is wrapped with AsSideEffect,
+// which also covers custom Chainables and whole sub-pipelines. A nil
+// sideEffects relies on AsSideEffect alone.
+//
+// Simulation is only as safe as the classification: any stage it misses
+// runs for real.
+//
+// Example:
+//
+//	preview, report, err := pipz.Simulate(ctx, checkout, order, pipz.SelectTags("side-effect"))
+//	for _, effect := range report.Effects {
+//	    fmt.Printf("would run %s\n", effect.Identity.Name())
+//	}
+func Simulate[T any](ctx context.Context, processor Chainable[T], data T, sideEffects Selector) (T, SimulationReport, error) {
+	sim := &simulation{sideEffects: sideEffects}
+	updateHooks(func(h *processorHooks) { h.simulations++ })
+	defer updateHooks(func(h *processorHooks) { h.simulations-- })
+
+	result, err := processor.Process(context.WithValue(ctx, simulationKey{}, sim), data)
+
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+	return result, SimulationReport{Effects: sim.effects}, err
+}
+
+// simulationFrom returns the simulation ctx belongs to, or nil outside a
+// simulated run.
+func simulationFrom(ctx context.Context) *simulation {
+	if hooks := activeHooks.Load(); hooks == nil || hooks.simulations == 0 || ctx == nil {
+		return nil
+	}
+	sim, _ := ctx.Value(simulationKey{}).(*simulation)
+	return sim
+}
+
+// stub records a side effect of identity on input, which the caller skips.
+func (s *simulation) stub(identity Identity, input any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.effects = append(s.effects, SimulatedEffect{
+		Timestamp: time.Now(),
+		Input:     input,
+		Identity:  identity,
+	})
+}
+
+// stubProcessor reports whether the processor identified by identity is
+// side-effecting in the simulation carried by ctx, recording the stubbed
+// effect if so. It is generic so data is only boxed when it is recorded.
+func stubProcessor[T any](ctx context.Context, identity Identity, data T) bool {
+	sim := simulationFrom(ctx)
+	if sim == nil || sim.sideEffects == nil || !sim.sideEffects(identity) {
+		return false
+	}
+	sim.stub(identity, data)
+	return true
+}
+
+// sideEffectChainable marks a Chainable as side-effecting.
+type sideEffectChainable[T any] struct {
+	Chainable[T]
+}
+
+// Process implements the Chainable interface, passing data through in
+// place of processor during a simulated run.
+func (s sideEffectChainable[T]) Process(ctx context.Context, data T) (T, error) {
+	if sim := simulationFrom(ctx); sim != nil {
+		sim.stub(s.Identity(), data)
+		return data, nil
+	}
+	return s.Chainable.Process(ctx, data)
+}
+
+// AsSideEffect marks processor as side-effecting, so Simulate replaces it
+// with a no-op. Outside a simulated run it behaves exactly as processor.
+// Identity, Schema, and Close are those of processor.
+//
+// Example:
+//
+//	notify := pipz.AsSideEffect(pipz.NewSequence(NotifyID, renderEmail, sendEmail))
+func AsSideEffect[T any](processor Chainable[T]) Chainable[T] {
+	return sideEffectChainable[T]{Chainable: processor}
+}
@@ -0,0 +1,83 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	// build returns a checkout pipeline whose effects count real runs.
+	build := func(charged, emailed *atomic.Int32) Chainable[int] {
+		return NewSequence(testIdentity("checkout"),
+			Transform(testIdentity("price"), func(_ context.Context, n int) int { return n * 2 }),
+			Effect(NewIdentity("charge", "").WithTags("side-effect"), func(_ context.Context, _ int) error {
+				charged.Add(1)
+				return nil
+			}),
+			Transform(testIdentity("discount"), func(_ context.Context, n int) int { return n - 1 }),
+			AsSideEffect(NewSequence(testIdentity("notify"),
+				Effect(testIdentity("email"), func(_ context.Context, _ int) error {
+					emailed.Add(1)
+					return nil
+				}),
+			)),
+		)
+	}
+
+	t.Run("Stubs Side Effects", func(t *testing.T) {
+		var charged, emailed atomic.Int32
+		result, report, err := Simulate(context.Background(), build(&charged, &emailed), 10, SelectTags("side-effect"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != 19 {
+			t.Errorf("expected pure transforms to run, got %d", result)
+		}
+		if charged.Load() != 0 || emailed.Load() != 0 {
+			t.Errorf("expected no real effects, got %d charges and %d emails", charged.Load(), emailed.Load())
+		}
+		if len(report.Effects) != 2 {
+			t.Fatalf("expected 2 stubbed effects, got %d", len(report.Effects))
+		}
+		if report.Effects[0].Identity.Name() != "charge" || report.Effects[0].Input != 20 {
+			t.Errorf("unexpected first effect: %+v", report.Effects[0])
+		}
+		if report.Effects[1].Identity.Name() != "notify" || report.Effects[1].Input != 19 {
+			t.Errorf("unexpected second effect: %+v", report.Effects[1])
+		}
+	})
+
+	t.Run("Real Run Unaffected", func(t *testing.T) {
+		var charged, emailed atomic.Int32
+		if _, err := build(&charged, &emailed).Process(context.Background(), 10); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if charged.Load() != 1 || emailed.Load() != 1 {
+			t.Errorf("expected real effects outside simulation, got %d charges and %d emails", charged.Load(), emailed.Load())
+		}
+	})
+
+	t.Run("Nil Selector Uses Markers Only", func(t *testing.T) {
+		var charged, emailed atomic.Int32
+		_, report, err := Simulate(context.Background(), build(&charged, &emailed), 10, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if charged.Load() != 1 || emailed.Load() != 0 || len(report.Effects) != 1 {
+			t.Errorf("expected only the marked effect stubbed, got %d charges, %d emails, %d effects",
+				charged.Load(), emailed.Load(), len(report.Effects))
+		}
+	})
+
+	t.Run("Reports Failures", func(t *testing.T) {
+		failing := Apply(testIdentity("validate"), func(_ context.Context, n int) (int, error) {
+			return n, errors.New("invalid")
+		})
+		_, _, err := Simulate(context.Background(), failing, 1, SelectTags("side-effect"))
+		if err == nil {
+			t.Error("expected validation failure from simulation")
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package pipz
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSkip signals that a processor deliberately dropped an item: it should
+// not continue down the pipeline, but nothing went wrong. Return it (or
+// Skip) from any processor to filter mid-sequence without an error branch.
+//
+// A skip still travels as an error so that every enclosing connector stops
+// processing the item, but pipz treats it as an outcome rather than a
+// failure:
+//   - Sequence stops and emits sequence.skipped
+//   - Retry and Backoff do not retry it (nor an early exit from Done)
+//   - Fallback does not try its backups
+//   - CircuitBreaker counts it as a success
+//   - Handle does not pass it to the error handler
+//   - Pipeline counts it in Stats().Skipped rather than Failed
+//   - Subscription acks the message, and Mailbox.Run does not report it
+//
+// Callers check for it with IsSkip.
+//
+// Example:
+//
+//	dropTests := pipz.Apply(DropTestsID, func(_ context.Context, o Order) (Order, error) {
+//	    if o.Test {
+//	        return o, pipz.Skip("test order")
+//	    }
+//	    return o, nil
+//	})
+//
+//	if _, err := pipeline.Process(ctx, order); err != nil && !pipz.IsSkip(err) {
+//	    return err
+//	}
+var ErrSkip = errors.New("skipped")
+
+// Skip returns an error wrapping ErrSkip that records why the item was
+// dropped.
+func Skip(reason string) error {
+	if reason == "" {
+		return ErrSkip
+	}
+	return fmt.Errorf("%w: %s", ErrSkip, reason)
+}
+
+// IsSkip reports whether err means an item was deliberately dropped.
+func IsSkip(err error) bool {
+	return errors.Is(err, ErrSkip)
+}
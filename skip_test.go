@@ -0,0 +1,159 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestSkip(t *testing.T) {
+	var calls atomic.Int32
+	skipOdd := Apply(NewIdentity("skip-odd", ""), func(_ context.Context, v int) (int, error) {
+		calls.Add(1)
+		if v%2 == 1 {
+			return v, Skip("odd")
+		}
+		return v, nil
+	})
+	double := Transform(NewIdentity("double", ""), func(_ context.Context, v int) int { return v * 2 })
+
+	t.Run("Skip Helper", func(t *testing.T) {
+		if Skip("") != ErrSkip {
+			t.Error("expected empty reason to return ErrSkip itself")
+		}
+		err := Skip("duplicate")
+		if !IsSkip(err) || err.Error() != "skipped: duplicate" {
+			t.Errorf("unexpected skip error: %v", err)
+		}
+		if IsSkip(errors.New("boom")) || IsSkip(nil) {
+			t.Error("expected ordinary errors not to be skips")
+		}
+	})
+
+	t.Run("Sequence Stops Without Failure Signal", func(t *testing.T) {
+		var skippedAt string
+		listener := capitan.Hook(SignalSequenceSkipped, func(_ context.Context, e *capitan.Event) {
+			skippedAt, _ = FieldProcessorName.From(e)
+		})
+		defer listener.Close()
+
+		seq := NewSequence(NewIdentity("seq", ""), skipOdd, double)
+		result, err := seq.Process(context.Background(), 3)
+		if !IsSkip(err) || result != 3 {
+			t.Fatalf("expected skip with value 3, got %d (%v)", result, err)
+		}
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || pipeErr.Category() != ErrorCategorySkipped || pipeErr.StepIndex != 0 {
+			t.Errorf("expected skipped error at step 0, got %+v", pipeErr)
+		}
+		if kept, keptErr := seq.Process(context.Background(), 4); keptErr != nil || kept != 8 {
+			t.Errorf("expected even value to continue, got %d (%v)", kept, keptErr)
+		}
+
+		if drainErr := listener.Drain(context.Background()); drainErr != nil {
+			t.Fatalf("drain failed: %v", drainErr)
+		}
+		if skippedAt != "skip-odd" {
+			t.Errorf("expected skip signal from 'skip-odd', got %q", skippedAt)
+		}
+	})
+
+	t.Run("Pipeline Counts Skips Separately", func(t *testing.T) {
+		p := NewPipeline(NewIdentity("pipeline", ""), NewSequence(NewIdentity("seq", ""), skipOdd, double))
+		for i := 0; i < 4; i++ {
+			_, _ = p.Process(context.Background(), i) //nolint:errcheck // counted below
+		}
+		if stats := p.Stats(); stats.Processed != 4 || stats.Skipped != 2 || stats.Failed != 0 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Retry And Backoff Do Not Retry", func(t *testing.T) {
+		calls.Store(0)
+		_, err := NewRetry(NewIdentity("retry", ""), skipOdd, 5).Process(context.Background(), 1)
+		if !IsSkip(err) || calls.Load() != 1 {
+			t.Errorf("expected one call for retry, got %d (%v)", calls.Load(), err)
+		}
+		calls.Store(0)
+		_, err = NewBackoff(NewIdentity("backoff", ""), skipOdd, 5, time.Hour).Process(context.Background(), 1)
+		if !IsSkip(err) || calls.Load() != 1 {
+			t.Errorf("expected one call for backoff, got %d (%v)", calls.Load(), err)
+		}
+	})
+
+	t.Run("Fallback Does Not Try Backups", func(t *testing.T) {
+		var backupRan bool
+		backup := Transform(NewIdentity("backup", ""), func(_ context.Context, v int) int {
+			backupRan = true
+			return v
+		})
+		_, err := NewFallback(NewIdentity("fallback", ""), skipOdd, backup).Process(context.Background(), 1)
+		var pipeErr *Error[int]
+		if !IsSkip(err) || backupRan || !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "fallback" {
+			t.Errorf("expected skip from primary only, got %v (backup ran: %v)", err, backupRan)
+		}
+	})
+
+	t.Run("CircuitBreaker Does Not Trip", func(t *testing.T) {
+		cb := NewCircuitBreaker(NewIdentity("breaker", ""), skipOdd, 2, time.Minute)
+		for i := 0; i < 5; i++ {
+			_, _ = cb.Process(context.Background(), 1) //nolint:errcheck // skips expected
+		}
+		if cb.GetState() != stateClosed {
+			t.Errorf("expected breaker closed after skips, got %s", cb.GetState())
+		}
+	})
+
+	t.Run("Handle Does Not Invoke Handler", func(t *testing.T) {
+		var handled bool
+		handler := Effect(NewIdentity("handler", ""), func(context.Context, *Error[int]) error {
+			handled = true
+			return nil
+		})
+		_, err := NewHandle(NewIdentity("handle", ""), skipOdd, handler).Process(context.Background(), 1)
+		if !IsSkip(err) || handled {
+			t.Errorf("expected skip to bypass the handler, got %v (handled: %v)", err, handled)
+		}
+	})
+
+	t.Run("Subscription Acks Skipped Messages", func(t *testing.T) {
+		var acked, nacked atomic.Int32
+		sub := &queueSubscriber{messages: []Message{{
+			Topic:   "n",
+			Payload: []byte("1"),
+			Ack:     func(context.Context) error { acked.Add(1); return nil },
+			Nack:    func(context.Context, error) error { nacked.Add(1); return nil },
+		}}}
+		handlerErr := errors.New("stop")
+		err := NewSubscription[int](NewIdentity("numbers", ""), sub, nil, skipOdd).
+			SetErrorHandler(func(context.Context, Message, error) error { return handlerErr }).
+			Run(context.Background())
+		if err != nil || acked.Load() != 1 || nacked.Load() != 0 {
+			t.Errorf("expected skip acked without error, got %v (acked %d, nacked %d)", err, acked.Load(), nacked.Load())
+		}
+	})
+
+	t.Run("Mailbox Run Does Not Report Skips", func(t *testing.T) {
+		var failures atomic.Int32
+		listener := capitan.Hook(SignalMailboxFailed, func(context.Context, *capitan.Event) {
+			failures.Add(1)
+		})
+		defer listener.Close()
+
+		box := NewMailbox[int](NewIdentity("box", ""), 2)
+		_ = box.Send(context.Background(), 1)      //nolint:errcheck // buffered
+		_ = box.Close()                            //nolint:errcheck // always nil
+		_ = box.Run(context.Background(), skipOdd) //nolint:errcheck // always nil
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if failures.Load() != 0 {
+			t.Errorf("expected no failure signals, got %d", failures.Load())
+		}
+	})
+}
@@ -0,0 +1,233 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// SLA watches the total time an item spends in a processor, typically a
+// whole pipeline, and raises escalating alerts while it is still running:
+// SignalSLAWarning once the warn threshold passes and SignalSLACritical
+// once the critical threshold passes. Unlike Timeout it never cancels the
+// work; it only reports, independently of any step timeouts inside.
+//
+// The clock starts when the item enters the SLA, or at the time read from
+// the item by SetStartFunc, such as an event's creation timestamp, so time
+// spent queued upstream counts too. An item already past a threshold when
+// it arrives alerts immediately. A zero threshold disables its level.
+//
+// Example:
+//
+//	var OrderSLAID = pipz.NewIdentity("order-sla", "Alerts on slow order fulfilment")
+//	sla := pipz.NewSLA(OrderSLAID, fulfilment, time.Second, 5*time.Second).
+//	    SetStartFunc(func(o Order) time.Time { return o.ReceivedAt })
+type SLA[T any] struct {
+	processor Chainable[T]
+	start     func(T) time.Time
+	clock     clockz.Clock
+	identity  Identity
+	warn      time.Duration
+	critical  time.Duration
+	mu        sync.RWMutex
+	warnings  atomic.Int64
+	breaches  atomic.Int64
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewSLA creates an SLA alerting when processor has held an item longer
+// than warn, and again past critical.
+func NewSLA[T any](identity Identity, processor Chainable[T], warn, critical time.Duration) *SLA[T] {
+	return &SLA[T]{
+		identity:  identity,
+		processor: processor,
+		warn:      warn,
+		critical:  critical,
+	}
+}
+
+// Process implements the Chainable interface.
+// Runs the processor while watching its elapsed time.
+func (s *SLA[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, s.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, s, s.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	s.mu.RLock()
+	processor := s.processor
+	startFunc := s.start
+	warn := s.warn
+	critical := s.critical
+	clock := s.getClock()
+	s.mu.RUnlock()
+
+	started := clock.Now()
+	if startFunc != nil {
+		if t := startFunc(data); !t.IsZero() {
+			started = t
+		}
+	}
+
+	done := make(chan struct{})
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		s.watch(ctx, clock, started, warn, critical, done)
+	}()
+	defer func() {
+		close(done)
+		<-watched
+	}()
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, s.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{s.identity},
+			Duration:  clock.Since(started),
+		}
+	}
+	return result, nil
+}
+
+// watch emits each threshold's signal as it passes, until done closes.
+func (s *SLA[T]) watch(ctx context.Context, clock clockz.Clock, started time.Time, warn, critical time.Duration, done <-chan struct{}) {
+	levels := []struct {
+		signal    capitan.Signal
+		severity  capitan.Severity
+		threshold time.Duration
+		count     *atomic.Int64
+	}{
+		{SignalSLAWarning, capitan.SeverityWarn, warn, &s.warnings},
+		{SignalSLACritical, capitan.SeverityError, critical, &s.breaches},
+	}
+	for _, level := range levels {
+		if level.threshold <= 0 {
+			continue
+		}
+		if wait := level.threshold - clock.Since(started); wait > 0 {
+			timer := clock.NewTimer(wait)
+			select {
+			case <-done:
+				timer.Stop()
+				return
+			case <-timer.C():
+			}
+		}
+		level.count.Add(1)
+		fields := []capitan.Field{
+			FieldName.Field(s.identity.Name()),
+			FieldIdentityID.Field(s.identity.ID().String()),
+			FieldThreshold.Field(level.threshold.Seconds()),
+			FieldElapsed.Field(clock.Since(started).Seconds()),
+		}
+		if level.severity == capitan.SeverityError {
+			capitan.Error(ctx, level.signal, fields...)
+		} else {
+			capitan.Warn(ctx, level.signal, fields...)
+		}
+	}
+}
+
+// SetThresholds updates the warn and critical thresholds.
+func (s *SLA[T]) SetThresholds(warn, critical time.Duration) *SLA[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.warn = warn
+	s.critical = critical
+	return s
+}
+
+// SetStartFunc reads the time each item's clock starts from the item, such
+// as an event timestamp. A zero time starts it on entry.
+func (s *SLA[T]) SetStartFunc(start func(T) time.Time) *SLA[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.start = start
+	return s
+}
+
+// SetProcessor updates the watched processor.
+func (s *SLA[T]) SetProcessor(processor Chainable[T]) *SLA[T] {
+	noteChildren(s.identity, processor)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processor = processor
+	return s
+}
+
+// Warnings returns the number of items that passed the warn threshold.
+func (s *SLA[T]) Warnings() int64 {
+	return s.warnings.Load()
+}
+
+// Breaches returns the number of items that passed the critical threshold.
+func (s *SLA[T]) Breaches() int64 {
+	return s.breaches.Load()
+}
+
+// WithClock sets a custom clock for testing.
+func (s *SLA[T]) WithClock(clock clockz.Clock) *SLA[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+	return s
+}
+
+// getClock returns the clock to use.
+func (s *SLA[T]) getClock() clockz.Clock {
+	if s.clock == nil {
+		return clockz.RealClock
+	}
+	return s.clock
+}
+
+// Identity returns the identity of this connector.
+func (s *SLA[T]) Identity() Identity {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (s *SLA[T]) Schema() Node {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return Node{
+		Identity: s.identity,
+		Type:     "sla",
+		Flow:     SLAFlow{Processor: s.processor.Schema()},
+		Metadata: map[string]any{
+			"warn":     s.warn.String(),
+			"critical": s.critical.String(),
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (s *SLA[T]) Close() error {
+	s.closeOnce.Do(func() {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		s.closeErr = s.processor.Close()
+	})
+	return s.closeErr
+}
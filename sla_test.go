@@ -0,0 +1,147 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+func TestSLA(t *testing.T) {
+	type order struct {
+		ReceivedAt time.Time
+		ID         string
+	}
+	ctx := context.Background()
+
+	// blocking returns a processor that waits for release.
+	blocking := func() (Chainable[order], chan struct{}, chan struct{}) {
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		return Apply(NewIdentity("fulfil", ""), func(_ context.Context, o order) (order, error) {
+			close(entered)
+			<-release
+			return o, nil
+		}), entered, release
+	}
+	waitFor := func(t *testing.T, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatal("condition not met")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	t.Run("Fast Item Raises Nothing", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		sla := NewSLA(NewIdentity("order-sla", ""), Transform(NewIdentity("fast", ""), func(_ context.Context, o order) order { return o }), time.Second, 5*time.Second).WithClock(clock)
+		if _, err := sla.Process(ctx, order{ID: "o1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sla.Warnings() != 0 || sla.Breaches() != 0 {
+			t.Errorf("warnings/breaches = %d/%d", sla.Warnings(), sla.Breaches())
+		}
+	})
+
+	t.Run("Escalates While Running", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		processor, entered, release := blocking()
+		sla := NewSLA(NewIdentity("order-sla", ""), processor, time.Second, 5*time.Second).WithClock(clock)
+
+		var critical capitan.Severity
+		gotCritical := make(chan struct{})
+		listener := capitan.Hook(SignalSLACritical, func(_ context.Context, e *capitan.Event) {
+			critical = e.Severity()
+			close(gotCritical)
+		})
+		defer listener.Close()
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := sla.Process(ctx, order{ID: "o1"})
+			done <- err
+		}()
+		<-entered
+
+		advanceWhenWaiting(t, clock, 1500*time.Millisecond)
+		waitFor(t, func() bool { return sla.Warnings() == 1 })
+		if sla.Breaches() != 0 {
+			t.Error("critical raised before its threshold")
+		}
+
+		advanceWhenWaiting(t, clock, 4*time.Second)
+		waitFor(t, func() bool { return sla.Breaches() == 1 })
+
+		close(release)
+		if err := <-done; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		select {
+		case <-gotCritical:
+		case <-time.After(time.Second):
+			t.Fatal("critical signal not received")
+		}
+		if critical != capitan.SeverityError {
+			t.Errorf("critical severity = %v", critical)
+		}
+	})
+
+	t.Run("Start Func Counts Upstream Time", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		sla := NewSLA(NewIdentity("order-sla", ""), Transform(NewIdentity("fast", ""), func(_ context.Context, o order) order { return o }), time.Second, 5*time.Second).
+			WithClock(clock).
+			SetStartFunc(func(o order) time.Time { return o.ReceivedAt })
+
+		if _, err := sla.Process(ctx, order{ReceivedAt: clock.Now().Add(-2 * time.Second)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sla.Warnings() != 1 || sla.Breaches() != 0 {
+			t.Errorf("warnings/breaches = %d/%d", sla.Warnings(), sla.Breaches())
+		}
+
+		if _, err := sla.Process(ctx, order{ReceivedAt: clock.Now().Add(-time.Minute)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sla.Warnings() != 2 || sla.Breaches() != 1 {
+			t.Errorf("warnings/breaches = %d/%d", sla.Warnings(), sla.Breaches())
+		}
+	})
+
+	t.Run("Zero Threshold Disables Level", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		sla := NewSLA(NewIdentity("order-sla", ""), Transform(NewIdentity("fast", ""), func(_ context.Context, o order) order { return o }), 0, 5*time.Second).
+			WithClock(clock).
+			SetStartFunc(func(o order) time.Time { return o.ReceivedAt })
+		_, _ = sla.Process(ctx, order{ReceivedAt: clock.Now().Add(-time.Minute)}) //nolint:errcheck // counters under test
+		if sla.Warnings() != 0 || sla.Breaches() != 1 {
+			t.Errorf("warnings/breaches = %d/%d", sla.Warnings(), sla.Breaches())
+		}
+	})
+
+	t.Run("Error Path", func(t *testing.T) {
+		failing := Apply(NewIdentity("fulfil", ""), func(_ context.Context, o order) (order, error) {
+			return o, errors.New("out of stock")
+		})
+		sla := NewSLA(NewIdentity("order-sla", ""), failing, time.Second, 5*time.Second)
+		_, err := sla.Process(ctx, order{})
+		var pipeErr *Error[order]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "order-sla" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		sla := NewSLA(NewIdentity("order-sla", ""), Transform(NewIdentity("fast", ""), func(_ context.Context, o order) order { return o }), time.Second, 5*time.Second)
+		node := sla.Schema()
+		flow, ok := SLAKey.From(node)
+		if !ok || flow.Processor.Identity.Name() != "fast" || node.Metadata["critical"] != "5s" {
+			t.Errorf("unexpected schema: %+v", node)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package pipz
+
+import "errors"
+
+// ErrInvalidSnapshot is returned by Restore when a snapshot is malformed.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// Stateful is implemented by connectors whose runtime state can be saved
+// and restored: CircuitBreaker (CircuitBreakerState) and RateLimiter
+// (RateLimiterState). Snapshots are plain JSON-encodable values, so they
+// can be persisted across restarts or shared through an external store.
+//
+// Persisting breaker state prevents a thundering herd when a fleet of
+// instances restarts: without it every instance comes back with a fresh,
+// closed breaker and hammers a dependency that is still down.
+//
+// Example:
+//
+//	// On shutdown.
+//	data, _ := json.Marshal(breaker.Snapshot())
+//	store.Put("breaker/payments", data)
+//
+//	// On startup.
+//	var state pipz.CircuitBreakerState
+//	if data, ok := store.Get("breaker/payments"); ok && json.Unmarshal(data, &state) == nil {
+//	    _ = breaker.Restore(state)
+//	}
+type Stateful[S any] interface {
+	Snapshot() S
+	Restore(state S) error
+}
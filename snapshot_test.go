@@ -0,0 +1,136 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+var (
+	_ Stateful[CircuitBreakerState] = (*CircuitBreaker[int])(nil)
+	_ Stateful[RateLimiterState]    = (*RateLimiter[int])(nil)
+)
+
+func TestCircuitBreakerSnapshot(t *testing.T) {
+	failing := Apply(NewIdentity("failing", ""), func(_ context.Context, v int) (int, error) {
+		return v, errors.New("down")
+	})
+
+	t.Run("Restored Breaker Stays Open", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		original := NewCircuitBreaker(NewIdentity("breaker", ""), failing, 2, time.Minute).WithClock(clock)
+		for i := 0; i < 2; i++ {
+			_, _ = original.Process(context.Background(), i) //nolint:errcheck // opening the breaker
+		}
+		if original.GetState() != stateOpen {
+			t.Fatalf("expected open breaker, got %s", original.GetState())
+		}
+
+		data, marshalErr := json.Marshal(original.Snapshot())
+		if marshalErr != nil {
+			t.Fatalf("marshal failed: %v", marshalErr)
+		}
+		var state CircuitBreakerState
+		if err := json.Unmarshal(data, &state); err != nil {
+			t.Fatalf("unmarshal failed: %v", err)
+		}
+
+		restarted := NewCircuitBreaker(NewIdentity("breaker", ""), failing, 2, time.Minute).WithClock(clock)
+		if err := restarted.Restore(state); err != nil {
+			t.Fatalf("restore failed: %v", err)
+		}
+		if restarted.GetState() != stateOpen {
+			t.Errorf("expected restored breaker open, got %s", restarted.GetState())
+		}
+		var pipeErr *Error[int]
+		if _, err := restarted.Process(context.Background(), 1); !errors.As(err, &pipeErr) || pipeErr.Path[len(pipeErr.Path)-1].Name() != "breaker" {
+			t.Errorf("expected fast failure from restored breaker, got %v", err)
+		}
+
+		clock.Advance(2 * time.Minute)
+		_, _ = restarted.Process(context.Background(), 1) //nolint:errcheck // probing half-open
+		if restarted.Snapshot().Generation <= state.Generation {
+			t.Error("expected restored breaker to recover after the reset timeout")
+		}
+	})
+
+	t.Run("Rejects Invalid State", func(t *testing.T) {
+		cb := NewCircuitBreaker(NewIdentity("breaker", ""), failing, 2, time.Minute)
+		if err := cb.Restore(CircuitBreakerState{State: "ajar"}); !errors.Is(err, ErrInvalidSnapshot) {
+			t.Errorf("expected ErrInvalidSnapshot, got %v", err)
+		}
+		if err := cb.Restore(CircuitBreakerState{State: stateClosed, Failures: -1}); !errors.Is(err, ErrInvalidSnapshot) {
+			t.Errorf("expected ErrInvalidSnapshot, got %v", err)
+		}
+		if cb.GetState() != stateClosed {
+			t.Errorf("expected breaker unchanged, got %s", cb.GetState())
+		}
+	})
+}
+
+func TestRateLimiterSnapshot(t *testing.T) {
+	pass := Transform(NewIdentity("pass", ""), func(_ context.Context, v int) int { return v })
+
+	t.Run("Restores Token Bucket", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		original := NewRateLimiter(NewIdentity("limiter", ""), 1, 5, pass).WithClock(clock)
+		original.SetMode(RateLimitDrop)
+		for i := 0; i < 5; i++ {
+			_, _ = original.Process(context.Background(), i) //nolint:errcheck // draining the bucket
+		}
+		state := original.Snapshot()
+		if state.Tokens >= 1 {
+			t.Fatalf("expected drained bucket, got %v tokens", state.Tokens)
+		}
+
+		restarted := NewRateLimiter(NewIdentity("limiter", ""), 1, 5, pass).WithClock(clock)
+		restarted.SetMode(RateLimitDrop)
+		if err := restarted.Restore(state); err != nil {
+			t.Fatalf("restore failed: %v", err)
+		}
+		if _, err := restarted.Process(context.Background(), 1); err == nil {
+			t.Error("expected restored limiter to drop with an empty bucket")
+		}
+
+		clock.Advance(2 * time.Second)
+		if tokens := restarted.GetAvailableTokens(); tokens < 1.9 || tokens > 2.1 {
+			t.Errorf("expected refill from restored time, got %v tokens", tokens)
+		}
+	})
+
+	t.Run("Caps Tokens At Burst", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		r := NewRateLimiter(NewIdentity("limiter", ""), 0, 3, pass).WithClock(clock)
+		if err := r.Restore(RateLimiterState{Tokens: 100, LastRefill: clock.Now()}); err != nil {
+			t.Fatalf("restore failed: %v", err)
+		}
+		if tokens := r.GetAvailableTokens(); tokens != 3 {
+			t.Errorf("expected tokens capped at burst, got %v", tokens)
+		}
+	})
+
+	t.Run("Future Refill Time", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		r := NewRateLimiter(NewIdentity("limiter", ""), 1, 3, pass).WithClock(clock)
+		if err := r.Restore(RateLimiterState{Tokens: 2, LastRefill: clock.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("restore failed: %v", err)
+		}
+		if tokens := r.GetAvailableTokens(); tokens != 2 {
+			t.Errorf("expected clock skew ignored, got %v tokens", tokens)
+		}
+	})
+
+	t.Run("Rejects Invalid Tokens", func(t *testing.T) {
+		r := NewRateLimiter(NewIdentity("limiter", ""), 1, 3, pass)
+		for _, tokens := range []float64{-1, math.NaN()} {
+			if err := r.Restore(RateLimiterState{Tokens: tokens}); !errors.Is(err, ErrInvalidSnapshot) {
+				t.Errorf("expected ErrInvalidSnapshot for %v, got %v", tokens, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,234 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Stage processes an input of one type into an output of another.
+// It generalizes Chainable to typed transitions such as parsing raw bytes
+// into an Order and an Order into a Receipt, so each step can work on the
+// type it naturally handles instead of threading one large struct through
+// the entire flow.
+//
+// Stage has the same method set as Chainable, so every Chainable[T] is a
+// Stage[T, T] and every Stage[T, T] is a Chainable[T]. Existing processors
+// and connectors plug into Compose directly, and a composed Stage[T, T]
+// can be used anywhere a Chainable[T] is expected.
+//
+// Errors from a Stage are *Error[In], carrying the stage's original input.
+type Stage[In, Out any] interface {
+	Process(context.Context, In) (Out, error)
+	Identity() Identity
+	Schema() Node
+	Close() error
+}
+
+// Converter is a leaf Stage built from a function that changes type.
+// Create one with Convert.
+type Converter[In, Out any] struct {
+	fn       func(context.Context, In) (Out, error)
+	identity Identity
+}
+
+// Convert creates a Stage from a function that maps In to Out and may fail.
+// It is the typed counterpart of Apply.
+//
+// Example:
+//
+//	var ParseOrderID = pipz.NewIdentity("parse-order", "Decodes an order from JSON")
+//	parse := pipz.Convert(ParseOrderID, func(_ context.Context, raw []byte) (Order, error) {
+//	    var o Order
+//	    err := json.Unmarshal(raw, &o)
+//	    return o, err
+//	})
+func Convert[In, Out any](identity Identity, fn func(context.Context, In) (Out, error)) Converter[In, Out] {
+	return Converter[In, Out]{identity: identity, fn: fn}
+}
+
+// Process implements the Stage interface.
+func (c Converter[In, Out]) Process(ctx context.Context, value In) (result Out, err error) {
+	defer recoverStagePanic(&result, &err, c.identity, value)
+	start := time.Now()
+	result, err = calibrated(ctx, c.identity, c.fn, value)
+	if err != nil {
+		var zero Out
+		return zero, &Error[In]{
+			Path:      []Identity{c.identity},
+			InputData: errorInput(value),
+			Err:       err,
+			Timestamp: time.Now(),
+			Duration:  time.Since(start),
+			Timeout:   errors.Is(err, context.DeadlineExceeded),
+			Canceled:  errors.Is(err, context.Canceled),
+		}
+	}
+	return result, nil
+}
+
+// Identity returns the identity of this stage.
+func (c Converter[In, Out]) Identity() Identity {
+	return c.identity
+}
+
+// Schema returns a Node representing this stage in the pipeline schema.
+func (c Converter[In, Out]) Schema() Node {
+	return Node{
+		Identity: c.identity,
+		Type:     "convert",
+	}
+}
+
+// Close gracefully shuts down any resources.
+func (Converter[In, Out]) Close() error {
+	return nil
+}
+
+// recoverStagePanic is recoverFromPanic for stages whose input and output
+// types differ.
+func recoverStagePanic[In, Out any](result *Out, err *error, identity Identity, inputData In) {
+	if r := recover(); r != nil {
+		var zero Out
+		*result = zero
+		*err = &Error[In]{
+			Path:      []Identity{identity},
+			InputData: errorInput(inputData),
+			Err:       newPanicError(identity, r),
+			Timestamp: time.Now(),
+		}
+	}
+}
+
+// Composed runs two stages in order, feeding the first stage's output to
+// the second. Create one with Compose.
+type Composed[A, B, C any] struct {
+	first     Stage[A, B]
+	second    Stage[B, C]
+	identity  Identity
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Compose chains two stages into one: A → B → C. Compose calls nest, so
+// longer flows are built by composing composed stages, and any Chainable
+// can serve as a same-type step along the way.
+//
+// Errors from either stage are returned as *Error[A] carrying the original
+// input, with the path prefixed by the composed stage's identity, so callers
+// handle one error type regardless of which stage failed.
+//
+// Go cannot infer type parameters through interface arguments, so the
+// stage types are spelled out at the call site.
+//
+// Example:
+//
+//	var (
+//	    CheckoutID = pipz.NewIdentity("checkout", "Raw request to receipt")
+//	    OrderFlowID = pipz.NewIdentity("order-flow", "Parse then validate")
+//	)
+//	orderFlow := pipz.Compose[[]byte, Order, Order](OrderFlowID, parse, validateOrder)
+//	checkout := pipz.Compose[[]byte, Order, Receipt](CheckoutID, orderFlow, charge)
+//	receipt, err := checkout.Process(ctx, body)
+func Compose[A, B, C any](identity Identity, first Stage[A, B], second Stage[B, C]) *Composed[A, B, C] {
+	return &Composed[A, B, C]{
+		identity: identity,
+		first:    first,
+		second:   second,
+	}
+}
+
+// Process implements the Stage interface.
+func (c *Composed[A, B, C]) Process(ctx context.Context, value A) (result C, err error) {
+	defer recoverStagePanic(&result, &err, c.identity, value)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	mid, err := c.first.Process(ctx, value)
+	if err != nil {
+		var zero C
+		var pipeErr *Error[A]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, c.identity)
+			return zero, pipeErr
+		}
+		return zero, &Error[A]{
+			Timestamp: time.Now(),
+			InputData: errorInput(value),
+			Err:       err,
+			Path:      []Identity{c.identity},
+		}
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		var zero C
+		return zero, &Error[A]{
+			Timestamp: time.Now(),
+			InputData: errorInput(value),
+			Err:       ctxErr,
+			Path:      []Identity{c.identity},
+			Timeout:   errors.Is(ctxErr, context.DeadlineExceeded),
+			Canceled:  errors.Is(ctxErr, context.Canceled),
+		}
+	}
+
+	result, err = c.second.Process(ctx, mid)
+	if err != nil {
+		var zero C
+		// Re-anchor the second stage's error on the original input so
+		// callers see a single error type for the whole composition.
+		var stageErr *Error[B]
+		if errors.As(err, &stageErr) {
+			return zero, &Error[A]{
+				Timestamp: stageErr.Timestamp,
+				InputData: errorInput(value),
+				Err:       stageErr.Err,
+				Path:      append([]Identity{c.identity}, stageErr.Path...),
+				Duration:  stageErr.Duration,
+				Timeout:   stageErr.Timeout,
+				Canceled:  stageErr.Canceled,
+			}
+		}
+		var pipeErr *Error[A]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, c.identity)
+			return zero, pipeErr
+		}
+		return zero, &Error[A]{
+			Timestamp: time.Now(),
+			InputData: errorInput(value),
+			Err:       err,
+			Path:      []Identity{c.identity},
+		}
+	}
+	return result, nil
+}
+
+// Identity returns the identity of this stage.
+func (c *Composed[A, B, C]) Identity() Identity {
+	return c.identity
+}
+
+// Schema returns a Node representing this stage in the pipeline schema.
+func (c *Composed[A, B, C]) Schema() Node {
+	return Node{
+		Identity: c.identity,
+		Type:     "compose",
+		Flow: ComposeFlow{
+			First:  c.first.Schema(),
+			Second: c.second.Schema(),
+		},
+	}
+}
+
+// Close gracefully shuts down both stages, second first.
+// Close is idempotent - multiple calls return the same result.
+func (c *Composed[A, B, C]) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = errors.Join(c.second.Close(), c.first.Close())
+	})
+	return c.closeErr
+}
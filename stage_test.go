@@ -0,0 +1,161 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type stageOrder struct {
+	ID    string
+	Total int
+}
+
+func TestConvert(t *testing.T) {
+	parse := Convert(NewIdentity("parse", ""), func(_ context.Context, raw string) (int, error) {
+		return strconv.Atoi(raw)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		n, err := parse.Process(context.Background(), "42")
+		if err != nil || n != 42 {
+			t.Errorf("expected 42, got %d (%v)", n, err)
+		}
+	})
+
+	t.Run("Error Carries Input", func(t *testing.T) {
+		_, err := parse.Process(context.Background(), "nope")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected *Error[string], got %T", err)
+		}
+		if pipeErr.InputData != "nope" || pipeErr.Path[0].Name() != "parse" {
+			t.Errorf("unexpected error: %+v", pipeErr)
+		}
+	})
+
+	t.Run("Panic Recovery", func(t *testing.T) {
+		boom := Convert(NewIdentity("boom", ""), func(_ context.Context, _ string) (int, error) {
+			panic("exploded")
+		})
+		_, err := boom.Process(context.Background(), "x")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected *Error[string], got %v", err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		if schema := parse.Schema(); schema.Type != "convert" || schema.Identity.Name() != "parse" {
+			t.Errorf("unexpected schema: %+v", schema)
+		}
+	})
+}
+
+func TestCompose(t *testing.T) {
+	parse := Convert(NewIdentity("parse", ""), func(_ context.Context, raw string) (stageOrder, error) {
+		total, err := strconv.Atoi(raw)
+		if err != nil {
+			return stageOrder{}, err
+		}
+		return stageOrder{ID: "o-" + raw, Total: total}, nil
+	})
+	validate := Apply(NewIdentity("validate", ""), func(_ context.Context, o stageOrder) (stageOrder, error) {
+		if o.Total <= 0 {
+			return o, errors.New("total must be positive")
+		}
+		return o, nil
+	})
+	receipt := Convert(NewIdentity("receipt", ""), func(_ context.Context, o stageOrder) (string, error) {
+		return o.ID + ":" + strconv.Itoa(o.Total), nil
+	})
+
+	orderFlow := Compose[string, stageOrder, stageOrder](NewIdentity("order-flow", ""), parse, validate)
+	checkout := Compose[string, stageOrder, string](NewIdentity("checkout", ""), orderFlow, receipt)
+
+	t.Run("Success", func(t *testing.T) {
+		out, err := checkout.Process(context.Background(), "15")
+		if err != nil || out != "o-15:15" {
+			t.Errorf("expected o-15:15, got %q (%v)", out, err)
+		}
+	})
+
+	t.Run("First Stage Error", func(t *testing.T) {
+		_, err := checkout.Process(context.Background(), "abc")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected *Error[string], got %T", err)
+		}
+		names := pathNames(pipeErr.Path)
+		if names != "checkout/order-flow/parse" {
+			t.Errorf("unexpected path: %s", names)
+		}
+	})
+
+	t.Run("Second Stage Error Is Re-Anchored", func(t *testing.T) {
+		_, err := checkout.Process(context.Background(), "-3")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) {
+			t.Fatalf("expected *Error[string], got %T", err)
+		}
+		if pipeErr.InputData != "-3" {
+			t.Errorf("expected original input, got %q", pipeErr.InputData)
+		}
+		if names := pathNames(pipeErr.Path); names != "checkout/order-flow/validate" {
+			t.Errorf("unexpected path: %s", names)
+		}
+	})
+
+	t.Run("Canceled Between Stages", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelling := Convert(NewIdentity("cancel", ""), func(_ context.Context, raw string) (stageOrder, error) {
+			cancel()
+			return stageOrder{ID: raw, Total: 1}, nil
+		})
+		flow := Compose[string, stageOrder, string](NewIdentity("flow", ""), cancelling, receipt)
+		_, err := flow.Process(ctx, "x")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) || !pipeErr.Canceled {
+			t.Errorf("expected canceled error, got %v", err)
+		}
+	})
+
+	t.Run("Same Type Composition Is Chainable", func(t *testing.T) {
+		double := Transform(NewIdentity("double", ""), func(_ context.Context, v int) int { return v * 2 })
+		var chain Chainable[int] = Compose[int, int, int](NewIdentity("quad", ""), double, double)
+		seq := NewSequence(NewIdentity("seq", ""), chain)
+		out, err := seq.Process(context.Background(), 3)
+		if err != nil || out != 12 {
+			t.Errorf("expected 12, got %d (%v)", out, err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		schema := checkout.Schema()
+		flow, ok := ComposeKey.From(schema)
+		if !ok || flow.Second.Identity.Name() != "receipt" || flow.First.Type != "compose" {
+			t.Errorf("unexpected schema: %+v", schema)
+		}
+		if count := NewSchema(schema).Count(); count != 5 {
+			t.Errorf("expected 5 nodes, got %d", count)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		if err := checkout.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	})
+}
+
+func pathNames(path []Identity) string {
+	names := ""
+	for i, id := range path {
+		if i > 0 {
+			names += "/"
+		}
+		names += id.Name()
+	}
+	return names
+}
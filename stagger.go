@@ -0,0 +1,231 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// InstanceID returns the identifier Stagger spreads replicas by: the
+// hostname, which is unique per pod or machine in most deployments, or the
+// process ID if the hostname is unavailable.
+var InstanceID = sync.OnceValue(func() string {
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return "pid-" + strconv.Itoa(os.Getpid())
+})
+
+// SpreadOffset returns a deterministic offset in [0, spread) for instance
+// running the job named key. Each instance gets the same offset for a job
+// on every run and across restarts, while different instances, and
+// different jobs on one instance, are spread evenly across the interval.
+// It returns zero for a spread of zero or less.
+//
+// Use it to offset schedules outside a pipeline, such as the first tick of
+// a ticker or a cron entry's delay.
+func SpreadOffset(instance, key string, spread time.Duration) time.Duration {
+	if spread <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(instance)) //nolint:errcheck // hash writes never fail
+	_, _ = h.Write([]byte{0})        //nolint:errcheck // hash writes never fail
+	_, _ = h.Write([]byte(key))      //nolint:errcheck // hash writes never fail
+	return time.Duration(h.Sum64() % uint64(spread))
+}
+
+// Stagger delays each run of a processor by a fixed per-instance offset, so
+// replicas triggered by the same schedule do not all hit shared
+// dependencies at the same instant. The offset is derived from the
+// instance ID and the connector's name (see SpreadOffset): it is the same
+// on every run of one replica, and spread evenly over [0, spread) across
+// replicas.
+//
+// Put Stagger first in pipelines run on a schedule by every replica, such
+// as cache warmers and periodic syncs. Keep the spread well under the
+// schedule's period so runs do not overlap. Work that should run on one
+// replica only belongs in LeaderOnly instead.
+//
+// Waiting honors the context: a run whose context ends during its offset
+// fails with the context's error without running the processor.
+//
+// Example:
+//
+//	var WarmCacheID = pipz.NewIdentity("warm-cache", "Refreshes the product cache every 5 minutes")
+//	warm := pipz.NewStagger(WarmCacheID, time.Minute, refreshProducts)
+//	// Replicas run at the 5-minute mark plus their own offset within a minute
+type Stagger[T any] struct {
+	processor Chainable[T]
+	clock     clockz.Clock
+	identity  Identity
+	instance  string
+	spread    time.Duration
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewStagger creates a Stagger delaying processor by this instance's offset
+// within spread, using InstanceID.
+func NewStagger[T any](identity Identity, spread time.Duration, processor Chainable[T]) *Stagger[T] {
+	return &Stagger[T]{
+		identity:  identity,
+		instance:  InstanceID(),
+		spread:    spread,
+		processor: processor,
+	}
+}
+
+// Process implements the Chainable interface.
+// Waits for this instance's offset, then runs the processor.
+func (s *Stagger[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, s.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, s, s.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	s.mu.RLock()
+	processor := s.processor
+	clock := s.getClock()
+	instance := s.instance
+	offset := SpreadOffset(instance, s.identity.Name(), s.spread)
+	s.mu.RUnlock()
+
+	if offset > 0 {
+		capitan.Debug(ctx, SignalStaggerDelayed,
+			FieldName.Field(s.identity.Name()),
+			FieldIdentityID.Field(s.identity.ID().String()),
+			FieldInstance.Field(instance),
+			FieldWaitTime.Field(offset.Seconds()),
+		)
+		start := clock.Now()
+		select {
+		case <-clock.After(offset):
+		case <-ctx.Done():
+			return data, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(data),
+				Err:       ctx.Err(),
+				Path:      []Identity{s.identity},
+				Duration:  clock.Since(start),
+				Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
+				Canceled:  errors.Is(ctx.Err(), context.Canceled),
+			}
+		}
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, s.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{s.identity},
+		}
+	}
+	return result, nil
+}
+
+// Offset returns the delay this instance applies before each run.
+func (s *Stagger[T]) Offset() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return SpreadOffset(s.instance, s.identity.Name(), s.spread)
+}
+
+// SetSpread updates the interval offsets are spread over. Zero or negative
+// disables the delay.
+func (s *Stagger[T]) SetSpread(spread time.Duration) *Stagger[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spread = spread
+	return s
+}
+
+// SetInstanceID sets the identifier the offset is derived from, for
+// deployments where the hostname is shared between replicas or changes on
+// every restart; a stable replica ordinal or pod name works well.
+func (s *Stagger[T]) SetInstanceID(instance string) *Stagger[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instance = instance
+	return s
+}
+
+// SetProcessor updates the delayed processor.
+func (s *Stagger[T]) SetProcessor(processor Chainable[T]) *Stagger[T] {
+	noteChildren(s.identity, processor)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processor = processor
+	return s
+}
+
+// GetSpread returns the interval offsets are spread over.
+func (s *Stagger[T]) GetSpread() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.spread
+}
+
+// WithClock sets a custom clock for testing.
+func (s *Stagger[T]) WithClock(clock clockz.Clock) *Stagger[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+	return s
+}
+
+// getClock returns the clock to use.
+func (s *Stagger[T]) getClock() clockz.Clock {
+	if s.clock == nil {
+		return clockz.RealClock
+	}
+	return s.clock
+}
+
+// Identity returns the identity of this connector.
+func (s *Stagger[T]) Identity() Identity {
+	return s.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (s *Stagger[T]) Schema() Node {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return Node{
+		Identity: s.identity,
+		Type:     "stagger",
+		Flow:     StaggerFlow{Processor: s.processor.Schema()},
+		Metadata: map[string]any{
+			"spread": s.spread.String(),
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processor.
+// Close is idempotent - multiple calls return the same result.
+func (s *Stagger[T]) Close() error {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closeErr = s.processor.Close()
+	})
+	return s.closeErr
+}
@@ -0,0 +1,141 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+func TestSpreadOffset(t *testing.T) {
+	t.Run("Deterministic Per Instance And Key", func(t *testing.T) {
+		a := SpreadOffset("web-0", "warm-cache", time.Minute)
+		if a != SpreadOffset("web-0", "warm-cache", time.Minute) {
+			t.Error("expected the same offset on every call")
+		}
+		if a < 0 || a >= time.Minute {
+			t.Errorf("expected an offset within the spread, got %v", a)
+		}
+		if SpreadOffset("web-0", "sync-prices", time.Minute) == a {
+			t.Error("expected another job on the same instance to get its own offset")
+		}
+	})
+
+	t.Run("Spreads Replicas", func(t *testing.T) {
+		const replicas = 100
+		spread := 10 * time.Second
+		var buckets [10]int
+		for i := range replicas {
+			offset := SpreadOffset(fmt.Sprintf("web-%d", i), "warm-cache", spread)
+			buckets[offset/time.Second]++
+		}
+		for i, n := range buckets {
+			if n == 0 || n > replicas/4 {
+				t.Errorf("expected replicas spread across the interval, second %d has %d", i, n)
+			}
+		}
+	})
+
+	t.Run("No Spread", func(t *testing.T) {
+		if offset := SpreadOffset("web-0", "warm-cache", 0); offset != 0 {
+			t.Errorf("expected no offset, got %v", offset)
+		}
+	})
+}
+
+func TestStagger(t *testing.T) {
+	staggerID := testIdentity("warm-cache")
+	double := Transform(testIdentity("double"), func(_ context.Context, n int) int { return n * 2 })
+
+	t.Run("Waits For Instance Offset", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		stagger := NewStagger(staggerID, time.Minute, double).SetInstanceID("web-3").WithClock(clock)
+		offset := stagger.Offset()
+		if offset != SpreadOffset("web-3", "warm-cache", time.Minute) || offset == 0 {
+			t.Fatalf("unexpected offset %v", offset)
+		}
+
+		done := make(chan int, 1)
+		go func() {
+			out, err := stagger.Process(context.Background(), 4)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			done <- out
+		}()
+		advanceWhenWaiting(t, clock, offset-time.Millisecond)
+		select {
+		case <-done:
+			t.Fatal("expected the run to wait for the full offset")
+		case <-time.After(10 * time.Millisecond):
+		}
+		clock.Advance(time.Millisecond)
+		clock.BlockUntilReady()
+		select {
+		case out := <-done:
+			if out != 8 {
+				t.Errorf("expected 8, got %d", out)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the run after the offset")
+		}
+	})
+
+	t.Run("Zero Spread Runs Immediately", func(t *testing.T) {
+		stagger := NewStagger(staggerID, 0, double)
+		if out, err := stagger.Process(context.Background(), 4); err != nil || out != 8 {
+			t.Errorf("expected 8, got %d, %v", out, err)
+		}
+	})
+
+	t.Run("Context Ends During Offset", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		ran := false
+		mark := Effect(testIdentity("mark"), func(context.Context, int) error {
+			ran = true
+			return nil
+		})
+		stagger := NewStagger(staggerID, time.Minute, mark).SetInstanceID("web-3").WithClock(clock)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := stagger.Process(ctx, 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.Canceled || ran {
+			t.Errorf("expected a canceled error without running, got %v", err)
+		}
+	})
+
+	t.Run("Error Path", func(t *testing.T) {
+		fail := Apply(testIdentity("fail"), func(_ context.Context, n int) (int, error) {
+			return n, errors.New("boom")
+		})
+		_, err := NewStagger(staggerID, 0, fail).Process(context.Background(), 1)
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "warm-cache" {
+			t.Errorf("expected path through the stagger, got %v", err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := NewStagger(staggerID, time.Minute, double).Schema()
+		flow, ok := StaggerKey.From(node)
+		if node.Type != "stagger" || !ok || flow.Processor.Identity.Name() != "double" {
+			t.Errorf("unexpected schema %+v", node)
+		}
+		if node.Metadata["spread"] != "1m0s" {
+			t.Errorf("unexpected metadata %v", node.Metadata)
+		}
+	})
+
+	t.Run("Default Instance ID", func(t *testing.T) {
+		if InstanceID() == "" {
+			t.Error("expected a non-empty instance ID")
+		}
+		if got := NewStagger(staggerID, time.Minute, double).Offset(); got != SpreadOffset(InstanceID(), "warm-cache", time.Minute) {
+			t.Errorf("expected the offset of InstanceID, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package pipz
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+)
+
+// Stash holds the input of a Backoff or Retry while it waits between
+// attempts, so large payloads need not stay in memory through long backoff
+// schedules with many items in flight. Stashing is an optimization: if Stash
+// fails, the input simply stays in memory.
+//
+// Memory is only reclaimed if nothing else holds the input, so callers
+// should not keep their own reference to a payload while it is processed.
+type Stash[T any] interface {
+	// Stash stores data and returns a function that restores it, which is
+	// called exactly once. A nil restore function with a nil error leaves
+	// data in memory, such as when it is too small to be worth stashing.
+	Stash(data T) (restore func() (T, error), err error)
+}
+
+// StashFunc adapts a function to the Stash interface, such as one encoding
+// structured data to an external store.
+type StashFunc[T any] func(data T) (func() (T, error), error)
+
+// Stash implements the Stash interface.
+func (f StashFunc[T]) Stash(data T) (func() (T, error), error) {
+	return f(data)
+}
+
+// NewCompressStash creates a Stash that gzips payloads of at least minSize
+// bytes while they wait. It suits compressible payloads such as JSON,
+// trading CPU for memory.
+//
+// Example:
+//
+//	var UploadBackoffID = pipz.NewIdentity("upload-backoff", "Retries uploads with backoff")
+//	backoff := pipz.NewBackoff(UploadBackoffID, upload, 8, time.Second).
+//	    SetStash(pipz.NewCompressStash[[]byte](64 << 10))
+func NewCompressStash[T ~[]byte](minSize int) Stash[T] {
+	return StashFunc[T](func(data T) (func() (T, error), error) {
+		if len(data) < minSize {
+			return nil, nil
+		}
+		var buf bytes.Buffer
+		zw, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+
+		size := len(data)
+		return func() (T, error) {
+			zr, err := gzip.NewReader(&buf)
+			if err != nil {
+				return nil, err
+			}
+			restored := bytes.NewBuffer(make([]byte, 0, size))
+			if _, err := io.Copy(restored, zr); err != nil {
+				return nil, err
+			}
+			return T(restored.Bytes()), nil
+		}, nil
+	})
+}
+
+// NewSpillStash creates a Stash that writes payloads of at least minSize
+// bytes to temporary files in dir while they wait, removing each file once
+// its payload is restored. An empty dir uses os.TempDir.
+func NewSpillStash[T ~[]byte](dir string, minSize int) Stash[T] {
+	return StashFunc[T](func(data T) (func() (T, error), error) {
+		if len(data) < minSize {
+			return nil, nil
+		}
+		f, err := os.CreateTemp(dir, "pipz-stash-*")
+		if err != nil {
+			return nil, err
+		}
+		name := f.Name()
+		_, err = f.Write(data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, errors.Join(err, os.Remove(name))
+		}
+
+		return func() (T, error) {
+			restored, err := os.ReadFile(name)
+			if removeErr := os.Remove(name); err == nil {
+				err = removeErr
+			}
+			if err != nil {
+				return nil, err
+			}
+			return T(restored), nil
+		}, nil
+	})
+}
+
+// stashInput stashes *data with stash, zeroing it so the caller no longer
+// holds the payload, and returns the function restoring it. It returns nil
+// when the input stays in memory: there is no stash, the stash declined,
+// or stashing failed.
+func stashInput[T any](stash Stash[T], data *T) func() (T, error) {
+	if stash == nil {
+		return nil
+	}
+	restore, err := stash.Stash(*data)
+	if err != nil || restore == nil {
+		return nil
+	}
+	var zero T
+	*data = zero
+	return restore
+}
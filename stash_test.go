@@ -0,0 +1,180 @@
+package pipz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompressStash(t *testing.T) {
+	t.Run("Round Trip", func(t *testing.T) {
+		payload := bytes.Repeat([]byte("pipz payload "), 1000)
+		restore, err := NewCompressStash[[]byte](1024).Stash(payload)
+		if err != nil || restore == nil {
+			t.Fatalf("expected payload to be stashed, got %v", err)
+		}
+		restored, err := restore()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(restored, payload) {
+			t.Error("restored payload differs from original")
+		}
+	})
+
+	t.Run("Small Payloads Stay In Memory", func(t *testing.T) {
+		restore, err := NewCompressStash[[]byte](1024).Stash([]byte("small"))
+		if err != nil || restore != nil {
+			t.Errorf("expected small payload to be declined, got restore=%v err=%v", restore != nil, err)
+		}
+	})
+}
+
+func TestSpillStash(t *testing.T) {
+	t.Run("Round Trip Removes File", func(t *testing.T) {
+		dir := t.TempDir()
+		payload := bytes.Repeat([]byte{7}, 4096)
+		restore, err := NewSpillStash[[]byte](dir, 1).Stash(payload)
+		if err != nil || restore == nil {
+			t.Fatalf("expected payload to be stashed, got %v", err)
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 1 {
+			t.Fatalf("expected 1 spill file, got %d", len(files))
+		}
+
+		restored, err := restore()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(restored, payload) {
+			t.Error("restored payload differs from original")
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Errorf("expected spill file to be removed, got %d files", len(files))
+		}
+	})
+
+	t.Run("Missing Dir Fails", func(t *testing.T) {
+		_, err := NewSpillStash[[]byte]("/nonexistent/pipz", 1).Stash([]byte("data"))
+		if err == nil {
+			t.Error("expected error for missing directory")
+		}
+	})
+}
+
+func TestBackoffStash(t *testing.T) {
+	t.Run("Input Restored For Each Attempt", func(t *testing.T) {
+		payload := bytes.Repeat([]byte("x"), 2048)
+		var stashed, seen int
+		stash := StashFunc[[]byte](func(data []byte) (func() ([]byte, error), error) {
+			stashed++
+			held := bytes.Clone(data)
+			return func() ([]byte, error) { return held, nil }, nil
+		})
+		flaky := Apply(NewIdentity("flaky", ""), func(_ context.Context, data []byte) ([]byte, error) {
+			if !bytes.Equal(data, payload) {
+				t.Errorf("attempt saw %d bytes, expected original payload", len(data))
+			}
+			seen++
+			if seen < 3 {
+				return data, errors.New("transient")
+			}
+			return data, nil
+		})
+
+		backoff := NewBackoff(NewIdentity("backoff", ""), flaky, 3, time.Millisecond).SetStash(stash)
+		result, err := backoff.Process(context.Background(), payload)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(result, payload) || stashed != 2 {
+			t.Errorf("expected payload back after 2 stashes, got %d bytes, %d stashes", len(result), stashed)
+		}
+	})
+
+	t.Run("Stash Failure Keeps Input In Memory", func(t *testing.T) {
+		stash := StashFunc[string](func(string) (func() (string, error), error) {
+			return nil, errors.New("store unavailable")
+		})
+		attempts := 0
+		flaky := Apply(NewIdentity("flaky", ""), func(_ context.Context, s string) (string, error) {
+			attempts++
+			if attempts == 1 {
+				return s, errors.New("transient")
+			}
+			return s + "!", nil
+		})
+
+		backoff := NewBackoff(NewIdentity("backoff", ""), flaky, 2, time.Millisecond).SetStash(stash)
+		result, err := backoff.Process(context.Background(), "data")
+		if err != nil || result != "data!" {
+			t.Errorf("expected data!, got %q (%v)", result, err)
+		}
+	})
+
+	t.Run("Restore Failure Fails Item", func(t *testing.T) {
+		stash := StashFunc[string](func(string) (func() (string, error), error) {
+			return func() (string, error) { return "", errors.New("spill file lost") }, nil
+		})
+		failing := Apply(NewIdentity("failing", ""), func(_ context.Context, s string) (string, error) {
+			return s, errors.New("transient")
+		})
+
+		backoff := NewBackoff(NewIdentity("backoff", ""), failing, 3, time.Millisecond).SetStash(stash)
+		_, err := backoff.Process(context.Background(), "data")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) || !strings.Contains(err.Error(), "restoring stashed input: spill file lost") {
+			t.Fatalf("expected restore error, got %v", err)
+		}
+		if pipeErr.Path[0].Name() != "backoff" {
+			t.Errorf("expected error at backoff, got path %v", pipeErr.Path)
+		}
+	})
+
+	t.Run("Cancellation Restores Input For Error", func(t *testing.T) {
+		dir := t.TempDir()
+		failing := Apply(NewIdentity("failing", ""), func(_ context.Context, data []byte) ([]byte, error) {
+			return data, errors.New("transient")
+		})
+		backoff := NewBackoff(NewIdentity("backoff", ""), failing, 3, time.Hour).
+			SetStash(NewSpillStash[[]byte](dir, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		result, err := backoff.Process(ctx, []byte("payload"))
+		if err == nil || string(result) != "payload" {
+			t.Errorf("expected cancellation with payload, got %q (%v)", result, err)
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Errorf("expected spill file to be removed, got %d files", len(files))
+		}
+	})
+}
+
+func TestRetryStash(t *testing.T) {
+	t.Run("Stashes During Requested Delay", func(t *testing.T) {
+		dir := t.TempDir()
+		attempts := 0
+		throttled := Apply(NewIdentity("throttled", ""), func(_ context.Context, data []byte) ([]byte, error) {
+			attempts++
+			if attempts == 1 {
+				files, _ := os.ReadDir(dir)
+				if len(files) != 0 {
+					t.Errorf("expected no spill files during attempt, got %d", len(files))
+				}
+				return data, RetryAfter(errors.New("throttled"), time.Millisecond)
+			}
+			return append(data, '!'), nil
+		})
+
+		retry := NewRetry(NewIdentity("retry", ""), throttled, 2).SetStash(NewSpillStash[[]byte](dir, 1))
+		result, err := retry.Process(context.Background(), []byte("payload"))
+		if err != nil || string(result) != "payload!" {
+			t.Errorf("expected payload!, got %q (%v)", result, err)
+		}
+	})
+}
@@ -0,0 +1,224 @@
+package pipz
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+)
+
+// Stat tracks a number extracted from each item, such as a provider's
+// reported latency or an order's value, passing items through unchanged.
+// It maintains an exponentially weighted moving average (EWMA) of every
+// sample and a rolling window of the most recent samples for quantiles,
+// so later stages can adapt to what the pipeline is seeing.
+//
+// Above and QuantileAbove turn the statistics into a Condition for a
+// Switch, such as routing to a fallback provider while latency is high.
+// Until SetMinSamples samples have been seen, they route below threshold,
+// so a single outlier after startup does not trip them.
+//
+// CRITICAL: Stat is STATEFUL. Create it once and reuse it.
+//
+// Example:
+//
+//	var ProviderLatencyID = pipz.NewIdentity("provider-latency", "Tracks charge latency")
+//	latency := pipz.NewStat(ProviderLatencyID, func(c Charge) float64 {
+//	    return c.Latency.Seconds()
+//	})
+//	route := pipz.NewSwitch(RouteChargeID, latency.Above(0.8, "fallback", "primary")).
+//	    AddRoute("primary", primaryProvider).
+//	    AddRoute("fallback", fallbackProvider)
+type Stat[T any] struct {
+	extract    func(T) float64
+	window     []float64
+	sorted     []float64
+	identity   Identity
+	alpha      float64
+	ewma       float64
+	count      int64
+	next       int
+	minSamples int64
+	mu         sync.RWMutex
+}
+
+// NewStat creates a Stat sampling the number extract returns for each
+// item, with an EWMA smoothing factor of 0.2 and quantiles over the last
+// 100 samples. NaN samples are ignored.
+func NewStat[T any](identity Identity, extract func(T) float64) *Stat[T] {
+	return &Stat[T]{
+		identity:   identity,
+		extract:    extract,
+		alpha:      0.2,
+		window:     make([]float64, 0, 100),
+		minSamples: 1,
+	}
+}
+
+// Process implements the Chainable interface, recording a sample and
+// returning data unchanged.
+func (s *Stat[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, s.identity, data)
+
+	if _, guardErr := enterDepth(ctx, s, s.identity, data); guardErr != nil {
+		return data, guardErr
+	}
+
+	s.mu.RLock()
+	extract := s.extract
+	s.mu.RUnlock()
+	s.Observe(extract(data))
+	return data, nil
+}
+
+// Observe records a sample directly, such as a latency measured around a
+// call rather than carried on the item.
+func (s *Stat[T]) Observe(value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.count == 0 {
+		s.ewma = value
+	} else {
+		s.ewma += s.alpha * (value - s.ewma)
+	}
+	s.count++
+
+	if len(s.window) < cap(s.window) {
+		s.window = append(s.window, value)
+	} else {
+		s.window[s.next] = value
+		s.next = (s.next + 1) % len(s.window)
+	}
+	s.sorted = nil
+}
+
+// EWMA returns the exponentially weighted moving average of the samples,
+// zero before the first.
+func (s *Stat[T]) EWMA() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ewma
+}
+
+// Quantile returns the q quantile of the samples in the rolling window,
+// such as 0.99 for the p99, interpolating between samples. It returns zero
+// before the first sample; q is clamped to [0, 1].
+func (s *Stat[T]) Quantile(q float64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.window) == 0 {
+		return 0
+	}
+	if s.sorted == nil {
+		s.sorted = slices.Clone(s.window)
+		slices.Sort(s.sorted)
+	}
+	pos := min(max(q, 0), 1) * float64(len(s.sorted)-1)
+	lower := int(pos)
+	if lower == len(s.sorted)-1 {
+		return s.sorted[lower]
+	}
+	frac := pos - float64(lower)
+	return s.sorted[lower] + frac*(s.sorted[lower+1]-s.sorted[lower])
+}
+
+// Count returns the number of samples recorded.
+func (s *Stat[T]) Count() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.count
+}
+
+// Above returns a Condition routing items to above while the EWMA exceeds
+// threshold and to below otherwise.
+func (s *Stat[T]) Above(threshold float64, above, below string) Condition[T] {
+	return s.condition(func() float64 { return s.EWMA() }, threshold, above, below)
+}
+
+// QuantileAbove returns a Condition routing items to above while the q
+// quantile of the rolling window exceeds threshold and to below otherwise.
+func (s *Stat[T]) QuantileAbove(q, threshold float64, above, below string) Condition[T] {
+	return s.condition(func() float64 { return s.Quantile(q) }, threshold, above, below)
+}
+
+func (s *Stat[T]) condition(value func() float64, threshold float64, above, below string) Condition[T] {
+	return func(context.Context, T) string {
+		s.mu.RLock()
+		warm := s.count >= s.minSamples
+		s.mu.RUnlock()
+		if warm && value() > threshold {
+			return above
+		}
+		return below
+	}
+}
+
+// SetAlpha sets the EWMA smoothing factor in (0, 1]: higher values follow
+// recent samples more closely, lower values smooth out spikes. Values out
+// of range are ignored.
+func (s *Stat[T]) SetAlpha(alpha float64) *Stat[T] {
+	if alpha > 0 && alpha <= 1 {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.alpha = alpha
+	}
+	return s
+}
+
+// SetWindow sets how many recent samples quantiles are computed over,
+// discarding the current window. Sizes below one are treated as one.
+func (s *Stat[T]) SetWindow(size int) *Stat[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.window = make([]float64, 0, max(size, 1))
+	s.sorted = nil
+	s.next = 0
+	return s
+}
+
+// SetMinSamples sets how many samples must be seen before conditions from
+// Above and QuantileAbove can route above threshold.
+func (s *Stat[T]) SetMinSamples(n int) *Stat[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.minSamples = int64(max(n, 1))
+	return s
+}
+
+// Reset discards all samples.
+func (s *Stat[T]) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ewma, s.count = 0, 0
+	s.window = s.window[:0]
+	s.sorted = nil
+	s.next = 0
+}
+
+// Identity returns the identity of this processor.
+func (s *Stat[T]) Identity() Identity {
+	return s.identity
+}
+
+// Schema returns a Node representing this processor in the pipeline schema.
+func (s *Stat[T]) Schema() Node {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Node{
+		Identity: s.identity,
+		Type:     "stat",
+		Metadata: map[string]any{
+			"alpha":  s.alpha,
+			"window": cap(s.window),
+		},
+	}
+}
+
+// Close implements the Chainable interface. Stat holds no resources.
+func (*Stat[T]) Close() error {
+	return nil
+}
@@ -0,0 +1,97 @@
+package pipz
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestStat(t *testing.T) {
+	ctx := context.Background()
+	identity := func(v float64) float64 { return v }
+
+	t.Run("EWMA", func(t *testing.T) {
+		stat := NewStat(testIdentity("stat"), identity).SetAlpha(0.5)
+		if stat.EWMA() != 0 {
+			t.Errorf("expected zero before samples, got %v", stat.EWMA())
+		}
+		for _, v := range []float64{10, 20, 40} {
+			result, err := stat.Process(ctx, v)
+			if err != nil || result != v {
+				t.Fatalf("expected %v passed through, got %v, %v", v, result, err)
+			}
+		}
+		// 10, then 15, then 27.5
+		if stat.EWMA() != 27.5 || stat.Count() != 3 {
+			t.Errorf("expected EWMA 27.5 over 3 samples, got %v over %d", stat.EWMA(), stat.Count())
+		}
+		stat.Observe(math.NaN())
+		if stat.Count() != 3 {
+			t.Errorf("expected NaN to be ignored, got %d samples", stat.Count())
+		}
+	})
+
+	t.Run("Quantiles Over Window", func(t *testing.T) {
+		stat := NewStat(testIdentity("stat"), identity).SetWindow(5)
+		for v := 1.0; v <= 10; v++ {
+			stat.Observe(v)
+		}
+		// The window holds 6 through 10.
+		cases := map[float64]float64{0: 6, 0.5: 8, 1: 10, 0.9: 9.6, 2: 10}
+		for q, want := range cases {
+			if got := stat.Quantile(q); math.Abs(got-want) > 1e-9 {
+				t.Errorf("Quantile(%v) = %v; want %v", q, got, want)
+			}
+		}
+		stat.Reset()
+		if stat.Quantile(0.5) != 0 || stat.Count() != 0 {
+			t.Errorf("expected reset stat to be empty")
+		}
+	})
+
+	t.Run("Gates Switch", func(t *testing.T) {
+		stat := NewStat(testIdentity("latency"), identity).SetAlpha(1).SetMinSamples(2)
+		route := func(name string) Chainable[float64] {
+			return Transform(testIdentity(name), func(_ context.Context, v float64) float64 {
+				if name == "fallback" {
+					return -v
+				}
+				return v
+			})
+		}
+		sw := NewSwitch(testIdentity("route"), stat.Above(0.8, "fallback", "primary")).
+			AddRoute("primary", route("primary")).
+			AddRoute("fallback", route("fallback"))
+		pipeline := NewSequence(testIdentity("pipeline"), Chainable[float64](stat), sw)
+
+		if got, _ := pipeline.Process(ctx, 0.9); got != 0.9 {
+			t.Errorf("expected primary before min samples, got %v", got)
+		}
+		if got, _ := pipeline.Process(ctx, 0.95); got != -0.95 {
+			t.Errorf("expected fallback while latency is high, got %v", got)
+		}
+		if got, _ := pipeline.Process(ctx, 0.1); got != 0.1 {
+			t.Errorf("expected primary once latency recovers, got %v", got)
+		}
+	})
+
+	t.Run("Quantile Condition", func(t *testing.T) {
+		stat := NewStat(testIdentity("stat"), identity)
+		cond := stat.QuantileAbove(0.99, 100, "slow", "fast")
+		for range 98 {
+			stat.Observe(10)
+		}
+		stat.Observe(500)
+		stat.Observe(500)
+		if got := cond(ctx, 0); got != "slow" {
+			t.Errorf("expected slow p99, got %q", got)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := NewStat(testIdentity("stat"), identity).SetWindow(50).Schema()
+		if node.Type != "stat" || node.Metadata["window"] != 50 {
+			t.Errorf("unexpected schema %+v", node)
+		}
+	})
+}
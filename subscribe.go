@@ -0,0 +1,374 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// ErrSubscriptionClosed is returned by a Subscriber's Receive when no more
+// messages will arrive. Run treats it as a clean shutdown.
+var ErrSubscriptionClosed = errors.New("subscription closed")
+
+// Message is a message received from a message bus. Ack and Nack are
+// optional hooks for brokers that need explicit acknowledgement. Partition
+// and Offset locate the message in a partitioned log, such as a Kafka
+// topic partition, for subscriptions that checkpoint their position.
+type Message struct {
+	Ack       func(context.Context) error
+	Nack      func(context.Context, error) error
+	Topic     string
+	Partition string
+	Payload   []byte
+	Offset    int64
+}
+
+// Subscriber receives messages from a message bus. Receive blocks until a
+// message arrives, ctx is done, or the subscription ends, in which case it
+// returns ErrSubscriptionClosed. Run calls Receive from a single goroutine.
+type Subscriber interface {
+	Receive(ctx context.Context) (Message, error)
+}
+
+// SubscriberFunc adapts a function to the Subscriber interface.
+type SubscriberFunc func(ctx context.Context) (Message, error)
+
+// Receive calls f.
+func (f SubscriberFunc) Receive(ctx context.Context) (Message, error) {
+	return f(ctx)
+}
+
+// Decoder converts a message payload to data.
+type Decoder[T any] func([]byte) (T, error)
+
+// JSONDecoder returns a Decoder that unmarshals JSON payloads.
+func JSONDecoder[T any]() Decoder[T] {
+	return func(payload []byte) (T, error) {
+		var v T
+		err := json.Unmarshal(payload, &v)
+		return v, err
+	}
+}
+
+type messageKey struct{}
+
+// MessageFromContext returns the message being processed, for processors
+// that need its topic or raw payload.
+func MessageFromContext(ctx context.Context) (Message, bool) {
+	msg, ok := ctx.Value(messageKey{}).(Message)
+	return msg, ok
+}
+
+// Subscription runs messages from a Subscriber through a pipeline. Each
+// message is decoded and processed with its own context, carrying the
+// message (see MessageFromContext) and the per-message timeout. Up to the
+// configured concurrency messages are processed at once; Receive is not
+// called again until a slot is free, so a slow pipeline applies
+// backpressure to the broker.
+//
+// Successful messages are acked and failed ones nacked, when the message
+// provides those hooks. Failures then go to the error handler: the default
+// logs a subscription.failed signal and continues, while a handler that
+// returns an error stops Run with that error.
+//
+// With SetCheckpointer, the subscription tracks each message's partition
+// and offset and commits its position on an interval, so a restarted
+// subscription resumes after the last committed message. Only positions
+// below every unfinished message are committed, so messages completing out
+// of order, or left unfinished at shutdown, are redelivered rather than
+// skipped: processing is at-least-once, and the pipeline should tolerate
+// duplicates.
+//
+// Example:
+//
+//	sub := pipz.NewSubscription(OrderEventsID, natsSubscriber,
+//	    pipz.JSONDecoder[OrderCreated](), orderPipeline).
+//	    SetConcurrency(8).
+//	    SetMessageTimeout(10 * time.Second)
+//
+//	if err := sub.Run(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+type Subscription[T any] struct {
+	identity    Identity
+	subscriber  Subscriber
+	decode      Decoder[T]
+	processor   Chainable[T]
+	onError     func(context.Context, Message, error) error
+	checkpoint  Checkpointer
+	clock       clockz.Clock
+	concurrency int
+	timeout     time.Duration
+	commitEvery time.Duration
+	mu          sync.RWMutex
+}
+
+// NewSubscription creates a Subscription processing one message at a time
+// with no per-message timeout. A nil decode decodes JSON.
+func NewSubscription[T any](identity Identity, subscriber Subscriber, decode Decoder[T], processor Chainable[T]) *Subscription[T] {
+	if decode == nil {
+		decode = JSONDecoder[T]()
+	}
+	return &Subscription[T]{
+		identity:    identity,
+		subscriber:  subscriber,
+		decode:      decode,
+		processor:   processor,
+		concurrency: 1,
+	}
+}
+
+// SetConcurrency sets how many messages are processed at once. Values below
+// 1 are treated as 1. Takes effect on the next Run.
+func (s *Subscription[T]) SetConcurrency(n int) *Subscription[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 1 {
+		n = 1
+	}
+	s.concurrency = n
+	return s
+}
+
+// SetMessageTimeout bounds processing of each message. Zero disables the
+// timeout.
+func (s *Subscription[T]) SetMessageTimeout(d time.Duration) *Subscription[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeout = d
+	return s
+}
+
+// SetErrorHandler sets the error policy. The handler receives each message
+// that failed to decode or process; returning nil continues with the next
+// message, returning an error stops Run with it.
+func (s *Subscription[T]) SetErrorHandler(handler func(context.Context, Message, error) error) *Subscription[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onError = handler
+	return s
+}
+
+// SetCheckpointer checkpoints the subscription's position with
+// checkpointer under its identity's name, committing every interval and
+// when Run returns. A zero interval commits after every message. On Run,
+// the committed positions are loaded and, if the Subscriber implements
+// Seeker, reading resumes from them. Failed messages count as processed
+// once the error handler lets Run continue. Takes effect on the next Run.
+//
+// Example:
+//
+//	sub := pipz.NewSubscription(OrderEventsID, kafkaSubscriber, nil, orderPipeline).
+//	    SetConcurrency(8).
+//	    SetCheckpointer(offsetStore, 5*time.Second)
+func (s *Subscription[T]) SetCheckpointer(checkpointer Checkpointer, interval time.Duration) *Subscription[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoint = checkpointer
+	s.commitEvery = max(interval, 0)
+	return s
+}
+
+// WithClock sets a custom clock for the checkpoint interval.
+func (s *Subscription[T]) WithClock(clock clockz.Clock) *Subscription[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+	return s
+}
+
+// getClock returns the clock to use.
+func (s *Subscription[T]) getClock() clockz.Clock {
+	if s.clock == nil {
+		return clockz.RealClock
+	}
+	return s.clock
+}
+
+// Identity returns the identity of this subscription.
+func (s *Subscription[T]) Identity() Identity {
+	return s.identity
+}
+
+// Run receives and processes messages until ctx is canceled, the
+// subscriber returns ErrSubscriptionClosed, or the error handler stops it.
+// In-flight messages see the cancellation and finish (or fail and are
+// nacked) before Run returns. Cancellation and a closed subscription return
+// nil; a receive failure, handler error, or failure to load or seek to the
+// checkpoint is returned.
+func (s *Subscription[T]) Run(ctx context.Context) error {
+	s.mu.RLock()
+	concurrency := s.concurrency
+	checkpointer := s.checkpoint
+	commitEvery := s.commitEvery
+	clock := s.getClock()
+	s.mu.RUnlock()
+
+	var tracker *offsetTracker
+	if checkpointer != nil {
+		var err error
+		tracker, err = s.resume(ctx, checkpointer)
+		if err != nil {
+			return err
+		}
+		if commitEvery > 0 {
+			committer := startCompactor(clock, commitEvery, func() {
+				s.commit(ctx, tracker, checkpointer)
+			})
+			defer committer.Stop()
+		}
+		// Runs after in-flight messages finish, before the committer stops.
+		defer s.commit(context.WithoutCancel(ctx), tracker, checkpointer)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		stopErr error
+		once    sync.Once
+	)
+	stop := func(err error) {
+		once.Do(func() {
+			stopErr = err
+			cancel()
+		})
+	}
+
+	slots := make(chan struct{}, concurrency)
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-runCtx.Done():
+			wg.Wait()
+			return stopErr
+		}
+
+		msg, err := s.subscriber.Receive(runCtx)
+		if err != nil {
+			<-slots
+			if runCtx.Err() == nil && !errors.Is(err, ErrSubscriptionClosed) {
+				stop(fmt.Errorf("receive: %w", err))
+			}
+			wg.Wait()
+			return stopErr
+		}
+
+		if tracker != nil {
+			tracker.start(msg)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			ok, handleErr := s.handle(runCtx, msg)
+			if handleErr != nil {
+				stop(handleErr)
+				return
+			}
+			// A failure caused by shutdown leaves the message for redelivery
+			if tracker != nil && (ok || runCtx.Err() == nil) {
+				tracker.done(msg)
+				if commitEvery == 0 {
+					s.commit(runCtx, tracker, checkpointer)
+				}
+			}
+		}()
+	}
+}
+
+// resume loads the committed positions, seeks the subscriber to them, and
+// returns a tracker starting from them.
+func (s *Subscription[T]) resume(ctx context.Context, checkpointer Checkpointer) (*offsetTracker, error) {
+	var positions map[string]int64
+	err := recoverCall(ctx, func() error {
+		var err error
+		positions, err = checkpointer.Load(ctx, s.identity.Name())
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load checkpoint: %w", err)
+	}
+	if seeker, ok := s.subscriber.(Seeker); ok && len(positions) > 0 {
+		if err := recoverCall(ctx, func() error { return seeker.Seek(ctx, positions) }); err != nil {
+			return nil, fmt.Errorf("seek: %w", err)
+		}
+	}
+	return newOffsetTracker(positions), nil
+}
+
+// commit commits the tracked positions, signaling failures; the next
+// commit retries them.
+func (s *Subscription[T]) commit(ctx context.Context, tracker *offsetTracker, checkpointer Checkpointer) {
+	if err := tracker.commit(ctx, checkpointer, s.identity.Name()); err != nil {
+		capitan.Warn(context.WithoutCancel(ctx), SignalCheckpointFailed,
+			FieldName.Field(s.identity.Name()),
+			FieldIdentityID.Field(s.identity.ID().String()),
+			FieldError.Field(err.Error()),
+		)
+	}
+}
+
+// handle processes one message and applies the error policy, reporting
+// whether the message was processed and acked.
+func (s *Subscription[T]) handle(ctx context.Context, msg Message) (bool, error) {
+	s.mu.RLock()
+	decode := s.decode
+	processor := s.processor
+	timeout := s.timeout
+	onError := s.onError
+	s.mu.RUnlock()
+
+	msgCtx := context.WithValue(ctx, messageKey{}, msg)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		msgCtx, cancel = context.WithTimeout(msgCtx, timeout)
+		defer cancel()
+	}
+
+	err := recoverCall(msgCtx, func() error {
+		data, decodeErr := decode(msg.Payload)
+		if decodeErr != nil {
+			return fmt.Errorf("decode: %w", decodeErr)
+		}
+		_, processErr := processor.Process(msgCtx, data)
+		return processErr
+	})
+
+	// A skipped or early-exited message was handled as intended
+	if isControl(err) {
+		err = nil
+	}
+	if err == nil {
+		if msg.Ack == nil {
+			return true, nil
+		}
+		ackErr := recoverCall(msgCtx, func() error { return msg.Ack(msgCtx) })
+		if ackErr == nil {
+			return true, nil
+		}
+		err = fmt.Errorf("ack: %w", ackErr)
+	} else if msg.Nack != nil {
+		if nackErr := recoverCall(msgCtx, func() error { return msg.Nack(msgCtx, err) }); nackErr != nil {
+			err = errors.Join(err, fmt.Errorf("nack: %w", nackErr))
+		}
+	}
+	if onError != nil {
+		return false, onError(msgCtx, msg, err)
+	}
+	// Detached so the report survives Run canceling its context on return.
+	capitan.Error(context.WithoutCancel(msgCtx), SignalSubscriptionFailed,
+		FieldName.Field(s.identity.Name()),
+		FieldIdentityID.Field(s.identity.ID().String()),
+		FieldTopic.Field(msg.Topic),
+		FieldError.Field(err.Error()),
+	)
+	return false, nil
+}
@@ -0,0 +1,150 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// queueSubscriber delivers a fixed list of messages, then reports closed.
+type queueSubscriber struct {
+	messages []Message
+	mu       sync.Mutex
+}
+
+func (q *queueSubscriber) Receive(_ context.Context) (Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.messages) == 0 {
+		return Message{}, ErrSubscriptionClosed
+	}
+	msg := q.messages[0]
+	q.messages = q.messages[1:]
+	return msg, nil
+}
+
+func TestSubscription(t *testing.T) {
+	t.Run("Processes And Acks Messages", func(t *testing.T) {
+		var sum, acked atomic.Int64
+		ack := func(context.Context) error { acked.Add(1); return nil }
+		sub := &queueSubscriber{messages: []Message{
+			{Topic: "n", Payload: []byte("1"), Ack: ack},
+			{Topic: "n", Payload: []byte("2"), Ack: ack},
+			{Topic: "n", Payload: []byte("3"), Ack: ack},
+		}}
+		add := Effect(NewIdentity("sum", ""), func(ctx context.Context, n int) error {
+			if msg, ok := MessageFromContext(ctx); !ok || msg.Topic != "n" {
+				return errors.New("missing message in context")
+			}
+			sum.Add(int64(n))
+			return nil
+		})
+
+		err := NewSubscription[int](NewIdentity("numbers", ""), sub, nil, add).SetConcurrency(2).Run(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sum.Load() != 6 || acked.Load() != 3 {
+			t.Errorf("expected sum 6 and 3 acks, got %d and %d", sum.Load(), acked.Load())
+		}
+	})
+
+	t.Run("Failures Are Nacked And Signaled", func(t *testing.T) {
+		var nacked atomic.Int64
+		var topic string
+		listener := capitan.Hook(SignalSubscriptionFailed, func(_ context.Context, e *capitan.Event) {
+			topic, _ = FieldTopic.From(e)
+		})
+		defer listener.Close()
+
+		sub := &queueSubscriber{messages: []Message{
+			{Topic: "bad", Payload: []byte("not json"), Nack: func(context.Context, error) error { nacked.Add(1); return nil }},
+		}}
+		err := NewSubscription[int](NewIdentity("numbers", ""), sub, nil, Transform(NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })).
+			Run(context.Background())
+		if err != nil {
+			t.Fatalf("default policy should continue, got %v", err)
+		}
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if nacked.Load() != 1 || topic != "bad" {
+			t.Errorf("expected nack and signal for 'bad', got %d, %q", nacked.Load(), topic)
+		}
+	})
+
+	t.Run("Error Handler Stops Run", func(t *testing.T) {
+		stopErr := errors.New("stop")
+		var processed atomic.Int64
+		sub := &queueSubscriber{messages: []Message{{Payload: []byte("1")}, {Payload: []byte("2")}, {Payload: []byte("3")}}}
+		fail := Apply(NewIdentity("fail", ""), func(_ context.Context, n int) (int, error) {
+			processed.Add(1)
+			return n, errors.New("boom")
+		})
+		err := NewSubscription[int](NewIdentity("numbers", ""), sub, nil, fail).
+			SetErrorHandler(func(context.Context, Message, error) error { return stopErr }).
+			Run(context.Background())
+		if !errors.Is(err, stopErr) {
+			t.Fatalf("expected stop error, got %v", err)
+		}
+		if processed.Load() != 1 {
+			t.Errorf("expected processing to stop after first failure, got %d", processed.Load())
+		}
+	})
+
+	t.Run("Receive Error Is Returned", func(t *testing.T) {
+		broken := errors.New("connection lost")
+		sub := SubscriberFunc(func(context.Context) (Message, error) { return Message{}, broken })
+		err := NewSubscription[int](NewIdentity("numbers", ""), sub, nil, Transform(NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })).
+			Run(context.Background())
+		if !errors.Is(err, broken) {
+			t.Fatalf("expected receive error, got %v", err)
+		}
+	})
+
+	t.Run("Cancellation Returns Nil", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		sub := SubscriberFunc(func(ctx context.Context) (Message, error) {
+			<-ctx.Done()
+			return Message{}, ctx.Err()
+		})
+		done := make(chan error, 1)
+		go func() {
+			done <- NewSubscription[int](NewIdentity("numbers", ""), sub, nil, Transform(NewIdentity("noop", ""), func(_ context.Context, n int) int { return n })).
+				Run(ctx)
+		}()
+		cancel()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("expected nil on cancellation, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("run did not stop on cancellation")
+		}
+	})
+
+	t.Run("Message Timeout", func(t *testing.T) {
+		var timedOut atomic.Bool
+		sub := &queueSubscriber{messages: []Message{{Payload: []byte("1")}}}
+		slow := Effect(NewIdentity("slow", ""), func(ctx context.Context, _ int) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		err := NewSubscription[int](NewIdentity("numbers", ""), sub, nil, slow).
+			SetMessageTimeout(10*time.Millisecond).
+			SetErrorHandler(func(_ context.Context, _ Message, err error) error {
+				timedOut.Store(errors.Is(err, context.DeadlineExceeded))
+				return nil
+			}).
+			Run(context.Background())
+		if err != nil || !timedOut.Load() {
+			t.Fatalf("expected per-message timeout, got %v, %v", err, timedOut.Load())
+		}
+	})
+}
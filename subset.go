@@ -0,0 +1,71 @@
+package pipz
+
+import (
+	"context"
+	"slices"
+)
+
+// Selector chooses stages by identity for ProcessSubset.
+type Selector func(Identity) bool
+
+// SelectTags returns a Selector matching stages tagged with any of tags.
+// See Identity.WithTags.
+func SelectTags(tags ...string) Selector {
+	return func(id Identity) bool {
+		return slices.ContainsFunc(tags, id.HasTag)
+	}
+}
+
+// ProcessSubset runs only the stages matching selector, in their original
+// order, skipping the rest. A matching stage runs whole; a nested Sequence
+// that does not match is searched for matching stages of its own. It suits
+// pre-flight validation endpoints that reuse a production pipeline without
+// executing its side effects.
+//
+// The selected stages run as this Sequence would run them, so errors carry
+// its identity and the outcome of early exits and skips is unchanged.
+//
+// Example:
+//
+//	// Validate an order without charging or shipping it
+//	_, err := checkout.ProcessSubset(ctx, order, pipz.SelectTags("validation"))
+func (c *Sequence[T]) ProcessSubset(ctx context.Context, data T, selector Selector) (T, error) {
+	return NewSequence(c.identity, c.subset(selector)...).Process(ctx, data)
+}
+
+// subset returns the stages of the sequence matching selector, descending
+// into nested sequences that do not match.
+func (c *Sequence[T]) subset(selector Selector) []Chainable[T] {
+	c.mu.RLock()
+	processors := slices.Clone(c.processors)
+	c.mu.RUnlock()
+
+	var selected []Chainable[T]
+	for _, proc := range processors {
+		if selector(proc.Identity()) {
+			selected = append(selected, proc)
+			continue
+		}
+		if nested, ok := proc.(*Sequence[T]); ok {
+			selected = append(selected, nested.subset(selector)...)
+		}
+	}
+	return selected
+}
+
+// ProcessSubset runs only the stages of the pipeline matching selector,
+// with the pipeline's execution context, policy, and counters. If the root
+// matches, it runs whole; if it is a Sequence, its matching stages run as
+// with Sequence.ProcessSubset; otherwise nothing runs and data is returned
+// unchanged.
+func (p *Pipeline[T]) ProcessSubset(ctx context.Context, data T, selector Selector) (T, error) {
+	root := p.root
+	if !selector(root.Identity()) {
+		var stages []Chainable[T]
+		if seq, ok := root.(*Sequence[T]); ok {
+			stages = seq.subset(selector)
+		}
+		root = NewSequence(root.Identity(), stages...)
+	}
+	return p.process(ctx, root, data)
+}
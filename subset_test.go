@@ -0,0 +1,107 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIdentityTags(t *testing.T) {
+	t.Run("Sorted And Deduplicated", func(t *testing.T) {
+		base := NewIdentity("check", "")
+		id := base.WithTags("validation", "", "cheap").WithTags("validation")
+		if !slices.Equal(id.Tags(), []string{"cheap", "validation"}) {
+			t.Errorf("unexpected tags: %v", id.Tags())
+		}
+		if id.ID() != base.ID() || !id.HasTag("cheap") || id.HasTag("") || id.HasTag("charge") {
+			t.Error("unexpected identity or tag lookup")
+		}
+		if base.Tags() != nil {
+			t.Errorf("expected the original to be untagged, got %v", base.Tags())
+		}
+	})
+
+	t.Run("Schema JSON", func(t *testing.T) {
+		node := Transform(NewIdentity("check", "").WithTags("validation"), func(_ context.Context, s string) string { return s }).Schema()
+		data, err := json.Marshal(node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"tags":["validation"]`) {
+			t.Errorf("expected tags in JSON, got %s", data)
+		}
+		var decoded Node
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.Identity.HasTag("validation") {
+			t.Errorf("expected tags to round-trip, got %v", decoded.Identity.Tags())
+		}
+	})
+}
+
+func TestProcessSubset(t *testing.T) {
+	// step appends name to the string, marking that it ran.
+	step := func(name string, tags ...string) Chainable[string] {
+		return Transform(NewIdentity(name, "").WithTags(tags...), func(_ context.Context, s string) string {
+			return s + name + ";"
+		})
+	}
+	checkout := NewSequence(testIdentity("checkout"),
+		step("validate-order", "validation"),
+		NewSequence(testIdentity("payment"),
+			step("validate-card", "validation"),
+			step("charge", "side-effect"),
+		),
+		step("ship", "side-effect"),
+		NewSequence(NewIdentity("fraud", "").WithTags("validation"),
+			step("score"),
+		),
+	)
+
+	t.Run("Runs Matching Stages In Order", func(t *testing.T) {
+		result, err := checkout.ProcessSubset(context.Background(), "", SelectTags("validation"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != "validate-order;validate-card;score;" {
+			t.Errorf("unexpected stages ran: %q", result)
+		}
+	})
+
+	t.Run("No Match", func(t *testing.T) {
+		result, err := checkout.ProcessSubset(context.Background(), "input", SelectTags("missing"))
+		if err != nil || result != "input" {
+			t.Errorf("expected input unchanged, got %q, %v", result, err)
+		}
+	})
+
+	t.Run("Errors Carry Sequence Path", func(t *testing.T) {
+		failing := Apply(NewIdentity("failing", "").WithTags("validation"), func(_ context.Context, s string) (string, error) {
+			return s, errors.New("invalid")
+		})
+		seq := NewSequence(testIdentity("seq"), failing)
+		_, err := seq.ProcessSubset(context.Background(), "", SelectTags("validation"))
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "seq" {
+			t.Errorf("expected path through seq, got %v", err)
+		}
+	})
+
+	t.Run("Pipeline", func(t *testing.T) {
+		pipeline := NewPipeline(testIdentity("pipeline"), checkout)
+		result, err := pipeline.ProcessSubset(context.Background(), "", SelectTags("side-effect"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != "charge;ship;" {
+			t.Errorf("unexpected stages ran: %q", result)
+		}
+		if pipeline.Stats().Processed != 1 {
+			t.Errorf("expected the pipeline to count the run, got %d", pipeline.Stats().Processed)
+		}
+	})
+}
@@ -0,0 +1,375 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// ErrRestartLimit is returned by Supervisor.Run when a child fails more
+// often than the restart limit allows.
+var ErrRestartLimit = errors.New("restart limit exceeded")
+
+// Worker states reported by Supervisor.Status.
+const (
+	WorkerIdle       = "idle"
+	WorkerRunning    = "running"
+	WorkerRestarting = "restarting"
+	WorkerStopped    = "stopped"
+	WorkerFailed     = "failed"
+)
+
+// Runner is a long-running background task, such as a Subscription, a
+// Mailbox consumer, or another Supervisor. Run blocks until ctx is canceled
+// or the task ends; a returned error means it ended unexpectedly.
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// RunnerFunc adapts a function to the Runner interface.
+type RunnerFunc func(ctx context.Context) error
+
+// Run implements Runner.
+func (f RunnerFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// WorkerStatus describes a supervised worker. Children is populated for
+// workers that are themselves supervisors, forming a status tree.
+type WorkerStatus struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	State     string         `json:"state"`
+	LastError string         `json:"last_error,omitempty"`
+	Children  []WorkerStatus `json:"children,omitempty"`
+	Restarts  int            `json:"restarts"`
+}
+
+// Supervisor runs long-running workers and restarts them when they fail,
+// in the style of an Erlang supervisor. A worker that returns an error or
+// panics is restarted after an exponential backoff; one that returns nil
+// has finished and is not restarted. If a worker fails more than the
+// restart limit within the restart window, the supervisor stops every
+// worker and Run returns an error wrapping ErrRestartLimit.
+//
+// Workers implementing Starter are started in order before any worker
+// runs, and Run returns the first start failure without running any.
+// Workers implementing HealthChecker are consulted by Health.
+//
+// Supervisor implements Runner, so supervisors nest: an escalation stops
+// the inner supervisor, which its parent then restarts or escalates in
+// turn. Status reports the whole tree.
+//
+// Defaults: 100ms initial backoff doubling to 30s, and at most 5 restarts
+// per worker within a minute.
+//
+// Example:
+//
+//	var IngestID = pipz.NewIdentity("ingest", "Supervises message consumers")
+//	sup := pipz.NewSupervisor(IngestID).
+//	    Add(OrdersSubID, ordersSubscription).
+//	    Add(ShipmentsID, pipz.RunnerFunc(func(ctx context.Context) error {
+//	        return shipments.Run(ctx, fulfilment)
+//	    })).
+//	    SetRestartLimit(3, time.Minute)
+//
+//	if err := sup.Run(ctx); err != nil {
+//	    log.Fatalf("ingest gave up: %v", err)
+//	}
+type Supervisor struct {
+	identity    Identity
+	clock       clockz.Clock
+	workers     []*supervisedWorker
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	window      time.Duration
+	maxRestarts int
+	mu          sync.RWMutex
+}
+
+// supervisedWorker tracks one worker's state for Status.
+type supervisedWorker struct {
+	runner   Runner
+	identity Identity
+	state    string
+	lastErr  error
+	restarts int
+	mu       sync.Mutex
+}
+
+// NewSupervisor creates an empty Supervisor with default restart policy.
+func NewSupervisor(identity Identity) *Supervisor {
+	return &Supervisor{
+		identity:    identity,
+		baseDelay:   100 * time.Millisecond,
+		maxDelay:    30 * time.Second,
+		maxRestarts: 5,
+		window:      time.Minute,
+	}
+}
+
+// Add registers a worker. Workers added while the supervisor is running
+// start on the next Run.
+func (s *Supervisor) Add(identity Identity, runner Runner) *Supervisor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workers = append(s.workers, &supervisedWorker{
+		identity: identity,
+		runner:   runner,
+		state:    WorkerIdle,
+	})
+	return s
+}
+
+// SetBackoff sets the delay before the first restart and the cap it
+// doubles up to.
+func (s *Supervisor) SetBackoff(base, maxDelay time.Duration) *Supervisor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.baseDelay = base
+	s.maxDelay = max(maxDelay, base)
+	return s
+}
+
+// SetRestartLimit sets how many times a worker may fail within window
+// before the supervisor escalates. A limit below zero is treated as zero,
+// escalating on the first failure.
+func (s *Supervisor) SetRestartLimit(n int, window time.Duration) *Supervisor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxRestarts = max(n, 0)
+	s.window = window
+	return s
+}
+
+// WithClock sets a custom clock for testing.
+func (s *Supervisor) WithClock(clock clockz.Clock) *Supervisor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+	return s
+}
+
+// Identity returns the identity of this supervisor.
+func (s *Supervisor) Identity() Identity {
+	return s.identity
+}
+
+// Run starts every worker and supervises them until ctx is canceled,
+// every worker has finished, or a worker exceeds the restart limit.
+// Cancellation and normal completion return nil.
+func (s *Supervisor) Run(ctx context.Context) error {
+	s.mu.RLock()
+	workers := make([]*supervisedWorker, len(s.workers))
+	copy(workers, s.workers)
+	s.mu.RUnlock()
+
+	for _, w := range workers {
+		starter, ok := w.runner.(Starter)
+		if !ok {
+			continue
+		}
+		if err := StartAll(ctx, starter); err != nil {
+			w.setState(WorkerFailed, err)
+			return fmt.Errorf("%s: starting worker %s: %w", s.identity.Name(), w.identity.Name(), err)
+		}
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg         sync.WaitGroup
+		once       sync.Once
+		escalation error
+	)
+	escalate := func(err error) {
+		once.Do(func() {
+			escalation = err
+			cancel()
+		})
+	}
+
+	for _, w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.supervise(runCtx, w); err != nil {
+				escalate(err)
+			}
+		}()
+	}
+	wg.Wait()
+	return escalation
+}
+
+// supervise runs w until it finishes, ctx is canceled, or it exceeds the
+// restart limit, which is returned as an error.
+func (s *Supervisor) supervise(ctx context.Context, w *supervisedWorker) error {
+	var failures []time.Time
+	for {
+		s.mu.RLock()
+		clock := s.getClock()
+		baseDelay, maxDelay := s.baseDelay, s.maxDelay
+		maxRestarts, window := s.maxRestarts, s.window
+		s.mu.RUnlock()
+
+		w.setState(WorkerRunning, nil)
+		err := recoverCall(ctx, func() error { return w.runner.Run(ctx) })
+		if ctx.Err() != nil || err == nil {
+			w.setState(WorkerStopped, err)
+			return nil
+		}
+
+		// Count failures within the window.
+		now := clock.Now()
+		recent := failures[:0]
+		for _, at := range failures {
+			if now.Sub(at) < window {
+				recent = append(recent, at)
+			}
+		}
+		failures = append(recent, now)
+
+		if len(failures) > maxRestarts {
+			w.setState(WorkerFailed, err)
+			capitan.Error(context.WithoutCancel(ctx), SignalSupervisorEscalated,
+				FieldName.Field(s.identity.Name()),
+				FieldIdentityID.Field(s.identity.ID().String()),
+				FieldWorker.Field(w.identity.Name()),
+				FieldError.Field(err.Error()),
+			)
+			return fmt.Errorf("%s: worker %s failed %d times within %v: %w: %w",
+				s.identity.Name(), w.identity.Name(), len(failures), window, ErrRestartLimit, err)
+		}
+
+		// Back off exponentially across consecutive recent failures.
+		delay := baseDelay
+		for i := 1; i < len(failures) && delay < maxDelay; i++ {
+			delay *= 2
+		}
+		delay = min(delay, maxDelay)
+
+		w.restart(err)
+		capitan.Warn(ctx, SignalSupervisorRestarting,
+			FieldName.Field(s.identity.Name()),
+			FieldIdentityID.Field(s.identity.ID().String()),
+			FieldWorker.Field(w.identity.Name()),
+			FieldAttempt.Field(len(failures)),
+			FieldDelay.Field(delay.Seconds()),
+			FieldError.Field(err.Error()),
+		)
+
+		select {
+		case <-clock.After(delay):
+		case <-ctx.Done():
+			w.setState(WorkerStopped, err)
+			return nil
+		}
+	}
+}
+
+// Status returns the state of the supervisor and its workers. Workers that
+// are supervisors report their own workers as children.
+func (s *Supervisor) Status() WorkerStatus {
+	s.mu.RLock()
+	workers := make([]*supervisedWorker, len(s.workers))
+	copy(workers, s.workers)
+	s.mu.RUnlock()
+
+	status := WorkerStatus{
+		ID:    s.identity.ID().String(),
+		Name:  s.identity.Name(),
+		State: WorkerIdle,
+	}
+	for _, w := range workers {
+		child := w.status()
+		status.Restarts += child.Restarts
+		switch child.State {
+		case WorkerFailed:
+			status.State = WorkerFailed
+		case WorkerRunning, WorkerRestarting:
+			if status.State != WorkerFailed {
+				status.State = WorkerRunning
+			}
+		case WorkerStopped:
+			if status.State == WorkerIdle {
+				status.State = WorkerStopped
+			}
+		}
+		status.Children = append(status.Children, child)
+	}
+	return status
+}
+
+// Health reports failed workers and checks every worker implementing
+// HealthChecker, returning their joined errors.
+func (s *Supervisor) Health(ctx context.Context) error {
+	s.mu.RLock()
+	workers := make([]*supervisedWorker, len(s.workers))
+	copy(workers, s.workers)
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, w := range workers {
+		if status := w.status(); status.State == WorkerFailed {
+			errs = append(errs, fmt.Errorf("%s: worker %s failed: %s", s.identity.Name(), w.identity.Name(), status.LastError))
+			continue
+		}
+		if checker, ok := w.runner.(HealthChecker); ok {
+			if err := CheckHealth(ctx, checker); err != nil {
+				errs = append(errs, fmt.Errorf("%s: worker %s unhealthy: %w", s.identity.Name(), w.identity.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// getClock returns the clock to use.
+func (s *Supervisor) getClock() clockz.Clock {
+	if s.clock == nil {
+		return clockz.RealClock
+	}
+	return s.clock
+}
+
+func (w *supervisedWorker) setState(state string, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.state = state
+	if err != nil {
+		w.lastErr = err
+	}
+}
+
+func (w *supervisedWorker) restart(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.state = WorkerRestarting
+	w.lastErr = err
+	w.restarts++
+}
+
+func (w *supervisedWorker) status() WorkerStatus {
+	w.mu.Lock()
+	status := WorkerStatus{
+		ID:       w.identity.ID().String(),
+		Name:     w.identity.Name(),
+		State:    w.state,
+		Restarts: w.restarts,
+	}
+	if w.lastErr != nil {
+		status.LastError = w.lastErr.Error()
+	}
+	w.mu.Unlock()
+
+	if sup, ok := w.runner.(*Supervisor); ok {
+		status.Children = sup.Status().Children
+	}
+	return status
+}
@@ -0,0 +1,157 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestSupervisor(t *testing.T) {
+	errCrash := errors.New("crash")
+	// flaky fails the first n runs, then finishes.
+	flaky := func(runs *atomic.Int32, n int32) Runner {
+		return RunnerFunc(func(context.Context) error {
+			if runs.Add(1) <= n {
+				return errCrash
+			}
+			return nil
+		})
+	}
+	blocking := RunnerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	newSupervisor := func(name string) *Supervisor {
+		return NewSupervisor(NewIdentity(name, "")).SetBackoff(time.Millisecond, 4*time.Millisecond)
+	}
+
+	t.Run("Restarts Until Worker Finishes", func(t *testing.T) {
+		var runs atomic.Int32
+		sup := newSupervisor("sup").Add(NewIdentity("worker", ""), flaky(&runs, 3))
+		if err := sup.Run(context.Background()); err != nil {
+			t.Fatalf("expected clean finish, got %v", err)
+		}
+		status := sup.Status()
+		if runs.Load() != 4 || status.Restarts != 3 {
+			t.Errorf("expected 4 runs and 3 restarts, got %d and %d", runs.Load(), status.Restarts)
+		}
+		if child := status.Children[0]; child.State != WorkerStopped || child.LastError != "crash" {
+			t.Errorf("unexpected worker status: %+v", child)
+		}
+	})
+
+	t.Run("Restarts After Panic", func(t *testing.T) {
+		var runs atomic.Int32
+		sup := newSupervisor("sup").Add(NewIdentity("worker", ""), RunnerFunc(func(context.Context) error {
+			if runs.Add(1) == 1 {
+				panic("boom")
+			}
+			return nil
+		}))
+		if err := sup.Run(context.Background()); err != nil || runs.Load() != 2 {
+			t.Errorf("expected restart after panic, got %v with %d runs", err, runs.Load())
+		}
+	})
+
+	t.Run("Escalates Past Restart Limit", func(t *testing.T) {
+		var runs, stopped atomic.Int32
+		sup := newSupervisor("sup").
+			Add(NewIdentity("crasher", ""), flaky(&runs, 100)).
+			Add(NewIdentity("steady", ""), RunnerFunc(func(ctx context.Context) error {
+				<-ctx.Done()
+				stopped.Add(1)
+				return nil
+			})).
+			SetRestartLimit(2, time.Minute)
+
+		err := sup.Run(context.Background())
+		if !errors.Is(err, ErrRestartLimit) || !errors.Is(err, errCrash) {
+			t.Fatalf("expected restart limit error, got %v", err)
+		}
+		if runs.Load() != 3 || stopped.Load() != 1 {
+			t.Errorf("expected 3 runs and siblings stopped, got %d runs, %d stopped", runs.Load(), stopped.Load())
+		}
+		if status := sup.Status(); status.State != WorkerFailed || status.Children[0].State != WorkerFailed {
+			t.Errorf("expected failed status, got %+v", status)
+		}
+	})
+
+	t.Run("Failures Outside Window Do Not Count", func(t *testing.T) {
+		var runs atomic.Int32
+		sup := newSupervisor("sup").
+			Add(NewIdentity("worker", ""), flaky(&runs, 5)).
+			SetRestartLimit(1, time.Nanosecond)
+		if err := sup.Run(context.Background()); err != nil {
+			t.Errorf("expected old failures to be forgiven, got %v", err)
+		}
+	})
+
+	t.Run("Cancellation Stops Workers", func(t *testing.T) {
+		sup := newSupervisor("sup").Add(NewIdentity("a", ""), blocking).Add(NewIdentity("b", ""), blocking)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- sup.Run(ctx) }()
+
+		time.Sleep(10 * time.Millisecond)
+		if status := sup.Status(); status.State != WorkerRunning {
+			t.Errorf("expected running, got %s", status.State)
+		}
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("expected nil on cancel, got %v", err)
+		}
+		for _, child := range sup.Status().Children {
+			if child.State != WorkerStopped || child.Restarts != 0 {
+				t.Errorf("unexpected child status: %+v", child)
+			}
+		}
+	})
+
+	t.Run("Nested Supervisors Form A Tree", func(t *testing.T) {
+		var runs atomic.Int32
+		inner := newSupervisor("inner").
+			Add(NewIdentity("crasher", ""), flaky(&runs, 100)).
+			SetRestartLimit(0, time.Minute)
+		outer := newSupervisor("outer").
+			Add(NewIdentity("inner", ""), inner).
+			SetRestartLimit(1, time.Minute)
+
+		err := outer.Run(context.Background())
+		if !errors.Is(err, ErrRestartLimit) {
+			t.Fatalf("expected escalation to reach the outer supervisor, got %v", err)
+		}
+		if runs.Load() != 2 {
+			t.Errorf("expected inner supervisor restarted once, got %d runs", runs.Load())
+		}
+
+		status := outer.Status()
+		if len(status.Children) != 1 || len(status.Children[0].Children) != 1 {
+			t.Fatalf("expected nested status tree, got %+v", status)
+		}
+		if leaf := status.Children[0].Children[0]; leaf.Name != "crasher" || leaf.State != WorkerFailed {
+			t.Errorf("unexpected leaf status: %+v", leaf)
+		}
+	})
+
+	t.Run("Emits Restart Signal", func(t *testing.T) {
+		var worker string
+		listener := capitan.Hook(SignalSupervisorRestarting, func(_ context.Context, e *capitan.Event) {
+			worker, _ = FieldWorker.From(e)
+		})
+		defer listener.Close()
+
+		var runs atomic.Int32
+		_ = newSupervisor("sup").Add(NewIdentity("indexer", ""), flaky(&runs, 1)).Run(context.Background()) //nolint:errcheck // signal checked below
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if worker != "indexer" {
+			t.Errorf("expected worker 'indexer', got %q", worker)
+		}
+	})
+}
@@ -3,10 +3,14 @@ package pipz
 import (
 	"context"
 	"errors"
+	"maps"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
 )
 
 // Condition determines routing based on input data.
@@ -34,6 +38,11 @@ type Condition[T any] func(context.Context, T) string
 //
 // If no route exists for the returned key, the input passes through unchanged.
 //
+// When the condition is expensive, such as a call to an ML classifier,
+// SetRouteCache remembers the route chosen for each item key for a TTL, so
+// identical follow-up items skip the condition. Cached routes can be
+// dropped early with InvalidateRoute.
+//
 // Switch is perfect for:
 //   - Status-based workflows with defined states
 //   - Region-specific logic
@@ -65,9 +74,15 @@ type Condition[T any] func(context.Context, T) string
 //	router.AddRoute(RouteCrypto, cryptoProcessor)
 type Switch[T any] struct {
 	condition Condition[T]
+	routing   *RouteSpec
 	routes    map[string]Chainable[T]
+	cacheKey  func(context.Context, T) string
+	cache     *keyedState[string]
+	clock     clockz.Clock
 	identity  Identity
+	cacheHits atomic.Int64
 	mu        sync.RWMutex
+	cacheMu   sync.Mutex
 	closeOnce sync.Once
 	closeErr  error
 }
@@ -78,18 +93,36 @@ func NewSwitch[T any](identity Identity, condition Condition[T]) *Switch[T] {
 		identity:  identity,
 		condition: condition,
 		routes:    make(map[string]Chainable[T]),
+		cache:     newKeyedState[string](),
 	}
 }
 
+// NewSwitchRouter creates a new Switch connector routed by a Router. The
+// router's logic is included in the switch's schema.
+func NewSwitchRouter[T any](identity Identity, router Router[T]) *Switch[T] {
+	s := NewSwitch(identity, router.Condition())
+	spec := router.Spec()
+	s.routing = &spec
+	return s
+}
+
 // Process implements the Chainable interface.
 // If no route matches the condition result, the input is returned unchanged.
 func (s *Switch[T]) Process(ctx context.Context, data T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, s.identity, data)
 
+	ctx, guardErr := enterDepth(ctx, s, s.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	route := s.condition(ctx, data)
+	route, forced := forcedRoute(ctx, s.identity)
+	if !forced {
+		route = s.route(ctx, data)
+	}
 
 	processor, exists := s.routes[route]
 
@@ -112,13 +145,13 @@ func (s *Switch[T]) Process(ctx context.Context, data T) (result T, err error) {
 		var pipeErr *Error[T]
 		if errors.As(err, &pipeErr) {
 			// Prepend this switch's identity to the path
-			pipeErr.Path = append([]Identity{s.identity}, pipeErr.Path...)
+			pipeErr.prependPath(ctx, s.identity)
 			return result, pipeErr
 		}
 		// Handle non-pipeline errors by wrapping them
 		return result, &Error[T]{
 			Timestamp: time.Now(),
-			InputData: data,
+			InputData: errorInput(data),
 			Err:       err,
 			Path:      []Identity{s.identity},
 		}
@@ -126,8 +159,37 @@ func (s *Switch[T]) Process(ctx context.Context, data T) (result T, err error) {
 	return result, nil
 }
 
+// route returns the condition's route for data, from the route cache when
+// the item's key has a live entry. The caller holds s.mu.
+func (s *Switch[T]) route(ctx context.Context, data T) string {
+	if s.cacheKey == nil {
+		return s.condition(ctx, data)
+	}
+	key := s.cacheKey(ctx, data)
+	if key == "" {
+		return s.condition(ctx, data)
+	}
+
+	if !CacheBypassed(ctx) {
+		s.cacheMu.Lock()
+		route, ok := s.cache.get(key, s.getClock().Now())
+		s.cacheMu.Unlock()
+		if ok {
+			s.cacheHits.Add(1)
+			return route
+		}
+	}
+
+	route := s.condition(ctx, data)
+	s.cacheMu.Lock()
+	s.cache.put(key, route, s.getClock().Now())
+	s.cacheMu.Unlock()
+	return route
+}
+
 // AddRoute adds or updates a route in the switch.
 func (s *Switch[T]) AddRoute(key string, processor Chainable[T]) *Switch[T] {
+	noteChildren(s.identity, processor)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.routes[key] = processor
@@ -147,9 +209,95 @@ func (s *Switch[T]) SetCondition(condition Condition[T]) *Switch[T] {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.condition = condition
+	s.routing = nil
+	s.InvalidateRoutes()
 	return s
 }
 
+// SetRouter updates the condition to a Router, recording its logic for
+// the schema.
+func (s *Switch[T]) SetRouter(router Router[T]) *Switch[T] {
+	spec := router.Spec()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.condition = router.Condition()
+	s.routing = &spec
+	s.InvalidateRoutes()
+	return s
+}
+
+// SetRouteCache caches the route chosen for each item for ttl, keyed by
+// key, so identical follow-up items skip the condition. Items with an
+// empty key are never cached, and routes forced by a CallOption bypass the
+// cache. Calls made with BypassCache run the condition and replace the
+// cached route. A nil key disables caching. Changing the condition clears
+// the cache.
+func (s *Switch[T]) SetRouteCache(key func(context.Context, T) string, ttl time.Duration) *Switch[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cacheKey = key
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.cache.ttl = ttl
+	s.cache.compact(s.getClock().Now())
+	return s
+}
+
+// SetRouteCacheMaxKeys bounds the number of cached routes, evicting the
+// least recently cached beyond it. Zero caches any number of keys.
+func (s *Switch[T]) SetRouteCacheMaxKeys(n int) *Switch[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.cache.maxKeys = max(n, 0)
+	s.cache.compact(s.getClock().Now())
+	return s
+}
+
+// InvalidateRoute drops the cached route for key, so its next item runs
+// the condition. Returns false if no route was cached for key.
+func (s *Switch[T]) InvalidateRoute(key string) bool {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	return s.cache.remove(key)
+}
+
+// InvalidateRoutes drops every cached route.
+func (s *Switch[T]) InvalidateRoutes() {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.cache.clear()
+}
+
+// RouteCacheHits returns the number of items routed from the cache.
+func (s *Switch[T]) RouteCacheHits() int64 {
+	return s.cacheHits.Load()
+}
+
+// RouteCacheStats returns the number of routes cached and evicted.
+func (s *Switch[T]) RouteCacheStats() KeyStats {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	return s.cache.keyStats()
+}
+
+// WithClock sets a custom clock for testing.
+func (s *Switch[T]) WithClock(clock clockz.Clock) *Switch[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+	return s
+}
+
+// getClock returns the clock to use.
+func (s *Switch[T]) getClock() clockz.Clock {
+	if s.clock == nil {
+		return clockz.RealClock
+	}
+	return s.clock
+}
+
 // Routes returns a copy of the current routes map.
 func (s *Switch[T]) Routes() map[string]Chainable[T] {
 	s.mu.RLock()
@@ -180,6 +328,7 @@ func (s *Switch[T]) ClearRoutes() *Switch[T] {
 
 // SetRoutes replaces all routes in the switch atomically.
 func (s *Switch[T]) SetRoutes(routes map[string]Chainable[T]) *Switch[T] {
+	noteChildren(s.identity, slices.Collect(maps.Values(routes))...)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.routes = make(map[string]Chainable[T], len(routes))
@@ -206,11 +355,24 @@ func (s *Switch[T]) Schema() Node {
 		routes[key] = proc.Schema()
 	}
 
-	return Node{
+	flow := SwitchFlow{Routes: routes}
+	if s.routing != nil {
+		routing := *s.routing
+		routing.Rules = slices.Clone(routing.Rules)
+		flow.Routing = &routing
+	}
+
+	node := Node{
 		Identity: s.identity,
 		Type:     "switch",
-		Flow:     SwitchFlow{Routes: routes},
+		Flow:     flow,
+	}
+	if s.cacheKey != nil {
+		s.cacheMu.Lock()
+		node.Metadata = map[string]any{"route_cache_ttl": s.cache.ttl.String()}
+		s.cacheMu.Unlock()
 	}
+	return node
 }
 
 // Close gracefully shuts down the connector and all its route processors.
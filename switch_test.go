@@ -3,9 +3,13 @@ package pipz
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
 )
 
 func TestSwitch(t *testing.T) {
@@ -460,3 +464,137 @@ func TestSwitchSignals(t *testing.T) {
 		}
 	})
 }
+
+func TestSwitchRouteCache(t *testing.T) {
+	type ticket struct {
+		Customer string
+		Text     string
+	}
+	ctx := context.Background()
+
+	// classify returns a Switch whose condition counts its calls.
+	classify := func(clock clockz.Clock) (*Switch[ticket], *int) {
+		calls := 0
+		sw := NewSwitch(testIdentity("classify"), func(_ context.Context, tk ticket) string {
+			calls++
+			if strings.Contains(tk.Text, "refund") {
+				return "billing"
+			}
+			return "support"
+		}).
+			WithClock(clock).
+			SetRouteCache(func(_ context.Context, tk ticket) string { return tk.Customer }, time.Minute)
+		return sw, &calls
+	}
+
+	t.Run("Skips Condition For Cached Keys", func(t *testing.T) {
+		sw, calls := classify(clockz.NewFakeClock())
+		for range 3 {
+			sw.Process(ctx, ticket{Customer: "acme", Text: "refund please"}) //nolint:errcheck
+		}
+		sw.Process(ctx, ticket{Customer: "globex", Text: "help"}) //nolint:errcheck
+		if *calls != 2 || sw.RouteCacheHits() != 2 {
+			t.Errorf("expected 2 condition calls and 2 hits, got %d and %d", *calls, sw.RouteCacheHits())
+		}
+		if stats := sw.RouteCacheStats(); stats.Keys != 2 {
+			t.Errorf("expected 2 cached routes, got %+v", stats)
+		}
+		if meta := sw.Schema().Metadata; meta["route_cache_ttl"] != "1m0s" {
+			t.Errorf("unexpected schema metadata %v", meta)
+		}
+	})
+
+	t.Run("Expires And Invalidates", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		sw, calls := classify(clock)
+		sw.Process(ctx, ticket{Customer: "acme"}) //nolint:errcheck
+
+		clock.Advance(2 * time.Minute)
+		sw.Process(ctx, ticket{Customer: "acme"}) //nolint:errcheck
+		if *calls != 2 {
+			t.Errorf("expected expired route to be recomputed, got %d calls", *calls)
+		}
+
+		if !sw.InvalidateRoute("acme") || sw.InvalidateRoute("acme") {
+			t.Error("expected InvalidateRoute to report whether a route was cached")
+		}
+		sw.Process(ctx, ticket{Customer: "acme"}) //nolint:errcheck
+		sw.InvalidateRoutes()
+		sw.Process(ctx, ticket{Customer: "acme"}) //nolint:errcheck
+		if *calls != 4 {
+			t.Errorf("expected invalidated routes to be recomputed, got %d calls", *calls)
+		}
+	})
+
+	t.Run("Condition Change Clears Cache", func(t *testing.T) {
+		sw, _ := classify(clockz.NewFakeClock())
+		sw.Process(ctx, ticket{Customer: "acme"}) //nolint:errcheck
+		sw.SetCondition(func(context.Context, ticket) string { return "billing" })
+		if sw.RouteCacheStats().Keys != 0 {
+			t.Error("expected SetCondition to clear cached routes")
+		}
+	})
+
+	t.Run("Empty Keys And Forced Routes Bypass Cache", func(t *testing.T) {
+		sw, calls := classify(clockz.NewFakeClock())
+		sw.Process(ctx, ticket{}) //nolint:errcheck
+		sw.Process(ctx, ticket{}) //nolint:errcheck
+		if *calls != 2 || sw.RouteCacheStats().Keys != 0 {
+			t.Errorf("expected empty keys not to be cached, got %d calls", *calls)
+		}
+		forced := WithCallOptions(ctx, ForceRoute(sw.Identity(), "billing"))
+		sw.Process(forced, ticket{Customer: "acme"}) //nolint:errcheck
+		if sw.RouteCacheStats().Keys != 0 {
+			t.Error("expected forced route not to be cached")
+		}
+	})
+
+	t.Run("Bypass Cache Reevaluates", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		sw, calls := classify(clock)
+		sw.Process(ctx, ticket{Customer: "acme", Text: "refund please"}) //nolint:errcheck
+
+		bypassed := WithCallOptions(ctx, BypassCache())
+		route, err := sw.Process(bypassed, ticket{Customer: "acme", Text: "help"})
+		if err != nil || route.Text != "help" || *calls != 2 || sw.RouteCacheHits() != 0 {
+			t.Fatalf("expected the condition run again, got %d calls and %d hits", *calls, sw.RouteCacheHits())
+		}
+		if route, _ := sw.cache.get("acme", clock.Now()); route != "support" {
+			t.Errorf("expected the cached route replaced, got %q", route)
+		}
+		sw.Process(ctx, ticket{Customer: "acme"}) //nolint:errcheck
+		if *calls != 2 || sw.RouteCacheHits() != 1 {
+			t.Errorf("expected the recomputed route cached, got %d calls and %d hits", *calls, sw.RouteCacheHits())
+		}
+	})
+
+	t.Run("Max Keys", func(t *testing.T) {
+		sw, _ := classify(clockz.NewFakeClock())
+		sw.SetRouteCacheMaxKeys(2)
+		for _, c := range []string{"a", "b", "c"} {
+			sw.Process(ctx, ticket{Customer: c}) //nolint:errcheck
+		}
+		if stats := sw.RouteCacheStats(); stats.Keys != 2 || stats.Displaced != 1 {
+			t.Errorf("expected 2 keys and 1 displaced, got %+v", stats)
+		}
+	})
+
+	t.Run("Settings Concurrent With Clock Change", func(t *testing.T) {
+		sw, _ := classify(clockz.NewFakeClock())
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				sw.WithClock(clockz.NewFakeClock())
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				sw.SetRouteCacheMaxKeys(i)
+			}
+		}()
+		wg.Wait()
+	})
+}
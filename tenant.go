@@ -0,0 +1,378 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// TenantStats holds per-tenant execution counters.
+// Each tenant's counters are isolated so one noisy tenant cannot obscure
+// another's error rate.
+type TenantStats struct {
+	LastUsed time.Time
+	Requests int64
+	Errors   int64
+}
+
+// tenantEntry holds a lazily constructed pipeline variant for one tenant.
+type tenantEntry[T any] struct {
+	chainable Chainable[T]
+	err       error
+	once      sync.Once
+	ready     atomic.Bool
+	lastUsed  atomic.Int64
+	requests  atomic.Int64
+	errors    atomic.Int64
+	inFlight  atomic.Int64
+}
+
+// TenantRouter routes each item to a pipeline variant owned by its tenant.
+// The tenant is extracted from the context or data, and the variant is
+// built on first use by the factory, which receives the tenant ID and can
+// load that tenant's configuration (steps, limits, providers). Variants are
+// cached, so stateful connectors such as RateLimiter and CircuitBreaker are
+// naturally isolated per tenant.
+//
+// Idle tenants can be evicted with SetIdleTimeout: variants unused for
+// longer than the timeout are closed and rebuilt on next use. Variants with
+// requests in flight are never evicted.
+//
+// CRITICAL: TenantRouter is STATEFUL - it caches one variant per tenant.
+// Create it once and reuse it.
+//
+// Example:
+//
+//	var TenantOrdersID = pipz.NewIdentity("tenant-orders", "Per-tenant order processing")
+//	router := pipz.NewTenantRouter(TenantOrdersID,
+//	    func(_ context.Context, o Order) string { return o.TenantID },
+//	    func(tenantID string) (pipz.Chainable[Order], error) {
+//	        cfg, err := tenants.Config(tenantID)
+//	        if err != nil {
+//	            return nil, err
+//	        }
+//	        return buildOrderPipeline(cfg), nil
+//	    },
+//	).SetIdleTimeout(30 * time.Minute)
+type TenantRouter[T any] struct {
+	clock       clockz.Clock
+	extract     func(context.Context, T) string
+	factory     func(tenantID string) (Chainable[T], error)
+	tenants     map[string]*tenantEntry[T]
+	identity    Identity
+	idleTimeout time.Duration
+	lastSweep   time.Time
+	mu          sync.RWMutex
+	closeOnce   sync.Once
+	closeErr    error
+}
+
+// NewTenantRouter creates a TenantRouter that extracts tenant IDs with
+// extract and builds per-tenant variants with factory.
+func NewTenantRouter[T any](identity Identity, extract func(context.Context, T) string, factory func(tenantID string) (Chainable[T], error)) *TenantRouter[T] {
+	return &TenantRouter[T]{
+		identity: identity,
+		extract:  extract,
+		factory:  factory,
+		tenants:  make(map[string]*tenantEntry[T]),
+	}
+}
+
+// Process implements the Chainable interface.
+func (r *TenantRouter[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, r.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, r, r.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	r.mu.RLock()
+	extract := r.extract
+	clock := r.getClock()
+	r.mu.RUnlock()
+
+	tenantID := extract(ctx, data)
+	r.sweep(ctx)
+
+	var entry *tenantEntry[T]
+	for entry == nil {
+		entry, err = r.entry(ctx, tenantID)
+		if err != nil {
+			var zero T
+			return zero, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(data),
+				Err:       fmt.Errorf("tenant %q: %w", tenantID, err),
+				Path:      []Identity{r.identity},
+			}
+		}
+		// Pin the variant so eviction cannot close it mid-request. If it was
+		// evicted between lookup and pinning, look it up again.
+		r.mu.RLock()
+		entry.inFlight.Add(1)
+		current := r.tenants[tenantID] == entry
+		r.mu.RUnlock()
+		if !current {
+			entry.inFlight.Add(-1)
+			entry = nil
+		}
+	}
+	defer entry.inFlight.Add(-1)
+	entry.lastUsed.Store(clock.Now().UnixNano())
+	entry.requests.Add(1)
+
+	result, err = entry.chainable.Process(ctx, data)
+	if err != nil {
+		entry.errors.Add(1)
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, r.identity)
+			return result, pipeErr
+		}
+		return result, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{r.identity},
+		}
+	}
+	return result, nil
+}
+
+// entry returns the tenant's variant, building it on first use.
+// Concurrent first requests for a tenant share a single factory call.
+// Failed constructions are not cached so the next request retries.
+func (r *TenantRouter[T]) entry(ctx context.Context, tenantID string) (*tenantEntry[T], error) {
+	r.mu.RLock()
+	entry, ok := r.tenants[tenantID]
+	factory := r.factory
+	clock := r.getClock()
+	r.mu.RUnlock()
+
+	if !ok {
+		r.mu.Lock()
+		if entry, ok = r.tenants[tenantID]; !ok {
+			entry = &tenantEntry[T]{}
+			r.tenants[tenantID] = entry
+		}
+		r.mu.Unlock()
+	}
+
+	entry.once.Do(func() {
+		entry.chainable, entry.err = factory(tenantID)
+		if entry.err == nil && entry.chainable == nil {
+			entry.err = errors.New("tenant factory returned nil chainable")
+		}
+		if entry.err != nil {
+			r.mu.Lock()
+			if r.tenants[tenantID] == entry {
+				delete(r.tenants, tenantID)
+			}
+			r.mu.Unlock()
+			return
+		}
+		noteChildren(r.identity, entry.chainable)
+		entry.lastUsed.Store(clock.Now().UnixNano())
+		entry.ready.Store(true)
+		capitan.Info(ctx, SignalTenantCreated,
+			FieldName.Field(r.identity.Name()),
+			FieldIdentityID.Field(r.identity.ID().String()),
+			FieldTenant.Field(tenantID),
+		)
+	})
+	return entry, entry.err
+}
+
+// sweep evicts idle tenants at most once per idle timeout.
+func (r *TenantRouter[T]) sweep(ctx context.Context) {
+	r.mu.RLock()
+	timeout := r.idleTimeout
+	due := timeout > 0 && r.getClock().Since(r.lastSweep) >= timeout
+	r.mu.RUnlock()
+	if due {
+		r.evictIdle(ctx)
+	}
+}
+
+// EvictIdle closes and removes variants idle for longer than the idle
+// timeout, returning the number evicted. It is called automatically during
+// processing; call it directly to reclaim resources on a schedule.
+func (r *TenantRouter[T]) EvictIdle() int {
+	return r.evictIdle(context.Background())
+}
+
+func (r *TenantRouter[T]) evictIdle(ctx context.Context) int {
+	r.mu.Lock()
+	now := r.getClock().Now()
+	r.lastSweep = now
+	if r.idleTimeout <= 0 {
+		r.mu.Unlock()
+		return 0
+	}
+	cutoff := now.Add(-r.idleTimeout).UnixNano()
+	evicted := make(map[string]*tenantEntry[T])
+	for id, entry := range r.tenants {
+		if !entry.ready.Load() || entry.inFlight.Load() > 0 {
+			continue
+		}
+		if entry.lastUsed.Load() < cutoff {
+			evicted[id] = entry
+			delete(r.tenants, id)
+		}
+	}
+	r.mu.Unlock()
+
+	for id, entry := range evicted {
+		r.closeEntry(ctx, id, entry)
+	}
+	return len(evicted)
+}
+
+// Evict closes and removes a tenant's variant immediately.
+// The next request for the tenant rebuilds it. Returns false if the tenant
+// had no variant.
+func (r *TenantRouter[T]) Evict(tenantID string) bool {
+	r.mu.Lock()
+	entry, ok := r.tenants[tenantID]
+	delete(r.tenants, tenantID)
+	r.mu.Unlock()
+	if ok && entry.ready.Load() {
+		r.closeEntry(context.Background(), tenantID, entry)
+	}
+	return ok
+}
+
+func (r *TenantRouter[T]) closeEntry(ctx context.Context, tenantID string, entry *tenantEntry[T]) {
+	_ = entry.chainable.Close() //nolint:errcheck // evicted variants are best-effort closed
+	capitan.Info(ctx, SignalTenantEvicted,
+		FieldName.Field(r.identity.Name()),
+		FieldIdentityID.Field(r.identity.ID().String()),
+		FieldTenant.Field(tenantID),
+	)
+}
+
+// SetIdleTimeout sets how long a tenant's variant may go unused before it is
+// evicted. Zero disables eviction.
+func (r *TenantRouter[T]) SetIdleTimeout(timeout time.Duration) *TenantRouter[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.idleTimeout = timeout
+	return r
+}
+
+// SetFactory updates the variant factory. Existing variants are kept;
+// Evict a tenant to rebuild it with the new factory.
+func (r *TenantRouter[T]) SetFactory(factory func(tenantID string) (Chainable[T], error)) *TenantRouter[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factory = factory
+	return r
+}
+
+// WithClock sets a custom clock for testing.
+func (r *TenantRouter[T]) WithClock(clock clockz.Clock) *TenantRouter[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = clock
+	return r
+}
+
+// getClock returns the clock to use.
+func (r *TenantRouter[T]) getClock() clockz.Clock {
+	if r.clock == nil {
+		return clockz.RealClock
+	}
+	return r.clock
+}
+
+// Tenants returns the IDs of tenants with a cached variant, sorted.
+func (r *TenantRouter[T]) Tenants() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0, len(r.tenants))
+	for id, entry := range r.tenants {
+		if entry.ready.Load() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Stats returns the counters for a tenant's current variant.
+// Counters reset when the variant is evicted.
+func (r *TenantRouter[T]) Stats(tenantID string) (TenantStats, bool) {
+	r.mu.RLock()
+	entry, ok := r.tenants[tenantID]
+	r.mu.RUnlock()
+	if !ok || !entry.ready.Load() {
+		return TenantStats{}, false
+	}
+	return TenantStats{
+		Requests: entry.requests.Load(),
+		Errors:   entry.errors.Load(),
+		LastUsed: time.Unix(0, entry.lastUsed.Load()),
+	}, true
+}
+
+// Identity returns the identity of this connector.
+func (r *TenantRouter[T]) Identity() Identity {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+// Only tenants with a cached variant appear.
+func (r *TenantRouter[T]) Schema() Node {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenants := make(map[string]Node, len(r.tenants))
+	for id, entry := range r.tenants {
+		if entry.ready.Load() {
+			tenants[id] = entry.chainable.Schema()
+		}
+	}
+	return Node{
+		Identity: r.identity,
+		Type:     "tenantrouter",
+		Flow:     TenantRouterFlow{Tenants: tenants},
+		Metadata: map[string]any{
+			"idle_timeout": r.idleTimeout.String(),
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and all cached variants.
+// Close is idempotent - multiple calls return the same result.
+func (r *TenantRouter[T]) Close() error {
+	r.closeOnce.Do(func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		ids := make([]string, 0, len(r.tenants))
+		for id := range r.tenants {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		var errs []error
+		for _, id := range ids {
+			if entry := r.tenants[id]; entry.ready.Load() {
+				if err := entry.chainable.Close(); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+		r.tenants = make(map[string]*tenantEntry[T])
+		r.closeErr = errors.Join(errs...)
+	})
+	return r.closeErr
+}
@@ -0,0 +1,209 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+type tenantRecord struct {
+	Tenant string
+	Value  int
+}
+
+func tenantOf(_ context.Context, r tenantRecord) string { return r.Tenant }
+
+func TestTenantRouter(t *testing.T) {
+	// Each tenant's variant multiplies by a tenant-specific factor.
+	factors := map[string]int{"acme": 2, "globex": 10}
+	newFactory := func(built *atomic.Int32) func(string) (Chainable[tenantRecord], error) {
+		return func(tenantID string) (Chainable[tenantRecord], error) {
+			factor, ok := factors[tenantID]
+			if !ok {
+				return nil, errors.New("unknown tenant")
+			}
+			if built != nil {
+				built.Add(1)
+			}
+			return Transform(NewIdentity("scale-"+tenantID, ""), func(_ context.Context, r tenantRecord) tenantRecord {
+				r.Value *= factor
+				return r
+			}), nil
+		}
+	}
+
+	t.Run("Routes To Tenant Variant", func(t *testing.T) {
+		router := NewTenantRouter(NewIdentity("tenants", ""), tenantOf, newFactory(nil))
+		a, err := router.Process(context.Background(), tenantRecord{Tenant: "acme", Value: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		g, err := router.Process(context.Background(), tenantRecord{Tenant: "globex", Value: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if a.Value != 2 || g.Value != 10 {
+			t.Errorf("expected 2 and 10, got %d and %d", a.Value, g.Value)
+		}
+		tenants := router.Tenants()
+		if len(tenants) != 2 || tenants[0] != "acme" || tenants[1] != "globex" {
+			t.Errorf("unexpected tenants: %v", tenants)
+		}
+	})
+
+	t.Run("Variant Built Once Under Concurrency", func(t *testing.T) {
+		var built atomic.Int32
+		router := NewTenantRouter(NewIdentity("tenants", ""), tenantOf, newFactory(&built))
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = router.Process(context.Background(), tenantRecord{Tenant: "acme", Value: 1}) //nolint:errcheck // counted below
+			}()
+		}
+		wg.Wait()
+		if built.Load() != 1 {
+			t.Errorf("expected factory to run once, ran %d times", built.Load())
+		}
+		stats, ok := router.Stats("acme")
+		if !ok || stats.Requests != 50 {
+			t.Errorf("expected 50 requests, got %+v", stats)
+		}
+	})
+
+	t.Run("Factory Errors Are Not Cached", func(t *testing.T) {
+		router := NewTenantRouter(NewIdentity("tenants", ""), tenantOf, newFactory(nil))
+		_, err := router.Process(context.Background(), tenantRecord{Tenant: "initech"})
+		var pipeErr *Error[tenantRecord]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "tenants" {
+			t.Fatalf("expected tenant router error, got %v", err)
+		}
+		factors["initech"] = 3
+		defer delete(factors, "initech")
+		result, err := router.Process(context.Background(), tenantRecord{Tenant: "initech", Value: 1})
+		if err != nil || result.Value != 3 {
+			t.Errorf("expected retry to succeed with 3, got %d (%v)", result.Value, err)
+		}
+	})
+
+	t.Run("Stats Are Isolated", func(t *testing.T) {
+		router := NewTenantRouter(NewIdentity("tenants", ""), tenantOf,
+			func(tenantID string) (Chainable[tenantRecord], error) {
+				return Apply(NewIdentity("step-"+tenantID, ""), func(_ context.Context, r tenantRecord) (tenantRecord, error) {
+					if r.Tenant == "globex" {
+						return r, errors.New("globex failure")
+					}
+					return r, nil
+				}), nil
+			})
+		for i := 0; i < 3; i++ {
+			_, _ = router.Process(context.Background(), tenantRecord{Tenant: "acme"})   //nolint:errcheck // counted below
+			_, _ = router.Process(context.Background(), tenantRecord{Tenant: "globex"}) //nolint:errcheck // counted below
+		}
+		acme, _ := router.Stats("acme")
+		globex, _ := router.Stats("globex")
+		if acme.Requests != 3 || acme.Errors != 0 {
+			t.Errorf("unexpected acme stats: %+v", acme)
+		}
+		if globex.Requests != 3 || globex.Errors != 3 {
+			t.Errorf("unexpected globex stats: %+v", globex)
+		}
+		if _, ok := router.Stats("missing"); ok {
+			t.Error("expected no stats for unknown tenant")
+		}
+	})
+
+	t.Run("Error Path", func(t *testing.T) {
+		router := NewTenantRouter(NewIdentity("tenants", ""), tenantOf,
+			func(string) (Chainable[tenantRecord], error) {
+				return Apply(NewIdentity("fail", ""), func(_ context.Context, r tenantRecord) (tenantRecord, error) {
+					return r, errors.New("boom")
+				}), nil
+			})
+		_, err := router.Process(context.Background(), tenantRecord{Tenant: "acme"})
+		var pipeErr *Error[tenantRecord]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[1].Name() != "fail" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Idle Eviction", func(t *testing.T) {
+		clock := clockz.NewFakeClock()
+		var built atomic.Int32
+		router := NewTenantRouter(NewIdentity("tenants", ""), tenantOf, newFactory(&built)).
+			SetIdleTimeout(time.Minute).
+			WithClock(clock)
+
+		_, _ = router.Process(context.Background(), tenantRecord{Tenant: "acme"}) //nolint:errcheck // setup
+		clock.Advance(30 * time.Second)
+		_, _ = router.Process(context.Background(), tenantRecord{Tenant: "globex"}) //nolint:errcheck // setup
+		clock.Advance(45 * time.Second)
+
+		if evicted := router.EvictIdle(); evicted != 1 {
+			t.Fatalf("expected 1 eviction, got %d", evicted)
+		}
+		if tenants := router.Tenants(); len(tenants) != 1 || tenants[0] != "globex" {
+			t.Errorf("expected only globex to remain, got %v", tenants)
+		}
+
+		_, _ = router.Process(context.Background(), tenantRecord{Tenant: "acme"}) //nolint:errcheck // rebuild
+		if built.Load() != 3 {
+			t.Errorf("expected acme to be rebuilt, factory ran %d times", built.Load())
+		}
+	})
+
+	t.Run("Evict", func(t *testing.T) {
+		var evictedTenant string
+		listener := capitan.Hook(SignalTenantEvicted, func(_ context.Context, e *capitan.Event) {
+			evictedTenant, _ = FieldTenant.From(e)
+		})
+		defer listener.Close()
+
+		router := NewTenantRouter(NewIdentity("tenants", ""), tenantOf, newFactory(nil))
+		_, _ = router.Process(context.Background(), tenantRecord{Tenant: "acme"}) //nolint:errcheck // setup
+		if !router.Evict("acme") {
+			t.Error("expected acme to be evicted")
+		}
+		if router.Evict("acme") {
+			t.Error("expected second evict to report missing tenant")
+		}
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if evictedTenant != "acme" {
+			t.Errorf("expected eviction signal for acme, got %q", evictedTenant)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		router := NewTenantRouter(NewIdentity("tenants", ""), tenantOf, newFactory(nil))
+		_, _ = router.Process(context.Background(), tenantRecord{Tenant: "acme"}) //nolint:errcheck // setup
+		schema := router.Schema()
+		flow, ok := TenantRouterKey.From(schema)
+		if !ok || schema.Type != "tenantrouter" {
+			t.Fatalf("unexpected schema: %+v", schema)
+		}
+		if flow.Tenants["acme"].Identity.Name() != "scale-acme" {
+			t.Errorf("expected acme variant in schema, got %+v", flow.Tenants)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		router := NewTenantRouter(NewIdentity("tenants", ""), tenantOf, newFactory(nil))
+		_, _ = router.Process(context.Background(), tenantRecord{Tenant: "acme"}) //nolint:errcheck // setup
+		if err := router.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+		if len(router.Tenants()) != 0 {
+			t.Error("expected no tenants after close")
+		}
+	})
+}
@@ -450,6 +450,23 @@ func BenchmarkErrorHandling(b *testing.B) {
 		}
 	})
 
+	b.Run("Nested_Failure", func(b *testing.B) {
+		var chain pipz.Chainable[ClonableInt] = pipz.Apply(pipz.NewIdentity("validate", ""), func(_ context.Context, _ ClonableInt) (ClonableInt, error) {
+			return 0, errors.New("invalid")
+		})
+		for range 6 {
+			chain = pipz.NewSequence(pipz.NewIdentity("level", ""), chain)
+		}
+		b.ResetTimer()
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := chain.Process(ctx, data); err == nil {
+				b.Fatal("expected error")
+			}
+		}
+	})
+
 	b.Run("Error_Creation", func(b *testing.B) {
 		b.ResetTimer()
 		b.ReportAllocs()
@@ -0,0 +1,373 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/pipz"
+)
+
+// Branch kinds reported by CoverageReport.
+const (
+	// BranchRoute is a route of a Switch or a tenant of a TenantRouter.
+	BranchRoute = "route"
+	// BranchFlag is the enabled or disabled side of a Flagged connector.
+	BranchFlag = "flag"
+	// BranchFallback is a backup of a Fallback or the fallback of a Verify.
+	BranchFallback = "fallback"
+	// BranchErrorHandler is the error handler of a Handle.
+	BranchErrorHandler = "error-handler"
+	// BranchFilter is the processor of a Filter, run when its condition holds.
+	BranchFilter = "filter"
+	// BranchRetry is a second or later attempt of a Retry or Backoff.
+	BranchRetry = "retry"
+)
+
+// NodeCoverage is how often one node of a tracked pipeline ran.
+type NodeCoverage struct {
+	Path string
+	Type string
+	Hits int64
+}
+
+// BranchCoverage is how often one conditional path of a tracked pipeline
+// was taken. Path is the node taken, or for BranchRetry the retrying
+// connector, and Label names the route or flag side. For BranchRetry, Hits
+// counts the executions that retried at least once.
+type BranchCoverage struct {
+	Path  string
+	Kind  string
+	Label string
+	Hits  int64
+}
+
+// CoverageReport is the topology coverage recorded by a Coverage tracker:
+// which nodes ran and which conditional paths were taken, by schema path
+// (see pipz.Schema.Nodes), in schema order.
+type CoverageReport struct {
+	Nodes    []NodeCoverage
+	Branches []BranchCoverage
+}
+
+// Uncovered returns the branches that were never taken.
+func (r CoverageReport) Uncovered() []BranchCoverage {
+	var uncovered []BranchCoverage
+	for _, b := range r.Branches {
+		if b.Hits == 0 {
+			uncovered = append(uncovered, b)
+		}
+	}
+	return uncovered
+}
+
+// Unreached returns the nodes that never ran.
+func (r CoverageReport) Unreached() []NodeCoverage {
+	var unreached []NodeCoverage
+	for _, n := range r.Nodes {
+		if n.Hits == 0 {
+			unreached = append(unreached, n)
+		}
+	}
+	return unreached
+}
+
+// Percent returns the share of branches taken, from 0 to 100. A pipeline
+// without branches is fully covered.
+func (r CoverageReport) Percent() float64 {
+	if len(r.Branches) == 0 {
+		return 100
+	}
+	covered := len(r.Branches) - len(r.Uncovered())
+	return 100 * float64(covered) / float64(len(r.Branches))
+}
+
+// String summarizes the report, listing every branch never taken.
+func (r CoverageReport) String() string {
+	var b strings.Builder
+	uncovered := r.Uncovered()
+	fmt.Fprintf(&b, "branch coverage: %.1f%% (%d/%d), nodes reached: %d/%d",
+		r.Percent(), len(r.Branches)-len(uncovered), len(r.Branches),
+		len(r.Nodes)-len(r.Unreached()), len(r.Nodes))
+	for _, branch := range uncovered {
+		fmt.Fprintf(&b, "\n  never taken: %s %s", branch.Kind, branch.Path)
+		if branch.Label != "" {
+			fmt.Fprintf(&b, " (%s)", branch.Label)
+		}
+	}
+	return b.String()
+}
+
+// coveredNode is the tracked state of one schema node.
+type coveredNode struct {
+	path    string
+	node    pipz.Node
+	kind    string // branch kind when taking this node is conditional
+	label   string
+	hits    int64
+	retries int64 // executions that retried, for Retry and Backoff nodes
+}
+
+// Coverage tracks which nodes and branches of a pipeline run, for
+// reporting the topology a test suite never exercises. Create one with
+// TrackCoverage.
+type Coverage struct {
+	nodes    map[string][]*coveredNode // by identity ID chain from the root
+	attempts map[string]int            // child entries per retrying execution
+	order    []*coveredNode
+	root     pipz.Identity
+	mu       sync.Mutex
+	stopOnce sync.Once
+}
+
+// coverage is the registry of active trackers consulted by the shared hook.
+var coverage struct {
+	trackers []*Coverage
+	mu       sync.Mutex
+}
+
+// TrackCoverage starts recording which nodes of processor run, anywhere in
+// the process, until Stop. Every run counts, whether processor is called
+// directly or nested in a larger pipeline. Like InjectFault it observes
+// nodes through pipz.SetFaultHook, keyed by the identities on the
+// processing path, so the pipeline is tracked as constructed for
+// production; the two can be used together.
+//
+// Beyond statement coverage, the report shows which Switch routes,
+// Flagged sides, Fallback and Verify fallbacks, Handle error handlers,
+// Filter processors, and Retry or Backoff retries were never exercised.
+// Custom Chainables are covered only through the processors and connectors
+// they contain.
+//
+// Example:
+//
+//	var checkoutCoverage *pipztesting.Coverage
+//
+//	func TestMain(m *testing.M) {
+//	    checkoutCoverage = pipztesting.TrackCoverage(checkout)
+//	    code := m.Run()
+//	    checkoutCoverage.Stop()
+//	    fmt.Println(checkoutCoverage.Report())
+//	    os.Exit(code)
+//	}
+func TrackCoverage[T any](processor pipz.Chainable[T]) *Coverage {
+	schema := pipz.NewSchema(processor.Schema())
+	c := &Coverage{
+		root:     schema.Root.Identity,
+		nodes:    make(map[string][]*coveredNode),
+		attempts: make(map[string]int),
+	}
+
+	chains := make(map[string]string)
+	schema.WalkPaths(func(n pipz.SchemaNode) {
+		chain := n.Node.Identity.ID().String()
+		if n.Parent != nil {
+			chain = chains[n.ParentPath()] + "/" + chain
+		}
+		chains[n.Path] = chain
+
+		covered := &coveredNode{path: n.Path, node: n.Node}
+		covered.kind, covered.label = branchOf(n)
+		c.nodes[chain] = append(c.nodes[chain], covered)
+		c.order = append(c.order, covered)
+	})
+
+	coverage.mu.Lock()
+	coverage.trackers = append(coverage.trackers, c)
+	coverage.mu.Unlock()
+	syncHook()
+	return c
+}
+
+// Stop ends tracking. The recorded coverage remains available.
+func (c *Coverage) Stop() {
+	c.stopOnce.Do(func() {
+		coverage.mu.Lock()
+		for i, tracker := range coverage.trackers {
+			if tracker == c {
+				coverage.trackers = append(coverage.trackers[:i:i], coverage.trackers[i+1:]...)
+				break
+			}
+		}
+		coverage.mu.Unlock()
+		syncHook()
+	})
+}
+
+// Reset discards the coverage recorded so far.
+func (c *Coverage) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, n := range c.order {
+		n.hits, n.retries = 0, 0
+	}
+	clear(c.attempts)
+}
+
+// Report returns the coverage recorded so far.
+func (c *Coverage) Report() CoverageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var report CoverageReport
+	for _, n := range c.order {
+		report.Nodes = append(report.Nodes, NodeCoverage{Path: n.path, Type: n.node.Type, Hits: n.hits})
+		if n.kind != "" {
+			report.Branches = append(report.Branches, BranchCoverage{Path: n.path, Kind: n.kind, Label: n.label, Hits: n.hits})
+		}
+		if isRetrying(n.node) {
+			report.Branches = append(report.Branches, BranchCoverage{Path: n.path, Kind: BranchRetry, Hits: n.retries})
+		}
+	}
+	return report
+}
+
+// AssertCovered fails t, listing the branches never taken, unless every
+// branch recorded by c was taken.
+//
+// Example:
+//
+//	func TestCheckoutCoverage(t *testing.T) {
+//	    coverage := pipztesting.TrackCoverage(checkout)
+//	    defer coverage.Stop()
+//	    for _, order := range fixtures {
+//	        checkout.Process(ctx, order)
+//	    }
+//	    pipztesting.AssertCovered(t, coverage)
+//	}
+func AssertCovered(t testing.TB, c *Coverage) {
+	t.Helper()
+	if report := c.Report(); len(report.Uncovered()) > 0 {
+		t.Errorf("pipeline branches never exercised:\n%s", report)
+	}
+}
+
+// record counts a run of the node at the end of path, if it belongs to the
+// tracked pipeline.
+func (c *Coverage) record(ctx context.Context, path []pipz.Identity) {
+	start := -1
+	for i, identity := range path {
+		if identity.ID() == c.root.ID() {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return
+	}
+	ids := make([]string, len(path)-start)
+	for i, identity := range path[start:] {
+		ids[i] = identity.ID().String()
+	}
+	chain := strings.Join(ids, "/")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	nodes, ok := c.nodes[chain]
+	if !ok {
+		return
+	}
+	for _, n := range nodes {
+		n.hits++
+	}
+
+	// Attempts are counted per execution of the retrying connector: its own
+	// entry resets the count, and a second entry of its child is a retry.
+	execution, _ := pipz.CorrelationIDFromContext(ctx)
+	if isRetrying(nodes[0].node) {
+		c.attempts[execution+"|"+chain] = 0
+	}
+	if i := strings.LastIndexByte(chain, '/'); i >= 0 {
+		parent := chain[:i]
+		parents := c.nodes[parent]
+		if len(parents) == 0 || !isRetrying(parents[0].node) {
+			return
+		}
+		key := execution + "|" + parent
+		attempts, ok := c.attempts[key]
+		if !ok {
+			return
+		}
+		if attempts++; attempts < 2 {
+			c.attempts[key] = attempts
+			return
+		}
+		delete(c.attempts, key)
+		for _, p := range parents {
+			p.retries++
+		}
+	}
+}
+
+// recordCoverage passes a node about to run to every active tracker.
+func recordCoverage(ctx context.Context, path []pipz.Identity) {
+	coverage.mu.Lock()
+	trackers := coverage.trackers
+	coverage.mu.Unlock()
+	for _, c := range trackers {
+		c.record(ctx, path)
+	}
+}
+
+// isRetrying reports whether node runs its processor again on failure.
+func isRetrying(node pipz.Node) bool {
+	switch node.Flow.(type) {
+	case pipz.RetryFlow, pipz.BackoffFlow:
+		return true
+	}
+	return false
+}
+
+// branchOf returns the branch kind and label of n when its parent runs it
+// only under some condition.
+func branchOf(n pipz.SchemaNode) (kind, label string) {
+	if n.Parent == nil {
+		return "", ""
+	}
+	id := n.Node.Identity.ID()
+	switch flow := n.Parent.Flow.(type) {
+	case pipz.SwitchFlow:
+		return BranchRoute, routeKey(flow.Routes, id)
+	case pipz.TenantRouterFlow:
+		return BranchRoute, routeKey(flow.Tenants, id)
+	case pipz.FlaggedFlow:
+		if flow.Disabled.Identity.ID() == id {
+			return BranchFlag, "disabled"
+		}
+		return BranchFlag, "enabled"
+	case pipz.FallbackFlow:
+		if flow.Primary.Identity.ID() != id {
+			return BranchFallback, ""
+		}
+	case pipz.VerifyFlow:
+		if flow.Fallback != nil && flow.Fallback.Identity.ID() == id {
+			return BranchFallback, ""
+		}
+	case pipz.HandleFlow:
+		if flow.ErrorHandler.Identity.ID() == id {
+			return BranchErrorHandler, ""
+		}
+	case pipz.FilterFlow:
+		return BranchFilter, ""
+	}
+	return "", ""
+}
+
+// routeKey returns the first key, in sorted order, routing to the node
+// identified by id.
+func routeKey(routes map[string]pipz.Node, id uuid.UUID) string {
+	keys := make([]string, 0, len(routes))
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if routes[key].Identity.ID() == id {
+			return key
+		}
+	}
+	return ""
+}
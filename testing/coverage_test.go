@@ -0,0 +1,123 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/pipz"
+)
+
+func TestTrackCoverage(t *testing.T) {
+	newCheckout := func() pipz.Chainable[int] {
+		charge := pipz.Apply(pipz.NewIdentity("charge", ""), func(_ context.Context, n int) (int, error) {
+			if n < 0 {
+				return n, errors.New("declined")
+			}
+			return n, nil
+		})
+		manual := pipz.Transform(pipz.NewIdentity("manual", ""), func(_ context.Context, n int) int { return n })
+		payment := pipz.NewFallback(pipz.NewIdentity("payment", ""), pipz.NewRetry(pipz.NewIdentity("retry", ""), charge, 2), manual)
+
+		express := pipz.Transform(pipz.NewIdentity("express", ""), func(_ context.Context, n int) int { return n })
+		standard := pipz.Transform(pipz.NewIdentity("standard", ""), func(_ context.Context, n int) int { return n })
+		shipping := pipz.NewSwitch(pipz.NewIdentity("shipping", ""), func(_ context.Context, n int) string {
+			if n > 100 {
+				return "express"
+			}
+			return "standard"
+		}).AddRoute("express", express).AddRoute("standard", standard)
+
+		return pipz.NewSequence(pipz.NewIdentity("checkout", ""), payment, shipping)
+	}
+	branches := func(report CoverageReport) map[string]int64 {
+		hits := make(map[string]int64)
+		for _, b := range report.Branches {
+			hits[b.Kind+" "+b.Path+" "+b.Label] = b.Hits
+		}
+		return hits
+	}
+
+	t.Run("Reports Branches Never Taken", func(t *testing.T) {
+		checkout := newCheckout()
+		coverage := TrackCoverage(checkout)
+		defer coverage.Stop()
+
+		if _, err := checkout.Process(context.Background(), 10); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		report := coverage.Report()
+		hits := branches(report)
+		want := map[string]int64{
+			"retry checkout/payment/retry ":             0,
+			"fallback checkout/payment/manual ":         0,
+			"route checkout/shipping/express express":   0,
+			"route checkout/shipping/standard standard": 1,
+		}
+		for branch, n := range want {
+			if got, ok := hits[branch]; !ok || got != n {
+				t.Errorf("branch %q: expected %d hits, got %d (recorded %v)", branch, n, got, ok)
+			}
+		}
+		if len(report.Uncovered()) != 3 || report.Percent() != 25 {
+			t.Errorf("expected three of four branches uncovered, got %s", report)
+		}
+		unreached := report.Unreached()
+		if len(unreached) != 2 || unreached[0].Path != "checkout/payment/manual" {
+			t.Errorf("expected manual and express unreached, got %+v", unreached)
+		}
+		if !strings.Contains(report.String(), "never taken: route checkout/shipping/express (express)") {
+			t.Errorf("expected uncovered routes listed, got %s", report)
+		}
+	})
+
+	t.Run("Failures Exercise Retries And Fallbacks", func(t *testing.T) {
+		checkout := newCheckout()
+		coverage := TrackCoverage(checkout)
+		defer coverage.Stop()
+
+		for _, n := range []int{-1, 500, 10} {
+			if _, err := checkout.Process(context.Background(), n); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		report := coverage.Report()
+		if len(report.Uncovered()) != 0 || report.Percent() != 100 {
+			t.Errorf("expected full coverage, got %s", report)
+		}
+		if hits := branches(report); hits["retry checkout/payment/retry "] != 1 {
+			t.Errorf("expected one retrying execution, got %v", hits)
+		}
+		AssertCovered(t, coverage)
+
+		coverage.Reset()
+		if len(coverage.Report().Unreached()) != len(report.Nodes) {
+			t.Error("expected Reset to discard the recorded coverage")
+		}
+	})
+
+	t.Run("Tracks Nested Runs With Injected Faults", func(t *testing.T) {
+		checkout := newCheckout()
+		coverage := TrackCoverage(checkout)
+		remove := InjectFault("checkout/charge", FailAlways(errors.New("connection reset")))
+		defer remove()
+
+		outer := pipz.NewPipeline(pipz.NewIdentity("api", ""), checkout)
+		if _, err := outer.Process(context.Background(), 10); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		coverage.Stop()
+		if _, err := outer.Process(context.Background(), 500); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		hits := branches(coverage.Report())
+		if hits["retry checkout/payment/retry "] != 1 || hits["fallback checkout/payment/manual "] != 1 {
+			t.Errorf("expected the fault to exercise retry and fallback, got %v", hits)
+		}
+		if hits["route checkout/shipping/express express"] != 0 {
+			t.Errorf("expected no coverage recorded after Stop, got %v", hits)
+		}
+	})
+}
@@ -0,0 +1,311 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/pipz"
+)
+
+// Tolerance reports whether two values of an output field are close enough
+// to count as equal. Values are compared in their JSON-decoded form:
+// numbers are float64, objects map[string]any, and arrays []any.
+type Tolerance func(before, after any) bool
+
+// Ignore treats every pair of values as equal, for fields such as
+// timestamps or generated IDs that are expected to differ between runs.
+func Ignore() Tolerance {
+	return func(any, any) bool { return true }
+}
+
+// Within treats two numbers as equal when they differ by at most delta.
+// Non-numeric values must be identical.
+func Within(delta float64) Tolerance {
+	return func(before, after any) bool {
+		b, bok := before.(float64)
+		a, aok := after.(float64)
+		if !bok || !aok {
+			return reflect.DeepEqual(before, after)
+		}
+		return math.Abs(a-b) <= delta
+	}
+}
+
+// DiffOptions tunes how CompareVersions judges differences.
+type DiffOptions struct {
+	// Tolerances maps output field paths to the rule comparing them. A
+	// path is the dot-separated chain of JSON keys and array indexes from
+	// the root of the output, such as "total" or "items.0.price"; a "*"
+	// segment matches any key or index. A rule applies to the whole
+	// subtree at its path.
+	Tolerances map[string]Tolerance
+	// MaxSlowdown flags cases whose latency under the new version exceeds
+	// the old version's by more than this factor, such as 1.5 for 50%
+	// slower. Zero disables latency checks.
+	MaxSlowdown float64
+}
+
+// FieldDiff records one output field that differs between versions.
+// Path is empty when the output itself is a differing scalar.
+type FieldDiff struct {
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+	Path   string `json:"path"`
+}
+
+// CaseDiff is the comparison of one corpus input. ErrorBefore and
+// ErrorAfter describe each version's failure, empty on success.
+type CaseDiff struct {
+	Name          string        `json:"name"`
+	Fields        []FieldDiff   `json:"fields,omitempty"`
+	ErrorBefore   string        `json:"error_before,omitempty"`
+	ErrorAfter    string        `json:"error_after,omitempty"`
+	LatencyBefore time.Duration `json:"latency_before"`
+	LatencyAfter  time.Duration `json:"latency_after"`
+	ErrorChanged  bool          `json:"error_changed,omitempty"`
+	Slower        bool          `json:"slower,omitempty"`
+}
+
+// Differs reports whether the case's outputs, errors, or latency differ
+// beyond tolerance.
+func (c CaseDiff) Differs() bool {
+	return len(c.Fields) > 0 || c.ErrorChanged || c.Slower
+}
+
+// DiffReport is the outcome of CompareVersions: one CaseDiff per corpus
+// input, in name order.
+type DiffReport struct {
+	Cases []CaseDiff `json:"cases"`
+}
+
+// Empty reports whether the versions behaved the same on every input.
+func (r DiffReport) Empty() bool {
+	for _, c := range r.Cases {
+		if c.Differs() {
+			return false
+		}
+	}
+	return true
+}
+
+// Differing returns the cases whose behavior changed.
+func (r DiffReport) Differing() []CaseDiff {
+	var out []CaseDiff
+	for _, c := range r.Cases {
+		if c.Differs() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// String renders the differing cases, one line per field, error, or
+// latency change.
+func (r DiffReport) String() string {
+	var b strings.Builder
+	for _, c := range r.Differing() {
+		fmt.Fprintf(&b, "%s:\n", c.Name)
+		if c.ErrorChanged {
+			fmt.Fprintf(&b, "  error: %s => %s\n", orNone(c.ErrorBefore), orNone(c.ErrorAfter))
+		}
+		for _, f := range c.Fields {
+			path := f.Path
+			if path == "" {
+				path = "(output)"
+			}
+			fmt.Fprintf(&b, "  %s: %v => %v\n", path, f.Before, f.After)
+		}
+		if c.Slower {
+			fmt.Fprintf(&b, "  latency: %v => %v\n", c.LatencyBefore, c.LatencyAfter)
+		}
+	}
+	return b.String()
+}
+
+// orNone renders an empty error description as "none".
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// CompareVersions runs every input in corpus through before and after, the
+// current and candidate versions of a pipeline, and reports how their
+// outputs, errors, and latencies differ. It is the development-time
+// counterpart of a canary or shadow deployment: replay recorded traffic
+// through both versions and review what the change actually does.
+//
+// Outputs are compared field by field in their JSON form, so T must be
+// JSON-encodable; DiffOptions.Tolerances relaxes the comparison per field.
+// Errors are compared by message and path, ignoring timing details. Inputs
+// implementing pipz.Cloner are cloned for each run, so a version that
+// modifies its input cannot affect the other.
+//
+// Example:
+//
+//	report := pipztesting.CompareVersions(ctx, pricingV1, pricingV2, orders,
+//	    pipztesting.DiffOptions{
+//	        Tolerances: map[string]pipztesting.Tolerance{
+//	            "total":     pipztesting.Within(0.01),
+//	            "quoted_at": pipztesting.Ignore(),
+//	        },
+//	        MaxSlowdown: 1.5,
+//	    })
+//	fmt.Print(report)
+func CompareVersions[T any](ctx context.Context, before, after pipz.Chainable[T], corpus map[string]T, opts DiffOptions) DiffReport {
+	names := make([]string, 0, len(corpus))
+	for name := range corpus {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	report := DiffReport{Cases: make([]CaseDiff, 0, len(names))}
+	for _, name := range names {
+		input := corpus[name]
+		c := CaseDiff{Name: name}
+
+		outBefore, latencyBefore, errBefore := runVersion(ctx, before, input)
+		outAfter, latencyAfter, errAfter := runVersion(ctx, after, input)
+		c.LatencyBefore, c.LatencyAfter = latencyBefore, latencyAfter
+
+		recBefore, recAfter := recordError[T](errBefore), recordError[T](errAfter)
+		c.ErrorBefore, c.ErrorAfter = describeError(recBefore), describeError(recAfter)
+		c.ErrorChanged = !reflect.DeepEqual(recBefore, recAfter)
+
+		if errBefore == nil && errAfter == nil {
+			c.Fields = diffOutputs(outBefore, outAfter, opts.Tolerances)
+		}
+		if opts.MaxSlowdown > 0 && float64(c.LatencyAfter) > float64(c.LatencyBefore)*opts.MaxSlowdown {
+			c.Slower = true
+		}
+		report.Cases = append(report.Cases, c)
+	}
+	return report
+}
+
+// AssertSameBehavior fails the test when CompareVersions finds any
+// difference, printing the report.
+func AssertSameBehavior[T any](t testing.TB, before, after pipz.Chainable[T], corpus map[string]T, opts DiffOptions) {
+	t.Helper()
+	if report := CompareVersions(context.Background(), before, after, corpus, opts); !report.Empty() {
+		t.Errorf("pipeline versions differ:\n%s", report)
+	}
+}
+
+// runVersion processes a private copy of input, returning the output as
+// decoded JSON and how long processing took.
+func runVersion[T any](ctx context.Context, processor pipz.Chainable[T], input T) (any, time.Duration, error) {
+	if cloner, ok := any(input).(pipz.Cloner[T]); ok {
+		input = cloner.Clone()
+	}
+	start := time.Now()
+	output, err := processor.Process(ctx, input)
+	latency := time.Since(start)
+
+	data, encodeErr := json.Marshal(output)
+	if encodeErr != nil {
+		return fmt.Sprintf("<unencodable: %v>", encodeErr), latency, err
+	}
+	var decoded any
+	if decodeErr := json.Unmarshal(data, &decoded); decodeErr != nil {
+		return string(data), latency, err
+	}
+	return decoded, latency, err
+}
+
+// describeError renders a recorded error as "message (at a -> b)".
+func describeError(record *goldenError) string {
+	if record == nil {
+		return ""
+	}
+	if len(record.Path) == 0 {
+		return record.Message
+	}
+	return fmt.Sprintf("%s (at %s)", record.Message, strings.Join(record.Path, " -> "))
+}
+
+// diffOutputs walks two decoded outputs and returns the fields that differ
+// beyond tolerance.
+func diffOutputs(before, after any, tolerances map[string]Tolerance) []FieldDiff {
+	var diffs []FieldDiff
+	var walk func(path []string, before, after any)
+	walk = func(path []string, before, after any) {
+		if tolerance := matchTolerance(path, tolerances); tolerance != nil {
+			if !tolerance(before, after) {
+				diffs = append(diffs, FieldDiff{Path: strings.Join(path, "."), Before: before, After: after})
+			}
+			return
+		}
+		switch b := before.(type) {
+		case map[string]any:
+			if a, ok := after.(map[string]any); ok {
+				keys := make([]string, 0, len(b)+len(a))
+				for k := range b {
+					keys = append(keys, k)
+				}
+				for k := range a {
+					if _, seen := b[k]; !seen {
+						keys = append(keys, k)
+					}
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					walk(append(path[:len(path):len(path)], k), b[k], a[k])
+				}
+				return
+			}
+		case []any:
+			if a, ok := after.([]any); ok {
+				for i := 0; i < max(len(b), len(a)); i++ {
+					var bv, av any
+					if i < len(b) {
+						bv = b[i]
+					}
+					if i < len(a) {
+						av = a[i]
+					}
+					walk(append(path[:len(path):len(path)], strconv.Itoa(i)), bv, av)
+				}
+				return
+			}
+		}
+		if !reflect.DeepEqual(before, after) {
+			diffs = append(diffs, FieldDiff{Path: strings.Join(path, "."), Before: before, After: after})
+		}
+	}
+	walk(nil, before, after)
+	return diffs
+}
+
+// matchTolerance returns the tolerance whose pattern matches path, or nil.
+func matchTolerance(path []string, tolerances map[string]Tolerance) Tolerance {
+	if len(path) == 0 {
+		return nil
+	}
+	for pattern, tolerance := range tolerances {
+		segments := strings.Split(pattern, ".")
+		if len(segments) != len(path) {
+			continue
+		}
+		matched := true
+		for i, s := range segments {
+			if s != "*" && s != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return tolerance
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/pipz"
+)
+
+type diffQuote struct {
+	QuotedAt string     `json:"quoted_at"`
+	Items    []diffItem `json:"items"`
+	Total    float64    `json:"total"`
+}
+
+type diffItem struct {
+	SKU   string  `json:"sku"`
+	Price float64 `json:"price"`
+}
+
+func TestCompareVersions(t *testing.T) {
+	id := pipz.NewIdentity("price", "")
+	corpus := map[string]diffQuote{
+		"single": {Items: []diffItem{{SKU: "a", Price: 10}}},
+		"pair":   {Items: []diffItem{{SKU: "a", Price: 10}, {SKU: "b", Price: 5}}},
+		"empty":  {},
+	}
+	price := func(rate float64, stamp string) pipz.Chainable[diffQuote] {
+		return pipz.Apply(id, func(_ context.Context, q diffQuote) (diffQuote, error) {
+			if len(q.Items) == 0 {
+				return q, errors.New("no items")
+			}
+			q.Total = 0
+			for _, item := range q.Items {
+				q.Total += item.Price * rate
+			}
+			q.QuotedAt = stamp
+			return q, nil
+		})
+	}
+
+	t.Run("Identical Versions", func(t *testing.T) {
+		report := CompareVersions(context.Background(), price(1, "x"), price(1, "x"), corpus, DiffOptions{})
+		if !report.Empty() {
+			t.Errorf("expected no differences, got:\n%s", report)
+		}
+		if len(report.Cases) != 3 || report.Cases[0].Name != "empty" {
+			t.Errorf("expected 3 cases in name order, got %+v", report.Cases)
+		}
+		if report.Cases[0].ErrorBefore != "no items (at price)" {
+			t.Errorf("expected recorded error, got %q", report.Cases[0].ErrorBefore)
+		}
+	})
+
+	t.Run("Reports Field Differences", func(t *testing.T) {
+		report := CompareVersions(context.Background(), price(1, "x"), price(1.1, "y"), corpus, DiffOptions{})
+		differing := report.Differing()
+		if len(differing) != 2 {
+			t.Fatalf("expected 2 differing cases, got:\n%s", report)
+		}
+		paths := make([]string, 0, len(differing[0].Fields))
+		for _, f := range differing[0].Fields {
+			paths = append(paths, f.Path)
+		}
+		if strings.Join(paths, ",") != "quoted_at,total" {
+			t.Errorf("expected quoted_at and total to differ, got %v", paths)
+		}
+	})
+
+	t.Run("Tolerances Relax Fields", func(t *testing.T) {
+		report := CompareVersions(context.Background(), price(1, "x"), price(1.001, "y"), corpus, DiffOptions{
+			Tolerances: map[string]Tolerance{
+				"total":     Within(0.1),
+				"quoted_at": Ignore(),
+			},
+		})
+		if !report.Empty() {
+			t.Errorf("expected differences within tolerance, got:\n%s", report)
+		}
+	})
+
+	t.Run("Wildcard Paths", func(t *testing.T) {
+		discount := pipz.Transform(id, func(_ context.Context, q diffQuote) diffQuote {
+			if q.Items == nil {
+				return q
+			}
+			items := make([]diffItem, len(q.Items))
+			for i, item := range q.Items {
+				items[i] = diffItem{SKU: item.SKU, Price: item.Price - 0.5}
+			}
+			q.Items = items
+			return q
+		})
+		same := pipz.Transform(id, func(_ context.Context, q diffQuote) diffQuote { return q })
+		report := CompareVersions(context.Background(), same, discount, corpus, DiffOptions{
+			Tolerances: map[string]Tolerance{"items.*.price": Within(1)},
+		})
+		if !report.Empty() {
+			t.Errorf("expected wildcard tolerance to cover every item, got:\n%s", report)
+		}
+	})
+
+	t.Run("Reports Error Changes", func(t *testing.T) {
+		strict := pipz.Apply(id, func(_ context.Context, q diffQuote) (diffQuote, error) {
+			return q, errors.New("rejected")
+		})
+		report := CompareVersions(context.Background(), price(1, "x"), strict, corpus, DiffOptions{})
+		if len(report.Differing()) != 3 {
+			t.Fatalf("expected every case to differ, got:\n%s", report)
+		}
+		if !strings.Contains(report.String(), "error: none => rejected (at price)") {
+			t.Errorf("expected error change in report, got:\n%s", report)
+		}
+	})
+
+	t.Run("Flags Slowdowns", func(t *testing.T) {
+		slow := pipz.Transform(id, func(_ context.Context, q diffQuote) diffQuote {
+			time.Sleep(20 * time.Millisecond)
+			return q
+		})
+		fast := pipz.Transform(id, func(_ context.Context, q diffQuote) diffQuote { return q })
+		report := CompareVersions(context.Background(), fast, slow, map[string]diffQuote{"one": {}}, DiffOptions{MaxSlowdown: 2})
+		if !report.Cases[0].Slower {
+			t.Errorf("expected slowdown to be flagged, got %+v", report.Cases[0])
+		}
+	})
+
+	t.Run("AssertSameBehavior", func(t *testing.T) {
+		rec := &recordingTB{T: t}
+		AssertSameBehavior[diffQuote](rec, price(1, "x"), price(2, "x"), corpus, DiffOptions{})
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "total: 15 => 30") {
+			t.Errorf("expected one failure naming the total, got %v", rec.errors)
+		}
+	})
+}
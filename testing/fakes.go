@@ -0,0 +1,332 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/pipz"
+)
+
+// Fake circuit breaker states, matching CircuitBreaker.GetState.
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// Fake rate limiter modes, matching RateLimiter.SetMode.
+const (
+	LimiterWait = pipz.RateLimitWait
+	LimiterDrop = pipz.RateLimitDrop
+)
+
+// ErrFakeCircuitOpen is wrapped by errors from a FakeCircuitBreaker that
+// rejects an item while open.
+var ErrFakeCircuitOpen = errors.New("circuit breaker is open")
+
+// ErrFakeRateLimited is wrapped by errors from a FakeRateLimiter in drop
+// mode that has no tokens left.
+var ErrFakeRateLimited = errors.New("rate limit exceeded")
+
+// FakeCircuitBreaker stands in for pipz.CircuitBreaker in tests of the
+// logic around a breaker. It never counts failures or watches the clock:
+// its state changes only when the test sets it or a scripted transition
+// comes due, so fallbacks, alerts, and retries can be exercised against
+// an open or half-open breaker without real thresholds or timing.
+//
+// Closed and half-open breakers run the wrapped processor; an open breaker
+// rejects the item with an error wrapping ErrFakeCircuitOpen, reported
+// like the real breaker's rejection.
+//
+// Example:
+//
+//	breaker := pipztesting.NewFakeCircuitBreaker(APIBreakerID, apiCall).
+//	    Script(pipztesting.BreakerClosed, pipztesting.BreakerOpen)
+//	pipeline := pipz.NewFallback(FallbackID, breaker, cachedResponse)
+//
+//	pipeline.Process(ctx, req) // breaker closed: calls the API
+//	pipeline.Process(ctx, req) // breaker open: served from cache
+type FakeCircuitBreaker[T any] struct {
+	processor pipz.Chainable[T]
+	identity  pipz.Identity
+	state     string
+	script    []string
+	passed    int
+	rejected  int
+	mu        sync.Mutex
+}
+
+// NewFakeCircuitBreaker creates a closed FakeCircuitBreaker wrapping
+// processor.
+func NewFakeCircuitBreaker[T any](identity pipz.Identity, processor pipz.Chainable[T]) *FakeCircuitBreaker[T] {
+	return &FakeCircuitBreaker[T]{
+		identity:  identity,
+		processor: processor,
+		state:     BreakerClosed,
+	}
+}
+
+// SetState sets the breaker's state and discards any remaining script.
+func (f *FakeCircuitBreaker[T]) SetState(state string) *FakeCircuitBreaker[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.state = state
+	f.script = nil
+	return f
+}
+
+// Script queues the state for each upcoming Process call: the first call
+// runs in states[0], the next in states[1], and so on. Once the script is
+// used up the breaker stays in its last state.
+func (f *FakeCircuitBreaker[T]) Script(states ...string) *FakeCircuitBreaker[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.script = append(f.script[:0:0], states...)
+	return f
+}
+
+// GetState returns the breaker's current state.
+func (f *FakeCircuitBreaker[T]) GetState() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.state
+}
+
+// Passed returns the number of items the breaker let through.
+func (f *FakeCircuitBreaker[T]) Passed() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.passed
+}
+
+// Rejected returns the number of items the breaker rejected while open.
+func (f *FakeCircuitBreaker[T]) Rejected() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rejected
+}
+
+// Process implements pipz.Chainable[T].
+func (f *FakeCircuitBreaker[T]) Process(ctx context.Context, data T) (T, error) {
+	f.mu.Lock()
+	if len(f.script) > 0 {
+		f.state = f.script[0]
+		f.script = f.script[1:]
+	}
+	if f.state == BreakerOpen {
+		f.rejected++
+		f.mu.Unlock()
+		return data, &pipz.Error[T]{
+			Timestamp: time.Now(),
+			InputData: pipz.RetainErrorInput(data),
+			Err:       ErrFakeCircuitOpen,
+			Path:      []pipz.Identity{f.identity},
+		}
+	}
+	f.passed++
+	processor := f.processor
+	f.mu.Unlock()
+
+	result, err := processor.Process(ctx, data)
+	return result, wrapFakeError(f.identity, data, err)
+}
+
+// Identity returns the identity of the fake breaker.
+func (f *FakeCircuitBreaker[T]) Identity() pipz.Identity {
+	return f.identity
+}
+
+// Schema returns a Node representing the fake as a circuit breaker.
+func (f *FakeCircuitBreaker[T]) Schema() pipz.Node {
+	return pipz.Node{
+		Identity: f.identity,
+		Type:     "circuitbreaker",
+		Flow:     pipz.CircuitBreakerFlow{Processor: f.processor.Schema()},
+		Metadata: map[string]any{"fake": true},
+	}
+}
+
+// Close closes the wrapped processor.
+func (f *FakeCircuitBreaker[T]) Close() error {
+	return f.processor.Close()
+}
+
+// FakeRateLimiter stands in for pipz.RateLimiter with deterministic
+// tokens: it starts with a fixed number, each item takes one, and tokens
+// are only added by Refill, never by the passage of time.
+//
+// Out of tokens, a limiter in LimiterDrop mode rejects the item with an
+// error wrapping ErrFakeRateLimited. In LimiterWait mode, the default as
+// for the real limiter, the item blocks until the test calls Refill or
+// its context ends, so tests can hold items at the limiter and release
+// them one at a time.
+//
+// Example:
+//
+//	limiter := pipztesting.NewFakeRateLimiter(LimiterID, 2, apiCall).SetMode(pipztesting.LimiterDrop)
+//	limiter.Process(ctx, req) // allowed
+//	limiter.Process(ctx, req) // allowed
+//	limiter.Process(ctx, req) // dropped
+//	limiter.Refill(1)
+//	limiter.Process(ctx, req) // allowed
+type FakeRateLimiter[T any] struct {
+	processor pipz.Chainable[T]
+	identity  pipz.Identity
+	mode      pipz.RateLimitMode
+	refilled  chan struct{}
+	tokens    int
+	allowed   int
+	dropped   int
+	mu        sync.Mutex
+}
+
+// NewFakeRateLimiter creates a FakeRateLimiter in LimiterWait mode holding
+// tokens tokens.
+func NewFakeRateLimiter[T any](identity pipz.Identity, tokens int, processor pipz.Chainable[T]) *FakeRateLimiter[T] {
+	return &FakeRateLimiter[T]{
+		identity:  identity,
+		processor: processor,
+		mode:      LimiterWait,
+		refilled:  make(chan struct{}),
+		tokens:    max(tokens, 0),
+	}
+}
+
+// SetMode sets LimiterWait or LimiterDrop behavior when out of tokens.
+func (f *FakeRateLimiter[T]) SetMode(mode pipz.RateLimitMode) *FakeRateLimiter[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mode = mode
+	return f
+}
+
+// GetMode returns the limiter's mode.
+func (f *FakeRateLimiter[T]) GetMode() pipz.RateLimitMode {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mode
+}
+
+// Refill adds n tokens, releasing waiting items.
+func (f *FakeRateLimiter[T]) Refill(n int) *FakeRateLimiter[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens += max(n, 0)
+	close(f.refilled)
+	f.refilled = make(chan struct{})
+	return f
+}
+
+// SetTokens replaces the remaining tokens.
+func (f *FakeRateLimiter[T]) SetTokens(n int) *FakeRateLimiter[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens = max(n, 0)
+	close(f.refilled)
+	f.refilled = make(chan struct{})
+	return f
+}
+
+// Tokens returns the number of tokens left.
+func (f *FakeRateLimiter[T]) Tokens() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tokens
+}
+
+// Allowed returns the number of items that took a token.
+func (f *FakeRateLimiter[T]) Allowed() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.allowed
+}
+
+// Dropped returns the number of items rejected in LimiterDrop mode.
+func (f *FakeRateLimiter[T]) Dropped() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.dropped
+}
+
+// Process implements pipz.Chainable[T].
+func (f *FakeRateLimiter[T]) Process(ctx context.Context, data T) (T, error) {
+	for {
+		f.mu.Lock()
+		if f.tokens > 0 {
+			f.tokens--
+			f.allowed++
+			processor := f.processor
+			f.mu.Unlock()
+			result, err := processor.Process(ctx, data)
+			return result, wrapFakeError(f.identity, data, err)
+		}
+		if f.mode != LimiterWait {
+			f.dropped++
+			f.mu.Unlock()
+			return data, &pipz.Error[T]{
+				Timestamp: time.Now(),
+				InputData: pipz.RetainErrorInput(data),
+				Err:       ErrFakeRateLimited,
+				Path:      []pipz.Identity{f.identity},
+			}
+		}
+		refilled := f.refilled
+		f.mu.Unlock()
+
+		select {
+		case <-refilled:
+		case <-ctx.Done():
+			return data, &pipz.Error[T]{
+				Timestamp: time.Now(),
+				InputData: pipz.RetainErrorInput(data),
+				Err:       ctx.Err(),
+				Path:      []pipz.Identity{f.identity},
+				Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
+				Canceled:  errors.Is(ctx.Err(), context.Canceled),
+			}
+		}
+	}
+}
+
+// Identity returns the identity of the fake limiter.
+func (f *FakeRateLimiter[T]) Identity() pipz.Identity {
+	return f.identity
+}
+
+// Schema returns a Node representing the fake as a rate limiter.
+func (f *FakeRateLimiter[T]) Schema() pipz.Node {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return pipz.Node{
+		Identity: f.identity,
+		Type:     "ratelimiter",
+		Flow:     pipz.RateLimiterFlow{Processor: f.processor.Schema()},
+		Metadata: map[string]any{"fake": true, "mode": string(f.mode)},
+	}
+}
+
+// Close closes the wrapped processor.
+func (f *FakeRateLimiter[T]) Close() error {
+	return f.processor.Close()
+}
+
+// wrapFakeError places identity at the head of err's path, as the real
+// connectors do.
+func wrapFakeError[T any](identity pipz.Identity, data T, err error) error {
+	if err == nil {
+		return nil
+	}
+	var pipeErr *pipz.Error[T]
+	if errors.As(err, &pipeErr) {
+		pipeErr.Path = append([]pipz.Identity{identity}, pipeErr.Path...)
+		return pipeErr
+	}
+	return &pipz.Error[T]{
+		Timestamp: time.Now(),
+		InputData: pipz.RetainErrorInput(data),
+		Err:       err,
+		Path:      []pipz.Identity{identity},
+	}
+}
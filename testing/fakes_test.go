@@ -0,0 +1,161 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/pipz"
+)
+
+func TestFakeCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	double := pipz.Transform(pipz.NewIdentity("double", ""), func(_ context.Context, n int) int { return n * 2 })
+
+	t.Run("Closed Passes Through", func(t *testing.T) {
+		breaker := NewFakeCircuitBreaker(pipz.NewIdentity("breaker", ""), double)
+		if got, err := breaker.Process(ctx, 2); err != nil || got != 4 {
+			t.Errorf("got %d, %v", got, err)
+		}
+		if breaker.Passed() != 1 || breaker.GetState() != BreakerClosed {
+			t.Errorf("passed %d, state %s", breaker.Passed(), breaker.GetState())
+		}
+	})
+
+	t.Run("Open Rejects", func(t *testing.T) {
+		breaker := NewFakeCircuitBreaker(pipz.NewIdentity("breaker", ""), double).SetState(BreakerOpen)
+		_, err := breaker.Process(ctx, 2)
+		if !errors.Is(err, ErrFakeCircuitOpen) {
+			t.Fatalf("expected ErrFakeCircuitOpen, got %v", err)
+		}
+		var pipeErr *pipz.Error[int]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[0].Name() != "breaker" {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if breaker.Rejected() != 1 || breaker.Passed() != 0 {
+			t.Errorf("rejected %d, passed %d", breaker.Rejected(), breaker.Passed())
+		}
+	})
+
+	t.Run("Open Applies Retention Policy", func(t *testing.T) {
+		pipz.SetRetentionPolicy(pipz.RetentionPolicy{OmitInput: true})
+		defer pipz.SetRetentionPolicy(pipz.RetentionPolicy{})
+		breaker := NewFakeCircuitBreaker(pipz.NewIdentity("breaker", ""), double).SetState(BreakerOpen)
+		_, err := breaker.Process(ctx, 2)
+		var pipeErr *pipz.Error[int]
+		if !errors.As(err, &pipeErr) || pipeErr.InputData != 0 {
+			t.Errorf("expected the input omitted, got %v", err)
+		}
+	})
+
+	t.Run("Scripted Transitions Drive Fallback", func(t *testing.T) {
+		breaker := NewFakeCircuitBreaker(pipz.NewIdentity("breaker", ""), double).
+			Script(BreakerClosed, BreakerOpen, BreakerHalfOpen)
+		cached := pipz.Transform(pipz.NewIdentity("cached", ""), func(_ context.Context, _ int) int { return -1 })
+		pipeline := pipz.NewFallback(pipz.NewIdentity("fallback", ""), breaker, cached)
+
+		var got []int
+		for i := 0; i < 4; i++ {
+			v, err := pipeline.Process(ctx, 3)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got = append(got, v)
+		}
+		if got[0] != 6 || got[1] != -1 || got[2] != 6 || got[3] != 6 {
+			t.Errorf("results = %v", got)
+		}
+		if breaker.GetState() != BreakerHalfOpen {
+			t.Errorf("expected breaker to stay in its last scripted state, got %s", breaker.GetState())
+		}
+	})
+
+	t.Run("Processor Error Path", func(t *testing.T) {
+		failing := pipz.Apply(pipz.NewIdentity("call", ""), func(_ context.Context, n int) (int, error) {
+			return n, errors.New("down")
+		})
+		breaker := NewFakeCircuitBreaker(pipz.NewIdentity("breaker", ""), failing)
+		_, err := breaker.Process(ctx, 1)
+		var pipeErr *pipz.Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "breaker" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		node := NewFakeCircuitBreaker(pipz.NewIdentity("breaker", ""), double).Schema()
+		if flow, ok := pipz.CircuitBreakerKey.From(node); !ok || flow.Processor.Identity.Name() != "double" {
+			t.Errorf("unexpected schema: %+v", node)
+		}
+	})
+}
+
+func TestFakeRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	echo := pipz.Transform(pipz.NewIdentity("echo", ""), func(_ context.Context, n int) int { return n })
+
+	t.Run("Drop Mode", func(t *testing.T) {
+		limiter := NewFakeRateLimiter(pipz.NewIdentity("limiter", ""), 2, echo).SetMode(LimiterDrop)
+		for i := 0; i < 2; i++ {
+			if _, err := limiter.Process(ctx, i); err != nil {
+				t.Fatalf("call %d: unexpected error: %v", i, err)
+			}
+		}
+		if _, err := limiter.Process(ctx, 3); !errors.Is(err, ErrFakeRateLimited) {
+			t.Fatalf("expected ErrFakeRateLimited, got %v", err)
+		}
+		limiter.Refill(1)
+		if _, err := limiter.Process(ctx, 4); err != nil {
+			t.Errorf("unexpected error after refill: %v", err)
+		}
+		if limiter.Allowed() != 3 || limiter.Dropped() != 1 || limiter.Tokens() != 0 {
+			t.Errorf("allowed %d, dropped %d, tokens %d", limiter.Allowed(), limiter.Dropped(), limiter.Tokens())
+		}
+	})
+
+	t.Run("Wait Mode Blocks Until Refill", func(t *testing.T) {
+		limiter := NewFakeRateLimiter(pipz.NewIdentity("limiter", ""), 0, echo)
+		done := make(chan error, 1)
+		go func() {
+			_, err := limiter.Process(ctx, 1)
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			t.Fatalf("returned before refill: %v", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+		limiter.Refill(1)
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("not released by refill")
+		}
+	})
+
+	t.Run("Wait Mode Honors Context", func(t *testing.T) {
+		limiter := NewFakeRateLimiter(pipz.NewIdentity("limiter", ""), 0, echo)
+		cctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+		defer cancel()
+		_, err := limiter.Process(cctx, 1)
+		var pipeErr *pipz.Error[int]
+		if !errors.As(err, &pipeErr) || !pipeErr.Timeout {
+			t.Errorf("expected timeout error, got %v", err)
+		}
+	})
+
+	t.Run("Set Tokens", func(t *testing.T) {
+		limiter := NewFakeRateLimiter(pipz.NewIdentity("limiter", ""), 5, echo).SetTokens(1)
+		if limiter.Tokens() != 1 || limiter.GetMode() != LimiterWait {
+			t.Errorf("tokens %d, mode %s", limiter.Tokens(), limiter.GetMode())
+		}
+		if node := limiter.Schema(); node.Type != "ratelimiter" {
+			t.Errorf("unexpected schema type %q", node.Type)
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package testing
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/pipz"
+)
+
+// Fault decides whether an execution of a faulted node fails. It is called
+// before the node runs; a non-nil error fails the node without running it.
+type Fault func(ctx context.Context) error
+
+// FailNTimes fails the first n executions with err, then lets the node run.
+func FailNTimes(n int, err error) Fault {
+	var calls atomic.Int64
+	return func(context.Context) error {
+		if calls.Add(1) <= int64(n) {
+			return err
+		}
+		return nil
+	}
+}
+
+// FailAlways fails every execution with err.
+func FailAlways(err error) Fault {
+	return func(context.Context) error {
+		return err
+	}
+}
+
+// Delay holds every execution for d before the node runs, failing with the
+// context's error if it ends first.
+func Delay(d time.Duration) Fault {
+	return func(ctx context.Context) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// injectedFault is one registered fault and the path pattern it targets.
+type injectedFault struct {
+	fault    Fault
+	segments []string
+}
+
+// faults is the registry consulted by the installed pipz.FaultHook.
+var faults struct {
+	entries []*injectedFault
+	mu      sync.Mutex
+}
+
+// InjectFault fails the nodes matching path according to fault, letting
+// end-to-end tests exercise the failure handling of a fully assembled
+// production pipeline without changing how it is constructed. It returns a
+// function that removes the fault.
+//
+// The path is a slash-separated list of identity names. Its last name must
+// be the node's own; any earlier names must be ancestors of the node, in
+// order, but need not be adjacent. "http-call" targets every node named
+// http-call, while "payments/http-call" targets only those inside a
+// pipeline or connector named payments. The failure is reported as an
+// *pipz.Error whose Path ends at the faulted node, just as if it had
+// failed on its own.
+//
+// Faults are process-wide: tests that inject them must not run in parallel
+// with tests processing the same pipelines.
+//
+// Example:
+//
+//	func TestPaymentRetries(t *testing.T) {
+//	    remove := pipztesting.InjectFault("payments/http-call",
+//	        pipztesting.FailNTimes(2, errors.New("connection reset")))
+//	    defer remove()
+//
+//	    _, err := payments.Process(ctx, order) // succeeds on the third attempt
+//	    require.NoError(t, err)
+//	}
+func InjectFault(path string, fault Fault) (remove func()) {
+	entry := &injectedFault{fault: fault, segments: strings.Split(path, "/")}
+
+	faults.mu.Lock()
+	faults.entries = append(faults.entries, entry)
+	faults.mu.Unlock()
+	syncHook()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			faults.mu.Lock()
+			for i, e := range faults.entries {
+				if e == entry {
+					faults.entries = append(faults.entries[:i:i], faults.entries[i+1:]...)
+					break
+				}
+			}
+			faults.mu.Unlock()
+			syncHook()
+		})
+	}
+}
+
+// ClearFaults removes every injected fault.
+func ClearFaults() {
+	faults.mu.Lock()
+	faults.entries = nil
+	faults.mu.Unlock()
+	syncHook()
+}
+
+// hookMu serializes installing and removing the shared pipz.FaultHook.
+var hookMu sync.Mutex
+
+// syncHook installs the pipz.FaultHook shared by injected faults and
+// coverage trackers while either is active, and removes it otherwise.
+func syncHook() {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+
+	faults.mu.Lock()
+	active := len(faults.entries) > 0
+	faults.mu.Unlock()
+	coverage.mu.Lock()
+	active = active || len(coverage.trackers) > 0
+	coverage.mu.Unlock()
+
+	if active {
+		pipz.SetFaultHook(consultHook)
+	} else {
+		pipz.SetFaultHook(nil)
+	}
+}
+
+// consultHook is the pipz.FaultHook backing InjectFault and TrackCoverage.
+// Coverage records every node about to run, including those that faults
+// then fail.
+func consultHook(ctx context.Context, path []pipz.Identity) error {
+	recordCoverage(ctx, path)
+	return consultFaults(ctx, path)
+}
+
+// consultFaults consults the faults injected with InjectFault. Matching faults
+// are consulted in the order they were injected; the first failure wins.
+func consultFaults(ctx context.Context, path []pipz.Identity) error {
+	faults.mu.Lock()
+	var matched []Fault
+	for _, e := range faults.entries {
+		if matchFaultPath(e.segments, path) {
+			matched = append(matched, e.fault)
+		}
+	}
+	faults.mu.Unlock()
+
+	for _, fault := range matched {
+		if err := fault(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchFaultPath reports whether segments targets the last node of path.
+func matchFaultPath(segments []string, path []pipz.Identity) bool {
+	last := len(segments) - 1
+	if len(path) == 0 || path[len(path)-1].Name() != segments[last] {
+		return false
+	}
+	i := len(path) - 2
+	for s := last - 1; s >= 0; s-- {
+		for i >= 0 && path[i].Name() != segments[s] {
+			i--
+		}
+		if i < 0 {
+			return false
+		}
+		i--
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/pipz"
+)
+
+func TestInjectFault(t *testing.T) {
+	injected := errors.New("connection reset")
+	newPayments := func(calls *int) *pipz.Pipeline[int] {
+		call := pipz.Apply(pipz.NewIdentity("http-call", ""), func(_ context.Context, v int) (int, error) {
+			*calls++
+			return v, nil
+		})
+		retry := pipz.NewRetry(pipz.NewIdentity("retry", ""), call, 3)
+		return pipz.NewPipeline(pipz.NewIdentity("payments", ""), pipz.NewSequence(pipz.NewIdentity("steps", ""), retry))
+	}
+
+	t.Run("Fail N Times Then Recover", func(t *testing.T) {
+		remove := InjectFault("payments/http-call", FailNTimes(2, injected))
+		defer remove()
+
+		var calls int
+		if _, err := newPayments(&calls).Process(context.Background(), 1); err != nil {
+			t.Fatalf("expected retry to recover, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected the processor to run once after two faults, got %d", calls)
+		}
+	})
+
+	t.Run("Fail Always Surfaces Error", func(t *testing.T) {
+		remove := InjectFault("http-call", FailAlways(injected))
+		defer remove()
+
+		var calls int
+		_, err := newPayments(&calls).Process(context.Background(), 1)
+		if !errors.Is(err, injected) {
+			t.Fatalf("expected injected error, got %v", err)
+		}
+		var pipeErr *pipz.Error[int]
+		if !errors.As(err, &pipeErr) || pipeErr.Path[len(pipeErr.Path)-1].Name() != "http-call" {
+			t.Errorf("expected path ending at http-call, got %v", err)
+		}
+		if calls != 0 {
+			t.Errorf("expected no real calls, got %d", calls)
+		}
+	})
+
+	t.Run("Unmatched Ancestor Does Not Fault", func(t *testing.T) {
+		remove := InjectFault("billing/http-call", FailAlways(injected))
+		defer remove()
+
+		var calls int
+		if _, err := newPayments(&calls).Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Remove Disables Fault", func(t *testing.T) {
+		remove := InjectFault("http-call", FailAlways(injected))
+		remove()
+		remove()
+
+		var calls int
+		if _, err := newPayments(&calls).Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error after remove: %v", err)
+		}
+	})
+
+	t.Run("Clear Faults", func(t *testing.T) {
+		InjectFault("http-call", FailAlways(injected))
+		InjectFault("steps", FailAlways(injected))
+		ClearFaults()
+
+		var calls int
+		if _, err := newPayments(&calls).Process(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error after clear: %v", err)
+		}
+	})
+
+	t.Run("Delay Respects Context", func(t *testing.T) {
+		remove := InjectFault("http-call", Delay(time.Second))
+		defer remove()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		var calls int
+		_, err := newPayments(&calls).Process(ctx, 1)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+	})
+}
+
+func TestMatchFaultPath(t *testing.T) {
+	path := []pipz.Identity{
+		pipz.NewIdentity("payments", ""),
+		pipz.NewIdentity("steps", ""),
+		pipz.NewIdentity("http-call", ""),
+	}
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"http-call", true},
+		{"payments/http-call", true},
+		{"payments/steps/http-call", true},
+		{"steps/payments/http-call", false},
+		{"payments", false},
+		{"billing/http-call", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := matchFaultPath(strings.Split(tt.pattern, "/"), path); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
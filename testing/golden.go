@@ -0,0 +1,177 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/zoobzio/pipz"
+)
+
+// UpdateGoldenEnv names the environment variable that, when set to a
+// non-empty value, rewrites golden files instead of verifying them. The
+// -pipz.update-golden test flag does the same.
+const UpdateGoldenEnv = "PIPZ_UPDATE_GOLDEN"
+
+var updateGolden = flag.Bool("pipz.update-golden", false, "rewrite pipz golden files with current processor output")
+
+// goldenUnsafe matches characters not allowed in golden file names.
+var goldenUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// goldenRecord is the on-disk form of one golden case.
+type goldenRecord struct {
+	Output any          `json:"output"`
+	Error  *goldenError `json:"error,omitempty"`
+}
+
+// goldenError records a failure without timing details, so golden files
+// stay stable between runs.
+type goldenError struct {
+	Message string   `json:"message"`
+	Path    []string `json:"path,omitempty"`
+}
+
+// Golden runs each input in cases through processor and compares the
+// output (and any error) with golden files under testdata/golden/<test>.
+// Run the tests with -pipz.update-golden, or with PIPZ_UPDATE_GOLDEN set,
+// to record the current behavior; later runs fail on any difference,
+// turning a corpus of inputs into a contract test for the processor.
+//
+// Outputs are stored as indented JSON, so T must be JSON-encodable. Errors
+// are recorded by message and path, without durations or timestamps.
+//
+// Example:
+//
+//	func TestNormalizeContract(t *testing.T) {
+//	    pipztesting.Golden(t, normalize, map[string]User{
+//	        "mixed-case-email": {Email: "Ann@Example.COM"},
+//	        "missing-name":     {Email: "bob@example.com"},
+//	    })
+//	}
+func Golden[T any](t testing.TB, processor pipz.Chainable[T], cases map[string]T) {
+	t.Helper()
+	GoldenDir(t, filepath.Join("testdata", "golden", goldenUnsafe.ReplaceAllString(t.Name(), "_")), processor, cases)
+}
+
+// GoldenDir is Golden with an explicit directory for the golden files.
+func GoldenDir[T any](t testing.TB, dir string, processor pipz.Chainable[T], cases map[string]T) {
+	t.Helper()
+	update := *updateGolden || os.Getenv(UpdateGoldenEnv) != ""
+
+	names := make([]string, 0, len(cases))
+	for name := range cases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		got, err := goldenOutput(context.Background(), processor, cases[name])
+		if err != nil {
+			t.Errorf("golden %s: encode output: %v", name, err)
+			continue
+		}
+		path := filepath.Join(dir, goldenUnsafe.ReplaceAllString(name, "_")+".golden")
+
+		if update {
+			if mkErr := os.MkdirAll(dir, 0o750); mkErr != nil {
+				t.Fatalf("golden %s: %v", name, mkErr)
+			}
+			if writeErr := os.WriteFile(path, got, 0o600); writeErr != nil {
+				t.Fatalf("golden %s: %v", name, writeErr)
+			}
+			continue
+		}
+
+		want, err := os.ReadFile(path) //nolint:gosec // path is built from the test's own golden directory
+		if errors.Is(err, os.ErrNotExist) {
+			t.Errorf("golden %s: missing %s (run with -pipz.update-golden to record it)", name, path)
+			continue
+		}
+		if err != nil {
+			t.Errorf("golden %s: %v", name, err)
+			continue
+		}
+		if !bytes.Equal(bytes.TrimSpace(want), bytes.TrimSpace(got)) {
+			t.Errorf("golden %s: output differs from %s\n--- want\n%s\n--- got\n%s", name, path, want, got)
+		}
+	}
+}
+
+// goldenOutput processes input and encodes the result as a golden record.
+func goldenOutput[T any](ctx context.Context, processor pipz.Chainable[T], input T) ([]byte, error) {
+	output, err := processor.Process(ctx, input)
+	record := goldenRecord{Output: output, Error: recordError[T](err)}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// recordError reduces err to its message and path names, or nil if err is
+// nil.
+func recordError[T any](err error) *goldenError {
+	if err == nil {
+		return nil
+	}
+	record := &goldenError{Message: err.Error()}
+	var pipeErr *pipz.Error[T]
+	if errors.As(err, &pipeErr) {
+		record.Message = fmt.Sprint(pipeErr.Err)
+		for _, id := range pipeErr.Path {
+			record.Path = append(record.Path, id.Name())
+		}
+	}
+	return record
+}
+
+// Fuzz wires Go's native fuzzing into a processor. Fuzz inputs are decoded
+// as JSON into T, so seeds and the fuzzer explore structured values; inputs
+// that do not decode are skipped. A processor panic (reported by pipz as an
+// error wrapping pipz.ErrPanic) fails the fuzz case.
+//
+// Example:
+//
+//	func FuzzNormalize(f *testing.F) {
+//	    pipztesting.Fuzz(f, normalize, User{Email: "a@b.c"}, User{})
+//	}
+func Fuzz[T any](f *testing.F, processor pipz.Chainable[T], seeds ...T) {
+	f.Helper()
+	FuzzCheck(f, processor, nil, seeds...)
+}
+
+// FuzzCheck is Fuzz with an invariant check run on every fuzz case, such as
+// "output is idempotent" or "errors never carry an empty path". check may
+// be nil.
+func FuzzCheck[T any](f *testing.F, processor pipz.Chainable[T], check func(t *testing.T, input, output T, err error), seeds ...T) {
+	f.Helper()
+	for _, seed := range seeds {
+		data, err := json.Marshal(seed)
+		if err != nil {
+			f.Fatalf("encode fuzz seed: %v", err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var input T
+		if err := json.Unmarshal(data, &input); err != nil {
+			t.Skip()
+		}
+		output, err := processor.Process(context.Background(), input)
+		if errors.Is(err, pipz.ErrPanic) {
+			t.Fatalf("processor panicked on %s: %v", data, err)
+		}
+		if check != nil {
+			check(t, input, output, err)
+		}
+	})
+}
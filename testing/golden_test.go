@@ -0,0 +1,106 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/pipz"
+)
+
+// recordingTB captures Errorf calls so golden mismatches can be asserted.
+type recordingTB struct {
+	*testing.T
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type goldenUser struct {
+	Email string `json:"email"`
+}
+
+func TestGolden(t *testing.T) {
+	normalize := pipz.Apply(pipz.NewIdentity("normalize", ""), func(_ context.Context, u goldenUser) (goldenUser, error) {
+		if u.Email == "" {
+			return u, errors.New("email required")
+		}
+		u.Email = strings.ToLower(u.Email)
+		return u, nil
+	})
+	cases := map[string]goldenUser{
+		"mixed case": {Email: "Ann@Example.COM"},
+		"missing":    {},
+	}
+
+	t.Run("Records Then Verifies", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv(UpdateGoldenEnv, "1")
+		GoldenDir(t, dir, normalize, cases)
+
+		data, err := os.ReadFile(filepath.Join(dir, "mixed_case.golden"))
+		if err != nil {
+			t.Fatalf("golden file not written: %v", err)
+		}
+		if !strings.Contains(string(data), `"email": "ann@example.com"`) {
+			t.Errorf("unexpected golden content: %s", data)
+		}
+		data, err = os.ReadFile(filepath.Join(dir, "missing.golden"))
+		if err != nil || !strings.Contains(string(data), `"message": "email required"`) || !strings.Contains(string(data), `"normalize"`) {
+			t.Errorf("unexpected error record: %s, %v", data, err)
+		}
+
+		t.Setenv(UpdateGoldenEnv, "")
+		rec := &recordingTB{T: t}
+		GoldenDir(rec, dir, normalize, cases)
+		if len(rec.errors) != 0 {
+			t.Errorf("expected golden files to match, got %v", rec.errors)
+		}
+	})
+
+	t.Run("Reports Differences", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv(UpdateGoldenEnv, "1")
+		GoldenDir(t, dir, normalize, cases)
+		t.Setenv(UpdateGoldenEnv, "")
+
+		upper := pipz.Transform(pipz.NewIdentity("normalize", ""), func(_ context.Context, u goldenUser) goldenUser {
+			u.Email = strings.ToUpper(u.Email)
+			return u
+		})
+		rec := &recordingTB{T: t}
+		GoldenDir(rec, dir, upper, map[string]goldenUser{"mixed case": cases["mixed case"]})
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "output differs") {
+			t.Errorf("expected one difference, got %v", rec.errors)
+		}
+	})
+
+	t.Run("Missing Golden File", func(t *testing.T) {
+		rec := &recordingTB{T: t}
+		GoldenDir(rec, t.TempDir(), normalize, cases)
+		if len(rec.errors) != 2 || !strings.Contains(rec.errors[0], "pipz.update-golden") {
+			t.Errorf("expected missing file errors, got %v", rec.errors)
+		}
+	})
+}
+
+func FuzzGoldenNormalize(f *testing.F) {
+	normalize := pipz.Transform(pipz.NewIdentity("normalize", ""), func(_ context.Context, u goldenUser) goldenUser {
+		u.Email = strings.ToLower(u.Email)
+		return u
+	})
+	FuzzCheck(f, normalize, func(t *testing.T, _, output goldenUser, err error) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output.Email != strings.ToLower(output.Email) {
+			t.Errorf("output not normalized: %q", output.Email)
+		}
+	}, goldenUser{Email: "A@B.C"}, goldenUser{})
+}
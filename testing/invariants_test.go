@@ -0,0 +1,112 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"math"
+	mathrand "math/rand"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/pipz"
+)
+
+type invariantOrder struct {
+	Tags     []string
+	Subtotal float64
+	Total    float64
+}
+
+func (o invariantOrder) Clone() invariantOrder {
+	o.Tags = append([]string(nil), o.Tags...)
+	return o
+}
+
+func genOrder(r *mathrand.Rand) invariantOrder {
+	return invariantOrder{Subtotal: math.Round(r.Float64() * 1000), Tags: []string{"new"}}
+}
+
+func TestCheckInvariants(t *testing.T) {
+	price := pipz.Transform(pipz.NewIdentity("price", ""), func(_ context.Context, o invariantOrder) invariantOrder {
+		o.Total = o.Subtotal * 1.2
+		return o
+	})
+
+	t.Run("Holding Invariants Pass", func(t *testing.T) {
+		rec := &recordingTB{T: t}
+		CheckInvariants(rec, price, genOrder, 200,
+			Idempotent(price),
+			Monotonic(func(o invariantOrder) float64 { return o.Total }),
+			Preserves("subtotal", func(o invariantOrder) float64 { return o.Subtotal }),
+			NoInputMutation(price),
+		)
+		if len(rec.errors) != 0 {
+			t.Errorf("unexpected violations: %v", rec.errors)
+		}
+	})
+
+	t.Run("Detects Non Idempotent Processor", func(t *testing.T) {
+		compound := pipz.Transform(pipz.NewIdentity("compound", ""), func(_ context.Context, o invariantOrder) invariantOrder {
+			o.Subtotal *= 1.2
+			return o
+		})
+		rec := &recordingTB{T: t}
+		CheckInvariants(rec, compound, func(*mathrand.Rand) invariantOrder { return invariantOrder{Subtotal: 10} }, 10, Idempotent(compound))
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "not idempotent") || !strings.Contains(rec.errors[0], InvariantSeedEnv) {
+			t.Errorf("expected one idempotency violation, got %v", rec.errors)
+		}
+	})
+
+	t.Run("Detects Input Mutation", func(t *testing.T) {
+		tag := pipz.Transform(pipz.NewIdentity("tag", ""), func(_ context.Context, o invariantOrder) invariantOrder {
+			o.Tags[0] = "seen"
+			return o
+		})
+		rec := &recordingTB{T: t}
+		CheckInvariants(rec, tag, genOrder, 5, NoInputMutation(tag))
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "mutated its input") {
+			t.Errorf("expected mutation violation, got %v", rec.errors)
+		}
+	})
+
+	t.Run("Detects Decrease", func(t *testing.T) {
+		discount := pipz.Transform(pipz.NewIdentity("discount", ""), func(_ context.Context, o invariantOrder) invariantOrder {
+			o.Total = -1
+			return o
+		})
+		rec := &recordingTB{T: t}
+		CheckInvariants(rec, discount, genOrder, 5, Monotonic(func(o invariantOrder) float64 { return o.Total }))
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "not monotonic") {
+			t.Errorf("expected monotonicity violation, got %v", rec.errors)
+		}
+	})
+
+	t.Run("Seed Replays Inputs", func(t *testing.T) {
+		t.Setenv(InvariantSeedEnv, "42")
+		var first, second []float64
+		record := func(dst *[]float64) pipz.Chainable[invariantOrder] {
+			return pipz.Effect(pipz.NewIdentity("record", ""), func(_ context.Context, o invariantOrder) error {
+				*dst = append(*dst, o.Subtotal)
+				return nil
+			})
+		}
+		CheckInvariants(t, record(&first), genOrder, 5)
+		CheckInvariants(t, record(&second), genOrder, 5)
+		for i := range first {
+			if first[i] != second[i] {
+				t.Fatalf("inputs differ at %d: %v vs %v", i, first, second)
+			}
+		}
+	})
+
+	t.Run("All Inputs Failing Is Reported", func(t *testing.T) {
+		fail := pipz.Apply(pipz.NewIdentity("fail", ""), func(_ context.Context, o invariantOrder) (invariantOrder, error) {
+			return o, errors.New("rejected")
+		})
+		rec := &recordingTB{T: t}
+		CheckInvariants(rec, fail, genOrder, 3)
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "all 3 generated inputs failed") {
+			t.Errorf("expected failure report, got %v", rec.errors)
+		}
+	})
+}
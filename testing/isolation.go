@@ -0,0 +1,33 @@
+package testing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zoobzio/pipz"
+)
+
+// AssertIsolated fails the test if processing a clone of input mutates
+// input itself. It catches shallow Clone methods that share slices, maps,
+// or pointers with the original, which Concurrent, Race, Contest, and Group
+// rely on to keep parallel processors apart. Run it with each processor
+// that will sit inside a fan-out connector, using an input that populates
+// every reference field.
+//
+// For whole pipelines, enable the same check at runtime with
+// pipz.Policy{CheckIsolation: true}.
+//
+// Example:
+//
+//	pipztesting.AssertIsolated(t, enrichOrder, Order{
+//	    Items:    []Item{{SKU: "A1"}},
+//	    Metadata: map[string]string{"source": "web"},
+//	})
+func AssertIsolated[T pipz.Cloner[T]](t testing.TB, processor pipz.Chainable[T], input T) {
+	t.Helper()
+	before := pipz.DeepHash(input)
+	_, _ = processor.Process(context.Background(), input.Clone()) //nolint:errcheck // only the input's state matters
+	if pipz.DeepHash(input) != before {
+		t.Errorf("%s mutated the original input through its clone; %T.Clone shares mutable state", processor.Identity().Name(), input)
+	}
+}
@@ -0,0 +1,47 @@
+package testing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/pipz"
+)
+
+type sharedTags struct {
+	Tags []string
+}
+
+func (s sharedTags) Clone() sharedTags { return s }
+
+type copiedTags struct {
+	Tags []string
+}
+
+func (c copiedTags) Clone() copiedTags { return copiedTags{Tags: append([]string(nil), c.Tags...)} }
+
+func TestAssertIsolated(t *testing.T) {
+	t.Run("Detects Shared State", func(t *testing.T) {
+		mark := pipz.Transform(pipz.NewIdentity("mark", ""), func(_ context.Context, s sharedTags) sharedTags {
+			s.Tags[0] = "marked"
+			return s
+		})
+		rec := &recordingTB{T: t}
+		AssertIsolated(rec, mark, sharedTags{Tags: []string{"new"}})
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "mark mutated the original input") {
+			t.Errorf("expected isolation failure, got %v", rec.errors)
+		}
+	})
+
+	t.Run("Passes Deep Clone", func(t *testing.T) {
+		mark := pipz.Transform(pipz.NewIdentity("mark", ""), func(_ context.Context, c copiedTags) copiedTags {
+			c.Tags[0] = "marked"
+			return c
+		})
+		rec := &recordingTB{T: t}
+		AssertIsolated(rec, mark, copiedTags{Tags: []string{"new"}})
+		if len(rec.errors) != 0 {
+			t.Errorf("unexpected failure: %v", rec.errors)
+		}
+	})
+}
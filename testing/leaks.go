@@ -0,0 +1,176 @@
+package testing
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// defaultLeakIgnores are goroutines that outlive any single test by design.
+var defaultLeakIgnores = []string{
+	"github.com/zoobzio/capitan.(*Capitan).processEvents", // per-signal event workers
+	"testing.(*T).Run",
+	"testing.tRunner",
+	"testing.(*F).Fuzz",
+	"os/signal.signal_recv",
+	"runtime.ensureSigM",
+}
+
+// leakHints explain leaks in pipz connectors.
+var leakHints = []struct {
+	frame string
+	hint  string
+}{
+	{"pipz.(*Race[", "Race loser still running: processors must return when their context is canceled"},
+	{"pipz.(*Contest[", "Contest loser still running: processors must return when their context is canceled"},
+	{"pipz.(*Scaffold[", "Scaffold worker still running: fire-and-forget processors should honor their timeout"},
+	{"pipz.(*WorkerPool[", "WorkerPool task still running: close the pool or bound tasks with WithTaskTimeout"},
+	{"pipz.(*Concurrent[", "Concurrent processor still running after cancellation"},
+	{"pipz.(*Timeout[", "processor kept running after its Timeout fired: it must honor ctx.Done()"},
+}
+
+// LeakOption configures VerifyNoLeaks.
+type LeakOption func(*leakConfig)
+
+type leakConfig struct {
+	ignores []string
+	timeout time.Duration
+}
+
+// IgnoreLeaks allows goroutines whose stack contains any of the given
+// substrings, such as a function name of a long-lived background worker.
+func IgnoreLeaks(substrings ...string) LeakOption {
+	return func(c *leakConfig) {
+		c.ignores = append(c.ignores, substrings...)
+	}
+}
+
+// LeakTimeout sets how long VerifyNoLeaks waits for goroutines to exit
+// before reporting them. The default is one second.
+func LeakTimeout(d time.Duration) LeakOption {
+	return func(c *leakConfig) {
+		c.timeout = d
+	}
+}
+
+// VerifyNoLeaks snapshots the running goroutines and, when the test
+// finishes, fails it if new goroutines are still running. Goroutines get a
+// grace period to exit, since Race losers and canceled workers unwind
+// asynchronously. Leaks inside pipz connectors are annotated with the
+// likely cause, such as a Race processor that ignores cancellation.
+//
+// Call it first in a test so its cleanup runs after everything else.
+//
+// Example:
+//
+//	func TestSearchCancels(t *testing.T) {
+//	    pipztesting.VerifyNoLeaks(t)
+//	    ctx, cancel := context.WithCancel(context.Background())
+//	    cancel()
+//	    _, _ = search.Process(ctx, query)
+//	}
+func VerifyNoLeaks(t testing.TB, opts ...LeakOption) {
+	t.Helper()
+	cfg := leakConfig{timeout: time.Second}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.ignores = append(cfg.ignores, defaultLeakIgnores...)
+
+	before := make(map[int]bool)
+	for _, g := range goroutines() {
+		before[g.id] = true
+	}
+
+	t.Cleanup(func() {
+		deadline := time.Now().Add(cfg.timeout)
+		for {
+			leaked := leakedGoroutines(before, cfg.ignores)
+			if len(leaked) == 0 {
+				return
+			}
+			if time.Now().After(deadline) {
+				var report strings.Builder
+				for _, g := range leaked {
+					report.WriteString("\n\n")
+					if hint := leakHint(g.stack); hint != "" {
+						report.WriteString(hint)
+						report.WriteString("\n")
+					}
+					report.WriteString(g.stack)
+				}
+				t.Errorf("%d goroutine(s) leaked:%s", len(leaked), report.String())
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+// goroutineStack is one goroutine from a full stack dump.
+type goroutineStack struct {
+	stack string
+	id    int
+}
+
+// goroutines returns every goroutine except the caller's.
+func goroutines() []goroutineStack {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	chunks := bytes.Split(buf, []byte("\n\n"))
+	result := make([]goroutineStack, 0, len(chunks))
+	for i, chunk := range chunks {
+		if i == 0 {
+			continue // the calling goroutine is always first
+		}
+		header, _, _ := strings.Cut(string(chunk), " [")
+		id, err := strconv.Atoi(strings.TrimPrefix(header, "goroutine "))
+		if err != nil {
+			continue
+		}
+		result = append(result, goroutineStack{id: id, stack: string(chunk)})
+	}
+	return result
+}
+
+// leakedGoroutines returns goroutines not in before and not ignored.
+func leakedGoroutines(before map[int]bool, ignores []string) []goroutineStack {
+	var leaked []goroutineStack
+	for _, g := range goroutines() {
+		if before[g.id] || ignored(g.stack, ignores) {
+			continue
+		}
+		leaked = append(leaked, g)
+	}
+	return leaked
+}
+
+func ignored(stack string, ignores []string) bool {
+	for _, s := range ignores {
+		if strings.Contains(stack, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// leakHint explains a leak inside a pipz connector, if recognized.
+func leakHint(stack string) string {
+	for _, h := range leakHints {
+		if strings.Contains(stack, h.frame) {
+			return h.hint
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package testing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/pipz"
+)
+
+type leakQuery struct {
+	Term string
+}
+
+func (q leakQuery) Clone() leakQuery { return q }
+
+func TestVerifyNoLeaks(t *testing.T) {
+	t.Run("Clean Race Passes", func(t *testing.T) {
+		rec := &recordingTB{T: t}
+		t.Run("Inner", func(t *testing.T) {
+			rec.T = t
+			VerifyNoLeaks(rec)
+			fast := pipz.Transform(pipz.NewIdentity("fast", ""), func(_ context.Context, q leakQuery) leakQuery { return q })
+			slow := pipz.Apply(pipz.NewIdentity("slow", ""), func(ctx context.Context, q leakQuery) (leakQuery, error) {
+				<-ctx.Done()
+				return q, ctx.Err()
+			})
+			race := pipz.NewRace(pipz.NewIdentity("race", ""), fast, slow)
+			if _, err := race.Process(context.Background(), leakQuery{Term: "q"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+		if len(rec.errors) != 0 {
+			t.Errorf("unexpected leak report: %v", rec.errors)
+		}
+	})
+
+	t.Run("Race Loser Ignoring Cancellation Is Reported", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		rec := &recordingTB{T: t}
+		t.Run("Inner", func(t *testing.T) {
+			rec.T = t
+			VerifyNoLeaks(rec, LeakTimeout(50*time.Millisecond))
+			fast := pipz.Transform(pipz.NewIdentity("fast", ""), func(_ context.Context, q leakQuery) leakQuery { return q })
+			stuck := pipz.Apply(pipz.NewIdentity("stuck", ""), func(_ context.Context, q leakQuery) (leakQuery, error) {
+				<-release
+				return q, nil
+			})
+			race := pipz.NewRace(pipz.NewIdentity("race", ""), fast, stuck)
+			if _, err := race.Process(context.Background(), leakQuery{Term: "q"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "Race loser still running") {
+			t.Errorf("expected race leak report, got %v", rec.errors)
+		}
+	})
+
+	t.Run("Ignored Goroutines", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		rec := &recordingTB{T: t}
+		t.Run("Inner", func(t *testing.T) {
+			rec.T = t
+			VerifyNoLeaks(rec, LeakTimeout(20*time.Millisecond), IgnoreLeaks("leaks_test.go"))
+			go func() { <-release }()
+		})
+		if len(rec.errors) != 0 {
+			t.Errorf("expected ignored goroutine, got %v", rec.errors)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package testing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+// maxFlushSteps bounds Flush so a connector that keeps scheduling new
+// timers cannot spin forever.
+const maxFlushSteps = 10000
+
+// TestScheduler drives virtual time for clock-based connectors such as
+// Backoff, RateLimiter, Timeout, and WorkerPool. It is a fake clock that
+// also tracks the deadlines connectors wait on through After and
+// WithTimeout, so a test can wait for a connector to block, then jump
+// straight to (or past) its pending timers instead of sleeping. Windowed
+// and delayed logic that takes seconds of wall time runs in microseconds.
+//
+// Pass the scheduler wherever a clockz.Clock is accepted.
+//
+// Example:
+//
+//	sched := pipztesting.NewTestScheduler()
+//	backoff := pipz.NewBackoff(BackoffID, flaky, 5, time.Minute).WithClock(sched)
+//
+//	done := make(chan error, 1)
+//	go func() { _, err := backoff.Process(ctx, order); done <- err }()
+//
+//	sched.WaitForPending(1, time.Second) // backoff is sleeping
+//	sched.Flush()                        // fire every retry delay
+//	err := <-done
+type TestScheduler struct {
+	*clockz.FakeClock
+	deadlines map[*deadline]struct{}
+	mu        sync.Mutex
+	changed   chan struct{}
+}
+
+// deadline is a pending timer tracked by the scheduler.
+type deadline struct {
+	at time.Time
+}
+
+// NewTestScheduler creates a scheduler starting at the fake clock's epoch.
+func NewTestScheduler() *TestScheduler {
+	return &TestScheduler{
+		FakeClock: clockz.NewFakeClock(),
+		deadlines: make(map[*deadline]struct{}),
+		changed:   make(chan struct{}),
+	}
+}
+
+// After implements clockz.Clock, tracking the deadline.
+func (s *TestScheduler) After(d time.Duration) <-chan time.Time {
+	s.track(d)
+	return s.FakeClock.After(d)
+}
+
+// WithTimeout implements clockz.Clock, tracking the deadline until it
+// passes or the context is canceled.
+func (s *TestScheduler) WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	dl := s.track(d)
+	ctx, cancel := s.FakeClock.WithTimeout(ctx, d)
+	return ctx, func() {
+		s.untrack(dl)
+		cancel()
+	}
+}
+
+// Advance moves virtual time forward by d, firing every timer that falls
+// due, and lets woken goroutines observe the new time.
+func (s *TestScheduler) Advance(d time.Duration) {
+	s.FakeClock.Advance(d)
+	s.FakeClock.BlockUntilReady()
+	s.prune()
+}
+
+// AdvanceTo moves virtual time forward to t. Times in the past are ignored.
+func (s *TestScheduler) AdvanceTo(t time.Time) {
+	if d := t.Sub(s.Now()); d > 0 {
+		s.Advance(d)
+	}
+}
+
+// Pending returns the number of tracked timers that have not fired.
+func (s *TestScheduler) Pending() int {
+	s.prune()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.deadlines)
+}
+
+// Next returns the earliest pending deadline, if any.
+func (s *TestScheduler) Next() (time.Time, bool) {
+	s.prune()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for dl := range s.deadlines {
+		if next.IsZero() || dl.at.Before(next) {
+			next = dl.at
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// Flush fires pending timers in deadline order until none remain, waiting
+// briefly after each for timers the woken goroutines schedule in turn (a
+// backoff's next delay, for example). It returns how far virtual time
+// moved.
+func (s *TestScheduler) Flush() time.Duration {
+	start := s.Now()
+	for i := 0; i < maxFlushSteps; i++ {
+		next, ok := s.Next()
+		if !ok && !s.WaitForPending(1, 10*time.Millisecond) {
+			break
+		}
+		if ok {
+			s.AdvanceTo(next)
+		}
+	}
+	return s.Now().Sub(start)
+}
+
+// WaitForPending blocks in real time until at least n timers are pending,
+// so a test advances only after the code under test has started waiting.
+// It returns false if timeout elapses first.
+func (s *TestScheduler) WaitForPending(n int, timeout time.Duration) bool {
+	expired := time.After(timeout)
+	for {
+		s.mu.Lock()
+		changed := s.changed
+		s.mu.Unlock()
+		if s.Pending() >= n {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-expired:
+			return false
+		}
+	}
+}
+
+// track records a deadline d from now.
+func (s *TestScheduler) track(d time.Duration) *deadline {
+	dl := &deadline{at: s.Now().Add(d)}
+	if d <= 0 {
+		return dl
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadlines[dl] = struct{}{}
+	s.notifyLocked()
+	return dl
+}
+
+// untrack forgets a deadline.
+func (s *TestScheduler) untrack(dl *deadline) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.deadlines, dl)
+}
+
+// prune forgets deadlines that have passed.
+func (s *TestScheduler) prune() {
+	now := s.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for dl := range s.deadlines {
+		if !dl.at.After(now) {
+			delete(s.deadlines, dl)
+		}
+	}
+}
+
+// notifyLocked wakes WaitForPending callers. s.mu must be held.
+func (s *TestScheduler) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
@@ -0,0 +1,93 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+	"github.com/zoobzio/pipz"
+)
+
+func TestTestScheduler(t *testing.T) {
+	t.Run("Implements Clock", func(_ *testing.T) {
+		var _ clockz.Clock = NewTestScheduler()
+	})
+
+	t.Run("Tracks And Advances Deadlines", func(t *testing.T) {
+		sched := NewTestScheduler()
+		ch := sched.After(time.Hour)
+		sched.After(2 * time.Hour)
+
+		if sched.Pending() != 2 {
+			t.Fatalf("expected 2 pending timers, got %d", sched.Pending())
+		}
+		next, ok := sched.Next()
+		if !ok || next.Sub(sched.Now()) != time.Hour {
+			t.Fatalf("unexpected next deadline: %v", next)
+		}
+
+		sched.Advance(time.Hour)
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("timer did not fire")
+		}
+		if sched.Pending() != 1 {
+			t.Errorf("expected 1 pending timer, got %d", sched.Pending())
+		}
+	})
+
+	t.Run("Canceled Timeouts Are Forgotten", func(t *testing.T) {
+		sched := NewTestScheduler()
+		_, cancel := sched.WithTimeout(context.Background(), time.Minute)
+		if sched.Pending() != 1 {
+			t.Fatalf("expected 1 pending timer, got %d", sched.Pending())
+		}
+		cancel()
+		if sched.Pending() != 0 {
+			t.Errorf("expected no pending timers, got %d", sched.Pending())
+		}
+	})
+
+	t.Run("Flushes Backoff Delays", func(t *testing.T) {
+		sched := NewTestScheduler()
+		mock := NewMockProcessor[int](t, "flaky").WithReturn(0, errors.New("unavailable"))
+		backoff := pipz.NewBackoff(pipz.NewIdentity("backoff", ""), mock, 4, time.Minute).WithClock(sched)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := backoff.Process(context.Background(), 1)
+			done <- err
+		}()
+
+		if !sched.WaitForPending(1, time.Second) {
+			t.Fatal("backoff never waited")
+		}
+		start := time.Now()
+		moved := sched.Flush()
+
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Fatal("expected backoff to fail after all attempts")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("backoff did not finish after flush")
+		}
+		AssertProcessed(t, mock, 4)
+		if moved != 7*time.Minute {
+			t.Errorf("expected virtual time to move 7m (1m+2m+4m), got %v", moved)
+		}
+		if time.Since(start) > 500*time.Millisecond {
+			t.Errorf("flush took too long in real time: %v", time.Since(start))
+		}
+	})
+
+	t.Run("WaitForPending Times Out", func(t *testing.T) {
+		if NewTestScheduler().WaitForPending(1, 10*time.Millisecond) {
+			t.Error("expected timeout with no timers")
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package pipz
+
+import (
+	"context"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// TimeoutConflict reports a Timeout whose nested time budget exceeds its
+// own duration.
+type TimeoutConflict struct {
+	Timeout  Identity
+	Duration time.Duration
+	Nested   time.Duration
+}
+
+// TimeBudget estimates the worst-case latency a schema subtree is
+// configured for, from the time-based connectors it contains: Timeout
+// durations, multiplied by Retry and Backoff attempts, plus Backoff delays.
+// Sequential flows add their children's budgets; parallel and routing flows
+// take the largest. Plain processors have no declared bound and count as
+// zero, so the result is a lower bound on how long the subtree may run.
+//
+// Example:
+//
+//	// Three 5s attempts with 1s, 2s backoff: 17s.
+//	budget := pipz.TimeBudget(pipz.NewBackoff(RetryID,
+//	    pipz.NewTimeout(CallID, call, 5*time.Second), 3, time.Second).Schema())
+func TimeBudget(node Node) time.Duration {
+	children := nodeChildren(node)
+	switch f := node.Flow.(type) {
+	case TimeoutFlow:
+		if d, ok := metadataDuration(node, "duration"); ok && d > 0 {
+			return d
+		}
+		return TimeBudget(f.Processor)
+	case RetryFlow:
+		return time.Duration(metadataInt(node, "max_attempts")) * TimeBudget(f.Processor)
+	case BackoffFlow:
+		attempts := metadataInt(node, "max_attempts")
+		budget := time.Duration(attempts) * TimeBudget(f.Processor)
+		if delay, ok := metadataDuration(node, "base_delay"); ok {
+			for i := 1; i < attempts; i++ {
+				budget += delay
+				delay *= 2
+			}
+		}
+		return budget
+	case WorkerpoolFlow:
+		budget := maxBudget(children)
+		if d, ok := metadataDuration(node, "timeout"); ok && d > 0 && d < budget {
+			budget = d
+		}
+		return budget
+	case ScaffoldFlow:
+		return 0 // fire-and-forget: the caller never waits
+	case SequenceFlow, FallbackFlow, HandleFlow, ComposeFlow, CollectErrorsFlow, VerifyFlow:
+		var sum time.Duration
+		for _, child := range children {
+			sum += TimeBudget(child)
+		}
+		return sum
+	}
+	return maxBudget(children)
+}
+
+// CheckTimeouts walks a schema and reports every Timeout whose nested
+// budget, as estimated by TimeBudget, exceeds its own duration. Nested
+// deadlines are already capped at runtime by the outer Timeout's context,
+// so a conflict means the inner configuration can never run to completion:
+// the later retries, or the inner timeout, are dead configuration that
+// hides the latency actually enforced.
+//
+// Example:
+//
+//	for _, c := range pipz.CheckTimeouts(pipz.NewSchema(pipeline.Schema())) {
+//	    log.Printf("%s allows %v but nests %v", c.Timeout.Name(), c.Duration, c.Nested)
+//	}
+func CheckTimeouts(schema Schema) []TimeoutConflict {
+	var conflicts []TimeoutConflict
+	schema.Walk(func(node Node) {
+		if conflict, ok := timeoutConflict(node); ok {
+			conflicts = append(conflicts, conflict)
+		}
+	})
+	return conflicts
+}
+
+// timeoutConflict checks a single Timeout node against its child's budget.
+func timeoutConflict(node Node) (TimeoutConflict, bool) {
+	flow, ok := node.Flow.(TimeoutFlow)
+	if !ok {
+		return TimeoutConflict{}, false
+	}
+	duration, ok := metadataDuration(node, "duration")
+	if !ok || duration <= 0 {
+		return TimeoutConflict{}, false
+	}
+	nested := TimeBudget(flow.Processor)
+	if nested <= duration {
+		return TimeoutConflict{}, false
+	}
+	return TimeoutConflict{Timeout: node.Identity, Duration: duration, Nested: nested}, true
+}
+
+// warnTimeoutBudget emits SignalTimeoutBudgetExceeded when node's nested
+// budget exceeds its duration.
+func warnTimeoutBudget(node Node) {
+	conflict, ok := timeoutConflict(node)
+	if !ok {
+		return
+	}
+	capitan.Warn(context.Background(), SignalTimeoutBudgetExceeded,
+		FieldName.Field(conflict.Timeout.Name()),
+		FieldIdentityID.Field(conflict.Timeout.ID().String()),
+		FieldDuration.Field(conflict.Duration.Seconds()),
+		FieldBudget.Field(conflict.Nested.Seconds()),
+	)
+}
+
+func maxBudget(nodes []Node) time.Duration {
+	var longest time.Duration
+	for _, n := range nodes {
+		if b := TimeBudget(n); b > longest {
+			longest = b
+		}
+	}
+	return longest
+}
+
+// metadataDuration reads a duration stored as a string in node metadata.
+func metadataDuration(node Node, key string) (time.Duration, bool) {
+	s, ok := node.Metadata[key].(string)
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// metadataInt reads an integer from node metadata, accepting float64 for
+// schemas decoded from JSON. Missing values count as one.
+func metadataInt(node Node, key string) int {
+	switch v := node.Metadata[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 1
+}
@@ -0,0 +1,134 @@
+package pipz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestTimeBudget(t *testing.T) {
+	call := Transform(NewIdentity("call", ""), func(_ context.Context, v int) int { return v })
+	inner := func(d time.Duration) Chainable[int] {
+		return NewTimeout(NewIdentity("inner", ""), call, d)
+	}
+
+	t.Run("Plain Processor Is Zero", func(t *testing.T) {
+		if b := TimeBudget(call.Schema()); b != 0 {
+			t.Errorf("expected 0, got %v", b)
+		}
+	})
+
+	t.Run("Retry Multiplies", func(t *testing.T) {
+		r := NewRetry(NewIdentity("retry", ""), inner(2*time.Second), 3)
+		if b := TimeBudget(r.Schema()); b != 6*time.Second {
+			t.Errorf("expected 6s, got %v", b)
+		}
+	})
+
+	t.Run("Backoff Adds Delays", func(t *testing.T) {
+		b := NewBackoff(NewIdentity("backoff", ""), inner(5*time.Second), 3, time.Second)
+		// 3 x 5s attempts + 1s + 2s delays.
+		if got := TimeBudget(b.Schema()); got != 18*time.Second {
+			t.Errorf("expected 18s, got %v", got)
+		}
+	})
+
+	t.Run("Sequence Sums And Concurrent Takes Max", func(t *testing.T) {
+		seq := NewSequence(NewIdentity("seq", ""), inner(time.Second), inner(2*time.Second))
+		if b := TimeBudget(seq.Schema()); b != 3*time.Second {
+			t.Errorf("expected sequence 3s, got %v", b)
+		}
+		race := NewRace(NewIdentity("race", ""), clonableTimeout(time.Second), clonableTimeout(4*time.Second))
+		if b := TimeBudget(race.Schema()); b != 4*time.Second {
+			t.Errorf("expected race 4s, got %v", b)
+		}
+	})
+
+	t.Run("Outer Timeout Bounds Budget", func(t *testing.T) {
+		outer := NewTimeout(NewIdentity("outer", ""), NewRetry(NewIdentity("retry", ""), inner(time.Second), 10), 3*time.Second)
+		if b := TimeBudget(outer.Schema()); b != 3*time.Second {
+			t.Errorf("expected outer duration 3s, got %v", b)
+		}
+	})
+}
+
+func TestCheckTimeouts(t *testing.T) {
+	call := Transform(NewIdentity("call", ""), func(_ context.Context, v int) int { return v })
+
+	t.Run("Reports Nested Budget Exceeding Outer", func(t *testing.T) {
+		retry := NewRetry(NewIdentity("retry", ""), NewTimeout(NewIdentity("attempt", ""), call, 5*time.Second), 3)
+		outer := NewTimeout(NewIdentity("outer", ""), retry, 10*time.Second)
+
+		conflicts := CheckTimeouts(NewSchema(outer.Schema()))
+		if len(conflicts) != 1 {
+			t.Fatalf("expected 1 conflict, got %d", len(conflicts))
+		}
+		c := conflicts[0]
+		if c.Timeout.Name() != "outer" || c.Duration != 10*time.Second || c.Nested != 15*time.Second {
+			t.Errorf("unexpected conflict: %+v", c)
+		}
+	})
+
+	t.Run("Finds Conflicts Deep In Tree", func(t *testing.T) {
+		nested := NewTimeout(NewIdentity("short", ""), NewTimeout(NewIdentity("long", ""), call, time.Minute), time.Second)
+		seq := NewSequence(NewIdentity("seq", ""), call, nested)
+		conflicts := CheckTimeouts(NewSchema(seq.Schema()))
+		if len(conflicts) != 1 || conflicts[0].Timeout.Name() != "short" {
+			t.Errorf("expected conflict on 'short', got %+v", conflicts)
+		}
+	})
+
+	t.Run("No Conflict Within Budget", func(t *testing.T) {
+		retry := NewRetry(NewIdentity("retry", ""), NewTimeout(NewIdentity("attempt", ""), call, time.Second), 3)
+		outer := NewTimeout(NewIdentity("outer", ""), retry, 5*time.Second)
+		if conflicts := CheckTimeouts(NewSchema(outer.Schema())); len(conflicts) != 0 {
+			t.Errorf("expected no conflicts, got %+v", conflicts)
+		}
+	})
+
+	t.Run("Construction Emits Warning", func(t *testing.T) {
+		var (
+			name   string
+			budget float64
+		)
+		listener := capitan.Hook(SignalTimeoutBudgetExceeded, func(_ context.Context, e *capitan.Event) {
+			name, _ = FieldName.From(e)
+			budget, _ = FieldBudget.From(e)
+		})
+		defer listener.Close()
+
+		retry := NewRetry(NewIdentity("retry", ""), NewTimeout(NewIdentity("attempt", ""), call, 2*time.Second), 3)
+		NewTimeout(NewIdentity("outer", ""), retry, time.Second)
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if name != "outer" || budget != 6 {
+			t.Errorf("expected warning for 'outer' with 6s budget, got %q %v", name, budget)
+		}
+	})
+
+	t.Run("SetDuration Emits Warning", func(t *testing.T) {
+		var warned bool
+		listener := capitan.Hook(SignalTimeoutBudgetExceeded, func(_ context.Context, _ *capitan.Event) {
+			warned = true
+		})
+		defer listener.Close()
+
+		outer := NewTimeout(NewIdentity("outer", ""), NewTimeout(NewIdentity("inner", ""), call, 2*time.Second), time.Minute)
+		outer.SetDuration(time.Second)
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if !warned {
+			t.Error("expected warning after shortening the outer timeout")
+		}
+	})
+}
+
+func clonableTimeout(d time.Duration) Chainable[clonableInt] {
+	return NewTimeout(NewIdentity("inner", ""), Transform(NewIdentity("call", ""), func(_ context.Context, v clonableInt) clonableInt { return v }), d)
+}
@@ -48,19 +48,45 @@ type Timeout[T any] struct {
 	closeErr  error
 }
 
-// NewTimeout creates a new Timeout connector.
+// NewTimeout creates a new Timeout connector. If the processor's nested
+// time budget (see TimeBudget) exceeds duration, as when wrapping a Retry
+// of longer Timeouts, SignalTimeoutBudgetExceeded is emitted: the outer
+// deadline will always cut the inner configuration short.
 func NewTimeout[T any](identity Identity, processor Chainable[T], duration time.Duration) *Timeout[T] {
-	return &Timeout[T]{
+	t := &Timeout[T]{
 		identity:  identity,
 		processor: processor,
 		duration:  duration,
 	}
+	if processor != nil {
+		warnTimeoutBudget(t.Schema())
+	}
+	return t
+}
+
+// NewTimeoutWithOptions creates a Timeout from functional options.
+// WithDuration is required; WithClock is optional. Invalid, missing, or
+// inapplicable options return an error wrapping ErrInvalidOption.
+func NewTimeoutWithOptions[T any](identity Identity, processor Chainable[T], opts ...Option) (*Timeout[T], error) {
+	cfg, err := newConfig("timeout", Config{}, optDuration|optClock, optDuration, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewTimeout(identity, processor, cfg.Duration)
+	t.clock = cfg.Clock
+	return t, nil
 }
 
 // Process implements the Chainable interface.
 func (t *Timeout[T]) Process(ctx context.Context, data T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, t.identity, data)
 
+	ctx, guardErr := enterDepth(ctx, t, t.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
 	t.mu.RLock()
 	processor := t.processor
 	duration := t.duration
@@ -85,8 +111,8 @@ func (t *Timeout[T]) Process(ctx context.Context, data T) (result T, err error)
 				var zero T
 				panicErr := &Error[T]{
 					Path:      []Identity{t.identity},
-					InputData: data,
-					Err:       &panicError{identity: t.identity, sanitized: sanitizePanicMessage(r)},
+					InputData: errorInput(data),
+					Err:       newPanicError(t.identity, r),
 					Timestamp: time.Now(),
 					Duration:  0,
 					Timeout:   false,
@@ -115,13 +141,13 @@ func (t *Timeout[T]) Process(ctx context.Context, data T) (result T, err error)
 			var pipeErr *Error[T]
 			if errors.As(res.err, &pipeErr) {
 				// Prepend this timeout's identity to the path
-				pipeErr.Path = append([]Identity{t.identity}, pipeErr.Path...)
+				pipeErr.prependPath(ctx, t.identity)
 				return res.result, pipeErr
 			}
 			// Handle non-pipeline errors by wrapping them
 			return res.result, &Error[T]{
 				Timestamp: time.Now(),
-				InputData: data,
+				InputData: errorInput(data),
 				Err:       res.err,
 				Path:      []Identity{t.identity},
 			}
@@ -134,7 +160,7 @@ func (t *Timeout[T]) Process(ctx context.Context, data T) (result T, err error)
 
 		// Emit timeout signal only when deadline exceeded (not cancellation)
 		if isTimeout {
-			capitan.Error(context.Background(), SignalTimeoutTriggered,
+			capitan.Error(context.WithoutCancel(ctx), SignalTimeoutTriggered,
 				FieldName.Field(t.identity.Name()),
 				FieldIdentityID.Field(t.identity.ID().String()),
 				FieldDuration.Field(duration.Seconds()),
@@ -144,7 +170,7 @@ func (t *Timeout[T]) Process(ctx context.Context, data T) (result T, err error)
 
 		return data, &Error[T]{
 			Err:       ctx.Err(),
-			InputData: data,
+			InputData: errorInput(data),
 			Path:      []Identity{t.identity},
 			Timeout:   isTimeout,
 			Canceled:  errors.Is(ctx.Err(), context.Canceled),
@@ -153,14 +179,36 @@ func (t *Timeout[T]) Process(ctx context.Context, data T) (result T, err error)
 	}
 }
 
-// SetDuration updates the timeout duration.
+// SetDuration updates the timeout duration, emitting
+// SignalTimeoutBudgetExceeded if it is shorter than the nested budget.
 func (t *Timeout[T]) SetDuration(d time.Duration) *Timeout[T] {
 	t.mu.Lock()
-	defer t.mu.Unlock()
 	t.duration = d
+	t.mu.Unlock()
+	warnTimeoutBudget(t.Schema())
 	return t
 }
 
+// Reconfigure atomically updates the timeout from WithDuration. In-flight
+// calls keep the deadline they started with. Invalid or inapplicable options
+// return an error wrapping ErrInvalidOption.
+func (t *Timeout[T]) Reconfigure(ctx context.Context, opts ...Option) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cfg, err := newConfig("timeout", Config{Duration: t.duration}, optDuration, 0, opts)
+	if err != nil {
+		return err
+	}
+
+	t.duration = cfg.Duration
+	emitReconfigured(ctx, t.identity, cfg)
+	if t.processor != nil {
+		warnTimeoutBudget(t.schemaLocked())
+	}
+	return nil
+}
+
 // GetDuration returns the current timeout duration.
 func (t *Timeout[T]) GetDuration() time.Duration {
 	t.mu.RLock()
@@ -179,7 +227,11 @@ func (t *Timeout[T]) Identity() Identity {
 func (t *Timeout[T]) Schema() Node {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
+	return t.schemaLocked()
+}
 
+// schemaLocked builds the schema node. Must be called with mutex held.
+func (t *Timeout[T]) schemaLocked() Node {
 	return Node{
 		Identity: t.identity,
 		Type:     "timeout",
@@ -0,0 +1,189 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// TombstoneStats holds the combined counters of a Tombstone connector's
+// live and deletion paths.
+type TombstoneStats struct {
+	Live          int64 `json:"live"`
+	Deleted       int64 `json:"deleted"`
+	LiveFailed    int64 `json:"live_failed"`
+	DeletedFailed int64 `json:"deleted_failed"`
+}
+
+// Processed returns the number of items seen on either path.
+func (s TombstoneStats) Processed() int64 {
+	return s.Live + s.Deleted
+}
+
+// Failed returns the number of items that failed on either path.
+func (s TombstoneStats) Failed() int64 {
+	return s.LiveFailed + s.DeletedFailed
+}
+
+// Tombstone splits a change stream into live records and deletions. Items
+// the predicate recognizes as tombstones (soft-deleted rows, CDC delete
+// events, null-valued compacted keys) are routed to the deletion processor,
+// while live records continue down the main processor. Both paths are
+// counted in one TombstoneStats, so the connector reports a single view of
+// the stream.
+//
+// Unlike Switch, the two paths are fixed and named for what they do, which
+// keeps the schema of CDC-style pipelines readable.
+//
+// Example:
+//
+//	var ChangesID = pipz.NewIdentity("customer-changes", "Applies customer CDC events")
+//	changes := pipz.NewTombstone(ChangesID,
+//	    func(_ context.Context, e CustomerEvent) bool { return e.Op == "d" },
+//	    deleteCustomer,
+//	    pipz.NewSequence(UpsertID, validateCustomer, upsertCustomer),
+//	)
+type Tombstone[T any] struct {
+	live          Chainable[T]
+	deletion      Chainable[T]
+	isTombstone   func(context.Context, T) bool
+	identity      Identity
+	mu            sync.RWMutex
+	liveCount     atomic.Int64
+	deletedCount  atomic.Int64
+	liveFailed    atomic.Int64
+	deletedFailed atomic.Int64
+	closeOnce     sync.Once
+	closeErr      error
+}
+
+// NewTombstone creates a Tombstone connector routing items for which
+// isTombstone returns true to deletion and all others to live.
+func NewTombstone[T any](identity Identity, isTombstone func(context.Context, T) bool, deletion, live Chainable[T]) *Tombstone[T] {
+	return &Tombstone[T]{
+		identity:    identity,
+		isTombstone: isTombstone,
+		deletion:    deletion,
+		live:        live,
+	}
+}
+
+// Process implements the Chainable interface.
+// Routes the item to the deletion or live processor.
+func (t *Tombstone[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, t.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, t, t.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	t.mu.RLock()
+	isTombstone := t.isTombstone
+	deletion := t.deletion
+	live := t.live
+	t.mu.RUnlock()
+
+	processor, count, failed := live, &t.liveCount, &t.liveFailed
+	if isTombstone(ctx, data) {
+		processor, count, failed = deletion, &t.deletedCount, &t.deletedFailed
+		capitan.Info(ctx, SignalTombstoneRouted,
+			FieldName.Field(t.identity.Name()),
+			FieldIdentityID.Field(t.identity.ID().String()),
+			FieldProcessorName.Field(deletion.Identity().Name()),
+		)
+	}
+	count.Add(1)
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		if !isControl(err) {
+			failed.Add(1)
+		}
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, t.identity)
+			return result, pipeErr
+		}
+		return data, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{t.identity},
+		}
+	}
+	return result, nil
+}
+
+// SetPredicate updates the function recognizing tombstones.
+func (t *Tombstone[T]) SetPredicate(isTombstone func(context.Context, T) bool) *Tombstone[T] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.isTombstone = isTombstone
+	return t
+}
+
+// SetDeletion updates the processor handling tombstones.
+func (t *Tombstone[T]) SetDeletion(processor Chainable[T]) *Tombstone[T] {
+	noteChildren(t.identity, processor)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.deletion = processor
+	return t
+}
+
+// SetLive updates the processor handling live records.
+func (t *Tombstone[T]) SetLive(processor Chainable[T]) *Tombstone[T] {
+	noteChildren(t.identity, processor)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.live = processor
+	return t
+}
+
+// Stats returns the combined counters of both paths.
+func (t *Tombstone[T]) Stats() TombstoneStats {
+	return TombstoneStats{
+		Live:          t.liveCount.Load(),
+		Deleted:       t.deletedCount.Load(),
+		LiveFailed:    t.liveFailed.Load(),
+		DeletedFailed: t.deletedFailed.Load(),
+	}
+}
+
+// Identity returns the identity of this connector.
+func (t *Tombstone[T]) Identity() Identity {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (t *Tombstone[T]) Schema() Node {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return Node{
+		Identity: t.identity,
+		Type:     "tombstone",
+		Flow: TombstoneFlow{
+			Live:     t.live.Schema(),
+			Deletion: t.deletion.Schema(),
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and both child processors.
+// Close is idempotent - multiple calls return the same result.
+func (t *Tombstone[T]) Close() error {
+	t.closeOnce.Do(func() {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+		t.closeErr = errors.Join(t.live.Close(), t.deletion.Close())
+	})
+	return t.closeErr
+}
@@ -0,0 +1,129 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+type changeEvent struct {
+	Key     string
+	Deleted bool
+}
+
+func TestTombstone(t *testing.T) {
+	isDeleted := func(_ context.Context, e changeEvent) bool { return e.Deleted }
+
+	t.Run("Routes Tombstones To Deletion", func(t *testing.T) {
+		var live, deleted []string
+		upsert := Effect(NewIdentity("upsert", ""), func(_ context.Context, e changeEvent) error {
+			live = append(live, e.Key)
+			return nil
+		})
+		remove := Effect(NewIdentity("delete", ""), func(_ context.Context, e changeEvent) error {
+			deleted = append(deleted, e.Key)
+			return nil
+		})
+		tomb := NewTombstone(NewIdentity("changes", ""), isDeleted, remove, upsert)
+
+		var routed string
+		listener := capitan.Hook(SignalTombstoneRouted, func(_ context.Context, e *capitan.Event) {
+			routed, _ = FieldProcessorName.From(e)
+		})
+		defer listener.Close()
+
+		for _, e := range []changeEvent{{Key: "a"}, {Key: "b", Deleted: true}, {Key: "c"}} {
+			if _, err := tomb.Process(context.Background(), e); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if len(live) != 2 || len(deleted) != 1 || deleted[0] != "b" {
+			t.Errorf("expected a, c live and b deleted, got %v and %v", live, deleted)
+		}
+		if routed != "delete" {
+			t.Errorf("expected routed signal naming delete, got %q", routed)
+		}
+		stats := tomb.Stats()
+		if stats.Live != 2 || stats.Deleted != 1 || stats.Processed() != 3 || stats.Failed() != 0 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Failures Are Counted Per Path", func(t *testing.T) {
+		boom := errors.New("boom")
+		fail := Apply(NewIdentity("delete", ""), func(_ context.Context, e changeEvent) (changeEvent, error) { return e, boom })
+		upsert := Transform(NewIdentity("upsert", ""), func(_ context.Context, e changeEvent) changeEvent { return e })
+		tomb := NewTombstone(NewIdentity("changes", ""), isDeleted, fail, upsert)
+
+		_, err := tomb.Process(context.Background(), changeEvent{Key: "x", Deleted: true})
+		if !errors.Is(err, boom) {
+			t.Fatalf("expected boom, got %v", err)
+		}
+		var pipeErr *Error[changeEvent]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "changes" {
+			t.Errorf("expected path changes -> delete, got %v", err)
+		}
+		if stats := tomb.Stats(); stats.DeletedFailed != 1 || stats.LiveFailed != 0 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Skip Is Not A Failure", func(t *testing.T) {
+		skip := Apply(NewIdentity("drop", ""), func(_ context.Context, e changeEvent) (changeEvent, error) { return e, ErrSkip })
+		upsert := Transform(NewIdentity("upsert", ""), func(_ context.Context, e changeEvent) changeEvent { return e })
+		tomb := NewTombstone(NewIdentity("changes", ""), isDeleted, skip, upsert)
+
+		_, err := tomb.Process(context.Background(), changeEvent{Deleted: true})
+		if !IsSkip(err) {
+			t.Fatalf("expected skip, got %v", err)
+		}
+		if stats := tomb.Stats(); stats.Failed() != 0 || stats.Deleted != 1 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		upsert := Transform(NewIdentity("upsert", ""), func(_ context.Context, e changeEvent) changeEvent { return e })
+		remove := Transform(NewIdentity("delete", ""), func(_ context.Context, e changeEvent) changeEvent { return e })
+		tomb := NewTombstone(NewIdentity("changes", ""), isDeleted, remove, upsert)
+
+		node := tomb.Schema()
+		flow, ok := TombstoneKey.From(node)
+		if !ok {
+			t.Fatalf("expected TombstoneFlow, got %T", node.Flow)
+		}
+		if flow.Live.Identity.Name() != "upsert" || flow.Deletion.Identity.Name() != "delete" {
+			t.Errorf("unexpected flow: %+v", flow)
+		}
+		if children := nodeChildren(node); len(children) != 2 {
+			t.Errorf("expected 2 children, got %d", len(children))
+		}
+	})
+
+	t.Run("Setters And Close", func(t *testing.T) {
+		upsert := Transform(NewIdentity("upsert", ""), func(_ context.Context, e changeEvent) changeEvent { return e })
+		remove := Transform(NewIdentity("delete", ""), func(_ context.Context, e changeEvent) changeEvent {
+			e.Key = "removed"
+			return e
+		})
+		tomb := NewTombstone(NewIdentity("changes", ""), isDeleted, upsert, upsert).
+			SetDeletion(remove).
+			SetPredicate(func(context.Context, changeEvent) bool { return true })
+
+		got, err := tomb.Process(context.Background(), changeEvent{Key: "a"})
+		if err != nil || got.Key != "removed" {
+			t.Fatalf("expected deletion path, got %+v, %v", got, err)
+		}
+		if err := tomb.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+		if err := tomb.Close(); err != nil {
+			t.Errorf("second close should return the same result, got %v", err)
+		}
+	})
+}
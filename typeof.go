@@ -0,0 +1,82 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrTypeMismatch is returned by AsChainable when a value is not a
+// Chainable of the requested data type.
+var ErrTypeMismatch = errors.New("chainable type mismatch")
+
+var (
+	contextType = reflect.TypeFor[context.Context]()
+	errorType   = reflect.TypeFor[error]()
+)
+
+// TypeOf returns the data type a Chainable processes.
+//
+// Example:
+//
+//	pipz.TypeOf(orderPipeline).String() // "main.Order"
+func TypeOf[T any](_ Chainable[T]) reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// DataTypeOf returns the data type of a Chainable held as any, such as an
+// entry in a registry or a value produced by a config loader. It inspects
+// the Process method, so it works for every Chainable implementation
+// including user-defined ones. The second result is false when v is not a
+// Chainable.
+//
+// Example:
+//
+//	for name, p := range registry {
+//	    if typ, ok := pipz.DataTypeOf(p); ok {
+//	        fmt.Printf("%s processes %s\n", name, typ)
+//	    }
+//	}
+func DataTypeOf(v any) (reflect.Type, bool) {
+	if v == nil {
+		return nil, false
+	}
+	method, ok := reflect.TypeOf(v).MethodByName("Process")
+	if !ok {
+		return nil, false
+	}
+	// Method types include the receiver as the first input.
+	fn := method.Type
+	if fn.NumIn() != 3 || fn.NumOut() != 2 || fn.In(1) != contextType {
+		return nil, false
+	}
+	data := fn.In(2)
+	if fn.Out(0) != data || fn.Out(1) != errorType {
+		return nil, false
+	}
+	return data, true
+}
+
+// AsChainable converts a value held as any back to a Chainable[T],
+// failing with ErrTypeMismatch when it processes a different type. Dynamic
+// wiring uses it to catch a Chainable[Order] registered where a
+// Chainable[User] was expected at load time rather than with a panic on the
+// first request.
+//
+// Example:
+//
+//	users, err := pipz.AsChainable[User](registry["users"])
+//	if err != nil {
+//	    return fmt.Errorf("wiring users pipeline: %w", err)
+//	}
+func AsChainable[T any](v any) (Chainable[T], error) {
+	if c, ok := v.(Chainable[T]); ok {
+		return c, nil
+	}
+	want := reflect.TypeFor[T]()
+	if got, ok := DataTypeOf(v); ok {
+		return nil, fmt.Errorf("%w: processes %s, want %s", ErrTypeMismatch, got, want)
+	}
+	return nil, fmt.Errorf("%w: %T is not a Chainable[%s]", ErrTypeMismatch, v, want)
+}
@@ -0,0 +1,94 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTypeOf(t *testing.T) {
+	t.Run("Processor", func(t *testing.T) {
+		p := Transform(NewIdentity("double", ""), func(_ context.Context, v int) int { return v * 2 })
+		if typ := TypeOf(p); typ != reflect.TypeFor[int]() {
+			t.Errorf("expected int, got %v", typ)
+		}
+	})
+
+	t.Run("Connector", func(t *testing.T) {
+		seq := NewSequence[TestData](NewIdentity("seq", ""))
+		if typ := TypeOf[TestData](seq); typ.Name() != "TestData" {
+			t.Errorf("expected TestData, got %v", typ)
+		}
+	})
+}
+
+func TestDataTypeOf(t *testing.T) {
+	registry := map[string]any{
+		"ints":    Transform(NewIdentity("ints", ""), func(_ context.Context, v int) int { return v }),
+		"strings": NewSequence[string](NewIdentity("strings", "")),
+		"custom":  customChainable{},
+	}
+
+	tests := []struct {
+		name string
+		want reflect.Type
+	}{
+		{"ints", reflect.TypeFor[int]()},
+		{"strings", reflect.TypeFor[string]()},
+		{"custom", reflect.TypeFor[float64]()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DataTypeOf(registry[tt.name])
+			if !ok || got != tt.want {
+				t.Errorf("expected %v, got %v (%v)", tt.want, got, ok)
+			}
+		})
+	}
+
+	t.Run("Not A Chainable", func(t *testing.T) {
+		for _, v := range []any{nil, 42, struct{ Process func() }{}} {
+			if _, ok := DataTypeOf(v); ok {
+				t.Errorf("expected %T not to be a Chainable", v)
+			}
+		}
+	})
+}
+
+func TestAsChainable(t *testing.T) {
+	var stored any = Transform(NewIdentity("double", ""), func(_ context.Context, v int) int { return v * 2 })
+
+	t.Run("Matching Type", func(t *testing.T) {
+		c, err := AsChainable[int](stored)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result, processErr := c.Process(context.Background(), 3); processErr != nil || result != 6 {
+			t.Errorf("expected 6, got %d (%v)", result, processErr)
+		}
+	})
+
+	t.Run("Mismatched Type", func(t *testing.T) {
+		_, err := AsChainable[string](stored)
+		if !errors.Is(err, ErrTypeMismatch) {
+			t.Fatalf("expected ErrTypeMismatch, got %v", err)
+		}
+		if err.Error() != "chainable type mismatch: processes int, want string" {
+			t.Errorf("unexpected message: %v", err)
+		}
+	})
+
+	t.Run("Not A Chainable", func(t *testing.T) {
+		if _, err := AsChainable[int]("nope"); !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("expected ErrTypeMismatch, got %v", err)
+		}
+	})
+}
+
+type customChainable struct{}
+
+func (customChainable) Process(_ context.Context, v float64) (float64, error) { return v, nil }
+func (customChainable) Identity() Identity                                    { return NewIdentity("custom", "") }
+func (customChainable) Schema() Node                                          { return Node{} }
+func (customChainable) Close() error                                          { return nil }
@@ -0,0 +1,368 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrValidationFailed is the sentinel wrapped by ValidationError.
+var ErrValidationFailed = errors.New("validation failed")
+
+// Violation describes one failed validation rule. Path locates the offending
+// value: a rule's Path for ValidateResponse, or a JSONPath-style location
+// such as "$.rates[0].amount" for ValidateJSON.
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// String formats the violation as "path: message".
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ValidationError reports every violation found in a response, not just the
+// first, so a single failure shows everything wrong with a provider payload.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(parts, "; "))
+}
+
+// Unwrap returns ErrValidationFailed.
+func (*ValidationError) Unwrap() error {
+	return ErrValidationFailed
+}
+
+// Rule is a declarative check on a value of type T. Path names the checked
+// field in violations; Check returns nil when the value is acceptable.
+type Rule[T any] struct {
+	Check func(T) error
+	Path  string
+}
+
+// ValidateResponse creates a Processor that checks data against every rule
+// and fails with a ValidationError listing all violations. Valid data passes
+// through unchanged. Place it directly after a third-party call so malformed
+// responses stop at the boundary instead of propagating.
+//
+// Example:
+//
+//	var CheckQuoteID = pipz.NewIdentity("check-quote", "Validates carrier quote responses")
+//	checkQuote := pipz.ValidateResponse(CheckQuoteID,
+//	    pipz.Rule[Quote]{Path: "carrier", Check: func(q Quote) error {
+//	        if q.Carrier == "" {
+//	            return errors.New("is required")
+//	        }
+//	        return nil
+//	    }},
+//	    pipz.Rule[Quote]{Path: "rates", Check: func(q Quote) error {
+//	        if len(q.Rates) == 0 {
+//	            return errors.New("must not be empty")
+//	        }
+//	        return nil
+//	    }},
+//	)
+func ValidateResponse[T any](identity Identity, rules ...Rule[T]) Processor[T] {
+	return Processor[T]{
+		identity: identity,
+		fn: func(_ context.Context, value T) (result T, err error) {
+			defer recoverFromPanic(&result, &err, identity, value)
+			start := time.Now()
+
+			var violations []Violation
+			for _, rule := range rules {
+				if rule.Check == nil {
+					continue
+				}
+				if checkErr := rule.Check(value); checkErr != nil {
+					violations = append(violations, Violation{Path: rule.Path, Message: checkErr.Error()})
+				}
+			}
+			if len(violations) > 0 {
+				return value, &Error[T]{
+					Path:      []Identity{identity},
+					InputData: errorInput(value),
+					Err:       &ValidationError{Violations: violations},
+					Timestamp: time.Now(),
+					Duration:  time.Since(start),
+				}
+			}
+			return value, nil
+		},
+	}
+}
+
+// ValidateJSON creates a Processor that parses a JSON payload and checks it
+// against schema, failing with a ValidationError listing all violations.
+// Valid payloads pass through unchanged. A schema with an invalid pattern
+// fails every item with the compilation error.
+//
+// Example:
+//
+//	schema, err := pipz.ParseJSONSchema([]byte(`{
+//	    "type": "object",
+//	    "required": ["id", "rates"],
+//	    "properties": {
+//	        "id":    {"type": "string", "minLength": 1},
+//	        "rates": {"type": "array", "minItems": 1, "items": {"type": "number", "minimum": 0}}
+//	    }
+//	}`))
+//	checkBody := pipz.ValidateJSON[[]byte](CheckBodyID, schema)
+func ValidateJSON[T ~[]byte | ~string](identity Identity, schema *JSONSchema) Processor[T] {
+	compileErr := schema.compile()
+
+	return Processor[T]{
+		identity: identity,
+		fn: func(_ context.Context, value T) (result T, err error) {
+			defer recoverFromPanic(&result, &err, identity, value)
+			start := time.Now()
+
+			var validationErr error
+			if compileErr != nil {
+				validationErr = compileErr
+			} else if violations := schema.ValidateBytes([]byte(value)); len(violations) > 0 {
+				validationErr = &ValidationError{Violations: violations}
+			}
+			if validationErr != nil {
+				return value, &Error[T]{
+					Path:      []Identity{identity},
+					InputData: errorInput(value),
+					Err:       validationErr,
+					Timestamp: time.Now(),
+					Duration:  time.Since(start),
+				}
+			}
+			return value, nil
+		},
+	}
+}
+
+// SchemaType lists the JSON types a JSONSchema accepts. In JSON it may be
+// written as a single string ("object") or an array (["string", "null"]).
+type SchemaType []string
+
+// UnmarshalJSON accepts a string or an array of strings.
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaType{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("schema type must be a string or array of strings: %w", err)
+	}
+	*t = many
+	return nil
+}
+
+// JSONSchema is the subset of JSON Schema used to validate provider
+// payloads: type, enum, const, required, properties, additionalProperties
+// (boolean form), items, minItems, maxItems, minLength, maxLength, pattern,
+// minimum, and maximum. Unknown keywords are ignored. Schemas may be
+// unmarshaled from JSON with ParseJSONSchema or built directly in Go.
+type JSONSchema struct {
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	Const                any                    `json:"const,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Type                 SchemaType             `json:"type,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Enum                 []any                  `json:"enum,omitempty"`
+	pattern              *regexp.Regexp
+}
+
+// ParseJSONSchema unmarshals and compiles a JSON Schema document.
+func ParseJSONSchema(data []byte) (*JSONSchema, error) {
+	var s JSONSchema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// compile prepares regular expressions throughout the schema.
+func (s *JSONSchema) compile() error {
+	if s == nil {
+		return nil
+	}
+	if s.Pattern != "" && s.pattern == nil {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid schema pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	for _, prop := range s.Properties {
+		if err := prop.compile(); err != nil {
+			return err
+		}
+	}
+	return s.Items.compile()
+}
+
+// ValidateBytes parses data as JSON and validates it, reporting a parse
+// failure as a single violation at the root.
+func (s *JSONSchema) ValidateBytes(data []byte) []Violation {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []Violation{{Path: "$", Message: "invalid JSON: " + err.Error()}}
+	}
+	return s.Validate(doc)
+}
+
+// Validate checks a decoded JSON document (as produced by json.Unmarshal
+// into an any) and returns all violations, or nil if the document is valid.
+// Patterns are compiled on first use; schemas from ParseJSONSchema or passed
+// to ValidateJSON are already compiled and safe for concurrent validation.
+func (s *JSONSchema) Validate(doc any) []Violation {
+	if err := s.compile(); err != nil {
+		return []Violation{{Path: "$", Message: err.Error()}}
+	}
+	var violations []Violation
+	s.validate("$", doc, &violations)
+	return violations
+}
+
+func (s *JSONSchema) validate(path string, value any, out *[]Violation) {
+	if s == nil {
+		return
+	}
+	fail := func(format string, args ...any) {
+		*out = append(*out, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.Type) > 0 && !s.matchesType(value) {
+		fail("expected %s, got %s", strings.Join(s.Type, " or "), jsonTypeOf(value))
+		return
+	}
+	if s.Const != nil && !reflect.DeepEqual(s.Const, value) {
+		fail("must equal %v", s.Const)
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if reflect.DeepEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %v", s.Enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.MinLength != nil && n < *s.MinLength {
+			fail("length %d is less than %d", n, *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			fail("length %d is greater than %d", n, *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("does not match pattern %q", s.Pattern)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			fail("%v is less than minimum %v", v, *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			fail("%v is greater than maximum %v", v, *s.Maximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("has %d items, fewer than %d", len(v), *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("has %d items, more than %d", len(v), *s.MaxItems)
+		}
+		for i, item := range v {
+			s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, out)
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*out = append(*out, Violation{Path: path + "." + name, Message: "is required"})
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			prop, ok := s.Properties[k]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					*out = append(*out, Violation{Path: path + "." + k, Message: "is not allowed"})
+				}
+				continue
+			}
+			prop.validate(path+"."+k, v[k], out)
+		}
+	}
+}
+
+// matchesType reports whether value is one of the schema's types.
+func (s *JSONSchema) matchesType(value any) bool {
+	actual := jsonTypeOf(value)
+	for _, t := range s.Type {
+		if t == actual {
+			return true
+		}
+		if t == "integer" && actual == "number" {
+			if f, ok := value.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonTypeOf names the JSON type of a decoded value.
+func jsonTypeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,125 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type quoteResponse struct {
+	Carrier string
+	Rates   []float64
+}
+
+func TestValidateResponse(t *testing.T) {
+	validate := ValidateResponse(NewIdentity("check-quote", ""),
+		Rule[quoteResponse]{Path: "carrier", Check: func(q quoteResponse) error {
+			if q.Carrier == "" {
+				return errors.New("is required")
+			}
+			return nil
+		}},
+		Rule[quoteResponse]{Path: "rates", Check: func(q quoteResponse) error {
+			if len(q.Rates) == 0 {
+				return errors.New("must not be empty")
+			}
+			return nil
+		}},
+	)
+
+	t.Run("Valid Passes Through", func(t *testing.T) {
+		in := quoteResponse{Carrier: "ups", Rates: []float64{9.5}}
+		out, err := validate.Process(context.Background(), in)
+		if err != nil || out.Carrier != "ups" {
+			t.Fatalf("unexpected result: %+v, %v", out, err)
+		}
+	})
+
+	t.Run("Reports All Violations", func(t *testing.T) {
+		_, err := validate.Process(context.Background(), quoteResponse{})
+		if !errors.Is(err, ErrValidationFailed) {
+			t.Fatalf("expected ErrValidationFailed, got %v", err)
+		}
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) || len(vErr.Violations) != 2 {
+			t.Fatalf("expected 2 violations, got %v", err)
+		}
+		if vErr.Violations[1].String() != "rates: must not be empty" {
+			t.Errorf("unexpected violation: %s", vErr.Violations[1])
+		}
+	})
+}
+
+func TestValidateJSON(t *testing.T) {
+	schema, err := ParseJSONSchema([]byte(`{
+		"type": "object",
+		"required": ["id", "rates"],
+		"additionalProperties": false,
+		"properties": {
+			"id":     {"type": "string", "minLength": 1, "pattern": "^q-"},
+			"status": {"enum": ["ok", "partial"]},
+			"count":  {"type": "integer"},
+			"note":   {"type": ["string", "null"], "maxLength": 5},
+			"rates":  {"type": "array", "minItems": 1, "items": {"type": "number", "minimum": 0}}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	validate := ValidateJSON[[]byte](NewIdentity("check-body", ""), schema)
+
+	t.Run("Valid Payload", func(t *testing.T) {
+		body := []byte(`{"id":"q-1","status":"ok","count":2,"note":null,"rates":[1.5,0]}`)
+		if _, err := validate.Process(context.Background(), body); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Detailed Violations", func(t *testing.T) {
+		body := []byte(`{"id":"x","status":"bad","count":1.5,"note":"too long","rates":[-1],"extra":true}`)
+		_, err := validate.Process(context.Background(), body)
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) {
+			t.Fatalf("expected ValidationError, got %v", err)
+		}
+		want := []string{"$.count", "$.extra", "$.id", "$.note", "$.rates[0]", "$.status"}
+		if len(vErr.Violations) != len(want) {
+			t.Fatalf("expected %d violations, got %v", len(want), vErr.Violations)
+		}
+		for i, v := range vErr.Violations {
+			if v.Path != want[i] {
+				t.Errorf("violation %d: expected path %s, got %s", i, want[i], v)
+			}
+		}
+	})
+
+	t.Run("Missing Required And Wrong Type", func(t *testing.T) {
+		violations := schema.ValidateBytes([]byte(`{"rates":"none"}`))
+		got := make([]string, len(violations))
+		for i, v := range violations {
+			got[i] = v.String()
+		}
+		joined := strings.Join(got, "; ")
+		if !strings.Contains(joined, "$.id: is required") || !strings.Contains(joined, "$.rates: expected array, got string") {
+			t.Errorf("unexpected violations: %s", joined)
+		}
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := ValidateJSON[string](NewIdentity("check", ""), schema).Process(context.Background(), "{")
+		if !errors.Is(err, ErrValidationFailed) || !strings.Contains(err.Error(), "invalid JSON") {
+			t.Fatalf("expected invalid JSON violation, got %v", err)
+		}
+	})
+
+	t.Run("Invalid Schema Pattern", func(t *testing.T) {
+		if _, err := ParseJSONSchema([]byte(`{"pattern": "("}`)); err == nil {
+			t.Fatal("expected compile error")
+		}
+		bad := &JSONSchema{Pattern: "("}
+		if _, err := ValidateJSON[string](NewIdentity("check", ""), bad).Process(context.Background(), `"a"`); err == nil {
+			t.Fatal("expected compile error on process")
+		}
+	})
+}
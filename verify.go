@@ -0,0 +1,208 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ErrUnacceptable wraps the check error when a Verify result is rejected
+// and no fallback is configured (or the fallback's result is also rejected).
+var ErrUnacceptable = errors.New("unacceptable result")
+
+// Verify engages a fallback when the primary processor's result is
+// unacceptable, not just when it errors. Some providers "succeed" with output
+// that is useless downstream: an empty AI completion, a shipping quote with
+// zero rates, a search with no hits. Verify runs a quality check on the
+// primary's result and, if the check returns an error, processes the
+// original input with the fallback instead.
+//
+// The fallback also engages when the primary returns an error, so Verify
+// can replace a Fallback where quality matters. The fallback's result is
+// checked too; if it is also unacceptable, Verify fails with an error
+// wrapping ErrUnacceptable and the check error. A nil fallback turns
+// Verify into a pure quality gate.
+//
+// Example:
+//
+//	var (
+//	    AnswerID   = pipz.NewIdentity("answer", "Answers with a usable completion")
+//	    PrimaryID  = pipz.NewIdentity("openai", "Primary completion provider")
+//	    FallbackID = pipz.NewIdentity("anthropic", "Backup completion provider")
+//	)
+//
+//	answer := pipz.NewVerify(AnswerID,
+//	    pipz.Apply(PrimaryID, callOpenAI),
+//	    func(_ context.Context, r Reply) error {
+//	        if strings.TrimSpace(r.Text) == "" {
+//	            return errors.New("empty completion")
+//	        }
+//	        return nil
+//	    },
+//	    pipz.Apply(FallbackID, callAnthropic),
+//	)
+type Verify[T any] struct {
+	identity  Identity
+	processor Chainable[T]
+	fallback  Chainable[T]
+	check     func(context.Context, T) error
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewVerify creates a Verify connector that checks processor's result with
+// check and engages fallback when the result is unacceptable or an error.
+func NewVerify[T any](identity Identity, processor Chainable[T], check func(context.Context, T) error, fallback Chainable[T]) *Verify[T] {
+	return &Verify[T]{
+		identity:  identity,
+		processor: processor,
+		check:     check,
+		fallback:  fallback,
+	}
+}
+
+// Process implements the Chainable interface.
+func (v *Verify[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, v.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, v, v.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	v.mu.RLock()
+	processor := v.processor
+	fallback := v.fallback
+	check := v.check
+	v.mu.RUnlock()
+
+	result, err = v.attempt(ctx, processor, check, data)
+	if err == nil {
+		return result, nil
+	}
+	if fallback == nil {
+		return data, v.wrap(ctx, err, data)
+	}
+
+	capitan.Warn(ctx, SignalVerifyRejected,
+		FieldName.Field(v.identity.Name()),
+		FieldIdentityID.Field(v.identity.ID().String()),
+		FieldProcessorName.Field(processor.Identity().Name()),
+		FieldError.Field(err.Error()),
+	)
+
+	result, err = v.attempt(ctx, fallback, check, data)
+	if err != nil {
+		return data, v.wrap(ctx, err, data)
+	}
+	return result, nil
+}
+
+// attempt runs processor and checks its result.
+func (*Verify[T]) attempt(ctx context.Context, processor Chainable[T], check func(context.Context, T) error, data T) (T, error) {
+	result, err := processor.Process(ctx, data)
+	if err != nil {
+		return result, err
+	}
+	if check != nil {
+		if checkErr := check(ctx, result); checkErr != nil {
+			return result, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(data),
+				Err:       fmt.Errorf("%w: %w", ErrUnacceptable, checkErr),
+				Path:      []Identity{processor.Identity()},
+			}
+		}
+	}
+	return result, nil
+}
+
+// wrap prepends this connector to err's path.
+func (v *Verify[T]) wrap(ctx context.Context, err error, data T) error {
+	var pipeErr *Error[T]
+	if errors.As(err, &pipeErr) {
+		pipeErr.prependPath(ctx, v.identity)
+		return pipeErr
+	}
+	return &Error[T]{
+		Timestamp: time.Now(),
+		InputData: errorInput(data),
+		Err:       err,
+		Path:      []Identity{v.identity},
+	}
+}
+
+// SetProcessor updates the primary processor.
+func (v *Verify[T]) SetProcessor(processor Chainable[T]) *Verify[T] {
+	noteChildren(v.identity, processor)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.processor = processor
+	return v
+}
+
+// SetFallback updates the fallback processor. A nil fallback makes Verify
+// fail on unacceptable results.
+func (v *Verify[T]) SetFallback(fallback Chainable[T]) *Verify[T] {
+	noteChildren(v.identity, fallback)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.fallback = fallback
+	return v
+}
+
+// SetCheck updates the quality check.
+func (v *Verify[T]) SetCheck(check func(context.Context, T) error) *Verify[T] {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.check = check
+	return v
+}
+
+// Identity returns the identity of this connector.
+func (v *Verify[T]) Identity() Identity {
+	return v.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (v *Verify[T]) Schema() Node {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	flow := VerifyFlow{Processor: v.processor.Schema()}
+	if v.fallback != nil {
+		fallback := v.fallback.Schema()
+		flow.Fallback = &fallback
+	}
+	return Node{
+		Identity: v.identity,
+		Type:     "verify",
+		Flow:     flow,
+	}
+}
+
+// Close gracefully shuts down the connector and its child processors.
+// Close is idempotent - multiple calls return the same result.
+func (v *Verify[T]) Close() error {
+	v.closeOnce.Do(func() {
+		v.mu.RLock()
+		defer v.mu.RUnlock()
+
+		var errs []error
+		if v.fallback != nil {
+			if err := v.fallback.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := v.processor.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		v.closeErr = errors.Join(errs...)
+	})
+	return v.closeErr
+}
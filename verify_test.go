@@ -0,0 +1,103 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestVerify(t *testing.T) {
+	nonZero := func(_ context.Context, v int) error {
+		if v == 0 {
+			return errors.New("zero rates returned")
+		}
+		return nil
+	}
+	zero := Transform(NewIdentity("zero", ""), func(_ context.Context, _ int) int { return 0 })
+	double := Transform(NewIdentity("double", ""), func(_ context.Context, v int) int { return v * 2 })
+	failing := Apply(NewIdentity("failing", ""), func(_ context.Context, v int) (int, error) {
+		return v, errors.New("provider down")
+	})
+
+	t.Run("Acceptable Result Passes", func(t *testing.T) {
+		v := NewVerify(NewIdentity("verify", ""), double, nonZero, zero)
+		result, err := v.Process(context.Background(), 3)
+		if err != nil || result != 6 {
+			t.Fatalf("expected 6, got %d, %v", result, err)
+		}
+	})
+
+	t.Run("Unacceptable Result Engages Fallback", func(t *testing.T) {
+		v := NewVerify(NewIdentity("verify", ""), zero, nonZero, double)
+		result, err := v.Process(context.Background(), 3)
+		if err != nil || result != 6 {
+			t.Fatalf("expected fallback result 6, got %d, %v", result, err)
+		}
+	})
+
+	t.Run("Error Engages Fallback", func(t *testing.T) {
+		v := NewVerify(NewIdentity("verify", ""), failing, nonZero, double)
+		result, err := v.Process(context.Background(), 3)
+		if err != nil || result != 6 {
+			t.Fatalf("expected fallback result 6, got %d, %v", result, err)
+		}
+	})
+
+	t.Run("Fallback Also Unacceptable", func(t *testing.T) {
+		v := NewVerify(NewIdentity("verify", ""), zero, nonZero, zero)
+		_, err := v.Process(context.Background(), 3)
+		if !errors.Is(err, ErrUnacceptable) {
+			t.Fatalf("expected ErrUnacceptable, got %v", err)
+		}
+		var pipeErr *Error[int]
+		if !errors.As(err, &pipeErr) || len(pipeErr.Path) != 2 || pipeErr.Path[0].Name() != "verify" {
+			t.Errorf("unexpected error path: %v", err)
+		}
+	})
+
+	t.Run("Nil Fallback Is A Gate", func(t *testing.T) {
+		v := NewVerify(NewIdentity("verify", ""), zero, nonZero, nil)
+		result, err := v.Process(context.Background(), 3)
+		if !errors.Is(err, ErrUnacceptable) {
+			t.Fatalf("expected ErrUnacceptable, got %v", err)
+		}
+		if result != 3 {
+			t.Errorf("expected original input on failure, got %d", result)
+		}
+	})
+
+	t.Run("Emits Rejected Signal", func(t *testing.T) {
+		var processor string
+		listener := capitan.Hook(SignalVerifyRejected, func(_ context.Context, e *capitan.Event) {
+			processor, _ = FieldProcessorName.From(e)
+		})
+		defer listener.Close()
+
+		v := NewVerify(NewIdentity("verify", ""), zero, nonZero, double)
+		_, _ = v.Process(context.Background(), 1) //nolint:errcheck // result checked elsewhere
+
+		if err := listener.Drain(context.Background()); err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+		if processor != "zero" {
+			t.Errorf("expected rejected processor 'zero', got %q", processor)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		v := NewVerify(NewIdentity("verify", ""), zero, nonZero, double)
+		node := v.Schema()
+		flow, ok := VerifyKey.From(node)
+		if !ok || flow.Fallback == nil || flow.Fallback.Identity.Name() != "double" {
+			t.Fatalf("unexpected flow: %+v", node.Flow)
+		}
+		if NewSchema(node).Count() != 3 {
+			t.Errorf("expected 3 nodes, got %d", NewSchema(node).Count())
+		}
+		if _, ok := VerifyKey.From(NewVerify(NewIdentity("gate", ""), zero, nonZero, nil).Schema()); !ok {
+			t.Error("expected verify flow without fallback")
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package pipz
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version errors.
+var (
+	ErrInvalidVersion   = errors.New("invalid semantic version")
+	ErrVersionDowngrade = errors.New("version downgrade")
+)
+
+// semver is a parsed semantic version. Build metadata is ignored.
+type semver struct {
+	prerelease []string
+	major      uint64
+	minor      uint64
+	patch      uint64
+}
+
+// parseVersion parses MAJOR[.MINOR[.PATCH]][-PRERELEASE][+BUILD], with an
+// optional leading "v".
+func parseVersion(version string) (semver, error) {
+	var v semver
+	s := strings.TrimPrefix(version, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.prerelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+		for _, id := range v.prerelease {
+			if id == "" {
+				return semver{}, fmt.Errorf("%w: %q", ErrInvalidVersion, version)
+			}
+		}
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return semver{}, fmt.Errorf("%w: %q", ErrInvalidVersion, version)
+	}
+	nums := [3]*uint64{&v.major, &v.minor, &v.patch}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return semver{}, fmt.Errorf("%w: %q", ErrInvalidVersion, version)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// compare orders v and other by semantic version precedence.
+func (v semver) compare(other semver) int {
+	for _, pair := range [][2]uint64{{v.major, other.major}, {v.minor, other.minor}, {v.patch, other.patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// A release outranks its pre-releases.
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.prerelease) && i < len(other.prerelease); i++ {
+		if c := comparePrerelease(v.prerelease[i], other.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.prerelease) < len(other.prerelease):
+		return -1
+	case len(v.prerelease) > len(other.prerelease):
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease orders pre-release identifiers: numeric identifiers
+// compare numerically and rank below alphanumeric ones.
+func comparePrerelease(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		switch {
+		case an < bn:
+			return -1
+		case an > bn:
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// CompareVersions compares two semantic versions, returning -1, 0, or 1
+// when a is older than, equal to, or newer than b. Versions may omit the
+// minor and patch numbers and carry a leading "v"; build metadata is
+// ignored. Malformed versions return an error wrapping ErrInvalidVersion.
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.compare(vb), nil
+}
+
+// VersionsCompatible reports whether two semantic versions are compatible:
+// they share a major version, or, below 1.0.0, a major and minor version.
+func VersionsCompatible(a, b string) (bool, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return false, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return false, err
+	}
+	if va.major != vb.major {
+		return false, nil
+	}
+	return va.major != 0 || va.minor == vb.minor, nil
+}
+
+// checkUpgrade rejects replacing current with a processor of an older or
+// undeclared version. Replacing an unversioned processor is always allowed.
+func checkUpgrade(current, replacement Identity) error {
+	if current.Version() == "" {
+		return nil
+	}
+	if replacement.Version() == "" {
+		return fmt.Errorf("%w: %q %s replaced by unversioned %q",
+			ErrVersionDowngrade, current.Name(), current.Version(), replacement.Name())
+	}
+	c, err := CompareVersions(replacement.Version(), current.Version())
+	if err != nil {
+		return err
+	}
+	if c < 0 {
+		return fmt.Errorf("%w: %q %s replaced by %q %s",
+			ErrVersionDowngrade, current.Name(), current.Version(), replacement.Name(), replacement.Version())
+	}
+	return nil
+}
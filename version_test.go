@@ -0,0 +1,154 @@
+package pipz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	t.Run("Orders By Precedence", func(t *testing.T) {
+		ordered := []string{
+			"0.9.0", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2",
+			"1.0.0-beta.11", "1.0.0-rc.1", "v1.0.0", "1.0.1", "1.2", "2",
+		}
+		for i := 0; i+1 < len(ordered); i++ {
+			c, err := CompareVersions(ordered[i], ordered[i+1])
+			if err != nil || c != -1 {
+				t.Errorf("expected %s < %s, got %d (%v)", ordered[i], ordered[i+1], c, err)
+			}
+			if c, _ := CompareVersions(ordered[i+1], ordered[i]); c != 1 {
+				t.Errorf("expected %s > %s, got %d", ordered[i+1], ordered[i], c)
+			}
+		}
+		if c, err := CompareVersions("v1.2.0+build.5", "1.2"); err != nil || c != 0 {
+			t.Errorf("expected equal versions, got %d (%v)", c, err)
+		}
+	})
+
+	t.Run("Rejects Malformed Versions", func(t *testing.T) {
+		for _, v := range []string{"", "x", "1.2.3.4", "1.-2", "1.0.0-", "1.0.0-a..b"} {
+			if _, err := CompareVersions(v, "1.0.0"); !errors.Is(err, ErrInvalidVersion) {
+				t.Errorf("expected %q to be invalid, got %v", v, err)
+			}
+		}
+	})
+
+	t.Run("Compatibility", func(t *testing.T) {
+		cases := []struct {
+			a, b string
+			want bool
+		}{
+			{"1.2.0", "1.9.3", true},
+			{"1.2.0", "2.0.0", false},
+			{"0.3.1", "0.3.9", true},
+			{"0.3.1", "0.4.0", false},
+		}
+		for _, tc := range cases {
+			if got, err := VersionsCompatible(tc.a, tc.b); err != nil || got != tc.want {
+				t.Errorf("VersionsCompatible(%s, %s): expected %v, got %v (%v)", tc.a, tc.b, tc.want, got, err)
+			}
+		}
+	})
+}
+
+func TestVersionedIdentity(t *testing.T) {
+	passthrough := func(id Identity) Chainable[int] {
+		return Transform(id, func(_ context.Context, v int) int { return v })
+	}
+
+	t.Run("WithVersion Keeps ID", func(t *testing.T) {
+		base := NewIdentity("score", "Scores risk")
+		versioned := base.WithVersion("2.1.0")
+		if versioned.ID() != base.ID() || versioned.Version() != "2.1.0" || base.Version() != "" {
+			t.Errorf("unexpected identities: %+v / %+v", base, versioned)
+		}
+	})
+
+	t.Run("Lookups Match Any Version", func(t *testing.T) {
+		base := NewIdentity("score", "")
+		seq := NewSequence(NewIdentity("seq", ""), passthrough(base.WithVersion("1.0.0")))
+
+		if err := seq.After(base, passthrough(NewIdentity("audit", ""))); err != nil {
+			t.Errorf("expected After to find the stage by ID, got %v", err)
+		}
+		if err := seq.Replace(base.WithVersion("0.9.0"), passthrough(base.WithVersion("2.0.0"))); err != nil {
+			t.Errorf("expected Replace to find the stage by ID, got %v", err)
+		}
+		if err := seq.Remove(base); err != nil {
+			t.Errorf("expected Remove to find the stage by ID, got %v", err)
+		}
+		if seq.Len() != 1 {
+			t.Errorf("expected only the audit stage left, got %v", seq.Names())
+		}
+	})
+
+	t.Run("Upgrade Only Replace", func(t *testing.T) {
+		current := NewIdentity("score", "").WithVersion("2.1.0")
+		seq := NewSequence(NewIdentity("seq", ""), passthrough(current)).SetUpgradeOnly(true)
+
+		older := NewIdentity("score", "").WithVersion("2.0.5")
+		if err := seq.Replace(current, passthrough(older)); !errors.Is(err, ErrVersionDowngrade) {
+			t.Errorf("expected downgrade rejection, got %v", err)
+		}
+		if err := seq.Replace(current, passthrough(NewIdentity("score", ""))); !errors.Is(err, ErrVersionDowngrade) {
+			t.Errorf("expected unversioned rejection, got %v", err)
+		}
+		err := seq.Edit(func(tx *SequenceTx[int]) error {
+			return tx.Replace(current, passthrough(older))
+		})
+		if !errors.Is(err, ErrVersionDowngrade) {
+			t.Errorf("expected downgrade rejection in Edit, got %v", err)
+		}
+
+		newer := NewIdentity("score", "").WithVersion("2.2.0")
+		if err := seq.Replace(current, passthrough(newer)); err != nil {
+			t.Fatalf("expected upgrade to succeed, got %v", err)
+		}
+		if got := seq.Schema().Flow.(SequenceFlow).Steps[0].Identity.Version(); got != "2.2.0" {
+			t.Errorf("expected version 2.2.0 after upgrade, got %s", got)
+		}
+	})
+
+	t.Run("Replace Allows Downgrade By Default", func(t *testing.T) {
+		current := NewIdentity("score", "").WithVersion("2.1.0")
+		seq := NewSequence(NewIdentity("seq", ""), passthrough(current))
+		if err := seq.Replace(current, passthrough(NewIdentity("score", "").WithVersion("1.0.0"))); err != nil {
+			t.Errorf("expected replace without policy to succeed, got %v", err)
+		}
+	})
+
+	t.Run("Schema And Diff Surface Versions", func(t *testing.T) {
+		before := NewSequence(NewIdentity("seq", ""), passthrough(NewIdentity("score", "").WithVersion("1.0.0")))
+		after := NewSequence(NewIdentity("seq", ""), passthrough(NewIdentity("score", "").WithVersion("1.1.0")))
+
+		data, err := json.Marshal(after.Schema())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Node
+		root, err := json.Marshal(Node{Identity: NewIdentity("score", "").WithVersion("3.0.0"), Type: "transform"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(root, &decoded); err != nil || decoded.Identity.Version() != "3.0.0" {
+			t.Errorf("expected version to round-trip, got %q (%v)", decoded.Identity.Version(), err)
+		}
+		var raw struct {
+			Flow struct {
+				Steps []struct {
+					Version string `json:"version"`
+				} `json:"steps"`
+			} `json:"flow"`
+		}
+		if err := json.Unmarshal(data, &raw); err != nil || len(raw.Flow.Steps) != 1 || raw.Flow.Steps[0].Version != "1.1.0" {
+			t.Errorf("expected step version in schema JSON, got %s", data)
+		}
+
+		diff := Diff[int](before, after)
+		if len(diff.Changes) != 1 || diff.Changes[0].Settings[0].Key != "version" || diff.Changes[0].Settings[0].After != "1.1.0" {
+			t.Errorf("expected version change, got %+v", diff.Changes)
+		}
+	})
+}
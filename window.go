@@ -0,0 +1,368 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/clockz"
+)
+
+// ErrOutsideWindow is returned by a Window when an item arrives outside
+// every processing window and cannot wait for the next one.
+var ErrOutsideWindow = errors.New("outside processing window")
+
+// Weekdays lists Monday through Friday, for business-hours windows.
+var Weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// TimeWindow is a span of local time repeated on the given days. Start and
+// End are offsets from local midnight; an End at or before Start wraps past
+// midnight into the next day, so 22:00-06:00 is an overnight window that
+// opens on each listed day. Empty Days means every day.
+type TimeWindow struct {
+	Days  []time.Weekday
+	Start time.Duration
+	End   time.Duration
+}
+
+// String renders the window as "Mon,Tue 09:00-17:00".
+func (w TimeWindow) String() string {
+	span := fmt.Sprintf("%s-%s", clockTime(w.Start), clockTime(w.End))
+	if len(w.Days) == 0 {
+		return span
+	}
+	days := make([]string, len(w.Days))
+	for i, d := range w.Days {
+		days[i] = d.String()[:3]
+	}
+	return strings.Join(days, ",") + " " + span
+}
+
+// clockTime renders an offset from midnight as HH:MM.
+func clockTime(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// opensOn reports whether the window opens on weekday.
+func (w TimeWindow) opensOn(weekday time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// Window only lets items through during configured time windows, such as
+// business hours or a maintenance window, evaluated in a time zone. It
+// keeps notification pipelines from texting customers at 3 AM and batch
+// jobs from touching production outside their slot.
+//
+// An item arriving while a window is open is processed immediately. Outside
+// every window, the item is routed to the alternate processor if one is
+// set; otherwise Window waits for the next window to open, honoring the
+// context, and then processes it. SetMaxDelay bounds that wait: items that
+// would wait longer fail with ErrOutsideWindow. With no windows configured,
+// Window is always open.
+//
+// The time zone defaults to the one passed to NewWindow; SetLocationFunc
+// picks one per item, such as each customer's own zone.
+//
+// Example:
+//
+//	var QuietHoursID = pipz.NewIdentity("sms-hours", "Texts customers 9-8 in their own time zone")
+//	sms := pipz.NewWindow(QuietHoursID, time.UTC, sendSMS, pipz.TimeWindow{
+//	    Start: 9 * time.Hour,
+//	    End:   20 * time.Hour,
+//	}).SetLocationFunc(func(_ context.Context, n Notification) *time.Location {
+//	    return n.Customer.Location
+//	}).SetAlternate(queueForMorning)
+type Window[T any] struct {
+	processor    Chainable[T]
+	alternate    Chainable[T]
+	location     *time.Location
+	locationFunc func(context.Context, T) *time.Location
+	clock        clockz.Clock
+	identity     Identity
+	windows      []TimeWindow
+	maxDelay     time.Duration
+	mu           sync.RWMutex
+	closeOnce    sync.Once
+	closeErr     error
+}
+
+// NewWindow creates a Window running processor during windows, evaluated
+// in location. A nil location means UTC.
+func NewWindow[T any](identity Identity, location *time.Location, processor Chainable[T], windows ...TimeWindow) *Window[T] {
+	if location == nil {
+		location = time.UTC
+	}
+	return &Window[T]{
+		identity:  identity,
+		location:  location,
+		processor: processor,
+		windows:   windows,
+	}
+}
+
+// Process implements the Chainable interface.
+// Processes the item in its window, routing or delaying it otherwise.
+func (w *Window[T]) Process(ctx context.Context, data T) (result T, err error) {
+	defer recoverFromPanic(&result, &err, w.identity, data)
+
+	ctx, guardErr := enterDepth(ctx, w, w.identity, data)
+	if guardErr != nil {
+		return data, guardErr
+	}
+
+	w.mu.RLock()
+	processor := w.processor
+	alternate := w.alternate
+	location := w.location
+	locationFunc := w.locationFunc
+	windows := w.windows
+	maxDelay := w.maxDelay
+	clock := w.getClock()
+	w.mu.RUnlock()
+
+	if locationFunc != nil {
+		if loc := locationFunc(ctx, data); loc != nil {
+			location = loc
+		}
+	}
+
+	now := clock.Now()
+	wait, open := untilOpen(now.In(location), windows)
+	if wait > 0 || !open {
+		switch {
+		case alternate != nil:
+			capitan.Info(ctx, SignalWindowRouted,
+				FieldName.Field(w.identity.Name()),
+				FieldIdentityID.Field(w.identity.ID().String()),
+				FieldProcessorName.Field(alternate.Identity().Name()),
+				FieldWaitTime.Field(wait.Seconds()),
+			)
+			processor = alternate
+		case !open || (maxDelay > 0 && wait > maxDelay):
+			capitan.Warn(ctx, SignalWindowRejected,
+				FieldName.Field(w.identity.Name()),
+				FieldIdentityID.Field(w.identity.ID().String()),
+				FieldWaitTime.Field(wait.Seconds()),
+			)
+			return data, &Error[T]{
+				Timestamp: time.Now(),
+				InputData: errorInput(data),
+				Err:       fmt.Errorf("%w: next window opens in %v", ErrOutsideWindow, wait),
+				Path:      []Identity{w.identity},
+			}
+		default:
+			capitan.Info(ctx, SignalWindowDelayed,
+				FieldName.Field(w.identity.Name()),
+				FieldIdentityID.Field(w.identity.ID().String()),
+				FieldWaitTime.Field(wait.Seconds()),
+			)
+			select {
+			case <-clock.After(wait):
+			case <-ctx.Done():
+				return data, &Error[T]{
+					Timestamp: time.Now(),
+					InputData: errorInput(data),
+					Err:       ctx.Err(),
+					Path:      []Identity{w.identity},
+					Duration:  clock.Since(now),
+					Timeout:   errors.Is(ctx.Err(), context.DeadlineExceeded),
+					Canceled:  errors.Is(ctx.Err(), context.Canceled),
+				}
+			}
+		}
+	}
+
+	result, err = processor.Process(ctx, data)
+	if err != nil {
+		var pipeErr *Error[T]
+		if errors.As(err, &pipeErr) {
+			pipeErr.prependPath(ctx, w.identity)
+			return result, pipeErr
+		}
+		return data, &Error[T]{
+			Timestamp: time.Now(),
+			InputData: errorInput(data),
+			Err:       err,
+			Path:      []Identity{w.identity},
+		}
+	}
+	return result, nil
+}
+
+// untilOpen returns how long until one of windows is open at or after now,
+// zero if one is open already. It reports false if no window opens within
+// the next week, which happens only when none lists a valid weekday.
+func untilOpen(now time.Time, windows []TimeWindow) (time.Duration, bool) {
+	if len(windows) == 0 {
+		return 0, true
+	}
+	var next time.Time
+	found := false
+	// Start a day early so overnight windows opened yesterday are seen.
+	for offset := -1; offset <= 7; offset++ {
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+offset, 0, 0, 0, 0, now.Location())
+		for _, win := range windows {
+			if !win.opensOn(midnight.Weekday()) {
+				continue
+			}
+			start := midnight.Add(win.Start)
+			end := midnight.Add(win.End)
+			if win.End <= win.Start {
+				end = end.Add(24 * time.Hour)
+			}
+			if !end.After(now) || !end.After(start) {
+				continue
+			}
+			if start.Before(now) {
+				start = now
+			}
+			if !found || start.Before(next) {
+				next, found = start, true
+			}
+		}
+	}
+	if !found {
+		return 0, false
+	}
+	return next.Sub(now), true
+}
+
+// SetWindows replaces the processing windows.
+func (w *Window[T]) SetWindows(windows ...TimeWindow) *Window[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.windows = windows
+	return w
+}
+
+// SetLocation updates the default time zone. A nil location means UTC.
+func (w *Window[T]) SetLocation(location *time.Location) *Window[T] {
+	if location == nil {
+		location = time.UTC
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.location = location
+	return w
+}
+
+// SetLocationFunc picks the time zone per item. A nil result falls back to
+// the default location.
+func (w *Window[T]) SetLocationFunc(locationFunc func(context.Context, T) *time.Location) *Window[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.locationFunc = locationFunc
+	return w
+}
+
+// SetAlternate routes items arriving outside every window to processor
+// instead of delaying them. A nil processor restores delaying.
+func (w *Window[T]) SetAlternate(processor Chainable[T]) *Window[T] {
+	noteChildren(w.identity, processor)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.alternate = processor
+	return w
+}
+
+// SetMaxDelay bounds how long an item may wait for the next window. Zero
+// means no bound.
+func (w *Window[T]) SetMaxDelay(d time.Duration) *Window[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.maxDelay = d
+	return w
+}
+
+// SetProcessor updates the processor run inside the windows.
+func (w *Window[T]) SetProcessor(processor Chainable[T]) *Window[T] {
+	noteChildren(w.identity, processor)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.processor = processor
+	return w
+}
+
+// IsOpen reports whether a window is open now in the default location.
+func (w *Window[T]) IsOpen() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	wait, open := untilOpen(w.getClock().Now().In(w.location), w.windows)
+	return open && wait == 0
+}
+
+// WithClock sets a custom clock for testing.
+func (w *Window[T]) WithClock(clock clockz.Clock) *Window[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.clock = clock
+	return w
+}
+
+// getClock returns the clock to use.
+func (w *Window[T]) getClock() clockz.Clock {
+	if w.clock == nil {
+		return clockz.RealClock
+	}
+	return w.clock
+}
+
+// Identity returns the identity of this connector.
+func (w *Window[T]) Identity() Identity {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.identity
+}
+
+// Schema returns a Node representing this connector in the pipeline schema.
+func (w *Window[T]) Schema() Node {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	flow := WindowFlow{Processor: w.processor.Schema()}
+	if w.alternate != nil {
+		alternate := w.alternate.Schema()
+		flow.Alternate = &alternate
+	}
+	windows := make([]string, len(w.windows))
+	for i, win := range w.windows {
+		windows[i] = win.String()
+	}
+	return Node{
+		Identity: w.identity,
+		Type:     "window",
+		Flow:     flow,
+		Metadata: map[string]any{
+			"location":  w.location.String(),
+			"windows":   windows,
+			"max_delay": w.maxDelay.String(),
+		},
+	}
+}
+
+// Close gracefully shuts down the connector and its child processors.
+// Close is idempotent - multiple calls return the same result.
+func (w *Window[T]) Close() error {
+	w.closeOnce.Do(func() {
+		w.mu.RLock()
+		defer w.mu.RUnlock()
+		var alternateErr error
+		if w.alternate != nil {
+			alternateErr = w.alternate.Close()
+		}
+		w.closeErr = errors.Join(w.processor.Close(), alternateErr)
+	})
+	return w.closeErr
+}
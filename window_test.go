@@ -0,0 +1,149 @@
+package pipz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/clockz"
+)
+
+func TestWindow(t *testing.T) {
+	// Monday 2024-01-01.
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+	business := TimeWindow{Days: Weekdays, Start: 9 * time.Hour, End: 17 * time.Hour}
+	echo := Transform(NewIdentity("echo", ""), func(_ context.Context, v string) string { return v + ":sent" })
+
+	t.Run("Until Open", func(t *testing.T) {
+		overnight := TimeWindow{Start: 22 * time.Hour, End: 6 * time.Hour}
+		tests := []struct {
+			name    string
+			now     time.Time
+			windows []TimeWindow
+			want    time.Duration
+		}{
+			{"Inside", monday(10, 0), []TimeWindow{business}, 0},
+			{"Before", monday(8, 30), []TimeWindow{business}, 30 * time.Minute},
+			{"After", monday(18, 0), []TimeWindow{business}, 15 * time.Hour},
+			{"Weekend", monday(9, 0).AddDate(0, 0, 5), []TimeWindow{business}, 48 * time.Hour},
+			{"Overnight Past Midnight", monday(2, 0), []TimeWindow{overnight}, 0},
+			{"Overnight Before Opening", monday(12, 0), []TimeWindow{overnight}, 10 * time.Hour},
+			{"Earliest Of Several", monday(7, 0), []TimeWindow{business, {Start: 8 * time.Hour, End: 9 * time.Hour}}, time.Hour},
+			{"No Windows", monday(3, 0), nil, 0},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, open := untilOpen(tt.now, tt.windows)
+				if !open || got != tt.want {
+					t.Errorf("expected %v, got %v (open %v)", tt.want, got, open)
+				}
+			})
+		}
+	})
+
+	t.Run("Processes Inside Window", func(t *testing.T) {
+		clock := clockz.NewFakeClockAt(monday(10, 0))
+		window := NewWindow(NewIdentity("hours", ""), time.UTC, echo, business).WithClock(clock)
+		result, err := window.Process(context.Background(), "a")
+		if err != nil || result != "a:sent" {
+			t.Fatalf("expected immediate processing, got %q, %v", result, err)
+		}
+		if !window.IsOpen() {
+			t.Error("expected window to report open")
+		}
+	})
+
+	t.Run("Routes To Alternate", func(t *testing.T) {
+		clock := clockz.NewFakeClockAt(monday(3, 0))
+		later := Transform(NewIdentity("later", ""), func(_ context.Context, v string) string { return v + ":queued" })
+		window := NewWindow(NewIdentity("hours", ""), time.UTC, echo, business).WithClock(clock).SetAlternate(later)
+		result, err := window.Process(context.Background(), "a")
+		if err != nil || result != "a:queued" {
+			t.Fatalf("expected alternate, got %q, %v", result, err)
+		}
+	})
+
+	t.Run("Delays Until Open", func(t *testing.T) {
+		clock := clockz.NewFakeClockAt(monday(8, 59))
+		window := NewWindow(NewIdentity("hours", ""), time.UTC, echo, business).WithClock(clock)
+
+		done := make(chan string, 1)
+		go func() {
+			result, err := window.Process(context.Background(), "a")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			done <- result
+		}()
+		waitForWaiters(t, clock)
+		select {
+		case <-done:
+			t.Fatal("expected item to wait for the window")
+		default:
+		}
+		clock.Advance(time.Minute)
+		clock.BlockUntilReady()
+		if result := <-done; result != "a:sent" {
+			t.Errorf("expected processing after opening, got %q", result)
+		}
+	})
+
+	t.Run("Max Delay Rejects", func(t *testing.T) {
+		clock := clockz.NewFakeClockAt(monday(3, 0))
+		window := NewWindow(NewIdentity("hours", ""), time.UTC, echo, business).WithClock(clock).SetMaxDelay(time.Hour)
+		_, err := window.Process(context.Background(), "a")
+		if !errors.Is(err, ErrOutsideWindow) {
+			t.Fatalf("expected ErrOutsideWindow, got %v", err)
+		}
+	})
+
+	t.Run("Cancellation While Waiting", func(t *testing.T) {
+		clock := clockz.NewFakeClockAt(monday(3, 0))
+		window := NewWindow(NewIdentity("hours", ""), time.UTC, echo, business).WithClock(clock)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := window.Process(ctx, "a")
+		var pipeErr *Error[string]
+		if !errors.As(err, &pipeErr) || !pipeErr.Canceled {
+			t.Fatalf("expected canceled error, got %v", err)
+		}
+	})
+
+	t.Run("Per Item Location", func(t *testing.T) {
+		tokyo := time.FixedZone("JST", 9*60*60)
+		// 01:00 UTC Monday is 10:00 in Tokyo.
+		clock := clockz.NewFakeClockAt(monday(1, 0))
+		window := NewWindow(NewIdentity("hours", ""), time.UTC, echo, business).WithClock(clock).SetMaxDelay(time.Minute).
+			SetLocationFunc(func(_ context.Context, v string) *time.Location {
+				if v == "tokyo" {
+					return tokyo
+				}
+				return nil
+			})
+		if _, err := window.Process(context.Background(), "tokyo"); err != nil {
+			t.Errorf("expected Tokyo item inside its window, got %v", err)
+		}
+		if _, err := window.Process(context.Background(), "london"); !errors.Is(err, ErrOutsideWindow) {
+			t.Errorf("expected UTC item outside its window, got %v", err)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		later := Transform(NewIdentity("later", ""), func(_ context.Context, v string) string { return v })
+		node := NewWindow(NewIdentity("hours", ""), time.UTC, echo, business).SetAlternate(later).Schema()
+		flow, ok := WindowKey.From(node)
+		if !ok {
+			t.Fatalf("expected WindowFlow, got %T", node.Flow)
+		}
+		if flow.Alternate == nil || flow.Alternate.Identity.Name() != "later" {
+			t.Errorf("expected alternate in schema, got %+v", flow)
+		}
+		windows, _ := node.Metadata["windows"].([]string)
+		if len(windows) != 1 || windows[0] != "Mon,Tue,Wed,Thu,Fri 09:00-17:00" {
+			t.Errorf("unexpected windows metadata: %v", node.Metadata["windows"])
+		}
+	})
+}
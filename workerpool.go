@@ -17,6 +17,10 @@ import (
 // The input type T must implement Cloner[T] to provide safe concurrent processing.
 // Each processor receives an isolated copy of the input data.
 //
+// WorkerPool honors the priority on the context (see WithPriority):
+// SetReserve holds back workers that bulk-priority items cannot use, so
+// background work cannot occupy every worker.
+//
 // Example:
 //
 //	pool := pipz.NewWorkerPool("api-calls", 5,
@@ -29,10 +33,12 @@ import (
 type WorkerPool[T Cloner[T]] struct {
 	processors []Chainable[T]
 	sem        chan struct{} // Semaphore for worker limit
+	bulkSem    chan struct{} // Semaphore for bulk items, nil without a reserve
 	identity   Identity
 	mu         sync.RWMutex  // Thread safety
 	timeout    time.Duration // Optional per-task timeout
 	queueSize  int           // Optional queue size (unused but kept for future)
+	reserve    int           // Workers bulk items cannot use
 	clock      clockz.Clock  // Clock for time operations
 	closeOnce  sync.Once
 	closeErr   error
@@ -58,6 +64,24 @@ func NewWorkerPool[T Cloner[T]](identity Identity, workers int, processors ...Ch
 	return wp
 }
 
+// NewWorkerPoolWithOptions creates a WorkerPool from functional options.
+// WithWorkers is required; WithTaskTimeout and WithClock are optional.
+// Invalid, missing, or inapplicable options return an error wrapping
+// ErrInvalidOption.
+func NewWorkerPoolWithOptions[T Cloner[T]](identity Identity, processors []Chainable[T], opts ...Option) (*WorkerPool[T], error) {
+	cfg, err := newConfig("worker pool", Config{}, optWorkers|optTaskTimeout|optClock, optWorkers, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	wp := NewWorkerPool(identity, cfg.Workers, processors...)
+	wp.timeout = cfg.TaskTimeout
+	if cfg.Clock != nil {
+		wp.clock = cfg.Clock
+	}
+	return wp, nil
+}
+
 // Identity returns the identity of this connector.
 func (w *WorkerPool[T]) Identity() Identity {
 	w.mu.RLock()
@@ -89,13 +113,23 @@ func (w *WorkerPool[T]) Schema() Node {
 // Process implements the Chainable interface.
 func (w *WorkerPool[T]) Process(ctx context.Context, input T) (result T, err error) {
 	defer recoverFromPanic(&result, &err, w.identity, input)
+
+	ctx, guardErr := enterDepth(ctx, w, w.identity, input)
+	if guardErr != nil {
+		return input, guardErr
+	}
 	w.mu.RLock()
 	processors := make([]Chainable[T], len(w.processors))
 	copy(processors, w.processors)
 	timeout := w.timeout
+	bulkSem := w.bulkSem
 	clock := w.getClock()
 	w.mu.RUnlock()
 
+	if PriorityFromContext(ctx) >= PriorityNormal {
+		bulkSem = nil
+	}
+
 	if len(processors) == 0 {
 		return input, nil
 	}
@@ -109,6 +143,17 @@ func (w *WorkerPool[T]) Process(ctx context.Context, input T) (result T, err err
 		go func(p Chainable[T]) {
 			defer wg.Done()
 
+			// Bulk items may only use the workers not held in reserve
+			if bulkSem != nil {
+				select {
+				case bulkSem <- struct{}{}:
+					defer func() { <-bulkSem }()
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+
 			// Check if pool is saturated before acquiring
 			workerCount := cap(w.sem)
 			activeWorkers := len(w.sem)
@@ -179,7 +224,7 @@ func (w *WorkerPool[T]) Process(ctx context.Context, input T) (result T, err err
 		if err != nil {
 			return input, &Error[T]{
 				Err:       err,
-				InputData: input,
+				InputData: errorInput(input),
 				Path:      []Identity{w.identity},
 				Timestamp: clock.Now(),
 				Duration:  0, // Duration not tracked at connector level
@@ -192,6 +237,7 @@ func (w *WorkerPool[T]) Process(ctx context.Context, input T) (result T, err err
 
 // Add appends a processor to the worker pool execution list.
 func (w *WorkerPool[T]) Add(processor Chainable[T]) *WorkerPool[T] {
+	noteChildren(w.identity, processor)
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.processors = append(w.processors, processor)
@@ -228,6 +274,7 @@ func (w *WorkerPool[T]) Clear() *WorkerPool[T] {
 
 // SetProcessors replaces all processors atomically.
 func (w *WorkerPool[T]) SetProcessors(processors ...Chainable[T]) *WorkerPool[T] {
+	noteChildren(w.identity, processors...)
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.processors = make([]Chainable[T], len(processors))
@@ -254,9 +301,39 @@ func (w *WorkerPool[T]) SetWorkerCount(workers int) *WorkerPool[T] {
 
 	// Create new semaphore with updated size
 	w.sem = make(chan struct{}, workers)
+	w.resizeBulk()
 	return w
 }
 
+// SetReserve holds back workers from bulk-priority items: at most the
+// worker count minus reserve bulk tasks run at once, leaving the rest for
+// normal and higher priorities. At least one worker stays available to
+// bulk items. Zero removes the reserve.
+func (w *WorkerPool[T]) SetReserve(workers int) *WorkerPool[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.reserve = max(workers, 0)
+	w.resizeBulk()
+	return w
+}
+
+// GetReserve returns the number of workers held back from bulk items.
+func (w *WorkerPool[T]) GetReserve() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.reserve
+}
+
+// resizeBulk recreates the bulk semaphore for the current worker count and
+// reserve. Must be called with mutex held.
+func (w *WorkerPool[T]) resizeBulk() {
+	if w.reserve == 0 {
+		w.bulkSem = nil
+		return
+	}
+	w.bulkSem = make(chan struct{}, max(cap(w.sem)-w.reserve, 1))
+}
+
 // GetWorkerCount returns the maximum number of concurrent workers.
 func (w *WorkerPool[T]) GetWorkerCount() int {
 	w.mu.RLock()